@@ -0,0 +1,323 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// Condition types.
+const (
+	// TypeCRDInstalled indicates whether the CustomResourceDefinition
+	// referenced by a definition is served by the API server.
+	TypeCRDInstalled runtimev1alpha1.ConditionType = "CRDInstalled"
+
+	// TypeCyclicDependency indicates whether the inputs and outputs of an
+	// ApplicationConfiguration's components form a cycle.
+	TypeCyclicDependency runtimev1alpha1.ConditionType = "CyclicDependency"
+
+	// TypeParametersValid indicates whether the parameter values of an
+	// ApplicationConfiguration's components match their declared types.
+	TypeParametersValid runtimev1alpha1.ConditionType = "ParametersValid"
+
+	// TypeTraitConflict indicates whether any of an ApplicationConfiguration's
+	// workloads have traits that conflict with each other.
+	TypeTraitConflict runtimev1alpha1.ConditionType = "TraitConflict"
+
+	// TypeUnauthorizedWorkloadKind indicates whether any of an
+	// ApplicationConfiguration's workloads are of a kind the controller is not
+	// allowed to apply.
+	TypeUnauthorizedWorkloadKind runtimev1alpha1.ConditionType = "UnauthorizedWorkloadKind"
+
+	// TypeUnsupportedEnv indicates whether any of an
+	// ApplicationConfiguration's components specify environment variables
+	// that cannot be injected into their workloads.
+	TypeUnsupportedEnv runtimev1alpha1.ConditionType = "UnsupportedEnv"
+
+	// TypeUnsupportedAffinity indicates whether any of an
+	// ApplicationConfiguration's components specify an affinity that cannot be
+	// injected into their workloads.
+	TypeUnsupportedAffinity runtimev1alpha1.ConditionType = "UnsupportedAffinity"
+
+	// TypeUnsupportedTolerations indicates whether any of an
+	// ApplicationConfiguration's components specify tolerations that cannot
+	// be injected into their workloads.
+	TypeUnsupportedTolerations runtimev1alpha1.ConditionType = "UnsupportedTolerations"
+
+	// TypeUnsupportedPriorityClass indicates whether any of an
+	// ApplicationConfiguration's components specify a priority class that cannot
+	// be injected into their workloads.
+	TypeUnsupportedPriorityClass runtimev1alpha1.ConditionType = "UnsupportedPriorityClass"
+
+	// TypeUnsupportedNodeSelector indicates whether any of an
+	// ApplicationConfiguration's components specify a node selector that
+	// cannot be injected into their workloads.
+	TypeUnsupportedNodeSelector runtimev1alpha1.ConditionType = "UnsupportedNodeSelector"
+
+	// TypeUnsupportedHostNetwork indicates whether any of an
+	// ApplicationConfiguration's components enable host network mode, but it
+	// cannot be injected into their workloads.
+	TypeUnsupportedHostNetwork runtimev1alpha1.ConditionType = "UnsupportedHostNetwork"
+
+	// TypeUnsupportedLivenessProbe indicates whether any of an
+	// ApplicationConfiguration's components specify a liveness probe that cannot
+	// be injected into their workloads.
+	TypeUnsupportedLivenessProbe runtimev1alpha1.ConditionType = "UnsupportedLivenessProbe"
+
+	// TypeUnsupportedPodDisruptionBudget indicates whether any of an
+	// ApplicationConfiguration's components specify a PodDisruptionBudget that
+	// cannot be rendered for their workloads.
+	TypeUnsupportedPodDisruptionBudget runtimev1alpha1.ConditionType = "UnsupportedPodDisruptionBudget"
+
+	// TypeUnsupportedServiceAccount indicates whether any of an
+	// ApplicationConfiguration's components specify a service account that
+	// cannot be injected into their workloads.
+	TypeUnsupportedServiceAccount runtimev1alpha1.ConditionType = "UnsupportedServiceAccount"
+
+	// TypeUnsupportedReadinessProbe indicates whether any of an
+	// ApplicationConfiguration's components specify a readiness probe that
+	// cannot be injected into their workloads.
+	TypeUnsupportedReadinessProbe runtimev1alpha1.ConditionType = "UnsupportedReadinessProbe"
+
+	// TypeUnsupportedSecurityContext indicates whether any of an
+	// ApplicationConfiguration's components specify a security context that
+	// cannot be injected into their workloads.
+	TypeUnsupportedSecurityContext runtimev1alpha1.ConditionType = "UnsupportedSecurityContext"
+
+	// TypeUnsupportedTrafficWeight indicates whether any of an
+	// ApplicationConfiguration's components specify a traffic weight that cannot
+	// be split across their workloads.
+	TypeUnsupportedTrafficWeight runtimev1alpha1.ConditionType = "UnsupportedTrafficWeight"
+
+	// TypeDependencyCycle indicates whether any of an
+	// ApplicationConfiguration's components depend on each other's workload
+	// kinds, preventing its workloads from being applied.
+	TypeDependencyCycle runtimev1alpha1.ConditionType = "DependencyCycle"
+
+	// TypeAdoptionConflict indicates whether any of an
+	// ApplicationConfiguration's workloads already exist and may not be
+	// adopted.
+	TypeAdoptionConflict runtimev1alpha1.ConditionType = "AdoptionConflict"
+
+	// TypeSuspended indicates whether the component that produced a workload
+	// is suspended, i.e. whether the workload's replicas are set to zero.
+	TypeSuspended runtimev1alpha1.ConditionType = "Suspended"
+
+	// TypePreApplyHookFailed indicates whether the PreApplyHook of an
+	// ApplicationConfiguration failed, preventing its workloads from being
+	// applied.
+	TypePreApplyHookFailed runtimev1alpha1.ConditionType = "PreApplyHookFailed"
+
+	// TypePriorityClassNotFound indicates whether any of the PriorityClasses
+	// of an ApplicationConfiguration's workloads do not exist.
+	TypePriorityClassNotFound runtimev1alpha1.ConditionType = "PriorityClassNotFound"
+
+	// TypeServiceAccountNotFound indicates whether any of the service
+	// accounts of an ApplicationConfiguration's workloads do not exist.
+	TypeServiceAccountNotFound runtimev1alpha1.ConditionType = "ServiceAccountNotFound"
+
+	// TypePodSecurityViolation indicates whether the security contexts
+	// injected into any of an ApplicationConfiguration's workloads violate the
+	// PodSecurity admission level enforced on its namespace.
+	TypePodSecurityViolation runtimev1alpha1.ConditionType = "PodSecurityViolation"
+
+	// TypePatchFailed indicates whether the patches of any of an
+	// ApplicationConfiguration's components could not be applied to their
+	// workloads.
+	TypePatchFailed runtimev1alpha1.ConditionType = "PatchFailed"
+
+	// TypeConfigMapNotFound indicates whether any of the ConfigMaps mounted
+	// into an ApplicationConfiguration's workloads do not exist.
+	TypeConfigMapNotFound runtimev1alpha1.ConditionType = "ConfigMapNotFound"
+
+	// TypeSpecValidationFailed indicates whether any of an
+	// ApplicationConfiguration's rendered workloads do not match the OpenAPI
+	// schema of their WorkloadDefinition's CustomResourceDefinition.
+	TypeSpecValidationFailed runtimev1alpha1.ConditionType = "SpecValidationFailed"
+
+	// TypeNameTemplateError indicates whether the workload name template of
+	// any of an ApplicationConfiguration's components could not be rendered.
+	TypeNameTemplateError runtimev1alpha1.ConditionType = "NameTemplateError"
+
+	// TypeSpecParseError indicates whether the SpecSource of an
+	// ApplicationConfiguration could not be parsed.
+	TypeSpecParseError runtimev1alpha1.ConditionType = "SpecParseError"
+
+	// TypeScopeIncompatibility indicates whether any of an
+	// ApplicationConfiguration's workloads were not added to a scope whose
+	// ScopeDefinition does not apply to their kind.
+	TypeScopeIncompatibility runtimev1alpha1.ConditionType = "ScopeIncompatibility"
+
+	// TypeDrifted indicates whether any of an ApplicationConfiguration's
+	// workloads no longer match the spec they were last applied with.
+	TypeDrifted runtimev1alpha1.ConditionType = "Drifted"
+)
+
+// Condition reasons.
+const (
+	ReasonCRDInstalled    runtimev1alpha1.ConditionReason = "CRDInstalled"
+	ReasonCRDNotInstalled runtimev1alpha1.ConditionReason = "CRDNotInstalled"
+
+	ReasonCyclicDependency runtimev1alpha1.ConditionReason = "CyclicDependency"
+	ReasonAcyclic          runtimev1alpha1.ConditionReason = "Acyclic"
+
+	ReasonParameterValidationFailed    runtimev1alpha1.ConditionReason = "ParameterValidationFailed"
+	ReasonParameterValidationSucceeded runtimev1alpha1.ConditionReason = "ParameterValidationSucceeded"
+
+	ReasonTraitConflict   runtimev1alpha1.ConditionReason = "TraitConflict"
+	ReasonNoTraitConflict runtimev1alpha1.ConditionReason = "NoTraitConflict"
+
+	ReasonUnauthorizedWorkloadKind runtimev1alpha1.ConditionReason = "UnauthorizedWorkloadKind"
+	ReasonAuthorizedWorkloadKinds  runtimev1alpha1.ConditionReason = "AuthorizedWorkloadKinds"
+
+	ReasonAdoptionConflict   runtimev1alpha1.ConditionReason = "AdoptionConflict"
+	ReasonNoAdoptionConflict runtimev1alpha1.ConditionReason = "NoAdoptionConflict"
+
+	ReasonUnsupportedEnv runtimev1alpha1.ConditionReason = "UnsupportedEnv"
+	ReasonEnvInjected    runtimev1alpha1.ConditionReason = "EnvInjected"
+
+	ReasonUnsupportedAffinity runtimev1alpha1.ConditionReason = "UnsupportedAffinity"
+	ReasonAffinityInjected    runtimev1alpha1.ConditionReason = "AffinityInjected"
+
+	ReasonUnsupportedTolerations runtimev1alpha1.ConditionReason = "UnsupportedTolerations"
+	ReasonTolerationsInjected    runtimev1alpha1.ConditionReason = "TolerationsInjected"
+
+	ReasonUnsupportedPriorityClass runtimev1alpha1.ConditionReason = "UnsupportedPriorityClass"
+	ReasonPriorityClassInjected    runtimev1alpha1.ConditionReason = "PriorityClassInjected"
+
+	ReasonUnsupportedNodeSelector runtimev1alpha1.ConditionReason = "UnsupportedNodeSelector"
+	ReasonNodeSelectorInjected    runtimev1alpha1.ConditionReason = "NodeSelectorInjected"
+
+	ReasonUnsupportedHostNetwork runtimev1alpha1.ConditionReason = "UnsupportedHostNetwork"
+	ReasonHostNetworkInjected    runtimev1alpha1.ConditionReason = "HostNetworkInjected"
+
+	ReasonUnsupportedLivenessProbe runtimev1alpha1.ConditionReason = "UnsupportedLivenessProbe"
+	ReasonLivenessProbeInjected    runtimev1alpha1.ConditionReason = "LivenessProbeInjected"
+
+	ReasonUnsupportedPodDisruptionBudget runtimev1alpha1.ConditionReason = "UnsupportedPodDisruptionBudget"
+	ReasonPodDisruptionBudgetsRendered   runtimev1alpha1.ConditionReason = "PodDisruptionBudgetsRendered"
+
+	ReasonUnsupportedServiceAccount runtimev1alpha1.ConditionReason = "UnsupportedServiceAccount"
+	ReasonServiceAccountInjected    runtimev1alpha1.ConditionReason = "ServiceAccountInjected"
+
+	ReasonUnsupportedReadinessProbe runtimev1alpha1.ConditionReason = "UnsupportedReadinessProbe"
+	ReasonReadinessProbeInjected    runtimev1alpha1.ConditionReason = "ReadinessProbeInjected"
+
+	ReasonUnsupportedSecurityContext runtimev1alpha1.ConditionReason = "UnsupportedSecurityContext"
+	ReasonSecurityContextInjected    runtimev1alpha1.ConditionReason = "SecurityContextInjected"
+
+	ReasonUnsupportedTrafficWeight runtimev1alpha1.ConditionReason = "UnsupportedTrafficWeight"
+	ReasonTrafficWeightsSplit      runtimev1alpha1.ConditionReason = "TrafficWeightsSplit"
+
+	ReasonDependencyCycle   runtimev1alpha1.ConditionReason = "DependencyCycle"
+	ReasonNoDependencyCycle runtimev1alpha1.ConditionReason = "NoDependencyCycle"
+
+	ReasonApplyTimeout runtimev1alpha1.ConditionReason = "ApplyTimeout"
+
+	ReasonComponentSuspended runtimev1alpha1.ConditionReason = "ComponentSuspended"
+	ReasonComponentResumed   runtimev1alpha1.ConditionReason = "ComponentResumed"
+
+	ReasonPreApplyHookFailed    runtimev1alpha1.ConditionReason = "PreApplyHookFailed"
+	ReasonPreApplyHookSucceeded runtimev1alpha1.ConditionReason = "PreApplyHookSucceeded"
+
+	ReasonPriorityClassNotFound runtimev1alpha1.ConditionReason = "PriorityClassNotFound"
+	ReasonPriorityClassesFound  runtimev1alpha1.ConditionReason = "PriorityClassesFound"
+
+	ReasonServiceAccountNotFound runtimev1alpha1.ConditionReason = "ServiceAccountNotFound"
+	ReasonServiceAccountsFound   runtimev1alpha1.ConditionReason = "ServiceAccountsFound"
+
+	ReasonPodSecurityViolation runtimev1alpha1.ConditionReason = "PodSecurityViolation"
+	ReasonPodSecurityCompliant runtimev1alpha1.ConditionReason = "PodSecurityCompliant"
+
+	ReasonPatchFailed    runtimev1alpha1.ConditionReason = "PatchFailed"
+	ReasonPatchesApplied runtimev1alpha1.ConditionReason = "PatchesApplied"
+
+	ReasonConfigMapNotFound runtimev1alpha1.ConditionReason = "ConfigMapNotFound"
+	ReasonConfigMapsFound   runtimev1alpha1.ConditionReason = "ConfigMapsFound"
+
+	ReasonSpecValidationFailed    runtimev1alpha1.ConditionReason = "SpecValidationFailed"
+	ReasonSpecValidationSucceeded runtimev1alpha1.ConditionReason = "SpecValidationSucceeded"
+
+	ReasonNameTemplateError    runtimev1alpha1.ConditionReason = "NameTemplateError"
+	ReasonNameTemplateRendered runtimev1alpha1.ConditionReason = "NameTemplateRendered"
+
+	ReasonSpecParseError runtimev1alpha1.ConditionReason = "SpecParseError"
+	ReasonSpecParsed     runtimev1alpha1.ConditionReason = "SpecParsed"
+
+	ReasonScopeIncompatible runtimev1alpha1.ConditionReason = "ScopeIncompatible"
+	ReasonScopesCompatible  runtimev1alpha1.ConditionReason = "ScopesCompatible"
+
+	ReasonDrifted         runtimev1alpha1.ConditionReason = "Drifted"
+	ReasonDriftRemediated runtimev1alpha1.ConditionReason = "DriftRemediated"
+	ReasonNoDrift         runtimev1alpha1.ConditionReason = "NoDrift"
+
+	ReasonTransientReconcileError runtimev1alpha1.ConditionReason = "TransientReconcileError"
+	ReasonPermanentReconcileError runtimev1alpha1.ConditionReason = "PermanentReconcileError"
+)
+
+// NewCondition returns a condition of the supplied type and status, set for
+// the supplied reason. The message may be empty.
+func NewCondition(t runtimev1alpha1.ConditionType, s corev1.ConditionStatus, r runtimev1alpha1.ConditionReason, msg string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               t,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// ApplyTimeout returns a condition indicating that a workload of an
+// ApplicationConfiguration could not be applied within its apply timeout.
+func ApplyTimeout(msg string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApplyTimeout,
+		Message:            msg,
+	}
+}
+
+// TransientReconcileError returns a condition indicating that an error that
+// may be resolved by retrying occurred while reconciling, e.g. a conflict or
+// an unavailable API server.
+func TransientReconcileError(err error) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTransientReconcileError,
+		Message:            err.Error(),
+	}
+}
+
+// PermanentReconcileError returns a condition indicating that an error that
+// will not be resolved by retrying occurred while reconciling, e.g. a
+// validation failure.
+func PermanentReconcileError(err error) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermanentReconcileError,
+		Message:            err.Error(),
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// A NamespaceParameterDefaultsSpec defines the desired state of a
+// NamespaceParameterDefaults.
+type NamespaceParameterDefaultsSpec struct {
+	// Defaults are the default parameter values of components, keyed by the
+	// kind of their workload and then by parameter name, e.g.
+	// {"Deployment": {"replicas": 2}}. A default is only used for components
+	// that accept the parameter and whose ApplicationConfiguration does not
+	// set it.
+	Defaults map[string]map[string]intstr.IntOrString `json:"defaults"`
+}
+
+// +kubebuilder:object:root=true
+
+// A NamespaceParameterDefaults sets default parameter values for the
+// components of every ApplicationConfiguration in its namespace. The defaults
+// of several NamespaceParameterDefaults in a namespace are merged in order of
+// their names; later names win.
+// +kubebuilder:resource:path=namespaceparameterdefaults,categories={crossplane,oam}
+type NamespaceParameterDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NamespaceParameterDefaultsSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceParameterDefaultsList contains a list of NamespaceParameterDefaults.
+type NamespaceParameterDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceParameterDefaults `json:"items"`
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplicationConfigurationEvent reasons.
+const (
+	// EventReasonRollback indicates that a component was rolled back to an
+	// earlier revision because the ApplicationConfiguration became unhealthy.
+	EventReasonRollback = "Rollback"
+)
+
+// An ApplicationConfigurationEventSpec describes something that happened to
+// an ApplicationConfiguration.
+type ApplicationConfigurationEventSpec struct {
+	// ApplicationConfigurationName is the name of the
+	// ApplicationConfiguration this event happened to.
+	ApplicationConfigurationName string `json:"applicationConfigurationName"`
+
+	// ComponentName is the name of the component this event happened to.
+	// +optional
+	ComponentName string `json:"componentName,omitempty"`
+
+	// Reason this event happened.
+	Reason string `json:"reason"`
+
+	// FromRevision is the component revision that was in use before this
+	// event happened.
+	// +optional
+	FromRevision string `json:"fromRevision,omitempty"`
+
+	// ToRevision is the component revision that was in use after this event
+	// happened.
+	// +optional
+	ToRevision string `json:"toRevision,omitempty"`
+
+	// Timestamp at which this event happened.
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// +kubebuilder:object:root=true
+
+// An ApplicationConfigurationEvent is a durable record of something that
+// happened to an ApplicationConfiguration, e.g. a rollback of one of its
+// components. Events are owned by, and deleted along with, their
+// ApplicationConfiguration.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:printcolumn:JSONPath=".spec.applicationConfigurationName",name=APPCONFIG,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.reason",name=REASON,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.fromRevision",name=FROM,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.toRevision",name=TO,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.timestamp",name=AGE,type=date
+type ApplicationConfigurationEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationConfigurationEventSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationConfigurationEventList contains a list of
+// ApplicationConfigurationEvent.
+type ApplicationConfigurationEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationConfigurationEvent `json:"items"`
+}
+
+// A ComponentEventSpec describes an event emitted by a component of an
+// ApplicationConfiguration.
+type ComponentEventSpec struct {
+	// ApplicationConfigurationName is the name of the
+	// ApplicationConfiguration whose component emitted this event.
+	ApplicationConfigurationName string `json:"applicationConfigurationName"`
+
+	// ComponentName is the name of the component that emitted this event.
+	ComponentName string `json:"componentName"`
+
+	// EventName is the name of this event.
+	EventName string `json:"eventName"`
+
+	// Value of the events.oam.dev/<name> annotation of the component's
+	// workload that emitted this event.
+	Value string `json:"value"`
+
+	// Timestamp at which this event was observed.
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ComponentEvent is a notification that a component of an
+// ApplicationConfiguration emitted an event to the components that subscribe
+// to it. ComponentEvents are owned by, and deleted along with, their
+// ApplicationConfiguration.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:printcolumn:JSONPath=".spec.applicationConfigurationName",name=APPCONFIG,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.componentName",name=COMPONENT,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.eventName",name=EVENT,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.timestamp",name=AGE,type=date
+type ComponentEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ComponentEventSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComponentEventList contains a list of ComponentEvent.
+type ComponentEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComponentEvent `json:"items"`
+}
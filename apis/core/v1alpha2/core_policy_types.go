@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A TraitPolicySpec defines the desired state of a TraitPolicy.
+type TraitPolicySpec struct {
+	// Selector of the ApplicationConfigurations to whose components traits
+	// are injected, by label. An empty selector selects all
+	// ApplicationConfigurations.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// InjectTrait specifies the traits injected into every component of the
+	// selected ApplicationConfigurations. A trait is not injected into a
+	// component that already has a trait of the same kind.
+	InjectTrait []ComponentTrait `json:"injectTrait"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TraitPolicy injects traits into the components of ApplicationConfigurations
+// when they are created or updated, e.g. to ensure every component has an
+// observability sidecar.
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+type TraitPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TraitPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TraitPolicyList contains a list of TraitPolicy.
+type TraitPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TraitPolicy `json:"items"`
+}
@@ -32,6 +32,47 @@ type HealthScopeSpec struct {
 
 	// WorkloadReferences to the workloads that are in this scope.
 	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs,omitempty"`
+
+	// HistoryRetentionCount is the number of health check results retained in
+	// the health history. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	HistoryRetentionCount *int32 `json:"historyRetentionCount,omitempty"`
+
+	// WorkloadPrometheusEndpoints specify Prometheus queries that determine
+	// the health of workloads in this scope, in addition to the health of
+	// their resources.
+	// +optional
+	WorkloadPrometheusEndpoints []WorkloadPrometheusEndpoint `json:"workloadPrometheusEndpoints,omitempty"`
+}
+
+// A WorkloadPrometheusEndpoint specifies a Prometheus query that determines
+// the health of a workload.
+type WorkloadPrometheusEndpoint struct {
+	// WorkloadName is the name of the workload whose health is queried. It
+	// must be the name of a workload in this scope.
+	WorkloadName string `json:"workloadName"`
+
+	// PrometheusURL is the base URL of the Prometheus server, e.g.
+	// http://prometheus.monitoring:9090.
+	PrometheusURL string `json:"prometheusURL"`
+
+	// HealthQuery is a PromQL query that returns a scalar or a single element
+	// vector, e.g. sum(rate(http_requests_total{code="200"}[1m])).
+	HealthQuery string `json:"healthQuery"`
+
+	// HealthyThreshold is the decimal value the result of the query must be
+	// above for the workload to be healthy, e.g. "0.95".
+	HealthyThreshold string `json:"healthyThreshold"`
+}
+
+// A HealthHistoryEntry records the result of a health check.
+type HealthHistoryEntry struct {
+	// Time at which the health check ran.
+	Time metav1.Time `json:"time"`
+
+	// Health is the aggregate health of the scope's workloads.
+	Health string `json:"health"`
 }
 
 // A HealthScopeStatus represents the observed state of a HealthScope.
@@ -39,6 +80,11 @@ type HealthScopeStatus struct {
 	runtimev1alpha1.ConditionedStatus `json:",inline"`
 
 	Health string `json:"health"`
+
+	// HealthHistory holds the results of the most recent health checks,
+	// oldest first.
+	// +optional
+	HealthHistory []HealthHistoryEntry `json:"healthHistory,omitempty"`
 }
 
 // +kubebuilder:object:root=true
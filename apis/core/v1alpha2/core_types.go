@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
@@ -54,6 +56,118 @@ type WorkloadDefinitionSpec struct {
 
 	// ChildResourceKinds are the list of GVK of the child resources this workload generates
 	ChildResourceKinds []ChildResourceKind `json:"childResourceKinds,omitempty"`
+
+	// ReplicaPath is the field path at which the replicas of a component of
+	// this workload kind are set. Defaults to spec.replicas.
+	// +optional
+	ReplicaPath string `json:"replicaPath,omitempty"`
+
+	// RequiredTraits specifies the kinds of traits that must be attached to
+	// every component of this workload kind.
+	// +optional
+	RequiredTraits []TraitKindReference `json:"requiredTraits,omitempty"`
+
+	// Schematic specifies a template for workloads of this kind. Components
+	// of this workload kind are rendered on top of the template.
+	// +optional
+	Schematic *Schematic `json:"schematic,omitempty"`
+
+	// Categories of this workload kind, e.g. database or stateful. They
+	// allow tooling to browse the available workload kinds.
+	// +optional
+	Categories []string `json:"categories,omitempty"`
+
+	// Tags are free-form key-value pairs that describe this workload kind.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Bindable indicates that workloads of this kind expose connection
+	// information that other components may be bound to.
+	// +optional
+	Bindable bool `json:"bindable,omitempty"`
+
+	// BindingPath is the field path of the connection information in the
+	// status of workloads of this kind, e.g. status.binding. The connection
+	// information must be an object whose keys are the names of the values
+	// it contains. It is required if the workload kind is bindable.
+	// +optional
+	BindingPath string `json:"bindingPath,omitempty"`
+
+	// RevisionGarbageCollector specifies how the old revisions of workloads
+	// of this kind, e.g. the ReplicaSets of a Deployment, are garbage
+	// collected after the workloads are updated.
+	// +optional
+	RevisionGarbageCollector *RevisionGarbageCollector `json:"revisionGarbageCollector,omitempty"`
+}
+
+// A RevisionGarbageCollector garbage collects the surplus old revisions of a
+// workload. Revisions are the resources of a kind that are controlled by the
+// workload. The newest revision, and revisions that are still in use, are
+// never garbage collected.
+type RevisionGarbageCollector struct {
+	// APIVersion of the revision resource kind, e.g. apps/v1.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the revision resource, e.g. ReplicaSet or ControllerRevision.
+	Kind string `json:"kind"`
+
+	// GarbageCollectPath is the field path of a revision that indicates
+	// whether it is still in use, e.g. status.replicas. Revisions with a
+	// non-zero value at this path are in use. All old revisions are
+	// considered unused if it is empty.
+	// +optional
+	GarbageCollectPath string `json:"garbageCollectPath,omitempty"`
+
+	// RevisionHistoryLimit is the number of unused old revisions of a
+	// workload that are retained. Defaults to the workload's
+	// spec.revisionHistoryLimit, or 10 if it has none.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// A Schematic specifies where the template of a kind of workload is stored.
+type Schematic struct {
+	// OCI specifies a template that is stored as an OCI artifact.
+	// +optional
+	OCI *OCISchematic `json:"oci,omitempty"`
+
+	// Kustomize specifies a template that is built from a kustomization
+	// stored in a Git repository.
+	// +optional
+	Kustomize *KustomizeSchematic `json:"kustomize,omitempty"`
+}
+
+// An OCISchematic specifies a template that is stored as an OCI artifact,
+// e.g. one pushed using oras. The artifact's YAML files, or the YAML files of
+// any tarballs it contains, are the template; the manifest of the workload's
+// kind is used.
+type OCISchematic struct {
+	// Ref is the reference of the OCI artifact, e.g.
+	// ghcr.io/example/schematics:v1 or
+	// ghcr.io/example/schematics@sha256:0123...
+	Ref string `json:"ref"`
+}
+
+// A KustomizeSchematic specifies a template that is built by running
+// kustomize build on a kustomization stored in a Git repository. The manifest
+// of the workload's kind in the build output is used.
+type KustomizeSchematic struct {
+	// URL of the Git repository, e.g. https://github.com/example/schematics.
+	// Absolute paths and file:// URLs are read from the local filesystem of
+	// the controller, and are intended for testing.
+	URL string `json:"url"`
+
+	// Path of the kustomization within the repository. Defaults to the root
+	// of the repository.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// A WorkloadDefinitionStatus represents the observed state of a
+// WorkloadDefinition.
+type WorkloadDefinitionStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
@@ -64,11 +178,13 @@ type WorkloadDefinitionSpec struct {
 // Component.
 // +kubebuilder:printcolumn:JSONPath=".spec.definitionRef.name",name=DEFINITION-NAME,type=string
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+// +kubebuilder:subresource:status
 type WorkloadDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec WorkloadDefinitionSpec `json:"spec,omitempty"`
+	Spec   WorkloadDefinitionSpec   `json:"spec,omitempty"`
+	Status WorkloadDefinitionStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -89,16 +205,94 @@ type TraitDefinitionSpec struct {
 	// +optional
 	RevisionEnabled bool `json:"revisionEnabled,omitempty"`
 
-	// WorkloadRefPath indicates where/if a trait accepts a workloadRef object
+	// WorkloadRefPath indicates where/if a trait accepts a workloadRef object.
+	// The path may contain a {containerName} placeholder, which is replaced
+	// with TargetContainerName.
 	// +optional
 	WorkloadRefPath string `json:"workloadRefPath,omitempty"`
 
+	// TargetContainerName is the name of the container of the workload that
+	// this trait targets, for traits that inject values into a specific
+	// container rather than all containers.
+	// +optional
+	TargetContainerName string `json:"targetContainerName,omitempty"`
+
 	// AppliesToWorkloads specifies the list of workload kinds this trait
 	// applies to. Workload kinds are specified in kind.group/version format,
 	// e.g. server.core.oam.dev/v1alpha2. Traits that omit this field apply to
 	// all workload kinds.
 	// +optional
 	AppliesToWorkloads []string `json:"appliesToWorkloads,omitempty"`
+
+	// ConflictsWith specifies the kinds of traits that may not be applied to
+	// the same workload as this trait.
+	// +optional
+	ConflictsWith []TraitKindReference `json:"conflictsWith,omitempty"`
+
+	// MergeStrategy determines how traits of this kind are applied to traits
+	// that already exist. Traits are patched with their rendered spec when it
+	// is replace. When it is merge they are patched using a three-way
+	// strategic merge with the trait as it was last applied, similar to
+	// kubectl apply, so that fields set by others are preserved. Custom
+	// resources, which have no strategic merge metadata, use a three-way JSON
+	// merge. Defaults to replace.
+	// +kubebuilder:validation:Enum=replace;merge
+	// +optional
+	MergeStrategy MergeStrategy `json:"mergeStrategy,omitempty"`
+
+	// Priority of traits of this kind. The traits of a component are applied
+	// in ascending, and pruned in descending order of priority. Defaults to
+	// zero.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// PrunedResources are the kinds of cluster scoped resources, such as
+	// ClusterRoleBindings, that the controller of traits of this kind may
+	// create. Resources of these kinds that are labelled with the UID of a
+	// component are deleted when the component is removed from its
+	// ApplicationConfiguration.
+	// +optional
+	PrunedResources []PrunedResourceKind `json:"prunedResources,omitempty"`
+}
+
+// A PrunedResourceKind is a kind of cluster scoped resource that is pruned
+// when the component that created it is removed.
+type PrunedResourceKind struct {
+	// APIVersion of the resource kind.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+}
+
+// A MergeStrategy determines how a trait is applied to a trait that already
+// exists.
+type MergeStrategy string
+
+// Merge strategies.
+const (
+	MergeStrategyReplace MergeStrategy = "replace"
+	MergeStrategyMerge   MergeStrategy = "merge"
+)
+
+// A TraitKindReference refers to a kind of trait.
+type TraitKindReference struct {
+	// APIVersion of the referenced trait kind.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced trait kind.
+	Kind string `json:"kind"`
+}
+
+// A TraitDefinitionStatus represents the observed state of a TraitDefinition.
+type TraitDefinitionStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// InstalledVersion is the version of the installed CustomResourceDefinition
+	// that defines this trait kind, read from its helm.sh/chart or
+	// app.kubernetes.io/version annotation.
+	// +optional
+	InstalledVersion string `json:"installedVersion,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -108,12 +302,15 @@ type TraitDefinitionSpec struct {
 // to validate the schema of the trait when it is embedded in an OAM
 // ApplicationConfiguration.
 // +kubebuilder:printcolumn:JSONPath=".spec.definitionRef.name",name=DEFINITION-NAME,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.installedVersion",name=INSTALLED-VERSION,type=string
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+// +kubebuilder:subresource:status
 type TraitDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec TraitDefinitionSpec `json:"spec,omitempty"`
+	Spec   TraitDefinitionSpec   `json:"spec,omitempty"`
+	Status TraitDefinitionStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -133,6 +330,14 @@ type ScopeDefinitionSpec struct {
 	// AllowComponentOverlap specifies whether an OAM component may exist in
 	// multiple instances of this kind of scope.
 	AllowComponentOverlap bool `json:"allowComponentOverlap"`
+
+	// AppliesToWorkloads specifies the list of workload kinds that may be
+	// members of this kind of scope. Workload kinds are specified in
+	// kind.group/version format, e.g. server.core.oam.dev/v1alpha2, or by the
+	// name of their WorkloadDefinition. Scopes that omit this field apply to
+	// all workload kinds.
+	// +optional
+	AppliesToWorkloads []string `json:"appliesToWorkloads,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -180,6 +385,12 @@ type ComponentParameter struct {
 	// +optional
 	Required *bool `json:"required,omitempty"`
 
+	// Type of this parameter. ApplicationConfigurations that enable strict
+	// parameter validation must supply a value of this type.
+	// +kubebuilder:validation:Enum=string;integer;number;boolean
+	// +optional
+	Type *string `json:"type,omitempty"`
+
 	// Description of this parameter.
 	// +optional
 	Description *string `json:"description,omitempty"`
@@ -243,6 +454,15 @@ type ComponentList struct {
 	Items           []Component `json:"items"`
 }
 
+// A ComponentPodDisruptionBudget configures the PodDisruptionBudget of a
+// component's workload.
+type ComponentPodDisruptionBudget struct {
+	// MinAvailable is the number, or percentage (e.g. "50%"), of the
+	// workload's pods that must remain available during a voluntary
+	// disruption.
+	MinAvailable intstr.IntOrString `json:"minAvailable"`
+}
+
 // A ComponentParameterValue specifies a value for a named parameter. The
 // associated component must publish a parameter with this name.
 type ComponentParameterValue struct {
@@ -251,6 +471,56 @@ type ComponentParameterValue struct {
 
 	// Value to set.
 	Value intstr.IntOrString `json:"value"`
+
+	// Encrypted indicates that Value is a document encrypted using SOPS. It is
+	// decrypted by the controller before the component is rendered.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+// A ComponentEventSubscription subscribes a component to an event emitted by
+// another component.
+type ComponentEventSubscription struct {
+	// ComponentName of the component that emits the event.
+	ComponentName string `json:"componentName"`
+
+	// EventName of the event.
+	EventName string `json:"eventName"`
+}
+
+// A ComponentTrafficWeight specifies the percentages of a Deployment
+// workload's spec.replicas run by its stable and canary Deployments.
+type ComponentTrafficWeight struct {
+	// Stable is the percentage of replicas run by the stable Deployment.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Stable int32 `json:"stable"`
+
+	// Canary is the percentage of replicas run by the canary Deployment.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Canary int32 `json:"canary"`
+}
+
+// A PatchOperation is an RFC 6902 JSON patch operation.
+type PatchOperation struct {
+	// Op is the operation to perform.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+
+	// Path is the JSON pointer to the location at which the operation is
+	// performed, e.g. /spec/template/spec/containers/0/image.
+	Path string `json:"path"`
+
+	// From is the JSON pointer to the location from which a move or copy
+	// operation moves or copies a value.
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// Value that an add, replace, or test operation uses.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value *runtime.RawExtension `json:"value,omitempty"`
 }
 
 // A ComponentTrait specifies a trait that should be applied to a component.
@@ -297,6 +567,204 @@ type ApplicationConfigurationComponent struct {
 	// DataInputs specify the data input sinks into this component.
 	DataInputs []DataInput `json:"dataInputs,omitempty"`
 
+	// Outputs expose fields of this component's workload to other components
+	// of the same ApplicationConfiguration.
+	// +optional
+	Outputs []ComponentOutput `json:"outputs,omitempty"`
+
+	// Inputs inject the outputs of other components of the same
+	// ApplicationConfiguration into this component's parameters. A component
+	// with inputs is not rendered until all of its inputs can be resolved.
+	// +optional
+	Inputs []ComponentInput `json:"inputs,omitempty"`
+
+	// ImageOverrides replace the images of the rendered workload's containers,
+	// keyed by container name. Containers are found in the pod template of
+	// the workload (spec.template.spec.containers) unless ImageOverridePath is
+	// set. This is a best-effort override; workloads with no containers at the
+	// expected path are left unchanged.
+	// +optional
+	ImageOverrides map[string]string `json:"imageOverrides,omitempty"`
+
+	// Replicas of the rendered workload. Replicas are set at the replica path
+	// of the workload's WorkloadDefinition, or spec.replicas if it specifies
+	// none. Replicas may not be set if a parameter value of this component
+	// already sets the replica path.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Suspended components have the replicas of their workload set to zero.
+	// Replicas are restored from the component when it is no longer
+	// suspended.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// Env variables injected into the containers of the rendered workload's
+	// pod template (spec.template.spec.containers). Variables replace any of
+	// the same name already set by the component. Workloads without a pod
+	// template are applied without them, and the ApplicationConfiguration
+	// reports that they could not be injected.
+	// +optional
+	Env []ComponentEnvVar `json:"env,omitempty"`
+
+	// Affinity of the pods of the rendered workload. It is merged with any
+	// affinity in the workload's pod template (spec.template.spec.affinity).
+	// Workloads without a pod template are applied without it.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations of the pods of the rendered workload. They are merged with
+	// any tolerations in the workload's pod template
+	// (spec.template.spec.tolerations). Workloads without a pod template are
+	// applied without them.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector of the pods of the rendered workload. It is merged with
+	// any node selector in the workload's pod template
+	// (spec.template.spec.nodeSelector), whose labels take precedence.
+	// Workloads without a pod template are applied without it.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// HostNetwork enables host network mode for the pods of the rendered
+	// workload (spec.template.spec.hostNetwork). It may only be enabled in
+	// namespaces annotated with oam.dev/allow-host-network: "true".
+	// Workloads without a pod template are applied without it.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// LivenessProbe of the main container of the rendered workload's pod
+	// template. It replaces any liveness probe the component specifies. A
+	// probe without a handler removes the component's liveness probe.
+	// Workloads without a pod template are applied without it.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// ReadinessProbe of the main container of the rendered workload's pod
+	// template. It replaces any readiness probe the component specifies. A
+	// probe without a handler removes the component's readiness probe.
+	// Workloads without a pod template are applied without it.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// MainContainerName is the name of the container of the rendered
+	// workload's pod template to which LivenessProbe and ReadinessProbe
+	// apply. Defaults to the first container.
+	// +optional
+	MainContainerName string `json:"mainContainerName,omitempty"`
+
+	// PodDisruptionBudget that is created alongside the rendered workload,
+	// selecting its pods by the workload's spec.selector.matchLabels. The
+	// PodDisruptionBudget is controlled by the workload, and deleted along
+	// with it. Workloads without a label selector are applied without one.
+	// +optional
+	PodDisruptionBudget *ComponentPodDisruptionBudget `json:"pdb,omitempty"`
+
+	// WorkloadNameTemplate is a Go template from which the name of the
+	// rendered workload is rendered, allowing a component to be instantiated
+	// more than once in the same namespace. It may reference
+	// {{.AppConfigName}}, {{.ComponentName}}, {{.Namespace}} and
+	// {{.Revision}}. The name defined by the component is used if the
+	// template cannot be rendered.
+	// +optional
+	WorkloadNameTemplate string `json:"workloadNameTemplate,omitempty"`
+
+	// DependsOnKinds are the kinds of workload, in the form
+	// <apiVersion>/<kind> (e.g. v1/PersistentVolumeClaim), that must be
+	// applied and healthy before this component's workload is applied. Kinds
+	// that no other component of the ApplicationConfiguration renders are
+	// ignored.
+	// +optional
+	DependsOnKinds []string `json:"dependsOnKinds,omitempty"`
+
+	// PriorityClassName of the pods of the rendered workload. It replaces any
+	// priority class in the workload's pod template
+	// (spec.template.spec.priorityClassName). Workloads without a pod
+	// template are applied without it.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// TerminationGracePeriodSeconds of the pods of the rendered workload. It
+	// replaces any grace period in the workload's pod template
+	// (spec.template.spec.terminationGracePeriodSeconds), and takes precedence
+	// over the ApplicationConfiguration's terminationGracePeriodSeconds.
+	// Workloads without a pod template are applied without it.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// ServiceAccountName of the pods of the rendered workload. It replaces
+	// any service account in the workload's pod template
+	// (spec.template.spec.serviceAccountName). Workloads without a pod
+	// template are applied without it.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// CreateServiceAccount creates the service account named by
+	// serviceAccountName, without any permissions, if it does not exist. The
+	// created service account is owned by the ApplicationConfiguration.
+	// +optional
+	CreateServiceAccount bool `json:"createServiceAccount,omitempty"`
+
+	// SecurityContext of the pods of the rendered workload. It is merged into
+	// the workload's pod template (spec.template.spec.securityContext), with
+	// the fields set here taking precedence. Workloads are only applied if
+	// the merged security context complies with the PodSecurity admission
+	// level enforced on their namespace. Workloads without a pod template are
+	// applied without it.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// Patches are RFC 6902 JSON patch operations that are applied to the
+	// rendered workload after parameter values have been substituted into
+	// it, but before it is applied.
+	// +optional
+	Patches []PatchOperation `json:"patches,omitempty"`
+
+	// Emits are the names of the events this component may emit. The
+	// workload of a component emits an event by changing the value of its
+	// events.oam.dev/<name> annotation. Emitted events are recorded as
+	// ComponentEvents when the ApplicationConfiguration is reconciled.
+	// +optional
+	Emits []string `json:"emits,omitempty"`
+
+	// SubscribesTo the events emitted by other components of this
+	// ApplicationConfiguration. The workload of this component is updated
+	// with the name of the latest ComponentEvent it subscribes to, as its
+	// oam.dev/last-received-event annotation, whenever one is emitted.
+	// +optional
+	SubscribesTo []ComponentEventSubscription `json:"subscribesTo,omitempty"`
+
+	// TrafficWeight splits the replicas of a Deployment workload between a
+	// stable and a canary Deployment, each selected by its own Service. While
+	// it is set, updates to the component are rolled out to the canary
+	// Deployment, and the stable Deployment is only updated once it receives
+	// no weight. It is ignored for workloads that are not Deployments.
+	// +optional
+	TrafficWeight *ComponentTrafficWeight `json:"trafficWeight,omitempty"`
+
+	// ServiceBindingRef binds the specified component to the workload of
+	// another component of the same ApplicationConfiguration, whose
+	// WorkloadDefinition must be bindable. The connection information of the
+	// bound workload is injected into the containers of the specified
+	// component's pod template.
+	// +optional
+	ServiceBindingRef *ServiceBindingReference `json:"serviceBindingRef,omitempty"`
+
+	// ConfigMapVolumes are mounted into the containers of the rendered
+	// workload's pod template (spec.template.spec.containers).
+	// +optional
+	ConfigMapVolumes []ComponentConfigMapVolume `json:"configMapVolumes,omitempty"`
+
+	// ImageOverridePath is the field path of the list of containers to which
+	// ImageOverrides are applied, for workload types that do not use a pod
+	// template, e.g. spec.containers. Each container must have a name and an
+	// image field.
+	// +optional
+	ImageOverridePath string `json:"imageOverridePath,omitempty"`
+
 	// ParameterValues specify values for the the specified component's
 	// parameters. Any parameter required by the component must be specified.
 	// +optional
@@ -309,14 +777,451 @@ type ApplicationConfigurationComponent struct {
 	// Scopes in which the specified component should exist.
 	// +optional
 	Scopes []ComponentScope `json:"scopes,omitempty"`
+
+	// HealthProbe of the specified component's workload. The workload's
+	// health is reported in the status of the ApplicationConfiguration.
+	// +optional
+	HealthProbe *ComponentHealthProbe `json:"healthProbe,omitempty"`
+
+	// StatusPollInterval is the interval at which the specified component's
+	// workload is probed using its health probe, and its health updated in
+	// the status of the ApplicationConfiguration, between reconciles. The
+	// workload is only probed when the ApplicationConfiguration is reconciled
+	// if it is not set.
+	// +optional
+	StatusPollInterval *metav1.Duration `json:"statusPollInterval,omitempty"`
+
+	// ApplyAs specifies the identity with which the specified component's
+	// workload and traits are applied. They are applied by the controller
+	// unless ApplyAs is set.
+	// +optional
+	ApplyAs *ComponentApplyAs `json:"applyAs,omitempty"`
+
+	// ApplyTimeout bounds how long applying the specified component's workload
+	// may take. A workload that times out is reported in the status of the
+	// ApplicationConfiguration, and the remaining components are applied.
+	// Applying is bounded only by the timeout of the whole reconcile if it is
+	// not set.
+	// +optional
+	ApplyTimeout *metav1.Duration `json:"applyTimeout,omitempty"`
+}
+
+// A ServiceBindingReference binds a component to the workload of another
+// component.
+type ServiceBindingReference struct {
+	// ComponentName of the component whose workload is bound.
+	ComponentName string `json:"componentName"`
+
+	// MountPath at which the connection information is mounted as a volume,
+	// with a file per value. The values are injected as environment
+	// variables if it is not set.
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// A ComponentConfigMapVolume is a ConfigMap mounted as a volume into the
+// containers of a component's workload.
+type ComponentConfigMapVolume struct {
+	// ConfigMapName is the name of the ConfigMap, which must be in the
+	// namespace of the ApplicationConfiguration.
+	ConfigMapName string `json:"configMapName"`
+
+	// MountPath at which the ConfigMap is mounted, with a file per key.
+	MountPath string `json:"mountPath"`
+
+	// ContainerName of the container into which the ConfigMap is mounted. It
+	// is mounted into all containers if this is not set.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+}
+
+// A ComponentEnvVar is an environment variable injected into the containers
+// of a component's workload.
+type ComponentEnvVar struct {
+	// Name of the environment variable.
+	Name string `json:"name"`
+
+	// Value of the environment variable.
+	Value string `json:"value"`
+}
+
+// A ComponentApplyAs specifies the identity with which a component's workload
+// and traits are applied.
+type ComponentApplyAs struct {
+	// ServiceAccountRef references the service account to impersonate. The
+	// service account must exist in the namespace to which the component is
+	// applied, and must be permitted to create and update the component's
+	// workload and traits.
+	ServiceAccountRef ServiceAccountReference `json:"serviceAccountRef"`
+}
+
+// A ServiceAccountReference refers to a service account in the same namespace
+// as the referrer.
+type ServiceAccountReference struct {
+	// Name of the referenced service account.
+	Name string `json:"name"`
+}
+
+// A ComponentHealthProbe determines whether a component's workload is
+// healthy.
+type ComponentHealthProbe struct {
+	// HTTPGet probes the workload by sending an HTTP GET request.
+	HTTPGet *ComponentHTTPGetProbe `json:"httpGet,omitempty"`
+}
+
+// A ComponentHTTPGetProbe considers a workload healthy if an HTTP GET request
+// to its URL returns the expected status code.
+type ComponentHTTPGetProbe struct {
+	// URL to which the HTTP GET request is sent. Its host must be a Service,
+	// or the IP address of a pod, in the namespace of the
+	// ApplicationConfiguration, e.g.
+	// http://example.default.svc.cluster.local:8080/healthz.
+	URL string `json:"url"`
+
+	// ExpectedStatusCode of the HTTP response. Defaults to 200.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatusCode *int32 `json:"expectedStatusCode,omitempty"`
 }
 
 // An ApplicationConfigurationSpec defines the desired state of a
 // ApplicationConfiguration.
 type ApplicationConfigurationSpec struct {
 	// Components of which this ApplicationConfiguration consists. Each
-	// component will be used to instantiate a workload.
-	Components []ApplicationConfigurationComponent `json:"components"`
+	// component will be used to instantiate a workload. Components are read
+	// from the SpecSource instead if it is set.
+	// +optional
+	Components []ApplicationConfigurationComponent `json:"components,omitempty"`
+
+	// SpecSource from which the components of this ApplicationConfiguration
+	// are read, e.g. a ConfigMap managed by a GitOps tool. Inline components
+	// are ignored when it is set.
+	// +optional
+	SpecSource *SpecSource `json:"specSource,omitempty"`
+
+	// NamespaceSelector selects additional namespaces to which the workloads
+	// and traits of this ApplicationConfiguration are applied. Workloads are
+	// always applied to the namespace of the ApplicationConfiguration. Copies
+	// in additional namespaces are labelled oam.dev/copy-of, and are deleted
+	// before the ApplicationConfiguration is.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// NamespaceLabels are applied to the namespace of this
+	// ApplicationConfiguration, in the TargetCluster if one is set. The
+	// namespace is created if it does not exist. NamespaceLabels are ignored
+	// unless the OAM runtime was started with --allow-namespace-creation.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// ReconcilePolicy determines when the workloads and traits of this
+	// ApplicationConfiguration are applied. Workloads and traits are applied
+	// every time the ApplicationConfiguration is reconciled if no policy is set.
+	// +optional
+	ReconcilePolicy *ReconcilePolicy `json:"reconcilePolicy,omitempty"`
+
+	// ProbeTimeoutSeconds is the number of seconds after which a health
+	// probe of a component times out. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProbeTimeoutSeconds *int32 `json:"probeTimeoutSeconds,omitempty"`
+
+	// MaxConcurrentApply is the number of workloads, along with their traits
+	// and scopes, that may be applied at once. Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentApply *int32 `json:"maxConcurrentApply,omitempty"`
+
+	// TerminationGracePeriodSeconds of the pods of all rendered workloads
+	// that embed a pod template, unless their component specifies its own.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// VaultCacheTTL is how long parameter values resolved from Vault secrets,
+	// i.e. values of the form vault://<path>#<key>, are cached. Defaults to
+	// 5m.
+	// +optional
+	VaultCacheTTL *metav1.Duration `json:"vaultCacheTTL,omitempty"`
+
+	// Encryption specifies how the sensitive parameter values of this
+	// ApplicationConfiguration are encrypted. Parameter values are sensitive
+	// if the ApplicationConfiguration is annotated oam.dev/sensitive=true.
+	// +optional
+	Encryption *SpecEncryption `json:"encryption,omitempty"`
+
+	// ComponentGroups group components so that they are rolled out together.
+	// Groups are rolled out in order; a group is not applied until the groups
+	// before it are healthy, though the controller may be configured to roll
+	// out more than one group at a time. A component is healthy once its
+	// current revision has been applied and its health probe, if any,
+	// succeeds. Components that are not in a group are applied immediately.
+	// Groups only govern the namespace of the ApplicationConfiguration;
+	// workloads are applied to selected namespaces immediately.
+	// +optional
+	ComponentGroups []ComponentGroup `json:"componentGroups,omitempty"`
+
+	// AdoptionPolicy determines what happens when a workload or trait of this
+	// ApplicationConfiguration already exists, but was not created by it. The
+	// policy applies to the namespace of the ApplicationConfiguration; objects
+	// in selected namespaces are always overwritten. Defaults to overwrite.
+	// +kubebuilder:validation:Enum=overwrite;adopt;reject
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// DriftPolicy determines what happens when a workload of this
+	// ApplicationConfiguration no longer matches the spec it was last
+	// applied with, for example because it was edited by hand. Only the
+	// fields of the last applied spec are compared, so fields defaulted by
+	// the API server are not considered drift. Defaults to inform.
+	// +kubebuilder:validation:Enum=inform;remediate;alert
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// RevisionHistoryLimit is the number of revisions of each of the
+	// specified components that are kept. Older revisions are deleted, unless
+	// an ApplicationConfiguration still uses them. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Rollback configures whether components are rolled back to their last
+	// healthy revision when this ApplicationConfiguration becomes unhealthy.
+	// +optional
+	Rollback *RollbackPolicy `json:"rollback,omitempty"`
+
+	// RolloutGroup of this ApplicationConfiguration. The
+	// ApplicationConfigurations of a rollout group in the same namespace are
+	// rolled out in ascending RolloutOrder; each is paused until those before
+	// it are healthy.
+	// +optional
+	RolloutGroup string `json:"rolloutGroup,omitempty"`
+
+	// RolloutOrder of this ApplicationConfiguration within its RolloutGroup.
+	// ApplicationConfigurations of the same order are rolled out in order of
+	// name.
+	// +optional
+	RolloutOrder int32 `json:"rolloutOrder,omitempty"`
+
+	// RolloutStepTimeout is how long this ApplicationConfiguration may take
+	// to become healthy once its RolloutGroup rolls it out. The rolled out
+	// ApplicationConfigurations of the group are rolled back if it does not.
+	// Defaults to 10m.
+	// +optional
+	RolloutStepTimeout *metav1.Duration `json:"rolloutStepTimeout,omitempty"`
+
+	// TargetCluster to which the workloads and traits of this
+	// ApplicationConfiguration are applied. They are applied to the cluster
+	// of the ApplicationConfiguration if it is not set. Scopes and the
+	// NamespaceSelector are not supported when a TargetCluster is set.
+	// +optional
+	TargetCluster *TargetCluster `json:"targetCluster,omitempty"`
+
+	// GlobalVariables are substituted for ${{variable}} placeholders in the
+	// specs of all rendered workloads. Placeholders of unknown variables are
+	// left as is, unless the ApplicationConfiguration is annotated with
+	// oam.dev/strict-global-variables: "true", in which case they fail
+	// rendering.
+	// +optional
+	GlobalVariables map[string]string `json:"globalVariables,omitempty"`
+
+	// GlobalTraits are appended to the traits of every component of this
+	// ApplicationConfiguration before it is rendered. A trait of a component
+	// overrides a global trait of the same apiVersion and kind. No two global
+	// traits may be of the same apiVersion and kind.
+	// +optional
+	GlobalTraits []ComponentTrait `json:"globalTraits,omitempty"`
+
+	// PreApplyHook is called before the workloads and traits of this
+	// ApplicationConfiguration are applied. They are only applied if the
+	// hook succeeds.
+	// +optional
+	PreApplyHook *PreApplyHook `json:"preApplyHook,omitempty"`
+}
+
+// A PreApplyHook is an HTTPS endpoint to which the rendered workloads of an
+// ApplicationConfiguration are POSTed as JSON before they are applied. The
+// workloads of components with encrypted parameter values are redacted.
+type PreApplyHook struct {
+	// Service that serves the hook. The hook succeeds if it returns 200 OK.
+	Service HookServiceReference `json:"service"`
+
+	// CABundle is a PEM encoded CA bundle used to verify the serving
+	// certificate of the hook.
+	CABundle []byte `json:"caBundle"`
+
+	// TimeoutSeconds is the number of seconds after which a call to the
+	// hook times out. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// A HookServiceReference is a reference to a Service in the namespace of an
+// ApplicationConfiguration that serves a hook via HTTPS. ExternalName
+// Services are not supported.
+type HookServiceReference struct {
+	// Name of the Service.
+	Name string `json:"name"`
+
+	// Port of the Service. Defaults to 443.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// Path to which requests are sent. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// A SpecEncryption specifies how sensitive parameter values are encrypted.
+// Values are encrypted before the ApplicationConfiguration is stored, and may
+// only be decrypted by the OAM runtime, which holds the private key.
+type SpecEncryption struct {
+	// AgeKeySecretRef references a key of a Secret in the namespace of the
+	// ApplicationConfiguration that contains the age public key, e.g.
+	// age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p, for
+	// which sensitive parameter values are encrypted.
+	AgeKeySecretRef SecretKeySelector `json:"ageKeySecretRef"`
+}
+
+// A SpecSource is a source of the components of an ApplicationConfiguration.
+type SpecSource struct {
+	// ConfigMapRef references a key of a ConfigMap in the namespace of the
+	// ApplicationConfiguration that contains the JSON or YAML encoded list of
+	// its components. The ConfigMap must be labelled
+	// oam.dev/app-config-source=true.
+	// +optional
+	ConfigMapRef ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// ArgoApplicationSetRef references an Argo CD ApplicationSet in the
+	// namespace of the ApplicationConfiguration. A component is read from
+	// each application its generators produce. It takes precedence over
+	// ConfigMapRef.
+	// +optional
+	ArgoApplicationSetRef *ArgoApplicationSetReference `json:"argoApplicationSetRef,omitempty"`
+}
+
+// An ArgoApplicationSetReference references an Argo CD ApplicationSet.
+type ArgoApplicationSetReference struct {
+	// Name of the ApplicationSet.
+	Name string `json:"name"`
+}
+
+// A ConfigMapKeySelector is a reference to a ConfigMap key.
+type ConfigMapKeySelector struct {
+	// The name of the ConfigMap.
+	Name string `json:"name"`
+
+	// The key to select.
+	Key string `json:"key"`
+}
+
+// A TargetCluster is a remote cluster to which workloads and traits are
+// applied.
+type TargetCluster struct {
+	// KubeconfigSecretRef references a key of a Secret in the namespace of
+	// the ApplicationConfiguration that contains a kubeconfig for the target
+	// cluster. The Secret must be labelled oam.dev/kubeconfig=true.
+	KubeconfigSecretRef SecretKeySelector `json:"kubeconfigSecretRef"`
+}
+
+// An AdoptionPolicy determines what happens when an object an
+// ApplicationConfiguration would create already exists.
+type AdoptionPolicy string
+
+// Adoption policies.
+const (
+	// AdoptionPolicyOverwrite overwrites existing objects with the rendered
+	// workloads and traits.
+	AdoptionPolicyOverwrite AdoptionPolicy = "overwrite"
+
+	// AdoptionPolicyAdopt annotates existing objects as owned by the
+	// ApplicationConfiguration without changing them otherwise.
+	AdoptionPolicyAdopt AdoptionPolicy = "adopt"
+
+	// AdoptionPolicyReject refuses to apply workloads and traits that already
+	// exist.
+	AdoptionPolicyReject AdoptionPolicy = "reject"
+)
+
+// A DriftPolicy determines what happens when a workload no longer matches
+// the spec it was last applied with.
+type DriftPolicy string
+
+// Drift policies.
+const (
+	// DriftPolicyInform sets the Drifted condition of the
+	// ApplicationConfiguration.
+	DriftPolicyInform DriftPolicy = "inform"
+
+	// DriftPolicyRemediate re-applies the last applied spec of the drifted
+	// workloads.
+	DriftPolicyRemediate DriftPolicy = "remediate"
+
+	// DriftPolicyAlert emits a warning event, and sets the Drifted condition
+	// of the ApplicationConfiguration.
+	DriftPolicyAlert DriftPolicy = "alert"
+)
+
+// A GroupRolloutStrategy determines how the components of a group are rolled
+// out.
+type GroupRolloutStrategy string
+
+// Group rollout strategies.
+const (
+	// GroupRolloutAllAtOnce applies all components of a group together.
+	GroupRolloutAllAtOnce GroupRolloutStrategy = "AllAtOnce"
+
+	// GroupRolloutOneByOne applies the components of a group in order, each
+	// once the components before it are healthy.
+	GroupRolloutOneByOne GroupRolloutStrategy = "OneByOne"
+)
+
+// A ComponentGroup is a set of components that are rolled out together.
+type ComponentGroup struct {
+	// Name of the group.
+	Name string `json:"name"`
+
+	// Components in the group, by component name. A component may be in at
+	// most one group.
+	Components []string `json:"components"`
+
+	// RolloutStrategy of the components in the group. Defaults to AllAtOnce.
+	// +kubebuilder:validation:Enum=AllAtOnce;OneByOne
+	// +optional
+	RolloutStrategy GroupRolloutStrategy `json:"rolloutStrategy,omitempty"`
+}
+
+// A ReconcilePolicyMode determines when the workloads and traits of an
+// ApplicationConfiguration are applied.
+type ReconcilePolicyMode string
+
+// Reconcile policy modes.
+const (
+	// ReconcilePolicyOnChange applies workloads and traits only when their
+	// rendered form changes, or when a previously applied workload or trait
+	// no longer exists.
+	ReconcilePolicyOnChange ReconcilePolicyMode = "onChange"
+
+	// ReconcilePolicyAlways applies workloads and traits every resync period.
+	ReconcilePolicyAlways ReconcilePolicyMode = "always"
+)
+
+// A ReconcilePolicy determines when the workloads and traits of an
+// ApplicationConfiguration are applied.
+type ReconcilePolicy struct {
+	// Mode of this policy.
+	// +kubebuilder:validation:Enum=onChange;always
+	Mode ReconcilePolicyMode `json:"mode"`
+
+	// ResyncPeriod is the period after which the ApplicationConfiguration is
+	// reconciled again, e.g. 5m. Defaults to 1m.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
 }
 
 // A TraitStatus represents the state of a trait.
@@ -324,8 +1229,15 @@ type TraitStatus string
 
 // A WorkloadTrait represents a trait associated with a workload.
 type WorkloadTrait struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
 	// Reference to a trait created by an ApplicationConfiguration.
 	Reference runtimev1alpha1.TypedReference `json:"traitRef"`
+
+	// UID of the trait, as of when it was last applied. A trait is only
+	// garbage collected if it still has this UID.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
 }
 
 // A WorkloadScope represents a trait associated with a workload.
@@ -336,12 +1248,20 @@ type WorkloadScope struct {
 
 // A WorkloadStatus represents the status of a workload.
 type WorkloadStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
 	// ComponentName that produced this workload.
 	ComponentName string `json:"componentName,omitempty"`
 
 	//ComponentRevisionName of current component
 	ComponentRevisionName string `json:"componentRevisionName,omitempty"`
 
+	// ComponentUID is the stable identity of the component that produced
+	// this workload. It is generated when the workload is first applied, and
+	// persists across updates.
+	// +optional
+	ComponentUID string `json:"componentUID,omitempty"`
+
 	// Reference to a workload created by an ApplicationConfiguration.
 	Reference runtimev1alpha1.TypedReference `json:"workloadRef,omitempty"`
 
@@ -350,21 +1270,276 @@ type WorkloadStatus struct {
 
 	// Scopes associated with this workload.
 	Scopes []WorkloadScope `json:"scopes,omitempty"`
+
+	// Health of this workload, as determined by the health probe of its
+	// component. Omitted if the component has no health probe.
+	// +optional
+	Health *WorkloadHealth `json:"health,omitempty"`
+
+	// LastTransitionTime is the last time the health status of this workload
+	// changed, e.g. from healthy to unhealthy. Omitted if the component has
+	// no readiness probe.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// LastAppliedTime is the last time this workload was successfully
+	// applied. It is not updated when applying is skipped because the
+	// rendered components are unchanged.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// LastAppliedSpec is the workload that was last sent to the API server,
+	// whether or not it was applied successfully. Workloads larger than the
+	// oam.dev/last-applied-spec-limit of the ApplicationConfiguration, which
+	// defaults to 100Ki, are truncated and annotated with
+	// oam.dev/spec-truncated.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	LastAppliedSpec *runtime.RawExtension `json:"lastAppliedSpec,omitempty"`
+
+	// Rollout status of this workload, as reported by the live workload.
+	// Omitted if the workload has no replicas.
+	// +optional
+	Rollout *WorkloadRollout `json:"rollout,omitempty"`
+
+	// TrafficSplit of this workload between its stable and canary
+	// Deployments. Omitted if the component has no traffic weight.
+	// +optional
+	TrafficSplit *WorkloadTrafficSplit `json:"trafficSplit,omitempty"`
+}
+
+// A WorkloadTrafficSplit records how the replicas of a Deployment workload
+// are split between it, as the stable Deployment, and its canary Deployment.
+type WorkloadTrafficSplit struct {
+	// CanaryDeploymentName is the name of the canary Deployment.
+	CanaryDeploymentName string `json:"canaryDeploymentName"`
+
+	// StableReplicas is the number of replicas of the stable Deployment.
+	StableReplicas int32 `json:"stableReplicas"`
+
+	// CanaryReplicas is the number of replicas of the canary Deployment.
+	CanaryReplicas int32 `json:"canaryReplicas"`
+}
+
+// A RolloutPhase is the phase of a workload's rollout.
+type RolloutPhase string
+
+// Workload rollout phases.
+const (
+	// RolloutPhaseProgressing indicates that some of a workload's replicas
+	// are not yet updated or ready.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+
+	// RolloutPhaseComplete indicates that all of a workload's replicas are
+	// updated and ready.
+	RolloutPhaseComplete RolloutPhase = "Complete"
+
+	// RolloutPhaseDegraded indicates that a workload reports its rollout
+	// failed, e.g. an Argo Rollout whose phase is Degraded.
+	RolloutPhaseDegraded RolloutPhase = "Degraded"
+)
+
+// A WorkloadRollout represents the progress of rolling out a workload.
+type WorkloadRollout struct {
+	// ReadyReplicas of the workload (status.readyReplicas).
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpdatedReplicas of the workload (status.updatedReplicas).
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// DesiredReplicas of the workload (spec.replicas).
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Phase of the rollout.
+	Phase RolloutPhase `json:"phase"`
+}
+
+// A RollbackPolicy configures the automatic rollback of the components of an
+// ApplicationConfiguration.
+type RollbackPolicy struct {
+	// Enabled causes each unhealthy workload to be re-rendered from, and
+	// applied using, the last revision of its component under which it was
+	// healthy when the aggregate health of the ApplicationConfiguration
+	// transitions from Healthy to Unhealthy. A rollback remains in effect
+	// until the component is revised again.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// CooldownPeriod is the minimum time between rollbacks. Defaults to 10m.
+	// +optional
+	CooldownPeriod *metav1.Duration `json:"cooldownPeriod,omitempty"`
+}
+
+// A ComponentRollback is a rollback of a component to an earlier revision.
+type ComponentRollback struct {
+	// ComponentName is the name of the component that was rolled back.
+	ComponentName string `json:"componentName"`
+
+	// FromRevision is the revision of the component that was rolled back.
+	// The rollback remains in effect while it is the component's current
+	// revision.
+	FromRevision string `json:"fromRevision"`
+
+	// ToRevision is the revision of the component that is rendered instead.
+	ToRevision string `json:"toRevision"`
+}
+
+// A HealthStatus represents the health of a workload.
+type HealthStatus string
+
+// Workload health statuses.
+const (
+	HealthStatusHealthy   HealthStatus = "Healthy"
+	HealthStatusUnhealthy HealthStatus = "Unhealthy"
+
+	// HealthStatusProgressing is the aggregate health of an
+	// ApplicationConfiguration with workloads that are still being rolled
+	// out.
+	HealthStatusProgressing HealthStatus = "Progressing"
+)
+
+// A WorkloadHealth represents the result of probing a workload.
+type WorkloadHealth struct {
+	// Status of the workload.
+	Status HealthStatus `json:"status"`
+
+	// Message explaining why the workload is unhealthy.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastProbeTime is the last time the workload was probed.
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+}
+
+// A NamespaceStatus represents the state of the workloads an
+// ApplicationConfiguration applied to a namespace selected by its
+// NamespaceSelector.
+type NamespaceStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Workloads applied to this namespace.
+	Workloads []WorkloadStatus `json:"workloads,omitempty"`
 }
 
+// An ApplicationConfigurationState is the state of an
+// ApplicationConfiguration's reconciliation.
+type ApplicationConfigurationState string
+
+// ApplicationConfiguration states.
+const (
+	// StateRendering indicates that components are being rendered.
+	StateRendering ApplicationConfigurationState = "Rendering"
+
+	// StateApplying indicates that rendered workloads and traits are being
+	// applied, or that component groups are still being rolled out.
+	StateApplying ApplicationConfigurationState = "Applying"
+
+	// StateReady indicates that all workloads and traits were applied.
+	StateReady ApplicationConfigurationState = "Ready"
+
+	// StateDegraded indicates that components could not be rendered, or
+	// that some workloads or traits could not be applied.
+	StateDegraded ApplicationConfigurationState = "Degraded"
+
+	// StatePaused indicates that reconciliation is paused.
+	StatePaused ApplicationConfigurationState = "Paused"
+)
+
 // An ApplicationConfigurationStatus represents the observed state of a
 // ApplicationConfiguration.
 type ApplicationConfigurationStatus struct {
 	runtimev1alpha1.ConditionedStatus `json:",inline"`
 
+	// State of the ApplicationConfiguration's reconciliation.
+	// +optional
+	State ApplicationConfigurationState `json:"state,omitempty"`
+
 	// Workloads created by this ApplicationConfiguration.
 	Workloads []WorkloadStatus `json:"workloads,omitempty"`
+
+	// NamespaceStatuses of the namespaces selected by the NamespaceSelector of
+	// this ApplicationConfiguration, keyed by namespace name.
+	NamespaceStatuses map[string]NamespaceStatus `json:"namespaceStatuses,omitempty"`
+
+	// LastAppliedHash is a hash of the workloads and traits that were last
+	// applied under the onChange reconcile policy.
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// Topology of the workloads that were last successfully applied, and
+	// their traits and scopes.
+	// +optional
+	Topology *Topology `json:"topology,omitempty"`
+
+	// Health is the aggregate health of the workloads whose components have
+	// a health probe. It is Unhealthy if any of them are unhealthy or any
+	// workload's rollout is degraded, or otherwise Progressing if any
+	// workload is still being rolled out.
+	// +optional
+	Health HealthStatus `json:"health,omitempty"`
+
+	// LastHealthyRevisions are the last revisions, keyed by component name,
+	// of the components whose workloads were healthy.
+	// +optional
+	LastHealthyRevisions map[string]string `json:"lastHealthyRevisions,omitempty"`
+
+	// PreviousHealthyRevisions are the revisions, keyed by component name,
+	// under which the components' workloads were healthy before their last
+	// healthy revisions.
+	// +optional
+	PreviousHealthyRevisions map[string]string `json:"previousHealthyRevisions,omitempty"`
+
+	// Rollbacks of components to earlier revisions that are in effect.
+	// +optional
+	Rollbacks []ComponentRollback `json:"rollbacks,omitempty"`
+
+	// LastRollbackTime is the last time a component was rolled back.
+	// +optional
+	LastRollbackTime *metav1.Time `json:"lastRollbackTime,omitempty"`
+}
+
+// A TopologyEdgeType is the relationship an edge of a Topology represents.
+type TopologyEdgeType string
+
+// Topology edge types.
+const (
+	// TopologyEdgeTrait connects a workload to one of its traits.
+	TopologyEdgeTrait TopologyEdgeType = "trait"
+
+	// TopologyEdgeScope connects a workload to one of its scopes.
+	TopologyEdgeScope TopologyEdgeType = "scope"
+)
+
+// A Topology is a directed graph of applied workloads and the traits and
+// scopes they are related to.
+type Topology struct {
+	// Nodes of the graph, keyed by node ID. The ID of a node is the API
+	// version, kind, and name of the object it represents, separated by
+	// slashes.
+	// +optional
+	Nodes map[string]runtimev1alpha1.TypedReference `json:"nodes,omitempty"`
+
+	// Edges of the graph as an adjacency list, keyed by the ID of the
+	// workload node each edge starts from.
+	// +optional
+	Edges map[string][]TopologyEdge `json:"edges,omitempty"`
+}
+
+// A TopologyEdge connects a workload to a trait or scope.
+type TopologyEdge struct {
+	// To is the ID of the node this edge ends at.
+	To string `json:"to"`
+
+	// Type of relationship this edge represents.
+	// +kubebuilder:validation:Enum=trait;scope
+	Type TopologyEdgeType `json:"type"`
 }
 
 // +kubebuilder:object:root=true
 
 // An ApplicationConfiguration represents an OAM application.
 // +kubebuilder:resource:shortName=appconfig,categories={crossplane,oam}
+// +kubebuilder:printcolumn:JSONPath=".status.state",name=STATE,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=AGE,type=date
 // +kubebuilder:subresource:status
 type ApplicationConfiguration struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -383,6 +1558,28 @@ type ApplicationConfigurationList struct {
 	Items           []ApplicationConfiguration `json:"items"`
 }
 
+// A ComponentOutput exposes a field of a component's workload to other
+// components of the same ApplicationConfiguration.
+type ComponentOutput struct {
+	// Name of this output. Must be unique within an ApplicationConfiguration.
+	Name string `json:"name"`
+
+	// FieldPath of the workload field whose value is exposed by this output,
+	// e.g. status.connectionString.
+	FieldPath string `json:"fieldPath"`
+}
+
+// A ComponentInput injects the value of an output of another component into a
+// parameter of a component.
+type ComponentInput struct {
+	// ParameterKey is the name of the component parameter to which the output
+	// value is supplied.
+	ParameterKey string `json:"parameterKey"`
+
+	// From is the name of the output whose value is supplied.
+	From string `json:"from"`
+}
+
 // DataOutput specifies a data output source from an object.
 type DataOutput struct {
 	// Name is the unique name of a DataOutput in an ApplicationConfiguration.
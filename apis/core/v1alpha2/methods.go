@@ -19,6 +19,8 @@ limitations under the License.
 package v1alpha2
 
 import (
+	"time"
+
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
@@ -48,6 +50,26 @@ func (tr *ManualScalerTrait) SetWorkloadReference(r runtimev1alpha1.TypedReferen
 	tr.Spec.WorkloadReference = r
 }
 
+// GetCondition of this WorkloadDefinition.
+func (wd *WorkloadDefinition) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return wd.Status.GetCondition(ct)
+}
+
+// SetConditions of this WorkloadDefinition.
+func (wd *WorkloadDefinition) SetConditions(c ...runtimev1alpha1.Condition) {
+	wd.Status.SetConditions(c...)
+}
+
+// GetCondition of this TraitDefinition.
+func (td *TraitDefinition) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return td.Status.GetCondition(ct)
+}
+
+// SetConditions of this TraitDefinition.
+func (td *TraitDefinition) SetConditions(c ...runtimev1alpha1.Condition) {
+	td.Status.SetConditions(c...)
+}
+
 // GetCondition of this ApplicationConfiguration.
 func (ac *ApplicationConfiguration) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
 	return ac.Status.GetCondition(ct)
@@ -97,3 +119,12 @@ func (hs *HealthScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference
 func (hs *HealthScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
 	hs.Spec.WorkloadReferences = append(hs.Spec.WorkloadReferences, r)
 }
+
+// TimeSinceLastApply returns how long ago this workload was last successfully
+// applied, or zero if it has never been applied.
+func (ws *WorkloadStatus) TimeSinceLastApply() time.Duration {
+	if ws.LastAppliedTime == nil {
+		return 0
+	}
+	return time.Since(ws.LastAppliedTime.Time)
+}
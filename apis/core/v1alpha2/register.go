@@ -101,6 +101,38 @@ var (
 	HealthScopeGroupVersionKind = SchemeGroupVersion.WithKind(HealthScopeKind)
 )
 
+// TraitPolicy type metadata.
+var (
+	TraitPolicyKind             = reflect.TypeOf(TraitPolicy{}).Name()
+	TraitPolicyGroupKind        = schema.GroupKind{Group: Group, Kind: TraitPolicyKind}.String()
+	TraitPolicyKindAPIVersion   = TraitPolicyKind + "." + SchemeGroupVersion.String()
+	TraitPolicyGroupVersionKind = SchemeGroupVersion.WithKind(TraitPolicyKind)
+)
+
+// ApplicationConfigurationEvent type metadata.
+var (
+	ApplicationConfigurationEventKind             = reflect.TypeOf(ApplicationConfigurationEvent{}).Name()
+	ApplicationConfigurationEventGroupKind        = schema.GroupKind{Group: Group, Kind: ApplicationConfigurationEventKind}.String()
+	ApplicationConfigurationEventKindAPIVersion   = ApplicationConfigurationEventKind + "." + SchemeGroupVersion.String()
+	ApplicationConfigurationEventGroupVersionKind = SchemeGroupVersion.WithKind(ApplicationConfigurationEventKind)
+)
+
+// ComponentEvent type metadata.
+var (
+	ComponentEventKind             = reflect.TypeOf(ComponentEvent{}).Name()
+	ComponentEventGroupKind        = schema.GroupKind{Group: Group, Kind: ComponentEventKind}.String()
+	ComponentEventKindAPIVersion   = ComponentEventKind + "." + SchemeGroupVersion.String()
+	ComponentEventGroupVersionKind = SchemeGroupVersion.WithKind(ComponentEventKind)
+)
+
+// NamespaceParameterDefaults type metadata.
+var (
+	NamespaceParameterDefaultsKind             = reflect.TypeOf(NamespaceParameterDefaults{}).Name()
+	NamespaceParameterDefaultsGroupKind        = schema.GroupKind{Group: Group, Kind: NamespaceParameterDefaultsKind}.String()
+	NamespaceParameterDefaultsKindAPIVersion   = NamespaceParameterDefaultsKind + "." + SchemeGroupVersion.String()
+	NamespaceParameterDefaultsGroupVersionKind = SchemeGroupVersion.WithKind(NamespaceParameterDefaultsKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&WorkloadDefinition{}, &WorkloadDefinitionList{})
 	SchemeBuilder.Register(&TraitDefinition{}, &TraitDefinitionList{})
@@ -110,4 +142,8 @@ func init() {
 	SchemeBuilder.Register(&ContainerizedWorkload{}, &ContainerizedWorkloadList{})
 	SchemeBuilder.Register(&ManualScalerTrait{}, &ManualScalerTraitList{})
 	SchemeBuilder.Register(&HealthScope{}, &HealthScopeList{})
+	SchemeBuilder.Register(&TraitPolicy{}, &TraitPolicyList{})
+	SchemeBuilder.Register(&ApplicationConfigurationEvent{}, &ApplicationConfigurationEventList{})
+	SchemeBuilder.Register(&ComponentEvent{}, &ComponentEventList{})
+	SchemeBuilder.Register(&NamespaceParameterDefaults{}, &NamespaceParameterDefaultsList{})
 }
@@ -22,7 +22,10 @@ package v1alpha2
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -69,6 +72,114 @@ func (in *ApplicationConfigurationComponent) DeepCopyInto(out *ApplicationConfig
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]ComponentOutput, len(*in))
+		copy(*out, *in)
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]ComponentInput, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ComponentEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ComponentPodDisruptionBudget)
+		**out = **in
+	}
+	if in.DependsOnKinds != nil {
+		in, out := &in.DependsOnKinds, &out.DependsOnKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]PatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Emits != nil {
+		in, out := &in.Emits, &out.Emits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscribesTo != nil {
+		in, out := &in.SubscribesTo, &out.SubscribesTo
+		*out = make([]ComponentEventSubscription, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrafficWeight != nil {
+		in, out := &in.TrafficWeight, &out.TrafficWeight
+		*out = new(ComponentTrafficWeight)
+		**out = **in
+	}
+	if in.ServiceBindingRef != nil {
+		in, out := &in.ServiceBindingRef, &out.ServiceBindingRef
+		*out = new(ServiceBindingReference)
+		**out = **in
+	}
+	if in.ConfigMapVolumes != nil {
+		in, out := &in.ConfigMapVolumes, &out.ConfigMapVolumes
+		*out = make([]ComponentConfigMapVolume, len(*in))
+		copy(*out, *in)
+	}
 	if in.ParameterValues != nil {
 		in, out := &in.ParameterValues, &out.ParameterValues
 		*out = make([]ComponentParameterValue, len(*in))
@@ -86,6 +197,26 @@ func (in *ApplicationConfigurationComponent) DeepCopyInto(out *ApplicationConfig
 		*out = make([]ComponentScope, len(*in))
 		copy(*out, *in)
 	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(ComponentHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusPollInterval != nil {
+		in, out := &in.StatusPollInterval, &out.StatusPollInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ApplyAs != nil {
+		in, out := &in.ApplyAs, &out.ApplyAs
+		*out = new(ComponentApplyAs)
+		**out = **in
+	}
+	if in.ApplyTimeout != nil {
+		in, out := &in.ApplyTimeout, &out.ApplyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationComponent.
@@ -98,6 +229,80 @@ func (in *ApplicationConfigurationComponent) DeepCopy() *ApplicationConfiguratio
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationConfigurationEvent) DeepCopyInto(out *ApplicationConfigurationEvent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationEvent.
+func (in *ApplicationConfigurationEvent) DeepCopy() *ApplicationConfigurationEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationConfigurationEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationConfigurationEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationConfigurationEventList) DeepCopyInto(out *ApplicationConfigurationEventList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApplicationConfigurationEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationEventList.
+func (in *ApplicationConfigurationEventList) DeepCopy() *ApplicationConfigurationEventList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationConfigurationEventList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationConfigurationEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationConfigurationEventSpec) DeepCopyInto(out *ApplicationConfigurationEventSpec) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationEventSpec.
+func (in *ApplicationConfigurationEventSpec) DeepCopy() *ApplicationConfigurationEventSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationConfigurationEventSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationConfigurationList) DeepCopyInto(out *ApplicationConfigurationList) {
 	*out = *in
@@ -140,6 +345,99 @@ func (in *ApplicationConfigurationSpec) DeepCopyInto(out *ApplicationConfigurati
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SpecSource != nil {
+		in, out := &in.SpecSource, &out.SpecSource
+		*out = new(SpecSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ReconcilePolicy != nil {
+		in, out := &in.ReconcilePolicy, &out.ReconcilePolicy
+		*out = new(ReconcilePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeTimeoutSeconds != nil {
+		in, out := &in.ProbeTimeoutSeconds, &out.ProbeTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentApply != nil {
+		in, out := &in.MaxConcurrentApply, &out.MaxConcurrentApply
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.VaultCacheTTL != nil {
+		in, out := &in.VaultCacheTTL, &out.VaultCacheTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(SpecEncryption)
+		**out = **in
+	}
+	if in.ComponentGroups != nil {
+		in, out := &in.ComponentGroups, &out.ComponentGroups
+		*out = make([]ComponentGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStepTimeout != nil {
+		in, out := &in.RolloutStepTimeout, &out.RolloutStepTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(TargetCluster)
+		**out = **in
+	}
+	if in.GlobalVariables != nil {
+		in, out := &in.GlobalVariables, &out.GlobalVariables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GlobalTraits != nil {
+		in, out := &in.GlobalTraits, &out.GlobalTraits
+		*out = make([]ComponentTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreApplyHook != nil {
+		in, out := &in.PreApplyHook, &out.PreApplyHook
+		*out = new(PreApplyHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationSpec.
@@ -163,6 +461,41 @@ func (in *ApplicationConfigurationStatus) DeepCopyInto(out *ApplicationConfigura
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NamespaceStatuses != nil {
+		in, out := &in.NamespaceStatuses, &out.NamespaceStatuses
+		*out = make(map[string]NamespaceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(Topology)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastHealthyRevisions != nil {
+		in, out := &in.LastHealthyRevisions, &out.LastHealthyRevisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PreviousHealthyRevisions != nil {
+		in, out := &in.PreviousHealthyRevisions, &out.PreviousHealthyRevisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Rollbacks != nil {
+		in, out := &in.Rollbacks, &out.Rollbacks
+		*out = make([]ComponentRollback, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastRollbackTime != nil {
+		in, out := &in.LastRollbackTime, &out.LastRollbackTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationStatus.
@@ -175,6 +508,21 @@ func (in *ApplicationConfigurationStatus) DeepCopy() *ApplicationConfigurationSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoApplicationSetReference) DeepCopyInto(out *ArgoApplicationSetReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoApplicationSetReference.
+func (in *ArgoApplicationSetReference) DeepCopy() *ArgoApplicationSetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoApplicationSetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUResources) DeepCopyInto(out *CPUResources) {
 	*out = *in
@@ -241,324 +589,615 @@ func (in *Component) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentList) DeepCopyInto(out *ComponentList) {
+func (in *ComponentApplyAs) DeepCopyInto(out *ComponentApplyAs) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Component, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.ServiceAccountRef = in.ServiceAccountRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentList.
-func (in *ComponentList) DeepCopy() *ComponentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentApplyAs.
+func (in *ComponentApplyAs) DeepCopy() *ComponentApplyAs {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentList)
+	out := new(ComponentApplyAs)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComponentList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentParameter) DeepCopyInto(out *ComponentParameter) {
+func (in *ComponentConfigMapVolume) DeepCopyInto(out *ComponentConfigMapVolume) {
 	*out = *in
-	if in.FieldPaths != nil {
-		in, out := &in.FieldPaths, &out.FieldPaths
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Required != nil {
-		in, out := &in.Required, &out.Required
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameter.
-func (in *ComponentParameter) DeepCopy() *ComponentParameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentConfigMapVolume.
+func (in *ComponentConfigMapVolume) DeepCopy() *ComponentConfigMapVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentParameter)
+	out := new(ComponentConfigMapVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentParameterValue) DeepCopyInto(out *ComponentParameterValue) {
+func (in *ComponentEnvVar) DeepCopyInto(out *ComponentEnvVar) {
 	*out = *in
-	out.Value = in.Value
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameterValue.
-func (in *ComponentParameterValue) DeepCopy() *ComponentParameterValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEnvVar.
+func (in *ComponentEnvVar) DeepCopy() *ComponentEnvVar {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentParameterValue)
+	out := new(ComponentEnvVar)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentScope) DeepCopyInto(out *ComponentScope) {
+func (in *ComponentEvent) DeepCopyInto(out *ComponentEvent) {
 	*out = *in
-	out.ScopeReference = in.ScopeReference
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentScope.
-func (in *ComponentScope) DeepCopy() *ComponentScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEvent.
+func (in *ComponentEvent) DeepCopy() *ComponentEvent {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentScope)
+	out := new(ComponentEvent)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
-	*out = *in
-	in.Workload.DeepCopyInto(&out.Workload)
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make([]ComponentParameter, len(*in))
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComponentEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentEventList) DeepCopyInto(out *ComponentEventList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComponentEvent, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
-func (in *ComponentSpec) DeepCopy() *ComponentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEventList.
+func (in *ComponentEventList) DeepCopy() *ComponentEventList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentSpec)
+	out := new(ComponentEventList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComponentEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+func (in *ComponentEventSpec) DeepCopyInto(out *ComponentEventSpec) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	if in.LatestRevision != nil {
-		in, out := &in.LatestRevision, &out.LatestRevision
-		*out = new(Revision)
-		**out = **in
-	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
-func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEventSpec.
+func (in *ComponentEventSpec) DeepCopy() *ComponentEventSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentStatus)
+	out := new(ComponentEventSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentTrait) DeepCopyInto(out *ComponentTrait) {
+func (in *ComponentEventSubscription) DeepCopyInto(out *ComponentEventSubscription) {
 	*out = *in
-	in.Trait.DeepCopyInto(&out.Trait)
-	if in.DataOutputs != nil {
-		in, out := &in.DataOutputs, &out.DataOutputs
-		*out = make([]DataOutput, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentEventSubscription.
+func (in *ComponentEventSubscription) DeepCopy() *ComponentEventSubscription {
+	if in == nil {
+		return nil
 	}
-	if in.DataInputs != nil {
-		in, out := &in.DataInputs, &out.DataInputs
-		*out = make([]DataInput, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(ComponentEventSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentGroup) DeepCopyInto(out *ComponentGroup) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTrait.
-func (in *ComponentTrait) DeepCopy() *ComponentTrait {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentGroup.
+func (in *ComponentGroup) DeepCopy() *ComponentGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentTrait)
+	out := new(ComponentGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConditionRequirement) DeepCopyInto(out *ConditionRequirement) {
+func (in *ComponentHTTPGetProbe) DeepCopyInto(out *ComponentHTTPGetProbe) {
 	*out = *in
+	if in.ExpectedStatusCode != nil {
+		in, out := &in.ExpectedStatusCode, &out.ExpectedStatusCode
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionRequirement.
-func (in *ConditionRequirement) DeepCopy() *ConditionRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHTTPGetProbe.
+func (in *ComponentHTTPGetProbe) DeepCopy() *ComponentHTTPGetProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(ConditionRequirement)
+	out := new(ComponentHTTPGetProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Container) DeepCopyInto(out *Container) {
+func (in *ComponentHealthProbe) DeepCopyInto(out *ComponentHealthProbe) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ContainerResources)
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(ComponentHTTPGetProbe)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Arguments != nil {
-		in, out := &in.Arguments, &out.Arguments
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Environment != nil {
-		in, out := &in.Environment, &out.Environment
-		*out = make([]ContainerEnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHealthProbe.
+func (in *ComponentHealthProbe) DeepCopy() *ComponentHealthProbe {
+	if in == nil {
+		return nil
 	}
-	if in.ConfigFiles != nil {
-		in, out := &in.ConfigFiles, &out.ConfigFiles
-		*out = make([]ContainerConfigFile, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(ComponentHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentInput) DeepCopyInto(out *ComponentInput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentInput.
+func (in *ComponentInput) DeepCopy() *ComponentInput {
+	if in == nil {
+		return nil
 	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]ContainerPort, len(*in))
+	out := new(ComponentInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentList) DeepCopyInto(out *ComponentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Component, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LivenessProbe != nil {
-		in, out := &in.LivenessProbe, &out.LivenessProbe
-		*out = new(ContainerHealthProbe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
-		*out = new(ContainerHealthProbe)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentList.
+func (in *ComponentList) DeepCopy() *ComponentList {
+	if in == nil {
+		return nil
 	}
-	if in.ImagePullSecret != nil {
-		in, out := &in.ImagePullSecret, &out.ImagePullSecret
-		*out = new(string)
-		**out = **in
+	out := new(ComponentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComponentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Container.
-func (in *Container) DeepCopy() *Container {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentOutput) DeepCopyInto(out *ComponentOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOutput.
+func (in *ComponentOutput) DeepCopy() *ComponentOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(Container)
+	out := new(ComponentOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerConfigFile) DeepCopyInto(out *ContainerConfigFile) {
+func (in *ComponentParameter) DeepCopyInto(out *ComponentParameter) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
 		*out = new(string)
 		**out = **in
 	}
-	if in.FromSecret != nil {
-		in, out := &in.FromSecret, &out.FromSecret
-		*out = new(SecretKeySelector)
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfigFile.
-func (in *ContainerConfigFile) DeepCopy() *ContainerConfigFile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameter.
+func (in *ComponentParameter) DeepCopy() *ComponentParameter {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerConfigFile)
+	out := new(ComponentParameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerEnvVar) DeepCopyInto(out *ContainerEnvVar) {
+func (in *ComponentParameterValue) DeepCopyInto(out *ComponentParameterValue) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
-		*out = new(string)
-		**out = **in
-	}
-	if in.FromSecret != nil {
-		in, out := &in.FromSecret, &out.FromSecret
-		*out = new(SecretKeySelector)
-		**out = **in
-	}
+	out.Value = in.Value
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvVar.
-func (in *ContainerEnvVar) DeepCopy() *ContainerEnvVar {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameterValue.
+func (in *ComponentParameterValue) DeepCopy() *ComponentParameterValue {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerEnvVar)
+	out := new(ComponentParameterValue)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerHealthProbe) DeepCopyInto(out *ContainerHealthProbe) {
+func (in *ComponentPodDisruptionBudget) DeepCopyInto(out *ComponentPodDisruptionBudget) {
 	*out = *in
-	if in.Exec != nil {
-		in, out := &in.Exec, &out.Exec
-		*out = new(ExecProbe)
-		(*in).DeepCopyInto(*out)
+	out.MinAvailable = in.MinAvailable
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPodDisruptionBudget.
+func (in *ComponentPodDisruptionBudget) DeepCopy() *ComponentPodDisruptionBudget {
+	if in == nil {
+		return nil
 	}
-	if in.HTTPGet != nil {
-		in, out := &in.HTTPGet, &out.HTTPGet
-		*out = new(HTTPGetProbe)
-		(*in).DeepCopyInto(*out)
+	out := new(ComponentPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentRollback) DeepCopyInto(out *ComponentRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentRollback.
+func (in *ComponentRollback) DeepCopy() *ComponentRollback {
+	if in == nil {
+		return nil
 	}
-	if in.TCPSocket != nil {
-		in, out := &in.TCPSocket, &out.TCPSocket
-		*out = new(TCPSocketProbe)
-		**out = **in
+	out := new(ComponentRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentScope) DeepCopyInto(out *ComponentScope) {
+	*out = *in
+	out.ScopeReference = in.ScopeReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentScope.
+func (in *ComponentScope) DeepCopy() *ComponentScope {
+	if in == nil {
+		return nil
 	}
-	if in.InitialDelaySeconds != nil {
-		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+	out := new(ComponentScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
+	*out = *in
+	in.Workload.DeepCopyInto(&out.Workload)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ComponentParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
+func (in *ComponentSpec) DeepCopy() *ComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.LatestRevision != nil {
+		in, out := &in.LatestRevision, &out.LatestRevision
+		*out = new(Revision)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTrafficWeight) DeepCopyInto(out *ComponentTrafficWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTrafficWeight.
+func (in *ComponentTrafficWeight) DeepCopy() *ComponentTrafficWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTrafficWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTrait) DeepCopyInto(out *ComponentTrait) {
+	*out = *in
+	in.Trait.DeepCopyInto(&out.Trait)
+	if in.DataOutputs != nil {
+		in, out := &in.DataOutputs, &out.DataOutputs
+		*out = make([]DataOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataInputs != nil {
+		in, out := &in.DataInputs, &out.DataInputs
+		*out = make([]DataInput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTrait.
+func (in *ComponentTrait) DeepCopy() *ComponentTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionRequirement) DeepCopyInto(out *ConditionRequirement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionRequirement.
+func (in *ConditionRequirement) DeepCopy() *ConditionRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Container) DeepCopyInto(out *Container) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ContainerResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = make([]ContainerEnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigFiles != nil {
+		in, out := &in.ConfigFiles, &out.ConfigFiles
+		*out = make([]ContainerConfigFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ContainerPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ContainerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ContainerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Container.
+func (in *Container) DeepCopy() *Container {
+	if in == nil {
+		return nil
+	}
+	out := new(Container)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerConfigFile) DeepCopyInto(out *ContainerConfigFile) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfigFile.
+func (in *ContainerConfigFile) DeepCopy() *ContainerConfigFile {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerConfigFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerEnvVar) DeepCopyInto(out *ContainerEnvVar) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvVar.
+func (in *ContainerEnvVar) DeepCopy() *ContainerEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerHealthProbe) DeepCopyInto(out *ContainerHealthProbe) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPGetProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCPSocket != nil {
+		in, out := &in.TCPSocket, &out.TCPSocket
+		*out = new(TCPSocketProbe)
+		**out = **in
+	}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
 		*out = new(int32)
 		**out = **in
 	}
@@ -584,91 +1223,661 @@ func (in *ContainerHealthProbe) DeepCopyInto(out *ContainerHealthProbe) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerHealthProbe.
-func (in *ContainerHealthProbe) DeepCopy() *ContainerHealthProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerHealthProbe.
+func (in *ContainerHealthProbe) DeepCopy() *ContainerHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
+	*out = *in
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(TransportProtocol)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerPort.
+func (in *ContainerPort) DeepCopy() *ContainerPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResources) DeepCopyInto(out *ContainerResources) {
+	*out = *in
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.Memory.DeepCopyInto(&out.Memory)
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPUResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VolumeResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Extended != nil {
+		in, out := &in.Extended, &out.Extended
+		*out = make([]ExtendedResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResources.
+func (in *ContainerResources) DeepCopy() *ContainerResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkload) DeepCopyInto(out *ContainerizedWorkload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkload.
+func (in *ContainerizedWorkload) DeepCopy() *ContainerizedWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadList) DeepCopyInto(out *ContainerizedWorkloadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContainerizedWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadList.
+func (in *ContainerizedWorkloadList) DeepCopy() *ContainerizedWorkloadList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadSpec) DeepCopyInto(out *ContainerizedWorkloadSpec) {
+	*out = *in
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(OperatingSystem)
+		**out = **in
+	}
+	if in.CPUArchitecture != nil {
+		in, out := &in.CPUArchitecture, &out.CPUArchitecture
+		*out = new(CPUArchitecture)
+		**out = **in
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadSpec.
+func (in *ContainerizedWorkloadSpec) DeepCopy() *ContainerizedWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadStatus) DeepCopyInto(out *ContainerizedWorkloadStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadStatus.
+func (in *ContainerizedWorkloadStatus) DeepCopy() *ContainerizedWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataInput) DeepCopyInto(out *DataInput) {
+	*out = *in
+	out.ValueFrom = in.ValueFrom
+	if in.ToFieldPaths != nil {
+		in, out := &in.ToFieldPaths, &out.ToFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInput.
+func (in *DataInput) DeepCopy() *DataInput {
+	if in == nil {
+		return nil
+	}
+	out := new(DataInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataInputValueFrom) DeepCopyInto(out *DataInputValueFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInputValueFrom.
+func (in *DataInputValueFrom) DeepCopy() *DataInputValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(DataInputValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataOutput) DeepCopyInto(out *DataOutput) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ConditionRequirement, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataOutput.
+func (in *DataOutput) DeepCopy() *DataOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(DataOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionReference) DeepCopyInto(out *DefinitionReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionReference.
+func (in *DefinitionReference) DeepCopy() *DefinitionReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskResource) DeepCopyInto(out *DiskResource) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskResource.
+func (in *DiskResource) DeepCopy() *DiskResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecProbe) DeepCopyInto(out *ExecProbe) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecProbe.
+func (in *ExecProbe) DeepCopy() *ExecProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedResource) DeepCopyInto(out *ExtendedResource) {
+	*out = *in
+	out.Required = in.Required
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResource.
+func (in *ExtendedResource) DeepCopy() *ExtendedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUResources) DeepCopyInto(out *GPUResources) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUResources.
+func (in *GPUResources) DeepCopy() *GPUResources {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGetProbe) DeepCopyInto(out *HTTPGetProbe) {
+	*out = *in
+	if in.HTTPHeaders != nil {
+		in, out := &in.HTTPHeaders, &out.HTTPHeaders
+		*out = make([]HTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetProbe.
+func (in *HTTPGetProbe) DeepCopy() *HTTPGetProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGetProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthHistoryEntry) DeepCopyInto(out *HealthHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthHistoryEntry.
+func (in *HealthHistoryEntry) DeepCopy() *HealthHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScope) DeepCopyInto(out *HealthScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScope.
+func (in *HealthScope) DeepCopy() *HealthScope {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeList) DeepCopyInto(out *HealthScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealthScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeList.
+func (in *HealthScopeList) DeepCopy() *HealthScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeSpec) DeepCopyInto(out *HealthScopeSpec) {
+	*out = *in
+	if in.ProbeTimeout != nil {
+		in, out := &in.ProbeTimeout, &out.ProbeTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProbeInterval != nil {
+		in, out := &in.ProbeInterval, &out.ProbeInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.HistoryRetentionCount != nil {
+		in, out := &in.HistoryRetentionCount, &out.HistoryRetentionCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WorkloadPrometheusEndpoints != nil {
+		in, out := &in.WorkloadPrometheusEndpoints, &out.WorkloadPrometheusEndpoints
+		*out = make([]WorkloadPrometheusEndpoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeSpec.
+func (in *HealthScopeSpec) DeepCopy() *HealthScopeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeStatus) DeepCopyInto(out *HealthScopeStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.HealthHistory != nil {
+		in, out := &in.HealthHistory, &out.HealthHistory
+		*out = make([]HealthHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeStatus.
+func (in *HealthScopeStatus) DeepCopy() *HealthScopeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookServiceReference) DeepCopyInto(out *HookServiceReference) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookServiceReference.
+func (in *HookServiceReference) DeepCopy() *HookServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(HookServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KustomizeSchematic) DeepCopyInto(out *KustomizeSchematic) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizeSchematic.
+func (in *KustomizeSchematic) DeepCopy() *KustomizeSchematic {
+	if in == nil {
+		return nil
+	}
+	out := new(KustomizeSchematic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTrait) DeepCopyInto(out *ManualScalerTrait) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTrait.
+func (in *ManualScalerTrait) DeepCopy() *ManualScalerTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualScalerTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManualScalerTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTraitList) DeepCopyInto(out *ManualScalerTraitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ManualScalerTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitList.
+func (in *ManualScalerTraitList) DeepCopy() *ManualScalerTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerHealthProbe)
+	out := new(ManualScalerTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManualScalerTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
+func (in *ManualScalerTraitSpec) DeepCopyInto(out *ManualScalerTraitSpec) {
 	*out = *in
-	if in.Protocol != nil {
-		in, out := &in.Protocol, &out.Protocol
-		*out = new(TransportProtocol)
-		**out = **in
-	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerPort.
-func (in *ContainerPort) DeepCopy() *ContainerPort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitSpec.
+func (in *ManualScalerTraitSpec) DeepCopy() *ManualScalerTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerPort)
+	out := new(ManualScalerTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerResources) DeepCopyInto(out *ContainerResources) {
+func (in *ManualScalerTraitStatus) DeepCopyInto(out *ManualScalerTraitStatus) {
 	*out = *in
-	in.CPU.DeepCopyInto(&out.CPU)
-	in.Memory.DeepCopyInto(&out.Memory)
-	if in.GPU != nil {
-		in, out := &in.GPU, &out.GPU
-		*out = new(GPUResources)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]VolumeResource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Extended != nil {
-		in, out := &in.Extended, &out.Extended
-		*out = make([]ExtendedResource, len(*in))
-		copy(*out, *in)
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitStatus.
+func (in *ManualScalerTraitStatus) DeepCopy() *ManualScalerTraitStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(ManualScalerTraitStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResources.
-func (in *ContainerResources) DeepCopy() *ContainerResources {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryResources) DeepCopyInto(out *MemoryResources) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryResources.
+func (in *MemoryResources) DeepCopy() *MemoryResources {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerResources)
+	out := new(MemoryResources)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkload) DeepCopyInto(out *ContainerizedWorkload) {
+func (in *NamespaceParameterDefaults) DeepCopyInto(out *NamespaceParameterDefaults) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkload.
-func (in *ContainerizedWorkload) DeepCopy() *ContainerizedWorkload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceParameterDefaults.
+func (in *NamespaceParameterDefaults) DeepCopy() *NamespaceParameterDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkload)
+	out := new(NamespaceParameterDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
+func (in *NamespaceParameterDefaults) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -676,31 +1885,31 @@ func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadList) DeepCopyInto(out *ContainerizedWorkloadList) {
+func (in *NamespaceParameterDefaultsList) DeepCopyInto(out *NamespaceParameterDefaultsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ContainerizedWorkload, len(*in))
+		*out = make([]NamespaceParameterDefaults, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadList.
-func (in *ContainerizedWorkloadList) DeepCopy() *ContainerizedWorkloadList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceParameterDefaultsList.
+func (in *NamespaceParameterDefaultsList) DeepCopy() *NamespaceParameterDefaultsList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadList)
+	out := new(NamespaceParameterDefaultsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
+func (in *NamespaceParameterDefaultsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -708,258 +1917,256 @@ func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadSpec) DeepCopyInto(out *ContainerizedWorkloadSpec) {
+func (in *NamespaceParameterDefaultsSpec) DeepCopyInto(out *NamespaceParameterDefaultsSpec) {
 	*out = *in
-	if in.OperatingSystem != nil {
-		in, out := &in.OperatingSystem, &out.OperatingSystem
-		*out = new(OperatingSystem)
-		**out = **in
-	}
-	if in.CPUArchitecture != nil {
-		in, out := &in.CPUArchitecture, &out.CPUArchitecture
-		*out = new(CPUArchitecture)
-		**out = **in
-	}
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make(map[string]map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			var outVal map[string]intstr.IntOrString
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]intstr.IntOrString, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadSpec.
-func (in *ContainerizedWorkloadSpec) DeepCopy() *ContainerizedWorkloadSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceParameterDefaultsSpec.
+func (in *NamespaceParameterDefaultsSpec) DeepCopy() *NamespaceParameterDefaultsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadSpec)
+	out := new(NamespaceParameterDefaultsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadStatus) DeepCopyInto(out *ContainerizedWorkloadStatus) {
+func (in *NamespaceStatus) DeepCopyInto(out *NamespaceStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]v1alpha1.TypedReference, len(*in))
-		copy(*out, *in)
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadStatus.
-func (in *ContainerizedWorkloadStatus) DeepCopy() *ContainerizedWorkloadStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStatus.
+func (in *NamespaceStatus) DeepCopy() *NamespaceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadStatus)
+	out := new(NamespaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataInput) DeepCopyInto(out *DataInput) {
+func (in *OCISchematic) DeepCopyInto(out *OCISchematic) {
 	*out = *in
-	out.ValueFrom = in.ValueFrom
-	if in.ToFieldPaths != nil {
-		in, out := &in.ToFieldPaths, &out.ToFieldPaths
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInput.
-func (in *DataInput) DeepCopy() *DataInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCISchematic.
+func (in *OCISchematic) DeepCopy() *OCISchematic {
 	if in == nil {
 		return nil
 	}
-	out := new(DataInput)
+	out := new(OCISchematic)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataInputValueFrom) DeepCopyInto(out *DataInputValueFrom) {
+func (in *PatchOperation) DeepCopyInto(out *PatchOperation) {
 	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInputValueFrom.
-func (in *DataInputValueFrom) DeepCopy() *DataInputValueFrom {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOperation.
+func (in *PatchOperation) DeepCopy() *PatchOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(DataInputValueFrom)
+	out := new(PatchOperation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataOutput) DeepCopyInto(out *DataOutput) {
+func (in *PreApplyHook) DeepCopyInto(out *PreApplyHook) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ConditionRequirement, len(*in))
+	in.Service.DeepCopyInto(&out.Service)
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataOutput.
-func (in *DataOutput) DeepCopy() *DataOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreApplyHook.
+func (in *PreApplyHook) DeepCopy() *PreApplyHook {
 	if in == nil {
 		return nil
 	}
-	out := new(DataOutput)
+	out := new(PreApplyHook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DefinitionReference) DeepCopyInto(out *DefinitionReference) {
+func (in *PrunedResourceKind) DeepCopyInto(out *PrunedResourceKind) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionReference.
-func (in *DefinitionReference) DeepCopy() *DefinitionReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrunedResourceKind.
+func (in *PrunedResourceKind) DeepCopy() *PrunedResourceKind {
 	if in == nil {
 		return nil
 	}
-	out := new(DefinitionReference)
+	out := new(PrunedResourceKind)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskResource) DeepCopyInto(out *DiskResource) {
+func (in *ReconcilePolicy) DeepCopyInto(out *ReconcilePolicy) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
-	if in.Ephemeral != nil {
-		in, out := &in.Ephemeral, &out.Ephemeral
-		*out = new(bool)
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskResource.
-func (in *DiskResource) DeepCopy() *DiskResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilePolicy.
+func (in *ReconcilePolicy) DeepCopy() *ReconcilePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskResource)
+	out := new(ReconcilePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExecProbe) DeepCopyInto(out *ExecProbe) {
+func (in *Revision) DeepCopyInto(out *Revision) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecProbe.
-func (in *ExecProbe) DeepCopy() *ExecProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Revision.
+func (in *Revision) DeepCopy() *Revision {
 	if in == nil {
 		return nil
 	}
-	out := new(ExecProbe)
+	out := new(Revision)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExtendedResource) DeepCopyInto(out *ExtendedResource) {
+func (in *RevisionGarbageCollector) DeepCopyInto(out *RevisionGarbageCollector) {
 	*out = *in
-	out.Required = in.Required
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResource.
-func (in *ExtendedResource) DeepCopy() *ExtendedResource {
-	if in == nil {
-		return nil
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
 	}
-	out := new(ExtendedResource)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GPUResources) DeepCopyInto(out *GPUResources) {
-	*out = *in
-	out.Required = in.Required.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUResources.
-func (in *GPUResources) DeepCopy() *GPUResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionGarbageCollector.
+func (in *RevisionGarbageCollector) DeepCopy() *RevisionGarbageCollector {
 	if in == nil {
 		return nil
 	}
-	out := new(GPUResources)
+	out := new(RevisionGarbageCollector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPGetProbe) DeepCopyInto(out *HTTPGetProbe) {
+func (in *RollbackPolicy) DeepCopyInto(out *RollbackPolicy) {
 	*out = *in
-	if in.HTTPHeaders != nil {
-		in, out := &in.HTTPHeaders, &out.HTTPHeaders
-		*out = make([]HTTPHeader, len(*in))
-		copy(*out, *in)
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetProbe.
-func (in *HTTPGetProbe) DeepCopy() *HTTPGetProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackPolicy.
+func (in *RollbackPolicy) DeepCopy() *RollbackPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPGetProbe)
+	out := new(RollbackPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+func (in *Schematic) DeepCopyInto(out *Schematic) {
 	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCISchematic)
+		**out = **in
+	}
+	if in.Kustomize != nil {
+		in, out := &in.Kustomize, &out.Kustomize
+		*out = new(KustomizeSchematic)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
-func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schematic.
+func (in *Schematic) DeepCopy() *Schematic {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPHeader)
+	out := new(Schematic)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScope) DeepCopyInto(out *HealthScope) {
+func (in *ScopeDefinition) DeepCopyInto(out *ScopeDefinition) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScope.
-func (in *HealthScope) DeepCopy() *HealthScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinition.
+func (in *ScopeDefinition) DeepCopy() *ScopeDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScope)
+	out := new(ScopeDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealthScope) DeepCopyObject() runtime.Object {
+func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -967,31 +2174,31 @@ func (in *HealthScope) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeList) DeepCopyInto(out *HealthScopeList) {
+func (in *ScopeDefinitionList) DeepCopyInto(out *ScopeDefinitionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HealthScope, len(*in))
+		*out = make([]ScopeDefinition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeList.
-func (in *HealthScopeList) DeepCopy() *HealthScopeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionList.
+func (in *ScopeDefinitionList) DeepCopy() *ScopeDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeList)
+	out := new(ScopeDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealthScopeList) DeepCopyObject() runtime.Object {
+func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -999,193 +2206,212 @@ func (in *HealthScopeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeSpec) DeepCopyInto(out *HealthScopeSpec) {
+func (in *ScopeDefinitionSpec) DeepCopyInto(out *ScopeDefinitionSpec) {
 	*out = *in
-	if in.ProbeTimeout != nil {
-		in, out := &in.ProbeTimeout, &out.ProbeTimeout
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ProbeInterval != nil {
-		in, out := &in.ProbeInterval, &out.ProbeInterval
-		*out = new(int32)
-		**out = **in
-	}
-	if in.WorkloadReferences != nil {
-		in, out := &in.WorkloadReferences, &out.WorkloadReferences
-		*out = make([]v1alpha1.TypedReference, len(*in))
+	out.Reference = in.Reference
+	if in.AppliesToWorkloads != nil {
+		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeSpec.
-func (in *HealthScopeSpec) DeepCopy() *HealthScopeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionSpec.
+func (in *ScopeDefinitionSpec) DeepCopy() *ScopeDefinitionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeSpec)
+	out := new(ScopeDefinitionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeStatus) DeepCopyInto(out *HealthScopeStatus) {
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeStatus.
-func (in *HealthScopeStatus) DeepCopy() *HealthScopeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeStatus)
+	out := new(SecretKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTrait) DeepCopyInto(out *ManualScalerTrait) {
+func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTrait.
-func (in *ManualScalerTrait) DeepCopy() *ManualScalerTrait {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountReference.
+func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTrait)
+	out := new(ServiceAccountReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManualScalerTrait) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceBindingReference) DeepCopyInto(out *ServiceBindingReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindingReference.
+func (in *ServiceBindingReference) DeepCopy() *ServiceBindingReference {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ServiceBindingReference)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitList) DeepCopyInto(out *ManualScalerTraitList) {
+func (in *SpecEncryption) DeepCopyInto(out *SpecEncryption) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ManualScalerTrait, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.AgeKeySecretRef = in.AgeKeySecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitList.
-func (in *ManualScalerTraitList) DeepCopy() *ManualScalerTraitList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecEncryption.
+func (in *SpecEncryption) DeepCopy() *SpecEncryption {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitList)
+	out := new(SpecEncryption)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManualScalerTraitList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecSource) DeepCopyInto(out *SpecSource) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	if in.ArgoApplicationSetRef != nil {
+		in, out := &in.ArgoApplicationSetRef, &out.ArgoApplicationSetRef
+		*out = new(ArgoApplicationSetReference)
+		**out = **in
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecSource.
+func (in *SpecSource) DeepCopy() *SpecSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecSource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitSpec) DeepCopyInto(out *ManualScalerTraitSpec) {
+func (in *TCPSocketProbe) DeepCopyInto(out *TCPSocketProbe) {
 	*out = *in
-	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitSpec.
-func (in *ManualScalerTraitSpec) DeepCopy() *ManualScalerTraitSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSocketProbe.
+func (in *TCPSocketProbe) DeepCopy() *TCPSocketProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitSpec)
+	out := new(TCPSocketProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitStatus) DeepCopyInto(out *ManualScalerTraitStatus) {
+func (in *TargetCluster) DeepCopyInto(out *TargetCluster) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitStatus.
-func (in *ManualScalerTraitStatus) DeepCopy() *ManualScalerTraitStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetCluster.
+func (in *TargetCluster) DeepCopy() *TargetCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitStatus)
+	out := new(TargetCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryResources) DeepCopyInto(out *MemoryResources) {
+func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]v1alpha1.TypedReference, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Edges != nil {
+		in, out := &in.Edges, &out.Edges
+		*out = make(map[string][]TopologyEdge, len(*in))
+		for key, val := range *in {
+			var outVal []TopologyEdge
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]TopologyEdge, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryResources.
-func (in *MemoryResources) DeepCopy() *MemoryResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Topology.
+func (in *Topology) DeepCopy() *Topology {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryResources)
+	out := new(Topology)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Revision) DeepCopyInto(out *Revision) {
+func (in *TopologyEdge) DeepCopyInto(out *TopologyEdge) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Revision.
-func (in *Revision) DeepCopy() *Revision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyEdge.
+func (in *TopologyEdge) DeepCopy() *TopologyEdge {
 	if in == nil {
 		return nil
 	}
-	out := new(Revision)
+	out := new(TopologyEdge)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinition) DeepCopyInto(out *ScopeDefinition) {
+func (in *TraitDefinition) DeepCopyInto(out *TraitDefinition) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinition.
-func (in *ScopeDefinition) DeepCopy() *ScopeDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinition.
+func (in *TraitDefinition) DeepCopy() *TraitDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinition)
+	out := new(TraitDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
+func (in *TraitDefinition) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1193,31 +2419,31 @@ func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinitionList) DeepCopyInto(out *ScopeDefinitionList) {
+func (in *TraitDefinitionList) DeepCopyInto(out *TraitDefinitionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScopeDefinition, len(*in))
+		*out = make([]TraitDefinition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionList.
-func (in *ScopeDefinitionList) DeepCopy() *ScopeDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionList.
+func (in *TraitDefinitionList) DeepCopy() *TraitDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinitionList)
+	out := new(TraitDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
+func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1225,71 +2451,87 @@ func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinitionSpec) DeepCopyInto(out *ScopeDefinitionSpec) {
+func (in *TraitDefinitionSpec) DeepCopyInto(out *TraitDefinitionSpec) {
 	*out = *in
 	out.Reference = in.Reference
+	if in.AppliesToWorkloads != nil {
+		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConflictsWith != nil {
+		in, out := &in.ConflictsWith, &out.ConflictsWith
+		*out = make([]TraitKindReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrunedResources != nil {
+		in, out := &in.PrunedResources, &out.PrunedResources
+		*out = make([]PrunedResourceKind, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionSpec.
-func (in *ScopeDefinitionSpec) DeepCopy() *ScopeDefinitionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionSpec.
+func (in *TraitDefinitionSpec) DeepCopy() *TraitDefinitionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinitionSpec)
+	out := new(TraitDefinitionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+func (in *TraitDefinitionStatus) DeepCopyInto(out *TraitDefinitionStatus) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
-func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionStatus.
+func (in *TraitDefinitionStatus) DeepCopy() *TraitDefinitionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeySelector)
+	out := new(TraitDefinitionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TCPSocketProbe) DeepCopyInto(out *TCPSocketProbe) {
+func (in *TraitKindReference) DeepCopyInto(out *TraitKindReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSocketProbe.
-func (in *TCPSocketProbe) DeepCopy() *TCPSocketProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitKindReference.
+func (in *TraitKindReference) DeepCopy() *TraitKindReference {
 	if in == nil {
 		return nil
 	}
-	out := new(TCPSocketProbe)
+	out := new(TraitKindReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinition) DeepCopyInto(out *TraitDefinition) {
+func (in *TraitPolicy) DeepCopyInto(out *TraitPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinition.
-func (in *TraitDefinition) DeepCopy() *TraitDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitPolicy.
+func (in *TraitPolicy) DeepCopy() *TraitPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinition)
+	out := new(TraitPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TraitDefinition) DeepCopyObject() runtime.Object {
+func (in *TraitPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1297,31 +2539,31 @@ func (in *TraitDefinition) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinitionList) DeepCopyInto(out *TraitDefinitionList) {
+func (in *TraitPolicyList) DeepCopyInto(out *TraitPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TraitDefinition, len(*in))
+		*out = make([]TraitPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionList.
-func (in *TraitDefinitionList) DeepCopy() *TraitDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitPolicyList.
+func (in *TraitPolicyList) DeepCopy() *TraitPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinitionList)
+	out := new(TraitPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
+func (in *TraitPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1329,22 +2571,24 @@ func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinitionSpec) DeepCopyInto(out *TraitDefinitionSpec) {
+func (in *TraitPolicySpec) DeepCopyInto(out *TraitPolicySpec) {
 	*out = *in
-	out.Reference = in.Reference
-	if in.AppliesToWorkloads != nil {
-		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.InjectTrait != nil {
+		in, out := &in.InjectTrait, &out.InjectTrait
+		*out = make([]ComponentTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionSpec.
-func (in *TraitDefinitionSpec) DeepCopy() *TraitDefinitionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitPolicySpec.
+func (in *TraitPolicySpec) DeepCopy() *TraitPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinitionSpec)
+	out := new(TraitPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1385,6 +2629,7 @@ func (in *WorkloadDefinition) DeepCopyInto(out *WorkloadDefinition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadDefinition.
@@ -1448,6 +2693,33 @@ func (in *WorkloadDefinitionSpec) DeepCopyInto(out *WorkloadDefinitionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RequiredTraits != nil {
+		in, out := &in.RequiredTraits, &out.RequiredTraits
+		*out = make([]TraitKindReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schematic != nil {
+		in, out := &in.Schematic, &out.Schematic
+		*out = new(Schematic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RevisionGarbageCollector != nil {
+		in, out := &in.RevisionGarbageCollector, &out.RevisionGarbageCollector
+		*out = new(RevisionGarbageCollector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadDefinitionSpec.
@@ -1460,6 +2732,68 @@ func (in *WorkloadDefinitionSpec) DeepCopy() *WorkloadDefinitionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadDefinitionStatus) DeepCopyInto(out *WorkloadDefinitionStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadDefinitionStatus.
+func (in *WorkloadDefinitionStatus) DeepCopy() *WorkloadDefinitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadDefinitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadHealth) DeepCopyInto(out *WorkloadHealth) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadHealth.
+func (in *WorkloadHealth) DeepCopy() *WorkloadHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPrometheusEndpoint) DeepCopyInto(out *WorkloadPrometheusEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPrometheusEndpoint.
+func (in *WorkloadPrometheusEndpoint) DeepCopy() *WorkloadPrometheusEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPrometheusEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadRollout) DeepCopyInto(out *WorkloadRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadRollout.
+func (in *WorkloadRollout) DeepCopy() *WorkloadRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadScope) DeepCopyInto(out *WorkloadScope) {
 	*out = *in
@@ -1479,17 +2813,48 @@ func (in *WorkloadScope) DeepCopy() *WorkloadScope {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 	out.Reference = in.Reference
 	if in.Traits != nil {
 		in, out := &in.Traits, &out.Traits
 		*out = make([]WorkloadTrait, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Scopes != nil {
 		in, out := &in.Scopes, &out.Scopes
 		*out = make([]WorkloadScope, len(*in))
 		copy(*out, *in)
 	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(WorkloadHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAppliedSpec != nil {
+		in, out := &in.LastAppliedSpec, &out.LastAppliedSpec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(WorkloadRollout)
+		**out = **in
+	}
+	if in.TrafficSplit != nil {
+		in, out := &in.TrafficSplit, &out.TrafficSplit
+		*out = new(WorkloadTrafficSplit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
@@ -1502,9 +2867,25 @@ func (in *WorkloadStatus) DeepCopy() *WorkloadStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadTrafficSplit) DeepCopyInto(out *WorkloadTrafficSplit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadTrafficSplit.
+func (in *WorkloadTrafficSplit) DeepCopy() *WorkloadTrafficSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadTrafficSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadTrait) DeepCopyInto(out *WorkloadTrait) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 	out.Reference = in.Reference
 }
 
@@ -3,18 +3,34 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"hash/fnv"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/transport"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	corev1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/cache"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/dependency"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/tracing"
+	webhookappconfig "github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/applicationconfiguration"
 )
 
 var scheme = runtime.NewScheme()
@@ -28,9 +44,62 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var useWebhook bool
+	var webhookCertDir string
+	var registryNamespace string
+	var sopsConfig string
+	var ageIdentity string
+	var allowedWorkloadKinds string
+	var maxConcurrentGroups int
+	var otelEndpoint string
+	var fieldSelector string
+	var vaultAddr string
+	var allowNamespaceCreation bool
+	var workloadDeletionTimeout time.Duration
+	var batchSize int
+	var leasePartitioning bool
+	var partitionNamespace string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&useWebhook, "use-webhook", false,
+		"Serve the ApplicationConfiguration validating and mutating webhooks. Requires serving certificates in --webhook-cert-dir.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"The directory containing the tls.crt and tls.key the webhook server serves.")
+	flag.StringVar(&registryNamespace, "registry-namespace", "oam-system",
+		"The namespace of the ConfigMap in which globally unique ApplicationConfiguration names are registered.")
+	flag.StringVar(&sopsConfig, "sops-config", "",
+		"Path to the .sops.yaml file with which encrypted ApplicationConfiguration parameter values are decrypted.")
+	flag.StringVar(&ageIdentity, "age-identity", "",
+		"Path to the age identity file used to decrypt sensitive ApplicationConfiguration parameter values, which the mutating webhook "+
+			"encrypts for the public key referenced by spec.encryption.")
+	flag.StringVar(&allowedWorkloadKinds, "allowed-workload-kinds", "",
+		"Comma separated group/version/kind of the workloads ApplicationConfigurations may use, e.g. apps/v1/Deployment. All kinds are allowed if empty.")
+	flag.IntVar(&maxConcurrentGroups, "max-concurrent-groups", 1,
+		"The number of component groups of an ApplicationConfiguration that may be rolled out at once.")
+	flag.StringVar(&otelEndpoint, "otel-endpoint", "",
+		"The OTLP HTTP endpoint to which ApplicationConfiguration reconcile traces are exported, e.g. http://otel-collector:4318. Tracing is disabled if empty.")
+	flag.StringVar(&fieldSelector, "field-selector", "",
+		"Only cache and reconcile the ApplicationConfigurations matching this field selector, e.g. metadata.namespace=production. "+
+			"Allows ApplicationConfigurations to be sharded across several controller instances, each with a different selector "+
+			"and thus a different leader election ID. All ApplicationConfigurations are reconciled if empty.")
+	flag.StringVar(&vaultAddr, "vault-addr", "",
+		"The address of the Vault server from which vault://<path>#<key> parameter values are resolved, e.g. https://vault:8200. "+
+			"The controller authenticates using the AppRole role and secret IDs in the VAULT_ROLE_ID and VAULT_SECRET_ID environment variables.")
+	flag.BoolVar(&allowNamespaceCreation, "allow-namespace-creation", false,
+		"Create and label the namespaces of ApplicationConfigurations per their spec.namespaceLabels. Requires permission to get, create and patch namespaces.")
+	flag.DurationVar(&workloadDeletionTimeout, "workload-deletion-timeout", 0,
+		"Wait up to this long for the workloads of a deleted ApplicationConfiguration, and their finalizers, to be deleted before the "+
+			"ApplicationConfiguration is. Workloads are garbage collected after their ApplicationConfiguration is deleted if zero.")
+	flag.IntVar(&batchSize, "batch-size", 0,
+		"Server-side apply the traits of each workload in parallel batches, with up to this many requests in flight at once. "+
+			"Traits are applied one at a time if zero.")
+	flag.BoolVar(&leasePartitioning, "lease-partitioning", false,
+		"Partition ApplicationConfigurations between several replicas of the controller manager using Leases. "+
+			"Each replica reconciles the ApplicationConfigurations it owns, per rendezvous hashing of their UIDs. "+
+			"The remaining controllers are run by an elected leader, so this implies --enable-leader-election.")
+	flag.StringVar(&partitionNamespace, "partition-namespace", "oam-system",
+		"The namespace of the Leases held by each replica of the controller manager when --lease-partitioning is enabled.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
@@ -39,12 +108,46 @@ func main() {
 
 	oamLog := ctrl.Log.WithName("oam-core-runtime")
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	cfg := ctrl.GetConfigOrDie()
+	var tp *sdktrace.TracerProvider
+	if otelEndpoint != "" {
+		var err error
+		if tp, err = tracing.NewTracerProvider(context.Background(), otelEndpoint, "oam-kubernetes-runtime"); err != nil {
+			oamLog.Error(err, "unable to setup the tracer")
+			os.Exit(1)
+		}
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		// Propagate reconcile traces to the API server.
+		cfg.WrapTransport = transport.Wrappers(cfg.WrapTransport, func(rt http.RoundTripper) http.RoundTripper {
+			return otelhttp.NewTransport(rt)
+		})
+	}
+
+	leaderElectionID := "oam-kubernetes-runtime"
+	selectors := applicationconfiguration.CacheSelectors()
+	if fieldSelector != "" {
+		sel, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			oamLog.Error(err, "unable to parse the field selector")
+			os.Exit(1)
+		}
+		selectors = append(selectors, cache.Selector{Kind: &corev1alpha2.ApplicationConfiguration{}, Fields: sel})
+
+		// Each shard must elect its own leader.
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(sel.String()))
+		leaderElectionID = fmt.Sprintf("%s-%08x", leaderElectionID, h.Sum32())
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "oam-kubernetes-runtime",
+		LeaderElection:     enableLeaderElection || leasePartitioning,
+		LeaderElectionID:   leaderElectionID,
 		Port:               9443,
+		CertDir:            webhookCertDir,
+		NewCache:           cache.NewSelectorCacheFunc(selectors...),
 	})
 	if err != nil {
 		oamLog.Error(err, "unable to create a controller manager")
@@ -53,14 +156,107 @@ func main() {
 
 	l := logging.NewLogrLogger(oamLog)
 	dependency.SetupGlobalDAGManager(l, mgr.GetClient())
-	if err = v1alpha2.Setup(mgr, logging.NewLogrLogger(oamLog)); err != nil {
+	// The runnables used by the ApplicationConfiguration reconciler, such as
+	// the DAG manager that triggers the dependencies of the
+	// ApplicationConfigurations it reconciles, run wherever it runs.
+	addReconcilerRunnable := func(r manager.Runnable) error {
+		if leasePartitioning {
+			r = applicationconfiguration.PartitionedRunnable(r)
+		}
+		return mgr.Add(r)
+	}
+	dag := manager.RunnableFunc(func(stop <-chan struct{}) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-stop
+			cancel()
+		}()
+		dependency.GlobalManager.Start(ctx)
+		return nil
+	})
+	if err = addReconcilerRunnable(dag); err != nil {
+		oamLog.Error(err, "unable to setup the DAG manager")
+		os.Exit(1)
+	}
+	var o []applicationconfiguration.ReconcilerOption
+	if tp != nil {
+		// Export any remaining spans when the manager stops.
+		shutdown := manager.RunnableFunc(func(stop <-chan struct{}) error {
+			<-stop
+			return tp.Shutdown(context.Background())
+		})
+		if err = addReconcilerRunnable(shutdown); err != nil {
+			oamLog.Error(err, "unable to setup the tracer")
+			os.Exit(1)
+		}
+		o = append(o, applicationconfiguration.WithTracer(tp.Tracer(tracing.InstrumentationName)))
+	}
+	if sopsConfig != "" {
+		d, err := applicationconfiguration.NewSOPSDecryptor(sopsConfig)
+		if err != nil {
+			oamLog.Error(err, "unable to setup the sops decryptor")
+			os.Exit(1)
+		}
+		o = append(o, applicationconfiguration.WithSpecDecryptor(d))
+	}
+	var encryptor applicationconfiguration.SpecEncryptor
+	if ageIdentity != "" {
+		e, err := applicationconfiguration.NewAgeEncryptor(ageIdentity)
+		if err != nil {
+			oamLog.Error(err, "unable to setup the age encryptor")
+			os.Exit(1)
+		}
+		encryptor = e
+		o = append(o, applicationconfiguration.WithSpecEncryptor(e))
+	}
+	if vaultAddr != "" {
+		vault := applicationconfiguration.NewVaultSecretResolver(&http.Client{}, vaultAddr,
+			os.Getenv("VAULT_ROLE_ID"), os.Getenv("VAULT_SECRET_ID"), l.WithValues("component", "vault"))
+		if err = addReconcilerRunnable(vault); err != nil {
+			oamLog.Error(err, "unable to setup the Vault secret resolver")
+			os.Exit(1)
+		}
+		o = append(o, applicationconfiguration.WithParameterResolver(vault))
+	}
+	if allowNamespaceCreation {
+		o = append(o, applicationconfiguration.WithNamespaceCreation())
+	}
+	if workloadDeletionTimeout > 0 {
+		o = append(o, applicationconfiguration.WithWorkloadFinalization(workloadDeletionTimeout))
+	}
+	if batchSize > 0 {
+		o = append(o, applicationconfiguration.WithTraitBatchSize(batchSize))
+	}
+	if leasePartitioning {
+		identity, err := os.Hostname()
+		if err != nil {
+			oamLog.Error(err, "unable to determine the lease partition identity")
+			os.Exit(1)
+		}
+		p := applicationconfiguration.NewLeasePartitioner(mgr.GetClient(), partitionNamespace, identity,
+			applicationconfiguration.DefaultPartitionLeaseDuration, l.WithValues("component", "partitioner"))
+		o = append(o, applicationconfiguration.WithLeasePartitioner(p))
+	}
+	kinds, err := applicationconfiguration.ParseWorkloadKinds(allowedWorkloadKinds)
+	if err != nil {
+		oamLog.Error(err, "unable to parse the allowed workload kinds")
+		os.Exit(1)
+	}
+	o = append(o,
+		applicationconfiguration.WithAllowedWorkloadKinds(kinds...),
+		applicationconfiguration.WithMaxConcurrentGroups(maxConcurrentGroups))
+	if err = v1alpha2.Setup(mgr, logging.NewLogrLogger(oamLog), o...); err != nil {
 		oamLog.Error(err, "unable to setup the oam core controller")
 		os.Exit(1)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	go dependency.GlobalManager.Start(ctx)
+	if useWebhook {
+		if err = webhookappconfig.Setup(mgr, registryNamespace, encryptor, l); err != nil {
+			oamLog.Error(err, "unable to setup the oam webhook")
+			os.Exit(1)
+		}
+	}
 
 	oamLog.Info("starting the controller manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
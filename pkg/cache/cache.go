@@ -0,0 +1,351 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache provides controller-runtime caches that only cache a subset of
+// the objects of some kinds. Controllers using such a cache only see, and thus
+// only reconcile or read, the objects it caches. This allows a controller to
+// watch e.g. only the Secrets it needs, rather than every Secret in the
+// cluster, and allows the objects of a kind to be sharded across several
+// controller instances, e.g. by namespace.
+package cache
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Error strings.
+const (
+	errNewCache             = "cannot create cache"
+	errGetGVK               = "cannot determine group, version, and kind of object"
+	errGetRESTMapping       = "cannot get REST mapping"
+	errNewRESTClient        = "cannot create REST client"
+	errNewRESTMapper        = "cannot create REST mapper"
+	errFmtNotAssignable     = "cannot assign cached %T to %T"
+	errFmtNotExactSelector  = "field selector %q does not select an exact field value"
+	errFmtNotIndexedField   = "field %q is not indexed"
+	errFmtUnsupportedObject = "cached object %T is not a Kubernetes object"
+)
+
+// Mirrors the index naming of controller-runtime caches, so that field indexes
+// behave identically regardless of whether an object is selectively cached.
+const (
+	fieldIndexPrefix = "field:"
+	allNamespaces    = "__all_namespaces"
+)
+
+// defaultResync is the resync period controller-runtime caches default to.
+const defaultResync = 10 * time.Hour
+
+// A Selector selects the objects of a kind that are cached.
+type Selector struct {
+	// Kind of the selected objects, e.g. &corev1.Secret{}.
+	Kind runtime.Object
+
+	// Labels the selected objects must match.
+	Labels labels.Selector
+
+	// Fields the selected objects must match. Note that the API server only
+	// supports selecting most kinds by metadata.name and metadata.namespace.
+	Fields fields.Selector
+}
+
+// NewSelectorCacheFunc returns a function that creates a controller-runtime
+// cache in which objects of the kinds of the supplied selectors are only
+// cached when they match their selector. Objects of all other kinds are
+// cached as usual.
+func NewSelectorCacheFunc(s ...Selector) ctrlcache.NewCacheFunc {
+	return func(cfg *rest.Config, o ctrlcache.Options) (ctrlcache.Cache, error) {
+		if o.Scheme == nil {
+			o.Scheme = scheme.Scheme
+		}
+		if o.Mapper == nil {
+			m, err := apiutil.NewDynamicRESTMapper(cfg)
+			if err != nil {
+				return nil, errors.Wrap(err, errNewRESTMapper)
+			}
+			o.Mapper = m
+		}
+
+		c, err := ctrlcache.New(cfg, o)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewCache)
+		}
+
+		sc := &selectorCache{Cache: c, scheme: o.Scheme, informers: make(map[schema.GroupVersionKind]*selectedInformer, len(s))}
+		for _, sel := range s {
+			i, err := newSelectedInformer(cfg, o, sel)
+			if err != nil {
+				return nil, err
+			}
+			sc.informers[i.gvk] = i
+		}
+		return sc, nil
+	}
+}
+
+// A selectedInformer informs on the objects of one kind that match a
+// selector.
+type selectedInformer struct {
+	toolscache.SharedIndexInformer
+
+	gvk      schema.GroupVersionKind
+	resource schema.GroupResource
+}
+
+func newSelectedInformer(cfg *rest.Config, o ctrlcache.Options, s Selector) (*selectedInformer, error) {
+	gvk, err := apiutil.GVKForObject(s.Kind, o.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetGVK)
+	}
+	mapping, err := o.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetRESTMapping)
+	}
+	rc, err := apiutil.RESTClientForGVK(gvk, cfg, serializer.NewCodecFactory(o.Scheme))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRESTClient)
+	}
+
+	ns := metav1.NamespaceAll
+	if mapping.Scope.Name() == apimeta.RESTScopeNameNamespace {
+		ns = o.Namespace
+	}
+	lw := toolscache.NewFilteredListWatchFromClient(rc, mapping.Resource.Resource, ns, func(lo *metav1.ListOptions) {
+		if s.Labels != nil {
+			lo.LabelSelector = s.Labels.String()
+		}
+		if s.Fields != nil {
+			lo.FieldSelector = s.Fields.String()
+		}
+	})
+	resync := defaultResync
+	if o.Resync != nil {
+		resync = *o.Resync
+	}
+	i := toolscache.NewSharedIndexInformer(lw, s.Kind.DeepCopyObject(), resync, toolscache.Indexers{
+		toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc,
+	})
+	return &selectedInformer{SharedIndexInformer: i, gvk: gvk, resource: mapping.Resource.GroupResource()}, nil
+}
+
+// A selectorCache serves objects of some kinds from informers that only watch
+// the objects matching a selector. Objects of all other kinds are served by
+// the embedded Cache.
+type selectorCache struct {
+	ctrlcache.Cache
+
+	scheme    *runtime.Scheme
+	informers map[schema.GroupVersionKind]*selectedInformer
+}
+
+// informerFor returns the selective informer of the supplied object, or list
+// of objects, if its kind is selectively cached.
+func (c *selectorCache) informerFor(obj runtime.Object) (*selectedInformer, bool) {
+	if _, ok := obj.(runtime.Unstructured); ok {
+		return nil, false
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, false
+	}
+	if i, ok := c.informers[gvk]; ok {
+		return i, true
+	}
+	i, ok := c.informers[gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))]
+	return i, ok
+}
+
+// Get the object identified by the supplied key.
+func (c *selectorCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	i, ok := c.informerFor(obj)
+	if !ok {
+		return c.Cache.Get(ctx, key, obj)
+	}
+	k := key.Name
+	if key.Namespace != "" {
+		k = key.Namespace + "/" + key.Name
+	}
+	item, exists, err := i.GetIndexer().GetByKey(k)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return kerrors.NewNotFound(i.resource, key.Name)
+	}
+	cached, ok := item.(runtime.Object)
+	if !ok {
+		return errors.Errorf(errFmtUnsupportedObject, item)
+	}
+	out := reflect.ValueOf(obj)
+	in := reflect.ValueOf(cached.DeepCopyObject())
+	if !in.Type().AssignableTo(out.Type()) {
+		return errors.Errorf(errFmtNotAssignable, cached, obj)
+	}
+	reflect.Indirect(out).Set(reflect.Indirect(in))
+	obj.GetObjectKind().SetGroupVersionKind(i.gvk)
+	return nil
+}
+
+// List the objects matching the supplied options.
+func (c *selectorCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	i, ok := c.informerFor(list)
+	if !ok {
+		return c.Cache.List(ctx, list, opts...)
+	}
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+
+	var items []interface{}
+	var err error
+	switch {
+	case lo.FieldSelector != nil:
+		field, value, ok := exactMatch(lo.FieldSelector)
+		if !ok {
+			return errors.Errorf(errFmtNotExactSelector, lo.FieldSelector)
+		}
+		ns := lo.Namespace
+		if ns == "" {
+			ns = allNamespaces
+		}
+		if _, ok := i.GetIndexer().GetIndexers()[fieldIndexPrefix+field]; !ok {
+			return errors.Errorf(errFmtNotIndexedField, field)
+		}
+		items, err = i.GetIndexer().ByIndex(fieldIndexPrefix+field, ns+"/"+value)
+	case lo.Namespace != "":
+		items, err = i.GetIndexer().ByIndex(toolscache.NamespaceIndex, lo.Namespace)
+	default:
+		items = i.GetIndexer().List()
+	}
+	if err != nil {
+		return err
+	}
+
+	objs := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(runtime.Object)
+		if !ok {
+			return errors.Errorf(errFmtUnsupportedObject, item)
+		}
+		if lo.LabelSelector != nil {
+			m, err := apimeta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			if !lo.LabelSelector.Matches(labels.Set(m.GetLabels())) {
+				continue
+			}
+		}
+		objs = append(objs, obj.DeepCopyObject())
+	}
+	return apimeta.SetList(list, objs)
+}
+
+// GetInformer returns the informer for the supplied object.
+func (c *selectorCache) GetInformer(ctx context.Context, obj runtime.Object) (ctrlcache.Informer, error) {
+	if i, ok := c.informerFor(obj); ok {
+		return i, nil
+	}
+	return c.Cache.GetInformer(ctx, obj)
+}
+
+// GetInformerForKind returns the informer for the supplied kind.
+func (c *selectorCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (ctrlcache.Informer, error) {
+	if i, ok := c.informers[gvk]; ok {
+		return i, nil
+	}
+	return c.Cache.GetInformerForKind(ctx, gvk)
+}
+
+// IndexField adds an index of the supplied field to the informer of the
+// supplied object.
+func (c *selectorCache) IndexField(ctx context.Context, obj runtime.Object, field string, extract client.IndexerFunc) error {
+	i, ok := c.informerFor(obj)
+	if !ok {
+		return c.Cache.IndexField(ctx, obj, field, extract)
+	}
+	return i.AddIndexers(toolscache.Indexers{fieldIndexPrefix + field: func(item interface{}) ([]string, error) {
+		obj, ok := item.(runtime.Object)
+		if !ok {
+			return nil, errors.Errorf(errFmtUnsupportedObject, item)
+		}
+		m, err := apimeta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		values := extract(obj)
+		keys := make([]string, 0, 2*len(values))
+		for _, v := range values {
+			keys = append(keys, allNamespaces+"/"+v)
+			if ns := m.GetNamespace(); ns != "" {
+				keys = append(keys, ns+"/"+v)
+			}
+		}
+		return keys, nil
+	}})
+}
+
+// Start the selective informers and the embedded Cache. Blocks until the
+// supplied channel is closed.
+func (c *selectorCache) Start(stop <-chan struct{}) error {
+	for _, i := range c.informers {
+		go i.Run(stop)
+	}
+	return c.Cache.Start(stop)
+}
+
+// WaitForCacheSync waits for the selective informers and the embedded Cache
+// to sync.
+func (c *selectorCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	for _, i := range c.informers {
+		if !toolscache.WaitForCacheSync(stop, i.HasSynced) {
+			return false
+		}
+	}
+	return c.Cache.WaitForCacheSync(stop)
+}
+
+// exactMatch returns the field and value of a field selector that selects
+// exactly one value of one field.
+func exactMatch(sel fields.Selector) (string, string, bool) {
+	reqs := sel.Requirements()
+	if len(reqs) != 1 {
+		return "", "", false
+	}
+	if reqs[0].Operator != selection.Equals && reqs[0].Operator != selection.DoubleEquals {
+		return "", "", false
+	}
+	return reqs[0].Field, reqs[0].Value, true
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestCache(t *testing.T, secrets ...*corev1.Secret) *selectorCache {
+	t.Helper()
+	gvk := corev1.SchemeGroupVersion.WithKind("Secret")
+	i := &selectedInformer{
+		SharedIndexInformer: toolscache.NewSharedIndexInformer(&toolscache.ListWatch{}, &corev1.Secret{}, 0, toolscache.Indexers{
+			toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc,
+		}),
+		gvk:      gvk,
+		resource: corev1.SchemeGroupVersion.WithResource("secrets").GroupResource(),
+	}
+	c := &selectorCache{scheme: scheme.Scheme, informers: map[schema.GroupVersionKind]*selectedInformer{gvk: i}}
+	if err := c.IndexField(context.Background(), &corev1.Secret{}, "type", func(o runtime.Object) []string {
+		return []string{string(o.(*corev1.Secret).Type)}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range secrets {
+		if err := i.GetIndexer().Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestSelectorCacheGet(t *testing.T) {
+	cached := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "production", Name: "cool"}}
+
+	cases := map[string]struct {
+		reason   string
+		key      types.NamespacedName
+		want     string
+		notFound bool
+	}{
+		"Cached": {
+			reason: "Cached objects should be returned",
+			key:    types.NamespacedName{Namespace: "production", Name: "cool"},
+			want:   "cool",
+		},
+		"NotCached": {
+			reason:   "Objects that were not cached should not be found",
+			key:      types.NamespacedName{Namespace: "staging", Name: "cool"},
+			notFound: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestCache(t, cached)
+			got := &corev1.Secret{}
+			err := c.Get(context.Background(), tc.key, got)
+			if diff := cmp.Diff(tc.notFound, kerrors.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want not found, +got not found:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelectorCacheList(t *testing.T) {
+	secret := func(ns, name string, l map[string]string, st corev1.SecretType) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: l}, Type: st}
+	}
+	cached := []*corev1.Secret{
+		secret("production", "a", map[string]string{"cool": "true"}, corev1.SecretTypeOpaque),
+		secret("production", "b", nil, corev1.SecretTypeTLS),
+		secret("staging", "c", map[string]string{"cool": "true"}, corev1.SecretTypeOpaque),
+	}
+
+	cases := map[string]struct {
+		reason string
+		opts   []client.ListOption
+		want   []string
+	}{
+		"All": {
+			reason: "All cached objects should be listed when no options are supplied",
+			want:   []string{"a", "b", "c"},
+		},
+		"InNamespace": {
+			reason: "Only cached objects in the supplied namespace should be listed",
+			opts:   []client.ListOption{client.InNamespace("production")},
+			want:   []string{"a", "b"},
+		},
+		"MatchingLabels": {
+			reason: "Only cached objects with the supplied labels should be listed",
+			opts:   []client.ListOption{client.MatchingLabels{"cool": "true"}},
+			want:   []string{"a", "c"},
+		},
+		"MatchingFields": {
+			reason: "Only cached objects with the supplied indexed field value should be listed",
+			opts:   []client.ListOption{client.InNamespace("staging"), client.MatchingFields{"type": string(corev1.SecretTypeOpaque)}},
+			want:   []string{"c"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestCache(t, cached...)
+			l := &corev1.SecretList{}
+			if err := c.List(context.Background(), l, tc.opts...); err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]bool{}
+			for _, i := range l.Items {
+				got[i.GetName()] = true
+			}
+			want := map[string]bool{}
+			for _, n := range tc.want {
+				want[n] = true
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nc.List(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
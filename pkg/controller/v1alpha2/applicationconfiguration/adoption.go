@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Adoption error strings.
+const (
+	errNotUnstructured     = "object is not unstructured"
+	errFmtAdoptionConflict = "%s %q already exists and is not owned by application configuration %q"
+)
+
+// adoptionOptions returns the apply options that implement the adoption policy
+// of the supplied ApplicationConfiguration.
+func adoptionOptions(ac *v1alpha2.ApplicationConfiguration) []resource.ApplyOption {
+	switch ac.Spec.AdoptionPolicy {
+	case v1alpha2.AdoptionPolicyAdopt:
+		return []resource.ApplyOption{adoptExisting(ac)}
+	case v1alpha2.AdoptionPolicyReject:
+		return []resource.ApplyOption{rejectExisting(ac)}
+	default:
+		return nil
+	}
+}
+
+// owner returns the value of the owner annotation of objects adopted by the
+// supplied ApplicationConfiguration.
+func owner(ac *v1alpha2.ApplicationConfiguration) string {
+	return types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}.String()
+}
+
+// controlledBy returns true if the supplied object is controlled by the
+// supplied controller, e.g. the ApplicationConfiguration that created it.
+func controlledBy(o, controller metav1.Object) bool {
+	ref := metav1.GetControllerOf(o)
+	return ref != nil && ref.UID == controller.GetUID()
+}
+
+// adoptExisting returns an ApplyOption that leaves an existing object that was
+// not created by the supplied ApplicationConfiguration unchanged, except for
+// an annotation marking it as adopted.
+func adoptExisting(ac *v1alpha2.ApplicationConfiguration) resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, ok := current.(*unstructured.Unstructured)
+		if !ok {
+			return errors.New(errNotUnstructured)
+		}
+		d, ok := desired.(*unstructured.Unstructured)
+		if !ok {
+			return errors.New(errNotUnstructured)
+		}
+		if controlledBy(c, ac) {
+			return nil
+		}
+
+		// The desired object is patched onto the existing object, so we patch
+		// only the annotations.
+		a := c.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string)
+		}
+		a[oam.AnnotationOwner] = owner(ac)
+		adopted := &unstructured.Unstructured{}
+		adopted.SetAPIVersion(c.GetAPIVersion())
+		adopted.SetKind(c.GetKind())
+		adopted.SetNamespace(c.GetNamespace())
+		adopted.SetName(c.GetName())
+		adopted.SetAnnotations(a)
+		d.Object = adopted.Object
+		return nil
+	}
+}
+
+// rejectExisting returns an ApplyOption that refuses to apply an object that
+// already exists, unless it was created or previously adopted by the supplied
+// ApplicationConfiguration.
+func rejectExisting(ac *v1alpha2.ApplicationConfiguration) resource.ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		c, ok := current.(*unstructured.Unstructured)
+		if !ok {
+			return errors.New(errNotUnstructured)
+		}
+		if controlledBy(c, ac) || c.GetAnnotations()[oam.AnnotationOwner] == owner(ac) {
+			return nil
+		}
+		return &adoptionConflictError{msg: fmt.Sprintf(errFmtAdoptionConflict, c.GetKind(), c.GetName(), ac.GetName())}
+	}
+}
+
+// An adoptionConflictError indicates that an object already exists, and the
+// adoption policy of an ApplicationConfiguration rejects it.
+type adoptionConflictError struct {
+	msg string
+}
+
+func (e *adoptionConflictError) Error() string {
+	return e.msg
+}
+
+// IsAdoptionConflict returns true if the supplied error indicates that an
+// object already exists, and the adoption policy of an ApplicationConfiguration
+// rejects it.
+func IsAdoptionConflict(err error) bool {
+	_, ok := errors.Cause(err).(*adoptionConflictError)
+	return ok
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestAdoption(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "coolapp",
+		UID:       types.UID("coolapp-uid"),
+	}}
+
+	existing := func(mod func(u *unstructured.Unstructured)) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetNamespace("ns")
+		u.SetName("cool")
+		_ = unstructured.SetNestedField(u.Object, int64(3), "spec", "replicas")
+		if mod != nil {
+			mod(u)
+		}
+		return u
+	}
+	controlled := func(u *unstructured.Unstructured) {
+		u.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)})
+	}
+	adopted := func(u *unstructured.Unstructured) {
+		u.SetAnnotations(map[string]string{oam.AnnotationOwner: "ns/coolapp"})
+	}
+	desired := func() *unstructured.Unstructured {
+		return existing(func(u *unstructured.Unstructured) {
+			_ = unstructured.SetNestedField(u.Object, int64(1), "spec", "replicas")
+		})
+	}
+
+	type want struct {
+		desired *unstructured.Unstructured
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		policy  v1alpha2.AdoptionPolicy
+		current *unstructured.Unstructured
+		want    want
+	}{
+		"AdoptControlled": {
+			reason:  "Objects created by the ApplicationConfiguration should be applied as usual",
+			policy:  v1alpha2.AdoptionPolicyAdopt,
+			current: existing(controlled),
+			want:    want{desired: desired()},
+		},
+		"Adopt": {
+			reason:  "Only the owner annotation should be applied to objects that were not created by the ApplicationConfiguration",
+			policy:  v1alpha2.AdoptionPolicyAdopt,
+			current: existing(nil),
+			want: want{desired: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{}
+				u.SetAPIVersion("apps/v1")
+				u.SetKind("Deployment")
+				u.SetNamespace("ns")
+				u.SetName("cool")
+				adopted(u)
+				return u
+			}()},
+		},
+		"RejectControlled": {
+			reason:  "Objects created by the ApplicationConfiguration should be applied as usual",
+			policy:  v1alpha2.AdoptionPolicyReject,
+			current: existing(controlled),
+			want:    want{desired: desired()},
+		},
+		"RejectAdopted": {
+			reason:  "Objects previously adopted by the ApplicationConfiguration should be applied as usual",
+			policy:  v1alpha2.AdoptionPolicyReject,
+			current: existing(adopted),
+			want:    want{desired: desired()},
+		},
+		"Reject": {
+			reason:  "Objects that were not created by the ApplicationConfiguration should be rejected",
+			policy:  v1alpha2.AdoptionPolicyReject,
+			current: existing(nil),
+			want: want{
+				desired: desired(),
+				err:     &adoptionConflictError{msg: fmt.Sprintf(errFmtAdoptionConflict, "Deployment", "cool", "coolapp")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := ac.DeepCopy()
+			a.Spec.AdoptionPolicy = tc.policy
+			d := desired()
+			var err error
+			for _, o := range adoptionOptions(a) {
+				if err = o(context.Background(), tc.current, d); err != nil {
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nadoptionOptions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.desired, d); diff != "" {
+				t.Errorf("\n%s\nadoptionOptions(...): -want desired, +got desired:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAdoptionOptionsOverwrite(t *testing.T) {
+	for _, p := range []v1alpha2.AdoptionPolicy{"", v1alpha2.AdoptionPolicyOverwrite} {
+		ac := &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{AdoptionPolicy: p}}
+		if got := adoptionOptions(ac); len(got) != 0 {
+			t.Errorf("adoptionOptions(%q): want no options, got %d", p, len(got))
+		}
+	}
+}
+
+func TestIsAdoptionConflict(t *testing.T) {
+	err := errors.Wrap(&adoptionConflictError{msg: "boom"}, errFmtApplyWorkload)
+	if !IsAdoptionConflict(err) {
+		t.Errorf("IsAdoptionConflict(...): want true for a wrapped adoption conflict")
+	}
+	if IsAdoptionConflict(errors.New("boom")) {
+		t.Errorf("IsAdoptionConflict(...): want false for other errors")
+	}
+}
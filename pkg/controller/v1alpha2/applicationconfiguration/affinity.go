@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Affinity error strings.
+const (
+	errParseAffinity   = "cannot parse pod template affinity"
+	errConvertAffinity = "cannot convert affinity"
+)
+
+const (
+	// podSpecPath is the field path of the pod spec of workloads that embed
+	// a pod template.
+	podSpecPath = "spec.template.spec"
+
+	// podAffinityPath is the field path of the affinity of workloads that
+	// embed a pod template.
+	podAffinityPath = "spec.template.spec.affinity"
+)
+
+// injectAffinity merges the supplied affinity into the pod template of the
+// supplied workload. It returns false if there is an affinity to inject but
+// the workload has no pod template.
+func injectAffinity(w *unstructured.Unstructured, a *corev1.Affinity) (bool, error) {
+	if a == nil {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+
+	existing := &corev1.Affinity{}
+	if v, err := p.GetValue(podAffinityPath); err == nil {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false, errors.New(errParseAffinity)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, existing); err != nil {
+			return false, errors.Wrap(err, errParseAffinity)
+		}
+	}
+
+	merged, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mergeAffinity(existing, a))
+	if err != nil {
+		return false, errors.Wrap(err, errConvertAffinity)
+	}
+	return true, p.SetValue(podAffinityPath, merged)
+}
+
+// mergeAffinity returns an affinity that satisfies both of the supplied
+// affinities. Scheduling terms are combined so that pods must satisfy the
+// required terms of both, and are weighed by the preferred terms of both.
+func mergeAffinity(a, b *corev1.Affinity) *corev1.Affinity {
+	out := a.DeepCopy()
+
+	if b.NodeAffinity != nil {
+		if out.NodeAffinity == nil {
+			out.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		na := out.NodeAffinity
+		na.RequiredDuringSchedulingIgnoredDuringExecution = mergeNodeSelectors(na.RequiredDuringSchedulingIgnoredDuringExecution, b.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		for _, t := range b.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			na.PreferredDuringSchedulingIgnoredDuringExecution = append(na.PreferredDuringSchedulingIgnoredDuringExecution, *t.DeepCopy())
+		}
+	}
+
+	if b.PodAffinity != nil {
+		if out.PodAffinity == nil {
+			out.PodAffinity = &corev1.PodAffinity{}
+		}
+		pa := out.PodAffinity
+		for _, t := range b.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			pa.RequiredDuringSchedulingIgnoredDuringExecution = append(pa.RequiredDuringSchedulingIgnoredDuringExecution, *t.DeepCopy())
+		}
+		for _, t := range b.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			pa.PreferredDuringSchedulingIgnoredDuringExecution = append(pa.PreferredDuringSchedulingIgnoredDuringExecution, *t.DeepCopy())
+		}
+	}
+
+	if b.PodAntiAffinity != nil {
+		if out.PodAntiAffinity == nil {
+			out.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		paa := out.PodAntiAffinity
+		for _, t := range b.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			paa.RequiredDuringSchedulingIgnoredDuringExecution = append(paa.RequiredDuringSchedulingIgnoredDuringExecution, *t.DeepCopy())
+		}
+		for _, t := range b.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			paa.PreferredDuringSchedulingIgnoredDuringExecution = append(paa.PreferredDuringSchedulingIgnoredDuringExecution, *t.DeepCopy())
+		}
+	}
+
+	return out
+}
+
+// mergeNodeSelectors returns a node selector that selects the nodes selected
+// by both of the supplied node selectors. The terms of a node selector are
+// ORed, so each term of one is combined with each term of the other.
+func mergeNodeSelectors(a, b *corev1.NodeSelector) *corev1.NodeSelector {
+	if b == nil {
+		return a
+	}
+	if a == nil {
+		return b.DeepCopy()
+	}
+	out := &corev1.NodeSelector{NodeSelectorTerms: make([]corev1.NodeSelectorTerm, 0, len(a.NodeSelectorTerms)*len(b.NodeSelectorTerms))}
+	for _, ta := range a.NodeSelectorTerms {
+		for _, tb := range b.NodeSelectorTerms {
+			t := corev1.NodeSelectorTerm{}
+			for _, r := range append(append([]corev1.NodeSelectorRequirement{}, ta.MatchExpressions...), tb.MatchExpressions...) {
+				t.MatchExpressions = append(t.MatchExpressions, *r.DeepCopy())
+			}
+			for _, r := range append(append([]corev1.NodeSelectorRequirement{}, ta.MatchFields...), tb.MatchFields...) {
+				t.MatchFields = append(t.MatchFields, *r.DeepCopy())
+			}
+			out.NodeSelectorTerms = append(out.NodeSelectorTerms, t)
+		}
+	}
+	return out
+}
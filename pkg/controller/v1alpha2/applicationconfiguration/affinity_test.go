@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+func TestInjectAffinity(t *testing.T) {
+	zone := func(zones ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: zones}
+	}
+	ssd := corev1.NodeSelectorRequirement{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}}
+	colocate := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cache"}},
+		TopologyKey:   "kubernetes.io/hostname",
+	}
+	nodes := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	workload := func(a *corev1.Affinity) *unstructured.Unstructured {
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(podSpecPath, map[string]interface{}{"containers": []interface{}{}})
+		if a != nil {
+			m, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(a)
+			_ = p.SetValue(podAffinityPath, m)
+		}
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+	noPodTemplate := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{}}}}
+	}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason   string
+		w        *unstructured.Unstructured
+		affinity *corev1.Affinity
+		want     want
+	}{
+		"NoAffinity": {
+			reason: "A workload should be unchanged when no affinity is supplied",
+			w:      noPodTemplate(),
+			want:   want{w: noPodTemplate(), injected: true},
+		},
+		"NoExistingAffinity": {
+			reason:   "The supplied affinity should be set on a pod template without one",
+			w:        workload(nil),
+			affinity: nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a")}}),
+			want: want{
+				w:        workload(nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a")}})),
+				injected: true,
+			},
+		},
+		"MergedNodeAffinity": {
+			reason: "Required node selector terms of both affinities should be satisfied",
+			w: workload(nodes(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a")}},
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("b")}},
+			)),
+			affinity: nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{ssd}}),
+			want: want{
+				w: workload(nodes(
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a"), ssd}},
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("b"), ssd}},
+				)),
+				injected: true,
+			},
+		},
+		"MergedPodAffinity": {
+			reason:   "Pod affinity terms should be added to the existing affinity",
+			w:        workload(nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a")}})),
+			affinity: &corev1.Affinity{PodAffinity: &corev1.PodAffinity{RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{colocate}}},
+			want: want{
+				w: workload(&corev1.Affinity{
+					NodeAffinity: nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zone("a")}}).NodeAffinity,
+					PodAffinity:  &corev1.PodAffinity{RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{colocate}},
+				}),
+				injected: true,
+			},
+		},
+		"NoPodTemplate": {
+			reason:   "A workload without a pod template should be unchanged, and reported as such",
+			w:        noPodTemplate(),
+			affinity: nodes(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{ssd}}),
+			want:     want{w: noPodTemplate(), injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectAffinity(tc.w, tc.affinity)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectAffinity(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectAffinity(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
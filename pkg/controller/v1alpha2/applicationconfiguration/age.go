@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Encryption error strings.
+const (
+	errNoEncryptor          = "parameter value is encrypted, but no encryptor is configured"
+	errFmtReadIdentity      = "cannot read age identity %q"
+	errFmtParseRecipient    = "cannot parse age recipient %q"
+	errEncryptAge           = "cannot encrypt with age"
+	errDecryptAge           = "cannot decrypt with age"
+	errFmtDecodeEncrypted   = "cannot decode encrypted parameter %q"
+	errFmtEncryptPV         = "cannot encrypt parameter %q of component %q"
+	errFmtGetAgeKeySecret   = "cannot get age key secret %q"
+	errFmtNoAgeKeySecretKey = "age key secret %q has no key %q"
+)
+
+// ageValuePrefix prefixes parameter values that were encrypted using age. The
+// rest of the value is the base64 encoded age ciphertext.
+const ageValuePrefix = "age:"
+
+// A SpecEncryptor encrypts sensitive parameter values for a public key, and
+// decrypts them using the corresponding private key.
+type SpecEncryptor interface {
+	// Encrypt the supplied plaintext for the supplied public key.
+	Encrypt(ctx context.Context, recipient string, plaintext []byte) ([]byte, error)
+
+	// Decrypt the supplied ciphertext.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// An AgeEncryptor encrypts parameter values using age, e.g. for a key pair
+// generated with:
+//
+//	age-keygen -o identity.txt
+//
+// Only the controller holds the identity, i.e. the private key, so only the
+// controller can decrypt.
+type AgeEncryptor struct {
+	identities []age.Identity
+}
+
+// NewAgeEncryptor returns a SpecEncryptor that decrypts values using the
+// supplied age identity file.
+func NewAgeEncryptor(identity string) (*AgeEncryptor, error) {
+	f, err := os.Open(identity) // #nosec G304 -- the identity is supplied by the operator of the controller.
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadIdentity, identity)
+	}
+	defer f.Close() // nolint:errcheck
+	ids, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadIdentity, identity)
+	}
+	return &AgeEncryptor{identities: ids}, nil
+}
+
+// Encrypt the supplied plaintext for the supplied age public key.
+func (e *AgeEncryptor) Encrypt(_ context.Context, recipient string, plaintext []byte) ([]byte, error) {
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseRecipient, recipient)
+	}
+	out := &bytes.Buffer{}
+	w, err := age.Encrypt(out, r)
+	if err != nil {
+		return nil, errors.Wrap(err, errEncryptAge)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, errors.Wrap(err, errEncryptAge)
+	}
+	// The final chunk of ciphertext is written on close.
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, errEncryptAge)
+	}
+	return out.Bytes(), nil
+}
+
+// Decrypt the supplied age ciphertext.
+func (e *AgeEncryptor) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), e.identities...)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecryptAge)
+	}
+	out, err := ioutil.ReadAll(r)
+	return out, errors.Wrap(err, errDecryptAge)
+}
+
+// isAgeEncrypted returns true if the supplied parameter value was encrypted
+// using age.
+func isAgeEncrypted(v intstr.IntOrString) bool {
+	return v.Type == intstr.String && strings.HasPrefix(v.StrVal, ageValuePrefix)
+}
+
+// EncryptSensitiveParameterValues encrypts the string parameter values of the
+// supplied ApplicationConfiguration for the age public key referenced by its
+// spec.encryption, if it is annotated as sensitive. Values that are already
+// encrypted are left unchanged. It returns true if any values were encrypted.
+func EncryptSensitiveParameterValues(ctx context.Context, c client.Reader, e SpecEncryptor, ac *v1alpha2.ApplicationConfiguration) (bool, error) {
+	if ac.Spec.Encryption == nil || ac.GetAnnotations()[oam.AnnotationSensitive] != "true" {
+		return false, nil
+	}
+
+	var recipient string
+	encrypted := false
+	for i := range ac.Spec.Components {
+		acc := &ac.Spec.Components[i]
+		for j := range acc.ParameterValues {
+			pv := &acc.ParameterValues[j]
+			if pv.Value.Type != intstr.String || isAgeEncrypted(pv.Value) {
+				continue
+			}
+			if recipient == "" {
+				r, err := ageRecipient(ctx, c, ac)
+				if err != nil {
+					return false, err
+				}
+				recipient = r
+			}
+			ct, err := e.Encrypt(ctx, recipient, []byte(pv.Value.StrVal))
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtEncryptPV, pv.Name, acc.ComponentName)
+			}
+			pv.Value = intstr.FromString(ageValuePrefix + base64.StdEncoding.EncodeToString(ct))
+			encrypted = true
+		}
+	}
+	return encrypted, nil
+}
+
+// ageRecipient returns the age public key referenced by the spec.encryption of
+// the supplied ApplicationConfiguration.
+func ageRecipient(ctx context.Context, c client.Reader, ac *v1alpha2.ApplicationConfiguration) (string, error) {
+	ref := ac.Spec.Encryption.AgeKeySecretRef
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return "", errors.Wrapf(err, errFmtGetAgeKeySecret, ref.Name)
+	}
+	key, ok := s.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf(errFmtNoAgeKeySecretKey, ref.Name, ref.Key)
+	}
+	return strings.TrimSpace(string(key)), nil
+}
+
+// decryptSensitiveParameterValues returns a copy of the supplied parameter
+// values with any values that were encrypted using age replaced by their
+// plaintext. Values that are not encrypted are returned unchanged.
+func decryptSensitiveParameterValues(ctx context.Context, e SpecEncryptor, pv []v1alpha2.ComponentParameterValue) ([]v1alpha2.ComponentParameterValue, error) {
+	out := make([]v1alpha2.ComponentParameterValue, len(pv))
+	for i, v := range pv {
+		out[i] = v
+		if !isAgeEncrypted(v.Value) {
+			continue
+		}
+		if e == nil {
+			return nil, errors.Wrapf(errors.New(errNoEncryptor), errFmtDecryptPV, v.Name)
+		}
+		ct, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v.Value.StrVal, ageValuePrefix))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeEncrypted, v.Name)
+		}
+		plain, err := e.Decrypt(ctx, ct)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecryptPV, v.Name)
+		}
+		out[i].Value = intstr.FromString(string(plain))
+	}
+	return out, nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// A fakeEncryptor "encrypts" values by prefixing them with their recipient.
+type fakeEncryptor struct{ err error }
+
+func (e fakeEncryptor) Encrypt(_ context.Context, recipient string, plaintext []byte) ([]byte, error) {
+	return []byte(recipient + "/" + string(plaintext)), e.err
+}
+
+func (e fakeEncryptor) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return []byte(strings.SplitN(string(ciphertext), "/", 2)[1]), e.err
+}
+
+func encrypted(recipient, plaintext string) intstr.IntOrString {
+	return intstr.FromString(ageValuePrefix + base64.StdEncoding.EncodeToString([]byte(recipient+"/"+plaintext)))
+}
+
+func TestAgeEncryptor(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "oam-age-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+	path := filepath.Join(dir, "identity.txt")
+	if err := ioutil.WriteFile(path, []byte("# public key: "+id.Recipient().String()+"\n"+id.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := NewAgeEncryptor(path)
+	if err != nil {
+		t.Fatalf("NewAgeEncryptor(...): %s", err)
+	}
+	ct, err := e.Encrypt(context.Background(), id.Recipient().String(), []byte("secret"))
+	if err != nil {
+		t.Fatalf("e.Encrypt(...): %s", err)
+	}
+	if strings.Contains(string(ct), "secret") {
+		t.Errorf("e.Encrypt(...): ciphertext contains the plaintext")
+	}
+	plain, err := e.Decrypt(context.Background(), ct)
+	if err != nil {
+		t.Fatalf("e.Decrypt(...): %s", err)
+	}
+	if diff := cmp.Diff("secret", string(plain)); diff != "" {
+		t.Errorf("e.Decrypt(...): -want, +got:\n%s", diff)
+	}
+
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err = e.Encrypt(context.Background(), other.Recipient().String(), []byte("secret"))
+	if err != nil {
+		t.Fatalf("e.Encrypt(...): %s", err)
+	}
+	if _, err := e.Decrypt(context.Background(), ct); err == nil {
+		t.Errorf("e.Decrypt(...): want error decrypting a value encrypted for another recipient")
+	}
+}
+
+func TestEncryptSensitiveParameterValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := v1alpha2.SecretKeySelector{Name: "age", Key: "public"}
+	secret := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"public": []byte("age1cool\n")}
+		return nil
+	})
+	ac := func(sensitive bool, values ...v1alpha2.ComponentParameterValue) *v1alpha2.ApplicationConfiguration {
+		ac := &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"},
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Encryption: &v1alpha2.SpecEncryption{AgeKeySecretRef: ref},
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "db", ParameterValues: values}},
+			},
+		}
+		if sensitive {
+			ac.SetAnnotations(map[string]string{oam.AnnotationSensitive: "true"})
+		}
+		return ac
+	}
+
+	type want struct {
+		ac        *v1alpha2.ApplicationConfiguration
+		encrypted bool
+		err       error
+	}
+	cases := map[string]struct {
+		reason    string
+		get       test.MockGetFn
+		encryptor SpecEncryptor
+		ac        *v1alpha2.ApplicationConfiguration
+		want      want
+	}{
+		"NotSensitive": {
+			reason:    "Parameter values of ApplicationConfigurations that are not annotated as sensitive should not be encrypted",
+			encryptor: fakeEncryptor{},
+			ac:        ac(false, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")}),
+			want:      want{ac: ac(false, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")})},
+		},
+		"Encrypted": {
+			reason:    "String parameter values should be encrypted for the referenced public key, leaving other values unchanged",
+			get:       secret,
+			encryptor: fakeEncryptor{},
+			ac: ac(true,
+				v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")},
+				v1alpha2.ComponentParameterValue{Name: "port", Value: intstr.FromInt(5432)},
+				v1alpha2.ComponentParameterValue{Name: "user", Value: encrypted("age1cool", "admin")},
+			),
+			want: want{
+				ac: ac(true,
+					v1alpha2.ComponentParameterValue{Name: "password", Value: encrypted("age1cool", "hunter2")},
+					v1alpha2.ComponentParameterValue{Name: "port", Value: intstr.FromInt(5432)},
+					v1alpha2.ComponentParameterValue{Name: "user", Value: encrypted("age1cool", "admin")},
+				),
+				encrypted: true,
+			},
+		},
+		"GetSecretError": {
+			reason:    "Errors getting the public key should be returned",
+			get:       test.NewMockGetFn(errBoom),
+			encryptor: fakeEncryptor{},
+			ac:        ac(true, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")}),
+			want: want{
+				ac:  ac(true, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")}),
+				err: errors.Wrapf(errBoom, errFmtGetAgeKeySecret, "age"),
+			},
+		},
+		"EncryptError": {
+			reason:    "Errors encrypting parameter values should be returned",
+			get:       secret,
+			encryptor: fakeEncryptor{err: errBoom},
+			ac:        ac(true, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")}),
+			want: want{
+				ac:  ac(true, v1alpha2.ComponentParameterValue{Name: "password", Value: intstr.FromString("hunter2")}),
+				err: errors.Wrapf(errBoom, errFmtEncryptPV, "password", "db"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := EncryptSensitiveParameterValues(context.Background(), &test.MockClient{MockGet: tc.get}, tc.encryptor, tc.ac)
+			if diff := cmp.Diff(tc.want, want{ac: tc.ac, encrypted: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEncryptSensitiveParameterValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDecryptSensitiveParameterValues(t *testing.T) {
+	pv := []v1alpha2.ComponentParameterValue{
+		{Name: "password", Value: encrypted("age1cool", "hunter2")},
+		{Name: "port", Value: intstr.FromInt(5432)},
+	}
+
+	type want struct {
+		pv  []v1alpha2.ComponentParameterValue
+		err error
+	}
+	cases := map[string]struct {
+		reason    string
+		encryptor SpecEncryptor
+		want      want
+	}{
+		"Decrypted": {
+			reason:    "Encrypted parameter values should be replaced by their plaintext",
+			encryptor: fakeEncryptor{},
+			want: want{pv: []v1alpha2.ComponentParameterValue{
+				{Name: "password", Value: intstr.FromString("hunter2")},
+				{Name: "port", Value: intstr.FromInt(5432)},
+			}},
+		},
+		"NoEncryptor": {
+			reason: "Encrypted parameter values cannot be decrypted without an encryptor",
+			want:   want{err: errors.Wrapf(errors.New(errNoEncryptor), errFmtDecryptPV, "password")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := decryptSensitiveParameterValues(context.Background(), tc.encryptor, pv)
+			if diff := cmp.Diff(tc.want, want{pv: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndecryptSensitiveParameterValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
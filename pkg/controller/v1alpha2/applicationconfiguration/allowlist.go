@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Workload kind allowlist error strings.
+const (
+	errFmtInvalidWorkloadKind      = "invalid workload kind %q: must be of the form group/version/kind, or version/kind for the core group"
+	errFmtUnauthorizedWorkloadKind = "workload kind %s of component %q is not allowed"
+)
+
+// ParseWorkloadKinds parses a comma separated list of workload kinds of the
+// form group/version/kind, e.g. apps/v1/Deployment. Kinds in the core group
+// may be specified as version/kind, e.g. v1/Pod.
+func ParseWorkloadKinds(s string) ([]schema.GroupVersionKind, error) {
+	kinds := make([]schema.GroupVersionKind, 0)
+	for _, k := range strings.Split(s, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		parts := strings.Split(k, "/")
+		switch {
+		case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+			kinds = append(kinds, schema.GroupVersionKind{Version: parts[0], Kind: parts[1]})
+		case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+			kinds = append(kinds, schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]})
+		default:
+			return nil, errors.Errorf(errFmtInvalidWorkloadKind, k)
+		}
+	}
+	return kinds, nil
+}
+
+// authorized returns the supplied workloads whose kinds are allowed, and a
+// message for each workload whose kind is not. All kinds are allowed if the
+// Reconciler has no allowed workload kinds.
+func (r *Reconciler) authorized(w []Workload) ([]Workload, []string) {
+	if len(r.allowedKinds) == 0 {
+		return w, nil
+	}
+	allowed := make([]Workload, 0, len(w))
+	msgs := make([]string, 0)
+	for _, wl := range w {
+		gvk := wl.Workload.GroupVersionKind()
+		if r.allowedKinds[gvk] {
+			allowed = append(allowed, wl)
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf(errFmtUnauthorizedWorkloadKind, gvk.GroupVersion().String()+"/"+gvk.Kind, wl.ComponentName))
+	}
+	return allowed, msgs
+}
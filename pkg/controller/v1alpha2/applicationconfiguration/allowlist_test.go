@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseWorkloadKinds(t *testing.T) {
+	type want struct {
+		kinds []schema.GroupVersionKind
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty string should allow all kinds",
+			s:      "",
+			want:   want{kinds: []schema.GroupVersionKind{}},
+		},
+		"Valid": {
+			reason: "Kinds in named and core groups should be parsed",
+			s:      "apps/v1/Deployment, v1/Pod,",
+			want: want{kinds: []schema.GroupVersionKind{
+				{Group: "apps", Version: "v1", Kind: "Deployment"},
+				{Version: "v1", Kind: "Pod"},
+			}},
+		},
+		"Invalid": {
+			reason: "Kinds that are not of the form group/version/kind should be rejected",
+			s:      "apps/v1/Deployment,Deployment",
+			want:   want{err: errors.Errorf(errFmtInvalidWorkloadKind, "Deployment")},
+		},
+		"EmptyPart": {
+			reason: "Kinds with empty parts should be rejected",
+			s:      "apps//Deployment",
+			want:   want{err: errors.Errorf(errFmtInvalidWorkloadKind, "apps//Deployment")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseWorkloadKinds(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseWorkloadKinds(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kinds, got); diff != "" {
+				t.Errorf("\n%s\nParseWorkloadKinds(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+
+	w := []Workload{
+		{ComponentName: "deployment", Workload: deployment},
+		{ComponentName: "pod", Workload: pod},
+	}
+
+	type want struct {
+		w    []Workload
+		msgs []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []ReconcilerOption
+		want   want
+	}{
+		"NoAllowlist": {
+			reason: "All kinds should be allowed if no kinds are specified",
+			want:   want{w: w},
+		},
+		"EmptyAllowlist": {
+			reason: "All kinds should be allowed if an empty allowlist is specified",
+			o:      []ReconcilerOption{WithAllowedWorkloadKinds()},
+			want:   want{w: w},
+		},
+		"Unauthorized": {
+			reason: "Workloads of kinds that are not allowed should be omitted",
+			o:      []ReconcilerOption{WithAllowedWorkloadKinds(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})},
+			want: want{
+				w:    w[:1],
+				msgs: []string{fmt.Sprintf(errFmtUnauthorizedWorkloadKind, "v1/Pod", "pod")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{}
+			for _, o := range tc.o {
+				o(r)
+			}
+			got, msgs := r.authorized(w)
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\n%s\nr.authorized(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msgs, msgs); diff != "" {
+				t.Errorf("\n%s\nr.authorized(...): -want messages, +got messages:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotation preservation error strings.
+const (
+	errGetLiveObject     = "cannot get live object to preserve its annotations"
+	errParseManagedField = "cannot parse managed fields of live object"
+)
+
+// DefaultFieldManager returns the field manager that the API server records
+// for requests made by the OAM runtime. Requests that don't specify a field
+// manager are recorded under the prefix of their user agent.
+func DefaultFieldManager() string {
+	return strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+}
+
+// An AnnotationPreservationApplicator preserves the annotations of a live
+// object that were set by other controllers or users, e.g.
+// kubectl.kubernetes.io/last-applied-configuration, by merging them into the
+// desired object before it is applied. Annotations are considered to be owned
+// by the OAM runtime if its field manager manages them. Annotations the OAM
+// runtime owns are not preserved, so that they may be removed.
+type AnnotationPreservationApplicator struct {
+	client  client.Reader
+	wrapped resource.Applicator
+	manager string
+}
+
+// NewAnnotationPreservationApplicator returns an applicator that preserves the
+// annotations of live objects that aren't owned by the supplied field manager
+// before applying objects using the supplied applicator.
+func NewAnnotationPreservationApplicator(c client.Reader, a resource.Applicator, manager string) *AnnotationPreservationApplicator {
+	return &AnnotationPreservationApplicator{client: c, wrapped: a, manager: manager}
+}
+
+// Apply the supplied object, which must be unstructured.
+func (a *AnnotationPreservationApplicator) Apply(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+	desired, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New(errNotUnstructured)
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(desired.GroupVersionKind())
+	err := a.client.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, live)
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetLiveObject)
+	}
+	if err == nil {
+		if err := a.preserve(live, desired); err != nil {
+			return err
+		}
+	}
+	return a.wrapped.Apply(ctx, desired, ao...)
+}
+
+// preserve merges the annotations of the live object that are not owned by
+// the field manager into the desired object. Annotations of the desired
+// object take precedence.
+func (a *AnnotationPreservationApplicator) preserve(live, desired *unstructured.Unstructured) error {
+	annotations := live.GetAnnotations()
+	if len(annotations) == 0 {
+		return nil
+	}
+	owned, err := ownedAnnotations(live.GetManagedFields(), a.manager)
+	if err != nil {
+		return errors.Wrap(err, errParseManagedField)
+	}
+	merged := desired.GetAnnotations()
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	var changed bool
+	for k, v := range annotations {
+		if _, ok := merged[k]; ok || owned[k] {
+			continue
+		}
+		merged[k] = v
+		changed = true
+	}
+	if changed {
+		desired.SetAnnotations(merged)
+	}
+	return nil
+}
+
+// ownedAnnotations returns the keys of the annotations managed by the supplied
+// field manager.
+func ownedAnnotations(fields []metav1.ManagedFieldsEntry, manager string) (map[string]bool, error) {
+	owned := make(map[string]bool)
+	for _, f := range fields {
+		if f.Manager != manager || f.FieldsV1 == nil {
+			continue
+		}
+		set := map[string]map[string]map[string]interface{}{}
+		if err := json.Unmarshal(f.FieldsV1.Raw, &set); err != nil {
+			return nil, err
+		}
+		for k := range set["f:metadata"]["f:annotations"] {
+			owned[strings.TrimPrefix(k, "f:")] = true
+		}
+	}
+	return owned, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAnnotationPreservationApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// Managed fields that are valid JSON, but not a field set.
+	malformed := `{"f:metadata":"v"}`
+	errMalformed := json.Unmarshal([]byte(malformed), &map[string]map[string]map[string]interface{}{})
+
+	desired := func(annotations map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind("Workload")
+		u.SetNamespace("ns")
+		u.SetName("workload")
+		u.SetAnnotations(annotations)
+		return u
+	}
+	live := func(annotations map[string]string, fields ...metav1.ManagedFieldsEntry) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			u.SetAnnotations(annotations)
+			u.SetManagedFields(fields)
+			return nil
+		}
+	}
+	managed := func(manager, raw string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: manager, FieldsV1: &metav1.FieldsV1{Raw: []byte(raw)}}
+	}
+
+	type want struct {
+		annotations map[string]string
+		err         error
+	}
+	cases := map[string]struct {
+		reason  string
+		get     test.MockGetFn
+		desired *unstructured.Unstructured
+		want    want
+	}{
+		"NotFound": {
+			reason:  "Objects that don't exist yet should be applied as is",
+			get:     test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			desired: desired(map[string]string{"oam": "v"}),
+			want:    want{annotations: map[string]string{"oam": "v"}},
+		},
+		"GetError": {
+			reason:  "Errors getting the live object should be returned",
+			get:     test.NewMockGetFn(errBoom),
+			desired: desired(nil),
+			want:    want{err: errors.Wrap(errBoom, errGetLiveObject)},
+		},
+		"PreserveForeignAnnotations": {
+			reason: "Annotations of the live object that aren't owned by the OAM runtime should be preserved",
+			get: test.NewMockGetFn(nil, live(
+				map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}", "oam": "old"},
+				managed("oam", `{"f:metadata":{"f:annotations":{"f:oam":{}}}}`),
+				managed("kubectl", `{"f:metadata":{"f:annotations":{"f:kubectl.kubernetes.io/last-applied-configuration":{}}}}`),
+			)),
+			desired: desired(map[string]string{"oam": "new"}),
+			want: want{annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"oam": "new",
+			}},
+		},
+		"DropOwnedAnnotations": {
+			reason: "Annotations owned by the OAM runtime that it no longer applies should not be preserved",
+			get: test.NewMockGetFn(nil, live(
+				map[string]string{"removed": "v"},
+				managed("oam", `{"f:metadata":{"f:annotations":{"f:removed":{}}},"f:spec":{"f:replicas":{}}}`),
+			)),
+			desired: desired(nil),
+			want:    want{},
+		},
+		"ManagedFieldsError": {
+			reason: "Errors parsing the managed fields of the live object should be returned",
+			get: test.NewMockGetFn(nil, live(
+				map[string]string{"other": "v"},
+				managed("oam", malformed),
+			)),
+			desired: desired(nil),
+			want:    want{err: errors.Wrap(errMalformed, errParseManagedField)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied *unstructured.Unstructured
+			wrapped := resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				applied = o.(*unstructured.Unstructured)
+				return nil
+			})
+			a := NewAnnotationPreservationApplicator(&test.MockClient{MockGet: tc.get}, wrapped, "oam")
+			err := a.Apply(context.Background(), tc.desired)
+			got := want{err: err}
+			if applied != nil {
+				got.annotations = applied.GetAnnotations()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\na.Apply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,15 +18,27 @@ package applicationconfiguration
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientappv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -37,6 +49,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/tracing"
 )
 
 const (
@@ -52,6 +66,24 @@ const (
 	errRenderComponents      = "cannot render components"
 	errApplyComponents       = "cannot apply components"
 	errGCComponent           = "cannot garbage collect components"
+	errApplyNamespaces       = "cannot apply components to selected namespaces"
+	errHashComponents        = "cannot compute hash of rendered components"
+	errCheckDrift            = "cannot check applied components for drift"
+	errPruneWorkloadStatus   = "cannot prune orphaned workload statuses"
+	errRolloutGroups         = "cannot roll out component groups"
+	errOrderDependencies     = "cannot order components by their dependencies"
+	errPruneHistory          = "cannot prune component revision history"
+	errConnectTargetCluster  = "cannot connect to target cluster"
+	errAddHealthPoller       = "cannot add workload health poller to manager"
+	errResolveSpecSource     = "cannot resolve spec source"
+	errPruneComponents       = "cannot prune removed components"
+	errPreApplyHook          = "pre-apply hook failed"
+	errPodSecurity           = "security contexts violate PodSecurity"
+	errComputeStatusPatch    = "cannot compute status patch"
+	errManageNamespace       = "cannot manage namespace"
+	errAcquireLease          = "cannot acquire application configuration lease"
+	errAddPartitioner        = "cannot add lease partitioner to manager"
+	errSetupCanaryWeights    = "cannot setup canary weight controller"
 )
 
 // Reconcile event reasons.
@@ -59,27 +91,121 @@ const (
 	reasonRenderComponents = "RenderedComponents"
 	reasonApplyComponents  = "AppliedComponents"
 	reasonGGComponent      = "GarbageCollectedComponent"
+	reasonPruneComponent   = "PrunedComponent"
+	reasonHoldComponents   = "WaitingForComponentGroups"
 
 	reasonCannotRenderComponents = "CannotRenderComponents"
 	reasonCannotApplyComponents  = "CannotApplyComponents"
 	reasonCannotGGComponents     = "CannotGarbageCollectComponents"
+	reasonCannotPruneComponents  = "CannotPruneComponents"
+	reasonPreApplyHookFailed     = "PreApplyHookFailed"
+	reasonCannotApplyNamespaces  = "CannotApplyComponentsToNamespaces"
+	reasonUnauthorizedWorkloads  = "UnauthorizedWorkloadKinds"
+	reasonCannotPruneHistory     = "CannotPruneRevisionHistory"
+	reasonCannotConnectCluster   = "CannotConnectToTargetCluster"
+	reasonUnsupportedEnv         = "UnsupportedEnv"
+	reasonUnsupportedAffinity    = "UnsupportedAffinity"
+	reasonUnsupportedTolerations = "UnsupportedTolerations"
+	reasonUnsupportedPriority    = "UnsupportedPriorityClass"
+	reasonUnsupportedNodeSel     = "UnsupportedNodeSelector"
+	reasonUnsupportedHostNet     = "UnsupportedHostNetwork"
+	reasonUnsupportedLiveness    = "UnsupportedLivenessProbe"
+	reasonUnsupportedPDB         = "UnsupportedPodDisruptionBudget"
+	reasonUnsupportedSvcAcct     = "UnsupportedServiceAccount"
+	reasonUnsupportedReadiness   = "UnsupportedReadinessProbe"
+	reasonUnsupportedSecCtx      = "UnsupportedSecurityContext"
+	reasonUnsupportedTraffic     = "UnsupportedTrafficWeight"
+	reasonNameTemplateError      = "NameTemplateError"
+	reasonDependencyCycle        = "DependencyCycle"
+	reasonRollback               = "RolledBackComponents"
+	reasonCannotRollback         = "CannotRollBackComponents"
+	reasonConfigMapNotFound      = "ConfigMapNotFound"
+	reasonPriorityClassNotFound  = "PriorityClassNotFound"
+	reasonServiceAccountNotFound = "ServiceAccountNotFound"
+	reasonPodSecurityViolation   = "PodSecurityViolation"
+	reasonCannotGCRevisions      = "CannotGarbageCollectRevisions"
+	reasonSpecValidationFailed   = "SpecValidationFailed"
+	reasonCannotResolveSource    = "CannotResolveSpecSource"
+	reasonComponentSuspended     = "ComponentSuspended"
+	reasonCannotManageNamespace  = "CannotManageNamespace"
+	reasonNamespaceUnmanaged     = "NamespaceLabelsIgnored"
+	reasonCannotRecordEvents     = "CannotRecordComponentEvents"
+	reasonScopeIncompatible      = "ScopeIncompatible"
+	reasonWorkloadsDrifted       = "WorkloadsDrifted"
+	reasonRemediatedDrift        = "RemediatedDrift"
 )
 
 // Setup adds a controller that reconciles ApplicationConfigurations.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o ...ReconcilerOption) error {
 	name := "oam/" + strings.ToLower(v1alpha2.ApplicationConfigurationGroupKind)
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		For(&v1alpha2.ApplicationConfiguration{}).
+	tdc := NewTraitDefinitionCache(mgr.GetClient(), DefaultTraitDefinitionTTL)
+	npd := NewNamespaceParameterDefaultsCache(mgr.GetClient(), l.WithValues("controller", name))
+	o = append([]ReconcilerOption{
+		WithLogger(l.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithTraitDefinitionCache(tdc),
+		WithNamespaceParameterDefaults(npd),
+	}, o...)
+
+	r := NewReconciler(mgr, o...)
+
+	// The controllers that reconcile partitioned ApplicationConfigurations run
+	// on every replica, regardless of leader election.
+	if r.partitioner != nil {
+		mgr = partitionedManager{Manager: mgr}
+	}
+	if err := mgr.Add(r.poller); err != nil {
+		return errors.Wrap(err, errAddHealthPoller)
+	}
+
+	// Changes to the canary weight alone are handled by the canary weight
+	// controller, without rendering the ApplicationConfiguration.
+	canary := []predicate.Predicate{canaryWeightChanged()}
+	appConfigs := []predicate.Predicate{ignoreCanaryWeightChanges()}
+	b := ctrl.NewControllerManagedBy(mgr).Named(name)
+	if r.partitioner != nil {
+		if err := mgr.Add(r.partitioner); err != nil {
+			return errors.Wrap(err, errAddPartitioner)
+		}
+		canary = append(canary, LeaseAwarePredicate(r.partitioner))
+		appConfigs = append(appConfigs, LeaseAwarePredicate(r.partitioner))
+		b = b.Watches(&source.Channel{Source: r.partitioner.events}, &handler.EnqueueRequestForObject{})
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named(name+"-canary-weight").
+		For(&v1alpha2.ApplicationConfiguration{}, builder.WithPredicates(canary...)).
+		Complete(&canaryWeightReconciler{
+			client:      mgr.GetClient(),
+			partitioner: r.partitioner,
+			log:         l.WithValues("controller", name+"-canary-weight"),
+		})
+	if err != nil {
+		return errors.Wrap(err, errSetupCanaryWeights)
+	}
+
+	return b.
+		For(&v1alpha2.ApplicationConfiguration{}, builder.WithPredicates(appConfigs...)).
 		Watches(&source.Kind{Type: &v1alpha2.Component{}}, &ComponentHandler{
 			client:     mgr.GetClient(),
 			l:          l,
 			appsClient: clientappv1.NewForConfigOrDie(mgr.GetConfig()),
 		}).
-		Complete(NewReconciler(mgr,
-			WithLogger(l.WithValues("controller", name)),
-			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+		Watches(&source.Kind{Type: &v1alpha2.TraitDefinition{}}, tdc).
+		Watches(&source.Kind{Type: &v1alpha2.NamespaceParameterDefaults{}}, npd).
+		Watches(&source.Kind{Type: &v1alpha2.HealthScope{}}, &HealthScopeHandler{client: mgr.GetClient(), log: l}).
+		Watches(&source.Kind{Type: &v1alpha2.ComponentEvent{}}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &v1alpha2.ApplicationConfiguration{},
+			IsController: true,
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &kubeconfigSecretMapper{client: mgr.GetClient(), log: l},
+		}, builder.WithPredicates(labelSelected(kubeconfigSecrets))).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &specSourceConfigMapMapper{client: mgr.GetClient(), log: l},
+		}, builder.WithPredicates(labelSelected(specSourceConfigMaps), predicate.ResourceVersionChangedPredicate{})).
+		Complete(r)
 }
 
 // A Reconciler reconciles OAM ApplicationConfigurations by rendering and
@@ -89,6 +215,59 @@ type Reconciler struct {
 	components ComponentRenderer
 	workloads  WorkloadApplicator
 	gc         GarbageCollector
+	pruner     ComponentPruner
+	health     HealthProber
+	rollouts   HealthChecker
+	hook       PreApplyHookCaller
+	specs      SpecValidator
+	conditions ConditionDeduplicator
+
+	// traitAppliers manage the lifecycle of the kinds of trait that need
+	// more than a plain apply and delete.
+	traitAppliers *TraitApplierRegistry
+
+	// allowedKinds of workload. All kinds are allowed if it is empty.
+	allowedKinds map[schema.GroupVersionKind]bool
+
+	// maxConcurrentGroups that may be rolled out at once.
+	maxConcurrentGroups int
+
+	// tracer traces reconciles.
+	tracer trace.Tracer
+
+	// manageNamespaces allows the namespaces of ApplicationConfigurations to
+	// be created and labelled per their NamespaceLabels.
+	manageNamespaces bool
+
+	// state transitions ApplicationConfigurations between states.
+	state *StateMachine
+
+	// clusters connects to the TargetCluster of ApplicationConfigurations.
+	clusters ClusterConnector
+
+	// poller polls the health of components with a status poll interval
+	// between reconciles.
+	poller *healthPoller
+
+	// appSets renders the components of ApplicationConfigurations whose spec
+	// source is an Argo CD ApplicationSet.
+	appSets *ArgoApplicationSetRenderer
+
+	// finalizers delete the workloads of deleted ApplicationConfigurations
+	// before their finalizer is removed. Workloads are instead garbage
+	// collected by their owner references if it is nil.
+	finalizers *FinalizerAwarePruner
+	finalizer  resource.Finalizer
+
+	// deletionTimeout after which the finalizer of a deleted
+	// ApplicationConfiguration is removed even though some of its workloads
+	// still exist. Workloads are not finalized if it is zero.
+	deletionTimeout time.Duration
+
+	// partitioner selects the ApplicationConfigurations this replica of the
+	// controller reconciles. All ApplicationConfigurations are reconciled if
+	// it is nil.
+	partitioner *LeasePartitioner
 
 	log    logging.Logger
 	record event.Recorder
@@ -111,6 +290,160 @@ func WithApplicator(a WorkloadApplicator) ReconcilerOption {
 	}
 }
 
+// WithSpecDecryptor specifies how the Reconciler should decrypt the encrypted
+// parameter values of ApplicationConfigurations annotated as encrypted. It has
+// no effect on a renderer supplied using WithRenderer.
+func WithSpecDecryptor(d SpecDecryptor) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.decryptor = d
+		}
+	}
+}
+
+// WithSpecEncryptor specifies how the Reconciler should decrypt the sensitive
+// parameter values of ApplicationConfigurations that specify an encryption.
+// It has no effect on a renderer supplied using WithRenderer.
+func WithSpecEncryptor(e SpecEncryptor) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.encryptor = e
+		}
+	}
+}
+
+// WithParameterResolver specifies how the Reconciler should resolve parameter
+// values that reference secrets held by an external secret manager.
+func WithParameterResolver(pr oam.ParameterResolver) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.secrets = pr
+		}
+	}
+}
+
+// WithLabelPropagation specifies which labels of an ApplicationConfiguration
+// the Reconciler should propagate to its workloads and traits. Labels are
+// filtered using ExcludeSystemLabels by default. It has no effect on a
+// renderer supplied using WithRenderer.
+func WithLabelPropagation(filter LabelFilter) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.labels = NewLabelPropagator(filter)
+		}
+	}
+}
+
+// WithOCISchematicFetcher specifies how the Reconciler should fetch workload
+// schematics that are stored as OCI artifacts. It has no effect on a renderer
+// supplied using WithRenderer.
+func WithOCISchematicFetcher(f OCISchematicFetcher) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.schematics = f
+		}
+	}
+}
+
+// WithKustomizeSchematicRenderer specifies how the Reconciler should render
+// workload schematics that are stored as kustomizations. It has no effect on
+// a renderer supplied using WithRenderer.
+func WithKustomizeSchematicRenderer(kr KustomizeSchematicRenderer) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if c, ok := rc.components.(*components); ok {
+			c.kustomize = kr
+		}
+	}
+}
+
+// WithAllowedWorkloadKinds specifies the kinds of workload the Reconciler is
+// allowed to apply. Workloads of other kinds are not applied. All kinds are
+// allowed if none are specified.
+func WithAllowedWorkloadKinds(k ...schema.GroupVersionKind) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.allowedKinds = make(map[schema.GroupVersionKind]bool, len(k))
+		for _, gvk := range k {
+			rc.allowedKinds[gvk] = true
+		}
+	}
+}
+
+// WithNamespaceCreation allows the Reconciler to create and label the
+// namespaces of ApplicationConfigurations that specify NamespaceLabels. Doing
+// so requires permission to get, create and patch namespaces.
+func WithNamespaceCreation() ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.manageNamespaces = true
+	}
+}
+
+// WithTraitDefinitionCache specifies how the Reconciler should cache the
+// TraitDefinitions of the traits it applies. It has no effect on an applicator
+// supplied using WithApplicator.
+func WithTraitDefinitionCache(c *TraitDefinitionCache) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if w, ok := rc.workloads.(*workloads); ok {
+			w.traitDefinitions = c
+		}
+	}
+}
+
+// WithNamespaceParameterDefaults specifies that the Reconciler should render
+// components with the default parameter values of the NamespaceParameterDefaults
+// in the namespace of their ApplicationConfiguration, as cached by the
+// supplied cache. It has no effect on a renderer supplied using WithRenderer.
+func WithNamespaceParameterDefaults(c *NamespaceParameterDefaultsCache) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if r, ok := rc.components.(*components); ok {
+			r.defaults = c
+		}
+	}
+}
+
+// WithTraitBatchSize specifies that the Reconciler should server-side apply
+// the traits of each workload in parallel batches, with up to the supplied
+// number of requests in flight at once. It has no effect on an applicator
+// supplied using WithApplicator.
+func WithTraitBatchSize(n int) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if w, ok := rc.workloads.(*workloads); ok {
+			w.batchSize = n
+		}
+	}
+}
+
+// WithMaxConcurrentGroups specifies how many component groups of an
+// ApplicationConfiguration the Reconciler may roll out at once. A group is
+// rolled out until all of its components are healthy.
+func WithMaxConcurrentGroups(n int) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.maxConcurrentGroups = n
+	}
+}
+
+// WithTracer specifies how the Reconciler should trace reconciles.
+func WithTracer(t trace.Tracer) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.tracer = t
+	}
+}
+
+// WithClusterConnector specifies how the Reconciler should connect to the
+// TargetCluster of an ApplicationConfiguration.
+func WithClusterConnector(c ClusterConnector) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.clusters = c
+	}
+}
+
+// WithStateMachine specifies how the Reconciler should transition
+// ApplicationConfigurations between states.
+func WithStateMachine(m *StateMachine) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.state = m
+	}
+}
+
 // WithGarbageCollector specifies how the Reconciler should garbage collect
 // workloads and traits when an ApplicationConfiguration is edited to remove
 // them.
@@ -120,6 +453,86 @@ func WithGarbageCollector(gc GarbageCollector) ReconcilerOption {
 	}
 }
 
+// WithComponentPruner specifies how the Reconciler should prune the resources
+// of components that are removed from an ApplicationConfiguration.
+func WithComponentPruner(p ComponentPruner) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.pruner = p
+	}
+}
+
+// WithPreApplyHookCaller specifies how the Reconciler should call the
+// PreApplyHook of an ApplicationConfiguration.
+func WithPreApplyHookCaller(c PreApplyHookCaller) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.hook = c
+	}
+}
+
+// WithSpecValidator specifies how the Reconciler should validate rendered
+// workloads against the schema of their kind.
+func WithSpecValidator(v SpecValidator) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.specs = v
+	}
+}
+
+// WithConditionDeduplicator specifies how the Reconciler should remove
+// duplicate conditions from the status of an ApplicationConfiguration before
+// writing it.
+func WithConditionDeduplicator(d ConditionDeduplicator) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.conditions = d
+	}
+}
+
+// WithHealthChecker specifies how the Reconciler should check the rollout of
+// live workloads.
+func WithHealthChecker(c HealthChecker) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.rollouts = c
+	}
+}
+
+// WithTraitApplier specifies how the Reconciler should apply and delete traits
+// of the supplied kind.
+func WithTraitApplier(gvk schema.GroupVersionKind, a TraitApplier) ReconcilerOption {
+	return func(rc *Reconciler) {
+		if rc.traitAppliers == nil {
+			rc.traitAppliers = NewTraitApplierRegistry()
+		}
+		rc.traitAppliers.Register(gvk, a)
+	}
+}
+
+// WithWorkloadFinalization specifies that the Reconciler should delete the
+// workloads of a deleted ApplicationConfiguration, and wait for them to be
+// fully deleted before allowing the ApplicationConfiguration to be deleted.
+// The Reconciler stops waiting once the ApplicationConfiguration has been
+// deleting for the supplied timeout.
+func WithWorkloadFinalization(timeout time.Duration) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.deletionTimeout = timeout
+	}
+}
+
+// WithLeasePartitioner specifies that the Reconciler should only reconcile the
+// ApplicationConfigurations whose Lease it holds per the supplied partitioner,
+// allowing several replicas of the controller to run at once.
+func WithLeasePartitioner(p *LeasePartitioner) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.partitioner = p
+	}
+}
+
+// WithHealthProber specifies how the Reconciler should probe the health of
+// workloads whose components have a health probe.
+func WithHealthProber(p HealthProber) ReconcilerOption {
+	return func(rc *Reconciler) {
+		rc.health = p
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(l logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -142,6 +555,13 @@ func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
 	// change the signature if we eventually need more from the manager (e.g its
 	// scheme).
 
+	traitAppliers := defaultTraitAppliers()
+	// Traffic splits fail to apply if no client can be built to scale their
+	// Deployments.
+	var scaler DeploymentScaler
+	if apps, err := clientappv1.NewForConfig(m.GetConfig()); err == nil {
+		scaler = &deploymentScaler{client: apps}
+	}
 	r := &Reconciler{
 		client: m.GetClient(),
 		components: &components{
@@ -150,29 +570,63 @@ func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
 			params:     ParameterResolveFn(resolve),
 			workload:   ResourceRenderFn(renderWorkload),
 			trait:      ResourceRenderFn(renderTrait),
+			schematics: NewOCISchematicFetcher(&http.Client{Timeout: registryTimeout}),
+			kustomize:  NewKustomizeSchematicRenderer(filepath.Join(os.TempDir(), "oam-kustomize")),
+			variables:  TemplateSubstituteFn(substituteVariables),
+			labels:     NewLabelPropagator(ExcludeSystemLabels),
 		},
 		workloads: &workloads{
-			client:    resource.NewAPIPatchingApplicator(m.GetClient()),
-			rawClient: m.GetClient(),
+			client:        NewAnnotationPreservationApplicator(m.GetClient(), resource.NewAPIPatchingApplicator(m.GetClient()), DefaultFieldManager()),
+			rawClient:     m.GetClient(),
+			impersonator:  &restImpersonator{client: m.GetClient(), config: m.GetConfig(), scheme: m.GetScheme()},
+			scaler:        scaler,
+			scheme:        m.GetScheme(),
+			traitAppliers: traitAppliers,
 		},
-		gc:     GarbageCollectorFn(eligible),
-		log:    logging.NewNopLogger(),
-		record: event.NewNopRecorder(),
+		gc:                  GarbageCollectorFn(eligible),
+		pruner:              &componentPruner{definitions: m.GetClient(), appliers: traitAppliers},
+		hook:                &httpsHookCaller{kube: m.GetClient()},
+		specs:               &crdSpecValidator{client: m.GetClient()},
+		appSets:             NewArgoApplicationSetRenderer(m.GetClient()),
+		conditions:          ConditionDeduplicatorFn(LatestConditions),
+		rollouts:            defaultHealthCheckers(),
+		traitAppliers:       traitAppliers,
+		clusters:            &secretConnector{client: m.GetClient(), scheme: m.GetScheme(), newClient: client.New},
+		finalizer:           resource.NewAPIFinalizer(m.GetClient(), finalizerWorkloads),
+		log:                 logging.NewNopLogger(),
+		record:              event.NewNopRecorder(),
+		health:              newHTTPProber(m.GetAPIReader()),
+		maxConcurrentGroups: defaultMaxConcurrentGroups,
+		tracer:              trace.NewNoopTracerProvider().Tracer(tracing.InstrumentationName),
 	}
 
 	for _, ro := range o {
 		ro(r)
 	}
 
+	if r.state == nil {
+		r.state = NewStateMachine(r.log)
+	}
+	if r.poller == nil {
+		r.poller = newHealthPoller(r.client, r.health, r.log)
+		r.poller.appSets = r.appSets
+	}
+	if r.deletionTimeout > 0 {
+		r.finalizers = NewFinalizerAwarePruner(r.pruner, r.deletionTimeout)
+	}
+
 	return r
 }
 
-// NOTE(negz): We don't validate anything against their definitions at the
-// controller level. We assume this will be done by validating admission
-// webhooks.
+// NOTE(negz): We don't reject anything that doesn't match its definition at
+// the controller level. We assume this will be done by validating admission
+// webhooks. Rendered workloads are validated against their schema, but only
+// to surface a condition.
 
 // Reconcile an OAM ApplicationConfigurations by rendering and instantiating its
 // Components and Traits.
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
@@ -180,60 +634,559 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
 	defer cancel()
 
+	ctx, span := r.tracer.Start(ctx, "appconfig.reconcile")
+	defer span.End()
+	span.SetAttributes(attribute.String("appconfig", req.NamespacedName.String()))
+
 	ac := &v1alpha2.ApplicationConfiguration{}
 	if err := r.client.Get(ctx, req.NamespacedName, ac); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.poller.Stop(req.NamespacedName)
+		}
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetAppConfig)
 	}
+	if r.partitioner != nil {
+		acquired, err := r.partitioner.Acquire(ctx, ac)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAcquireLease)
+		}
+		if !acquired {
+			if !r.partitioner.Owns(ac.GetUID()) {
+				log.Debug("Skipping application configuration owned by another replica")
+				r.poller.Stop(req.NamespacedName)
+				return reconcile.Result{}, nil
+			}
+			// Another replica still holds the Lease; wait for it to expire.
+			log.Debug("Application configuration lease is held by another replica", "requeue-after", time.Now().Add(r.partitioner.LeaseDuration()))
+			return reconcile.Result{RequeueAfter: r.partitioner.LeaseDuration()}, nil
+		}
+	}
+	if ac.GetDeletionTimestamp() != nil {
+		r.poller.Stop(req.NamespacedName)
+		if hasFinalizer(ac, finalizerWorkloads) {
+			return r.finalize(ctx, log, ac)
+		}
+	} else if r.finalizers != nil || hasNamespaceCopies(ac) {
+		if err := r.finalizer.AddFinalizer(ctx, ac); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	// The status is only patched if it differs from the observed status.
+	observed := ac.Status.DeepCopy()
 
 	log = log.WithValues("uid", ac.GetUID(), "version", ac.GetResourceVersion())
 
-	workloads, err := r.components.Render(ctx, ac)
+	if ac.GetAnnotations()[oam.AnnotationPaused] == "true" {
+		log.Debug("Reconciliation is paused")
+		r.state.Transition(ac, v1alpha2.StatePaused)
+		return reconcile.Result{}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	r.state.Transition(ac, v1alpha2.StateRendering)
+	if err := resolveSpecSource(ctx, r.client, r.appSets, ac); err != nil {
+		log.Debug("Cannot resolve spec source", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotResolveSource, err))
+		if IsSpecParseError(err) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSpecParseError, corev1.ConditionTrue, v1alpha2.ReasonSpecParseError, err.Error()))
+		}
+		ac.SetConditions(reconcileError(errors.Wrap(err, errResolveSpecSource)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	if ac.GetCondition(v1alpha2.TypeSpecParseError).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSpecParseError, corev1.ConditionFalse, v1alpha2.ReasonSpecParsed, ""))
+	}
+	if ac.GetDeletionTimestamp() == nil {
+		r.poller.Sync(ac)
+	}
+	rctx, rspan := tracing.StartSpan(ctx, "render")
+	workloads, err := r.components.Render(rctx, ac)
+	tracing.RecordError(rspan, err)
+	rspan.End()
 	if err != nil {
 		log.Debug("Cannot render components", "error", err, "requeue-after", time.Now().Add(shortWait))
 		r.record.Event(ac, event.Warning(reasonCannotRenderComponents, err))
-		ac.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errRenderComponents)))
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, ac), errUpdateAppConfigStatus)
+		if IsCyclicDependency(err) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCyclicDependency, corev1.ConditionTrue, v1alpha2.ReasonCyclicDependency, err.Error()))
+		}
+		if IsParameterValidationFailed(err) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeParametersValid, corev1.ConditionFalse, v1alpha2.ReasonParameterValidationFailed, err.Error()))
+		}
+		if IsTraitConflict(err) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeTraitConflict, corev1.ConditionTrue, v1alpha2.ReasonTraitConflict, err.Error()))
+		}
+		if IsPatchFailed(err) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePatchFailed, corev1.ConditionTrue, v1alpha2.ReasonPatchFailed, err.Error()))
+		}
+		ac.SetConditions(reconcileError(errors.Wrap(err, errRenderComponents)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	if ac.GetCondition(v1alpha2.TypeCyclicDependency).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCyclicDependency, corev1.ConditionFalse, v1alpha2.ReasonAcyclic, ""))
+	}
+	if ac.GetCondition(v1alpha2.TypeTraitConflict).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeTraitConflict, corev1.ConditionFalse, v1alpha2.ReasonNoTraitConflict, ""))
 	}
+	if ac.GetCondition(v1alpha2.TypePatchFailed).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePatchFailed, corev1.ConditionFalse, v1alpha2.ReasonPatchesApplied, ""))
+	}
+	if ac.GetAnnotations()[oam.AnnotationStrictParameterValidation] == "true" {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeParametersValid, corev1.ConditionTrue, v1alpha2.ReasonParameterValidationSucceeded, ""))
+	}
+
+	r.reportUnsupported(log, ac, workloads)
+
+	if failed := nameTemplateErrors(workloads); len(failed) > 0 {
+		msg := strings.Join(failed, "; ")
+		log.Debug("Some workload name templates cannot be rendered", "error", msg)
+		r.record.Event(ac, event.Warning(reasonNameTemplateError, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeNameTemplateError, corev1.ConditionTrue, v1alpha2.ReasonNameTemplateError, msg))
+	} else if ac.GetCondition(v1alpha2.TypeNameTemplateError).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeNameTemplateError, corev1.ConditionFalse, v1alpha2.ReasonNameTemplateRendered, ""))
+	}
+
+	for _, w := range workloads {
+		if w.Suspended {
+			r.record.Event(ac, event.Normal(reasonComponentSuspended, "Component is suspended", "component", w.ComponentName))
+		}
+	}
+
 	log.Debug("Successfully rendered components", "workloads", len(workloads))
 	r.record.Event(ac, event.Normal(reasonRenderComponents, "Successfully rendered components", "workloads", strconv.Itoa(len(workloads))))
 
-	if err := r.workloads.Apply(ctx, ac.Status.Workloads, workloads, resource.MustBeControllableBy(ac.GetUID())); err != nil {
-		log.Debug("Cannot apply components", "error", err, "requeue-after", time.Now().Add(shortWait))
-		r.record.Event(ac, event.Warning(reasonCannotApplyComponents, err))
-		ac.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyComponents)))
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, ac), errUpdateAppConfigStatus)
+	// Workloads of unauthorized kinds are not applied, and are garbage
+	// collected if they were previously applied. Other workloads are applied
+	// as usual.
+	workloads, denied := r.authorized(workloads)
+	if len(denied) > 0 {
+		msg := strings.Join(denied, "; ")
+		log.Debug("Some workloads are of unauthorized kinds", "error", msg)
+		r.record.Event(ac, event.Warning(reasonUnauthorizedWorkloads, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeUnauthorizedWorkloadKind, corev1.ConditionTrue, v1alpha2.ReasonUnauthorizedWorkloadKind, msg))
+	} else if ac.GetCondition(v1alpha2.TypeUnauthorizedWorkloadKind).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeUnauthorizedWorkloadKind, corev1.ConditionFalse, v1alpha2.ReasonAuthorizedWorkloadKinds, ""))
+	}
+
+	// Workloads and traits are applied to, and read from, the target cluster
+	// if there is one.
+	target, applicator, err := r.target(ctx, ac)
+	if err != nil {
+		log.Debug("Cannot connect to target cluster", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotConnectCluster, err))
+		ac.SetConditions(reconcileError(errors.Wrap(err, errConnectTargetCluster)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	if ac.Spec.TargetCluster != nil {
+		// Objects in the target cluster may not be owned by the
+		// ApplicationConfiguration, and scopes are not supported there.
+		workloads = inNamespace(workloads, ac.GetNamespace(), ac.GetUID())
+	}
+
+	if len(ac.Spec.NamespaceLabels) > 0 {
+		if !r.manageNamespaces {
+			log.Debug("Namespace creation is not allowed; ignoring namespace labels")
+			r.record.Event(ac, event.Warning(reasonNamespaceUnmanaged, errors.New(errNamespaceCreationDisallowed)))
+		} else if err := ensureNamespace(ctx, target, ac.GetNamespace(), ac.Spec.NamespaceLabels); err != nil {
+			log.Debug("Cannot manage namespace", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(ac, event.Warning(reasonCannotManageNamespace, err))
+			ac.SetConditions(reconcileError(errors.Wrap(err, errManageNamespace)))
+			r.state.Transition(ac, v1alpha2.StateDegraded)
+			return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+		}
+	}
+
+	// Missing ConfigMaps do not block applying workloads; their pods will
+	// fail to start until the ConfigMaps are created.
+	missing, err := missingConfigMaps(ctx, target, ac.GetNamespace(), workloads)
+	if err != nil {
+		log.Debug("Cannot check whether mounted ConfigMaps exist", "error", err)
+	}
+	if len(missing) > 0 {
+		msg := strings.Join(missing, "; ")
+		log.Debug("Some mounted ConfigMaps do not exist", "error", msg)
+		r.record.Event(ac, event.Warning(reasonConfigMapNotFound, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeConfigMapNotFound, corev1.ConditionTrue, v1alpha2.ReasonConfigMapNotFound, msg))
+	} else if err == nil && ac.GetCondition(v1alpha2.TypeConfigMapNotFound).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeConfigMapNotFound, corev1.ConditionFalse, v1alpha2.ReasonConfigMapsFound, ""))
+	}
+
+	// Missing PriorityClasses do not block applying workloads either; their
+	// pods will be rejected until the PriorityClasses are created.
+	missing, err = missingPriorityClasses(ctx, target, workloads)
+	if err != nil {
+		log.Debug("Cannot check whether PriorityClasses exist", "error", err)
+	}
+	if len(missing) > 0 {
+		msg := strings.Join(missing, "; ")
+		log.Debug("Some PriorityClasses do not exist", "error", msg)
+		r.record.Event(ac, event.Warning(reasonPriorityClassNotFound, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePriorityClassNotFound, corev1.ConditionTrue, v1alpha2.ReasonPriorityClassNotFound, msg))
+	} else if err == nil && ac.GetCondition(v1alpha2.TypePriorityClassNotFound).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePriorityClassNotFound, corev1.ConditionFalse, v1alpha2.ReasonPriorityClassesFound, ""))
+	}
+
+	// Service accounts are created before the workloads that use them are
+	// applied. Missing service accounts do not block applying workloads;
+	// their pods will be rejected until the service accounts are created.
+	missing, err = ensureServiceAccounts(ctx, target, ac, workloads)
+	if err != nil {
+		log.Debug("Cannot ensure service accounts exist", "error", err)
+	}
+	if len(missing) > 0 {
+		msg := strings.Join(missing, "; ")
+		log.Debug("Some service accounts do not exist", "error", msg)
+		r.record.Event(ac, event.Warning(reasonServiceAccountNotFound, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeServiceAccountNotFound, corev1.ConditionTrue, v1alpha2.ReasonServiceAccountNotFound, msg))
+	} else if err == nil && ac.GetCondition(v1alpha2.TypeServiceAccountNotFound).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeServiceAccountNotFound, corev1.ConditionFalse, v1alpha2.ReasonServiceAccountsFound, ""))
+	}
+
+	// Invalid workloads are still applied, so that adding or tightening a
+	// schema does not break existing deployments.
+	invalid, err := invalidWorkloads(ctx, r.specs, workloads)
+	if err != nil {
+		log.Debug("Cannot validate workloads against their schemas", "error", err)
+	}
+	if len(invalid) > 0 {
+		msg := strings.Join(invalid, "; ")
+		log.Debug("Some workloads do not match their schemas", "error", msg)
+		r.record.Event(ac, event.Warning(reasonSpecValidationFailed, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSpecValidationFailed, corev1.ConditionTrue, v1alpha2.ReasonSpecValidationFailed, msg))
+	} else if err == nil && ac.GetCondition(v1alpha2.TypeSpecValidationFailed).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSpecValidationFailed, corev1.ConditionFalse, v1alpha2.ReasonSpecValidationSucceeded, ""))
+	}
+
+	// Workloads are not added to scopes that do not apply to their kind, and
+	// are removed from them if they were previously added.
+	incompatible, err := incompatibleScopes(ctx, r.client, workloads)
+	if err != nil {
+		log.Debug("Cannot check whether scopes apply to their workloads", "error", err)
+	}
+	if len(incompatible) > 0 {
+		msg := strings.Join(incompatible, "; ")
+		log.Debug("Some workloads cannot join their scopes", "error", msg)
+		r.record.Event(ac, event.Warning(reasonScopeIncompatible, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeScopeIncompatibility, corev1.ConditionTrue, v1alpha2.ReasonScopeIncompatible, msg))
+	} else if err == nil && ac.GetCondition(v1alpha2.TypeScopeIncompatibility).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeScopeIncompatibility, corev1.ConditionFalse, v1alpha2.ReasonScopesCompatible, ""))
+	}
+
+	// Orphaned workload statuses would otherwise be passed to the applicator
+	// and garbage collector as though their workloads still existed.
+	if err := r.pruneWorkloadStatuses(ctx, target, ac, workloads); err != nil {
+		log.Debug("Cannot prune orphaned workload statuses", "error", err, "requeue-after", time.Now().Add(shortWait))
+		ac.SetConditions(reconcileError(errors.Wrap(err, errPruneWorkloadStatus)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	// Components in groups are rolled out a group at a time. Workloads that
+	// are held back are neither applied nor garbage collected, and keep their
+	// previous status.
+	released, held, err := r.rollout(ac, workloads)
+	if err != nil {
+		log.Debug("Cannot roll out component groups", "error", err, "requeue-after", time.Now().Add(shortWait))
+		ac.SetConditions(reconcileError(errors.Wrap(err, errRolloutGroups)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	// Workloads are applied after those of the kinds their components depend
+	// on, once those are healthy.
+	released, waiting, err := orderByDependencies(ac, released)
+	if err != nil {
+		log.Debug("Cannot order components by their dependencies", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonDependencyCycle, err))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDependencyCycle, corev1.ConditionTrue, v1alpha2.ReasonDependencyCycle, err.Error()), reconcileError(errors.Wrap(err, errOrderDependencies)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	if ac.GetCondition(v1alpha2.TypeDependencyCycle).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDependencyCycle, corev1.ConditionFalse, v1alpha2.ReasonNoDependencyCycle, ""))
+	}
+	held = append(held, waiting...)
+	releasedStatus, heldStatus := heldStatuses(ac.Status.Workloads, held)
+
+	// Workloads that no longer match the spec they were last applied with are
+	// handled per the drift policy before they are applied again, whatever
+	// the reconcile policy. Drifted workloads are re-applied as the service
+	// account their component applies as, if any.
+	if err := r.handleDrift(ctx, target, driftApplicatorFor(target, applicator), ac); err != nil {
+		log.Debug("Cannot handle drifted workloads", "error", err)
+	}
+
+	// In onChange mode we only apply rendered components that differ from
+	// those we last applied, or that have since been deleted.
+	hash := ""
+	if applyOnChange(ac) {
+		if hash, err = r.renderHash(ctx, ac, workloads); err != nil {
+			log.Debug("Cannot compute hash of rendered components", "error", err, "requeue-after", time.Now().Add(shortWait))
+			ac.SetConditions(reconcileError(errors.Wrap(err, errHashComponents)))
+			r.state.Transition(ac, v1alpha2.StateDegraded)
+			return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+		}
+		if hash == ac.Status.LastAppliedHash {
+			drifted, err := r.drifted(ctx, target, ac)
+			if err != nil {
+				log.Debug("Cannot check applied components for drift", "error", err, "requeue-after", time.Now().Add(shortWait))
+				ac.SetConditions(reconcileError(errors.Wrap(err, errCheckDrift)))
+				r.state.Transition(ac, v1alpha2.StateDegraded)
+				return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+			}
+			if !drifted {
+				log.Debug("Rendered components are unchanged", "requeue-after", time.Now().Add(resyncPeriod(ac)))
+				r.probeHealth(ctx, ac)
+				if err := observeRollouts(ctx, target, r.rollouts, ac); err != nil {
+					log.Debug("Cannot observe workload rollouts", "error", err)
+				}
+				if err := observeTraitReadiness(ctx, target, r.traitAppliers, ac); err != nil {
+					log.Debug("Cannot observe trait readiness", "error", err)
+				}
+				if err := recordComponentEvents(ctx, target, r.client, ac); err != nil {
+					log.Debug("Cannot record component events", "error", err)
+					r.record.Event(ac, event.Warning(reasonCannotRecordEvents, err))
+				}
+				if rolledBack, err := r.rollback(ctx, ac); err != nil {
+					log.Debug("Cannot roll back unhealthy components", "error", err)
+					r.record.Event(ac, event.Warning(reasonCannotRollback, err))
+				} else if rolledBack {
+					log.Debug("Rolled back unhealthy components")
+					r.record.Event(ac, event.Normal(reasonRollback, "Rolled back unhealthy components to their last healthy revision"))
+					ac.Status.LastAppliedHash = ""
+					return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+				}
+				r.state.Transition(ac, v1alpha2.StateReady)
+				ac.SetConditions(v1alpha1.ReconcileSuccess())
+				return reconcile.Result{RequeueAfter: resyncPeriod(ac)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+			}
+		}
+	}
+
+	// Workloads are only applied if the security contexts injected into them
+	// comply with the PodSecurity level enforced on their namespace, given
+	// that their pods would otherwise be rejected.
+	violations, err := podSecurityViolations(ctx, target, released)
+	if err != nil {
+		log.Debug("Cannot check whether security contexts comply with PodSecurity", "error", err)
+	}
+	if len(violations) > 0 {
+		msg := strings.Join(violations, "; ")
+		log.Debug("Some security contexts violate PodSecurity", "error", msg, "requeue-after", time.Now().Add(longWait))
+		r.record.Event(ac, event.Warning(reasonPodSecurityViolation, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePodSecurityViolation, corev1.ConditionTrue, v1alpha2.ReasonPodSecurityViolation, msg))
+		ac.SetConditions(v1alpha2.PermanentReconcileError(errors.Wrap(errors.New(msg), errPodSecurity)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	} else if err == nil && ac.GetCondition(v1alpha2.TypePodSecurityViolation).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePodSecurityViolation, corev1.ConditionFalse, v1alpha2.ReasonPodSecurityCompliant, ""))
+	}
+
+	// Workloads are only applied once the pre-apply hook, if any, accepts
+	// them.
+	if h := ac.Spec.PreApplyHook; h != nil {
+		if err := r.hook.Call(ctx, h, ac, released); err != nil {
+			log.Debug("Pre-apply hook failed", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(ac, event.Warning(reasonPreApplyHookFailed, err))
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePreApplyHookFailed, corev1.ConditionTrue, v1alpha2.ReasonPreApplyHookFailed, err.Error()))
+			ac.SetConditions(reconcileError(errors.Wrap(err, errPreApplyHook)))
+			r.state.Transition(ac, v1alpha2.StateDegraded)
+			return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+		}
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePreApplyHookFailed, corev1.ConditionFalse, v1alpha2.ReasonPreApplyHookSucceeded, ""))
+	} else if ac.GetCondition(v1alpha2.TypePreApplyHookFailed).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypePreApplyHookFailed, corev1.ConditionFalse, v1alpha2.ReasonPreApplyHookSucceeded, ""))
+	}
+
+	// Traits that fail to apply, and workloads that time out, are reported in
+	// the status of the workload they are associated with. The remaining
+	// workloads and traits have been applied, so we continue.
+	ao := append([]resource.ApplyOption{resource.MustBeControllableBy(ac.GetUID())}, adoptionOptions(ac)...)
+	r.state.Transition(ac, v1alpha2.StateApplying)
+	actx, aspan := tracing.StartSpan(ctx, "workloads.apply")
+	// Statuses of removed components are excluded so that their scope
+	// membership is only removed once they have been pruned.
+	releasedStatus, _ = removedComponents(ac.Spec.Components, releasedStatus)
+	applyErr := applicator.Apply(withMaxConcurrentApply(actx, ac), releasedStatus, released, ao...)
+	tracing.RecordError(aspan, applyErr)
+	aspan.End()
+	if applyErr != nil && !IsPartiallyApplied(applyErr) {
+		log.Debug("Cannot apply components", "error", applyErr, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotApplyComponents, applyErr))
+		if IsAdoptionConflict(applyErr) {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeAdoptionConflict, corev1.ConditionTrue, v1alpha2.ReasonAdoptionConflict, errors.Cause(applyErr).Error()))
+		}
+		ac.SetConditions(reconcileError(errors.Wrap(applyErr, errApplyComponents)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(applyErr)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	if ac.GetCondition(v1alpha2.TypeAdoptionConflict).Status == corev1.ConditionTrue {
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeAdoptionConflict, corev1.ConditionFalse, v1alpha2.ReasonNoAdoptionConflict, ""))
+	}
+	if applyErr != nil {
+		log.Debug("Cannot apply some workloads or traits", "error", applyErr, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotApplyComponents, applyErr))
+	} else {
+		log.Debug("Successfully applied components", "workloads", len(released))
+		r.record.Event(ac, event.Normal(reasonApplyComponents, "Successfully applied components", "workloads", strconv.Itoa(len(released))))
 	}
-	log.Debug("Successfully applied components", "workloads", len(workloads))
-	r.record.Event(ac, event.Normal(reasonApplyComponents, "Successfully applied components", "workloads", strconv.Itoa(len(workloads))))
 
 	// Kubernetes garbage collection will (by default) reap workloads and traits
 	// when the appconfig that controls them (in the controller reference sense)
 	// is deleted. Here we cover the case in which a component or one of its
 	// traits is removed from an extant appconfig.
-	for _, e := range r.gc.Eligible(ac.GetNamespace(), ac.Status.Workloads, workloads) {
+	retained, removed := removedComponents(ac.Spec.Components, ac.Status.Workloads)
+	if err := r.pruner.Prune(ctx, target, ac.GetNamespace(), removed); err != nil {
+		log.Debug("Cannot prune removed components", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotPruneComponents, err))
+		ac.SetConditions(reconcileError(errors.Wrap(err, errPruneComponents)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+	for _, ws := range removed {
+		log.Debug("Pruned removed component", "component", ws.ComponentName)
+		r.record.Event(ac, event.Normal(reasonPruneComponent, "Successfully pruned removed component", "component", ws.ComponentName))
+	}
+
+	for _, e := range r.traitAppliers.DeletionOrder(r.gc.Eligible(ac.GetNamespace(), retained, workloads)) {
 		// https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
 		e := e
 
 		log := log.WithValues("kind", e.GetKind(), "name", e.GetName())
 		record := r.record.WithAnnotations("kind", e.GetKind(), "name", e.GetName())
 
-		if err := r.client.Delete(ctx, &e); resource.IgnoreNotFound(err) != nil {
+		if err := r.traitAppliers.Delete(ctx, target, &e); resource.IgnoreNotFound(err) != nil {
 			log.Debug("Cannot garbage collect component", "error", err, "requeue-after", time.Now().Add(shortWait))
 			record.Event(ac, event.Warning(reasonCannotGGComponents, err))
-			ac.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errGCComponent)))
-			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, ac), errUpdateAppConfigStatus)
+			ac.SetConditions(reconcileError(errors.Wrap(err, errGCComponent)))
+			r.state.Transition(ac, v1alpha2.StateDegraded)
+			return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
 		}
 		log.Debug("Garbage collected resource")
 		record.Event(ac, event.Normal(reasonGGComponent, "Successfully garbage collected component"))
 	}
 
-	ac.Status.Workloads = make([]v1alpha2.WorkloadStatus, len(workloads))
-	for i := range workloads {
-		ac.Status.Workloads[i] = workloads[i].Status()
+	// Surplus old revisions of updated workloads, e.g. the ReplicaSets of a
+	// Deployment, are garbage collected per their WorkloadDefinitions. Failing
+	// to do so does not prevent the workloads from running.
+	if n, err := collectRevisions(ctx, r.client, target, released); err != nil {
+		log.Debug("Cannot garbage collect workload revisions", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotGCRevisions, err))
+	} else if n > 0 {
+		log.Debug("Garbage collected workload revisions", "revisions", n)
+	}
+
+	ac.Status.Workloads = make([]v1alpha2.WorkloadStatus, len(released))
+	for i := range released {
+		ac.Status.Workloads[i] = released[i].Status()
+	}
+	preserveLastAppliedTimes(ac.Status.Workloads, releasedStatus)
+	preserveTraitUIDs(ac.Status.Workloads, releasedStatus)
+	preserveHealth(ac.Status.Workloads, releasedStatus)
+	recordLastAppliedSpecs(ac.Status.Workloads, released, lastAppliedSpecLimit(ac))
+	setWorkloadConditions(ac.Status.Workloads, applyErr)
+	setTraitConditions(ac.Status.Workloads, applyErr)
+	ac.Status.Workloads = append(ac.Status.Workloads, heldStatus...)
+	if applyErr == nil {
+		ac.Status.Topology = topology(released)
+	}
+
+	if err := r.applyToSelectedNamespaces(ctx, ac, workloads); err != nil {
+		log.Debug("Cannot apply components to selected namespaces", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotApplyNamespaces, err))
+		ac.SetConditions(reconcileError(errors.Wrap(err, errApplyNamespaces)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(err)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	r.probeHealth(ctx, ac)
+	if err := observeRollouts(ctx, target, r.rollouts, ac); err != nil {
+		log.Debug("Cannot observe workload rollouts", "error", err)
+	}
+	if err := observeTraitReadiness(ctx, target, r.traitAppliers, ac); err != nil {
+		log.Debug("Cannot observe trait readiness", "error", err)
+	}
+	if err := recordComponentEvents(ctx, target, r.client, ac); err != nil {
+		log.Debug("Cannot record component events", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotRecordEvents, err))
+	}
+
+	// Unhealthy components are rolled back by rendering and applying their
+	// last healthy revision, which happens on the next reconcile.
+	if rolledBack, err := r.rollback(ctx, ac); err != nil {
+		log.Debug("Cannot roll back unhealthy components", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotRollback, err))
+	} else if rolledBack {
+		log.Debug("Rolled back unhealthy components")
+		r.record.Event(ac, event.Normal(reasonRollback, "Rolled back unhealthy components to their last healthy revision"))
+		ac.Status.LastAppliedHash = ""
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	if applyErr != nil {
+		// Apply again next time, even if nothing has changed.
+		ac.Status.LastAppliedHash = ""
+		ac.SetConditions(reconcileError(errors.Wrap(applyErr, errApplyComponents)))
+		r.state.Transition(ac, v1alpha2.StateDegraded)
+		return reconcile.Result{RequeueAfter: errorWait(applyErr)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	// Failing to prune old component revisions does not affect the workloads
+	// we just applied, so we only report it.
+	if err := r.pruneHistory(ctx, ac); err != nil {
+		log.Debug("Cannot prune component revision history", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotPruneHistory, errors.Wrap(err, errPruneHistory)))
 	}
 
+	if len(held) > 0 {
+		// Apply again once the groups being rolled out, or the workloads
+		// that held workloads depend on, are healthy.
+		log.Debug("Waiting for component groups or dependencies to become healthy", "held", len(held), "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Normal(reasonHoldComponents, "Waiting for component groups or dependencies to become healthy", "held", strconv.Itoa(len(held))))
+		ac.Status.LastAppliedHash = ""
+		ac.SetConditions(v1alpha1.ReconcileSuccess())
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+	}
+
+	ac.Status.LastAppliedHash = hash
+	r.state.Transition(ac, v1alpha2.StateReady)
 	ac.SetConditions(v1alpha1.ReconcileSuccess())
-	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, ac), errUpdateAppConfigStatus)
+	return reconcile.Result{RequeueAfter: resyncPeriod(ac)}, errors.Wrap(r.updateStatus(ctx, ac, observed), errUpdateAppConfigStatus)
+}
+
+// updateStatus patches the status of the supplied ApplicationConfiguration,
+// sending only the fields that differ from the supplied observed status. The
+// API server is not called if the status is unchanged. Merge patches are used
+// given that the API server does not support strategic merge patches of custom
+// resources. Duplicate conditions are removed before the status is patched.
+func (r *Reconciler) updateStatus(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, observed *v1alpha2.ApplicationConfigurationStatus) error {
+	ctx, span := tracing.StartSpan(ctx, "status.update")
+	defer span.End()
+
+	if r.conditions != nil {
+		ac.Status.Conditions = r.conditions.Deduplicate(ac.Status.Conditions)
+	}
+
+	from := ac.DeepCopy()
+	from.Status = *observed
+	patch := client.MergeFrom(from)
+	data, err := patch.Data(ac)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return errors.Wrap(err, errComputeStatusPatch)
+	}
+	if string(data) == "{}" {
+		statusUpdates.WithLabelValues(statusUpdateNoop).Inc()
+		return nil
+	}
+
+	err = r.client.Status().Patch(ctx, ac, client.RawPatch(patch.Type(), data))
+	tracing.RecordError(span, err)
+	if err == nil {
+		statusUpdates.WithLabelValues(statusUpdatePatched).Inc()
+	}
+	return err
 }
 
 // A Workload produced by an OAM ApplicationConfiguration.
@@ -244,6 +1197,10 @@ type Workload struct {
 	//ComponentRevisionName of current component
 	ComponentRevisionName string
 
+	// ComponentUID is the stable identity of the component that produced
+	// this workload.
+	ComponentUID string
+
 	// A Workload object.
 	Workload *unstructured.Unstructured
 
@@ -252,6 +1209,149 @@ type Workload struct {
 
 	// Scopes associated with this workload.
 	Scopes []unstructured.Unstructured
+
+	// ServiceAccountName that is impersonated to apply this workload and its
+	// traits. The workload is applied by the controller if it is empty.
+	ServiceAccountName string
+
+	// ApplyTimeout bounds how long applying this workload may take. Applying
+	// is not bounded if it is zero.
+	ApplyTimeout time.Duration
+
+	// UnsupportedEnv is true if the component that produced this workload
+	// specifies environment variables that could not be injected into it.
+	UnsupportedEnv bool
+
+	// UnsupportedAffinity is true if the component that produced this
+	// workload specifies an affinity that could not be injected into it.
+	UnsupportedAffinity bool
+
+	// UnsupportedTolerations is true if the component that produced this
+	// workload specifies tolerations that could not be injected into it.
+	UnsupportedTolerations bool
+
+	// UnsupportedNodeSelector is true if the component that produced this
+	// workload specifies a node selector that could not be injected into it.
+	UnsupportedNodeSelector bool
+
+	// UnsupportedPriorityClass is true if the component that produced this
+	// workload specifies a priority class that could not be injected into it.
+	UnsupportedPriorityClass bool
+
+	// UnsupportedHostNetwork is true if the component that produced this
+	// workload enables host network mode, but it could not be injected into
+	// it.
+	UnsupportedHostNetwork bool
+
+	// UnsupportedLivenessProbe is true if the component that produced this
+	// workload specifies a liveness probe that could not be injected into it.
+	UnsupportedLivenessProbe bool
+
+	// UnsupportedReadinessProbe is true if the component that produced this
+	// workload specifies a readiness probe that could not be injected into it.
+	UnsupportedReadinessProbe bool
+
+	// PodDisruptionBudget that is applied alongside this workload, if any.
+	PodDisruptionBudget *unstructured.Unstructured
+
+	// UnsupportedPodDisruptionBudget is true if the component that produced
+	// this workload specifies a PodDisruptionBudget, but the workload has no
+	// label selector from which to render it.
+	UnsupportedPodDisruptionBudget bool
+
+	// TrafficSplit that is applied alongside this workload, if any.
+	TrafficSplit *TrafficSplit
+
+	// UnsupportedTrafficWeight is true if the component that produced this
+	// workload specifies a traffic weight, but the workload is not a
+	// Deployment with a label selector.
+	UnsupportedTrafficWeight bool
+
+	// NameTemplateError explains why the workload name template of the
+	// component that produced this workload could not be rendered, in which
+	// case the workload has the name defined by the component.
+	NameTemplateError string
+
+	// Suspended is true if the component that produced this workload is
+	// suspended.
+	Suspended bool
+
+	// Resumed is true if the component that produced this workload was
+	// suspended when it was last reconciled, but no longer is.
+	Resumed bool
+
+	// Decrypted is true if any parameter values of the component that
+	// produced this workload were decrypted. The workload may thus contain
+	// secrets.
+	Decrypted bool
+
+	// ConfigMaps that are mounted into this workload.
+	ConfigMaps []string
+
+	// PriorityClassName of the pods of this workload, if it was set by the
+	// component that produced it.
+	PriorityClassName string
+
+	// PodServiceAccountName is the service account of the pods of this
+	// workload, if it was set by the component that produced it.
+	PodServiceAccountName string
+
+	// CreatePodServiceAccount is true if the component that produced this
+	// workload asks for its pods' service account to be created.
+	CreatePodServiceAccount bool
+
+	// UnsupportedServiceAccount is true if the component that produced this
+	// workload specifies a service account that could not be injected into
+	// it.
+	UnsupportedServiceAccount bool
+
+	// SecurityContextInjected is true if the component that produced this
+	// workload specifies a security context that was injected into it.
+	SecurityContextInjected bool
+
+	// UnsupportedSecurityContext is true if the component that produced this
+	// workload specifies a security context that could not be injected into
+	// it.
+	UnsupportedSecurityContext bool
+
+	// LastAppliedTime is set by the WorkloadApplicator once this workload has
+	// been successfully applied.
+	LastAppliedTime *metav1.Time
+}
+
+// DeepCopy returns a deep copy of this workload.
+func (w Workload) DeepCopy() Workload {
+	out := w
+	if w.Workload != nil {
+		out.Workload = w.Workload.DeepCopy()
+	}
+	if w.Traits != nil {
+		out.Traits = copyManifests(w.Traits)
+	}
+	if w.Scopes != nil {
+		out.Scopes = copyManifests(w.Scopes)
+	}
+	if w.ConfigMaps != nil {
+		out.ConfigMaps = append([]string(nil), w.ConfigMaps...)
+	}
+	if w.LastAppliedTime != nil {
+		out.LastAppliedTime = w.LastAppliedTime.DeepCopy()
+	}
+	if w.PodDisruptionBudget != nil {
+		out.PodDisruptionBudget = w.PodDisruptionBudget.DeepCopy()
+	}
+	if w.TrafficSplit != nil {
+		out.TrafficSplit = w.TrafficSplit.DeepCopy()
+	}
+	return out
+}
+
+func copyManifests(m []unstructured.Unstructured) []unstructured.Unstructured {
+	out := make([]unstructured.Unstructured, len(m))
+	for i := range m {
+		m[i].DeepCopyInto(&out[i])
+	}
+	return out
 }
 
 // Status produces the status of this workload and its traits, suitable for use
@@ -260,13 +1360,15 @@ func (w Workload) Status() v1alpha2.WorkloadStatus {
 	acw := v1alpha2.WorkloadStatus{
 		ComponentName:         w.ComponentName,
 		ComponentRevisionName: w.ComponentRevisionName,
+		ComponentUID:          w.ComponentUID,
 		Reference: runtimev1alpha1.TypedReference{
 			APIVersion: w.Workload.GetAPIVersion(),
 			Kind:       w.Workload.GetKind(),
 			Name:       w.Workload.GetName(),
 		},
-		Traits: make([]v1alpha2.WorkloadTrait, len(w.Traits)),
-		Scopes: make([]v1alpha2.WorkloadScope, len(w.Scopes)),
+		Traits:          make([]v1alpha2.WorkloadTrait, len(w.Traits)),
+		Scopes:          make([]v1alpha2.WorkloadScope, len(w.Scopes)),
+		LastAppliedTime: w.LastAppliedTime,
 	}
 	for i := range w.Traits {
 		acw.Traits[i].Reference = runtimev1alpha1.TypedReference{
@@ -274,6 +1376,7 @@ func (w Workload) Status() v1alpha2.WorkloadStatus {
 			Kind:       w.Traits[i].GetKind(),
 			Name:       w.Traits[i].GetName(),
 		}
+		acw.Traits[i].UID = w.Traits[i].GetUID()
 	}
 	for i, s := range w.Scopes {
 		acw.Scopes[i].Reference = runtimev1alpha1.TypedReference{
@@ -282,6 +1385,15 @@ func (w Workload) Status() v1alpha2.WorkloadStatus {
 			Name:       s.GetName(),
 		}
 	}
+	if w.TrafficSplit != nil {
+		acw.TrafficSplit = w.TrafficSplit.Status()
+	}
+	if w.Suspended {
+		acw.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSuspended, corev1.ConditionTrue, v1alpha2.ReasonComponentSuspended, ""))
+	}
+	if w.Resumed {
+		acw.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSuspended, corev1.ConditionFalse, v1alpha2.ReasonComponentResumed, ""))
+	}
 	return acw
 }
 
@@ -343,6 +1455,9 @@ func eligible(namespace string, ws []v1alpha2.WorkloadStatus, w []Workload) []un
 				t.SetKind(ts.Reference.Kind)
 				t.SetNamespace(namespace)
 				t.SetName(ts.Reference.Name)
+				if ts.UID != "" {
+					t.SetUID(ts.UID)
+				}
 				eligible = append(eligible, *t)
 			}
 		}
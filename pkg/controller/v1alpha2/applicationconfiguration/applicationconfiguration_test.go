@@ -18,27 +18,46 @@ package applicationconfiguration
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/dependency"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
+// newMockStatusPatchFn returns a MockStatusPatchFn that passes the patched
+// object to the supplied ObjectFns, then returns the supplied error.
+func newMockStatusPatchFn(err error, ofn ...test.ObjectFn) test.MockStatusPatchFn {
+	return func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+		for _, fn := range ofn {
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
 type acParam func(*v1alpha2.ApplicationConfiguration)
 
 func withConditions(c ...runtimev1alpha1.Condition) acParam {
@@ -53,6 +72,18 @@ func withWorkloadStatuses(ws ...v1alpha2.WorkloadStatus) acParam {
 	}
 }
 
+func withTopology(t *v1alpha2.Topology) acParam {
+	return func(ac *v1alpha2.ApplicationConfiguration) {
+		ac.Status.Topology = t
+	}
+}
+
+func withState(st v1alpha2.ApplicationConfigurationState) acParam {
+	return func(ac *v1alpha2.ApplicationConfiguration) {
+		ac.Status.State = st
+	}
+}
+
 func ac(p ...acParam) *v1alpha2.ApplicationConfiguration {
 	ac := &v1alpha2.ApplicationConfiguration{}
 	for _, fn := range p {
@@ -74,6 +105,9 @@ func TestReconciler(t *testing.T) {
 	workload.SetNamespace(namespace)
 	workload.SetName("workload")
 
+	raw, _ := json.Marshal(workload)
+	lastAppliedSpec := &runtime.RawExtension{Raw: raw}
+
 	trait := &unstructured.Unstructured{}
 	trait.SetAPIVersion("v")
 	trait.SetKind("trait")
@@ -113,11 +147,11 @@ func TestReconciler(t *testing.T) {
 				m: &mock.Manager{
 					Client: &test.MockClient{
 						MockGet: test.NewMockGetFn(nil),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
 
-							want := ac(withConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errRenderComponents))))
+							want := ac(withState(v1alpha2.StateDegraded), withConditions(v1alpha2.TransientReconcileError(errors.Wrap(errBoom, errRenderComponents))))
 							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration)); diff != "" {
-								t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
 								return errUnexpectedStatus
 							}
 
@@ -141,10 +175,10 @@ func TestReconciler(t *testing.T) {
 				m: &mock.Manager{
 					Client: &test.MockClient{
 						MockGet: test.NewMockGetFn(nil),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
-							want := ac(withConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errApplyComponents))))
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
+							want := ac(withState(v1alpha2.StateDegraded), withConditions(v1alpha2.TransientReconcileError(errors.Wrap(errBoom, errApplyComponents))))
 							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration)); diff != "" {
-								t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
 								return errUnexpectedStatus
 							}
 							return nil
@@ -171,10 +205,10 @@ func TestReconciler(t *testing.T) {
 					Client: &test.MockClient{
 						MockGet:    test.NewMockGetFn(nil),
 						MockDelete: test.NewMockDeleteFn(errBoom),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
-							want := ac(withConditions(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errGCComponent))))
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
+							want := ac(withState(v1alpha2.StateDegraded), withConditions(v1alpha2.TransientReconcileError(errors.Wrap(errBoom, errGCComponent))))
 							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration)); diff != "" {
-								t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
 								return errUnexpectedStatus
 							}
 							return nil
@@ -203,9 +237,11 @@ func TestReconciler(t *testing.T) {
 				m: &mock.Manager{
 					Client: &test.MockClient{
 						MockGet:    test.NewMockGetFn(nil),
+						MockList:   test.NewMockListFn(nil),
 						MockDelete: test.NewMockDeleteFn(nil),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
 							want := ac(
+								withState(v1alpha2.StateReady),
 								withConditions(runtimev1alpha1.ReconcileSuccess()),
 								withWorkloadStatuses(v1alpha2.WorkloadStatus{
 									ComponentName: componentName,
@@ -214,10 +250,14 @@ func TestReconciler(t *testing.T) {
 										Kind:       workload.GetKind(),
 										Name:       workload.GetName(),
 									},
+									LastAppliedSpec: lastAppliedSpec,
 								}),
+								withTopology(&v1alpha2.Topology{Nodes: map[string]runtimev1alpha1.TypedReference{
+									"v/workload/workload": {APIVersion: "v", Kind: "workload", Name: "workload"},
+								}}),
 							)
 							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration), cmpopts.EquateEmpty()); diff != "" {
-								t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
 								return errUnexpectedStatus
 							}
 							return nil
@@ -240,11 +280,132 @@ func TestReconciler(t *testing.T) {
 				result: reconcile.Result{RequeueAfter: longWait},
 			},
 		},
+		"ApplyTraitError": {
+			reason: "Errors applying a trait should be reflected as conditions of the trait and the ApplicationConfiguration",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
+							traitErr := &partialApplyError{}
+							traitErr.add(*trait, errBoom)
+							ts := v1alpha2.WorkloadTrait{Reference: runtimev1alpha1.TypedReference{
+								APIVersion: trait.GetAPIVersion(),
+								Kind:       trait.GetKind(),
+								Name:       trait.GetName(),
+							}}
+							ts.SetConditions(runtimev1alpha1.ReconcileError(errBoom))
+							want := ac(
+								withState(v1alpha2.StateDegraded),
+								withConditions(v1alpha2.TransientReconcileError(errors.Wrap(traitErr, errApplyComponents))),
+								withWorkloadStatuses(v1alpha2.WorkloadStatus{
+									ComponentName: componentName,
+									Reference: runtimev1alpha1.TypedReference{
+										APIVersion: workload.GetAPIVersion(),
+										Kind:       workload.GetKind(),
+										Name:       workload.GetName(),
+									},
+									LastAppliedSpec: lastAppliedSpec,
+									Traits:          []v1alpha2.WorkloadTrait{ts},
+								}),
+							)
+							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration), cmpopts.EquateEmpty()); diff != "" {
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
+								return errUnexpectedStatus
+							}
+							return nil
+						}),
+					},
+				},
+				o: []ReconcilerOption{
+					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, error) {
+						return []Workload{{ComponentName: componentName, Workload: workload, Traits: []unstructured.Unstructured{*trait}}}, nil
+					})),
+					WithApplicator(WorkloadApplyFn(func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+						err := &partialApplyError{}
+						err.add(*trait, errBoom)
+						return err
+					})),
+					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"PruneOrphanedWorkloadStatus": {
+			reason: "Workload statuses whose workload was not rendered and no longer exists should be pruned before applying",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha2.ApplicationConfiguration:
+								withWorkloadStatuses(v1alpha2.WorkloadStatus{
+									ComponentName: "removedcomponent",
+									Reference:     runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "orphan"},
+								})(o)
+							case *unstructured.Unstructured:
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							}
+							return nil
+						},
+						MockList: test.NewMockListFn(nil),
+						MockStatusPatch: newMockStatusPatchFn(nil, func(o runtime.Object) error {
+							want := ac(
+								withState(v1alpha2.StateReady),
+								withConditions(runtimev1alpha1.ReconcileSuccess()),
+								withWorkloadStatuses(v1alpha2.WorkloadStatus{
+									ComponentName: componentName,
+									Reference: runtimev1alpha1.TypedReference{
+										APIVersion: workload.GetAPIVersion(),
+										Kind:       workload.GetKind(),
+										Name:       workload.GetName(),
+									},
+									LastAppliedSpec: lastAppliedSpec,
+								}),
+								withTopology(&v1alpha2.Topology{Nodes: map[string]runtimev1alpha1.TypedReference{
+									"v/workload/workload": {APIVersion: "v", Kind: "workload", Name: "workload"},
+								}}),
+							)
+							if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration), cmpopts.EquateEmpty()); diff != "" {
+								t.Errorf("\nclient.Status().Patch(): -want, +got:\n%s", diff)
+								return errUnexpectedStatus
+							}
+							return nil
+						}),
+					},
+				},
+				o: []ReconcilerOption{
+					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, error) {
+						return []Workload{{ComponentName: componentName, Workload: workload}}, nil
+					})),
+					WithApplicator(WorkloadApplyFn(func(_ context.Context, ws []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+						if len(ws) != 0 {
+							t.Errorf("Apply(...): want no workload statuses, got %v", ws)
+						}
+						return nil
+					})),
+					WithGarbageCollector(GarbageCollectorFn(func(_ string, ws []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
+						if len(ws) != 0 {
+							t.Errorf("Eligible(...): want no workload statuses, got %v", ws)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: longWait},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.m, tc.args.o...)
+			o := append([]ReconcilerOption{WithStateMachine(NewStateMachine(logging.NewNopLogger(), WithStrictTransitions()))}, tc.args.o...)
+			r := NewReconciler(tc.args.m, o...)
 			got, err := r.Reconcile(reconcile.Request{})
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -258,6 +419,107 @@ func TestReconciler(t *testing.T) {
 	}
 }
 
+func TestUpdateStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	observed := &v1alpha2.ApplicationConfigurationStatus{}
+	observed.SetConditions(runtimev1alpha1.ReconcileSuccess())
+
+	type want struct {
+		patch string
+		err   error
+	}
+	cases := map[string]struct {
+		reason   string
+		ac       *v1alpha2.ApplicationConfiguration
+		patchErr error
+		want     want
+	}{
+		"Unchanged": {
+			reason: "The status should not be patched if it is unchanged",
+			ac: &v1alpha2.ApplicationConfiguration{
+				Spec:   v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "resolved"}}},
+				Status: *observed.DeepCopy(),
+			},
+		},
+		"Changed": {
+			reason: "Only the changed fields of the status should be patched",
+			ac: &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{
+				ConditionedStatus: observed.ConditionedStatus,
+				LastAppliedHash:   "hash",
+			}},
+			want: want{patch: `{"status":{"lastAppliedHash":"hash"}}`},
+		},
+		"PatchError": {
+			reason: "Errors patching the status should be returned",
+			ac: &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{
+				ConditionedStatus: observed.ConditionedStatus,
+				LastAppliedHash:   "hash",
+			}},
+			patchErr: errBoom,
+			want:     want{patch: `{"status":{"lastAppliedHash":"hash"}}`, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			r := &Reconciler{client: &test.MockClient{
+				MockStatusPatch: func(_ context.Context, obj runtime.Object, p client.Patch, _ ...client.PatchOption) error {
+					data, _ := p.Data(obj)
+					got.patch = string(data)
+					return tc.patchErr
+				},
+			}}
+			got.err = r.updateStatus(context.Background(), tc.ac, observed)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.updateStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusDeduplicatesConditions(t *testing.T) {
+	types := []runtimev1alpha1.ConditionType{
+		runtimev1alpha1.TypeSynced,
+		v1alpha2.TypeUnsupportedEnv,
+		v1alpha2.TypeSpecValidationFailed,
+	}
+	observed := &v1alpha2.ApplicationConfigurationStatus{}
+	ac := &v1alpha2.ApplicationConfiguration{}
+
+	var patched []runtimev1alpha1.Condition
+	r := &Reconciler{
+		client: &test.MockClient{
+			MockStatusPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+				patched = obj.(*v1alpha2.ApplicationConfiguration).Status.Conditions
+				return nil
+			},
+		},
+		conditions: ConditionDeduplicatorFn(LatestConditions),
+	}
+
+	for i := 0; i < 100; i++ {
+		// Append rather than set conditions, as a writer that does not check
+		// for conditions of the same type would.
+		for _, ct := range types {
+			ac.Status.Conditions = append(ac.Status.Conditions, runtimev1alpha1.Condition{Type: ct, LastTransitionTime: metav1.Unix(int64(i), 0)})
+		}
+		if err := r.updateStatus(context.Background(), ac, observed); err != nil {
+			t.Fatalf("r.updateStatus(...): unexpected error: %s", err)
+		}
+		observed = ac.Status.DeepCopy()
+	}
+
+	if len(patched) != len(types) {
+		t.Fatalf("r.updateStatus(...): want %d conditions, got %d", len(types), len(patched))
+	}
+	for i, c := range patched {
+		if c.Type != types[i] || c.LastTransitionTime.Unix() != 99 {
+			t.Errorf("r.updateStatus(...): want the most recent %s condition, got %s condition from %s", types[i], c.Type, c.LastTransitionTime)
+		}
+	}
+}
+
 func TestWorkloadStatus(t *testing.T) {
 	namespace := "ns"
 	componentName := "coolcomponent"
@@ -595,7 +857,7 @@ func TestAddDataInputsToDAG(t *testing.T) {
 		t.Fatal("didn't add sinks to specified output correctly")
 	}
 
-	s, ok := sps.Sinks["Trait:test-ns/test-name"]
+	s, ok := sps.Sinks[util.WorkloadIdentityKey(obj)]
 	if !ok {
 		t.Fatal("didn't add object as sink correctly")
 	}
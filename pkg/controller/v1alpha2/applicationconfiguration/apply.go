@@ -18,17 +18,22 @@ package applicationconfiguration
 
 import (
 	"context"
+	"strings"
+	"sync"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/tracing"
 )
 
 // Reconcile error strings.
@@ -38,6 +43,12 @@ const (
 	errFmtGetTraitDefinition = "cannot find trait definition %q %q %q"
 	errFmtApplyTrait         = "cannot apply trait %q %q %q"
 	errFmtApplyScope         = "cannot apply scope %q %q %q"
+	errFmtImpersonate        = "cannot impersonate service account of workload %q"
+	errFmtApplyTimeout       = "cannot apply workload %q within %s"
+	errWaitApply             = "cannot wait to apply workloads"
+
+	errFmtNoTargetContainer       = "workload reference path %q requires a target container name"
+	errFmtTargetContainerNotFound = "workload %q has no container named %q"
 )
 
 // A WorkloadApplicator creates or updates workloads and their traits.
@@ -55,48 +66,462 @@ func (fn WorkloadApplyFn) Apply(ctx context.Context, status []v1alpha2.WorkloadS
 }
 
 type workloads struct {
-	client    resource.Applicator
-	rawClient client.Client
+	client       resource.Applicator
+	rawClient    client.Client
+	impersonator Impersonator
+
+	// scaler scales the stable and canary Deployments of workloads with a
+	// traffic split.
+	scaler DeploymentScaler
+
+	// scheme is used to find the strategic merge metadata of traits whose
+	// TraitDefinition uses the merge strategy. Traits are merged using a JSON
+	// merge if it is nil.
+	scheme *runtime.Scheme
+
+	// traitDefinitions caches TraitDefinitions. TraitDefinitions are read
+	// using the rawClient if it is nil.
+	traitDefinitions *TraitDefinitionCache
+
+	// traitAppliers apply the kinds of trait that need more than a plain
+	// apply. All traits are applied using the applicator if it is nil.
+	traitAppliers *TraitApplierRegistry
+
+	// batchSize is the number of traits of a workload that are server-side
+	// applied at once. Traits are applied one at a time if it is zero.
+	batchSize int
 }
 
 func (a *workloads) Apply(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload, ao ...resource.ApplyOption) error {
 	// they are all in the same namespace
 	var namespace = w[0].Workload.GetNamespace()
-	for _, wl := range w {
-		if err := a.client.Apply(ctx, wl.Workload, ao...); err != nil {
-			return errors.Wrapf(err, errFmtApplyWorkload, wl.Workload.GetName())
+
+	// Workloads are applied concurrently, up to the limit of their
+	// ApplicationConfiguration at once. Their results are gathered in order
+	// so that errors are reported deterministically.
+	results := make([]*partialApplyError, len(w))
+	errs := make([]error, len(w))
+	l := newApplyLimiter(maxConcurrentApply(ctx))
+	wg := &sync.WaitGroup{}
+	var waitErr error
+	for i := range w {
+		if waitErr = l.Acquire(ctx); waitErr != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer l.Release()
+			results[i], errs[i] = a.applyOne(ctx, &w[i], ao...)
+		}(i)
+	}
+	wg.Wait()
+
+	failed := &partialApplyError{}
+	for i := range w {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		if results[i] != nil {
+			failed.merge(results[i])
 		}
-		workloadRef := runtimev1alpha1.TypedReference{
-			APIVersion: wl.Workload.GetAPIVersion(),
-			Kind:       wl.Workload.GetKind(),
-			Name:       wl.Workload.GetName(),
+	}
+	if waitErr != nil {
+		return errors.Wrap(waitErr, errWaitApply)
+	}
+
+	if err := a.removeTrafficSplits(ctx, status, w); err != nil {
+		return err
+	}
+
+	sctx, span := tracing.StartSpan(ctx, "scope.update")
+	err := a.dereferenceScope(sctx, namespace, status, w)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		return err
+	}
+	if len(failed.errs) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// applyOne applies the supplied workload, then its traits and scopes. Traits
+// that fail to apply, and workloads that time out, are returned as a partial
+// apply error. The workload's LastAppliedTime is set once it is applied.
+func (a *workloads) applyOne(ctx context.Context, wl *Workload, ao ...resource.ApplyOption) (*partialApplyError, error) {
+	failed := &partialApplyError{}
+	applicator, err := a.applicatorFor(ctx, *wl)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtImpersonate, wl.Workload.GetName())
+	}
+	if err := a.preserveStableTemplate(ctx, *wl); err != nil {
+		return nil, err
+	}
+	if err := a.applyWorkload(ctx, applicator, *wl, ao...); err != nil {
+		if isApplyTimeout(err) {
+			// A workload that times out does not prevent the remaining
+			// workloads from being applied. Its traits and scopes are
+			// applied once it has been.
+			failed.add(*wl.Workload, err)
+			return failed, nil
 		}
+		return nil, err
+	}
+	now := metav1.Now()
+	wl.LastAppliedTime = &now
+
+	if err := a.applyPodDisruptionBudget(ctx, applicator, *wl); err != nil {
+		// A PodDisruptionBudget that fails to apply does not prevent the
+		// workload's traits from being applied.
+		failed.add(*wl.PodDisruptionBudget, err)
+	}
+
+	if err := a.applyTrafficSplit(ctx, applicator, *wl); err != nil {
+		// A traffic split that fails to apply does not prevent the
+		// workload's traits from being applied.
+		failed.add(*wl.TrafficSplit.Canary, err)
+	}
+
+	workloadRef := runtimev1alpha1.TypedReference{
+		APIVersion: wl.Workload.GetAPIVersion(),
+		Kind:       wl.Workload.GetKind(),
+		Name:       wl.Workload.GetName(),
+	}
 
-		for _, t := range wl.Traits {
-			//  We only patch a TypedReference object to the trait if it asks for it
+	if a.batchSize > 0 {
+		if err := a.applyTraitBatch(ctx, applicator, wl, workloadRef, failed, ao...); err != nil {
+			return nil, err
+		}
+	} else {
+		for i, t := range wl.Traits {
+			// A trait that fails to apply does not prevent the remaining
+			// traits from being applied.
 			trait := t
-			if traitDefinition, err := util.FetchTraitDefinition(ctx, a.rawClient, &trait); err == nil {
-				workloadRefPath := traitDefinition.Spec.WorkloadRefPath
-				if len(workloadRefPath) != 0 {
-					if err := fieldpath.Pave(t.UnstructuredContent()).SetValue(workloadRefPath, workloadRef); err != nil {
-						return errors.Wrapf(err, errFmtSetWorkloadRef, t.GetName(), wl.Workload.GetName())
-					}
-				}
-			} else {
-				return errors.Wrapf(err, errFmtGetTraitDefinition, t.GetAPIVersion(), t.GetKind(), t.GetName())
+			if err := a.applyTrait(ctx, applicator, wl.ServiceAccountName, &trait, wl.Workload, workloadRef, ao...); err != nil {
+				failed.add(trait, err)
+				continue
+			}
+			// The applied trait has the UID of the live trait, which is
+			// recorded in the workload's status.
+			if uid := trait.GetUID(); uid != "" {
+				wl.Traits[i].SetUID(uid)
 			}
+		}
+	}
+
+	if err := a.applyScopes(ctx, *wl, workloadRef); err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+// applyWorkload applies the supplied workload, within its apply timeout if it
+// has one.
+func (a *workloads) applyWorkload(ctx context.Context, applicator resource.Applicator, wl Workload, ao ...resource.ApplyOption) error {
+	actx := ctx
+	if wl.ApplyTimeout > 0 {
+		var cancel context.CancelFunc
+		actx, cancel = context.WithTimeout(ctx, wl.ApplyTimeout)
+		defer cancel()
+	}
+	err := applicator.Apply(actx, wl.Workload, ao...)
+	if err == nil {
+		return nil
+	}
+	if wl.ApplyTimeout > 0 && actx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return &applyTimeoutError{msg: errors.Wrapf(err, errFmtApplyTimeout, wl.Workload.GetName(), wl.ApplyTimeout).Error()}
+	}
+	err = explainForbidden(err, wl.ServiceAccountName, wl.Workload.GetKind(), wl.Workload.GetName())
+	return errors.Wrapf(err, errFmtApplyWorkload, wl.Workload.GetName())
+}
+
+// An applyTimeoutError indicates that a workload could not be applied within
+// its apply timeout.
+type applyTimeoutError struct {
+	msg string
+}
+
+func (e *applyTimeoutError) Error() string {
+	return e.msg
+}
+
+func isApplyTimeout(err error) bool {
+	_, ok := errors.Cause(err).(*applyTimeoutError)
+	return ok
+}
+
+// applicatorFor returns the applicator used to apply the supplied workload and
+// its traits.
+func (a *workloads) applicatorFor(ctx context.Context, wl Workload) (resource.Applicator, error) {
+	if wl.ServiceAccountName == "" {
+		return a.client, nil
+	}
+	c, err := a.impersonator.Impersonate(ctx, wl.Workload.GetNamespace(), wl.ServiceAccountName)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnnotationPreservationApplicator(c, resource.NewAPIPatchingApplicator(c), DefaultFieldManager()), nil
+}
 
-			if err := a.client.Apply(ctx, &trait, ao...); err != nil {
-				return errors.Wrapf(err, errFmtApplyTrait, t.GetAPIVersion(), t.GetKind(), t.GetName())
+func (a *workloads) applyTrait(ctx context.Context, applicator resource.Applicator, serviceAccount string, t *unstructured.Unstructured, w *unstructured.Unstructured, workloadRef runtimev1alpha1.TypedReference, ao ...resource.ApplyOption) error {
+	traitDefinition, err := a.prepareTrait(ctx, t, w, workloadRef)
+	if err != nil {
+		return err
+	}
+	return a.applyPreparedTrait(ctx, applicator, serviceAccount, t, w, traitDefinition, ao...)
+}
+
+// prepareTrait returns the TraitDefinition of the supplied trait, having set
+// the trait's workload reference if its TraitDefinition asks for one.
+func (a *workloads) prepareTrait(ctx context.Context, t *unstructured.Unstructured, w *unstructured.Unstructured, workloadRef runtimev1alpha1.TypedReference) (*v1alpha2.TraitDefinition, error) {
+	//  We only patch a TypedReference object to the trait if it asks for it
+	traitDefinition, err := a.getTraitDefinition(ctx, t)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetTraitDefinition, t.GetAPIVersion(), t.GetKind(), t.GetName())
+	}
+	workloadRefPath, err := resolveWorkloadRefPath(traitDefinition.Spec, w)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtSetWorkloadRef, t.GetName(), w.GetName())
+	}
+	if len(workloadRefPath) != 0 {
+		if err := fieldpath.Pave(t.UnstructuredContent()).SetValue(workloadRefPath, workloadRef); err != nil {
+			return nil, errors.Wrapf(err, errFmtSetWorkloadRef, t.GetName(), w.GetName())
+		}
+	}
+	return traitDefinition, nil
+}
+
+// batchable returns true if the supplied trait is applied using a plain
+// apply, and may therefore be server-side applied as part of a batch.
+func (a *workloads) batchable(t *unstructured.Unstructured, td *v1alpha2.TraitDefinition) bool {
+	if _, ok := a.traitAppliers.Lookup(t.GroupVersionKind()); ok {
+		return false
+	}
+	return td.Spec.MergeStrategy != v1alpha2.MergeStrategyMerge
+}
+
+// applyTraitBatch applies the traits of the supplied workload. Traits that
+// are applied using a plain apply are server-side applied in parallel as a
+// batch; the rest are applied one at a time. Traits that fail to apply are
+// added to the supplied partial apply error.
+func (a *workloads) applyTraitBatch(ctx context.Context, applicator resource.Applicator, wl *Workload, workloadRef runtimev1alpha1.TypedReference, failed *partialApplyError, ao ...resource.ApplyOption) error {
+	traits := make([]unstructured.Unstructured, len(wl.Traits))
+	copy(traits, wl.Traits)
+
+	batch := make([]runtime.Object, 0, len(traits))
+	batched := make([]int, 0, len(traits))
+	applied := make([]bool, len(traits))
+	for i := range traits {
+		t := &traits[i]
+		td, err := a.prepareTrait(ctx, t, wl.Workload, workloadRef)
+		if err != nil {
+			failed.add(*t, err)
+			continue
+		}
+		if a.batchable(t, td) {
+			batch = append(batch, t)
+			batched = append(batched, i)
+			continue
+		}
+		if err := a.applyPreparedTrait(ctx, applicator, wl.ServiceAccountName, t, wl.Workload, td, ao...); err != nil {
+			failed.add(*t, err)
+			continue
+		}
+		applied[i] = true
+	}
+
+	if len(batch) > 0 {
+		c, err := a.clientFor(ctx, wl.Workload.GetNamespace(), wl.ServiceAccountName)
+		if err != nil {
+			return errors.Wrapf(err, errFmtImpersonate, wl.Workload.GetName())
+		}
+		err = NewBatchApplicator(NewServerSideApplicator(c, DefaultFieldManager()), a.batchSize).ApplyBatch(ctx, batch, ao...)
+		var errs []error
+		if be, ok := err.(*BatchApplyError); ok {
+			errs = be.Errors()
+		} else if err != nil {
+			return err
+		}
+		for j, i := range batched {
+			t := &traits[i]
+			if errs != nil && errs[j] != nil {
+				err := explainForbidden(errs[j], wl.ServiceAccountName, t.GetKind(), t.GetName())
+				failed.add(*t, errors.Wrapf(err, errFmtApplyTrait, t.GetAPIVersion(), t.GetKind(), t.GetName()))
+				continue
 			}
+			applied[i] = true
+		}
+	}
+
+	// The applied traits have the UID of the live traits, which is recorded
+	// in the workload's status.
+	for i := range traits {
+		if uid := traits[i].GetUID(); applied[i] && uid != "" {
+			wl.Traits[i].SetUID(uid)
+		}
+	}
+	return nil
+}
+
+func (a *workloads) applyPreparedTrait(ctx context.Context, applicator resource.Applicator, serviceAccount string, t *unstructured.Unstructured, w *unstructured.Unstructured, traitDefinition *v1alpha2.TraitDefinition, ao ...resource.ApplyOption) error {
+	var err error
+	if ta, ok := a.traitAppliers.Lookup(t.GroupVersionKind()); ok {
+		c, err := a.clientFor(ctx, t.GetNamespace(), serviceAccount)
+		if err != nil {
+			return errors.Wrapf(err, errFmtImpersonate, w.GetName())
+		}
+		err = explainForbidden(ta.Apply(ctx, c, t, w, ao...), serviceAccount, t.GetKind(), t.GetName())
+		return errors.Wrapf(err, errFmtApplyTrait, t.GetAPIVersion(), t.GetKind(), t.GetName())
+	}
+	if traitDefinition.Spec.MergeStrategy == v1alpha2.MergeStrategyMerge {
+		if applicator, err = a.mergingApplicatorFor(ctx, t.GetNamespace(), serviceAccount); err != nil {
+			return errors.Wrapf(err, errFmtImpersonate, w.GetName())
 		}
+	}
+	err = explainForbidden(applicator.Apply(ctx, t, ao...), serviceAccount, t.GetKind(), t.GetName())
+	return errors.Wrapf(err, errFmtApplyTrait, t.GetAPIVersion(), t.GetKind(), t.GetName())
+}
+
+// mergingApplicatorFor returns an applicator that merges traits with the
+// traits that already exist, impersonating the supplied service account if
+// any.
+func (a *workloads) mergingApplicatorFor(ctx context.Context, namespace, serviceAccount string) (resource.Applicator, error) {
+	c, err := a.clientFor(ctx, namespace, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+	return &mergingApplicator{client: c, scheme: a.scheme}, nil
+}
+
+// clientFor returns a client that impersonates the supplied service account,
+// if any.
+func (a *workloads) clientFor(ctx context.Context, namespace, serviceAccount string) (client.Client, error) {
+	if serviceAccount == "" {
+		return a.rawClient, nil
+	}
+	return a.impersonator.Impersonate(ctx, namespace, serviceAccount)
+}
+
+func (a *workloads) getTraitDefinition(ctx context.Context, t *unstructured.Unstructured) (*v1alpha2.TraitDefinition, error) {
+	if a.traitDefinitions != nil {
+		return a.traitDefinitions.Get(ctx, t)
+	}
+	return util.FetchTraitDefinition(ctx, a.rawClient, t)
+}
 
-		for _, s := range wl.Scopes {
-			return a.applyScope(ctx, wl, s, workloadRef)
+// A partialApplyError indicates that one or more traits could not be applied,
+// or that one or more workloads could not be applied within their apply
+// timeout. The remaining workloads and traits were applied.
+type partialApplyError struct {
+	errs map[runtimev1alpha1.TypedReference]error
+
+	// order of the failed workloads and traits, for a stable error message.
+	order []runtimev1alpha1.TypedReference
+}
+
+func (e *partialApplyError) add(t unstructured.Unstructured, err error) {
+	if e.errs == nil {
+		e.errs = make(map[runtimev1alpha1.TypedReference]error)
+	}
+	ref := runtimev1alpha1.TypedReference{APIVersion: t.GetAPIVersion(), Kind: t.GetKind(), Name: t.GetName()}
+	e.errs[ref] = err
+	e.order = append(e.order, ref)
+}
+
+func (e *partialApplyError) merge(o *partialApplyError) {
+	for _, ref := range o.order {
+		if e.errs == nil {
+			e.errs = make(map[runtimev1alpha1.TypedReference]error)
 		}
+		e.errs[ref] = o.errs[ref]
+		e.order = append(e.order, ref)
 	}
+}
 
-	return a.dereferenceScope(ctx, namespace, status, w)
+func (e *partialApplyError) Error() string {
+	msgs := make([]string, 0, len(e.order))
+	for _, ref := range e.order {
+		msgs = append(msgs, e.errs[ref].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// IsPartiallyApplied returns true if the supplied error indicates that one or
+// more traits could not be applied, or that one or more workloads timed out,
+// while the remaining workloads and traits were applied.
+func IsPartiallyApplied(err error) bool {
+	_, ok := errors.Cause(err).(*partialApplyError)
+	return ok
+}
+
+// setWorkloadConditions sets a condition on each of the supplied workload
+// statuses whose workload could not be applied within its apply timeout,
+// according to the supplied error.
+func setWorkloadConditions(ws []v1alpha2.WorkloadStatus, err error) {
+	failed, _ := errors.Cause(err).(*partialApplyError)
+	if failed == nil {
+		return
+	}
+	for i := range ws {
+		if e := failed.errs[ws[i].Reference]; e != nil {
+			ws[i].SetConditions(v1alpha2.ApplyTimeout(e.Error()))
+		}
+	}
+}
+
+// setTraitConditions sets a condition on each trait of the supplied workload
+// statuses indicating whether it was applied, according to the supplied
+// error.
+func setTraitConditions(ws []v1alpha2.WorkloadStatus, err error) {
+	failed, _ := errors.Cause(err).(*partialApplyError)
+	for i := range ws {
+		for j := range ws[i].Traits {
+			t := &ws[i].Traits[j]
+			if failed != nil && failed.errs[t.Reference] != nil {
+				t.SetConditions(runtimev1alpha1.ReconcileError(failed.errs[t.Reference]))
+				continue
+			}
+			t.SetConditions(runtimev1alpha1.ReconcileSuccess())
+		}
+	}
+}
+
+// resolveWorkloadRefPath returns the workload reference path of the supplied
+// TraitDefinition, with any container name placeholder replaced by the name of
+// the container the trait targets.
+func resolveWorkloadRefPath(td v1alpha2.TraitDefinitionSpec, w *unstructured.Unstructured) (string, error) {
+	if !strings.Contains(td.WorkloadRefPath, containerNamePlaceholder) {
+		return td.WorkloadRefPath, nil
+	}
+	if td.TargetContainerName == "" {
+		return "", errors.Errorf(errFmtNoTargetContainer, td.WorkloadRefPath)
+	}
+	if err := checkContainerExists(w, td.TargetContainerName); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(td.WorkloadRefPath, containerNamePlaceholder, td.TargetContainerName), nil
+}
+
+// checkContainerExists returns an error if the supplied workload has a pod
+// template, but no container with the supplied name. Workloads that do not
+// use a pod template are not checked.
+func checkContainerExists(w *unstructured.Unstructured, name string) error {
+	v, err := fieldpath.Pave(w.UnstructuredContent()).GetValue(podTemplateContainersPath)
+	if err != nil {
+		return nil
+	}
+	containers, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, c := range containers {
+		if m, ok := c.(map[string]interface{}); ok && m["name"] == name {
+			return nil
+		}
+	}
+	return errors.Errorf(errFmtTargetContainerNotFound, w.GetName(), name)
 }
 
 func (a *workloads) dereferenceScope(ctx context.Context, namespace string, status []v1alpha2.WorkloadStatus, w []Workload) error {
@@ -141,6 +566,22 @@ func findDereferencedScopes(statusScopes []v1alpha2.WorkloadScope, scopes []unst
 	return toBeDeferenced
 }
 
+// applyScopes adds the supplied workload to each of its scopes.
+func (a *workloads) applyScopes(ctx context.Context, wl Workload, workloadRef runtimev1alpha1.TypedReference) error {
+	if len(wl.Scopes) == 0 {
+		return nil
+	}
+	ctx, span := tracing.StartSpan(ctx, "scope.update")
+	defer span.End()
+	for _, s := range wl.Scopes {
+		if err := a.applyScope(ctx, wl, s, workloadRef); err != nil {
+			tracing.RecordError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *workloads) applyScope(ctx context.Context, wl Workload, s unstructured.Unstructured, workloadRef runtimev1alpha1.TypedReference) error {
 	var refs []interface{}
 	if value, err := fieldpath.Pave(s.UnstructuredContent()).GetValue("spec.workloadRefs"); err == nil {
@@ -171,6 +612,12 @@ func (a *workloads) applyScope(ctx context.Context, wl Workload, s unstructured.
 }
 
 func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, ws v1alpha2.WorkloadStatus, s v1alpha2.WorkloadScope) error {
+	return removeFromScope(ctx, a.rawClient, namespace, ws, s)
+}
+
+// removeFromScope removes the workload of the supplied workload status from the
+// supplied scope.
+func removeFromScope(ctx context.Context, c client.Client, namespace string, ws v1alpha2.WorkloadStatus, s v1alpha2.WorkloadScope) error {
 	workloadRef := runtimev1alpha1.TypedReference{
 		APIVersion: ws.Reference.APIVersion,
 		Kind:       ws.Reference.Kind,
@@ -181,7 +628,7 @@ func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, ws
 	scopeObject.SetAPIVersion(s.Reference.APIVersion)
 	scopeObject.SetKind(s.Reference.Kind)
 	scopeObjectRef := types.NamespacedName{Namespace: namespace, Name: s.Reference.Name}
-	if err := a.rawClient.Get(ctx, scopeObjectRef, &scopeObject); err != nil {
+	if err := c.Get(ctx, scopeObjectRef, &scopeObject); err != nil {
 		return errors.Wrapf(err, errFmtApplyScope, s.Reference.APIVersion, s.Reference.Kind, s.Reference.Name)
 	}
 
@@ -209,7 +656,7 @@ func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, ws
 				return errors.Wrapf(err, errFmtSetWorkloadRef, s.Reference.Name, ws.Reference.Name)
 			}
 
-			if err := a.rawClient.Update(ctx, &scopeObject); err != nil {
+			if err := c.Update(ctx, &scopeObject); err != nil {
 				return errors.Wrapf(err, errFmtApplyScope, s.Reference.APIVersion, s.Reference.Kind, s.Reference.Name)
 			}
 		}
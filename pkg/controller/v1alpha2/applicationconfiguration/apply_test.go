@@ -19,7 +19,9 @@ package applicationconfiguration
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -27,9 +29,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -57,6 +61,20 @@ func TestApplyWorkloads(t *testing.T) {
 	trait.SetName("trait-example")
 	trait.SetUID(types.UID("trait-uid"))
 
+	otherTrait := trait.DeepCopy()
+	otherTrait.SetName("other-trait-example")
+	otherTrait.SetUID(types.UID("other-trait-uid"))
+
+	otherWorkload := workload.DeepCopy()
+	otherWorkload.SetName("other-workload-example")
+	otherWorkload.SetUID(types.UID("other-workload-uid"))
+
+	traitErr := func(t unstructured.Unstructured, err error) error {
+		e := &partialApplyError{}
+		e.add(t, err)
+		return e
+	}
+
 	scope := &unstructured.Unstructured{}
 	scope.SetAPIVersion("scope.oam.dev")
 	scope.SetKind("scopeKind")
@@ -87,14 +105,16 @@ func TestApplyWorkloads(t *testing.T) {
 		ctx context.Context
 		ws  []v1alpha2.WorkloadStatus
 		w   []Workload
+		ao  []resource.ApplyOption
 	}
 
 	cases := map[string]struct {
-		reason    string
-		client    resource.Applicator
-		rawClient client.Client
-		args      args
-		want      error
+		reason       string
+		client       resource.Applicator
+		rawClient    client.Client
+		impersonator Impersonator
+		args         args
+		want         error
 	}{
 		"ApplyWorkloadError": {
 			reason: "Errors applying a workload should be reflected as a status condition",
@@ -110,6 +130,23 @@ func TestApplyWorkloads(t *testing.T) {
 				ws: []v1alpha2.WorkloadStatus{}},
 			want: errors.Wrapf(errBoom, errFmtApplyWorkload, workload.GetName()),
 		},
+		"ApplyOptionError": {
+			reason: "Errors returned by an apply option should be reflected as a status condition",
+			client: resource.ApplyFn(func(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+				for _, fn := range ao {
+					if err := fn(ctx, o, o); err != nil {
+						return err
+					}
+				}
+				return nil
+			}),
+			rawClient: nil,
+			args: args{
+				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait}}},
+				ws: []v1alpha2.WorkloadStatus{},
+				ao: []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error { return errBoom }}},
+			want: errors.Wrapf(errBoom, errFmtApplyWorkload, workload.GetName()),
+		},
 		"ApplyTraitError": {
 			reason: "Errors applying a trait should be reflected as a status condition",
 			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
@@ -122,7 +159,109 @@ func TestApplyWorkloads(t *testing.T) {
 			args: args{
 				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait}}},
 				ws: []v1alpha2.WorkloadStatus{}},
-			want: errors.Wrapf(errBoom, errFmtApplyTrait, trait.GetAPIVersion(), trait.GetKind(), trait.GetName()),
+			want: traitErr(*trait, errors.Wrapf(errBoom, errFmtApplyTrait, trait.GetAPIVersion(), trait.GetKind(), trait.GetName())),
+		},
+		"ContinueAfterTraitError": {
+			reason: "Traits should still be applied after another trait fails to apply",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				if t, ok := o.(*unstructured.Unstructured); ok && t.GetUID() == trait.GetUID() {
+					return errBoom
+				}
+				if t, ok := o.(*unstructured.Unstructured); ok && t.GetUID() == otherTrait.GetUID() {
+					return errTrait
+				}
+				return nil
+			}),
+			rawClient: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			args: args{
+				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait, *otherTrait}}},
+				ws: []v1alpha2.WorkloadStatus{}},
+			want: func() error {
+				e := &partialApplyError{}
+				e.add(*trait, errors.Wrapf(errBoom, errFmtApplyTrait, trait.GetAPIVersion(), trait.GetKind(), trait.GetName()))
+				e.add(*otherTrait, errors.Wrapf(errTrait, errFmtApplyTrait, otherTrait.GetAPIVersion(), otherTrait.GetKind(), otherTrait.GetName()))
+				return e
+			}(),
+		},
+		"ApplyWorkloadTimeout": {
+			reason: "Workloads that time out should be reported, and the remaining workloads should still be applied",
+			client: resource.ApplyFn(func(ctx context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				if w, ok := o.(*unstructured.Unstructured); ok && w.GetUID() == workload.GetUID() {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				if w, ok := o.(*unstructured.Unstructured); ok && w.GetUID() == trait.GetUID() {
+					return errors.New("traits of a workload that timed out should not be applied")
+				}
+				return nil
+			}),
+			rawClient: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				w: []Workload{
+					{Workload: workload, Traits: []unstructured.Unstructured{*trait}, ApplyTimeout: time.Millisecond},
+					{Workload: otherWorkload, Traits: []unstructured.Unstructured{*otherTrait}},
+				},
+				ws: []v1alpha2.WorkloadStatus{}},
+			want: func() error {
+				e := &partialApplyError{}
+				e.add(*workload, &applyTimeoutError{msg: errors.Wrapf(context.DeadlineExceeded, errFmtApplyTimeout, workload.GetName(), time.Millisecond).Error()})
+				return e
+			}(),
+		},
+		"MergeTrait": {
+			reason: "Traits whose TraitDefinition uses the merge strategy should be merged with the existing trait",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				if t, ok := o.(*unstructured.Unstructured); ok && t.GetUID() == trait.GetUID() {
+					return errors.New("traits that use the merge strategy should not be patched")
+				}
+				return nil
+			}),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if td, ok := obj.(*v1alpha2.TraitDefinition); ok {
+						td.Spec.MergeStrategy = v1alpha2.MergeStrategyMerge
+						return nil
+					}
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				},
+				MockCreate: test.NewMockCreateFn(nil),
+			},
+			args: args{
+				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait.DeepCopy()}}},
+				ws: []v1alpha2.WorkloadStatus{}},
+		},
+		"ImpersonateError": {
+			reason: "Errors impersonating a workload's service account should be returned",
+			client: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+				return nil
+			}),
+			impersonator: ImpersonatorFn(func(_ context.Context, _, _ string) (client.Client, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				w:  []Workload{{Workload: workload, ServiceAccountName: "sa"}},
+				ws: []v1alpha2.WorkloadStatus{}},
+			want: errors.Wrapf(errBoom, errFmtImpersonate, workload.GetName()),
+		},
+		"Impersonate": {
+			reason: "The workload and traits of a component with a service account should be applied by impersonating it",
+			client: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+				return errBoom
+			}),
+			rawClient: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			impersonator: ImpersonatorFn(func(_ context.Context, ns, name string) (client.Client, error) {
+				if ns != namespace || name != "sa" {
+					return nil, errors.Errorf("impersonated %s/%s", ns, name)
+				}
+				return &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil),
+				}, nil
+			}),
+			args: args{
+				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait.DeepCopy()}, ServiceAccountName: "sa"}},
+				ws: []v1alpha2.WorkloadStatus{}},
 		},
 		"GetTraitDefinitionError": {
 			reason:    "Errors getting a traitDefinition should be reflected as a status condition",
@@ -133,7 +272,7 @@ func TestApplyWorkloads(t *testing.T) {
 			args: args{
 				w:  []Workload{{Workload: workload, Traits: []unstructured.Unstructured{*trait}}},
 				ws: []v1alpha2.WorkloadStatus{}},
-			want: errors.Wrapf(errTrait, errFmtGetTraitDefinition, trait.GetAPIVersion(), trait.GetKind(), trait.GetName()),
+			want: traitErr(*trait, errors.Wrapf(errTrait, errFmtGetTraitDefinition, trait.GetAPIVersion(), trait.GetKind(), trait.GetName())),
 		},
 		"TestApplyWorkloadRef": {
 			reason: "The workloadRef should be applied to a trait if its traitDefinition asks for it",
@@ -325,8 +464,12 @@ func TestApplyWorkloads(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			w := workloads{client: tc.client, rawClient: tc.rawClient}
-			err := w.Apply(tc.args.ctx, tc.args.ws, tc.args.w)
+			ctx := tc.args.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			w := workloads{client: tc.client, rawClient: tc.rawClient, impersonator: tc.impersonator}
+			err := w.Apply(ctx, tc.args.ws, tc.args.w, tc.args.ao...)
 
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nw.Apply(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -334,3 +477,143 @@ func TestApplyWorkloads(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyWorkloadsConcurrently(t *testing.T) {
+	w := make([]Workload, 10)
+	for i := range w {
+		wl := &unstructured.Unstructured{}
+		wl.SetAPIVersion("workload.oam.dev")
+		wl.SetKind("workloadKind")
+		wl.SetNamespace("ns")
+		wl.SetName(fmt.Sprintf("workload-%d", i))
+		w[i] = Workload{Workload: wl}
+	}
+
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	applicator := resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	})
+
+	limit := int32(3)
+	ac := &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{MaxConcurrentApply: &limit}}
+	a := workloads{client: applicator, rawClient: &test.MockClient{MockGet: test.NewMockGetFn(nil)}}
+	if err := a.Apply(withMaxConcurrentApply(context.Background(), ac), []v1alpha2.WorkloadStatus{}, w); err != nil {
+		t.Fatalf("a.Apply(...): %s", err)
+	}
+	if most > int(limit) {
+		t.Errorf("a.Apply(...): want at most %d workloads applied at once, got %d", limit, most)
+	}
+	if most < 2 {
+		t.Errorf("a.Apply(...): want workloads applied concurrently, got %d at once", most)
+	}
+	for _, wl := range w {
+		if wl.LastAppliedTime == nil {
+			t.Errorf("a.Apply(...): want LastAppliedTime of workload %q set", wl.Workload.GetName())
+		}
+	}
+}
+
+func TestSetWorkloadConditions(t *testing.T) {
+	ref := v1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "slow"}
+	slow := &unstructured.Unstructured{}
+	slow.SetAPIVersion(ref.APIVersion)
+	slow.SetKind(ref.Kind)
+	slow.SetName(ref.Name)
+
+	failed := &partialApplyError{}
+	failed.add(*slow, &applyTimeoutError{msg: "boom"})
+
+	ws := []v1alpha2.WorkloadStatus{{Reference: ref}, {Reference: v1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "fast"}}}
+	setWorkloadConditions(ws, errors.Wrap(failed, errApplyComponents))
+
+	want := []v1alpha2.WorkloadStatus{{Reference: ref}, {Reference: v1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "fast"}}}
+	want[0].SetConditions(v1alpha2.ApplyTimeout("boom"))
+	if diff := cmp.Diff(want, ws); diff != "" {
+		t.Errorf("setWorkloadConditions(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestResolveWorkloadRefPath(t *testing.T) {
+	w := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "app"}},
+				},
+			},
+		},
+	}}
+	w.SetName("workload")
+
+	type want struct {
+		path string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		td     v1alpha2.TraitDefinitionSpec
+		w      *unstructured.Unstructured
+		want   want
+	}{
+		"NoPlaceholder": {
+			reason: "Paths without a placeholder should be returned unchanged",
+			td:     v1alpha2.TraitDefinitionSpec{WorkloadRefPath: "spec.workloadRef", TargetContainerName: "app"},
+			w:      w,
+			want:   want{path: "spec.workloadRef"},
+		},
+		"NoTargetContainerName": {
+			reason: "Paths with a placeholder should require a target container name",
+			td:     v1alpha2.TraitDefinitionSpec{WorkloadRefPath: "spec.containers.{containerName}.workloadRef"},
+			w:      w,
+			want: want{
+				err: errors.Errorf(errFmtNoTargetContainer, "spec.containers.{containerName}.workloadRef"),
+			},
+		},
+		"ContainerNotFound": {
+			reason: "Target containers that do not exist in the workload's pod template should be rejected",
+			td:     v1alpha2.TraitDefinitionSpec{WorkloadRefPath: "spec.containers.{containerName}.workloadRef", TargetContainerName: "sidecar"},
+			w:      w,
+			want: want{
+				err: errors.Errorf(errFmtTargetContainerNotFound, "workload", "sidecar"),
+			},
+		},
+		"Substituted": {
+			reason: "The placeholder should be replaced with the target container name",
+			td:     v1alpha2.TraitDefinitionSpec{WorkloadRefPath: "spec.containers.{containerName}.workloadRef", TargetContainerName: "app"},
+			w:      w,
+			want:   want{path: "spec.containers.app.workloadRef"},
+		},
+		"NoPodTemplate": {
+			reason: "Workloads without a pod template should not be checked for the target container",
+			td:     v1alpha2.TraitDefinitionSpec{WorkloadRefPath: "spec.containers.{containerName}.workloadRef", TargetContainerName: "app"},
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   want{path: "spec.containers.app.workloadRef"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveWorkloadRefPath(tc.td, tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveWorkloadRefPath(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.path, got); diff != "" {
+				t.Errorf("\n%s\nresolveWorkloadRefPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
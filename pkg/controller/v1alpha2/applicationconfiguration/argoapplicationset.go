@@ -0,0 +1,244 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// ApplicationSet error strings.
+const (
+	errFmtGetApplicationSet       = "cannot get Argo CD ApplicationSet %q"
+	errFmtRenderApplicationSet    = "cannot render components of Argo CD ApplicationSet %q"
+	errFmtUnsupportedGenerator    = "generator %d has unsupported types %q; supported types are %q"
+	errFmtInvalidGenerator        = "generator %d is invalid"
+	errFmtNoApplicationName       = "application %d has no name"
+	errFmtParseApplicationSetComp = "cannot parse component of application %q"
+)
+
+// ArgoApplicationSetGroupVersionKind is the kind of an Argo CD ApplicationSet.
+var ArgoApplicationSetGroupVersionKind = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}
+
+// supportedGenerators are the kinds of ApplicationSet generator that can be
+// evaluated without access to anything but the ApplicationSet.
+var supportedGenerators = []string{"list", "matrix"}
+
+// An ArgoApplicationSetRenderer renders the components of an
+// ApplicationConfiguration from the applications generated by an Argo CD
+// ApplicationSet. Each application produces the component encoded in the
+// oam.dev/component annotation of the ApplicationSet's template, or a
+// component named after the application if there is none. The components of
+// each ApplicationSet are cached until its resource version changes.
+type ArgoApplicationSetRenderer struct {
+	client client.Reader
+
+	mu    sync.Mutex
+	cache map[types.NamespacedName]cachedApplicationSet
+}
+
+type cachedApplicationSet struct {
+	resourceVersion string
+	components      []v1alpha2.ApplicationConfigurationComponent
+}
+
+// NewArgoApplicationSetRenderer returns an ArgoApplicationSetRenderer that
+// reads ApplicationSets using the supplied client.
+func NewArgoApplicationSetRenderer(c client.Reader) *ArgoApplicationSetRenderer {
+	return &ArgoApplicationSetRenderer{client: c, cache: make(map[types.NamespacedName]cachedApplicationSet)}
+}
+
+// Render the components of the supplied ApplicationSet.
+func (r *ArgoApplicationSetRenderer) Render(ctx context.Context, nn types.NamespacedName) ([]v1alpha2.ApplicationConfigurationComponent, error) {
+	as := &unstructured.Unstructured{}
+	as.SetGroupVersionKind(ArgoApplicationSetGroupVersionKind)
+	if err := r.client.Get(ctx, nn, as); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetApplicationSet, nn.Name)
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[nn]
+	r.mu.Unlock()
+	if !ok || cached.resourceVersion != as.GetResourceVersion() {
+		comps, err := renderApplicationSet(as)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderApplicationSet, nn.Name)
+		}
+		cached = cachedApplicationSet{resourceVersion: as.GetResourceVersion(), components: comps}
+		r.mu.Lock()
+		r.cache[nn] = cached
+		r.mu.Unlock()
+	}
+
+	// Callers may modify the components they are returned.
+	out := make([]v1alpha2.ApplicationConfigurationComponent, len(cached.components))
+	for i := range cached.components {
+		cached.components[i].DeepCopyInto(&out[i])
+	}
+	return out, nil
+}
+
+// renderApplicationSet returns the component of each application generated
+// by the supplied ApplicationSet.
+func renderApplicationSet(as *unstructured.Unstructured) ([]v1alpha2.ApplicationConfigurationComponent, error) {
+	generators, _, err := unstructured.NestedSlice(as.Object, "spec", "generators")
+	if err != nil {
+		return nil, err
+	}
+	name, _, err := unstructured.NestedString(as.Object, "spec", "template", "metadata", "name")
+	if err != nil {
+		return nil, err
+	}
+	annotations, _, err := unstructured.NestedStringMap(as.Object, "spec", "template", "metadata", "annotations")
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]map[string]interface{}, 0)
+	for i, g := range generators {
+		p, err := generateParams(i, g)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p...)
+	}
+
+	comps := make([]v1alpha2.ApplicationConfigurationComponent, 0, len(params))
+	for i, p := range params {
+		app := substituteParams(name, p)
+		if app == "" {
+			return nil, errors.Errorf(errFmtNoApplicationName, i)
+		}
+		doc, ok := annotations[oam.AnnotationApplicationSetComponent]
+		if !ok {
+			comps = append(comps, v1alpha2.ApplicationConfigurationComponent{ComponentName: app})
+			continue
+		}
+		parsed, err := parseYAMLDocument(substituteParams(doc, p))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseApplicationSetComp, app)
+		}
+		comps = append(comps, parsed...)
+	}
+	return comps, nil
+}
+
+// generateParams returns the parameters of each application produced by the
+// supplied generator. Only generators that need nothing but the
+// ApplicationSet to be evaluated are supported: list generators, and matrix
+// generators that combine them.
+func generateParams(i int, generator interface{}) ([]map[string]interface{}, error) {
+	g, ok := generator.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf(errFmtInvalidGenerator, i)
+	}
+
+	if l, ok := g["list"].(map[string]interface{}); ok {
+		elements, _, err := unstructured.NestedSlice(l, "elements")
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidGenerator, i)
+		}
+		params := make([]map[string]interface{}, 0, len(elements))
+		for _, e := range elements {
+			p, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf(errFmtInvalidGenerator, i)
+			}
+			params = append(params, p)
+		}
+		return params, nil
+	}
+
+	if m, ok := g["matrix"].(map[string]interface{}); ok {
+		children, _, err := unstructured.NestedSlice(m, "generators")
+		if err != nil || len(children) != 2 {
+			return nil, errors.Errorf(errFmtInvalidGenerator, i)
+		}
+		a, err := generateParams(i, children[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := generateParams(i, children[1])
+		if err != nil {
+			return nil, err
+		}
+		params := make([]map[string]interface{}, 0, len(a)*len(b))
+		for _, pa := range a {
+			for _, pb := range b {
+				p := make(map[string]interface{}, len(pa)+len(pb))
+				for k, v := range pa {
+					p[k] = v
+				}
+				for k, v := range pb {
+					p[k] = v
+				}
+				params = append(params, p)
+			}
+		}
+		return params, nil
+	}
+
+	kinds := make([]string, 0, len(g))
+	for k := range g {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return nil, errors.Errorf(errFmtUnsupportedGenerator, i, kinds, supportedGenerators)
+}
+
+// applicationSetParam matches the {{param}} placeholders of ApplicationSet
+// templates. It does not match Go templates like {{.AppConfigName}}.
+var applicationSetParam = regexp.MustCompile(`{{\s*([a-zA-Z0-9_\-]+(?:\.[a-zA-Z0-9_\-]+)*)\s*}}`)
+
+// substituteParams replaces each {{param}} placeholder in the supplied string
+// with the value of the supplied parameter, if there is one. Nested
+// parameters are referenced using dots, e.g. {{metadata.labels.env}}.
+func substituteParams(s string, params map[string]interface{}) string {
+	return applicationSetParam.ReplaceAllStringFunc(s, func(m string) string {
+		path := strings.Split(applicationSetParam.FindStringSubmatch(m)[1], ".")
+		v, found, err := unstructured.NestedFieldNoCopy(params, path...)
+		if err != nil || !found {
+			return m
+		}
+		switch t := v.(type) {
+		case string:
+			return t
+		case map[string]interface{}, []interface{}:
+			b, err := json.Marshal(t)
+			if err != nil {
+				return m
+			}
+			return string(b)
+		default:
+			return fmt.Sprintf("%v", t)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestArgoApplicationSetRenderer(t *testing.T) {
+	errBoom := errors.New("boom")
+	nn := types.NamespacedName{Namespace: "ns", Name: "apps"}
+
+	appSet := func(rv string, generators []interface{}, annotations map[string]interface{}) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			u.SetResourceVersion(rv)
+			meta := map[string]interface{}{"name": "{{cluster}}-guestbook"}
+			if annotations != nil {
+				meta["annotations"] = annotations
+			}
+			u.Object["spec"] = map[string]interface{}{
+				"generators": generators,
+				"template":   map[string]interface{}{"metadata": meta},
+			}
+			return nil
+		}
+	}
+	list := map[string]interface{}{"list": map[string]interface{}{"elements": []interface{}{
+		map[string]interface{}{"cluster": "dev"},
+		map[string]interface{}{"cluster": "prod"},
+	}}}
+
+	type want struct {
+		components []v1alpha2.ApplicationConfigurationComponent
+		err        error
+	}
+	cases := map[string]struct {
+		reason   string
+		client   *test.MockClient
+		renderer func(c *test.MockClient) *ArgoApplicationSetRenderer
+		want     want
+	}{
+		"GetError": {
+			reason: "Errors getting the ApplicationSet should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetApplicationSet, "apps")},
+		},
+		"ListGenerator": {
+			reason: "A component named after each application should be rendered from a list generator",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("1", []interface{}{list}, nil))},
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "dev-guestbook"},
+				{ComponentName: "prod-guestbook"},
+			}},
+		},
+		"ComponentAnnotation": {
+			reason: "The parameters of each application should be substituted into the component annotation",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("1", []interface{}{list}, map[string]interface{}{
+				oam.AnnotationApplicationSetComponent: "componentName: guestbook\nrevisionName: guestbook-{{ cluster }}\n",
+			}))},
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "guestbook", RevisionName: "guestbook-dev"},
+				{ComponentName: "guestbook", RevisionName: "guestbook-prod"},
+			}},
+		},
+		"MatrixGenerator": {
+			reason: "A component should be rendered for each combination of the parameters of a matrix generator",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("1", []interface{}{
+				map[string]interface{}{"matrix": map[string]interface{}{"generators": []interface{}{
+					list,
+					map[string]interface{}{"list": map[string]interface{}{"elements": []interface{}{
+						map[string]interface{}{"region": "eu"},
+					}}},
+				}}},
+			}, map[string]interface{}{
+				oam.AnnotationApplicationSetComponent: "componentName: '{{cluster}}-{{region}}'",
+			}))},
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "dev-eu"},
+				{ComponentName: "prod-eu"},
+			}},
+		},
+		"UnsupportedGenerator": {
+			reason: "Generators that cannot be evaluated from the ApplicationSet alone should return an error",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("1", []interface{}{
+				map[string]interface{}{"git": map[string]interface{}{}},
+			}, nil))},
+			want: want{err: errors.Wrapf(errors.Errorf(errFmtUnsupportedGenerator, 0, []string{"git"}, supportedGenerators), errFmtRenderApplicationSet, "apps")},
+		},
+		"Cached": {
+			reason: "Components should be served from the cache while the resource version of the ApplicationSet is unchanged",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("1", []interface{}{list}, nil))},
+			renderer: func(c *test.MockClient) *ArgoApplicationSetRenderer {
+				r := NewArgoApplicationSetRenderer(c)
+				r.cache[nn] = cachedApplicationSet{
+					resourceVersion: "1",
+					components:      []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "cached"}},
+				}
+				return r
+			},
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "cached"}}},
+		},
+		"Stale": {
+			reason: "Components should be rendered again when the resource version of the ApplicationSet changes",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, appSet("2", []interface{}{list}, nil))},
+			renderer: func(c *test.MockClient) *ArgoApplicationSetRenderer {
+				r := NewArgoApplicationSetRenderer(c)
+				r.cache[nn] = cachedApplicationSet{
+					resourceVersion: "1",
+					components:      []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "cached"}},
+				}
+				return r
+			},
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "dev-guestbook"},
+				{ComponentName: "prod-guestbook"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewArgoApplicationSetRenderer(tc.client)
+			if tc.renderer != nil {
+				r = tc.renderer(tc.client)
+			}
+			comps, err := r.Render(context.Background(), nn)
+			got := want{components: comps, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Render(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSubstituteParams(t *testing.T) {
+	params := map[string]interface{}{
+		"cluster":  "dev",
+		"replicas": int64(3),
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"env": "staging"}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   string
+	}{
+		"Params": {
+			reason: "Placeholders should be replaced by the values of their parameters",
+			s:      "{{cluster}}-{{ replicas }}",
+			want:   "dev-3",
+		},
+		"NestedParams": {
+			reason: "Nested parameters should be referenced using dots",
+			s:      "{{metadata.labels.env}}",
+			want:   "staging",
+		},
+		"UnknownParams": {
+			reason: "Placeholders without a parameter should be left as is",
+			s:      "{{region}}",
+			want:   "{{region}}",
+		},
+		"GoTemplates": {
+			reason: "Go templates should be left for the component variables to substitute",
+			s:      "{{.AppConfigName}}-{{cluster}}",
+			want:   "{{.AppConfigName}}-dev",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := substituteParams(tc.s, params)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsubstituteParams(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Batch apply error strings.
+const (
+	errGetCurrent      = "cannot get current object"
+	errServerSideApply = "cannot server-side apply object"
+	errWaitBatch       = "cannot wait to apply batch"
+)
+
+// A serverSideApplicator applies objects using server-side apply, forcing
+// ownership of the fields it sets. Fields set by other field managers, e.g.
+// annotations added by other controllers, are preserved by the API server.
+type serverSideApplicator struct {
+	client  client.Client
+	manager string
+}
+
+// NewServerSideApplicator returns an applicator that applies objects using
+// server-side apply as the supplied field manager.
+func NewServerSideApplicator(c client.Client, manager string) resource.Applicator {
+	return &serverSideApplicator{client: c, manager: manager}
+}
+
+// Apply the supplied object. The current object is only read if there are
+// apply options to check it against, e.g. that it is controllable.
+func (a *serverSideApplicator) Apply(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+	if len(ao) > 0 {
+		m, err := meta.Accessor(o)
+		if err != nil {
+			return errors.Wrap(err, errGetCurrent)
+		}
+		current := o.DeepCopyObject()
+		err = a.client.Get(ctx, types.NamespacedName{Namespace: m.GetNamespace(), Name: m.GetName()}, current)
+		if resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errGetCurrent)
+		}
+		if err == nil {
+			for _, fn := range ao {
+				if err := fn(ctx, current, o); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return errors.Wrap(a.client.Patch(ctx, o, client.Apply, client.ForceOwnership, client.FieldOwner(a.manager)), errServerSideApply)
+}
+
+// A BatchApplicator applies batches of objects in parallel, which is much
+// faster than applying them one at a time when there are many of them, e.g.
+// the traits of an ApplicationConfiguration with many components. Objects are
+// grouped by kind, and the groups are applied in order of kind so that the
+// requests made for a batch are predictable.
+type BatchApplicator struct {
+	wrapped resource.Applicator
+	size    int
+}
+
+// NewBatchApplicator returns an applicator that applies batches of objects
+// using the supplied applicator, with up to the supplied number of requests in
+// flight at once.
+func NewBatchApplicator(a resource.Applicator, size int) *BatchApplicator {
+	return &BatchApplicator{wrapped: a, size: size}
+}
+
+// Apply the supplied object. BatchApplicator may be used anywhere a
+// resource.Applicator is; objects applied one at a time are passed straight
+// to the wrapped applicator.
+func (a *BatchApplicator) Apply(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+	return a.wrapped.Apply(ctx, o, ao...)
+}
+
+// ApplyBatch applies the supplied objects. All objects are applied even if
+// some fail to apply, in which case a *BatchApplyError is returned.
+func (a *BatchApplicator) ApplyBatch(ctx context.Context, objs []runtime.Object, ao ...resource.ApplyOption) error {
+	groups := make(map[string][]int)
+	kinds := make([]string, 0)
+	for i, o := range objs {
+		k := o.GetObjectKind().GroupVersionKind().String()
+		if _, ok := groups[k]; !ok {
+			kinds = append(kinds, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	sort.Strings(kinds)
+
+	errs := make([]error, len(objs))
+	l := newApplyLimiter(a.size)
+	for _, k := range kinds {
+		done := make(chan struct{}, len(groups[k]))
+		started := 0
+		for _, i := range groups[k] {
+			if err := l.Acquire(ctx); err != nil {
+				errs[i] = errors.Wrap(err, errWaitBatch)
+				continue
+			}
+			started++
+			go func(i int) {
+				defer func() { done <- struct{}{} }()
+				defer l.Release()
+				errs[i] = a.wrapped.Apply(ctx, objs[i], ao...)
+			}(i)
+		}
+		for ; started > 0; started-- {
+			<-done
+		}
+	}
+
+	failed := &BatchApplyError{errs: errs}
+	for _, err := range errs {
+		if err != nil {
+			return failed
+		}
+	}
+	return nil
+}
+
+// A BatchApplyError indicates that one or more objects of a batch could not be
+// applied. The remaining objects were applied.
+type BatchApplyError struct {
+	// errs of each object of the batch, in the order in which the objects
+	// were supplied. Objects that were applied have a nil error.
+	errs []error
+}
+
+// Errors returns the error of each object of the batch, in the order in which
+// the objects were supplied. Objects that were applied have a nil error.
+func (e *BatchApplyError) Errors() []error {
+	return e.errs
+}
+
+func (e *BatchApplyError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestServerSideApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+	mustBeController := resource.ApplyOption(func(_ context.Context, current, _ runtime.Object) error {
+		if metav1.GetControllerOf(current.(metav1.Object)) == nil {
+			return errBoom
+		}
+		return nil
+	})
+
+	type want struct {
+		patched bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		ao     []resource.ApplyOption
+		want   want
+	}{
+		"NoOptions": {
+			reason: "Objects should be server-side applied without being read if there are no apply options",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{patched: true},
+		},
+		"NotFound": {
+			reason: "Apply options should not be checked against objects that do not exist",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool")),
+			ao:     []resource.ApplyOption{mustBeController},
+			want:   want{patched: true},
+		},
+		"OptionRejected": {
+			reason: "Objects should not be applied if an apply option rejects the current object",
+			get:    test.NewMockGetFn(nil),
+			ao:     []resource.ApplyOption{mustBeController},
+			want:   want{err: errBoom},
+		},
+		"GetError": {
+			reason: "Errors getting the current object should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			ao:     []resource.ApplyOption{mustBeController},
+			want:   want{err: errors.Wrap(errBoom, errGetCurrent)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			patched := false
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockPatch: func(_ context.Context, _ runtime.Object, p client.Patch, _ ...client.PatchOption) error {
+					patched = p == client.Apply
+					return nil
+				},
+			}
+			o := &unstructured.Unstructured{}
+			o.SetName("cool")
+			err := NewServerSideApplicator(c, "oam").Apply(context.Background(), o, tc.ao...)
+			if diff := cmp.Diff(tc.want, want{patched: patched, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\na.Apply(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// batchObjects returns the supplied number of objects, alternating between two
+// kinds.
+func batchObjects(n int) []runtime.Object {
+	objs := make([]runtime.Object, n)
+	for i := range objs {
+		o := &unstructured.Unstructured{}
+		o.SetAPIVersion("example.org/v1")
+		o.SetKind([]string{"Alpha", "Beta"}[i%2])
+		o.SetName(fmt.Sprintf("trait-%d", i))
+		objs[i] = o
+	}
+	return objs
+}
+
+// slowApplicator returns an applicator that takes the supplied time to apply
+// an object, like a request to the API server would.
+func slowApplicator(d time.Duration) resource.Applicator {
+	return resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+		time.Sleep(d)
+		return nil
+	})
+}
+
+func TestBatchApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+	objs := batchObjects(10)
+
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	kinds := make([]string, 0)
+	a := NewBatchApplicator(resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		kinds = append(kinds, o.GetObjectKind().GroupVersionKind().Kind)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if n := o.(*unstructured.Unstructured).GetName(); n == "trait-3" || n == "trait-6" {
+			return errBoom
+		}
+		return nil
+	}), 3)
+
+	err := a.ApplyBatch(context.Background(), objs)
+	be, ok := err.(*BatchApplyError)
+	if !ok {
+		t.Fatalf("a.ApplyBatch(...): want *BatchApplyError, got %#v", err)
+	}
+	want := make([]error, len(objs))
+	want[3], want[6] = errBoom, errBoom
+	if diff := cmp.Diff(want, be.Errors(), test.EquateErrors()); diff != "" {
+		t.Errorf("be.Errors(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("boom; boom", be.Error()); diff != "" {
+		t.Errorf("be.Error(): -want, +got:\n%s", diff)
+	}
+	if most > 3 {
+		t.Errorf("a.ApplyBatch(...): want at most 3 objects applied at once, got %d", most)
+	}
+	if most < 2 {
+		t.Errorf("a.ApplyBatch(...): want objects applied concurrently, got %d at once", most)
+	}
+	wantKinds := []string{"Alpha", "Alpha", "Alpha", "Alpha", "Alpha", "Beta", "Beta", "Beta", "Beta", "Beta"}
+	if diff := cmp.Diff(wantKinds, kinds); diff != "" {
+		t.Errorf("a.ApplyBatch(...): want objects applied grouped by kind: -want, +got:\n%s", diff)
+	}
+
+	if err := NewBatchApplicator(slowApplicator(0), 3).ApplyBatch(context.Background(), objs); err != nil {
+		t.Errorf("a.ApplyBatch(...): want nil error when all objects are applied, got %s", err)
+	}
+}
+
+func TestApplyTraitBatch(t *testing.T) {
+	errBoom := errors.New("boom")
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion("v")
+	w.SetKind("workload")
+	w.SetNamespace("ns")
+	w.SetName("workload")
+	traits := make([]unstructured.Unstructured, 0)
+	for _, o := range batchObjects(4) {
+		traits = append(traits, *o.(*unstructured.Unstructured))
+	}
+	wl := &Workload{Workload: w, Traits: traits}
+
+	a := &workloads{
+		rawClient: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil),
+			MockPatch: func(_ context.Context, o runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+				u := o.(*unstructured.Unstructured)
+				if u.GetName() == "trait-1" {
+					return errBoom
+				}
+				u.SetUID(types.UID("uid-" + u.GetName()))
+				return nil
+			},
+		},
+		batchSize: 2,
+	}
+	failed := &partialApplyError{}
+	if err := a.applyTraitBatch(context.Background(), nil, wl, runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "workload"}, failed); err != nil {
+		t.Fatalf("a.applyTraitBatch(...): %s", err)
+	}
+
+	want := &partialApplyError{}
+	want.add(traits[1], errors.Wrapf(errors.Wrap(errBoom, errServerSideApply), errFmtApplyTrait, "example.org/v1", "Beta", "trait-1"))
+	if diff := cmp.Diff(want.Error(), failed.Error()); diff != "" {
+		t.Errorf("a.applyTraitBatch(...): -want, +got:\n%s", diff)
+	}
+	uids := make([]string, 0, len(wl.Traits))
+	for _, tr := range wl.Traits {
+		uids = append(uids, string(tr.GetUID()))
+	}
+	if diff := cmp.Diff([]string{"uid-trait-0", "", "uid-trait-2", "uid-trait-3"}, uids); diff != "" {
+		t.Errorf("a.applyTraitBatch(...): want UIDs of applied traits recorded: -want, +got:\n%s", diff)
+	}
+}
+
+func BenchmarkSequentialApply(b *testing.B) {
+	objs := batchObjects(100)
+	a := slowApplicator(time.Millisecond)
+	for n := 0; n < b.N; n++ {
+		for _, o := range objs {
+			_ = a.Apply(context.Background(), o)
+		}
+	}
+}
+
+func BenchmarkBatchApply(b *testing.B) {
+	objs := batchObjects(100)
+	a := NewBatchApplicator(slowApplicator(time.Millisecond), 10)
+	for n := 0; n < b.N; n++ {
+		_ = a.ApplyBatch(context.Background(), objs)
+	}
+}
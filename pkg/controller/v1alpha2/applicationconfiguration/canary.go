@@ -0,0 +1,279 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Canary weight error strings.
+const (
+	errFmtInvalidCanaryWeight = "invalid %s annotation %q: must be an integer between 0 and 100"
+	errFmtGetVirtualService   = "cannot get virtual service %q"
+	errFmtPatchCanaryWeights  = "cannot patch canary weights of virtual service %q"
+	errFmtSetCanaryWeights    = "cannot set canary weights of virtual service %q"
+	errRoutesNotList          = "spec.http[0].route is not a list"
+	errRouteNotObject         = "spec.http[0].route contains a route that is not an object"
+)
+
+const canaryRoutesPath = "spec.http[0].route"
+
+// IstioVirtualServiceGroupVersionKinds are the kinds of Istio VirtualService
+// whose canary weights are managed per the oam.dev/canary-weight annotation.
+var IstioVirtualServiceGroupVersionKinds = []schema.GroupVersionKind{
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"},
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"},
+}
+
+func isVirtualService(gvk schema.GroupVersionKind) bool {
+	for _, vs := range IstioVirtualServiceGroupVersionKinds {
+		if gvk == vs {
+			return true
+		}
+	}
+	return false
+}
+
+// A VirtualServiceApplier manages the lifecycle of Istio VirtualServices. A
+// VirtualService that exists is updated using a JSON merge patch, so that its
+// canary weights may also be patched independently of the rest of its spec.
+type VirtualServiceApplier struct{}
+
+// Apply the supplied VirtualService of the supplied workload, creating it if
+// it does not exist and patching it otherwise.
+func (a *VirtualServiceApplier) Apply(ctx context.Context, c client.Client, t, _ *unstructured.Unstructured, ao ...resource.ApplyOption) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(t.GroupVersionKind())
+	err := c.Get(ctx, types.NamespacedName{Namespace: t.GetNamespace(), Name: t.GetName()}, current)
+	if kerrors.IsNotFound(err) {
+		return errors.Wrap(c.Create(ctx, t), errCreateObject)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetObject)
+	}
+
+	for _, fn := range ao {
+		if err := fn(ctx, current, t); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(c.Patch(ctx, t, client.Merge), errPatchObject)
+}
+
+// Delete the supplied VirtualService.
+func (a *VirtualServiceApplier) Delete(ctx context.Context, c client.Client, t *unstructured.Unstructured) error {
+	return c.Delete(ctx, t, deleteOptions(t)...)
+}
+
+// canaryWeight returns the canary weight the supplied ApplicationConfiguration
+// is annotated with, if any.
+func canaryWeight(ac metav1.Object) (int64, bool, error) {
+	v, ok := ac.GetAnnotations()[oam.AnnotationCanaryWeight]
+	if !ok {
+		return 0, false, nil
+	}
+	w, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || w < 0 || w > 100 {
+		return 0, false, errors.Errorf(errFmtInvalidCanaryWeight, oam.AnnotationCanaryWeight, v)
+	}
+	return w, true, nil
+}
+
+// setCanaryWeights sets the weights of the routes of the first HTTP route of
+// the supplied VirtualService such that the last route receives the supplied
+// percentage of traffic, and the other routes share the rest evenly. A
+// VirtualService with fewer than two routes is unchanged.
+func setCanaryWeights(vs *unstructured.Unstructured, weight int64) error {
+	p := fieldpath.Pave(vs.UnstructuredContent())
+	v, err := p.GetValue(canaryRoutesPath)
+	if err != nil {
+		// A VirtualService without HTTP routes has nothing to weight.
+		return nil
+	}
+	routes, ok := v.([]interface{})
+	if !ok {
+		return errors.New(errRoutesNotList)
+	}
+	if len(routes) < 2 {
+		return nil
+	}
+
+	stable := int64(len(routes) - 1)
+	share, remainder := (100-weight)/stable, (100-weight)%stable
+	for i := range routes {
+		r, ok := routes[i].(map[string]interface{})
+		if !ok {
+			return errors.New(errRouteNotObject)
+		}
+		switch {
+		case i == len(routes)-1:
+			r["weight"] = weight
+		case i == 0:
+			r["weight"] = share + remainder
+		default:
+			r["weight"] = share
+		}
+	}
+	// The routes are updated in place, rather than set via their field path,
+	// so that their weights remain integers.
+	return nil
+}
+
+// renderCanaryWeights sets the canary weights of the supplied trait per the
+// supplied ApplicationConfiguration, if the trait is a VirtualService and the
+// ApplicationConfiguration is annotated with a canary weight.
+func renderCanaryWeights(ac *v1alpha2.ApplicationConfiguration, t *unstructured.Unstructured) error {
+	if !isVirtualService(t.GroupVersionKind()) {
+		return nil
+	}
+	weight, ok, err := canaryWeight(ac)
+	if err != nil || !ok {
+		return err
+	}
+	return errors.Wrapf(setCanaryWeights(t, weight), errFmtSetCanaryWeights, t.GetName())
+}
+
+// A canaryWeightReconciler patches the canary weights of the VirtualService
+// traits of ApplicationConfigurations when their oam.dev/canary-weight
+// annotation changes, without rendering and applying their components.
+type canaryWeightReconciler struct {
+	client      client.Client
+	partitioner *LeasePartitioner
+	log         logging.Logger
+}
+
+// Reconcile the canary weights of the VirtualServices of an
+// ApplicationConfiguration with its oam.dev/canary-weight annotation.
+func (r *canaryWeightReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling canary weights")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := r.client.Get(ctx, req.NamespacedName, ac); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetAppConfig)
+	}
+	if ac.GetDeletionTimestamp() != nil || (r.partitioner != nil && !r.partitioner.Owns(ac.GetUID())) {
+		return reconcile.Result{}, nil
+	}
+	weight, ok, err := canaryWeight(ac)
+	if err != nil || !ok {
+		// Invalid canary weights are reported when the
+		// ApplicationConfiguration is rendered.
+		return reconcile.Result{}, nil
+	}
+
+	for _, ws := range ac.Status.Workloads {
+		for _, ts := range ws.Traits {
+			if !isVirtualService(schema.FromAPIVersionAndKind(ts.Reference.APIVersion, ts.Reference.Kind)) {
+				continue
+			}
+			if err := r.patchCanaryWeights(ctx, asUnstructured(ts.Reference, ac.GetNamespace()), weight); err != nil {
+				log.Debug("Cannot patch canary weights", "error", err, "requeue-after", time.Now().Add(shortWait))
+				return reconcile.Result{RequeueAfter: shortWait}, nil
+			}
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (r *canaryWeightReconciler) patchCanaryWeights(ctx context.Context, vs *unstructured.Unstructured, weight int64) error {
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: vs.GetNamespace(), Name: vs.GetName()}, vs); err != nil {
+		return errors.Wrapf(resource.IgnoreNotFound(err), errFmtGetVirtualService, vs.GetName())
+	}
+	original := vs.DeepCopy()
+	if err := setCanaryWeights(vs, weight); err != nil {
+		return errors.Wrapf(err, errFmtSetCanaryWeights, vs.GetName())
+	}
+	return errors.Wrapf(r.client.Patch(ctx, vs, client.MergeFrom(original)), errFmtPatchCanaryWeights, vs.GetName())
+}
+
+// canaryWeightChanged accepts only updates that change an
+// ApplicationConfiguration's oam.dev/canary-weight annotation.
+func canaryWeightChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(_ event.CreateEvent) bool { return false },
+		DeleteFunc:  func(_ event.DeleteEvent) bool { return false },
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaOld.GetAnnotations()[oam.AnnotationCanaryWeight] != e.MetaNew.GetAnnotations()[oam.AnnotationCanaryWeight]
+		},
+	}
+}
+
+// ignoreCanaryWeightChanges filters out updates that only change an
+// ApplicationConfiguration's oam.dev/canary-weight annotation, which are
+// handled by the canaryWeightReconciler. Updates to ApplicationConfigurations
+// with a target cluster are always accepted, because the
+// canaryWeightReconciler only patches VirtualServices in this cluster.
+func ignoreCanaryWeightChanges() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o, ok := e.ObjectOld.(*v1alpha2.ApplicationConfiguration)
+			if !ok {
+				return true
+			}
+			n, ok := e.ObjectNew.(*v1alpha2.ApplicationConfiguration)
+			if !ok || n.Spec.TargetCluster != nil {
+				return true
+			}
+			if o.GetAnnotations()[oam.AnnotationCanaryWeight] == n.GetAnnotations()[oam.AnnotationCanaryWeight] {
+				return true
+			}
+			return !reflect.DeepEqual(withoutCanaryWeight(o), withoutCanaryWeight(n))
+		},
+	}
+}
+
+// withoutCanaryWeight returns a copy of the supplied ApplicationConfiguration
+// without its canary weight annotation, or the metadata that changes with
+// every update.
+func withoutCanaryWeight(ac *v1alpha2.ApplicationConfiguration) *v1alpha2.ApplicationConfiguration {
+	c := ac.DeepCopy()
+	a := c.GetAnnotations()
+	delete(a, oam.AnnotationCanaryWeight)
+	if len(a) == 0 {
+		a = nil
+	}
+	c.SetAnnotations(a)
+	c.SetResourceVersion("")
+	c.SetManagedFields(nil)
+	return c
+}
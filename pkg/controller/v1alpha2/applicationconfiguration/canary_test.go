@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// virtualService returns a VirtualService with the supplied route weights.
+func virtualService(weights ...int64) *unstructured.Unstructured {
+	routes := make([]interface{}, len(weights))
+	for i, w := range weights {
+		routes[i] = map[string]interface{}{"destination": map[string]interface{}{"subset": string(rune('a' + i))}, "weight": w}
+	}
+	vs := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"http": []interface{}{map[string]interface{}{"route": routes}}},
+	}}
+	vs.SetGroupVersionKind(IstioVirtualServiceGroupVersionKinds[1])
+	vs.SetNamespace("ns")
+	vs.SetName("vs")
+	return vs
+}
+
+func TestSetCanaryWeights(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		vs     *unstructured.Unstructured
+		weight int64
+		want   *unstructured.Unstructured
+	}{
+		"TwoRoutes": {
+			reason: "The last route should receive the canary weight, and the other the rest",
+			vs:     virtualService(100, 0),
+			weight: 30,
+			want:   virtualService(70, 30),
+		},
+		"ThreeRoutes": {
+			reason: "The stable routes should share the rest, with any remainder going to the first",
+			vs:     virtualService(50, 50, 0),
+			weight: 25,
+			want:   virtualService(38, 37, 25),
+		},
+		"OneRoute": {
+			reason: "A VirtualService with a single route should be unchanged",
+			vs:     virtualService(100),
+			weight: 30,
+			want:   virtualService(100),
+		},
+		"NoHTTPRoutes": {
+			reason: "A VirtualService without HTTP routes should be unchanged",
+			vs:     &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}},
+			weight: 30,
+			want:   &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := setCanaryWeights(tc.vs, tc.weight); err != nil {
+				t.Fatalf("\n%s\nsetCanaryWeights(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.vs); diff != "" {
+				t.Errorf("\n%s\nsetCanaryWeights(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanaryWeight(t *testing.T) {
+	type want struct {
+		weight int64
+		ok     bool
+		err    error
+	}
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        want
+	}{
+		"NotAnnotated": {
+			reason: "An ApplicationConfiguration without the annotation should have no canary weight",
+		},
+		"Valid": {
+			reason:      "A valid annotation should be parsed",
+			annotations: map[string]string{oam.AnnotationCanaryWeight: "30"},
+			want:        want{weight: 30, ok: true},
+		},
+		"OutOfRange": {
+			reason:      "A weight over 100 should be invalid",
+			annotations: map[string]string{oam.AnnotationCanaryWeight: "130"},
+			want:        want{err: errors.Errorf(errFmtInvalidCanaryWeight, oam.AnnotationCanaryWeight, "130")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			weight, ok, err := canaryWeight(ac)
+			got := want{weight: weight, ok: ok, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncanaryWeight(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanaryWeightPredicates(t *testing.T) {
+	ac := func(weight string, modify ...func(*v1alpha2.ApplicationConfiguration)) *v1alpha2.ApplicationConfiguration {
+		a := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: weight}}
+		if weight != "" {
+			a.SetAnnotations(map[string]string{oam.AnnotationCanaryWeight: weight})
+		}
+		for _, fn := range modify {
+			fn(a)
+		}
+		return a
+	}
+	update := func(o, n *v1alpha2.ApplicationConfiguration) event.UpdateEvent {
+		return event.UpdateEvent{MetaOld: o, ObjectOld: o, MetaNew: n, ObjectNew: n}
+	}
+	withComponent := func(a *v1alpha2.ApplicationConfiguration) {
+		a.Spec.Components = []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "new"}}
+	}
+	withTargetCluster := func(a *v1alpha2.ApplicationConfiguration) {
+		a.Spec.TargetCluster = &v1alpha2.TargetCluster{}
+	}
+
+	type want struct {
+		canary     bool
+		appConfigs bool
+	}
+	cases := map[string]struct {
+		reason string
+		e      event.UpdateEvent
+		want   want
+	}{
+		"OnlyWeightChanged": {
+			reason: "A change to only the canary weight should only be handled by the canary weight controller",
+			e:      update(ac("10"), ac("30")),
+			want:   want{canary: true},
+		},
+		"WeightAdded": {
+			reason: "Adding the canary weight should only be handled by the canary weight controller",
+			e:      update(ac(""), ac("30")),
+			want:   want{canary: true},
+		},
+		"SpecChanged": {
+			reason: "A change to the spec should be handled by the ApplicationConfiguration controller",
+			e:      update(ac("30"), ac("30", withComponent)),
+			want:   want{appConfigs: true},
+		},
+		"WeightAndSpecChanged": {
+			reason: "A change to both the canary weight and spec should be handled by both controllers",
+			e:      update(ac("10"), ac("30", withComponent)),
+			want:   want{canary: true, appConfigs: true},
+		},
+		"TargetCluster": {
+			reason: "A change to the canary weight of an ApplicationConfiguration with a target cluster should be rendered",
+			e:      update(ac("10", withTargetCluster), ac("30", withTargetCluster)),
+			want:   want{canary: true, appConfigs: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				canary:     canaryWeightChanged().Update(tc.e),
+				appConfigs: ignoreCanaryWeightChanges().Update(tc.e),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanaryWeightReconcile(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool", Annotations: map[string]string{oam.AnnotationCanaryWeight: "30"}},
+		Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{{
+			Traits: []v1alpha2.WorkloadTrait{
+				{Reference: runtimev1alpha1.TypedReference{APIVersion: "networking.istio.io/v1beta1", Kind: "VirtualService", Name: "vs"}},
+				{Reference: runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "Trait", Name: "other"}},
+			},
+		}}},
+	}
+
+	var patched []*unstructured.Unstructured
+	c := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.ApplicationConfiguration:
+				ac.DeepCopyInto(o)
+			case *unstructured.Unstructured:
+				if key.Name != "vs" {
+					return errors.Errorf("unexpected get of %s", key.Name)
+				}
+				o.Object = virtualService(100, 0).Object
+			}
+			return nil
+		},
+		MockPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+			patched = append(patched, obj.(*unstructured.Unstructured))
+			return nil
+		},
+	}
+	r := &canaryWeightReconciler{client: c, log: logging.NewNopLogger()}
+	got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}})
+	if err != nil {
+		t.Fatalf("r.Reconcile(...): %s", err)
+	}
+	if diff := cmp.Diff(reconcile.Result{}, got); diff != "" {
+		t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]*unstructured.Unstructured{virtualService(70, 30)}, patched); diff != "" {
+		t.Errorf("r.Reconcile(...): want only the VirtualService patched, -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientappv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/cache"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Target cluster error strings.
+const (
+	errFmtGetKubeconfigSecret = "cannot get kubeconfig secret %q"
+	errFmtNoKubeconfig        = "kubeconfig secret %q has no key %q"
+	errFmtNotKubeconfig       = "secret %q is not labelled as a kubeconfig secret"
+	errParseKubeconfig        = "cannot parse kubeconfig"
+	errNewClusterClient       = "cannot create target cluster client"
+	errListAppConfigsSecret   = "cannot list ApplicationConfigurations that may use secret"
+)
+
+// kubeconfigSecrets selects the Secrets from which ApplicationConfigurations
+// may read the kubeconfig of their TargetCluster.
+var kubeconfigSecrets = labels.SelectorFromSet(labels.Set{oam.LabelKubeconfig: "true"})
+
+// CacheSelectors returns the selectors of the objects the
+// ApplicationConfiguration controller reads from its manager's cache that
+// need not be cached in their entirety. Managers should use a cache created by
+// cache.NewSelectorCacheFunc with these selectors, so that e.g. every Secret
+// and ConfigMap in the cluster is not cached.
+func CacheSelectors() []cache.Selector {
+	return []cache.Selector{
+		{Kind: &corev1.Secret{}, Labels: kubeconfigSecrets},
+		{Kind: &corev1.ConfigMap{}, Labels: specSourceConfigMaps},
+	}
+}
+
+// A Cluster to which workloads and traits may be applied.
+type Cluster struct {
+	// Config used to connect to the cluster.
+	Config *rest.Config
+
+	// Client of the cluster.
+	Client client.Client
+}
+
+// A ClusterConnector connects to the cluster targeted by an
+// ApplicationConfiguration.
+type ClusterConnector interface {
+	// Connect to the TargetCluster of the supplied ApplicationConfiguration.
+	Connect(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (*Cluster, error)
+}
+
+// A ClusterConnectorFn connects to the cluster targeted by an
+// ApplicationConfiguration.
+type ClusterConnectorFn func(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (*Cluster, error)
+
+// Connect to the TargetCluster of the supplied ApplicationConfiguration.
+func (fn ClusterConnectorFn) Connect(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (*Cluster, error) {
+	return fn(ctx, ac)
+}
+
+// A secretConnector connects to clusters using the kubeconfig stored in a
+// Secret. Clusters are cached by Secret, and reconnected when the Secret
+// changes.
+type secretConnector struct {
+	client    client.Reader
+	scheme    *runtime.Scheme
+	newClient func(*rest.Config, client.Options) (client.Client, error)
+
+	mu       sync.Mutex
+	clusters map[types.NamespacedName]cachedCluster
+}
+
+type cachedCluster struct {
+	resourceVersion string
+	cluster         *Cluster
+}
+
+func (s *secretConnector) Connect(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (*Cluster, error) {
+	ref := ac.Spec.TargetCluster.KubeconfigSecretRef
+	nn := types.NamespacedName{Namespace: ac.GetNamespace(), Name: ref.Name}
+	sec := &corev1.Secret{}
+	if err := s.client.Get(ctx, nn, sec); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetKubeconfigSecret, ref.Name)
+	}
+	if !kubeconfigSecrets.Matches(labels.Set(sec.GetLabels())) {
+		return nil, errors.Errorf(errFmtNotKubeconfig, ref.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clusters[nn]; ok && c.resourceVersion == sec.GetResourceVersion() {
+		return c.cluster, nil
+	}
+
+	kubeconfig, ok := sec.Data[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoKubeconfig, ref.Name, ref.Key)
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKubeconfig)
+	}
+	c, err := s.newClient(cfg, client.Options{Scheme: s.scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClusterClient)
+	}
+	if s.clusters == nil {
+		s.clusters = make(map[types.NamespacedName]cachedCluster)
+	}
+	s.clusters[nn] = cachedCluster{resourceVersion: sec.GetResourceVersion(), cluster: &Cluster{Config: cfg, Client: c}}
+	return s.clusters[nn].cluster, nil
+}
+
+// inCluster returns a copy of the supplied workloads that applies workloads
+// and traits to the supplied cluster. TraitDefinitions are still read from
+// the cluster of the ApplicationConfiguration.
+func (a *workloads) inCluster(c *Cluster) *workloads {
+	tdc := a.traitDefinitions
+	if tdc == nil {
+		tdc = NewTraitDefinitionCache(a.rawClient, DefaultTraitDefinitionTTL)
+	}
+	// Deployments are scaled using the controller's credentials in the
+	// cluster. Traffic splits fail to apply if no client can be built.
+	var scaler DeploymentScaler
+	if apps, err := clientappv1.NewForConfig(c.Config); err == nil {
+		scaler = &deploymentScaler{client: apps}
+	}
+	return &workloads{
+		client:           NewAnnotationPreservationApplicator(c.Client, resource.NewAPIPatchingApplicator(c.Client), DefaultFieldManager()),
+		rawClient:        c.Client,
+		impersonator:     &restImpersonator{client: c.Client, config: c.Config, scheme: a.scheme},
+		scaler:           scaler,
+		scheme:           a.scheme,
+		traitDefinitions: tdc,
+		traitAppliers:    a.traitAppliers,
+		batchSize:        a.batchSize,
+	}
+}
+
+// A kubeconfigSecretMapper maps a Secret to the ApplicationConfigurations in
+// its namespace that use it to connect to their TargetCluster.
+type kubeconfigSecretMapper struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+var _ handler.Mapper = &kubeconfigSecretMapper{}
+
+// labelSelected returns a predicate that only accepts events for objects
+// matching the supplied label selector. An update is accepted if the object
+// matched the selector before or after it was updated.
+func labelSelected(sel labels.Selector) predicate.Funcs {
+	matches := func(o metav1.Object) bool { return o != nil && sel.Matches(labels.Set(o.GetLabels())) }
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return matches(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return matches(e.MetaOld) || matches(e.MetaNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return matches(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) bool { return matches(e.Meta) },
+	}
+}
+
+func (m *kubeconfigSecretMapper) Map(o handler.MapObject) []reconcile.Request {
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := m.client.List(context.Background(), acs, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+		m.log.Debug(errListAppConfigsSecret, "error", err, "secret", o.Meta.GetName())
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, ac := range acs.Items {
+		if ac.Spec.TargetCluster == nil || ac.Spec.TargetCluster.KubeconfigSecretRef.Name != o.Meta.GetName() {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}})
+	}
+	return reqs
+}
+
+// target returns a client of the cluster to which the workloads and traits of
+// the supplied ApplicationConfiguration are applied, and an applicator that
+// applies them there. The Reconciler's applicator is returned as is if it
+// does not support target clusters.
+func (r *Reconciler) target(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (client.Client, WorkloadApplicator, error) {
+	if ac.Spec.TargetCluster == nil {
+		return r.client, r.workloads, nil
+	}
+	c, err := r.clusters.Connect(ctx, ac)
+	if err != nil {
+		return nil, nil, err
+	}
+	w, ok := r.workloads.(*workloads)
+	if !ok {
+		return c.Client, r.workloads, nil
+	}
+	return c.Client, w.inCluster(c), nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.org
+contexts:
+- name: remote
+  context:
+    cluster: remote
+current-context: remote
+`
+
+func TestSecretConnector(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	target := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			TargetCluster: &v1alpha2.TargetCluster{
+				KubeconfigSecretRef: v1alpha2.SecretKeySelector{Name: "remote", Key: "kubeconfig"},
+			},
+		},
+	}
+	secret := func(rv string, data map[string][]byte) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			s := obj.(*corev1.Secret)
+			s.SetLabels(map[string]string{oam.LabelKubeconfig: "true"})
+			s.SetResourceVersion(rv)
+			s.Data = data
+			return nil
+		}
+	}
+
+	type want struct {
+		host    string
+		clients int
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		gets   []error
+		secret []func(obj runtime.Object) error
+		want   want
+	}{
+		"GetSecretError": {
+			reason: "Errors getting the kubeconfig secret should be returned",
+			gets:   []error{errBoom},
+			secret: []func(obj runtime.Object) error{nil},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetKubeconfigSecret, "remote")},
+		},
+		"NotKubeconfigSecret": {
+			reason: "A secret that is not labelled as a kubeconfig secret should return an error",
+			gets:   []error{nil},
+			secret: []func(obj runtime.Object) error{func(obj runtime.Object) error { return nil }},
+			want:   want{err: errors.Errorf(errFmtNotKubeconfig, "remote")},
+		},
+		"MissingKey": {
+			reason: "A kubeconfig secret without the referenced key should return an error",
+			gets:   []error{nil},
+			secret: []func(obj runtime.Object) error{secret("1", nil)},
+			want:   want{err: errors.Errorf(errFmtNoKubeconfig, "remote", "kubeconfig")},
+		},
+		"Cached": {
+			reason: "A client should only be created once for an unchanged secret",
+			gets:   []error{nil, nil},
+			secret: []func(obj runtime.Object) error{
+				secret("1", map[string][]byte{"kubeconfig": []byte(testKubeconfig)}),
+				secret("1", map[string][]byte{"kubeconfig": []byte(testKubeconfig)}),
+			},
+			want: want{host: "https://remote.example.org", clients: 1},
+		},
+		"Refreshed": {
+			reason: "A new client should be created when the secret changes",
+			gets:   []error{nil, nil},
+			secret: []func(obj runtime.Object) error{
+				secret("1", map[string][]byte{"kubeconfig": []byte(testKubeconfig)}),
+				secret("2", map[string][]byte{"kubeconfig": []byte(testKubeconfig)}),
+			},
+			want: want{host: "https://remote.example.org", clients: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			call := 0
+			clients := 0
+			s := &secretConnector{
+				client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					i := call
+					call++
+					if tc.gets[i] != nil {
+						return tc.gets[i]
+					}
+					return tc.secret[i](obj)
+				}},
+				newClient: func(_ *rest.Config, _ client.Options) (client.Client, error) {
+					clients++
+					return &test.MockClient{}, nil
+				},
+			}
+
+			var c *Cluster
+			var err error
+			for range tc.gets {
+				c, err = s.Connect(context.Background(), target)
+			}
+			got := want{clients: clients, err: err}
+			if c != nil {
+				got.host = c.Config.Host
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Connect(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeconfigSecretMapper(t *testing.T) {
+	using := v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "using"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			TargetCluster: &v1alpha2.TargetCluster{KubeconfigSecretRef: v1alpha2.SecretKeySelector{Name: "remote", Key: "kubeconfig"}},
+		},
+	}
+	other := v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			TargetCluster: &v1alpha2.TargetCluster{KubeconfigSecretRef: v1alpha2.SecretKeySelector{Name: "elsewhere", Key: "kubeconfig"}},
+		},
+	}
+	local := v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "local"}}
+
+	m := &kubeconfigSecretMapper{
+		client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.ApplicationConfigurationList).Items = []v1alpha2.ApplicationConfiguration{using, other, local}
+			return nil
+		})},
+		log: logging.NewNopLogger(),
+	}
+
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "remote"}}
+	got := m.Map(handler.MapObject{Meta: s, Object: s})
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "using"}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("m.Map(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestLabelSelected(t *testing.T) {
+	labelled := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{oam.LabelKubeconfig: "true"}}}
+	unlabelled := &corev1.Secret{}
+
+	cases := map[string]struct {
+		reason string
+		e      interface{}
+		want   bool
+	}{
+		"CreateLabelled": {
+			reason: "Creating a labelled object should be accepted",
+			e:      event.CreateEvent{Meta: labelled, Object: labelled},
+			want:   true,
+		},
+		"CreateUnlabelled": {
+			reason: "Creating an unlabelled object should be ignored",
+			e:      event.CreateEvent{Meta: unlabelled, Object: unlabelled},
+			want:   false,
+		},
+		"UpdateLabelRemoved": {
+			reason: "Removing the label from an object should be accepted",
+			e:      event.UpdateEvent{MetaOld: labelled, ObjectOld: labelled, MetaNew: unlabelled, ObjectNew: unlabelled},
+			want:   true,
+		},
+		"DeleteUnlabelled": {
+			reason: "Deleting an unlabelled object should be ignored",
+			e:      event.DeleteEvent{Meta: unlabelled, Object: unlabelled},
+			want:   false,
+		},
+	}
+
+	p := labelSelected(kubeconfigSecrets)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got bool
+			switch e := tc.e.(type) {
+			case event.CreateEvent:
+				got = p.Create(e)
+			case event.UpdateEvent:
+				got = p.Update(e)
+			case event.DeleteEvent:
+				got = p.Delete(e)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\np(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -70,27 +70,34 @@ func (c *ComponentHandler) Generic(_ event.GenericEvent, _ workqueue.RateLimitin
 	// so we need to do nothing here.
 }
 
-func isMatch(appConfigs *v1alpha2.ApplicationConfigurationList, compName string) (bool, types.NamespacedName) {
+// matchingAppConfigs returns the ApplicationConfigurations that reference the
+// latest revision of the named component. ApplicationConfigurations that
+// reference a specific revision are unaffected by changes to the component.
+func matchingAppConfigs(appConfigs *v1alpha2.ApplicationConfigurationList, compName string) []types.NamespacedName {
+	var matches []types.NamespacedName
 	for _, app := range appConfigs.Items {
 		for _, comp := range app.Spec.Components {
 			if comp.ComponentName == compName {
-				return true, types.NamespacedName{Namespace: app.Namespace, Name: app.Name}
+				matches = append(matches, types.NamespacedName{Namespace: app.Namespace, Name: app.Name})
+				break
 			}
 		}
 	}
-	return false, types.NamespacedName{}
+	return matches
 }
 
 func (c *ComponentHandler) getRelatedAppConfig(object metav1.Object) []reconcile.Request {
 	var appConfigs v1alpha2.ApplicationConfigurationList
-	err := c.client.List(context.Background(), &appConfigs)
+	// A component may only be referenced by ApplicationConfigurations in its
+	// own namespace.
+	err := c.client.List(context.Background(), &appConfigs, client.InNamespace(object.GetNamespace()))
 	if err != nil {
 		c.l.Info(fmt.Sprintf("error list all applicationConfigurations %v", err))
 		return nil
 	}
 	var reqs []reconcile.Request
-	if match, namespaceName := isMatch(&appConfigs, object.GetName()); match {
-		reqs = append(reqs, reconcile.Request{NamespacedName: namespaceName})
+	for _, nn := range matchingAppConfigs(&appConfigs, object.GetName()) {
+		reqs = append(reqs, reconcile.Request{NamespacedName: nn})
 	}
 	return reqs
 }
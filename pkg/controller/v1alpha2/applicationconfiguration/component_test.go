@@ -23,6 +23,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -166,7 +167,7 @@ func TestConstructExtract(t *testing.T) {
 	}
 }
 
-func TestIsMatch(t *testing.T) {
+func TestMatchingAppConfigs(t *testing.T) {
 	var appConfigs v1alpha2.ApplicationConfigurationList
 	appConfigs.Items = []v1alpha2.ApplicationConfiguration{
 		{
@@ -176,23 +177,60 @@ func TestIsMatch(t *testing.T) {
 			},
 		},
 		{
-			ObjectMeta: metav1.ObjectMeta{Name: "bar-app", Namespace: "bar-namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "bar-app", Namespace: "foo-namespace"},
 			Spec: v1alpha2.ApplicationConfigurationSpec{
-				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "bar"}},
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "bar"}, {ComponentName: "foo"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pinned-app", Namespace: "foo-namespace"},
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{RevisionName: "foo-v1"}},
 			},
 		},
 	}
-	got, namespaceNamed := isMatch(&appConfigs, "foo")
-	assert.Equal(t, true, got)
-	assert.Equal(t, types.NamespacedName{Name: "foo-app", Namespace: "foo-namespace"}, namespaceNamed)
-	got, _ = isMatch(&appConfigs, "foo1")
-	assert.Equal(t, false, got)
-	got, namespaceNamed = isMatch(&appConfigs, "bar")
-	assert.Equal(t, true, got)
-	assert.Equal(t, types.NamespacedName{Name: "bar-app", Namespace: "bar-namespace"}, namespaceNamed)
+	got := matchingAppConfigs(&appConfigs, "foo")
+	assert.Equal(t, []types.NamespacedName{
+		{Name: "foo-app", Namespace: "foo-namespace"},
+		{Name: "bar-app", Namespace: "foo-namespace"},
+	}, got)
+	got = matchingAppConfigs(&appConfigs, "bar")
+	assert.Equal(t, []types.NamespacedName{{Name: "bar-app", Namespace: "foo-namespace"}}, got)
+	got = matchingAppConfigs(&appConfigs, "foo1")
+	assert.Empty(t, got)
 	appConfigs.Items = nil
-	got, _ = isMatch(&appConfigs, "foo")
-	assert.Equal(t, false, got)
+	got = matchingAppConfigs(&appConfigs, "foo")
+	assert.Empty(t, got)
+}
+
+func TestGetRelatedAppConfig(t *testing.T) {
+	instance := ComponentHandler{
+		client: &test.MockClient{
+			MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				for _, o := range opts {
+					o.ApplyToList(lo)
+				}
+				assert.Equal(t, "biz", lo.Namespace)
+				l := obj.(*v1alpha2.ApplicationConfigurationList)
+				for _, name := range []string{"app1", "app2"} {
+					l.Items = append(l.Items, v1alpha2.ApplicationConfiguration{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: name},
+						Spec: v1alpha2.ApplicationConfigurationSpec{
+							Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "comp1"}},
+						},
+					})
+				}
+				return nil
+			},
+		},
+		l: logging.NewLogrLogger(ctrl.Log.WithName("test")),
+	}
+	got := instance.getRelatedAppConfig(&metav1.ObjectMeta{Namespace: "biz", Name: "comp1"})
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "app1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "app2"}},
+	}, got)
 }
 
 func TestUnpackRevisionData(t *testing.T) {
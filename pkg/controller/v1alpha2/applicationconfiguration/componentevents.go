@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Component event error strings.
+const (
+	errListComponentEvents      = "cannot list component events"
+	errFmtGetEmittingWorkload   = "cannot get workload of component %q to observe its events"
+	errFmtCreateComponentEvent  = "cannot record event %q of component %q"
+	errFmtLastReceivedEvent     = "cannot determine last event received by component %q"
+	maxComponentEventNamePrefix = 253 - 17
+)
+
+// recordComponentEvents records a ComponentEvent for each event emitted by the
+// workloads of the supplied ApplicationConfiguration's components. A workload
+// emits an event its component declares by changing the value of its
+// events.oam.dev/<name> annotation. The workloads are read using the supplied
+// reader, and the ComponentEvents created using the supplied client.
+func recordComponentEvents(ctx context.Context, r client.Reader, c client.Client, ac *v1alpha2.ApplicationConfiguration) error {
+	emits := make(map[string][]string)
+	for _, acc := range ac.Spec.Components {
+		if len(acc.Emits) > 0 {
+			emits[acc.ComponentName] = acc.Emits
+		}
+	}
+	if len(emits) == 0 {
+		return nil
+	}
+
+	for _, ws := range ac.Status.Workloads {
+		names := emits[ws.ComponentName]
+		if len(names) == 0 {
+			continue
+		}
+		w := asUnstructured(ws.Reference, ac.GetNamespace())
+		err := r.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ws.Reference.Name}, w)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetEmittingWorkload, ws.ComponentName)
+		}
+		for _, name := range names {
+			value := w.GetAnnotations()[oam.AnnotationEventPrefix+name]
+			if value == "" {
+				continue
+			}
+			e := &v1alpha2.ComponentEvent{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       ac.GetNamespace(),
+					Name:            componentEventName(ac.GetName(), ws.ComponentName, name, value),
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+				},
+				Spec: v1alpha2.ComponentEventSpec{
+					ApplicationConfigurationName: ac.GetName(),
+					ComponentName:                ws.ComponentName,
+					EventName:                    name,
+					Value:                        value,
+					Timestamp:                    metav1.Now(),
+				},
+			}
+			// Each value of an event's annotation is recorded only once.
+			if err := c.Create(ctx, e); err != nil && !kerrors.IsAlreadyExists(err) {
+				return errors.Wrapf(err, errFmtCreateComponentEvent, name, ws.ComponentName)
+			}
+		}
+	}
+	return nil
+}
+
+// componentEventName returns the name of the ComponentEvent that records the
+// supplied value of the supplied event of the supplied component. Names are
+// deterministic, so that each value is only recorded once.
+func componentEventName(appConfig, component, event, value string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(component))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(event))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(value))
+	if len(appConfig) > maxComponentEventNamePrefix {
+		appConfig = appConfig[:maxComponentEventNamePrefix]
+	}
+	return fmt.Sprintf("%s-%016x", appConfig, h.Sum64())
+}
+
+// lastReceivedEvent returns the name of the latest of the supplied
+// ApplicationConfiguration's ComponentEvents that match any of the supplied
+// subscriptions, or an empty string if there is none.
+func lastReceivedEvent(ctx context.Context, c client.Reader, ac *v1alpha2.ApplicationConfiguration, subs []v1alpha2.ComponentEventSubscription) (string, error) {
+	if len(subs) == 0 {
+		return "", nil
+	}
+	l := &v1alpha2.ComponentEventList{}
+	if err := c.List(ctx, l, client.InNamespace(ac.GetNamespace())); err != nil {
+		return "", errors.Wrap(err, errListComponentEvents)
+	}
+
+	subscribed := make(map[v1alpha2.ComponentEventSubscription]bool, len(subs))
+	for _, s := range subs {
+		subscribed[s] = true
+	}
+	var last *v1alpha2.ComponentEvent
+	for i := range l.Items {
+		e := &l.Items[i]
+		if !metav1.IsControlledBy(e, ac) {
+			continue
+		}
+		if !subscribed[v1alpha2.ComponentEventSubscription{ComponentName: e.Spec.ComponentName, EventName: e.Spec.EventName}] {
+			continue
+		}
+		if last == nil || last.Spec.Timestamp.Before(&e.Spec.Timestamp) ||
+			(last.Spec.Timestamp.Equal(&e.Spec.Timestamp) && last.GetName() < e.GetName()) {
+			last = e
+		}
+	}
+	if last == nil {
+		return "", nil
+	}
+	return last.GetName(), nil
+}
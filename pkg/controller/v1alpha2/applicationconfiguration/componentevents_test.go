@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestRecordComponentEvents(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app", UID: "app-uid"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+			{ComponentName: "db", Emits: []string{"migrated", "backedup"}},
+			{ComponentName: "web"},
+		}},
+		Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{
+			{ComponentName: "db", Reference: runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "k", Name: "db"}},
+			{ComponentName: "web", Reference: runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "k", Name: "web"}},
+		}},
+	}
+	emitted := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		if obj.(*unstructured.Unstructured).GetName() != "db" {
+			t.Errorf("recordComponentEvents(...): want only workloads that emit events to be read")
+		}
+		obj.(*unstructured.Unstructured).SetAnnotations(map[string]string{oam.AnnotationEventPrefix + "migrated": "v2"})
+		return nil
+	})
+	migrated := v1alpha2.ComponentEventSpec{
+		ApplicationConfigurationName: "app",
+		ComponentName:                "db",
+		EventName:                    "migrated",
+		Value:                        "v2",
+	}
+
+	type want struct {
+		created []v1alpha2.ComponentEventSpec
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		create error
+		want   want
+	}{
+		"Emitted": {
+			reason: "An event should be recorded for each emitted event with a value",
+			get:    emitted,
+			want:   want{created: []v1alpha2.ComponentEventSpec{migrated}},
+		},
+		"AlreadyRecorded": {
+			reason: "A value that was already recorded should not be considered an error",
+			get:    emitted,
+			create: kerrors.NewAlreadyExists(schema.GroupResource{}, "event"),
+			want:   want{created: []v1alpha2.ComponentEventSpec{migrated}},
+		},
+		"WorkloadNotFound": {
+			reason: "Workloads that do not exist yet should be skipped",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "db")),
+		},
+		"GetError": {
+			reason: "Errors getting a workload should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetEmittingWorkload, "db")},
+		},
+		"CreateError": {
+			reason: "Errors recording an event should be returned",
+			get:    emitted,
+			create: errBoom,
+			want: want{
+				created: []v1alpha2.ComponentEventSpec{migrated},
+				err:     errors.Wrapf(errBoom, errFmtCreateComponentEvent, "migrated", "db"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created []v1alpha2.ComponentEventSpec
+			c := &test.MockClient{MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+				e := obj.(*v1alpha2.ComponentEvent)
+				if !metav1.IsControlledBy(e, ac) {
+					t.Errorf("recordComponentEvents(...): want event to be controlled by its ApplicationConfiguration")
+				}
+				if e.GetName() != componentEventName("app", "db", "migrated", "v2") {
+					t.Errorf("recordComponentEvents(...): want deterministic event name, got %q", e.GetName())
+				}
+				created = append(created, e.Spec)
+				return tc.create
+			}}
+			err := recordComponentEvents(context.Background(), &test.MockClient{MockGet: tc.get}, c, ac)
+			if diff := cmp.Diff(tc.want, want{created: created, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nrecordComponentEvents(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLastReceivedEvent(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app", UID: "app-uid"}}
+	other := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other", UID: "other-uid"}}
+	now := time.Now()
+	event := func(name string, owner *v1alpha2.ApplicationConfiguration, component, eventName string, age time.Duration) v1alpha2.ComponentEvent {
+		return v1alpha2.ComponentEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+			},
+			Spec: v1alpha2.ComponentEventSpec{ComponentName: component, EventName: eventName, Timestamp: metav1.NewTime(now.Add(-age))},
+		}
+	}
+	list := func(events ...v1alpha2.ComponentEvent) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			obj.(*v1alpha2.ComponentEventList).Items = events
+			return nil
+		}
+	}
+	subs := []v1alpha2.ComponentEventSubscription{{ComponentName: "db", EventName: "migrated"}}
+
+	type want struct {
+		name string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		subs   []v1alpha2.ComponentEventSubscription
+		want   want
+	}{
+		"NoSubscriptions": {
+			reason: "Components without subscriptions should receive no events",
+			list: func(_ context.Context, _ runtime.Object, _ ...client.ListOption) error {
+				t.Errorf("lastReceivedEvent(...): want no events to be listed without subscriptions")
+				return nil
+			},
+		},
+		"Latest": {
+			reason: "The latest subscribed event should be returned",
+			list: list(
+				event("old", ac, "db", "migrated", 2*time.Minute),
+				event("new", ac, "db", "migrated", time.Minute),
+			),
+			subs: subs,
+			want: want{name: "new"},
+		},
+		"Unsubscribed": {
+			reason: "Events that are not subscribed to, or of another ApplicationConfiguration, should be ignored",
+			list: list(
+				event("subscribed", ac, "db", "migrated", 3*time.Minute),
+				event("other-event", ac, "db", "backedup", time.Minute),
+				event("other-component", ac, "web", "migrated", time.Minute),
+				event("other-appconfig", other, "db", "migrated", time.Minute),
+			),
+			subs: subs,
+			want: want{name: "subscribed"},
+		},
+		"NoEvents": {
+			reason: "An empty string should be returned when no subscribed event was emitted",
+			list:   list(),
+			subs:   subs,
+		},
+		"ListError": {
+			reason: "Errors listing events should be returned",
+			list: func(_ context.Context, _ runtime.Object, _ ...client.ListOption) error {
+				return errBoom
+			},
+			subs: subs,
+			want: want{err: errors.Wrap(errBoom, errListComponentEvents)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := lastReceivedEvent(context.Background(), &test.MockClient{MockList: tc.list}, ac, tc.subs)
+			if diff := cmp.Diff(tc.want, want{name: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nlastReceivedEvent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/decrypt"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Decryption error strings.
+const (
+	errNoDecryptor    = "parameter value is encrypted, but no decryptor is configured"
+	errNotSOPSDoc     = "parameter value is marked as encrypted, but is not a SOPS document"
+	errFmtStatConfig  = "cannot read SOPS config %q"
+	errDecryptSOPS    = "cannot decrypt SOPS document"
+	errFmtDecryptPV   = "cannot decrypt parameter %q"
+	errFmtDecryptComp = "cannot decrypt parameter values of component %q"
+)
+
+// sopsMetadataKey is the key of the metadata SOPS adds to the documents it
+// encrypts.
+const sopsMetadataKey = "sops"
+
+// A SpecDecryptor decrypts encrypted parameter values.
+type SpecDecryptor interface {
+	// Decrypt the supplied encrypted document.
+	Decrypt(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// A SpecDecryptorFn decrypts encrypted parameter values.
+type SpecDecryptorFn func(ctx context.Context, encrypted []byte) ([]byte, error)
+
+// Decrypt the supplied encrypted document.
+func (fn SpecDecryptorFn) Decrypt(ctx context.Context, encrypted []byte) ([]byte, error) {
+	return fn(ctx, encrypted)
+}
+
+// A SOPSDecryptor decrypts parameter values that were encrypted by SOPS, e.g.
+// with:
+//
+//	printf 'secret' | sops --encrypt --input-type binary --output-type json /dev/stdin
+//
+// SOPS supports both KMS and PGP keys. The keys that can decrypt a value are
+// recorded in its SOPS metadata; credentials for them must be available to the
+// controller.
+type SOPSDecryptor struct{}
+
+// NewSOPSDecryptor returns a SpecDecryptor that decrypts values encrypted per
+// the supplied SOPS config file, typically named .sops.yaml.
+func NewSOPSDecryptor(config string) (*SOPSDecryptor, error) {
+	if _, err := os.Stat(config); err != nil {
+		return nil, errors.Wrapf(err, errFmtStatConfig, config)
+	}
+	return &SOPSDecryptor{}, nil
+}
+
+// Decrypt the supplied SOPS document.
+func (d *SOPSDecryptor) Decrypt(_ context.Context, encrypted []byte) ([]byte, error) {
+	// The binary format stores the encrypted value under the data key of a
+	// JSON document, and emits the plain value as is.
+	out, err := decrypt.DataWithFormat(encrypted, formats.Binary)
+	return out, errors.Wrap(err, errDecryptSOPS)
+}
+
+// isEncrypted returns true if the supplied parameter value is a SOPS document.
+func isEncrypted(v intstr.IntOrString) bool {
+	if v.Type != intstr.String {
+		return false
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(v.StrVal), &doc); err != nil {
+		return false
+	}
+	_, ok := doc[sopsMetadataKey]
+	return ok
+}
+
+// hasEncrypted returns true if any of the supplied parameter values are
+// marked as encrypted, or were encrypted using age.
+func hasEncrypted(pv []v1alpha2.ComponentParameterValue) bool {
+	for _, v := range pv {
+		if v.Encrypted || isAgeEncrypted(v.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// decryptParameterValues returns a copy of the supplied parameter values with
+// any values that are marked as encrypted replaced by their decrypted content.
+// Values that are not encrypted are returned unchanged.
+func decryptParameterValues(ctx context.Context, d SpecDecryptor, pv []v1alpha2.ComponentParameterValue) ([]v1alpha2.ComponentParameterValue, error) {
+	out := make([]v1alpha2.ComponentParameterValue, len(pv))
+	for i, v := range pv {
+		out[i] = v
+		if !v.Encrypted {
+			continue
+		}
+		if !isEncrypted(v.Value) {
+			return nil, errors.Wrapf(errors.New(errNotSOPSDoc), errFmtDecryptPV, v.Name)
+		}
+		if d == nil {
+			return nil, errors.Wrapf(errors.New(errNoDecryptor), errFmtDecryptPV, v.Name)
+		}
+		plain, err := d.Decrypt(ctx, []byte(v.Value.StrVal))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecryptPV, v.Name)
+		}
+		out[i].Value = intstr.FromString(string(plain))
+		out[i].Encrypted = false
+	}
+	return out, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestDecryptParameterValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	encrypted := `{"data":"ENC[AES256_GCM,data:c2VjcmV0,type:str]","sops":{"version":"3.6.1"}}`
+
+	pv := []v1alpha2.ComponentParameterValue{
+		{Name: "plain", Value: intstr.FromString("plain")},
+		{Name: "port", Value: intstr.FromInt(8080)},
+		{Name: "json", Value: intstr.FromString(`{"data":"notencrypted"}`)},
+		{Name: "secret", Value: intstr.FromString(encrypted), Encrypted: true},
+	}
+
+	type want struct {
+		pv  []v1alpha2.ComponentParameterValue
+		err error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		pv        []v1alpha2.ComponentParameterValue
+		decryptor SpecDecryptor
+		want      want
+	}{
+		"NotSOPSDocument": {
+			reason: "Values that are marked as encrypted must be SOPS documents",
+			pv: []v1alpha2.ComponentParameterValue{
+				{Name: "json", Value: intstr.FromString(`{"data":"notencrypted"}`), Encrypted: true},
+			},
+			decryptor: SpecDecryptorFn(func(_ context.Context, _ []byte) ([]byte, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrapf(errors.New(errNotSOPSDoc), errFmtDecryptPV, "json"),
+			},
+		},
+		"NoDecryptor": {
+			reason: "Encrypted values cannot be decrypted if no decryptor is configured",
+			want: want{
+				err: errors.Wrapf(errors.New(errNoDecryptor), errFmtDecryptPV, "secret"),
+			},
+		},
+		"DecryptError": {
+			reason: "Errors decrypting a value should be returned",
+			decryptor: SpecDecryptorFn(func(_ context.Context, _ []byte) ([]byte, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtDecryptPV, "secret"),
+			},
+		},
+		"Decrypted": {
+			reason: "Only values that are marked as encrypted should be decrypted",
+			decryptor: SpecDecryptorFn(func(_ context.Context, encrypted []byte) ([]byte, error) {
+				if string(encrypted) != pv[3].Value.StrVal {
+					return nil, errors.Errorf("decrypted %s", encrypted)
+				}
+				return []byte("secret"), nil
+			}),
+			want: want{
+				pv: []v1alpha2.ComponentParameterValue{
+					{Name: "plain", Value: intstr.FromString("plain")},
+					{Name: "port", Value: intstr.FromInt(8080)},
+					{Name: "json", Value: intstr.FromString(`{"data":"notencrypted"}`)},
+					{Name: "secret", Value: intstr.FromString("secret")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := pv
+			if tc.pv != nil {
+				in = tc.pv
+			}
+			got, err := decryptParameterValues(context.Background(), tc.decryptor, in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndecryptParameterValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pv, got); diff != "" {
+				t.Errorf("\n%s\ndecryptParameterValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if pv[3].Value.StrVal != encrypted {
+				t.Errorf("\n%s\ndecryptParameterValues(...): supplied parameter values were modified", tc.reason)
+			}
+		})
+	}
+}
+
+func TestHasEncrypted(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pv     []v1alpha2.ComponentParameterValue
+		want   bool
+	}{
+		"Plain": {
+			reason: "Plain values should not be reported as encrypted",
+			pv:     []v1alpha2.ComponentParameterValue{{Name: "plain", Value: intstr.FromString("plain")}},
+			want:   false,
+		},
+		"SOPS": {
+			reason: "Values marked as encrypted should be reported as encrypted",
+			pv:     []v1alpha2.ComponentParameterValue{{Name: "secret", Value: intstr.FromString("sops"), Encrypted: true}},
+			want:   true,
+		},
+		"Age": {
+			reason: "Values encrypted using age should be reported as encrypted",
+			pv:     []v1alpha2.ComponentParameterValue{{Name: "secret", Value: intstr.FromString(ageValuePrefix + "c2VjcmV0")}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, hasEncrypted(tc.pv)); diff != "" {
+				t.Errorf("\n%s\nhasEncrypted(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Dependency error strings.
+const (
+	errFmtDependencyCycle = "components depend on each other's workload kinds: %s"
+)
+
+// orderByDependencies sorts the supplied workloads so that each follows the
+// workloads of the kinds its component depends on, then splits them into
+// those that may be applied and those that are held back because a workload
+// they depend on has not yet been applied or is not yet healthy. It returns an
+// error if components depend on each other's kinds, directly or otherwise.
+func orderByDependencies(ac *v1alpha2.ApplicationConfiguration, w []Workload) ([]Workload, []Workload, error) {
+	dependsOn := make(map[string][]string)
+	for _, acc := range ac.Spec.Components {
+		if len(acc.DependsOnKinds) == 0 {
+			continue
+		}
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = ExtractComponentName(acc.RevisionName)
+		}
+		dependsOn[name] = acc.DependsOnKinds
+	}
+	if len(dependsOn) == 0 {
+		return w, nil, nil
+	}
+
+	byKind := make(map[string][]int)
+	for i, wl := range w {
+		k := workloadKind(wl)
+		byKind[k] = append(byKind[k], i)
+	}
+
+	// deps[i] are the indices of the workloads that workload i depends on.
+	deps := make([][]int, len(w))
+	dependents := make([][]int, len(w))
+	for i, wl := range w {
+		for _, k := range dependsOn[wl.ComponentName] {
+			for _, j := range byKind[k] {
+				if j == i {
+					continue
+				}
+				deps[i] = append(deps[i], j)
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	// Kahn's algorithm, always picking the earliest ready workload so that
+	// workloads keep the order of their components where possible.
+	remaining := make([]int, len(w))
+	ready := make([]int, 0, len(w))
+	for i := range w {
+		remaining[i] = len(deps[i])
+		if remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	order := make([]int, 0, len(w))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+		for _, d := range dependents[i] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	if len(order) < len(w) {
+		cyclic := make([]string, 0, len(w)-len(order))
+		for i := range w {
+			if remaining[i] > 0 {
+				cyclic = append(cyclic, w[i].ComponentName)
+			}
+		}
+		return nil, nil, errors.Errorf(errFmtDependencyCycle, strings.Join(cyclic, ", "))
+	}
+
+	waiting := make(map[int]bool)
+	released := make([]Workload, 0, len(w))
+	held := make([]Workload, 0)
+	for _, i := range order {
+		for _, j := range deps[i] {
+			if waiting[j] || !isHealthy(ac.Status.Workloads, w[j]) {
+				waiting[i] = true
+				break
+			}
+		}
+		if waiting[i] {
+			held = append(held, w[i])
+			continue
+		}
+		released = append(released, w[i])
+	}
+	return released, held, nil
+}
+
+// workloadKind returns the kind of the supplied workload in the form used by
+// DependsOnKinds, i.e. its API version and kind separated by a slash.
+func workloadKind(w Workload) string {
+	return w.Workload.GetAPIVersion() + "/" + w.Workload.GetKind()
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestOrderByDependencies(t *testing.T) {
+	workload := func(name, apiVersion, kind string) Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion(apiVersion)
+		w.SetKind(kind)
+		w.SetName(name)
+		return Workload{ComponentName: name, ComponentRevisionName: name + "-v1", Workload: w}
+	}
+	db := workload("db", "apps/v1", "StatefulSet")
+	pvc := workload("pvc", "v1", "PersistentVolumeClaim")
+	web := workload("web", "apps/v1", "Deployment")
+	all := []Workload{web, db, pvc}
+
+	healthy := func(w Workload) v1alpha2.WorkloadStatus {
+		return v1alpha2.WorkloadStatus{
+			ComponentName:         w.ComponentName,
+			ComponentRevisionName: w.ComponentRevisionName,
+			Reference: runtimev1alpha1.TypedReference{
+				APIVersion: w.Workload.GetAPIVersion(),
+				Kind:       w.Workload.GetKind(),
+				Name:       w.Workload.GetName(),
+			},
+		}
+	}
+
+	ac := func(acc []v1alpha2.ApplicationConfigurationComponent, ws ...v1alpha2.WorkloadStatus) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			Spec:   v1alpha2.ApplicationConfigurationSpec{Components: acc},
+			Status: v1alpha2.ApplicationConfigurationStatus{Workloads: ws},
+		}
+	}
+	chain := []v1alpha2.ApplicationConfigurationComponent{
+		{ComponentName: "web", DependsOnKinds: []string{"apps/v1/StatefulSet"}},
+		{RevisionName: "db-v1", DependsOnKinds: []string{"v1/PersistentVolumeClaim"}},
+		{ComponentName: "pvc"},
+	}
+
+	type want struct {
+		released []Workload
+		held     []Workload
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"NoDependencies": {
+			reason: "Workloads should be released in their original order when no component has dependencies",
+			ac:     ac([]v1alpha2.ApplicationConfigurationComponent{{ComponentName: "web"}, {ComponentName: "db"}, {ComponentName: "pvc"}}),
+			want:   want{released: all},
+		},
+		"NothingApplied": {
+			reason: "Only workloads without dependencies should be released when nothing has been applied",
+			ac:     ac(chain),
+			want:   want{released: []Workload{pvc}, held: []Workload{db, web}},
+		},
+		"DependencyHealthy": {
+			reason: "Workloads should be released once the workloads they depend on are healthy, in dependency order",
+			ac:     ac(chain, healthy(pvc)),
+			want:   want{released: []Workload{pvc, db}, held: []Workload{web}},
+		},
+		"AllHealthy": {
+			reason: "All workloads should be released in dependency order once their dependencies are healthy",
+			ac:     ac(chain, healthy(pvc), healthy(db)),
+			want:   want{released: []Workload{pvc, db, web}},
+		},
+		"UnknownKind": {
+			reason: "Dependencies on kinds no component renders should be ignored",
+			ac:     ac([]v1alpha2.ApplicationConfigurationComponent{{ComponentName: "web", DependsOnKinds: []string{"v1/ConfigMap"}}}),
+			want:   want{released: all},
+		},
+		"Cycle": {
+			reason: "Components that depend on each other's kinds should return an error",
+			ac: ac([]v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "web", DependsOnKinds: []string{"apps/v1/StatefulSet"}},
+				{ComponentName: "db", DependsOnKinds: []string{"apps/v1/Deployment"}},
+			}),
+			want: want{err: errors.Errorf(errFmtDependencyCycle, "web, db")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			released, held, err := orderByDependencies(tc.ac, all)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\norderByDependencies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.released, released, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\norderByDependencies(...): -want released, +got released:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.held, held, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\norderByDependencies(...): -want held, +got held:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Drift error strings.
+const (
+	errFmtDecodeLastAppliedSpec = "cannot decode last applied spec of workload %q"
+	errFmtGetDriftedWorkload    = "cannot get workload %q to check it for drift"
+	errFmtRemediateDrift        = "cannot re-apply last applied spec of workload %q"
+	errFmtDrifted               = "workloads %q do not match their last applied spec"
+)
+
+// A driftedWorkload is a workload that no longer matches the spec it was last
+// applied with.
+type driftedWorkload struct {
+	name      string
+	component string
+
+	// desired is the last applied spec of the workload, stripped of the
+	// metadata set by the API server.
+	desired *unstructured.Unstructured
+}
+
+// driftedWorkloads returns the workloads recorded in the status of the
+// supplied ApplicationConfiguration, including the copies applied to the
+// namespaces selected by its namespace selector, whose live state no longer
+// matches their last applied spec. Workloads without a last applied spec, or
+// whose last applied spec was truncated, and workloads that do not exist are
+// skipped.
+func driftedWorkloads(ctx context.Context, c client.Reader, ac *v1alpha2.ApplicationConfiguration) ([]driftedWorkload, error) {
+	drifted, err := driftedInNamespace(ctx, c, ac.GetNamespace(), ac.Status.Workloads)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(ac.Status.NamespaceStatuses))
+	for ns := range ac.Status.NamespaceStatuses {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		d, err := driftedInNamespace(ctx, c, ns, ac.Status.NamespaceStatuses[ns].Workloads)
+		if err != nil {
+			return nil, err
+		}
+		// Copies are named after their namespace, given that they share
+		// the name of the workload they were copied from.
+		for i := range d {
+			d[i].name = ns + "/" + d[i].name
+		}
+		drifted = append(drifted, d...)
+	}
+	return drifted, nil
+}
+
+func driftedInNamespace(ctx context.Context, c client.Reader, ns string, status []v1alpha2.WorkloadStatus) ([]driftedWorkload, error) {
+	drifted := make([]driftedWorkload, 0)
+	for _, ws := range status {
+		if ws.LastAppliedSpec == nil || len(ws.LastAppliedSpec.Raw) == 0 {
+			continue
+		}
+		applied := &unstructured.Unstructured{}
+		if err := applied.UnmarshalJSON(ws.LastAppliedSpec.Raw); err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeLastAppliedSpec, ws.Reference.Name)
+		}
+		if applied.GetAnnotations()[oam.AnnotationSpecTruncated] == "true" {
+			continue
+		}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(applied.GroupVersionKind())
+		err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: ws.Reference.Name}, live)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetDriftedWorkload, ws.Reference.Name)
+		}
+
+		desired := desiredState(applied, ns)
+		for k, v := range desired.Object {
+			if k == "apiVersion" || k == "kind" || k == "metadata" {
+				continue
+			}
+			if !isSubset(v, live.Object[k]) {
+				drifted = append(drifted, driftedWorkload{name: ws.Reference.Name, component: ws.ComponentName, desired: desired})
+				break
+			}
+		}
+	}
+	return drifted, nil
+}
+
+// desiredState returns the supplied last applied spec without its status, and
+// without the metadata set by the API server, so that it may be applied
+// again.
+func desiredState(applied *unstructured.Unstructured, namespace string) *unstructured.Unstructured {
+	d := &unstructured.Unstructured{Object: make(map[string]interface{}, len(applied.Object))}
+	for k, v := range applied.Object {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		d.Object[k] = v
+	}
+	d.SetName(applied.GetName())
+	d.SetNamespace(namespace)
+	d.SetLabels(applied.GetLabels())
+	d.SetAnnotations(applied.GetAnnotations())
+	d.SetOwnerReferences(applied.GetOwnerReferences())
+	return d
+}
+
+// isSubset returns true if every field of the supplied desired value has the
+// same value in the supplied live value. Fields of the live value that are
+// not in the desired value, for example because they were defaulted by the
+// API server, are ignored.
+func isSubset(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && live == nil
+		}
+		for k, v := range d {
+			if !isSubset(v, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return len(d) == 0 && live == nil
+		}
+		if len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, live)
+	}
+}
+
+// An applicatorFn returns the applicator used to apply the supplied workload.
+type applicatorFn func(ctx context.Context, wl Workload) (resource.Applicator, error)
+
+// driftApplicatorFor returns a function that returns the applicator with which
+// the supplied workload applicator would apply a workload, impersonating the
+// service account its component applies as, if any. Drifted workloads are
+// otherwise re-applied using the supplied client.
+func driftApplicatorFor(c client.Client, wa WorkloadApplicator) applicatorFn {
+	if w, ok := wa.(*workloads); ok {
+		return w.applicatorFor
+	}
+	return func(context.Context, Workload) (resource.Applicator, error) {
+		return resource.NewAPIPatchingApplicator(c), nil
+	}
+}
+
+// handleDrift checks whether the workloads of the supplied
+// ApplicationConfiguration match the spec they were last applied with, and
+// handles those that do not per its drift policy. Drifted workloads are
+// re-applied using the applicator returned for them, so that they are applied
+// as the service account their component applies as, if any.
+func (r *Reconciler) handleDrift(ctx context.Context, c client.Reader, applicatorFor applicatorFn, ac *v1alpha2.ApplicationConfiguration) error {
+	drifted, err := driftedWorkloads(ctx, c, ac)
+	if err != nil {
+		return err
+	}
+	if len(drifted) == 0 {
+		if ac.GetCondition(v1alpha2.TypeDrifted).Status == corev1.ConditionTrue {
+			ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionFalse, v1alpha2.ReasonNoDrift, ""))
+		}
+		return nil
+	}
+
+	names := make([]string, len(drifted))
+	for i := range drifted {
+		names[i] = drifted[i].name
+	}
+	msg := fmt.Sprintf(errFmtDrifted, names)
+
+	switch ac.Spec.DriftPolicy {
+	case v1alpha2.DriftPolicyRemediate:
+		ao := []resource.ApplyOption{}
+		if ac.Spec.TargetCluster == nil {
+			ao = append(ao, resource.MustBeControllableBy(ac.GetUID()))
+		}
+		for _, d := range drifted {
+			a, err := applicatorFor(ctx, Workload{ComponentName: d.component, Workload: d.desired, ServiceAccountName: applyAs(ac, d.component)})
+			if err != nil {
+				ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, msg))
+				return errors.Wrapf(err, errFmtImpersonate, d.name)
+			}
+			if err := a.Apply(ctx, d.desired, ao...); err != nil {
+				ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, msg))
+				return errors.Wrapf(err, errFmtRemediateDrift, d.name)
+			}
+		}
+		r.record.Event(ac, event.Normal(reasonRemediatedDrift, "Re-applied drifted workloads", "workloads", strings.Join(names, ",")))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionFalse, v1alpha2.ReasonDriftRemediated, msg))
+	case v1alpha2.DriftPolicyAlert:
+		r.record.Event(ac, event.Warning(reasonWorkloadsDrifted, errors.New(msg)))
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, msg))
+	default:
+		ac.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, msg))
+	}
+	return nil
+}
+
+// applyAs returns the service account the supplied component of the supplied
+// ApplicationConfiguration is applied as, if any.
+func applyAs(ac *v1alpha2.ApplicationConfiguration, component string) string {
+	for _, acc := range ac.Spec.Components {
+		if acc.ComponentName == component && acc.ApplyAs != nil {
+			return acc.ApplyAs.ServiceAccountRef.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestIsSubset(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		desired interface{}
+		live    interface{}
+		want    bool
+	}{
+		"Defaulted": {
+			reason:  "Fields of the live value that are not desired should be ignored",
+			desired: map[string]interface{}{"replicas": int64(2)},
+			live:    map[string]interface{}{"replicas": int64(2), "revisionHistoryLimit": int64(10)},
+			want:    true,
+		},
+		"Changed": {
+			reason:  "A desired field with a different live value should not be a subset",
+			desired: map[string]interface{}{"replicas": int64(2)},
+			live:    map[string]interface{}{"replicas": int64(3)},
+			want:    false,
+		},
+		"Removed": {
+			reason:  "A desired field that is not live should not be a subset",
+			desired: map[string]interface{}{"replicas": int64(2)},
+			live:    map[string]interface{}{},
+			want:    false,
+		},
+		"ListElementDefaulted": {
+			reason:  "Fields of list elements that are not desired should be ignored",
+			desired: []interface{}{map[string]interface{}{"name": "web"}},
+			live:    []interface{}{map[string]interface{}{"name": "web", "imagePullPolicy": "Always"}},
+			want:    true,
+		},
+		"ListLengthChanged": {
+			reason:  "A list with a different number of live elements should not be a subset",
+			desired: []interface{}{"a"},
+			live:    []interface{}{"a", "b"},
+			want:    false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isSubset(tc.desired, tc.live); got != tc.want {
+				t.Errorf("\n%s\nisSubset(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDriftedWorkloads(t *testing.T) {
+	applied := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":2}}`
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app"}}
+	ac.Status.NamespaceStatuses = map[string]v1alpha2.NamespaceStatus{
+		"other": {Workloads: []v1alpha2.WorkloadStatus{{
+			Reference:       runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			LastAppliedSpec: &runtime.RawExtension{Raw: []byte(applied)},
+		}}},
+	}
+	c := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		if key.Namespace != "other" {
+			return errors.Errorf("got workload in namespace %q", key.Namespace)
+		}
+		obj.(*unstructured.Unstructured).Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+		return nil
+	}}
+
+	got, err := driftedWorkloads(context.Background(), c, ac)
+	if err != nil {
+		t.Fatalf("driftedWorkloads(...): %s", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("driftedWorkloads(...): want the drifted copy of the workload, got %d drifted workloads", len(got))
+	}
+	if diff := cmp.Diff("other/web", got[0].name); diff != "" {
+		t.Errorf("driftedWorkloads(...): -want name, +got name:\n%s", diff)
+	}
+	if diff := cmp.Diff("other", got[0].desired.GetNamespace()); diff != "" {
+		t.Errorf("driftedWorkloads(...): -want namespace, +got namespace:\n%s", diff)
+	}
+}
+
+func TestHandleDrift(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ac := func(p v1alpha2.DriftPolicy, spec string) *v1alpha2.ApplicationConfiguration {
+		a := &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app", UID: "app-uid"},
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				DriftPolicy: p,
+				Components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "web",
+					ApplyAs:       &v1alpha2.ComponentApplyAs{ServiceAccountRef: v1alpha2.ServiceAccountReference{Name: "deployer"}},
+				}},
+			},
+		}
+		a.Status.Workloads = []v1alpha2.WorkloadStatus{{
+			ComponentName:   "web",
+			Reference:       runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			LastAppliedSpec: &runtime.RawExtension{Raw: []byte(spec)},
+		}}
+		return a
+	}
+	applied := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","resourceVersion":"1"},"spec":{"replicas":2}}`
+	truncated := fmt.Sprintf(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","annotations":{%q:"true"}},"truncated":"{"}`, oam.AnnotationSpecTruncated)
+	live := func(replicas int64) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			u.SetName("web")
+			u.Object["spec"] = map[string]interface{}{"replicas": replicas, "revisionHistoryLimit": int64(10)}
+			return nil
+		})
+	}
+	drifted := fmt.Sprintf(errFmtDrifted, []string{"web"})
+
+	type want struct {
+		condition      runtimev1alpha1.Condition
+		serviceAccount string
+		patched        bool
+		err            error
+	}
+	cases := map[string]struct {
+		reason      string
+		ac          *v1alpha2.ApplicationConfiguration
+		get         test.MockGetFn
+		impersonate error
+		patch       error
+		want        want
+	}{
+		"NoDrift": {
+			reason: "Workloads that only differ by defaulted fields should not be drifted",
+			ac:     ac(v1alpha2.DriftPolicyInform, applied),
+			get:    live(2),
+			want:   want{},
+		},
+		"Truncated": {
+			reason: "Workloads whose last applied spec was truncated should be skipped",
+			ac:     ac(v1alpha2.DriftPolicyInform, truncated),
+			get:    live(3),
+			want:   want{},
+		},
+		"NotFound": {
+			reason: "Workloads that do not exist should be skipped",
+			ac:     ac(v1alpha2.DriftPolicyInform, applied),
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "web")),
+			want:   want{},
+		},
+		"Inform": {
+			reason: "The inform policy should set the Drifted condition",
+			ac:     ac(v1alpha2.DriftPolicyInform, applied),
+			get:    live(3),
+			want:   want{condition: v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, drifted)},
+		},
+		"Alert": {
+			reason: "The alert policy should set the Drifted condition",
+			ac:     ac(v1alpha2.DriftPolicyAlert, applied),
+			get:    live(3),
+			want:   want{condition: v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, drifted)},
+		},
+		"Remediate": {
+			reason: "The remediate policy should re-apply the drifted workloads as the service account their component applies as",
+			ac:     ac(v1alpha2.DriftPolicyRemediate, applied),
+			get:    live(3),
+			want: want{
+				condition:      v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionFalse, v1alpha2.ReasonDriftRemediated, drifted),
+				serviceAccount: "deployer",
+				patched:        true,
+			},
+		},
+		"ImpersonateError": {
+			reason:      "Errors impersonating the service account of drifted workloads should be returned",
+			ac:          ac(v1alpha2.DriftPolicyRemediate, applied),
+			get:         live(3),
+			impersonate: errBoom,
+			want: want{
+				condition:      v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, drifted),
+				serviceAccount: "deployer",
+				err:            errors.Wrapf(errBoom, errFmtImpersonate, "web"),
+			},
+		},
+		"RemediateError": {
+			reason: "Errors re-applying drifted workloads should be returned",
+			ac:     ac(v1alpha2.DriftPolicyRemediate, applied),
+			get:    live(3),
+			patch:  errBoom,
+			want: want{
+				condition:      v1alpha2.NewCondition(v1alpha2.TypeDrifted, corev1.ConditionTrue, v1alpha2.ReasonDrifted, drifted),
+				serviceAccount: "deployer",
+				patched:        true,
+				err:            errors.Wrapf(errBoom, errFmtRemediateDrift, "web"),
+			},
+		},
+		"GetError": {
+			reason: "Errors getting workloads should be returned",
+			ac:     ac(v1alpha2.DriftPolicyInform, applied),
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetDriftedWorkload, "web")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			a := resource.ApplyFn(func(_ context.Context, obj runtime.Object, ao ...resource.ApplyOption) error {
+				u := obj.(*unstructured.Unstructured)
+				if u.GetResourceVersion() != "" || u.GetNamespace() != "ns" {
+					t.Errorf("\n%s\nhandleDrift(...): want drifted workloads to be re-applied in their namespace, without their resource version", tc.reason)
+				}
+				if len(ao) != 1 {
+					t.Errorf("\n%s\nhandleDrift(...): want drifted workloads to be re-applied only if controlled by their ApplicationConfiguration", tc.reason)
+				}
+				got.patched = true
+				return tc.patch
+			})
+			applicatorFor := func(_ context.Context, wl Workload) (resource.Applicator, error) {
+				got.serviceAccount = wl.ServiceAccountName
+				return a, tc.impersonate
+			}
+			r := &Reconciler{record: event.NewNopRecorder()}
+			got.err = r.handleDrift(context.Background(), &test.MockClient{MockGet: tc.get}, applicatorFor, tc.ac)
+			got.condition = tc.ac.GetCondition(v1alpha2.TypeDrifted)
+			if tc.want.condition.Type == "" {
+				tc.want.condition = runtimev1alpha1.Condition{Type: v1alpha2.TypeDrifted, Status: "Unknown"}
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nhandleDrift(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
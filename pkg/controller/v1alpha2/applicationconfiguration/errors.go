@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// IsPermanentError returns true if the supplied error will not be resolved by
+// reconciling again until the ApplicationConfiguration, or something it
+// references, is changed. Validation failures and objects the API server
+// rejects as invalid are permanent. All other errors, e.g. conflicts or an
+// unavailable API server, are assumed to be transient.
+func IsPermanentError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if IsCyclicDependency(err) || IsParameterValidationFailed(err) || IsTraitConflict(err) || IsAdoptionConflict(err) ||
+		IsPatchFailed(err) {
+		return true
+	}
+	cause := errors.Cause(err)
+	return kerrors.IsInvalid(cause) || kerrors.IsBadRequest(cause) || kerrors.IsMethodNotSupported(cause)
+}
+
+// reconcileError returns a condition indicating that the supplied error
+// occurred while reconciling, and whether it is transient or permanent.
+func reconcileError(err error) runtimev1alpha1.Condition {
+	if IsPermanentError(err) {
+		return v1alpha2.PermanentReconcileError(err)
+	}
+	return v1alpha2.TransientReconcileError(err)
+}
+
+// errorWait returns how long to wait before reconciling again after the
+// supplied error. Permanent errors are retried after a long wait rather than
+// with increasing backoff, given that retrying sooner will not resolve them.
+func errorWait(err error) time.Duration {
+	if IsPermanentError(err) {
+		return longWait
+	}
+	return shortWait
+}
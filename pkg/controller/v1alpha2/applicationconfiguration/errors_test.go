@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestErrorClassification(t *testing.T) {
+	gr := schema.GroupResource{Group: v1alpha2.Group, Resource: "containerizedworkloads"}
+
+	type want struct {
+		permanent bool
+		reason    runtimev1alpha1.ConditionReason
+		wait      time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Unknown": {
+			reason: "Errors that are not known to be permanent should be transient",
+			err:    errors.New("boom"),
+			want:   want{reason: v1alpha2.ReasonTransientReconcileError, wait: shortWait},
+		},
+		"Conflict": {
+			reason: "Conflicts should be transient",
+			err:    errors.Wrap(kerrors.NewConflict(gr, "cool", errors.New("boom")), errApplyComponents),
+			want:   want{reason: v1alpha2.ReasonTransientReconcileError, wait: shortWait},
+		},
+		"ServiceUnavailable": {
+			reason: "An unavailable API server should be transient",
+			err:    errors.Wrap(kerrors.NewServiceUnavailable("boom"), errApplyComponents),
+			want:   want{reason: v1alpha2.ReasonTransientReconcileError, wait: shortWait},
+		},
+		"Invalid": {
+			reason: "Objects the API server rejects as invalid should be permanent",
+			err:    errors.Wrap(kerrors.NewInvalid(v1alpha2.ContainerizedWorkloadGroupVersionKind.GroupKind(), "cool", field.ErrorList{}), errApplyComponents),
+			want:   want{permanent: true, reason: v1alpha2.ReasonPermanentReconcileError, wait: longWait},
+		},
+		"ParameterValidationFailed": {
+			reason: "Parameter validation failures should be permanent",
+			err:    errors.Wrap(&parameterValidationError{errs: field.ErrorList{field.Required(field.NewPath("spec"), "")}}, errRenderComponents),
+			want:   want{permanent: true, reason: v1alpha2.ReasonPermanentReconcileError, wait: longWait},
+		},
+		"PatchFailed": {
+			reason: "Patches that cannot be applied should be permanent",
+			err:    errors.Wrap(&patchError{component: "cool", err: errors.New("boom")}, errRenderComponents),
+			want:   want{permanent: true, reason: v1alpha2.ReasonPermanentReconcileError, wait: longWait},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				permanent: IsPermanentError(tc.err),
+				reason:    reconcileError(tc.err).Reason,
+				wait:      errorWait(tc.err),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nclassification: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// finalizerWorkloads is added to ApplicationConfigurations whose workloads
+// must be deleted before they are.
+const finalizerWorkloads = "finalizer.core.oam.dev/workloads"
+
+// Finalization error strings.
+const (
+	errAddFinalizer          = "cannot add finalizer"
+	errRemoveFinalizer       = "cannot remove finalizer"
+	errDeleteWorkloads       = "cannot delete workloads"
+	errDeleteNamespaceCopies = "cannot delete copies of workloads in selected namespaces"
+	errFmtGetDeleting        = "cannot get deleting workload %q"
+	errFmtRemaining          = "workloads still exist: %s"
+	errFmtDeletionTimeout    = "workloads were not deleted within %s; removing finalizer anyway"
+)
+
+// Finalization event reasons.
+const (
+	reasonCannotDeleteWorkloads = "CannotDeleteWorkloads"
+	reasonDeletionTimedOut      = "WorkloadDeletionTimedOut"
+)
+
+// A FinalizerAwarePruner deletes the workloads and traits of a deleted
+// ApplicationConfiguration, and reports the workloads that still exist, for
+// example because their finalizers are waiting for external resources to be
+// deleted.
+type FinalizerAwarePruner struct {
+	pruner  ComponentPruner
+	timeout time.Duration
+}
+
+// NewFinalizerAwarePruner returns a FinalizerAwarePruner that deletes
+// workloads and traits using the supplied ComponentPruner, and that gives up
+// waiting for them once an ApplicationConfiguration has been deleting for
+// the supplied timeout.
+func NewFinalizerAwarePruner(p ComponentPruner, timeout time.Duration) *FinalizerAwarePruner {
+	return &FinalizerAwarePruner{pruner: p, timeout: timeout}
+}
+
+// Prune deletes the workloads and traits of the supplied
+// ApplicationConfiguration from the supplied cluster, returning those
+// workloads that have not yet been fully deleted.
+func (p *FinalizerAwarePruner) Prune(ctx context.Context, target client.Client, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	statuses := map[string][]v1alpha2.WorkloadStatus{ac.GetNamespace(): ac.Status.Workloads}
+	for ns, s := range ac.Status.NamespaceStatuses {
+		statuses[ns] = s.Workloads
+	}
+
+	remaining := make([]string, 0)
+	for ns, ws := range statuses {
+		if err := p.pruner.Prune(ctx, target, ns, ws); err != nil {
+			return nil, err
+		}
+		for _, s := range ws {
+			// Revision workloads are owned by their ControllerRevision
+			// rather than the ApplicationConfiguration.
+			if IsRevisionWorkload(s) {
+				continue
+			}
+			u := asUnstructured(s.Reference, ns)
+			err := target.Get(ctx, types.NamespacedName{Namespace: ns, Name: s.Reference.Name}, u)
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetDeleting, s.Reference.Name)
+			}
+			remaining = append(remaining, fmt.Sprintf("%s %q in namespace %q", s.Reference.Kind, s.Reference.Name, ns))
+		}
+	}
+	return remaining, nil
+}
+
+// TimedOut returns true if the supplied ApplicationConfiguration has been
+// deleting for longer than the timeout at the supplied time.
+func (p *FinalizerAwarePruner) TimedOut(ac *v1alpha2.ApplicationConfiguration, now time.Time) bool {
+	d := ac.GetDeletionTimestamp()
+	return d != nil && now.Sub(d.Time) >= p.timeout
+}
+
+// finalize deletes the workloads of the supplied deleted
+// ApplicationConfiguration, then removes its finalizer once they are gone or
+// the FinalizerAwarePruner times out.
+func (r *Reconciler) finalize(ctx context.Context, log logging.Logger, ac *v1alpha2.ApplicationConfiguration) (reconcile.Result, error) {
+	// Copies of workloads in other namespaces are not owned by this
+	// ApplicationConfiguration, so they are deleted whether or not workload
+	// finalization is enabled.
+	err := errors.Wrap(r.deleteNamespaceCopies(ctx, ac), errDeleteNamespaceCopies)
+
+	if r.finalizers == nil {
+		if err != nil {
+			log.Debug("Cannot delete namespace copies", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(ac, event.Warning(reasonCannotDeleteWorkloads, err))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+		// Workload finalization is disabled, so the remaining workloads
+		// are garbage collected by their owner references.
+		return reconcile.Result{}, errors.Wrap(r.finalizer.RemoveFinalizer(ctx, ac), errRemoveFinalizer)
+	}
+
+	var target client.Client
+	if err == nil {
+		target, _, err = r.target(ctx, ac)
+		err = errors.Wrap(err, errConnectTargetCluster)
+	}
+	var remaining []string
+	if err == nil {
+		remaining, err = r.finalizers.Prune(ctx, target, ac)
+		err = errors.Wrap(err, errDeleteWorkloads)
+	}
+
+	switch {
+	case r.finalizers.TimedOut(ac, time.Now()) && (err != nil || len(remaining) > 0):
+		if err == nil {
+			err = errors.Errorf(errFmtRemaining, strings.Join(remaining, "; "))
+		}
+		err = errors.Wrapf(err, errFmtDeletionTimeout, r.finalizers.timeout)
+		log.Info("Workloads were not deleted in time", "error", err)
+		r.record.Event(ac, event.Warning(reasonDeletionTimedOut, err))
+	case err != nil:
+		log.Debug("Cannot delete workloads", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotDeleteWorkloads, err))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	case len(remaining) > 0:
+		log.Debug("Waiting for workloads to be deleted", "workloads", strings.Join(remaining, "; "), "requeue-after", time.Now().Add(shortWait))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+	return reconcile.Result{}, errors.Wrap(r.finalizer.RemoveFinalizer(ctx, ac), errRemoveFinalizer)
+}
+
+// hasFinalizer returns true if the supplied object has the supplied
+// finalizer.
+func hasFinalizer(o metav1.Object, finalizer string) bool {
+	for _, f := range o.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
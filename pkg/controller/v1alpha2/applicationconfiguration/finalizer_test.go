@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestFinalizerAwarePrunerPrune(t *testing.T) {
+	errBoom := errors.New("boom")
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"},
+		Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{
+			{ComponentName: "db", Reference: runtimev1alpha1.TypedReference{APIVersion: "rds.services.k8s.aws/v1alpha1", Kind: "DBInstance", Name: "db"}},
+			{ComponentName: "web", Reference: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}},
+		}},
+	}
+	deleting := func(_ context.Context, key client.ObjectKey, _ runtime.Object) error {
+		if key.Name == "db" {
+			// The DBInstance is waiting for its finalizer to be cleared.
+			return nil
+		}
+		return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+
+	type want struct {
+		remaining []string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		pruner ComponentPruner
+		get    test.MockGetFn
+		want   want
+	}{
+		"WaitingForFinalizers": {
+			reason: "Workloads that still exist after being deleted should be returned",
+			pruner: ComponentPrunerFn(func(_ context.Context, _ client.Client, _ string, _ []v1alpha2.WorkloadStatus) error { return nil }),
+			get:    deleting,
+			want:   want{remaining: []string{`DBInstance "db" in namespace "ns"`}},
+		},
+		"Deleted": {
+			reason: "No workloads should be returned once they are all gone",
+			pruner: ComponentPrunerFn(func(_ context.Context, _ client.Client, _ string, _ []v1alpha2.WorkloadStatus) error { return nil }),
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			want:   want{remaining: []string{}},
+		},
+		"PruneError": {
+			reason: "Errors deleting workloads should be returned",
+			pruner: ComponentPrunerFn(func(_ context.Context, _ client.Client, _ string, _ []v1alpha2.WorkloadStatus) error { return errBoom }),
+			get:    deleting,
+			want:   want{err: errBoom},
+		},
+		"GetError": {
+			reason: "Errors getting deleting workloads should be returned",
+			pruner: ComponentPrunerFn(func(_ context.Context, _ client.Client, _ string, _ []v1alpha2.WorkloadStatus) error { return nil }),
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetDeleting, "db")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewFinalizerAwarePruner(tc.pruner, time.Minute)
+			remaining, err := p.Prune(context.Background(), &test.MockClient{MockGet: tc.get}, ac)
+			if diff := cmp.Diff(tc.want, want{remaining: remaining, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Prune(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFinalizerAwarePrunerTimedOut(t *testing.T) {
+	now := time.Now()
+	deleted := metav1.NewTime(now.Add(-2 * time.Minute))
+
+	cases := map[string]struct {
+		reason  string
+		deleted *metav1.Time
+		timeout time.Duration
+		want    bool
+	}{
+		"NotDeleted": {
+			reason:  "An ApplicationConfiguration that is not being deleted cannot time out",
+			timeout: time.Minute,
+			want:    false,
+		},
+		"Waiting": {
+			reason:  "An ApplicationConfiguration deleted more recently than the timeout should not have timed out",
+			deleted: &deleted,
+			timeout: 5 * time.Minute,
+			want:    false,
+		},
+		"TimedOut": {
+			reason:  "An ApplicationConfiguration deleted longer ago than the timeout should have timed out",
+			deleted: &deleted,
+			timeout: time.Minute,
+			want:    true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: tc.deleted}}
+			got := NewFinalizerAwarePruner(nil, tc.timeout).TimedOut(ac, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\np.TimedOut(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
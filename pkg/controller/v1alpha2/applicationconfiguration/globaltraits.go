@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Global trait error strings.
+const (
+	errFmtDecodeGlobalTrait = "cannot decode global trait %d"
+	errFmtDecodeCompTrait   = "cannot decode trait %d of component %q"
+)
+
+// withGlobalTraits returns the traits of the supplied component with the
+// supplied global traits appended. A trait of the component overrides any
+// global trait of the same apiVersion and kind.
+func withGlobalTraits(global []v1alpha2.ComponentTrait, acc v1alpha2.ApplicationConfigurationComponent) ([]v1alpha2.ComponentTrait, error) {
+	if len(global) == 0 {
+		return acc.Traits, nil
+	}
+	kinds := make(map[schema.GroupVersionKind]bool, len(acc.Traits))
+	for i, ct := range acc.Traits {
+		gvk, err := componentTraitGVK(ct)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeCompTrait, i, acc.ComponentName)
+		}
+		kinds[gvk] = true
+	}
+	traits := make([]v1alpha2.ComponentTrait, 0, len(acc.Traits)+len(global))
+	traits = append(traits, acc.Traits...)
+	for i, ct := range global {
+		gvk, err := componentTraitGVK(ct)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeGlobalTrait, i)
+		}
+		if kinds[gvk] {
+			continue
+		}
+		traits = append(traits, *ct.DeepCopy())
+	}
+	return traits, nil
+}
+
+// componentTraitGVK returns the kind of the supplied trait.
+func componentTraitGVK(ct v1alpha2.ComponentTrait) (schema.GroupVersionKind, error) {
+	t := &unstructured.Unstructured{}
+	if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return t.GroupVersionKind(), nil
+}
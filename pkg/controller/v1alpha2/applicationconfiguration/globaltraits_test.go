@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestWithGlobalTraits(t *testing.T) {
+	trait := func(raw string) v1alpha2.ComponentTrait {
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(raw)}}
+	}
+	logging := trait(`{"apiVersion":"example.org/v1","kind":"Logging","spec":{"level":"info"}}`)
+	override := trait(`{"apiVersion":"example.org/v1","kind":"Logging","spec":{"level":"debug"}}`)
+	scaler := trait(`{"apiVersion":"example.org/v1","kind":"Scaler"}`)
+	invalid := trait(`{`)
+
+	type want struct {
+		traits []v1alpha2.ComponentTrait
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		global []v1alpha2.ComponentTrait
+		acc    v1alpha2.ApplicationConfigurationComponent
+		want   want
+	}{
+		"NoGlobalTraits": {
+			reason: "The traits of a component should be unchanged when there are no global traits",
+			acc:    v1alpha2.ApplicationConfigurationComponent{Traits: []v1alpha2.ComponentTrait{scaler}},
+			want:   want{traits: []v1alpha2.ComponentTrait{scaler}},
+		},
+		"Appended": {
+			reason: "Global traits should be appended to the traits of a component",
+			global: []v1alpha2.ComponentTrait{logging},
+			acc:    v1alpha2.ApplicationConfigurationComponent{Traits: []v1alpha2.ComponentTrait{scaler}},
+			want:   want{traits: []v1alpha2.ComponentTrait{scaler, logging}},
+		},
+		"ComponentWins": {
+			reason: "A trait of a component should override a global trait of the same kind",
+			global: []v1alpha2.ComponentTrait{logging, scaler},
+			acc:    v1alpha2.ApplicationConfigurationComponent{Traits: []v1alpha2.ComponentTrait{override}},
+			want:   want{traits: []v1alpha2.ComponentTrait{override, scaler}},
+		},
+		"InvalidGlobalTrait": {
+			reason: "Errors decoding a global trait should be returned",
+			global: []v1alpha2.ComponentTrait{invalid},
+			want:   want{err: errors.Wrapf(errors.New("unexpected end of JSON input"), errFmtDecodeGlobalTrait, 0)},
+		},
+		"InvalidComponentTrait": {
+			reason: "Errors decoding a trait of a component should be returned",
+			global: []v1alpha2.ComponentTrait{logging},
+			acc:    v1alpha2.ApplicationConfigurationComponent{ComponentName: "cool", Traits: []v1alpha2.ComponentTrait{invalid}},
+			want:   want{err: errors.Wrapf(errors.New("unexpected end of JSON input"), errFmtDecodeCompTrait, 0, "cool")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			traits, err := withGlobalTraits(tc.global, tc.acc)
+			got := want{traits: traits, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nwithGlobalTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	defaultProbeTimeout       = 5 * time.Second
+	defaultExpectedStatusCode = http.StatusOK
+
+	// probeClientTimeout bounds each health probe request regardless of
+	// the probe timeout of its ApplicationConfiguration.
+	probeClientTimeout = 30 * time.Second
+)
+
+// Health probe error strings.
+const (
+	errNewProbeRequest    = "cannot create health probe request"
+	errProbe              = "health probe failed"
+	errFmtUnexpectedCode  = "health probe returned status code %d, expected %d"
+	errFmtUnsupportedType = "health probe of component %q specifies no supported probe type"
+	errParseProbeURL      = "cannot parse health probe URL"
+	errFmtProbeScheme     = "health probe URL scheme %q is not http or https"
+	errFmtProbeService    = "health probe host %q is not a Service in namespace %q"
+	errFmtProbePod        = "health probe host %q is not the IP address of a pod in namespace %q"
+	errFmtGetProbeService = "cannot get health probe Service %q"
+	errListProbePods      = "cannot list pods with the health probe IP address"
+)
+
+// A HealthProber probes the health of a workload.
+type HealthProber interface {
+	// Probe returns an error if the supplied probe considers its workload,
+	// which is in the supplied namespace, unhealthy.
+	Probe(ctx context.Context, namespace string, p *v1alpha2.ComponentHealthProbe) error
+}
+
+// A HealthProberFn probes the health of a workload.
+type HealthProberFn func(ctx context.Context, namespace string, p *v1alpha2.ComponentHealthProbe) error
+
+// Probe returns an error if the supplied probe considers its workload,
+// which is in the supplied namespace, unhealthy.
+func (fn HealthProberFn) Probe(ctx context.Context, namespace string, p *v1alpha2.ComponentHealthProbe) error {
+	return fn(ctx, namespace, p)
+}
+
+// An httpProber probes the health of workloads via HTTP. Probes may only be
+// sent to a Service, or to the IP address of a pod, in the namespace of the
+// probed workload, so that an ApplicationConfiguration cannot use the
+// controller to send requests to arbitrary hosts.
+type httpProber struct {
+	client *http.Client
+	kube   client.Reader
+}
+
+func newHTTPProber(kube client.Reader) *httpProber {
+	return &httpProber{
+		client: &http.Client{
+			Timeout: probeClientTimeout,
+			// Redirects could send the probe to a host that is not allowed.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		kube: kube,
+	}
+}
+
+func (h *httpProber) Probe(ctx context.Context, namespace string, p *v1alpha2.ComponentHealthProbe) error {
+	if p.HTTPGet == nil {
+		return nil
+	}
+
+	u, err := url.Parse(p.HTTPGet.URL)
+	if err != nil {
+		return errors.Wrap(err, errParseProbeURL)
+	}
+	if err := h.allowed(ctx, namespace, u); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, errNewProbeRequest)
+	}
+
+	rsp, err := h.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, errProbe)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+	// Drain the body so that the connection may be reused.
+	_, _ = io.Copy(ioutil.Discard, rsp.Body)
+
+	want := defaultExpectedStatusCode
+	if p.HTTPGet.ExpectedStatusCode != nil {
+		want = int(*p.HTTPGet.ExpectedStatusCode)
+	}
+	if rsp.StatusCode != want {
+		return errors.Errorf(errFmtUnexpectedCode, rsp.StatusCode, want)
+	}
+	return nil
+}
+
+// allowed returns an error unless the supplied URL is an HTTP or HTTPS URL
+// whose host is a Service, other than an ExternalName Service, or the IP
+// address of a pod in the supplied namespace. Services may be addressed by
+// their name, or by their DNS name in the namespace, e.g. name.namespace.svc.
+func (h *httpProber) allowed(ctx context.Context, namespace string, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf(errFmtProbeScheme, u.Scheme)
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		l := &corev1.PodList{}
+		if err := h.kube.List(ctx, l, client.InNamespace(namespace), client.MatchingFields{"status.podIP": ip.String()}); err != nil {
+			return errors.Wrap(err, errListProbePods)
+		}
+		if len(l.Items) == 0 {
+			return errors.Errorf(errFmtProbePod, host, namespace)
+		}
+		return nil
+	}
+
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(parts) > 1 && parts[1] != namespace || len(parts) > 2 && parts[2] != "svc" {
+		return errors.Errorf(errFmtProbeService, host, namespace)
+	}
+	svc := &corev1.Service{}
+	err := h.kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: parts[0]}, svc)
+	if kerrors.IsNotFound(err) {
+		return errors.Errorf(errFmtProbeService, host, namespace)
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetProbeService, parts[0])
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return errors.Errorf(errFmtProbeService, host, namespace)
+	}
+	return nil
+}
+
+// probeTimeout returns the deadline of each health probe of the supplied
+// ApplicationConfiguration.
+func probeTimeout(ac *v1alpha2.ApplicationConfiguration) time.Duration {
+	if ac.Spec.ProbeTimeoutSeconds == nil || *ac.Spec.ProbeTimeoutSeconds <= 0 {
+		return defaultProbeTimeout
+	}
+	return time.Duration(*ac.Spec.ProbeTimeoutSeconds) * time.Second
+}
+
+// probeHealth probes each workload of the supplied ApplicationConfiguration
+// whose component has a health probe, and records its health in the
+// ApplicationConfiguration's status. Each probe is bounded by both the
+// supplied context and the ApplicationConfiguration's probe timeout.
+func (r *Reconciler) probeHealth(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) {
+	probes := make(map[string]*v1alpha2.ComponentHealthProbe)
+	for _, c := range ac.Spec.Components {
+		if c.HealthProbe != nil {
+			probes[c.ComponentName] = c.HealthProbe
+		}
+	}
+
+	for i := range ac.Status.Workloads {
+		ws := &ac.Status.Workloads[i]
+		p, ok := probes[ws.ComponentName]
+		if !ok {
+			setHealth(ws, nil)
+			continue
+		}
+
+		probeWorkload(ctx, r.health, ac, ws, p)
+	}
+}
+
+// probeWorkload probes the workload of the supplied workload status using the
+// supplied probe, and records its health in the workload status.
+func probeWorkload(ctx context.Context, h HealthProber, ac *v1alpha2.ApplicationConfiguration, ws *v1alpha2.WorkloadStatus, p *v1alpha2.ComponentHealthProbe) {
+	health := &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy, LastProbeTime: metav1.Now()}
+	defer setHealth(ws, health)
+	if p.HTTPGet == nil {
+		health.Status = v1alpha2.HealthStatusUnhealthy
+		health.Message = errors.Errorf(errFmtUnsupportedType, ws.ComponentName).Error()
+		return
+	}
+
+	pctx, cancel := context.WithTimeout(ctx, probeTimeout(ac))
+	err := h.Probe(pctx, ac.GetNamespace(), p)
+	cancel()
+	if err != nil {
+		health.Status = v1alpha2.HealthStatusUnhealthy
+		health.Message = err.Error()
+	}
+}
+
+// setHealth records the supplied health in the supplied workload status. Its
+// last transition time is only updated if the health status changed, so that
+// it tracks how long the workload has been healthy or unhealthy.
+func setHealth(ws *v1alpha2.WorkloadStatus, h *v1alpha2.WorkloadHealth) {
+	switch {
+	case h == nil:
+		ws.LastTransitionTime = nil
+	case ws.Health == nil || ws.Health.Status != h.Status || ws.LastTransitionTime == nil:
+		t := h.LastProbeTime
+		ws.LastTransitionTime = &t
+	}
+	ws.Health = h
+}
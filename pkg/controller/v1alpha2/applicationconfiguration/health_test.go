@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestHTTPProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	code := func(c int32) *int32 { return &c }
+
+	cases := map[string]struct {
+		reason string
+		probe  *v1alpha2.ComponentHealthProbe
+		want   error
+	}{
+		"NoHTTPGet": {
+			reason: "Probes without an HTTP GET should be ignored",
+			probe:  &v1alpha2.ComponentHealthProbe{},
+		},
+		"Healthy": {
+			reason: "A response with the default expected status code should be healthy",
+			probe:  &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{URL: srv.URL + "/healthz"}},
+		},
+		"ExpectedStatusCode": {
+			reason: "A response with the expected status code should be healthy",
+			probe: &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{
+				URL:                srv.URL + "/unavailable",
+				ExpectedStatusCode: code(http.StatusServiceUnavailable),
+			}},
+		},
+		"UnexpectedStatusCode": {
+			reason: "A response with an unexpected status code should be unhealthy",
+			probe:  &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{URL: srv.URL + "/unavailable"}},
+			want:   errors.Errorf(errFmtUnexpectedCode, http.StatusServiceUnavailable, http.StatusOK),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// The test server listens on an IP address that is the
+			// IP address of a pod.
+			kube := &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+				obj.(*corev1.PodList).Items = []corev1.Pod{{}}
+				return nil
+			}}
+			p := &httpProber{client: srv.Client(), kube: kube}
+			got := p.Probe(context.Background(), "ns", tc.probe)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Probe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHTTPProberAllowed(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pods := func(n int) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "ns" || lo.FieldSelector.String() != "status.podIP=10.0.0.1" {
+				return errors.Errorf("listed pods in namespace %q with selector %q", lo.Namespace, lo.FieldSelector)
+			}
+			obj.(*corev1.PodList).Items = make([]corev1.Pod, n)
+			return nil
+		}
+	}
+	service := func(t corev1.ServiceType) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			if key.Namespace != "ns" || key.Name != "web" {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "services"}, key.Name)
+			}
+			obj.(*corev1.Service).Spec.Type = t
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		url    string
+		want   error
+	}{
+		"UnsupportedScheme": {
+			reason: "URLs that are not HTTP or HTTPS should not be allowed",
+			url:    "file:///etc/passwd",
+			want:   errors.Errorf(errFmtProbeScheme, "file"),
+		},
+		"PodIP": {
+			reason: "The IP address of a pod in the namespace should be allowed",
+			kube:   &test.MockClient{MockList: pods(1)},
+			url:    "http://10.0.0.1:8080/healthz",
+		},
+		"NotPodIP": {
+			reason: "IP addresses that are not of a pod in the namespace should not be allowed",
+			kube:   &test.MockClient{MockList: pods(0)},
+			url:    "http://10.0.0.1:8080/healthz",
+			want:   errors.Errorf(errFmtProbePod, "10.0.0.1", "ns"),
+		},
+		"ListPodsError": {
+			reason: "Errors listing pods should be returned",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			url:    "http://10.0.0.1/healthz",
+			want:   errors.Wrap(errBoom, errListProbePods),
+		},
+		"ServiceName": {
+			reason: "A Service in the namespace should be allowed by its name",
+			kube:   &test.MockClient{MockGet: service(corev1.ServiceTypeClusterIP)},
+			url:    "http://web:8080/healthz",
+		},
+		"ServiceDNSName": {
+			reason: "A Service in the namespace should be allowed by its DNS name",
+			kube:   &test.MockClient{MockGet: service(corev1.ServiceTypeClusterIP)},
+			url:    "https://web.ns.svc.cluster.local/healthz",
+		},
+		"OtherNamespace": {
+			reason: "Services in other namespaces should not be allowed",
+			kube:   &test.MockClient{MockGet: service(corev1.ServiceTypeClusterIP)},
+			url:    "http://web.kube-system.svc/healthz",
+			want:   errors.Errorf(errFmtProbeService, "web.kube-system.svc", "ns"),
+		},
+		"NotService": {
+			reason: "Hosts that are not a Service in the namespace should not be allowed",
+			kube:   &test.MockClient{MockGet: service(corev1.ServiceTypeClusterIP)},
+			url:    "http://example.org/healthz",
+			want:   errors.Errorf(errFmtProbeService, "example.org", "ns"),
+		},
+		"ExternalNameService": {
+			reason: "ExternalName Services should not be allowed, because they may resolve to any host",
+			kube:   &test.MockClient{MockGet: service(corev1.ServiceTypeExternalName)},
+			url:    "http://web/healthz",
+			want:   errors.Errorf(errFmtProbeService, "web", "ns"),
+		},
+		"GetServiceError": {
+			reason: "Errors getting the Service should be returned",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			url:    "http://web/healthz",
+			want:   errors.Wrapf(errBoom, errFmtGetProbeService, "web"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &httpProber{kube: tc.kube}
+			got := p.allowed(context.Background(), "ns", u)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.allowed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProbeHealth(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	probe := &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{URL: "http://example.org/healthz"}}
+	timeout := int32(2)
+
+	ac := func(p *v1alpha2.ComponentHealthProbe) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{
+					{ComponentName: "probed", HealthProbe: p},
+					{ComponentName: "unprobed"},
+				},
+				ProbeTimeoutSeconds: &timeout,
+			},
+			Status: v1alpha2.ApplicationConfigurationStatus{
+				Workloads: []v1alpha2.WorkloadStatus{
+					{ComponentName: "probed"},
+					{ComponentName: "unprobed", Health: &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy}},
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		probe  *v1alpha2.ComponentHealthProbe
+		prober HealthProber
+		want   []v1alpha2.WorkloadStatus
+	}{
+		"Healthy": {
+			reason: "Workloads whose probe succeeds should be healthy, and workloads without a probe should have no health",
+			probe:  probe,
+			prober: HealthProberFn(func(ctx context.Context, _ string, _ *v1alpha2.ComponentHealthProbe) error {
+				if d, ok := ctx.Deadline(); !ok || time.Until(d) > 2*time.Second {
+					return errors.New("probe deadline was not derived from probeTimeoutSeconds")
+				}
+				return nil
+			}),
+			want: []v1alpha2.WorkloadStatus{
+				{ComponentName: "probed", Health: &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy}, LastTransitionTime: &metav1.Time{}},
+				{ComponentName: "unprobed"},
+			},
+		},
+		"Unhealthy": {
+			reason: "Workloads whose probe fails should be unhealthy",
+			probe:  probe,
+			prober: HealthProberFn(func(_ context.Context, _ string, _ *v1alpha2.ComponentHealthProbe) error {
+				return errBoom
+			}),
+			want: []v1alpha2.WorkloadStatus{
+				{ComponentName: "probed", Health: &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusUnhealthy, Message: errBoom.Error()}, LastTransitionTime: &metav1.Time{}},
+				{ComponentName: "unprobed"},
+			},
+		},
+		"UnsupportedProbe": {
+			reason: "Workloads whose probe specifies no supported probe type should be unhealthy",
+			probe:  &v1alpha2.ComponentHealthProbe{},
+			prober: HealthProberFn(func(_ context.Context, _ string, _ *v1alpha2.ComponentHealthProbe) error {
+				return nil
+			}),
+			want: []v1alpha2.WorkloadStatus{
+				{ComponentName: "probed", Health: &v1alpha2.WorkloadHealth{
+					Status:  v1alpha2.HealthStatusUnhealthy,
+					Message: errors.Errorf(errFmtUnsupportedType, "probed").Error(),
+				}, LastTransitionTime: &metav1.Time{}},
+				{ComponentName: "unprobed"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{health: tc.prober}
+			a := ac(tc.probe)
+			r.probeHealth(context.Background(), a)
+			if diff := cmp.Diff(tc.want, a.Status.Workloads, cmpopts.IgnoreTypes(metav1.Time{}), equateTimeSet()); diff != "" {
+				t.Errorf("\n%s\nr.probeHealth(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// equateTimeSet compares times only by whether they are set, given that
+// metav1.Time's Equal method prevents cmpopts.IgnoreTypes from ignoring them.
+func equateTimeSet() cmp.Option {
+	return cmp.Comparer(func(a, b *metav1.Time) bool { return (a == nil) == (b == nil) })
+}
+
+func TestSetHealth(t *testing.T) {
+	then := metav1.Unix(1, 0)
+	now := metav1.Unix(2, 0)
+	healthy := func(t metav1.Time) *v1alpha2.WorkloadHealth {
+		return &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy, LastProbeTime: t}
+	}
+	unhealthy := func(t metav1.Time) *v1alpha2.WorkloadHealth {
+		return &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusUnhealthy, Message: "boom", LastProbeTime: t}
+	}
+
+	cases := map[string]struct {
+		reason string
+		ws     v1alpha2.WorkloadStatus
+		h      *v1alpha2.WorkloadHealth
+		want   v1alpha2.WorkloadStatus
+	}{
+		"FirstProbe": {
+			reason: "The first probe of a workload should be recorded as a transition",
+			h:      healthy(now),
+			want:   v1alpha2.WorkloadStatus{Health: healthy(now), LastTransitionTime: &now},
+		},
+		"Unchanged": {
+			reason: "The last transition time should be preserved when the health status is unchanged",
+			ws:     v1alpha2.WorkloadStatus{Health: healthy(then), LastTransitionTime: &then},
+			h:      healthy(now),
+			want:   v1alpha2.WorkloadStatus{Health: healthy(now), LastTransitionTime: &then},
+		},
+		"Transitioned": {
+			reason: "The last transition time should be updated when the health status changes",
+			ws:     v1alpha2.WorkloadStatus{Health: healthy(then), LastTransitionTime: &then},
+			h:      unhealthy(now),
+			want:   v1alpha2.WorkloadStatus{Health: unhealthy(now), LastTransitionTime: &now},
+		},
+		"NoProbe": {
+			reason: "The last transition time should be removed along with the health of a workload",
+			ws:     v1alpha2.WorkloadStatus{Health: healthy(then), LastTransitionTime: &then},
+			want:   v1alpha2.WorkloadStatus{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setHealth(&tc.ws, tc.h)
+			if diff := cmp.Diff(tc.want, tc.ws); diff != "" {
+				t.Errorf("\n%s\nsetHealth(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// ArgoRolloutGroupVersionKind is the kind of an Argo Rollout.
+var ArgoRolloutGroupVersionKind = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+// Phases of an Argo Rollout, per its status.phase.
+const (
+	argoPhaseHealthy     = "Healthy"
+	argoPhaseProgressing = "Progressing"
+	argoPhasePaused      = "Paused"
+	argoPhaseDegraded    = "Degraded"
+)
+
+// A HealthChecker determines how far the rollout of a live workload has
+// progressed.
+type HealthChecker interface {
+	// Check the rollout of the supplied live workload, which was read using
+	// the supplied reader. It returns nil if the workload is not rolled out,
+	// e.g. because it has no replicas.
+	Check(ctx context.Context, c client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout
+}
+
+// A HealthCheckerFn determines how far the rollout of a live workload has
+// progressed.
+type HealthCheckerFn func(ctx context.Context, c client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout
+
+// Check the rollout of the supplied live workload.
+func (fn HealthCheckerFn) Check(ctx context.Context, c client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout {
+	return fn(ctx, c, w)
+}
+
+// A ReplicaHealthChecker checks the rollout of workloads that report their
+// desired, ready, and updated replicas like a Deployment does.
+type ReplicaHealthChecker struct{}
+
+// Check the rollout of the supplied live workload.
+func (ReplicaHealthChecker) Check(_ context.Context, _ client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout {
+	return rolloutOf(fieldpath.Pave(w.UnstructuredContent()))
+}
+
+// An ArgoRolloutHealthChecker checks the rollout of Argo Rollouts, whose
+// status.phase reports whether they are healthy.
+type ArgoRolloutHealthChecker struct{}
+
+// Check the rollout of the supplied Argo Rollout.
+func (ArgoRolloutHealthChecker) Check(_ context.Context, _ client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout {
+	p := fieldpath.Pave(w.UnstructuredContent())
+	r := rolloutOf(p)
+	phase, err := p.GetString("status.phase")
+	if err != nil {
+		return r
+	}
+	if r == nil {
+		r = &v1alpha2.WorkloadRollout{}
+	}
+	switch phase {
+	case argoPhaseHealthy:
+		r.Phase = v1alpha2.RolloutPhaseComplete
+	case argoPhaseProgressing, argoPhasePaused:
+		r.Phase = v1alpha2.RolloutPhaseProgressing
+	case argoPhaseDegraded:
+		r.Phase = v1alpha2.RolloutPhaseDegraded
+	}
+	return r
+}
+
+// A HealthCheckerRegistry checks the rollout of live workloads using the
+// HealthChecker registered for their kind, or a fallback HealthChecker if none
+// is registered.
+type HealthCheckerRegistry struct {
+	checkers map[schema.GroupVersionKind]HealthChecker
+	fallback HealthChecker
+}
+
+// NewHealthCheckerRegistry returns a HealthCheckerRegistry that uses the
+// supplied HealthChecker for kinds of workload without a registered one.
+func NewHealthCheckerRegistry(fallback HealthChecker) *HealthCheckerRegistry {
+	return &HealthCheckerRegistry{checkers: make(map[schema.GroupVersionKind]HealthChecker), fallback: fallback}
+}
+
+// Register the supplied HealthChecker for the supplied kind of workload.
+func (r *HealthCheckerRegistry) Register(gvk schema.GroupVersionKind, c HealthChecker) {
+	r.checkers[gvk] = c
+}
+
+// Check the rollout of the supplied live workload.
+func (r *HealthCheckerRegistry) Check(ctx context.Context, c client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout {
+	if hc, ok := r.checkers[w.GroupVersionKind()]; ok {
+		return hc.Check(ctx, c, w)
+	}
+	return r.fallback.Check(ctx, c, w)
+}
+
+// defaultHealthCheckers returns a HealthCheckerRegistry that checks Argo
+// Rollouts by their phase, workloads installed by a Helm release by the
+// release's status, and all other workloads by their replicas.
+func defaultHealthCheckers() *HealthCheckerRegistry {
+	r := NewHealthCheckerRegistry(NewHelmReleaseHealthChecker(ReplicaHealthChecker{}))
+	r.Register(ArgoRolloutGroupVersionKind, ArgoRolloutHealthChecker{})
+	return r
+}
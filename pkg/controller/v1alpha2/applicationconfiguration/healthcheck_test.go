@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestArgoRolloutHealthChecker(t *testing.T) {
+	rollout := func(phase string) *unstructured.Unstructured {
+		status := map[string]interface{}{"readyReplicas": int64(2), "updatedReplicas": int64(2)}
+		if phase != "" {
+			status["phase"] = phase
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(2)},
+			"status": status,
+		}}
+	}
+	counted := func(phase v1alpha2.RolloutPhase) *v1alpha2.WorkloadRollout {
+		return &v1alpha2.WorkloadRollout{DesiredReplicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2, Phase: phase}
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		want   *v1alpha2.WorkloadRollout
+	}{
+		"Healthy": {
+			reason: "A Healthy rollout should be complete",
+			w:      rollout("Healthy"),
+			want:   counted(v1alpha2.RolloutPhaseComplete),
+		},
+		"Progressing": {
+			reason: "A Progressing rollout should be progressing, even if all replicas are updated",
+			w:      rollout("Progressing"),
+			want:   counted(v1alpha2.RolloutPhaseProgressing),
+		},
+		"Paused": {
+			reason: "A Paused rollout should be progressing",
+			w:      rollout("Paused"),
+			want:   counted(v1alpha2.RolloutPhaseProgressing),
+		},
+		"Degraded": {
+			reason: "A Degraded rollout should be degraded",
+			w:      rollout("Degraded"),
+			want:   counted(v1alpha2.RolloutPhaseDegraded),
+		},
+		"NoPhase": {
+			reason: "A rollout without a phase should be checked by its replicas",
+			w:      rollout(""),
+			want:   counted(v1alpha2.RolloutPhaseComplete),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ArgoRolloutHealthChecker{}.Check(context.Background(), &test.MockClient{}, tc.w)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nArgoRolloutHealthChecker{}.Check(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHealthCheckerRegistry(t *testing.T) {
+	r := defaultHealthCheckers()
+
+	argo := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"phase": "Degraded"}}}
+	argo.SetGroupVersionKind(ArgoRolloutGroupVersionKind)
+	want := &v1alpha2.WorkloadRollout{Phase: v1alpha2.RolloutPhaseDegraded}
+	if diff := cmp.Diff(want, r.Check(context.Background(), &test.MockClient{}, argo)); diff != "" {
+		t.Errorf("r.Check(...): -want Argo Rollout, +got Argo Rollout:\n%s", diff)
+	}
+
+	// Other kinds are checked by their replicas, ignoring any phase.
+	deploy := argo.DeepCopy()
+	deploy.SetAPIVersion("apps/v1")
+	deploy.SetKind("Deployment")
+	if got := r.Check(context.Background(), &test.MockClient{}, deploy); got != nil {
+		t.Errorf("r.Check(...): want no rollout for a Deployment without replicas, got %+v", got)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const errListAppConfigsScope = "cannot list ApplicationConfigurations that may reference health scope"
+
+// A HealthScopeHandler enqueues the ApplicationConfigurations whose workloads
+// are in a HealthScope when the aggregate health of the scope changes.
+type HealthScopeHandler struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+var _ handler.EventHandler = &HealthScopeHandler{}
+
+// Create does nothing. ApplicationConfigurations are reconciled when they add
+// their workloads to a scope.
+func (h *HealthScopeHandler) Create(_ event.CreateEvent, _ workqueue.RateLimitingInterface) {}
+
+// Update enqueues the ApplicationConfigurations that reference the scope if
+// its health changed.
+func (h *HealthScopeHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	o, ok := evt.ObjectOld.(*v1alpha2.HealthScope)
+	if !ok {
+		return
+	}
+	n, ok := evt.ObjectNew.(*v1alpha2.HealthScope)
+	if !ok {
+		return
+	}
+	if o.Status.Health == n.Status.Health {
+		return
+	}
+	h.enqueue(evt.MetaNew.GetNamespace(), evt.MetaNew.GetName(), q)
+}
+
+// Delete enqueues the ApplicationConfigurations that reference the scope.
+func (h *HealthScopeHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Meta.GetNamespace(), evt.Meta.GetName(), q)
+}
+
+// Generic does nothing.
+func (h *HealthScopeHandler) Generic(_ event.GenericEvent, _ workqueue.RateLimitingInterface) {}
+
+func (h *HealthScopeHandler) enqueue(namespace, name string, q workqueue.RateLimitingInterface) {
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := h.client.List(context.Background(), acs, client.InNamespace(namespace)); err != nil {
+		h.log.Debug(errListAppConfigsScope, "error", err, "scope", name)
+		return
+	}
+	for _, nn := range referencingHealthScope(acs, name) {
+		q.Add(reconcile.Request{NamespacedName: nn})
+	}
+}
+
+// referencingHealthScope returns the ApplicationConfigurations whose
+// workloads are in the named HealthScope, according to their status.
+func referencingHealthScope(acs *v1alpha2.ApplicationConfigurationList, name string) []types.NamespacedName {
+	var matches []types.NamespacedName
+	for _, ac := range acs.Items {
+		if inHealthScope(ac.Status.Workloads, name) {
+			matches = append(matches, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()})
+		}
+	}
+	return matches
+}
+
+func inHealthScope(ws []v1alpha2.WorkloadStatus, name string) bool {
+	for _, w := range ws {
+		for _, s := range w.Scopes {
+			gv, err := schema.ParseGroupVersion(s.Reference.APIVersion)
+			if err != nil {
+				continue
+			}
+			if gv.Group == v1alpha2.Group && s.Reference.Kind == v1alpha2.HealthScopeKind && s.Reference.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestHealthScopeHandler(t *testing.T) {
+	inScope := func(name, scope string) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Status: v1alpha2.ApplicationConfigurationStatus{
+				Workloads: []v1alpha2.WorkloadStatus{{
+					Scopes: []v1alpha2.WorkloadScope{{
+						Reference: runtimev1alpha1.TypedReference{
+							APIVersion: v1alpha2.SchemeGroupVersion.String(),
+							Kind:       v1alpha2.HealthScopeKind,
+							Name:       scope,
+						},
+					}},
+				}},
+			},
+		}
+	}
+	h := &HealthScopeHandler{
+		client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.ApplicationConfigurationList).Items = []v1alpha2.ApplicationConfiguration{
+				inScope("using", "cool-scope"),
+				inScope("other", "other-scope"),
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "unscoped"}},
+			}
+			return nil
+		})},
+		log: logging.NewNopLogger(),
+	}
+	scope := func(health string) *v1alpha2.HealthScope {
+		return &v1alpha2.HealthScope{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool-scope"},
+			Status:     v1alpha2.HealthScopeStatus{Health: health},
+		}
+	}
+	using := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "using"}}}
+
+	cases := map[string]struct {
+		reason string
+		event  func(q workqueue.RateLimitingInterface)
+		want   []reconcile.Request
+	}{
+		"HealthChanged": {
+			reason: "ApplicationConfigurations in a scope should be enqueued when its health changes",
+			event: func(q workqueue.RateLimitingInterface) {
+				o, n := scope("unhealthy"), scope("healthy")
+				h.Update(event.UpdateEvent{MetaOld: o, ObjectOld: o, MetaNew: n, ObjectNew: n}, q)
+			},
+			want: using,
+		},
+		"HealthUnchanged": {
+			reason: "Nothing should be enqueued when the health of a scope is unchanged",
+			event: func(q workqueue.RateLimitingInterface) {
+				o, n := scope("healthy"), scope("healthy")
+				h.Update(event.UpdateEvent{MetaOld: o, ObjectOld: o, MetaNew: n, ObjectNew: n}, q)
+			},
+		},
+		"Deleted": {
+			reason: "ApplicationConfigurations in a scope should be enqueued when it is deleted",
+			event: func(q workqueue.RateLimitingInterface) {
+				s := scope("healthy")
+				h.Delete(event.DeleteEvent{Meta: s, Object: s}, q)
+			},
+			want: using,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+			tc.event(q)
+
+			var got []reconcile.Request
+			for q.Len() > 0 {
+				item, _ := q.Get()
+				got = append(got, item.(reconcile.Request))
+				q.Done(item)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nHealthScopeHandler: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
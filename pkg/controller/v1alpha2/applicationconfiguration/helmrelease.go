@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Annotations Helm adds to the resources a release installs.
+const (
+	annotationHelmReleaseName      = "meta.helm.sh/release-name"
+	annotationHelmReleaseNamespace = "meta.helm.sh/release-namespace"
+)
+
+// Labels of the Secrets in which Helm stores each revision of a release, per
+// the helm.sh/release.v1 storage driver.
+const (
+	labelHelmOwner   = "owner"
+	labelHelmName    = "name"
+	labelHelmStatus  = "status"
+	labelHelmVersion = "version"
+
+	helmOwner = "helm"
+)
+
+// Statuses of a Helm release revision.
+const (
+	helmStatusDeployed        = "deployed"
+	helmStatusFailed          = "failed"
+	helmStatusPendingInstall  = "pending-install"
+	helmStatusPendingUpgrade  = "pending-upgrade"
+	helmStatusPendingRollback = "pending-rollback"
+)
+
+// A HelmReleaseHealthChecker checks the rollout of workloads installed by a
+// Helm release by the status of the latest revision of the release. Workloads
+// that were not installed by Helm, or whose release cannot be read, are
+// checked using the wrapped HealthChecker.
+type HelmReleaseHealthChecker struct {
+	wrapped HealthChecker
+}
+
+// NewHelmReleaseHealthChecker returns a HealthChecker that checks workloads
+// installed by a Helm release by the release's status, and all other
+// workloads using the supplied HealthChecker.
+func NewHelmReleaseHealthChecker(hc HealthChecker) *HelmReleaseHealthChecker {
+	return &HelmReleaseHealthChecker{wrapped: hc}
+}
+
+// Check the rollout of the supplied live workload. The replica counts of the
+// workload, if any, are those reported by the wrapped HealthChecker.
+func (hc *HelmReleaseHealthChecker) Check(ctx context.Context, c client.Reader, w *unstructured.Unstructured) *v1alpha2.WorkloadRollout {
+	r := hc.wrapped.Check(ctx, c, w)
+	status, ok := helmReleaseStatus(ctx, c, w)
+	if !ok {
+		return r
+	}
+	if r == nil {
+		r = &v1alpha2.WorkloadRollout{}
+	}
+	switch status {
+	case helmStatusDeployed:
+		r.Phase = v1alpha2.RolloutPhaseComplete
+	case helmStatusPendingInstall, helmStatusPendingUpgrade, helmStatusPendingRollback:
+		r.Phase = v1alpha2.RolloutPhaseProgressing
+	case helmStatusFailed:
+		r.Phase = v1alpha2.RolloutPhaseDegraded
+	}
+	return r
+}
+
+// helmReleaseStatus returns the status of the latest revision of the Helm
+// release that installed the supplied workload, and whether there is one.
+func helmReleaseStatus(ctx context.Context, c client.Reader, w *unstructured.Unstructured) (string, bool) {
+	name := w.GetAnnotations()[annotationHelmReleaseName]
+	if name == "" {
+		return "", false
+	}
+	namespace := w.GetAnnotations()[annotationHelmReleaseNamespace]
+	if namespace == "" {
+		namespace = w.GetNamespace()
+	}
+
+	l := &corev1.SecretList{}
+	if err := c.List(ctx, l, client.InNamespace(namespace), client.MatchingLabels{labelHelmOwner: helmOwner, labelHelmName: name}); err != nil {
+		return "", false
+	}
+	status, latest := "", -1
+	for _, s := range l.Items {
+		v, err := strconv.Atoi(s.GetLabels()[labelHelmVersion])
+		if err != nil || v <= latest {
+			continue
+		}
+		status, latest = s.GetLabels()[labelHelmStatus], v
+	}
+	return status, latest >= 0
+}
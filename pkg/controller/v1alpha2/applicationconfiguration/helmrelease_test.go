@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestHelmReleaseHealthChecker(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// workload returns a Deployment with two of its three replicas ready,
+	// installed by the supplied Helm release, if any.
+	workload := func(release string) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(2), "updatedReplicas": int64(3)},
+		}}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetNamespace("ns")
+		if release != "" {
+			w.SetAnnotations(map[string]string{annotationHelmReleaseName: release})
+		}
+		return w
+	}
+	// revisions returns a client whose Helm release Secrets have the supplied
+	// statuses, in order of revision.
+	revisions := func(statuses ...string) client.Reader {
+		return &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "ns" || lo.LabelSelector.String() != "name=cool,owner=helm" {
+				return errors.Errorf("unexpected list options %+v", lo)
+			}
+			l := obj.(*corev1.SecretList)
+			// Helm does not list revisions in order.
+			for i := len(statuses) - 1; i >= 0; i-- {
+				l.Items = append(l.Items, corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					labelHelmOwner: helmOwner, labelHelmName: "cool", labelHelmStatus: statuses[i], labelHelmVersion: strconv.Itoa(i + 1),
+				}}})
+			}
+			return nil
+		}}
+	}
+	rollout := func(phase v1alpha2.RolloutPhase) *v1alpha2.WorkloadRollout {
+		return &v1alpha2.WorkloadRollout{DesiredReplicas: 3, ReadyReplicas: 2, UpdatedReplicas: 3, Phase: phase}
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		w      *unstructured.Unstructured
+		want   *v1alpha2.WorkloadRollout
+	}{
+		"Deployed": {
+			reason: "A workload whose release is deployed should be complete, even if not all replicas are ready",
+			c:      revisions(helmStatusDeployed),
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseComplete),
+		},
+		"PendingUpgrade": {
+			reason: "A workload whose release is being upgraded should be progressing",
+			c:      revisions(helmStatusDeployed, helmStatusPendingUpgrade),
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseProgressing),
+		},
+		"Failed": {
+			reason: "A workload whose latest release revision failed should be degraded",
+			c:      revisions(helmStatusDeployed, "superseded", helmStatusFailed),
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseDegraded),
+		},
+		"LatestRevision": {
+			reason: "Only the latest revision of the release should be considered",
+			c:      revisions(helmStatusFailed, helmStatusDeployed),
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseComplete),
+		},
+		"NotHelm": {
+			reason: "A workload that was not installed by Helm should be checked by its replicas",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			w:      workload(""),
+			want:   rollout(v1alpha2.RolloutPhaseProgressing),
+		},
+		"NoRelease": {
+			reason: "A workload whose release has no revisions should be checked by its replicas",
+			c:      revisions(),
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseProgressing),
+		},
+		"ListError": {
+			reason: "A workload whose release cannot be read should be checked by its replicas",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			w:      workload("cool"),
+			want:   rollout(v1alpha2.RolloutPhaseProgressing),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewHelmReleaseHealthChecker(ReplicaHealthChecker{}).Check(context.Background(), tc.c, tc.w)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhc.Check(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
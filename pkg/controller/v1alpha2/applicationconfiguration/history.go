@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// History pruning error strings.
+const (
+	errListAppConfigs    = "cannot list application configurations"
+	errListRevisions     = "cannot list component revisions"
+	errFmtDeleteRevision = "cannot delete revision %q"
+)
+
+// DefaultRevisionHistoryLimit is the number of revisions of each component
+// that are kept unless an ApplicationConfiguration specifies otherwise.
+const DefaultRevisionHistoryLimit = 10
+
+// pruneHistory deletes the oldest revisions of the components of the supplied
+// ApplicationConfiguration, keeping its revision history limit of revisions
+// per component. The latest revision of a component, and revisions that any
+// ApplicationConfiguration in the namespace still uses, are never deleted.
+func (r *Reconciler) pruneHistory(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
+	limit := DefaultRevisionHistoryLimit
+	if ac.Spec.RevisionHistoryLimit != nil {
+		limit = int(*ac.Spec.RevisionHistoryLimit)
+	}
+
+	components := make(map[string]bool, len(ac.Spec.Components))
+	for _, acc := range ac.Spec.Components {
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = ExtractComponentName(acc.RevisionName)
+		}
+		components[name] = true
+	}
+
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := r.client.List(ctx, acs, client.InNamespace(ac.GetNamespace())); err != nil {
+		return errors.Wrap(err, errListAppConfigs)
+	}
+	inUse := make(map[string]bool)
+	for _, a := range acs.Items {
+		for _, acc := range a.Spec.Components {
+			inUse[acc.RevisionName] = true
+		}
+		for _, w := range a.Status.Workloads {
+			inUse[w.ComponentRevisionName] = true
+		}
+	}
+
+	revs := &appsv1.ControllerRevisionList{}
+	if err := r.client.List(ctx, revs, client.InNamespace(ac.GetNamespace())); err != nil {
+		return errors.Wrap(err, errListRevisions)
+	}
+	history := make(map[string][]appsv1.ControllerRevision)
+	for _, rev := range revs.Items {
+		ref := metav1.GetControllerOf(&rev)
+		if ref == nil || ref.Kind != v1alpha2.ComponentKind || !components[ref.Name] {
+			continue
+		}
+		history[ref.Name] = append(history[ref.Name], rev)
+	}
+
+	for _, h := range history {
+		// Newest first.
+		sort.SliceStable(h, func(i, j int) bool {
+			if !h[i].CreationTimestamp.Equal(&h[j].CreationTimestamp) {
+				return h[j].CreationTimestamp.Before(&h[i].CreationTimestamp)
+			}
+			return h[i].Revision > h[j].Revision
+		})
+		for i := range h {
+			if i < limit || i == 0 || inUse[h[i].GetName()] {
+				continue
+			}
+			if err := r.client.Delete(ctx, &h[i]); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errFmtDeleteRevision, h[i].GetName())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestPruneHistory(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	revision := func(component, name string, age int) appsv1.ControllerRevision {
+		c := &v1alpha2.Component{ObjectMeta: metav1.ObjectMeta{Name: component}}
+		return appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Duration(age) * time.Hour)),
+				OwnerReferences:   []metav1.OwnerReference{*metav1.NewControllerRef(c, v1alpha2.ComponentGroupVersionKind)},
+			},
+		}
+	}
+	revs := []appsv1.ControllerRevision{
+		revision("a", "a-4", 1),
+		revision("a", "a-1", 4),
+		revision("a", "a-3", 2),
+		revision("a", "a-2", 3),
+		revision("other", "other-1", 4),
+		revision("other", "other-2", 3),
+		revision("other", "other-3", 2),
+	}
+
+	limit := int32(2)
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "coolapp"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components:           []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "a"}},
+			RevisionHistoryLimit: &limit,
+		},
+	}
+	pinned := v1alpha2.ApplicationConfiguration{
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{{RevisionName: "a-1"}},
+		},
+	}
+
+	list := func(err error) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1alpha2.ApplicationConfigurationList:
+				l.Items = []v1alpha2.ApplicationConfiguration{*ac, pinned}
+			case *appsv1.ControllerRevisionList:
+				l.Items = append([]appsv1.ControllerRevision{}, revs...)
+				return err
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		deleted []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		delete error
+		want   want
+	}{
+		"ListAppConfigsError": {
+			reason: "Errors listing ApplicationConfigurations should be returned",
+			list:   test.NewMockListFn(errBoom),
+			want:   want{err: errors.Wrap(errBoom, errListAppConfigs)},
+		},
+		"ListRevisionsError": {
+			reason: "Errors listing component revisions should be returned",
+			list:   list(errBoom),
+			want:   want{err: errors.Wrap(errBoom, errListRevisions)},
+		},
+		"DeleteError": {
+			reason: "Errors deleting a component revision should be returned",
+			list:   list(nil),
+			delete: errBoom,
+			want:   want{deleted: []string{"a-2"}, err: errors.Wrapf(errBoom, errFmtDeleteRevision, "a-2")},
+		},
+		"Pruned": {
+			reason: "Revisions beyond the limit should be deleted, unless they are in use or belong to other components",
+			list:   list(nil),
+			want:   want{deleted: []string{"a-2"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			r := &Reconciler{client: &test.MockClient{
+				MockList: tc.list,
+				MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+					deleted = append(deleted, obj.(*appsv1.ControllerRevision).GetName())
+					return tc.delete
+				},
+			}}
+			err := r.pruneHistory(context.Background(), ac)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.pruneHistory(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.pruneHistory(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Pre-apply hook error strings.
+const (
+	errMarshalHookRequest  = "cannot marshal pre-apply hook request"
+	errNewHookRequest      = "cannot create pre-apply hook request"
+	errCallHook            = "cannot call pre-apply hook"
+	errHookCABundle        = "pre-apply hook CA bundle contains no PEM encoded certificates"
+	errFmtHookStatus       = "pre-apply hook returned %s"
+	errFmtGetHookService   = "cannot get pre-apply hook Service %q"
+	errFmtHookExternalName = "pre-apply hook Service %q is an ExternalName Service"
+)
+
+const (
+	defaultHookTimeout = 10 * time.Second
+	defaultHookPort    = 443
+
+	// maxHookTimeout bounds each call to a pre-apply hook regardless of the
+	// hook timeout of its ApplicationConfiguration.
+	maxHookTimeout = 30 * time.Second
+)
+
+// A PreApplyHookCaller calls the PreApplyHook of an ApplicationConfiguration.
+type PreApplyHookCaller interface {
+	// Call the supplied hook with the supplied workloads of the supplied
+	// ApplicationConfiguration. It returns an error if the hook fails.
+	Call(ctx context.Context, h *v1alpha2.PreApplyHook, ac *v1alpha2.ApplicationConfiguration, w []Workload) error
+}
+
+// A PreApplyHookCallerFn calls the PreApplyHook of an
+// ApplicationConfiguration.
+type PreApplyHookCallerFn func(ctx context.Context, h *v1alpha2.PreApplyHook, ac *v1alpha2.ApplicationConfiguration, w []Workload) error
+
+// Call the supplied hook with the supplied workloads of the supplied
+// ApplicationConfiguration.
+func (fn PreApplyHookCallerFn) Call(ctx context.Context, h *v1alpha2.PreApplyHook, ac *v1alpha2.ApplicationConfiguration, w []Workload) error {
+	return fn(ctx, h, ac, w)
+}
+
+// A hookRequest is the JSON body POSTed to a pre-apply hook.
+type hookRequest struct {
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Workloads []hookWorkload `json:"workloads"`
+}
+
+// A hookWorkload is a rendered workload in a hookRequest. Workloads that may
+// contain decrypted parameter values are redacted, i.e. omitted.
+type hookWorkload struct {
+	ComponentName string                 `json:"componentName"`
+	Workload      map[string]interface{} `json:"workload,omitempty"`
+	Redacted      bool                   `json:"redacted,omitempty"`
+}
+
+// An httpsHookCaller calls pre-apply hooks served via HTTPS by a Service in
+// the namespace of their ApplicationConfiguration, so that an
+// ApplicationConfiguration cannot use the controller to send its workloads
+// to arbitrary hosts.
+type httpsHookCaller struct {
+	kube client.Reader
+
+	// dial connects to hooks. The default dialer is used if it is nil.
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func (c *httpsHookCaller) Call(ctx context.Context, h *v1alpha2.PreApplyHook, ac *v1alpha2.ApplicationConfiguration, w []Workload) error {
+	svc := &corev1.Service{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: h.Service.Name}, svc); err != nil {
+		return errors.Wrapf(err, errFmtGetHookService, h.Service.Name)
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return errors.Errorf(errFmtHookExternalName, h.Service.Name)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(h.CABundle) {
+		return errors.New(errHookCABundle)
+	}
+
+	hr := hookRequest{Namespace: ac.GetNamespace(), Name: ac.GetName(), Workloads: make([]hookWorkload, len(w))}
+	for i := range w {
+		hr.Workloads[i] = hookWorkload{ComponentName: w[i].ComponentName, Redacted: w[i].Decrypted}
+		if !w[i].Decrypted {
+			hr.Workloads[i].Workload = w[i].Workload.UnstructuredContent()
+		}
+	}
+	body, err := json.Marshal(hr)
+	if err != nil {
+		return errors.Wrap(err, errMarshalHookRequest)
+	}
+
+	hctx, cancel := context.WithTimeout(ctx, hookTimeout(h))
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, hookURL(ac.GetNamespace(), h.Service), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewHookRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	t := &http.Transport{
+		DialContext:     c.dial,
+		TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+	}
+	defer t.CloseIdleConnections()
+	hc := &http.Client{
+		Transport: t,
+		Timeout:   maxHookTimeout,
+		// Redirects could send the workloads to a host that is not allowed.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	rsp, err := hc.Do(req.WithContext(hctx))
+	if err != nil {
+		return errors.Wrap(err, errCallHook)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+	// Drain the body so that the connection may be reused.
+	_, _ = io.Copy(ioutil.Discard, rsp.Body)
+
+	if rsp.StatusCode != http.StatusOK {
+		return errors.Errorf(errFmtHookStatus, rsp.Status)
+	}
+	return nil
+}
+
+// hookURL returns the URL of a hook served by the supplied Service in the
+// supplied namespace.
+func hookURL(namespace string, s v1alpha2.HookServiceReference) string {
+	port := defaultHookPort
+	if s.Port != nil {
+		port = int(*s.Port)
+	}
+	path := s.Path
+	if path == "" {
+		path = "/"
+	}
+	u := url.URL{Scheme: "https", Host: net.JoinHostPort(s.Name+"."+namespace+".svc", strconv.Itoa(port)), Path: path}
+	return u.String()
+}
+
+// hookTimeout returns the deadline of each call to the supplied hook.
+func hookTimeout(h *v1alpha2.PreApplyHook) time.Duration {
+	if h.TimeoutSeconds == nil || *h.TimeoutSeconds <= 0 {
+		return defaultHookTimeout
+	}
+	if t := time.Duration(*h.TimeoutSeconds) * time.Second; t < maxHookTimeout {
+		return t
+	}
+	return maxHookTimeout
+}
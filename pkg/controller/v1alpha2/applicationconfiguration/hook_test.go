@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// servingCert returns a self-signed serving certificate for the supplied
+// host, and its PEM encoding.
+func servingCert(t *testing.T, host string) (tls.Certificate, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: host},
+		DNSNames:              []string{host},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestHTTPSHookCaller(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var got hookRequest
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	cert, caBundle := servingCert(t, "hook.ns.svc")
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	// Connections to any Service are sent to the test server.
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	service := func(t corev1.ServiceType) *test.MockClient {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.Service).Spec.Type = t
+			return nil
+		})}
+	}
+
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"}}
+	wl := &unstructured.Unstructured{}
+	wl.SetAPIVersion("example.org/v1")
+	wl.SetKind("Workload")
+	wl.SetName("workload")
+	hook := func(path string, caBundle []byte) *v1alpha2.PreApplyHook {
+		return &v1alpha2.PreApplyHook{Service: v1alpha2.HookServiceReference{Name: "hook", Path: path}, CABundle: caBundle}
+	}
+
+	type want struct {
+		req hookRequest
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   *test.MockClient
+		hook   *v1alpha2.PreApplyHook
+		w      []Workload
+		want   want
+	}{
+		"OK": {
+			reason: "A 200 OK response should succeed",
+			kube:   service(corev1.ServiceTypeClusterIP),
+			hook:   hook("/ok", caBundle),
+			w:      []Workload{{ComponentName: "c", Workload: wl}},
+			want:   want{req: hookRequest{Namespace: "ns", Name: "cool", Workloads: []hookWorkload{{ComponentName: "c", Workload: wl.UnstructuredContent()}}}},
+		},
+		"Forbidden": {
+			reason: "Any other response should fail",
+			kube:   service(corev1.ServiceTypeClusterIP),
+			hook:   hook("/forbidden", caBundle),
+			w:      []Workload{{ComponentName: "c", Workload: wl}},
+			want: want{
+				req: hookRequest{Namespace: "ns", Name: "cool", Workloads: []hookWorkload{{ComponentName: "c", Workload: wl.UnstructuredContent()}}},
+				err: errors.Errorf(errFmtHookStatus, "403 Forbidden"),
+			},
+		},
+		"Redacted": {
+			reason: "Workloads that may contain decrypted parameter values should not be sent",
+			kube:   service(corev1.ServiceTypeClusterIP),
+			hook:   hook("/ok", caBundle),
+			w:      []Workload{{ComponentName: "c", Workload: wl, Decrypted: true}},
+			want:   want{req: hookRequest{Namespace: "ns", Name: "cool", Workloads: []hookWorkload{{ComponentName: "c", Redacted: true}}}},
+		},
+		"GetServiceError": {
+			reason: "Errors getting the hook's Service should be returned",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			hook:   hook("/ok", caBundle),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetHookService, "hook")},
+		},
+		"ExternalName": {
+			reason: "Hooks served by an ExternalName Service should not be called",
+			kube:   service(corev1.ServiceTypeExternalName),
+			hook:   hook("/ok", caBundle),
+			want:   want{err: errors.Errorf(errFmtHookExternalName, "hook")},
+		},
+		"InvalidCABundle": {
+			reason: "Hooks without a valid CA bundle should not be called",
+			kube:   service(corev1.ServiceTypeClusterIP),
+			hook:   hook("/ok", []byte("nope")),
+			want:   want{err: errors.New(errHookCABundle)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got = hookRequest{}
+			c := &httpsHookCaller{kube: tc.kube, dial: dial}
+			err := c.Call(context.Background(), tc.hook, ac, tc.w)
+			if diff := cmp.Diff(tc.want, want{req: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Call(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	short, long := int32(5), int32(3600)
+
+	cases := map[string]struct {
+		reason  string
+		seconds *int32
+		want    time.Duration
+	}{
+		"Default": {
+			reason: "Hooks without a timeout should use the default timeout",
+			want:   defaultHookTimeout,
+		},
+		"Specified": {
+			reason:  "Hooks should use their timeout",
+			seconds: &short,
+			want:    5 * time.Second,
+		},
+		"Capped": {
+			reason:  "Hook timeouts should be capped",
+			seconds: &long,
+			want:    maxHookTimeout,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := hookTimeout(&v1alpha2.PreApplyHook{TimeoutSeconds: tc.seconds})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhookTimeout(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// podHostNetworkPath is the field path of the host network mode of workloads
+// that embed a pod template.
+const podHostNetworkPath = "spec.template.spec.hostNetwork"
+
+// injectHostNetwork enables host network mode in the pod template of the
+// supplied workload. It returns false if host network mode is to be enabled
+// but the workload has no pod template.
+func injectHostNetwork(w *unstructured.Unstructured, enabled bool) (bool, error) {
+	if !enabled {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	return true, p.SetValue(podHostNetworkPath, true)
+}
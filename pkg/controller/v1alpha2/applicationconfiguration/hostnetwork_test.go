@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInjectHostNetwork(t *testing.T) {
+	workload := func(hostNetwork bool) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "c0"}}}
+		if hostNetwork {
+			spec["hostNetwork"] = true
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason  string
+		w       *unstructured.Unstructured
+		enabled bool
+		want    want
+	}{
+		"Disabled": {
+			reason: "A workload should be unchanged when host network mode is not enabled",
+			w:      workload(false),
+			want:   want{w: workload(false), injected: true},
+		},
+		"Enabled": {
+			reason:  "Host network mode should be enabled in the pod template",
+			w:       workload(false),
+			enabled: true,
+			want:    want{w: workload(true), injected: true},
+		},
+		"NoPodTemplate": {
+			reason:  "A workload without a pod template should be unchanged, and reported as such",
+			w:       &unstructured.Unstructured{Object: map[string]interface{}{}},
+			enabled: true,
+			want:    want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectHostNetwork(tc.w, tc.enabled)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectHostNetwork(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectHostNetwork(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
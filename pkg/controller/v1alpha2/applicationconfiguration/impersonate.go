@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Impersonation error strings.
+const (
+	errFmtGetServiceAccount = "cannot get service account %q"
+	errFmtNewClient         = "cannot create client impersonating service account %q"
+	errFmtForbidden         = "service account %q is not permitted to apply %s %q"
+)
+
+// An Impersonator returns clients that act as a service account.
+type Impersonator interface {
+	// Impersonate the supplied service account.
+	Impersonate(ctx context.Context, namespace, name string) (client.Client, error)
+}
+
+// An ImpersonatorFn returns clients that act as a service account.
+type ImpersonatorFn func(ctx context.Context, namespace, name string) (client.Client, error)
+
+// Impersonate the supplied service account.
+func (fn ImpersonatorFn) Impersonate(ctx context.Context, namespace, name string) (client.Client, error) {
+	return fn(ctx, namespace, name)
+}
+
+// A restImpersonator returns clients that send impersonation headers for the
+// supplied service account. Clients are cached by the user they impersonate.
+type restImpersonator struct {
+	client client.Client
+	config *rest.Config
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	clients map[string]client.Client
+}
+
+func (i *restImpersonator) Impersonate(ctx context.Context, namespace, name string) (client.Client, error) {
+	// Kubernetes would happily impersonate a service account that does not
+	// exist, so we make sure it does to surface misconfiguration early.
+	sa := &corev1.ServiceAccount{}
+	if err := i.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, sa); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetServiceAccount, name)
+	}
+
+	user := serviceAccountUser(namespace, name)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if c, ok := i.clients[user]; ok {
+		return c, nil
+	}
+
+	cfg := rest.CopyConfig(i.config)
+	cfg.Impersonate = rest.ImpersonationConfig{UserName: user}
+	c, err := client.New(cfg, client.Options{Scheme: i.scheme})
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtNewClient, name)
+	}
+	if i.clients == nil {
+		i.clients = make(map[string]client.Client)
+	}
+	i.clients[user] = c
+	return c, nil
+}
+
+// serviceAccountUser returns the name of the user as which the supplied
+// service account authenticates.
+func serviceAccountUser(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// explainForbidden wraps the supplied error with a more helpful message if it
+// indicates that the supplied service account was not permitted to apply the
+// supplied object.
+func explainForbidden(err error, serviceAccount, kind, name string) error {
+	if serviceAccount == "" || !kerrors.IsForbidden(errors.Cause(err)) {
+		return err
+	}
+	return errors.Wrapf(err, errFmtForbidden, serviceAccount, kind, name)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRestImpersonator(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{Resource: "serviceaccounts"}, "sa")
+
+	cases := map[string]struct {
+		reason string
+		client *test.MockClient
+		want   error
+	}{
+		"GetServiceAccountError": {
+			reason: "Errors getting the service account should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrapf(errBoom, errFmtGetServiceAccount, "sa"),
+		},
+		"ServiceAccountNotFound": {
+			reason: "Service accounts that do not exist should not be impersonated",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+			want:   errors.Wrapf(notFound, errFmtGetServiceAccount, "sa"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i := &restImpersonator{client: tc.client, config: &rest.Config{}}
+			_, err := i.Impersonate(context.Background(), "ns", "sa")
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ni.Impersonate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExplainForbidden(t *testing.T) {
+	errBoom := errors.New("boom")
+	forbidden := kerrors.NewForbidden(schema.GroupResource{Resource: "deployments"}, "cool", errBoom)
+
+	type args struct {
+		err            error
+		serviceAccount string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotForbidden": {
+			reason: "Errors that are not forbidden errors should be returned unchanged",
+			args:   args{err: errBoom, serviceAccount: "sa"},
+			want:   errBoom,
+		},
+		"NotImpersonated": {
+			reason: "Forbidden errors encountered by the controller itself should be returned unchanged",
+			args:   args{err: forbidden},
+			want:   forbidden,
+		},
+		"Forbidden": {
+			reason: "Forbidden errors encountered while impersonating should name the service account",
+			args:   args{err: errors.Wrap(forbidden, "cannot get object"), serviceAccount: "sa"},
+			want:   errors.Wrapf(errors.Wrap(forbidden, "cannot get object"), errFmtForbidden, "sa", "Deployment", "cool"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := explainForbidden(tc.args.err, tc.args.serviceAccount, "Deployment", "cool")
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nexplainForbidden(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceAccountUser(t *testing.T) {
+	want := "system:serviceaccount:ns:sa"
+	if diff := cmp.Diff(want, serviceAccountUser("ns", "sa")); diff != "" {
+		t.Errorf("serviceAccountUser(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Kustomize schematic error strings.
+const (
+	errFmtRenderKustomize    = "cannot render kustomize schematic %q"
+	errFmtCloneRepo          = "cannot clone repository %q"
+	errFmtFetchRepo          = "cannot fetch repository %q"
+	errFmtCheckoutRepo       = "cannot check out repository %q"
+	errBuildKustomization    = "cannot build kustomization"
+	errFmtKustomizePath      = "path %q is not within the repository"
+	errFmtInvalidFieldPath   = "invalid field path %q"
+	errFmtPatchParameter     = "cannot patch parameter %q"
+	errWriteKustomization    = "cannot write kustomization"
+	errDecodeKustomizeOutput = "cannot decode kustomize build output"
+)
+
+// A KustomizePatch is a JSON patch of the manifests of one kind in the output
+// of a kustomization.
+type KustomizePatch struct {
+	// APIVersion of the patched manifests.
+	APIVersion string
+
+	// Kind of the patched manifests.
+	Kind string
+
+	// Operations of the patch.
+	Operations []JSONPatchOperation
+}
+
+// A JSONPatchOperation is an RFC 6902 JSON patch operation.
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// A KustomizeSchematicRenderer renders the YAML manifests of a workload
+// schematic that is stored as a kustomization.
+type KustomizeSchematicRenderer interface {
+	// Render the manifests of the supplied kustomization, with the supplied
+	// patches applied.
+	Render(ctx context.Context, s v1alpha2.KustomizeSchematic, p []KustomizePatch) ([]unstructured.Unstructured, error)
+}
+
+// A KustomizeSchematicRendererFn renders the YAML manifests of a workload
+// schematic that is stored as a kustomization.
+type KustomizeSchematicRendererFn func(ctx context.Context, s v1alpha2.KustomizeSchematic, p []KustomizePatch) ([]unstructured.Unstructured, error)
+
+// Render the manifests of the supplied kustomization, with the supplied
+// patches applied.
+func (fn KustomizeSchematicRendererFn) Render(ctx context.Context, s v1alpha2.KustomizeSchematic, p []KustomizePatch) ([]unstructured.Unstructured, error) {
+	return fn(ctx, s, p)
+}
+
+// A GitKustomizeRenderer renders kustomize schematics by cloning their Git
+// repository and building an overlay of the referenced kustomization that
+// contains the supplied patches.
+//
+// Repositories are cloned once and fetched before each render. Rendered
+// manifests are cached by commit, so a kustomization is built only once per
+// commit and set of patches.
+type GitKustomizeRenderer struct {
+	// dir in which repositories are cloned.
+	dir string
+
+	mu    sync.Mutex
+	cache map[string][]unstructured.Unstructured
+}
+
+// NewKustomizeSchematicRenderer returns a KustomizeSchematicRenderer that
+// clones repositories into the supplied directory.
+func NewKustomizeSchematicRenderer(dir string) *GitKustomizeRenderer {
+	return &GitKustomizeRenderer{dir: dir, cache: make(map[string][]unstructured.Unstructured)}
+}
+
+// Render the manifests of the supplied kustomization, with the supplied
+// patches applied. The manifests are returned from the cache if the
+// repository's commit was rendered with the same patches before.
+// Kustomizations read from a local path are never cached.
+func (r *GitKustomizeRenderer) Render(ctx context.Context, s v1alpha2.KustomizeSchematic, p []KustomizePatch) ([]unstructured.Unstructured, error) {
+	// Checkouts are serialised, so that a repository is never fetched while
+	// it is being built.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	root, commit, err := r.checkout(ctx, s.URL)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(root, filepath.FromSlash(s.Path))
+	if rel, err := filepath.Rel(root, base); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, errors.Errorf(errFmtKustomizePath, s.Path)
+	}
+
+	key := ""
+	if commit != "" {
+		b, _ := json.Marshal(p) // nolint:errcheck
+		key = fmt.Sprintf("%s@%s//%s:%x", s.URL, commit, s.Path, sha256.Sum256(b))
+		if m, ok := r.cache[key]; ok {
+			return copyManifests(m), nil
+		}
+	}
+
+	manifests, err := r.build(base, p)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		r.cache[key] = manifests
+	}
+	return copyManifests(manifests), nil
+}
+
+// checkout returns the local path of the supplied repository, and the commit
+// it is checked out at. Local paths are returned as is, without a commit.
+func (r *GitKustomizeRenderer) checkout(ctx context.Context, url string) (string, string, error) {
+	if filepath.IsAbs(url) {
+		return url, "", nil
+	}
+	if strings.HasPrefix(url, "file://") {
+		return strings.TrimPrefix(url, "file://"), "", nil
+	}
+
+	dir := filepath.Join(r.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(url)))[:16])
+	var repo *git.Repository
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		repo, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{URL: url, Depth: 1})
+		if err != nil {
+			return "", "", errors.Wrapf(err, errFmtCloneRepo, url)
+		}
+	} else {
+		repo, err = git.PlainOpen(dir)
+		if err != nil {
+			return "", "", errors.Wrapf(err, errFmtCheckoutRepo, url)
+		}
+		if err := fetch(ctx, repo); err != nil {
+			return "", "", errors.Wrapf(err, errFmtFetchRepo, url)
+		}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", errors.Wrapf(err, errFmtCheckoutRepo, url)
+	}
+	return dir, head.Hash().String(), nil
+}
+
+// fetch the branch the supplied repository was cloned at, and hard reset its
+// worktree to the fetched commit.
+func fetch(ctx context.Context, repo *git.Repository) error {
+	err := repo.FetchContext(ctx, &git.FetchOptions{Depth: 1, Force: true})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, head.Name().Short()), true)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return wt.Reset(&git.ResetOptions{Commit: remote.Hash(), Mode: git.HardReset})
+}
+
+// A kustomization is an overlay of a schematic's kustomization.
+type kustomization struct {
+	Resources []string             `json:"resources"`
+	Patches   []kustomizationPatch `json:"patches,omitempty"`
+}
+
+type kustomizationPatch struct {
+	Path   string              `json:"path"`
+	Target kustomizationTarget `json:"target"`
+}
+
+type kustomizationTarget struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind"`
+}
+
+// build an overlay of the kustomization at the supplied path that applies the
+// supplied patches.
+func (r *GitKustomizeRenderer) build(base string, p []KustomizePatch) ([]unstructured.Unstructured, error) {
+	overlay, err := ioutil.TempDir("", "oam-kustomize-")
+	if err != nil {
+		return nil, errors.Wrap(err, errWriteKustomization)
+	}
+	defer os.RemoveAll(overlay) // nolint:errcheck
+
+	if err := writeKustomization(overlay, base, p); err != nil {
+		return nil, errors.Wrap(err, errWriteKustomization)
+	}
+	rm, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildKustomization)
+	}
+	out, err := rm.AsYaml()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildKustomization)
+	}
+	m, err := decodeManifests(bytes.NewReader(out))
+	return m, errors.Wrap(err, errDecodeKustomizeOutput)
+}
+
+// writeKustomization writes a kustomization to the supplied overlay directory
+// that includes the supplied base kustomization and applies the supplied
+// patches to it.
+func writeKustomization(overlay, base string, p []KustomizePatch) error {
+	// Kustomize only loads bases that are referenced by relative paths.
+	rel, err := filepath.Rel(overlay, base)
+	if err != nil {
+		return err
+	}
+	k := kustomization{Resources: []string{filepath.ToSlash(rel)}}
+	for i, patch := range p {
+		b, err := json.Marshal(patch.Operations)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("patch-%d.json", i)
+		if err := ioutil.WriteFile(filepath.Join(overlay, name), b, 0600); err != nil {
+			return err
+		}
+		gv, err := schema.ParseGroupVersion(patch.APIVersion)
+		if err != nil {
+			return err
+		}
+		k.Patches = append(k.Patches, kustomizationPatch{
+			Path:   name,
+			Target: kustomizationTarget{Group: gv.Group, Version: gv.Version, Kind: patch.Kind},
+		})
+	}
+	// JSON is valid YAML.
+	b, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(overlay, "kustomization.yaml"), b, 0600)
+}
+
+// kustomizePatches returns the supplied parameters as a patch of the manifests
+// of the supplied workload's kind. No patches are returned if there are no
+// parameters.
+func kustomizePatches(w *unstructured.Unstructured, p []Parameter) ([]KustomizePatch, error) {
+	ops := make([]JSONPatchOperation, 0)
+	for _, param := range p {
+		var v interface{} = param.Value.StrVal
+		if param.Value.Type == intstr.Int {
+			v = int64(param.Value.IntVal)
+		}
+		for _, fp := range param.FieldPaths {
+			ptr, index, err := jsonPointer(fp)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtPatchParameter, param.Name)
+			}
+			// Adding an array element would insert it rather than
+			// replace it; adding an object member replaces it if it
+			// already exists.
+			op := "add"
+			if index {
+				op = "replace"
+			}
+			ops = append(ops, JSONPatchOperation{Op: op, Path: ptr, Value: v})
+		}
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return []KustomizePatch{{APIVersion: w.GetAPIVersion(), Kind: w.GetKind(), Operations: ops}}, nil
+}
+
+// jsonPointer returns the RFC 6901 JSON pointer of the supplied field path,
+// e.g. /spec/containers/0/image for spec.containers[0].image, and whether its
+// last segment is an array index.
+func jsonPointer(path string) (string, bool, error) {
+	var segments []string
+	index := false
+	rest := path
+	for rest != "" {
+		switch {
+		case rest[0] == '.' && len(segments) > 0:
+			rest = rest[1:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return "", false, errors.Errorf(errFmtInvalidFieldPath, path)
+			}
+			s := rest[1:end]
+			rest = rest[end+1:]
+			if _, err := strconv.Atoi(s); err == nil {
+				segments, index = append(segments, s), true
+				continue
+			}
+			segments, index = append(segments, strings.Trim(s, `"'`)), false
+			continue
+		}
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return "", false, errors.Errorf(errFmtInvalidFieldPath, path)
+		}
+		segments, index = append(segments, rest[:end]), false
+		rest = rest[end:]
+	}
+	if len(segments) == 0 {
+		return "", false, errors.Errorf(errFmtInvalidFieldPath, path)
+	}
+	r := strings.NewReplacer("~", "~0", "/", "~1")
+	for i := range segments {
+		segments[i] = r.Replace(segments[i])
+	}
+	return "/" + strings.Join(segments, "/"), index, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestApplyKustomizeSchematic(t *testing.T) {
+	errBoom := errors.New("boom")
+	url := "https://github.com/example/schematics"
+	raw := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"}}`)
+	params := []Parameter{{Name: "image", Value: intstr.FromString("nginx:1.19"), FieldPaths: []string{"spec.template.spec.containers[0].image"}}}
+
+	get := func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+		obj.(*v1alpha2.WorkloadDefinition).Spec.Schematic = &v1alpha2.Schematic{Kustomize: &v1alpha2.KustomizeSchematic{URL: url, Path: "deployment"}}
+		return nil
+	}
+	built := []unstructured.Unstructured{{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "template"},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+	}}}
+
+	type want struct {
+		raw     string
+		patches []KustomizePatch
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"RenderError": {
+			reason: "Errors rendering a kustomization should be returned",
+			err:    errBoom,
+			want: want{
+				patches: []KustomizePatch{{APIVersion: "apps/v1", Kind: "Deployment", Operations: []JSONPatchOperation{
+					{Op: "add", Path: "/spec/template/spec/containers/0/image", Value: "nginx:1.19"},
+				}}},
+				err: errors.Wrapf(errBoom, errFmtRenderKustomize, url),
+			},
+		},
+		"Merged": {
+			reason: "Parameters should be applied as patches, and workloads rendered on top of the built manifest of their kind",
+			want: want{
+				raw: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"},"spec":{"replicas":2}}`,
+				patches: []KustomizePatch{{APIVersion: "apps/v1", Kind: "Deployment", Operations: []JSONPatchOperation{
+					{Op: "add", Path: "/spec/template/spec/containers/0/image", Value: "nginx:1.19"},
+				}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patches []KustomizePatch
+			kr := KustomizeSchematicRendererFn(func(_ context.Context, s v1alpha2.KustomizeSchematic, p []KustomizePatch) ([]unstructured.Unstructured, error) {
+				patches = p
+				return built, tc.err
+			})
+			r := &components{client: &test.MockClient{MockGet: get}, kustomize: kr}
+			b, err := r.applySchematic(context.Background(), raw, params)
+			got := want{raw: string(b), patches: patches, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.applySchematic(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	type want struct {
+		ptr   string
+		index bool
+		err   error
+	}
+	cases := map[string]struct {
+		path string
+		want want
+	}{
+		"Fields":      {path: "spec.replicas", want: want{ptr: "/spec/replicas"}},
+		"Index":       {path: "spec.containers[0].image", want: want{ptr: "/spec/containers/0/image"}},
+		"TrailingIdx": {path: "spec.args[1]", want: want{ptr: "/spec/args/1", index: true}},
+		"QuotedKey":   {path: `metadata.labels["oam.dev/app"]`, want: want{ptr: "/metadata/labels/oam.dev~1app"}},
+		"Empty":       {path: "", want: want{err: errors.Errorf(errFmtInvalidFieldPath, "")}},
+		"Unclosed":    {path: "spec.args[1", want: want{err: errors.Errorf(errFmtInvalidFieldPath, "spec.args[1")}},
+		"EmptyField":  {path: "spec..replicas", want: want{err: errors.Errorf(errFmtInvalidFieldPath, "spec..replicas")}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ptr, index, err := jsonPointer(tc.path)
+			got := want{ptr: ptr, index: index, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("jsonPointer(%q): -want, +got:\n%s", tc.path, diff)
+			}
+		})
+	}
+}
+
+func TestWriteKustomization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay := filepath.Join(dir, "overlay")
+	base := filepath.Join(dir, "repo", "deployment")
+	for _, d := range []string{overlay, base} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	p := []KustomizePatch{{APIVersion: "apps/v1", Kind: "Deployment", Operations: []JSONPatchOperation{{Op: "add", Path: "/spec/replicas", Value: int64(3)}}}}
+	if err := writeKustomization(overlay, base, p); err != nil {
+		t.Fatalf("writeKustomization(...): %s", err)
+	}
+
+	for file, want := range map[string]string{
+		"kustomization.yaml": `{"resources":["../repo/deployment"],"patches":[{"path":"patch-0.json","target":{"group":"apps","version":"v1","kind":"Deployment"}}]}`,
+		"patch-0.json":       `[{"op":"add","path":"/spec/replicas","value":3}]`,
+	} {
+		got, err := ioutil.ReadFile(filepath.Join(overlay, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("writeKustomization(...): -want %s, +got %s:\n%s", file, file, diff)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// A LabelFilter determines which labels of an ApplicationConfiguration are
+// propagated to its workloads and traits.
+type LabelFilter interface {
+	// Propagate returns true if the label with the supplied key should be
+	// propagated.
+	Propagate(key string) bool
+}
+
+// A LabelFilterFn determines which labels of an ApplicationConfiguration are
+// propagated to its workloads and traits.
+type LabelFilterFn func(key string) bool
+
+// Propagate returns true if the label with the supplied key should be
+// propagated.
+func (fn LabelFilterFn) Propagate(key string) bool {
+	return fn(key)
+}
+
+// systemLabels are never propagated.
+var systemLabels = map[string]bool{
+	"app.kubernetes.io/managed-by": true,
+}
+
+// systemLabelDomains are the domains, and their subdomains, whose prefixed
+// labels are never propagated. The app.kubernetes.io recommended labels are
+// the exception.
+var systemLabelDomains = []string{"kubernetes.io", "k8s.io", "helm.sh", "oam.dev"}
+
+// ExcludeSystemLabels propagates all labels except those that are managed by
+// Kubernetes, Helm, or the OAM runtime, e.g. app.kubernetes.io/managed-by or
+// oam.dev/component-uid.
+var ExcludeSystemLabels = LabelFilterFn(func(key string) bool {
+	if systemLabels[key] {
+		return false
+	}
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return true
+	}
+	domain := key[:i]
+	if domain == "app.kubernetes.io" {
+		return true
+	}
+	for _, d := range systemLabelDomains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return false
+		}
+	}
+	return true
+})
+
+// A LabelPropagator propagates the labels of an ApplicationConfiguration to
+// its rendered workloads and traits.
+type LabelPropagator struct {
+	filter LabelFilter
+}
+
+// NewLabelPropagator returns a LabelPropagator that propagates the labels
+// accepted by the supplied filter.
+func NewLabelPropagator(f LabelFilter) *LabelPropagator {
+	return &LabelPropagator{filter: f}
+}
+
+// Propagate the supplied labels to the supplied object. Labels the object
+// already has take precedence over propagated labels. The keys of the labels
+// that were propagated are recorded in the oam.dev/propagated-labels
+// annotation, so that they may be told apart from labels added by the
+// component or by other controllers.
+func (p *LabelPropagator) Propagate(labels map[string]string, o *unstructured.Unstructured) {
+	existing := o.GetLabels()
+	propagated := make(map[string]string)
+	for k, v := range labels {
+		if _, ok := existing[k]; ok || !p.filter.Propagate(k) {
+			continue
+		}
+		propagated[k] = v
+	}
+	if len(propagated) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(propagated))
+	for k := range propagated {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	meta.AddLabels(o, propagated)
+	meta.AddAnnotations(o, map[string]string{oam.AnnotationPropagatedLabels: strings.Join(keys, ",")})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestExcludeSystemLabels(t *testing.T) {
+	cases := map[string]bool{
+		"team":                         true,
+		"example.org/cost-centre":      true,
+		"app.kubernetes.io/name":       true,
+		"app.kubernetes.io/managed-by": false,
+		"kubernetes.io/metadata.name":  false,
+		"node.kubernetes.io/instance":  false,
+		"k8s.io/cluster-service":       false,
+		"helm.sh/chart":                false,
+		"oam.dev/component-uid":        false,
+		"core.oam.dev/whatever":        false,
+	}
+
+	for key, want := range cases {
+		t.Run(key, func(t *testing.T) {
+			if got := ExcludeSystemLabels.Propagate(key); got != want {
+				t.Errorf("ExcludeSystemLabels.Propagate(%q): want %t, got %t", key, want, got)
+			}
+		})
+	}
+}
+
+func TestLabelPropagator(t *testing.T) {
+	labels := map[string]string{
+		"team":                         "platform",
+		"tier":                         "backend",
+		"app.kubernetes.io/managed-by": "Helm",
+	}
+
+	type want struct {
+		labels      map[string]string
+		annotations map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		labels map[string]string
+		o      map[string]string
+		want   want
+	}{
+		"NoLabels": {
+			reason: "Nothing should be propagated when the ApplicationConfiguration has no labels",
+			o:      map[string]string{"existing": "label"},
+			want:   want{labels: map[string]string{"existing": "label"}},
+		},
+		"Propagated": {
+			reason: "Labels accepted by the filter should be propagated and recorded",
+			labels: labels,
+			want: want{
+				labels:      map[string]string{"team": "platform", "tier": "backend"},
+				annotations: map[string]string{oam.AnnotationPropagatedLabels: "team,tier"},
+			},
+		},
+		"ExistingLabelsTakePrecedence": {
+			reason: "Labels the object already has should not be overwritten",
+			labels: labels,
+			o:      map[string]string{"team": "payments"},
+			want: want{
+				labels:      map[string]string{"team": "payments", "tier": "backend"},
+				annotations: map[string]string{oam.AnnotationPropagatedLabels: "tier"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &unstructured.Unstructured{}
+			o.SetLabels(tc.o)
+			NewLabelPropagator(ExcludeSystemLabels).Propagate(tc.labels, o)
+			got := want{labels: o.GetLabels(), annotations: o.GetAnnotations()}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\np.Propagate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// defaultLastAppliedSpecLimit is the default maximum size in bytes of the
+// last applied spec recorded in the status of each workload.
+const defaultLastAppliedSpecLimit = 100 * 1024
+
+// lastAppliedSpecLimit returns the maximum size in bytes of the last applied
+// spec recorded in the status of each workload of the supplied
+// ApplicationConfiguration. Invalid limits are ignored.
+func lastAppliedSpecLimit(ac *v1alpha2.ApplicationConfiguration) int {
+	v, ok := ac.GetAnnotations()[oam.AnnotationLastAppliedSpecLimit]
+	if !ok {
+		return defaultLastAppliedSpecLimit
+	}
+	q, err := resource.ParseQuantity(v)
+	if err != nil || q.Sign() < 0 {
+		return defaultLastAppliedSpecLimit
+	}
+	return int(q.Value())
+}
+
+// recordLastAppliedSpecs records each of the supplied workloads as the last
+// applied spec of the corresponding workload status, truncating those larger
+// than the supplied limit.
+func recordLastAppliedSpecs(ws []v1alpha2.WorkloadStatus, w []Workload, limit int) {
+	for i := range w {
+		if i >= len(ws) || w[i].Workload == nil {
+			continue
+		}
+		raw, err := json.Marshal(w[i].Workload)
+		if err != nil {
+			// We'd rather omit the spec than fail to record the status.
+			ws[i].LastAppliedSpec = nil
+			continue
+		}
+		ws[i].LastAppliedSpec = &runtime.RawExtension{Raw: truncateSpec(w[i], raw, limit)}
+	}
+}
+
+// truncateSpec returns the supplied raw workload if it is within the supplied
+// limit. Otherwise it returns the workload's type and name, annotated as
+// truncated, with as much of the raw workload as fits within the limit.
+func truncateSpec(w Workload, raw []byte, limit int) []byte {
+	if len(raw) <= limit {
+		return raw
+	}
+
+	type metadata struct {
+		Name        string            `json:"name,omitempty"`
+		Namespace   string            `json:"namespace,omitempty"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	type truncated struct {
+		APIVersion string   `json:"apiVersion,omitempty"`
+		Kind       string   `json:"kind,omitempty"`
+		Metadata   metadata `json:"metadata"`
+		Truncated  string   `json:"truncated"`
+	}
+	t := truncated{
+		APIVersion: w.Workload.GetAPIVersion(),
+		Kind:       w.Workload.GetKind(),
+		Metadata: metadata{
+			Name:        w.Workload.GetName(),
+			Namespace:   w.Workload.GetNamespace(),
+			Annotations: map[string]string{oam.AnnotationSpecTruncated: "true"},
+		},
+	}
+
+	// Escaping the truncated workload as a JSON string may grow it, so we
+	// shrink it until it fits.
+	n := limit
+	for {
+		if n > len(raw) {
+			n = len(raw)
+		}
+		if n < 0 {
+			n = 0
+		}
+		t.Truncated = string(raw[:n])
+		out, err := json.Marshal(t)
+		if err != nil || len(out) <= limit || n == 0 {
+			return out
+		}
+		n -= len(out) - limit
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestLastAppliedSpecLimit(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        int
+	}{
+		"Default": {
+			reason: "The default limit should be used when the annotation is not set",
+			want:   defaultLastAppliedSpecLimit,
+		},
+		"Quantity": {
+			reason:      "The limit should be parsed as a quantity",
+			annotations: map[string]string{oam.AnnotationLastAppliedSpecLimit: "10Ki"},
+			want:        10 * 1024,
+		},
+		"Invalid": {
+			reason:      "The default limit should be used when the annotation is invalid",
+			annotations: map[string]string{oam.AnnotationLastAppliedSpecLimit: "lots"},
+			want:        defaultLastAppliedSpecLimit,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if diff := cmp.Diff(tc.want, lastAppliedSpecLimit(ac)); diff != "" {
+				t.Errorf("\n%s\nlastAppliedSpecLimit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordLastAppliedSpecs(t *testing.T) {
+	workload := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "ns"},
+		"spec":       map[string]interface{}{"image": strings.Repeat("x", 512)},
+	}}
+	raw, _ := json.Marshal(workload)
+
+	type truncated struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name        string            `json:"name"`
+			Namespace   string            `json:"namespace"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Truncated string `json:"truncated"`
+	}
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		ws := []v1alpha2.WorkloadStatus{{}}
+		recordLastAppliedSpecs(ws, []Workload{{Workload: workload}}, len(raw))
+		if diff := cmp.Diff(string(raw), string(ws[0].LastAppliedSpec.Raw)); diff != "" {
+			t.Errorf("recordLastAppliedSpecs(...): -want, +got:\n%s", diff)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		limit := 256
+		ws := []v1alpha2.WorkloadStatus{{}}
+		recordLastAppliedSpecs(ws, []Workload{{Workload: workload}}, limit)
+
+		got := ws[0].LastAppliedSpec.Raw
+		if len(got) > limit {
+			t.Errorf("recordLastAppliedSpecs(...): want at most %d bytes, got %d", limit, len(got))
+		}
+		tr := truncated{}
+		if err := json.Unmarshal(got, &tr); err != nil {
+			t.Fatalf("recordLastAppliedSpecs(...): want valid JSON, got error %s", err)
+		}
+		if tr.Kind != "Deployment" || tr.Metadata.Name != "web" || tr.Metadata.Annotations[oam.AnnotationSpecTruncated] != "true" {
+			t.Errorf("recordLastAppliedSpecs(...): want annotated Deployment web, got %+v", tr)
+		}
+		if !strings.HasPrefix(string(raw), tr.Truncated) || tr.Truncated == "" {
+			t.Errorf("recordLastAppliedSpecs(...): want a prefix of the workload, got %q", tr.Truncated)
+		}
+	})
+}
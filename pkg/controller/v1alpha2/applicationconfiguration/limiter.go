@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// defaultMaxConcurrentApply is the number of workloads that are applied at
+// once when an ApplicationConfiguration does not specify a limit.
+const defaultMaxConcurrentApply = 4
+
+// An applyLimiter is a semaphore that limits how many workloads are applied at
+// once.
+type applyLimiter chan struct{}
+
+func newApplyLimiter(n int) applyLimiter {
+	if n < 1 {
+		n = 1
+	}
+	return make(applyLimiter, n)
+}
+
+// Acquire a slot, blocking until one is free. It returns immediately if the
+// supplied context is cancelled while waiting.
+func (l applyLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release a slot acquired by Acquire.
+func (l applyLimiter) Release() {
+	<-l
+}
+
+type maxConcurrentApplyKey struct{}
+
+// withMaxConcurrentApply returns a context that limits how many workloads a
+// WorkloadApplicator applies at once. The limit is passed via the context
+// because it is a property of the ApplicationConfiguration being applied,
+// not of the applicator.
+func withMaxConcurrentApply(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) context.Context {
+	n := defaultMaxConcurrentApply
+	if ac.Spec.MaxConcurrentApply != nil {
+		n = int(*ac.Spec.MaxConcurrentApply)
+	}
+	return context.WithValue(ctx, maxConcurrentApplyKey{}, n)
+}
+
+// maxConcurrentApply returns how many workloads may be applied at once.
+func maxConcurrentApply(ctx context.Context) int {
+	if n, ok := ctx.Value(maxConcurrentApplyKey{}).(int); ok {
+		return n
+	}
+	return defaultMaxConcurrentApply
+}
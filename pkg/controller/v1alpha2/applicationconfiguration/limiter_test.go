@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestApplyLimiter(t *testing.T) {
+	l := newApplyLimiter(1)
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Fatalf("l.Acquire(...): %s", err)
+	}
+
+	// The only slot is taken, so acquiring another must wait until the
+	// context is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if diff := cmp.Diff(context.Canceled, l.Acquire(ctx), test.EquateErrors()); diff != "" {
+		t.Errorf("l.Acquire(...): -want error, +got error:\n%s", diff)
+	}
+
+	l.Release()
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Errorf("l.Acquire(...): %s", err)
+	}
+}
+
+func TestMaxConcurrentApply(t *testing.T) {
+	two := int32(2)
+
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		want   int
+	}{
+		"NoLimit": {
+			reason: "The default limit should be used when none was set",
+			ctx:    context.Background(),
+			want:   defaultMaxConcurrentApply,
+		},
+		"DefaultLimit": {
+			reason: "The default limit should be used when the ApplicationConfiguration sets none",
+			ctx:    withMaxConcurrentApply(context.Background(), &v1alpha2.ApplicationConfiguration{}),
+			want:   defaultMaxConcurrentApply,
+		},
+		"Limit": {
+			reason: "The limit of the ApplicationConfiguration should be used",
+			ctx: withMaxConcurrentApply(context.Background(), &v1alpha2.ApplicationConfiguration{
+				Spec: v1alpha2.ApplicationConfigurationSpec{MaxConcurrentApply: &two},
+			}),
+			want: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, maxConcurrentApply(tc.ctx)); diff != "" {
+				t.Errorf("\n%s\nmaxConcurrentApply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
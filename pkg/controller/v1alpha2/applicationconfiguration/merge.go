@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Merge error strings.
+const (
+	errGetObject     = "cannot get object"
+	errCreateObject  = "cannot create object"
+	errPatchObject   = "cannot patch object"
+	errMarshalObject = "cannot marshal object"
+	errCreatePatch   = "cannot create three-way merge patch"
+)
+
+// A mergingApplicator applies objects using a three-way merge between the
+// object as it was last applied, as it should be, and as it currently is. The
+// last applied object is recorded in an annotation, like kubectl apply does.
+// Objects whose kind is known to the scheme are merged using a strategic
+// merge; all other objects, e.g. custom resources, use a JSON merge.
+type mergingApplicator struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Apply the supplied object, which must be unstructured.
+func (a *mergingApplicator) Apply(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+	desired, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New(errNotUnstructured)
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := a.client.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, current)
+	if kerrors.IsNotFound(err) {
+		if err := setLastApplied(desired); err != nil {
+			return err
+		}
+		return errors.Wrap(a.client.Create(ctx, desired), errCreateObject)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetObject)
+	}
+
+	for _, fn := range ao {
+		if err := fn(ctx, current, desired); err != nil {
+			return err
+		}
+	}
+
+	original := []byte(current.GetAnnotations()[oam.AnnotationLastAppliedConfig])
+	modified := desired.DeepCopy()
+	if err := setLastApplied(modified); err != nil {
+		return err
+	}
+	m, err := json.Marshal(modified)
+	if err != nil {
+		return errors.Wrap(err, errMarshalObject)
+	}
+	c, err := json.Marshal(current)
+	if err != nil {
+		return errors.Wrap(err, errMarshalObject)
+	}
+
+	patchType, patch, err := a.patch(desired, original, m, c)
+	if err != nil {
+		return errors.Wrap(err, errCreatePatch)
+	}
+	if err := a.client.Patch(ctx, current, client.RawPatch(patchType, patch)); err != nil {
+		return errors.Wrap(err, errPatchObject)
+	}
+	desired.Object = current.Object
+	return nil
+}
+
+// patch returns a three-way merge patch, and its type, that applies the
+// modified object to the current object.
+func (a *mergingApplicator) patch(o runtime.Object, original, modified, current []byte) (types.PatchType, []byte, error) {
+	if a.scheme != nil {
+		if typed, err := a.scheme.New(o.GetObjectKind().GroupVersionKind()); err == nil {
+			meta, err := strategicpatch.NewPatchMetaFromStruct(typed)
+			if err != nil {
+				return "", nil, err
+			}
+			p, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, meta, true)
+			return types.StrategicMergePatchType, p, err
+		}
+	}
+	p, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current)
+	return types.MergePatchType, p, err
+}
+
+// setLastApplied records the supplied object, without its last applied
+// annotation, in its last applied annotation.
+func setLastApplied(u *unstructured.Unstructured) error {
+	a := u.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	delete(a, oam.AnnotationLastAppliedConfig)
+	last := u.DeepCopy()
+	if len(a) == 0 {
+		last.SetAnnotations(nil)
+	} else {
+		last.SetAnnotations(a)
+	}
+	b, err := json.Marshal(last)
+	if err != nil {
+		return errors.Wrap(err, errMarshalObject)
+	}
+	a[oam.AnnotationLastAppliedConfig] = string(b)
+	u.SetAnnotations(a)
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestMergingApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	deployment := func(containers ...interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"containers": containers}}},
+		}}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetNamespace("ns")
+		u.SetName("cool")
+		return u
+	}
+	container := func(name, image string) interface{} {
+		return map[string]interface{}{"name": name, "image": image}
+	}
+	policy := func(spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind("Policy")
+		u.SetNamespace("ns")
+		u.SetName("cool")
+		return u
+	}
+	lastApplied := func(u *unstructured.Unstructured) *unstructured.Unstructured {
+		_ = setLastApplied(u)
+		return u
+	}
+
+	s := runtime.NewScheme()
+	_ = appsv1.AddToScheme(s)
+
+	type patched struct {
+		patchType types.PatchType
+		data      map[string]interface{}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		current *unstructured.Unstructured
+		getErr  error
+		desired *unstructured.Unstructured
+		ao      []resource.ApplyOption
+
+		// check the patch that was sent.
+		check func(t *testing.T, current *unstructured.Unstructured, p patched)
+		want  error
+	}{
+		"GetError": {
+			reason:  "Errors getting the current object should be returned",
+			getErr:  errBoom,
+			desired: policy(nil),
+			want:    errors.Wrap(errBoom, errGetObject),
+		},
+		"ApplyOptionError": {
+			reason:  "Errors returned by an apply option should be returned",
+			current: policy(nil),
+			desired: policy(nil),
+			ao:      []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error { return errBoom }},
+			want:    errBoom,
+		},
+		"StrategicMerge": {
+			reason:  "Objects whose kind has strategic merge metadata should be merged strategically, preserving list entries added by others",
+			current: lastApplied(deployment(container("app", "v1"))),
+			desired: deployment(container("app", "v2")),
+			check: func(t *testing.T, current *unstructured.Unstructured, p patched) {
+				if p.patchType != types.StrategicMergePatchType {
+					t.Errorf("Patch type: want %s, got %s", types.StrategicMergePatchType, p.patchType)
+				}
+				// Someone else added a sidecar since we last applied.
+				_ = unstructured.SetNestedSlice(current.Object, []interface{}{container("app", "v1"), container("sidecar", "v1")}, "spec", "template", "spec", "containers")
+				c, _ := json.Marshal(current)
+				b, _ := json.Marshal(p.data)
+				merged, err := strategicpatch.StrategicMergePatch(c, b, appsv1.Deployment{})
+				if err != nil {
+					t.Fatalf("StrategicMergePatch(...): %v", err)
+				}
+				got := &unstructured.Unstructured{}
+				_ = got.UnmarshalJSON(merged)
+				containers, _, _ := unstructured.NestedSlice(got.Object, "spec", "template", "spec", "containers")
+				if diff := cmp.Diff([]interface{}{container("app", "v2"), container("sidecar", "v1")}, containers); diff != "" {
+					t.Errorf("Merged containers: -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"JSONMerge": {
+			reason:  "Objects whose kind has no strategic merge metadata should be merged using a JSON merge, preserving fields set by others",
+			current: lastApplied(policy(map[string]interface{}{"rules": []interface{}{"a"}})),
+			desired: policy(map[string]interface{}{"rules": []interface{}{"a", "b"}}),
+			check: func(t *testing.T, _ *unstructured.Unstructured, p patched) {
+				if p.patchType != types.MergePatchType {
+					t.Errorf("Patch type: want %s, got %s", types.MergePatchType, p.patchType)
+				}
+				if diff := cmp.Diff(map[string]interface{}{"rules": []interface{}{"a", "b"}}, p.data["spec"]); diff != "" {
+					t.Errorf("Patched spec: -want, +got:\n%s", diff)
+				}
+				if _, ok := p.data["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})[oam.AnnotationLastAppliedConfig]; !ok {
+					t.Errorf("Patch should update the last applied annotation")
+				}
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var p *patched
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					if tc.getErr != nil {
+						return tc.getErr
+					}
+					tc.current.DeepCopyInto(obj.(*unstructured.Unstructured))
+					return nil
+				},
+				MockPatch: func(_ context.Context, obj runtime.Object, patch client.Patch, _ ...client.PatchOption) error {
+					b, _ := patch.Data(obj)
+					p = &patched{patchType: patch.Type()}
+					_ = json.Unmarshal(b, &p.data)
+					return nil
+				},
+			}
+			a := &mergingApplicator{client: c, scheme: s}
+			err := a.Apply(context.Background(), tc.desired, tc.ao...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\na.Apply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.check == nil {
+				return
+			}
+			if p == nil {
+				t.Fatalf("\n%s\na.Apply(...): want a patch", tc.reason)
+			}
+			tc.check(t, tc.current.DeepCopy(), *p)
+		})
+	}
+}
+
+func TestMergingApplicatorCreate(t *testing.T) {
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"rules": []interface{}{"a"}}}}
+	desired.SetAPIVersion("example.com/v1")
+	desired.SetKind("Policy")
+	desired.SetName("cool")
+
+	var created *unstructured.Unstructured
+	a := &mergingApplicator{client: &test.MockClient{
+		MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool")),
+		MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+			created = obj.(*unstructured.Unstructured).DeepCopy()
+			return nil
+		},
+	}}
+	if err := a.Apply(context.Background(), desired.DeepCopy()); err != nil {
+		t.Fatalf("a.Apply(...): %v", err)
+	}
+
+	last := &unstructured.Unstructured{}
+	if err := last.UnmarshalJSON([]byte(created.GetAnnotations()[oam.AnnotationLastAppliedConfig])); err != nil {
+		t.Fatalf("cannot unmarshal last applied annotation: %v", err)
+	}
+	if diff := cmp.Diff(desired, last); diff != "" {
+		t.Errorf("a.Apply(...): -want last applied, +got last applied:\n%s", diff)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Results of an ApplicationConfiguration status update.
+const (
+	statusUpdateNoop    = "noop"
+	statusUpdatePatched = "patched"
+)
+
+// statusUpdates counts ApplicationConfiguration status updates by whether the
+// status changed and was patched, or was unchanged and skipped.
+var statusUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "oam",
+	Subsystem: "applicationconfiguration",
+	Name:      "status_updates_total",
+	Help:      "Number of ApplicationConfiguration status updates, by whether the status was patched or unchanged.",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(statusUpdates)
+}
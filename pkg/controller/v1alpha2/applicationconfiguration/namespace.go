@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Namespace error strings.
+const (
+	errNamespaceSelector  = "cannot convert namespace selector"
+	errListNamespaces     = "cannot list namespaces selected by namespace selector"
+	errFmtApplyNamespace  = "cannot apply components to namespace %q"
+	errFmtGCNamespace     = "cannot garbage collect components in namespace %q"
+	errFmtApplyNamespaces = "cannot apply components to namespaces %q"
+	errFmtDeleteResource  = "cannot delete %s %q"
+	errFmtListCopies      = "cannot list copies of %s workloads and traits"
+)
+
+// applyToSelectedNamespaces applies the supplied workloads to each namespace
+// selected by the ApplicationConfiguration's namespace selector, other than
+// the ApplicationConfiguration's own namespace, and records the result in its
+// NamespaceStatuses. Workloads are garbage collected from namespaces that are
+// no longer selected.
+func (r *Reconciler) applyToSelectedNamespaces(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload) error {
+	selected, err := r.selectedNamespaces(ctx, ac)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]v1alpha2.NamespaceStatus, len(selected))
+	for ns, st := range ac.Status.NamespaceStatuses {
+		if selected[ns] {
+			continue
+		}
+		if err := r.garbageCollect(ctx, ns, st.Workloads, nil); err != nil {
+			// Keep the status so that we try again next time.
+			st.SetConditions(v1alpha1.ReconcileError(errors.Wrapf(err, errFmtGCNamespace, ns)))
+			statuses[ns] = st
+		}
+	}
+
+	names := make([]string, 0, len(selected))
+	for ns := range selected {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+
+	failed := make([]string, 0)
+	for _, ns := range names {
+		st := ac.Status.NamespaceStatuses[ns]
+		nw := inNamespace(w, ns, ac.GetUID())
+		if err := r.applyToNamespace(ctx, ac, ns, st.Workloads, nw); err != nil {
+			st.SetConditions(v1alpha1.ReconcileError(err))
+			statuses[ns] = st
+			failed = append(failed, ns)
+			continue
+		}
+		st.Workloads = make([]v1alpha2.WorkloadStatus, len(nw))
+		for i := range nw {
+			st.Workloads[i] = nw[i].Status()
+		}
+		recordLastAppliedSpecs(st.Workloads, nw, lastAppliedSpecLimit(ac))
+		st.SetConditions(v1alpha1.ReconcileSuccess())
+		statuses[ns] = st
+	}
+
+	ac.Status.NamespaceStatuses = nil
+	if len(statuses) > 0 {
+		ac.Status.NamespaceStatuses = statuses
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf(errFmtApplyNamespaces, failed)
+	}
+	return nil
+}
+
+func (r *Reconciler) selectedNamespaces(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	// Namespaces are not selected in target clusters.
+	if ac.Spec.NamespaceSelector == nil || ac.Spec.TargetCluster != nil {
+		return selected, nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(ac.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, errNamespaceSelector)
+	}
+
+	l := &corev1.NamespaceList{}
+	if err := r.client.List(ctx, l, client.MatchingLabelsSelector{Selector: s}); err != nil {
+		return nil, errors.Wrap(err, errListNamespaces)
+	}
+	for _, ns := range l.Items {
+		if ns.GetName() == ac.GetNamespace() {
+			// Workloads are always applied to this namespace.
+			continue
+		}
+		selected[ns.GetName()] = true
+	}
+	return selected, nil
+}
+
+func (r *Reconciler) applyToNamespace(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, ns string, status []v1alpha2.WorkloadStatus, w []Workload) error {
+	if len(w) > 0 {
+		if err := r.workloads.Apply(ctx, status, w, resource.MustBeControllableBy(ac.GetUID())); err != nil {
+			return errors.Wrapf(err, errFmtApplyNamespace, ns)
+		}
+	}
+	return errors.Wrapf(r.garbageCollect(ctx, ns, status, w), errFmtGCNamespace, ns)
+}
+
+func (r *Reconciler) garbageCollect(ctx context.Context, ns string, status []v1alpha2.WorkloadStatus, w []Workload) error {
+	for _, e := range r.traitAppliers.DeletionOrder(r.gc.Eligible(ns, status, w)) {
+		// https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		e := e
+		if err := r.traitAppliers.Delete(ctx, r.client, &e); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtDeleteResource, e.GetKind(), e.GetName())
+		}
+	}
+	return nil
+}
+
+// inNamespace returns copies of the supplied workloads and their traits in
+// the supplied namespace. Owner references are removed because an object may
+// not be owned by an object in another namespace; copies are instead labelled
+// with the supplied ApplicationConfiguration UID. Scopes are removed because
+// they are resolved in the namespace of the ApplicationConfiguration.
+func inNamespace(w []Workload, ns string, uid types.UID) []Workload {
+	out := make([]Workload, len(w))
+	for i := range w {
+		out[i] = w[i].DeepCopy()
+		out[i].Scopes = nil
+		asCopy(out[i].Workload, ns, uid)
+		for j := range out[i].Traits {
+			t := &out[i].Traits[j]
+			asCopy(t, ns, uid)
+			// A trait that was already applied has the UID of the trait in
+			// the namespace it was applied to.
+			unstructured.RemoveNestedField(t.Object, "metadata", "uid")
+		}
+		if out[i].PodDisruptionBudget != nil {
+			out[i].PodDisruptionBudget.SetNamespace(ns)
+		}
+		if ts := out[i].TrafficSplit; ts != nil {
+			ts.Canary.SetNamespace(ns)
+			for j := range ts.Services {
+				ts.Services[j].SetNamespace(ns)
+			}
+		}
+	}
+	return out
+}
+
+func asCopy(u *unstructured.Unstructured, ns string, uid types.UID) {
+	u.SetNamespace(ns)
+	u.SetOwnerReferences(nil)
+	meta.AddLabels(u, map[string]string{oam.LabelCopyOf: string(uid)})
+}
+
+// hasNamespaceCopies returns true if the supplied ApplicationConfiguration
+// may have applied copies of its workloads to other namespaces.
+func hasNamespaceCopies(ac *v1alpha2.ApplicationConfiguration) bool {
+	return ac.Spec.NamespaceSelector != nil || len(ac.Status.NamespaceStatuses) > 0
+}
+
+// deleteNamespaceCopies deletes the copies of the workloads and traits of the
+// supplied ApplicationConfiguration from the namespaces selected by its
+// namespace selector. Copies are not owned by the ApplicationConfiguration,
+// so they are not garbage collected when it is deleted. Copies that were
+// applied but never recorded in its status, for example because the status
+// could not be updated, are found by their label.
+func (r *Reconciler) deleteNamespaceCopies(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
+	if !hasNamespaceCopies(ac) {
+		return nil
+	}
+
+	kinds := make(map[schema.GroupVersionKind]bool)
+	addKinds := func(ws []v1alpha2.WorkloadStatus) {
+		for _, s := range ws {
+			kinds[schema.FromAPIVersionAndKind(s.Reference.APIVersion, s.Reference.Kind)] = true
+			for _, t := range s.Traits {
+				kinds[schema.FromAPIVersionAndKind(t.Reference.APIVersion, t.Reference.Kind)] = true
+			}
+		}
+	}
+	addKinds(ac.Status.Workloads)
+	for ns, st := range ac.Status.NamespaceStatuses {
+		if err := r.garbageCollect(ctx, ns, st.Workloads, nil); err != nil {
+			return errors.Wrapf(err, errFmtGCNamespace, ns)
+		}
+		addKinds(st.Workloads)
+	}
+
+	for gvk := range kinds {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.client.List(ctx, l, client.MatchingLabels{oam.LabelCopyOf: string(ac.GetUID())}); err != nil {
+			return errors.Wrapf(err, errFmtListCopies, gvk.Kind)
+		}
+		for i := range l.Items {
+			u := &l.Items[i]
+			if err := r.client.Delete(ctx, u); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errFmtDeleteResource, u.GetKind(), u.GetName())
+			}
+		}
+	}
+	return nil
+}
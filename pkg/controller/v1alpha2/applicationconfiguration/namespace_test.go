@@ -0,0 +1,318 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestApplyToSelectedNamespaces(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	namespace := "ns"
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "production"}}
+
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("v")
+	workload.SetKind("workload")
+	workload.SetNamespace(namespace)
+	workload.SetName("workload")
+	workload.SetOwnerReferences([]metav1.OwnerReference{{Name: "coolappconfig"}})
+
+	w := []Workload{{ComponentName: "coolcomponent", Workload: workload}}
+
+	inProd := inNamespace(w, "prod", "")
+	prodStatus := inProd[0].Status()
+	appliedStatus := []v1alpha2.WorkloadStatus{prodStatus}
+	recordLastAppliedSpecs(appliedStatus, inProd, defaultLastAppliedSpecLimit)
+
+	list := func(names ...string) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*corev1.NamespaceList)
+			for _, n := range names {
+				l.Items = append(l.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: n}})
+			}
+			return nil
+		}
+	}
+
+	type fields struct {
+		client    client.Client
+		workloads WorkloadApplicator
+	}
+	type want struct {
+		statuses map[string]v1alpha2.NamespaceStatus
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"NoSelector": {
+			reason: "Nothing should be applied when no namespace selector is set",
+			fields: fields{client: &test.MockClient{}},
+			ac:     &v1alpha2.ApplicationConfiguration{},
+			want:   want{},
+		},
+		"ListNamespacesError": {
+			reason: "Errors listing the selected namespaces should be returned",
+			fields: fields{client: &test.MockClient{MockList: test.NewMockListFn(errBoom)}},
+			ac: &v1alpha2.ApplicationConfiguration{
+				Spec: v1alpha2.ApplicationConfigurationSpec{NamespaceSelector: selector},
+			},
+			want: want{err: errors.Wrap(errBoom, errListNamespaces)},
+		},
+		"ApplyToSelectedNamespaces": {
+			reason: "Workloads should be applied to selected namespaces other than the ApplicationConfiguration's own",
+			fields: fields{
+				client: &test.MockClient{MockList: list(namespace, "prod")},
+				workloads: WorkloadApplyFn(func(_ context.Context, _ []v1alpha2.WorkloadStatus, got []Workload, _ ...resource.ApplyOption) error {
+					if diff := cmp.Diff(inProd, got); diff != "" {
+						t.Errorf("\nApply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+			ac: &v1alpha2.ApplicationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+				Spec:       v1alpha2.ApplicationConfigurationSpec{NamespaceSelector: selector},
+			},
+			want: want{statuses: map[string]v1alpha2.NamespaceStatus{
+				"prod": func() v1alpha2.NamespaceStatus {
+					st := v1alpha2.NamespaceStatus{Workloads: appliedStatus}
+					st.SetConditions(runtimev1alpha1.ReconcileSuccess())
+					return st
+				}(),
+			}},
+		},
+		"ApplyError": {
+			reason: "Errors applying workloads to a selected namespace should be reflected in its status",
+			fields: fields{
+				client: &test.MockClient{MockList: list("prod")},
+				workloads: WorkloadApplyFn(func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					return errBoom
+				}),
+			},
+			ac: &v1alpha2.ApplicationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+				Spec:       v1alpha2.ApplicationConfigurationSpec{NamespaceSelector: selector},
+			},
+			want: want{
+				statuses: map[string]v1alpha2.NamespaceStatus{
+					"prod": func() v1alpha2.NamespaceStatus {
+						st := v1alpha2.NamespaceStatus{}
+						st.SetConditions(runtimev1alpha1.ReconcileError(errors.Wrapf(errBoom, errFmtApplyNamespace, "prod")))
+						return st
+					}(),
+				},
+				err: errors.Errorf(errFmtApplyNamespaces, []string{"prod"}),
+			},
+		},
+		"GarbageCollectUnselectedNamespaces": {
+			reason: "Workloads should be garbage collected from namespaces that are no longer selected",
+			fields: fields{
+				client: &test.MockClient{
+					MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+						u := obj.(*unstructured.Unstructured)
+						if u.GetNamespace() != "prod" || u.GetName() != "workload" {
+							t.Errorf("Delete(...): unexpected object %s/%s", u.GetNamespace(), u.GetName())
+						}
+						return nil
+					},
+				},
+			},
+			ac: &v1alpha2.ApplicationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+				Status: v1alpha2.ApplicationConfigurationStatus{
+					NamespaceStatuses: map[string]v1alpha2.NamespaceStatus{
+						"prod": {Workloads: []v1alpha2.WorkloadStatus{prodStatus}},
+					},
+				},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{client: tc.fields.client, workloads: tc.fields.workloads, gc: GarbageCollectorFn(eligible)}
+			err := r.applyToSelectedNamespaces(context.Background(), tc.ac, w)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.applyToSelectedNamespaces(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.statuses, tc.ac.Status.NamespaceStatuses, cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nr.applyToSelectedNamespaces(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInNamespace(t *testing.T) {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("apps/v1")
+	workload.SetKind("Deployment")
+	workload.SetNamespace("ns")
+	workload.SetName("web")
+	workload.SetOwnerReferences([]metav1.OwnerReference{{Name: "coolappconfig"}})
+
+	trait := unstructured.Unstructured{}
+	trait.SetNamespace("ns")
+	trait.SetName("trait")
+	trait.SetOwnerReferences([]metav1.OwnerReference{{Name: "coolappconfig"}})
+
+	scope := unstructured.Unstructured{}
+	scope.SetNamespace("ns")
+
+	w := []Workload{{
+		ComponentName: "web",
+		Workload:      workload,
+		Traits:        []unstructured.Unstructured{trait},
+		Scopes:        []unstructured.Unstructured{scope},
+	}}
+
+	got := inNamespace(w, "prod", "app-uid")
+
+	wantWorkload := workload.DeepCopy()
+	wantWorkload.SetNamespace("prod")
+	wantWorkload.SetOwnerReferences(nil)
+	wantWorkload.SetLabels(map[string]string{oam.LabelCopyOf: "app-uid"})
+	wantTrait := trait.DeepCopy()
+	wantTrait.SetNamespace("prod")
+	wantTrait.SetOwnerReferences(nil)
+	wantTrait.SetLabels(map[string]string{oam.LabelCopyOf: "app-uid"})
+	want := []Workload{{
+		ComponentName: "web",
+		Workload:      wantWorkload,
+		Traits:        []unstructured.Unstructured{*wantTrait},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("inNamespace(...): -want, +got:\n%s", diff)
+	}
+	if w[0].Workload.GetNamespace() != "ns" || w[0].Traits[0].GetNamespace() != "ns" {
+		t.Errorf("inNamespace(...): supplied workloads were modified")
+	}
+}
+
+func TestDeleteNamespaceCopies(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ref := runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", UID: "app-uid"}}
+	ac.Status.NamespaceStatuses = map[string]v1alpha2.NamespaceStatus{
+		"prod": {Workloads: []v1alpha2.WorkloadStatus{{Reference: ref}}},
+	}
+
+	recorded := unstructured.Unstructured{}
+	recorded.SetNamespace("prod")
+	recorded.SetName("web")
+
+	type want struct {
+		deleted []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		delete error
+		list   test.MockListFn
+		want   want
+	}{
+		"NoCopies": {
+			reason: "Nothing should be deleted from an ApplicationConfiguration without a namespace selector",
+			ac:     &v1alpha2.ApplicationConfiguration{},
+			want:   want{},
+		},
+		"GarbageCollectError": {
+			reason: "Errors deleting recorded copies should be returned",
+			ac:     ac,
+			delete: errBoom,
+			want: want{
+				deleted: []string{"prod/web"},
+				err:     errors.Wrapf(errors.Wrapf(errBoom, errFmtDeleteResource, "", "web"), errFmtGCNamespace, "prod"),
+			},
+		},
+		"ListError": {
+			reason: "Errors listing labelled copies should be returned",
+			ac:     ac,
+			list:   test.NewMockListFn(errBoom),
+			want:   want{deleted: []string{"prod/web"}, err: errors.Wrapf(errBoom, errFmtListCopies, "Deployment")},
+		},
+		"Deleted": {
+			reason: "Recorded copies should be deleted, and labelled copies that were not recorded deleted",
+			ac:     ac,
+			list: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.LabelSelector.String() != oam.LabelCopyOf+"=app-uid" {
+					return errors.Errorf("listed copies with selector %q", lo.LabelSelector)
+				}
+				l := obj.(*unstructured.UnstructuredList)
+				u := unstructured.Unstructured{}
+				u.SetNamespace("staging")
+				u.SetName("web")
+				l.Items = append(l.Items, u)
+				return nil
+			},
+			want: want{deleted: []string{"prod/web", "staging/web"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			r := &Reconciler{
+				client: &test.MockClient{
+					MockList: tc.list,
+					MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+						u := obj.(*unstructured.Unstructured)
+						got.deleted = append(got.deleted, u.GetNamespace()+"/"+u.GetName())
+						return tc.delete
+					},
+				},
+				gc: GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
+					return []unstructured.Unstructured{recorded}
+				}),
+			}
+			got.err = r.deleteNamespaceCopies(context.Background(), tc.ac)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.deleteNamespaceCopies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
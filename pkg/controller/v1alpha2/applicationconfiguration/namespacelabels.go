@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Namespace management error strings.
+const (
+	errNamespaceCreationDisallowed = "namespace labels are ignored because namespace creation is not allowed"
+
+	errFmtGetNamespace    = "cannot get namespace %q"
+	errFmtCreateNamespace = "cannot create namespace %q"
+	errFmtLabelNamespace  = "cannot label namespace %q"
+)
+
+// ensureNamespace creates the named namespace with the supplied labels if it
+// does not exist, or adds the supplied labels to it if it does. Labels that
+// are not supplied are left untouched.
+func ensureNamespace(ctx context.Context, c client.Client, name string, labels map[string]string) error {
+	ns := &corev1.Namespace{}
+	err := c.Get(ctx, types.NamespacedName{Name: name}, ns)
+	if kerrors.IsNotFound(err) {
+		ns.SetName(name)
+		ns.SetLabels(labels)
+		return errors.Wrapf(c.Create(ctx, ns), errFmtCreateNamespace, name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetNamespace, name)
+	}
+
+	existing := ns.GetLabels()
+	patch := client.MergeFrom(ns.DeepCopy())
+	changed := false
+	for k, v := range labels {
+		if cur, ok := existing[k]; ok && cur == v {
+			continue
+		}
+		if existing == nil {
+			existing = make(map[string]string, len(labels))
+		}
+		existing[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	ns.SetLabels(existing)
+	return errors.Wrapf(c.Patch(ctx, ns, patch), errFmtLabelNamespace, name)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestEnsureNamespace(t *testing.T) {
+	errBoom := errors.New("boom")
+	labels := map[string]string{"env": "dev"}
+	existing := func(l map[string]string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.Namespace).SetLabels(l)
+			return nil
+		})
+	}
+
+	type want struct {
+		ops    []string
+		labels map[string]string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		err    error
+		want   want
+	}{
+		"Create": {
+			reason: "A namespace that does not exist should be created with the supplied labels",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "ns")),
+			want:   want{ops: []string{"create"}, labels: labels},
+		},
+		"CreateError": {
+			reason: "Errors creating the namespace should be returned",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "ns")),
+			err:    errBoom,
+			want:   want{ops: []string{"create"}, labels: labels, err: errors.Wrapf(errBoom, errFmtCreateNamespace, "ns")},
+		},
+		"GetError": {
+			reason: "Errors getting the namespace should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetNamespace, "ns")},
+		},
+		"Label": {
+			reason: "The supplied labels should be added to an existing namespace, retaining its other labels",
+			get:    existing(map[string]string{"team": "a", "env": "prod"}),
+			want:   want{ops: []string{"patch"}, labels: map[string]string{"team": "a", "env": "dev"}},
+		},
+		"AlreadyLabelled": {
+			reason: "A namespace that already has the supplied labels should not be patched",
+			get:    existing(map[string]string{"team": "a", "env": "dev"}),
+			want:   want{},
+		},
+		"PatchError": {
+			reason: "Errors labelling the namespace should be returned",
+			get:    existing(nil),
+			err:    errBoom,
+			want:   want{ops: []string{"patch"}, labels: labels, err: errors.Wrapf(errBoom, errFmtLabelNamespace, "ns")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					got.ops = append(got.ops, "create")
+					got.labels = obj.(*corev1.Namespace).GetLabels()
+					return tc.err
+				},
+				MockPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					got.ops = append(got.ops, "patch")
+					got.labels = obj.(*corev1.Namespace).GetLabels()
+					return tc.err
+				},
+			}
+			got.err = ensureNamespace(context.Background(), c, "ns", labels)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nensureNamespace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Workload name template error strings.
+const (
+	errParseNameTemplate   = "cannot parse workload name template"
+	errExecNameTemplate    = "cannot execute workload name template"
+	errFmtInvalidNameValue = "rendered workload name %q is invalid: %s"
+)
+
+// workloadNameValues may be referenced by a workload name template.
+type workloadNameValues struct {
+	AppConfigName string
+	ComponentName string
+	Namespace     string
+	Revision      string
+}
+
+// renderWorkloadName renders the supplied workload name template using the
+// supplied values. It returns an error if the rendered name is not a valid
+// object name.
+func renderWorkloadName(tmpl string, v workloadNameValues) (string, error) {
+	t, err := template.New("workloadName").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, errParseNameTemplate)
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, v); err != nil {
+		return "", errors.Wrap(err, errExecNameTemplate)
+	}
+	name := b.String()
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidNameValue, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// nameTemplateErrors returns the errors rendering the workload name templates
+// of the supplied workloads.
+func nameTemplateErrors(w []Workload) []string {
+	msgs := make([]string, 0)
+	for _, wl := range w {
+		if wl.NameTemplateError != "" {
+			msgs = append(msgs, wl.NameTemplateError)
+		}
+	}
+	return msgs
+}
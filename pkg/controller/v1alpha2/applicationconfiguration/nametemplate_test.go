@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderWorkloadName(t *testing.T) {
+	v := workloadNameValues{AppConfigName: "shop", ComponentName: "web", Namespace: "prod", Revision: "web-v2"}
+
+	type want struct {
+		name string
+		err  bool
+	}
+	cases := map[string]struct {
+		reason string
+		tmpl   string
+		want   want
+	}{
+		"Rendered": {
+			reason: "The template should be rendered using the supplied values",
+			tmpl:   "{{.AppConfigName}}-{{.ComponentName}}-{{.Revision}}",
+			want:   want{name: "shop-web-web-v2"},
+		},
+		"ParseError": {
+			reason: "Templates that cannot be parsed should return an error",
+			tmpl:   "{{.AppConfigName",
+			want:   want{err: true},
+		},
+		"UnknownValue": {
+			reason: "Templates that reference unknown values should return an error",
+			tmpl:   "{{.Cluster}}",
+			want:   want{err: true},
+		},
+		"InvalidName": {
+			reason: "Templates that render invalid object names should return an error",
+			tmpl:   "{{.Namespace}}_{{.ComponentName}}",
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderWorkloadName(tc.tmpl, v)
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nrenderWorkloadName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nrenderWorkloadName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Node selector error strings.
+const (
+	errParseNodeSelector = "cannot parse pod template node selector"
+)
+
+// podNodeSelectorPath is the field path of the node selector of workloads
+// that embed a pod template.
+const podNodeSelectorPath = "spec.template.spec.nodeSelector"
+
+// injectNodeSelector merges the supplied node selector into the pod template
+// of the supplied workload. Labels already selected by the pod template take
+// precedence over those supplied. It returns false if there is a node selector
+// to inject but the workload has no pod template.
+func injectNodeSelector(w *unstructured.Unstructured, sel map[string]string) (bool, error) {
+	if len(sel) == 0 {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+
+	merged := make(map[string]interface{}, len(sel))
+	for k, v := range sel {
+		merged[k] = v
+	}
+	if v, err := p.GetValue(podNodeSelectorPath); err == nil {
+		existing, ok := v.(map[string]interface{})
+		if !ok {
+			return false, errors.New(errParseNodeSelector)
+		}
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	return true, p.SetValue(podNodeSelectorPath, merged)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInjectNodeSelector(t *testing.T) {
+	workload := func(sel map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "c0"}}}
+		if sel != nil {
+			spec["nodeSelector"] = sel
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		sel    map[string]string
+		want   want
+	}{
+		"NoNodeSelector": {
+			reason: "A workload should be unchanged when no node selector is supplied",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: true},
+		},
+		"Injected": {
+			reason: "A node selector should be injected into a pod template that has none",
+			w:      workload(nil),
+			sel:    map[string]string{"disktype": "ssd"},
+			want:   want{w: workload(map[string]interface{}{"disktype": "ssd"}), injected: true},
+		},
+		"Merged": {
+			reason: "A node selector should be merged with that of the pod template, whose labels take precedence",
+			w:      workload(map[string]interface{}{"zone": "us-east-1a"}),
+			sel:    map[string]string{"zone": "us-west-2a", "disktype": "ssd"},
+			want:   want{w: workload(map[string]interface{}{"zone": "us-east-1a", "disktype": "ssd"}), injected: true},
+		},
+		"NoPodTemplate": {
+			reason: "A workload without a pod template should be unchanged, and reported as such",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			sel:    map[string]string{"disktype": "ssd"},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectNodeSelector(tc.w, tc.sel)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectNodeSelector(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectNodeSelector(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
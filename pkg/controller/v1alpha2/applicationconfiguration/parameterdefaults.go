@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Parameter defaults error strings.
+const (
+	errListParameterDefaults       = "cannot list NamespaceParameterDefaults"
+	errListAppConfigsParamDefaults = "cannot list ApplicationConfigurations that may use NamespaceParameterDefaults"
+	errFmtParameterDefaults        = "cannot get default parameter values of component %q"
+)
+
+// parameterDefaults are default parameter values, keyed by workload kind and
+// then by parameter name.
+type parameterDefaults map[string]map[string]intstr.IntOrString
+
+// A NamespaceParameterDefaultsCache caches the merged NamespaceParameterDefaults
+// of each namespace. A NamespaceParameterDefaultsCache is also an event
+// handler; the defaults of a namespace are evicted from the cache when any of
+// its NamespaceParameterDefaults change, and the ApplicationConfigurations in
+// the namespace are enqueued to be rendered with the new defaults.
+type NamespaceParameterDefaultsCache struct {
+	client client.Reader
+	log    logging.Logger
+
+	mu       sync.RWMutex
+	defaults map[string]parameterDefaults
+}
+
+var _ handler.EventHandler = &NamespaceParameterDefaultsCache{}
+
+// NewNamespaceParameterDefaultsCache returns a NamespaceParameterDefaultsCache
+// that reads NamespaceParameterDefaults using the supplied client.
+func NewNamespaceParameterDefaultsCache(c client.Reader, l logging.Logger) *NamespaceParameterDefaultsCache {
+	return &NamespaceParameterDefaultsCache{client: c, log: l, defaults: make(map[string]parameterDefaults)}
+}
+
+// Get the default parameter values of the supplied workload kind in the
+// supplied namespace. The returned map must not be modified.
+func (c *NamespaceParameterDefaultsCache) Get(ctx context.Context, namespace, kind string) (map[string]intstr.IntOrString, error) {
+	c.mu.RLock()
+	d, ok := c.defaults[namespace]
+	c.mu.RUnlock()
+	if ok {
+		return d[kind], nil
+	}
+
+	l := &v1alpha2.NamespaceParameterDefaultsList{}
+	if err := c.client.List(ctx, l, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, errListParameterDefaults)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].GetName() < l.Items[j].GetName() })
+
+	d = make(parameterDefaults)
+	for _, npd := range l.Items {
+		for k, params := range npd.Spec.Defaults {
+			if d[k] == nil {
+				d[k] = make(map[string]intstr.IntOrString, len(params))
+			}
+			for name, v := range params {
+				d[k][name] = v
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.defaults[namespace] = d
+	c.mu.Unlock()
+	return d[kind], nil
+}
+
+// evict the cached defaults of the supplied namespace, and enqueue the
+// ApplicationConfigurations in it.
+func (c *NamespaceParameterDefaultsCache) evict(o metav1.Object, q workqueue.RateLimitingInterface) {
+	c.mu.Lock()
+	delete(c.defaults, o.GetNamespace())
+	c.mu.Unlock()
+
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := c.client.List(context.Background(), acs, client.InNamespace(o.GetNamespace())); err != nil {
+		c.log.Debug(errListAppConfigsParamDefaults, "error", err, "namespace", o.GetNamespace())
+		return
+	}
+	for _, ac := range acs.Items {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}})
+	}
+}
+
+// Create implements EventHandler
+func (c *NamespaceParameterDefaultsCache) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta, q)
+}
+
+// Update implements EventHandler
+func (c *NamespaceParameterDefaultsCache) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	c.evict(evt.MetaNew, q)
+}
+
+// Delete implements EventHandler
+func (c *NamespaceParameterDefaultsCache) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta, q)
+}
+
+// Generic implements EventHandler
+func (c *NamespaceParameterDefaultsCache) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta, q)
+}
+
+// rawWorkloadKind returns the kind of the supplied raw workload.
+func rawWorkloadKind(raw []byte) (string, error) {
+	tm := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &tm); err != nil {
+		return "", errors.Wrap(err, errUnmarshalWorkload)
+	}
+	return tm.Kind, nil
+}
+
+// withParameterDefaults returns the supplied parameter values, followed by the
+// supplied defaults of any parameters the component accepts that they do not
+// set. Values set by the ApplicationConfiguration win over defaults.
+func withParameterDefaults(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameterValue, defaults map[string]intstr.IntOrString) []v1alpha2.ComponentParameterValue {
+	if len(defaults) == 0 {
+		return cpv
+	}
+	set := make(map[string]bool, len(cpv))
+	for _, v := range cpv {
+		set[v.Name] = true
+	}
+	out := append([]v1alpha2.ComponentParameterValue{}, cpv...)
+	for _, p := range cp {
+		v, ok := defaults[p.Name]
+		if !ok || set[p.Name] {
+			continue
+		}
+		out = append(out, v1alpha2.ComponentParameterValue{Name: p.Name, Value: v})
+	}
+	return out
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestNamespaceParameterDefaultsCache(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	npds := []v1alpha2.NamespaceParameterDefaults{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "b-team"},
+			Spec: v1alpha2.NamespaceParameterDefaultsSpec{Defaults: map[string]map[string]intstr.IntOrString{
+				"Deployment": {"replicas": intstr.FromInt(3)},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a-platform"},
+			Spec: v1alpha2.NamespaceParameterDefaultsSpec{Defaults: map[string]map[string]intstr.IntOrString{
+				"Deployment":            {"replicas": intstr.FromInt(2), "image": intstr.FromString("nginx")},
+				"ContainerizedWorkload": {"image": intstr.FromString("busybox")},
+			}},
+		},
+	}
+	ac := v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"}}
+
+	// step is performed before each Get on the cache.
+	type step func(c *NamespaceParameterDefaultsCache, q workqueue.RateLimitingInterface)
+
+	type want struct {
+		defaults map[string]intstr.IntOrString
+		err      error
+		lists    int
+		queued   int
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		steps  []step
+		want   want
+	}{
+		"Merged": {
+			reason: "Defaults should be merged in order of name, with later names winning",
+			steps:  []step{nil},
+			want: want{
+				defaults: map[string]intstr.IntOrString{"replicas": intstr.FromInt(3), "image": intstr.FromString("nginx")},
+				lists:    1,
+			},
+		},
+		"Cached": {
+			reason: "The NamespaceParameterDefaults of a namespace should only be listed once",
+			steps:  []step{nil, nil, nil},
+			want: want{
+				defaults: map[string]intstr.IntOrString{"replicas": intstr.FromInt(3), "image": intstr.FromString("nginx")},
+				lists:    1,
+			},
+		},
+		"Evicted": {
+			reason: "The NamespaceParameterDefaults of a namespace should be listed again, and its ApplicationConfigurations enqueued, once one is updated",
+			steps: []step{nil, func(c *NamespaceParameterDefaultsCache, q workqueue.RateLimitingInterface) {
+				c.Update(event.UpdateEvent{MetaNew: npds[0].DeepCopy()}, q)
+			}},
+			want: want{
+				defaults: map[string]intstr.IntOrString{"replicas": intstr.FromInt(3), "image": intstr.FromString("nginx")},
+				lists:    2,
+				queued:   1,
+			},
+		},
+		"EvictedOther": {
+			reason: "Updates to NamespaceParameterDefaults in other namespaces should not evict the cached defaults",
+			steps: []step{nil, func(c *NamespaceParameterDefaultsCache, q workqueue.RateLimitingInterface) {
+				c.Update(event.UpdateEvent{MetaNew: &v1alpha2.NamespaceParameterDefaults{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "a-platform"}}}, q)
+			}},
+			want: want{
+				defaults: map[string]intstr.IntOrString{"replicas": intstr.FromInt(3), "image": intstr.FromString("nginx")},
+				lists:    1,
+				queued:   1,
+			},
+		},
+		"ErrorNotCached": {
+			reason: "Errors listing NamespaceParameterDefaults should not be cached",
+			err:    errBoom,
+			steps:  []step{nil, nil},
+			want:   want{err: errors.Wrap(errBoom, errListParameterDefaults), lists: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lists := 0
+			c := NewNamespaceParameterDefaultsCache(&test.MockClient{
+				MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					switch l := obj.(type) {
+					case *v1alpha2.NamespaceParameterDefaultsList:
+						lists++
+						l.Items = append([]v1alpha2.NamespaceParameterDefaults{}, npds...)
+						return tc.err
+					case *v1alpha2.ApplicationConfigurationList:
+						l.Items = []v1alpha2.ApplicationConfiguration{ac}
+					}
+					return nil
+				},
+			}, logging.NewNopLogger())
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+
+			var got map[string]intstr.IntOrString
+			var err error
+			for _, s := range tc.steps {
+				if s != nil {
+					s(c, q)
+				}
+				got, err = c.Get(context.Background(), "ns", "Deployment")
+			}
+			if diff := cmp.Diff(tc.want, want{defaults: got, err: err, lists: lists, queued: q.Len()}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if tc.want.queued > 0 {
+				r, _ := q.Get()
+				if diff := cmp.Diff(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}}, r); diff != "" {
+					t.Errorf("\n%s\nq.Get(): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestWithParameterDefaults(t *testing.T) {
+	cp := []v1alpha2.ComponentParameter{{Name: "image"}, {Name: "replicas"}}
+
+	cases := map[string]struct {
+		reason   string
+		cpv      []v1alpha2.ComponentParameterValue
+		defaults map[string]intstr.IntOrString
+		want     []v1alpha2.ComponentParameterValue
+	}{
+		"NoDefaults": {
+			reason: "Parameter values should be unchanged when there are no defaults",
+			cpv:    []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx")}},
+			want:   []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx")}},
+		},
+		"AppConfigWins": {
+			reason: "Parameter values set by the ApplicationConfiguration should win over defaults",
+			cpv:    []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx")}},
+			defaults: map[string]intstr.IntOrString{
+				"image":    intstr.FromString("busybox"),
+				"replicas": intstr.FromInt(2),
+			},
+			want: []v1alpha2.ComponentParameterValue{
+				{Name: "image", Value: intstr.FromString("nginx")},
+				{Name: "replicas", Value: intstr.FromInt(2)},
+			},
+		},
+		"UnsupportedDefaults": {
+			reason: "Defaults of parameters the component does not accept should be ignored",
+			defaults: map[string]intstr.IntOrString{
+				"replicas": intstr.FromInt(2),
+				"cpu":      intstr.FromString("500m"),
+			},
+			want: []v1alpha2.ComponentParameterValue{{Name: "replicas", Value: intstr.FromInt(2)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := withParameterDefaults(cp, tc.cpv, tc.defaults)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nwithParameterDefaults(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Partition error strings.
+const (
+	errGetMemberLease       = "cannot get partition member lease"
+	errCreateMemberLease    = "cannot create partition member lease"
+	errRenewMemberLease     = "cannot renew partition member lease"
+	errDeleteMemberLease    = "cannot delete partition member lease"
+	errListMemberLeases     = "cannot list partition member leases"
+	errGetAppConfigLease    = "cannot get ApplicationConfiguration lease"
+	errCreateAppConfigLease = "cannot create ApplicationConfiguration lease"
+	errRenewAppConfigLease  = "cannot renew ApplicationConfiguration lease"
+)
+
+// DefaultPartitionLeaseDuration is the default duration of the Leases held by
+// a LeasePartitioner.
+const DefaultPartitionLeaseDuration = 15 * time.Second
+
+// appConfigLeasePrefix prefixes the name of the Lease of an
+// ApplicationConfiguration, which is created in its namespace.
+const appConfigLeasePrefix = "oam-appconfig-"
+
+// A LeasePartitioner partitions ApplicationConfigurations between several
+// replicas of the controller, allowing it to scale horizontally rather than
+// electing a single leader. Each replica holds a member Lease, labelled
+// oam.dev/partition-member, in the partition namespace. Each
+// ApplicationConfiguration is owned by the member selected by rendezvous
+// hashing of its UID, and is only reconciled by its owner while the owner
+// holds the ApplicationConfiguration's Lease. A replica acquires the Lease of
+// an ApplicationConfiguration it owns once any previous holder's Lease has
+// expired, so ApplicationConfigurations move between replicas safely as
+// replicas join and leave.
+type LeasePartitioner struct {
+	client    client.Client
+	namespace string
+	identity  string
+	duration  time.Duration
+	log       logging.Logger
+	now       func() time.Time
+
+	// events enqueue the ApplicationConfigurations a replica owns when the
+	// members change, since their watch events may have been filtered out
+	// while another member owned them.
+	events chan event.GenericEvent
+
+	mu sync.RWMutex
+	// members that hold an unexpired member Lease, sorted by identity. It is
+	// nil until the members are first listed, during which time no
+	// ApplicationConfigurations are owned.
+	members []string
+}
+
+var _ manager.Runnable = &LeasePartitioner{}
+
+// NewLeasePartitioner returns a LeasePartitioner that is a member of the
+// partition whose member Leases are in the supplied namespace, under the
+// supplied identity. Its Leases expire after the supplied duration unless
+// they are renewed. The identity must be unique to each replica, e.g. its
+// pod name.
+func NewLeasePartitioner(c client.Client, namespace, identity string, d time.Duration, l logging.Logger) *LeasePartitioner {
+	return &LeasePartitioner{
+		client:    c,
+		namespace: namespace,
+		identity:  identity,
+		duration:  d,
+		log:       l,
+		now:       time.Now,
+		events:    make(chan event.GenericEvent),
+	}
+}
+
+// Start renewing the member Lease, and watching for members that join or
+// leave. It blocks until the supplied channel is closed, then deletes the
+// member Lease so that the remaining members may take over promptly.
+func (p *LeasePartitioner) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t := time.NewTicker(p.duration / 3)
+	defer t.Stop()
+	for {
+		if err := p.refresh(ctx, stop); err != nil {
+			p.log.Debug("Cannot refresh partition members", "error", err)
+		}
+		select {
+		case <-stop:
+			l := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: p.namespace, Name: p.identity}}
+			return errors.Wrap(resource.IgnoreNotFound(p.client.Delete(ctx, l)), errDeleteMemberLease)
+		case <-t.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false; every replica of the controller manager
+// is a member of the partition.
+func (p *LeasePartitioner) NeedLeaderElection() bool {
+	return false
+}
+
+// Owns returns true if the supplied ApplicationConfiguration UID is owned by
+// this replica.
+func (p *LeasePartitioner) Owns(uid types.UID) bool {
+	return p.Owner(uid) == p.identity
+}
+
+// Owner returns the identity of the member that owns the supplied
+// ApplicationConfiguration UID, i.e. the member with the highest rendezvous
+// hash for it. It returns an empty string if the members are not yet known.
+func (p *LeasePartitioner) Owner(uid types.UID) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	owner, max := "", uint64(0)
+	for _, m := range p.members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(m))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(uid))
+		if s := h.Sum64(); owner == "" || s > max {
+			owner, max = m, s
+		}
+	}
+	return owner
+}
+
+// Acquire or renew the Lease of the supplied ApplicationConfiguration. It
+// returns false if this replica does not own the ApplicationConfiguration, or
+// if another replica still holds its Lease.
+func (p *LeasePartitioner) Acquire(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (bool, error) {
+	if !p.Owns(ac.GetUID()) {
+		return false, nil
+	}
+
+	l := &coordinationv1.Lease{}
+	nn := types.NamespacedName{Namespace: ac.GetNamespace(), Name: appConfigLeasePrefix + string(ac.GetUID())}
+	err := p.client.Get(ctx, nn, l)
+	if kerrors.IsNotFound(err) {
+		l = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+			Namespace: nn.Namespace,
+			Name:      nn.Name,
+			// The Lease is garbage collected with its ApplicationConfiguration.
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+		}}
+		p.hold(l)
+		err := p.client.Create(ctx, l)
+		if kerrors.IsAlreadyExists(err) {
+			// Another replica created the Lease first.
+			return false, nil
+		}
+		return err == nil, errors.Wrap(err, errCreateAppConfigLease)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetAppConfigLease)
+	}
+	if p.heldByOther(l) {
+		return false, nil
+	}
+	p.hold(l)
+	err = p.client.Update(ctx, l)
+	if kerrors.IsConflict(err) {
+		// Another replica updated the Lease first.
+		return false, nil
+	}
+	return err == nil, errors.Wrap(err, errRenewAppConfigLease)
+}
+
+// LeaseDuration returns the duration of the Leases held by the partitioner.
+func (p *LeasePartitioner) LeaseDuration() time.Duration {
+	return p.duration
+}
+
+// refresh renews the member Lease, then lists the members. The
+// ApplicationConfigurations this replica owns are enqueued if the members
+// changed.
+func (p *LeasePartitioner) refresh(ctx context.Context, stop <-chan struct{}) error {
+	if err := p.renew(ctx); err != nil {
+		return err
+	}
+
+	l := &coordinationv1.LeaseList{}
+	if err := p.client.List(ctx, l, client.InNamespace(p.namespace), client.MatchingLabels{oam.LabelPartitionMember: "true"}); err != nil {
+		return errors.Wrap(err, errListMemberLeases)
+	}
+	members := []string{p.identity}
+	for i := range l.Items {
+		if l.Items[i].GetName() == p.identity || !p.held(&l.Items[i]) {
+			continue
+		}
+		members = append(members, l.Items[i].GetName())
+	}
+	sort.Strings(members)
+
+	p.mu.Lock()
+	changed := !equalMembers(p.members, members)
+	p.members = members
+	p.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+	p.log.Debug("Partition members changed", "members", members)
+	return p.enqueueOwned(ctx, stop)
+}
+
+func (p *LeasePartitioner) renew(ctx context.Context) error {
+	l := &coordinationv1.Lease{}
+	err := p.client.Get(ctx, types.NamespacedName{Namespace: p.namespace, Name: p.identity}, l)
+	if kerrors.IsNotFound(err) {
+		l = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.namespace,
+			Name:      p.identity,
+			Labels:    map[string]string{oam.LabelPartitionMember: "true"},
+		}}
+		p.hold(l)
+		return errors.Wrap(p.client.Create(ctx, l), errCreateMemberLease)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetMemberLease)
+	}
+	p.hold(l)
+	return errors.Wrap(p.client.Update(ctx, l), errRenewMemberLease)
+}
+
+func (p *LeasePartitioner) enqueueOwned(ctx context.Context, stop <-chan struct{}) error {
+	l := &v1alpha2.ApplicationConfigurationList{}
+	if err := p.client.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListAppConfigs)
+	}
+	for i := range l.Items {
+		ac := &l.Items[i]
+		if !p.Owns(ac.GetUID()) {
+			continue
+		}
+		select {
+		case p.events <- event.GenericEvent{Meta: ac, Object: ac}:
+		case <-stop:
+			return nil
+		}
+	}
+	return nil
+}
+
+// hold the supplied Lease, renewing it if it is already held.
+func (p *LeasePartitioner) hold(l *coordinationv1.Lease) {
+	now := metav1.NewMicroTime(p.now())
+	seconds := int32(p.duration / time.Second)
+	if l.Spec.HolderIdentity == nil || *l.Spec.HolderIdentity != p.identity {
+		id := p.identity
+		l.Spec.HolderIdentity = &id
+		l.Spec.AcquireTime = &now
+	}
+	l.Spec.LeaseDurationSeconds = &seconds
+	l.Spec.RenewTime = &now
+}
+
+// held returns true if the supplied Lease is held and has not expired.
+func (p *LeasePartitioner) held(l *coordinationv1.Lease) bool {
+	s := l.Spec
+	if s.HolderIdentity == nil || *s.HolderIdentity == "" || s.RenewTime == nil || s.LeaseDurationSeconds == nil {
+		return false
+	}
+	return s.RenewTime.Add(time.Duration(*s.LeaseDurationSeconds) * time.Second).After(p.now())
+}
+
+// heldByOther returns true if the supplied Lease is held by another replica
+// and has not expired.
+func (p *LeasePartitioner) heldByOther(l *coordinationv1.Lease) bool {
+	return p.held(l) && *l.Spec.HolderIdentity != p.identity
+}
+
+func equalMembers(a, b []string) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// LeaseAwarePredicate filters out the watch events of ApplicationConfigurations
+// that are not owned by the supplied partitioner's replica.
+func LeaseAwarePredicate(p *LeasePartitioner) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return p.Owns(e.Meta.GetUID()) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return p.Owns(e.MetaNew.GetUID()) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return p.Owns(e.Meta.GetUID()) },
+		GenericFunc: func(e event.GenericEvent) bool { return p.Owns(e.Meta.GetUID()) },
+	}
+}
+
+// PartitionedRunnable returns the supplied runnable wrapped so that it runs on
+// every replica of a controller manager whose ApplicationConfigurations are
+// partitioned, rather than only on the elected leader.
+func PartitionedRunnable(r manager.Runnable) manager.Runnable {
+	return unelectedRunnable{Runnable: r}
+}
+
+type unelectedRunnable struct {
+	manager.Runnable
+}
+
+func (unelectedRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// A partitionedManager adds the controllers and other runnables of the
+// ApplicationConfiguration reconciler such that they run on every replica.
+// Runnables added through it are not injected with the manager's
+// dependencies, so it is only used for runnables that are constructed with
+// them, such as controllers.
+type partitionedManager struct {
+	manager.Manager
+}
+
+func (m partitionedManager) Add(r manager.Runnable) error {
+	return m.Manager.Add(PartitionedRunnable(r))
+}
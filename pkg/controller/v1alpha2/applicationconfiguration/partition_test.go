@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestLeasePartitionerOwner(t *testing.T) {
+	p := NewLeasePartitioner(nil, "oam-system", "a", DefaultPartitionLeaseDuration, logging.NewNopLogger())
+	if got := p.Owner("uid"); got != "" {
+		t.Errorf("Owner(...): want no owner before the members are known, got %q", got)
+	}
+
+	p.members = []string{"a", "b", "c"}
+	owners := make(map[types.UID]string)
+	counts := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		uid := types.UID(fmt.Sprintf("uid-%d", i))
+		owners[uid] = p.Owner(uid)
+		counts[owners[uid]]++
+	}
+	for _, m := range p.members {
+		if counts[m] == 0 {
+			t.Errorf("Owner(...): want member %q to own some ApplicationConfigurations, got none", m)
+		}
+	}
+
+	// Removing a member must only move the ApplicationConfigurations it owned.
+	p.members = []string{"a", "c"}
+	for uid, was := range owners {
+		got := p.Owner(uid)
+		if was != "b" && got != was {
+			t.Errorf("Owner(%q): want %q to remain the owner after b left, got %q", uid, was, got)
+		}
+		if got == "b" {
+			t.Errorf("Owner(%q): want b to own nothing after it left", uid)
+		}
+	}
+}
+
+func TestLeasePartitionerAcquire(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+	gr := schema.GroupResource{Group: coordinationv1.GroupName, Resource: "leases"}
+
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool", UID: "uid"}}
+
+	lease := func(holder string, renewed time.Time) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			seconds := int32(DefaultPartitionLeaseDuration / time.Second)
+			rt := metav1.NewMicroTime(renewed)
+			*obj.(*coordinationv1.Lease) = coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &seconds,
+				RenewTime:            &rt,
+			}}
+			return nil
+		}
+	}
+
+	type want struct {
+		acquired bool
+		err      error
+	}
+	cases := map[string]struct {
+		reason  string
+		members []string
+		c       client.Client
+		want    want
+	}{
+		"NotOwned": {
+			reason:  "An ApplicationConfiguration owned by another member should not be acquired",
+			members: []string{"b"},
+			want:    want{acquired: false},
+		},
+		"GetError": {
+			reason:  "Errors getting the Lease should be returned",
+			members: []string{"a"},
+			c:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:    want{err: errors.Wrap(errBoom, errGetAppConfigLease)},
+		},
+		"Created": {
+			reason:  "A Lease that does not exist should be created",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(gr, "oam-appconfig-uid")),
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					l := obj.(*coordinationv1.Lease)
+					if l.GetNamespace() != "ns" || l.GetName() != "oam-appconfig-uid" || *l.Spec.HolderIdentity != "a" {
+						return errors.Errorf("unexpected lease %s/%s held by %s", l.GetNamespace(), l.GetName(), *l.Spec.HolderIdentity)
+					}
+					return nil
+				},
+			},
+			want: want{acquired: true},
+		},
+		"CreatedByOther": {
+			reason:  "A Lease that another replica created first should not be acquired",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet:    test.NewMockGetFn(kerrors.NewNotFound(gr, "oam-appconfig-uid")),
+				MockCreate: test.NewMockCreateFn(kerrors.NewAlreadyExists(gr, "oam-appconfig-uid")),
+			},
+			want: want{acquired: false},
+		},
+		"HeldByOther": {
+			reason:  "A Lease held by another replica should not be acquired until it expires",
+			members: []string{"a"},
+			c:       &test.MockClient{MockGet: test.NewMockGetFn(nil, lease("b", now))},
+			want:    want{acquired: false},
+		},
+		"Expired": {
+			reason:  "An expired Lease held by another replica should be acquired",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, lease("b", now.Add(-2*DefaultPartitionLeaseDuration))),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			want: want{acquired: true},
+		},
+		"Renewed": {
+			reason:  "A Lease already held by this replica should be renewed",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, lease("a", now)),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			want: want{acquired: true},
+		},
+		"Conflict": {
+			reason:  "A Lease that another replica updated first should not be acquired",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, lease("a", now)),
+				MockUpdate: test.NewMockUpdateFn(kerrors.NewConflict(gr, "oam-appconfig-uid", errBoom)),
+			},
+			want: want{acquired: false},
+		},
+		"UpdateError": {
+			reason:  "Errors renewing the Lease should be returned",
+			members: []string{"a"},
+			c: &test.MockClient{
+				MockGet:    test.NewMockGetFn(nil, lease("a", now)),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: want{err: errors.Wrap(errBoom, errRenewAppConfigLease)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewLeasePartitioner(tc.c, "oam-system", "a", DefaultPartitionLeaseDuration, logging.NewNopLogger())
+			p.now = func() time.Time { return now }
+			p.members = tc.members
+
+			acquired, err := p.Acquire(context.Background(), ac)
+			got := want{acquired: acquired, err: err}
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\np.Acquire(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLeasePartitionerRefresh(t *testing.T) {
+	now := time.Now()
+	seconds := int32(DefaultPartitionLeaseDuration / time.Second)
+	member := func(name string, renewed time.Time) coordinationv1.Lease {
+		rt := metav1.NewMicroTime(renewed)
+		return coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &name, LeaseDurationSeconds: &seconds, RenewTime: &rt},
+		}
+	}
+
+	c := &test.MockClient{
+		MockGet:    test.NewMockGetFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil),
+		MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+			switch l := obj.(type) {
+			case *coordinationv1.LeaseList:
+				l.Items = []coordinationv1.Lease{
+					member("a", now),
+					member("c", now),
+					member("b", now.Add(-2*DefaultPartitionLeaseDuration)),
+				}
+			case *v1alpha2.ApplicationConfigurationList:
+				// No ApplicationConfigurations need to be enqueued.
+			}
+			return nil
+		}),
+	}
+	p := NewLeasePartitioner(c, "oam-system", "a", DefaultPartitionLeaseDuration, logging.NewNopLogger())
+	p.now = func() time.Time { return now }
+
+	if err := p.refresh(context.Background(), make(chan struct{})); err != nil {
+		t.Fatalf("p.refresh(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{"a", "c"}, p.members); diff != "" {
+		t.Errorf("p.refresh(...): want members with expired Leases excluded, -want, +got:\n%s", diff)
+	}
+}
+
+func TestPartitionedManagerAdd(t *testing.T) {
+	var added manager.Runnable
+	m := partitionedManager{Manager: &addRecordingManager{add: func(r manager.Runnable) error {
+		added = r
+		return nil
+	}}}
+
+	started := false
+	r := manager.RunnableFunc(func(_ <-chan struct{}) error {
+		started = true
+		return nil
+	})
+	if err := m.Add(r); err != nil {
+		t.Fatalf("m.Add(...): %s", err)
+	}
+	le, ok := added.(manager.LeaderElectionRunnable)
+	if !ok || le.NeedLeaderElection() {
+		t.Errorf("m.Add(...): want runnable that does not need leader election")
+	}
+	_ = added.Start(nil)
+	if !started {
+		t.Errorf("m.Add(...): want added runnable to start the supplied runnable")
+	}
+}
+
+// An addRecordingManager is a manager.Manager whose Add method is mocked.
+type addRecordingManager struct {
+	manager.Manager
+	add func(manager.Runnable) error
+}
+
+func (m *addRecordingManager) Add(r manager.Runnable) error {
+	return m.add(r)
+}
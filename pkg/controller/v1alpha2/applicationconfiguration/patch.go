@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Patch error strings.
+const (
+	errMarshalPatches = "cannot marshal patches"
+	errDecodePatches  = "cannot decode patches"
+	errApplyPatches   = "cannot apply patches"
+	errPatchIdentity  = "patches must not change the apiVersion or kind of a workload"
+
+	errFmtPatchWorkload = "cannot patch workload of component %q: %s"
+)
+
+// A patchError indicates that the patches of a component could not be applied
+// to its workload.
+type patchError struct {
+	component string
+	err       error
+}
+
+func (e *patchError) Error() string {
+	return fmt.Sprintf(errFmtPatchWorkload, e.component, e.err)
+}
+
+// IsPatchFailed returns true if the supplied error indicates that the patches
+// of a component could not be applied to its workload.
+func IsPatchFailed(err error) bool {
+	_, ok := errors.Cause(err).(*patchError)
+	return ok
+}
+
+// applyPatches applies the supplied RFC 6902 JSON patch operations to the
+// supplied workload, in order. The workload is left unchanged if any
+// operation fails.
+func applyPatches(w *unstructured.Unstructured, ops []v1alpha2.PatchOperation) (err error) {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// Some malformed patches cause the JSON patch library to panic, e.g. a
+	// path that indexes past the end of an array. Surface them as errors.
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%s: %v", errApplyPatches, r)
+		}
+	}()
+
+	raw, err := json.Marshal(ops)
+	if err != nil {
+		return errors.Wrap(err, errMarshalPatches)
+	}
+	p, err := jsonpatch.DecodePatch(raw)
+	if err != nil {
+		return errors.Wrap(err, errDecodePatches)
+	}
+	doc, err := w.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, errMarshalWorkload)
+	}
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return errors.Wrap(err, errApplyPatches)
+	}
+
+	// Unstructured's own decoder is used so that integers remain int64s.
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(patched); err != nil {
+		return errors.Wrap(err, errUnmarshalWorkload)
+	}
+	if u.GetAPIVersion() != w.GetAPIVersion() || u.GetKind() != w.GetKind() {
+		return errors.New(errPatchIdentity)
+	}
+	w.Object = u.Object
+	return nil
+}
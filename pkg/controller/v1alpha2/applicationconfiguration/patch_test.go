@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestApplyPatches(t *testing.T) {
+	workload := func(image string, replicas int64, labels map[string]interface{}) *unstructured.Unstructured {
+		meta := map[string]interface{}{"name": "cool"}
+		if labels != nil {
+			meta["labels"] = labels
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   meta,
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "c0", "image": image}},
+				}},
+			},
+		}}
+	}
+	value := func(raw string) *runtime.RawExtension { return &runtime.RawExtension{Raw: []byte(raw)} }
+
+	type want struct {
+		w      *unstructured.Unstructured
+		failed bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		ops    []v1alpha2.PatchOperation
+		want   want
+	}{
+		"NoPatches": {
+			reason: "A workload should be unchanged when there are no patches",
+			w:      workload("nginx:1", 1, nil),
+			want:   want{w: workload("nginx:1", 1, nil)},
+		},
+		"AddAndReplace": {
+			reason: "Patch operations should be applied in order",
+			w:      workload("nginx:1", 1, nil),
+			ops: []v1alpha2.PatchOperation{
+				{Op: "replace", Path: "/spec/template/spec/containers/0/image", Value: value(`"nginx:2"`)},
+				{Op: "replace", Path: "/spec/replicas", Value: value(`3`)},
+				{Op: "add", Path: "/metadata/labels", Value: value(`{"app":"cool"}`)},
+			},
+			want: want{w: workload("nginx:2", 3, map[string]interface{}{"app": "cool"})},
+		},
+		"Remove": {
+			reason: "Remove operations should remove fields",
+			w:      workload("nginx:1", 1, map[string]interface{}{"app": "cool"}),
+			ops:    []v1alpha2.PatchOperation{{Op: "remove", Path: "/metadata/labels"}},
+			want:   want{w: workload("nginx:1", 1, nil)},
+		},
+		"InvalidPath": {
+			reason: "A patch of a path that does not exist should fail and leave the workload unchanged",
+			w:      workload("nginx:1", 1, nil),
+			ops:    []v1alpha2.PatchOperation{{Op: "replace", Path: "/spec/template/spec/containers/3/image", Value: value(`"nginx:2"`)}},
+			want:   want{w: workload("nginx:1", 1, nil), failed: true},
+		},
+		"FailedTest": {
+			reason: "A failed test operation should fail and leave the workload unchanged",
+			w:      workload("nginx:1", 1, nil),
+			ops: []v1alpha2.PatchOperation{
+				{Op: "replace", Path: "/spec/replicas", Value: value(`3`)},
+				{Op: "test", Path: "/spec/template/spec/containers/0/image", Value: value(`"nginx:2"`)},
+			},
+			want: want{w: workload("nginx:1", 1, nil), failed: true},
+		},
+		"ChangeKind": {
+			reason: "A patch should not be allowed to change the kind of a workload",
+			w:      workload("nginx:1", 1, nil),
+			ops:    []v1alpha2.PatchOperation{{Op: "replace", Path: "/kind", Value: value(`"StatefulSet"`)}},
+			want:   want{w: workload("nginx:1", 1, nil), failed: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := applyPatches(tc.w, tc.ops)
+			if err != nil {
+				err = &patchError{component: "cool", err: err}
+			}
+			got := want{w: tc.w, failed: IsPatchFailed(err)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\napplyPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
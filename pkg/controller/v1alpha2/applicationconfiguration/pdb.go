@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// PodDisruptionBudget error strings.
+const (
+	errFmtApplyPDB = "cannot apply pod disruption budget of workload %q"
+)
+
+// workloadMatchLabelsPath is the field path of the labels by which workloads
+// like Deployments select their pods.
+const workloadMatchLabelsPath = "spec.selector.matchLabels"
+
+// renderPodDisruptionBudget returns the PodDisruptionBudget of the supplied
+// workload, or nil if it has none. It is named after the workload, and its
+// namespace is set when it is applied. It returns false if a PodDisruptionBudget is to be rendered but
+// the workload has no label selector.
+func renderPodDisruptionBudget(w *unstructured.Unstructured, pdb *v1alpha2.ComponentPodDisruptionBudget) (*unstructured.Unstructured, bool, error) {
+	if pdb == nil {
+		return nil, true, nil
+	}
+
+	v, err := fieldpath.Pave(w.UnstructuredContent()).GetValue(workloadMatchLabelsPath)
+	if err != nil {
+		// The workload has no label selector.
+		return nil, false, nil
+	}
+	labels, ok := v.(map[string]interface{})
+	if !ok || len(labels) == 0 {
+		return nil, false, nil
+	}
+
+	b := &unstructured.Unstructured{}
+	b.SetAPIVersion("policy/v1beta1")
+	b.SetKind("PodDisruptionBudget")
+	b.SetName(w.GetName())
+	var minAvailable interface{} = pdb.MinAvailable.StrVal
+	if pdb.MinAvailable.Type == intstr.Int {
+		minAvailable = int64(pdb.MinAvailable.IntVal)
+	}
+	// Unlike a paved object, these setters preserve an integer minAvailable
+	// rather than round tripping it through JSON as a float.
+	if err := unstructured.SetNestedField(b.Object, minAvailable, "spec", "minAvailable"); err != nil {
+		return nil, false, err
+	}
+	if err := unstructured.SetNestedMap(b.Object, labels, "spec", "selector", "matchLabels"); err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// applyPodDisruptionBudget applies the PodDisruptionBudget of the supplied
+// workload, if any, controlled by the applied workload.
+func (a *workloads) applyPodDisruptionBudget(ctx context.Context, applicator resource.Applicator, wl Workload) error {
+	if wl.PodDisruptionBudget == nil {
+		return nil
+	}
+	b := wl.PodDisruptionBudget.DeepCopy()
+	b.SetNamespace(wl.Workload.GetNamespace())
+	b.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(wl.Workload, wl.Workload.GroupVersionKind())})
+
+	err := applicator.Apply(ctx, b, resource.MustBeControllableBy(wl.Workload.GetUID()))
+	err = explainForbidden(err, wl.ServiceAccountName, b.GetKind(), b.GetName())
+	return errors.Wrapf(err, errFmtApplyPDB, wl.Workload.GetName())
+}
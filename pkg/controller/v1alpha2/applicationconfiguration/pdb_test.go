@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRenderPodDisruptionBudget(t *testing.T) {
+	workload := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		},
+	}}
+	pdb := func(minAvailable interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodDisruptionBudget",
+			"metadata":   map[string]interface{}{"name": "web"},
+			"spec": map[string]interface{}{
+				"minAvailable": minAvailable,
+				"selector":     map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+			},
+		}}
+	}
+
+	type want struct {
+		pdb       *unstructured.Unstructured
+		supported bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		pdb    *v1alpha2.ComponentPodDisruptionBudget
+		want   want
+	}{
+		"NoPodDisruptionBudget": {
+			reason: "No PodDisruptionBudget should be rendered when the component does not specify one",
+			w:      workload,
+			want:   want{supported: true},
+		},
+		"Int": {
+			reason: "An integer minAvailable should be rendered as an integer",
+			w:      workload,
+			pdb:    &v1alpha2.ComponentPodDisruptionBudget{MinAvailable: intstr.FromInt(2)},
+			want:   want{pdb: pdb(int64(2)), supported: true},
+		},
+		"Percent": {
+			reason: "A percentage minAvailable should be rendered as a string",
+			w:      workload,
+			pdb:    &v1alpha2.ComponentPodDisruptionBudget{MinAvailable: intstr.FromString("50%")},
+			want:   want{pdb: pdb("50%"), supported: true},
+		},
+		"NoSelector": {
+			reason: "A workload without a label selector should be reported as such",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			pdb:    &v1alpha2.ComponentPodDisruptionBudget{MinAvailable: intstr.FromInt(1)},
+			want:   want{supported: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, supported, err := renderPodDisruptionBudget(tc.w, tc.pdb)
+			if err != nil {
+				t.Fatalf("\n%s\nrenderPodDisruptionBudget(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{pdb: got, supported: supported}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nrenderPodDisruptionBudget(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Reconcile policy error strings.
+const (
+	errHashWorkloads = "cannot hash rendered workloads"
+	errFmtGetApplied = "cannot get applied %s %q"
+)
+
+// resyncPeriod returns the period after which the supplied
+// ApplicationConfiguration should be reconciled again.
+func resyncPeriod(ac *v1alpha2.ApplicationConfiguration) time.Duration {
+	p := ac.Spec.ReconcilePolicy
+	if p == nil || p.ResyncPeriod == nil || p.ResyncPeriod.Duration <= 0 {
+		return longWait
+	}
+	return p.ResyncPeriod.Duration
+}
+
+// applyOnChange returns true if the supplied ApplicationConfiguration should
+// only be applied when its rendered workloads change.
+func applyOnChange(ac *v1alpha2.ApplicationConfiguration) bool {
+	return ac.Spec.ReconcilePolicy != nil && ac.Spec.ReconcilePolicy.Mode == v1alpha2.ReconcilePolicyOnChange
+}
+
+// renderHash returns a hash of the supplied rendered workloads and traits, the
+// references of their scopes, and the namespaces they are applied to.
+func (r *Reconciler) renderHash(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload) (string, error) {
+	selected, err := r.selectedNamespaces(ctx, ac)
+	if err != nil {
+		return "", err
+	}
+	namespaces := make([]string, 0, len(selected))
+	for ns := range selected {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	type hashed struct {
+		Workload *unstructured.Unstructured
+		Traits   []unstructured.Unstructured
+		Scopes   []v1alpha2.WorkloadScope
+
+		PodDisruptionBudget *unstructured.Unstructured `json:",omitempty"`
+		TrafficSplit        *TrafficSplit              `json:",omitempty"`
+	}
+	h := struct {
+		Workloads  []hashed
+		Namespaces []string
+	}{Workloads: make([]hashed, len(w)), Namespaces: namespaces}
+	for i := range w {
+		// Scopes are hashed by reference because their live state changes
+		// independently of the ApplicationConfiguration.
+		h.Workloads[i] = hashed{Workload: w[i].Workload, Traits: w[i].Traits, Scopes: w[i].Status().Scopes, PodDisruptionBudget: w[i].PodDisruptionBudget, TrafficSplit: w[i].TrafficSplit}
+	}
+
+	b, err := json.Marshal(h)
+	if err != nil {
+		return "", errors.Wrap(err, errHashWorkloads)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// drifted returns true if any workload or trait recorded in the status of the
+// supplied ApplicationConfiguration no longer exists.
+func (r *Reconciler) drifted(ctx context.Context, c client.Reader, ac *v1alpha2.ApplicationConfiguration) (bool, error) {
+	for _, ws := range ac.Status.Workloads {
+		refs := make([]runtimev1alpha1.TypedReference, 0, len(ws.Traits)+1)
+		refs = append(refs, ws.Reference)
+		for _, t := range ws.Traits {
+			refs = append(refs, t.Reference)
+		}
+
+		for _, ref := range refs {
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion(ref.APIVersion)
+			u.SetKind(ref.Kind)
+			err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ref.Name}, u)
+			if kerrors.IsNotFound(err) {
+				return true, nil
+			}
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtGetApplied, ref.Kind, ref.Name)
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestResyncPeriod(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		policy *v1alpha2.ReconcilePolicy
+		want   time.Duration
+	}{
+		"NoPolicy": {
+			reason: "ApplicationConfigurations without a reconcile policy should be reconciled after the default period",
+			want:   longWait,
+		},
+		"NoResyncPeriod": {
+			reason: "Reconcile policies without a resync period should use the default period",
+			policy: &v1alpha2.ReconcilePolicy{Mode: v1alpha2.ReconcilePolicyAlways},
+			want:   longWait,
+		},
+		"ResyncPeriod": {
+			reason: "Reconcile policies with a resync period should use it",
+			policy: &v1alpha2.ReconcilePolicy{
+				Mode:         v1alpha2.ReconcilePolicyAlways,
+				ResyncPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+			want: 5 * time.Minute,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{ReconcilePolicy: tc.policy}}
+			if diff := cmp.Diff(tc.want, resyncPeriod(ac)); diff != "" {
+				t.Errorf("\n%s\nresyncPeriod(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderHash(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{}
+	r := &Reconciler{}
+
+	workload := func(image string) []Workload {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v")
+		u.SetKind("workload")
+		u.SetName("workload")
+		_ = unstructured.SetNestedField(u.Object, image, "spec", "image")
+		return []Workload{{ComponentName: "coolcomponent", Workload: u}}
+	}
+
+	a, err := r.renderHash(context.Background(), ac, workload("cool:1"))
+	if err != nil {
+		t.Fatalf("r.renderHash(...): unexpected error: %s", err)
+	}
+	b, err := r.renderHash(context.Background(), ac, workload("cool:1"))
+	if err != nil {
+		t.Fatalf("r.renderHash(...): unexpected error: %s", err)
+	}
+	c, err := r.renderHash(context.Background(), ac, workload("cool:2"))
+	if err != nil {
+		t.Fatalf("r.renderHash(...): unexpected error: %s", err)
+	}
+
+	if a != b {
+		t.Errorf("r.renderHash(...): identical workloads should have identical hashes: %q != %q", a, b)
+	}
+	if a == c {
+		t.Errorf("r.renderHash(...): different workloads should have different hashes: %q == %q", a, c)
+	}
+}
+
+func TestDrifted(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+		Status: v1alpha2.ApplicationConfigurationStatus{
+			Workloads: []v1alpha2.WorkloadStatus{{
+				Reference: runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "workload"},
+				Traits: []v1alpha2.WorkloadTrait{{
+					Reference: runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "trait", Name: "trait"},
+				}},
+			}},
+		},
+	}
+
+	type want struct {
+		drifted bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting an applied resource should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetApplied, "workload", "workload"),
+			},
+		},
+		"TraitDeleted": {
+			reason: "A deleted trait should be considered drift",
+			client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if obj.(*unstructured.Unstructured).GetKind() == "trait" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				return nil
+			}},
+			want: want{drifted: true},
+		},
+		"NoDrift": {
+			reason: "No drift should be reported when all applied resources exist",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			want:   want{drifted: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{}
+			got, err := r.drifted(context.Background(), tc.client, ac)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.drifted(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drifted, got); diff != "" {
+				t.Errorf("\n%s\nr.drifted(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// A healthPoller probes the workloads of components with a status poll
+// interval between reconciles, using a goroutine per component. Its
+// goroutines are stopped when it is stopped.
+type healthPoller struct {
+	client client.Client
+	health HealthProber
+	log    logging.Logger
+
+	// appSets renders the components of ApplicationConfigurations whose spec
+	// source is an Argo CD ApplicationSet.
+	appSets *ArgoApplicationSetRenderer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	polls map[types.NamespacedName]map[string]*poll
+}
+
+type poll struct {
+	interval time.Duration
+	cancel   context.CancelFunc
+}
+
+var _ manager.Runnable = &healthPoller{}
+
+func newHealthPoller(c client.Client, h HealthProber, l logging.Logger) *healthPoller {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &healthPoller{
+		client: c,
+		health: h,
+		log:    l,
+		ctx:    ctx,
+		cancel: cancel,
+		polls:  make(map[types.NamespacedName]map[string]*poll),
+	}
+}
+
+// Start blocks until the supplied channel is closed, then stops all polls.
+func (p *healthPoller) Start(stop <-chan struct{}) error {
+	<-stop
+	p.cancel()
+	return nil
+}
+
+// Sync the polls of the supplied ApplicationConfiguration with its components.
+// Polls are started for components with a health probe and a status poll
+// interval, and stopped for any other components.
+func (p *healthPoller) Sync(ac *v1alpha2.ApplicationConfiguration) {
+	nn := types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}
+	want := make(map[string]time.Duration)
+	for _, c := range ac.Spec.Components {
+		if c.HealthProbe == nil || c.StatusPollInterval == nil || c.StatusPollInterval.Duration <= 0 {
+			continue
+		}
+		want[c.ComponentName] = c.StatusPollInterval.Duration
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	polls := p.polls[nn]
+	for component, pl := range polls {
+		if interval, ok := want[component]; !ok || interval != pl.interval {
+			pl.cancel()
+			delete(polls, component)
+		}
+	}
+	for component, interval := range want {
+		if _, ok := polls[component]; ok {
+			continue
+		}
+		if polls == nil {
+			polls = make(map[string]*poll)
+		}
+		ctx, cancel := context.WithCancel(p.ctx)
+		polls[component] = &poll{interval: interval, cancel: cancel}
+		go p.run(ctx, nn, component, interval)
+	}
+	if len(polls) == 0 {
+		delete(p.polls, nn)
+		return
+	}
+	p.polls[nn] = polls
+}
+
+// Stop all polls of the supplied ApplicationConfiguration, e.g. because it was
+// deleted.
+func (p *healthPoller) Stop(nn types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pl := range p.polls[nn] {
+		pl.cancel()
+	}
+	delete(p.polls, nn)
+}
+
+func (p *healthPoller) run(ctx context.Context, nn types.NamespacedName, component string, interval time.Duration) {
+	log := p.log.WithValues("request", nn, "component", component)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := p.poll(ctx, nn, component); err != nil {
+				log.Debug("Cannot poll workload health", "error", err)
+			}
+		}
+	}
+}
+
+// poll probes the workload of the supplied component, and updates its health
+// in the status of the supplied ApplicationConfiguration.
+func (p *healthPoller) poll(ctx context.Context, nn types.NamespacedName, component string) error {
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := p.client.Get(ctx, nn, ac); err != nil {
+		return errors.Wrap(err, errGetAppConfig)
+	}
+	if err := resolveSpecSource(ctx, p.client, p.appSets, ac); err != nil {
+		return errors.Wrap(err, errResolveSpecSource)
+	}
+
+	var probe *v1alpha2.ComponentHealthProbe
+	for _, c := range ac.Spec.Components {
+		if c.ComponentName == component {
+			probe = c.HealthProbe
+		}
+	}
+	if probe == nil {
+		return nil
+	}
+
+	for i := range ac.Status.Workloads {
+		if ac.Status.Workloads[i].ComponentName == component {
+			probeWorkload(ctx, p.health, ac, &ac.Status.Workloads[i], probe)
+		}
+	}
+	return errors.Wrap(p.client.Status().Update(ctx, ac), errUpdateAppConfigStatus)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestHealthPollerSync(t *testing.T) {
+	probe := &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{URL: "http://example.org/healthz"}}
+	interval := &metav1.Duration{Duration: time.Hour}
+	nn := types.NamespacedName{Namespace: "ns", Name: "cool"}
+
+	ac := func(c ...v1alpha2.ApplicationConfigurationComponent) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name},
+			Spec:       v1alpha2.ApplicationConfigurationSpec{Components: c},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		acs    []*v1alpha2.ApplicationConfiguration
+		want   []string
+	}{
+		"PollIntervalAndProbe": {
+			reason: "Components with a health probe and a status poll interval should be polled",
+			acs: []*v1alpha2.ApplicationConfiguration{ac(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "polled", HealthProbe: probe, StatusPollInterval: interval},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "unprobed", StatusPollInterval: interval},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "unpolled", HealthProbe: probe},
+			)},
+			want: []string{"polled"},
+		},
+		"ComponentRemoved": {
+			reason: "Polls of components that no longer have a status poll interval should be stopped",
+			acs: []*v1alpha2.ApplicationConfiguration{
+				ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "polled", HealthProbe: probe, StatusPollInterval: interval}),
+				ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "polled", HealthProbe: probe}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newHealthPoller(&test.MockClient{}, nil, logging.NewNopLogger())
+			defer p.cancel()
+			for _, a := range tc.acs {
+				p.Sync(a)
+			}
+			var got []string
+			for component := range p.polls[nn] {
+				got = append(got, component)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want polled components, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHealthPollerStop(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "ns", Name: "cool"}
+	p := newHealthPoller(&test.MockClient{}, nil, logging.NewNopLogger())
+	defer p.cancel()
+	p.Sync(&v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name},
+		Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{{
+			ComponentName:      "polled",
+			HealthProbe:        &v1alpha2.ComponentHealthProbe{},
+			StatusPollInterval: &metav1.Duration{Duration: time.Hour},
+		}}},
+	})
+	p.Stop(nn)
+	if _, ok := p.polls[nn]; ok {
+		t.Errorf("p.Stop(...): polls of %s were not stopped", nn)
+	}
+}
+
+func TestHealthPollerPoll(t *testing.T) {
+	errBoom := errors.New("boom")
+	probe := &v1alpha2.ComponentHealthProbe{HTTPGet: &v1alpha2.ComponentHTTPGetProbe{URL: "http://example.org/healthz"}}
+
+	ac := &v1alpha2.ApplicationConfiguration{
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "polled", HealthProbe: probe},
+				{ComponentName: "other", HealthProbe: probe},
+			},
+		},
+		Status: v1alpha2.ApplicationConfigurationStatus{
+			Workloads: []v1alpha2.WorkloadStatus{{ComponentName: "polled"}, {ComponentName: "other"}},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *test.MockClient
+		want   error
+	}{
+		"GetError": {
+			reason: "Errors getting the ApplicationConfiguration should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrap(errBoom, errGetAppConfig),
+		},
+		"UpdateError": {
+			reason: "Errors updating the ApplicationConfiguration's status should be returned",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+					ac.DeepCopyInto(o.(*v1alpha2.ApplicationConfiguration))
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: errors.Wrap(errBoom, errUpdateAppConfigStatus),
+		},
+		"Polled": {
+			reason: "Only the health of the polled component's workload should be updated",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+					ac.DeepCopyInto(o.(*v1alpha2.ApplicationConfiguration))
+					return nil
+				}),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
+					want := []v1alpha2.WorkloadStatus{
+						{ComponentName: "polled", Health: &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusUnhealthy, Message: errBoom.Error()}, LastTransitionTime: &metav1.Time{}},
+						{ComponentName: "other"},
+					}
+					if diff := cmp.Diff(want, o.(*v1alpha2.ApplicationConfiguration).Status.Workloads, cmpopts.IgnoreTypes(metav1.Time{}), equateTimeSet()); diff != "" {
+						return errors.Errorf("-want workloads, +got workloads:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newHealthPoller(tc.client, HealthProberFn(func(_ context.Context, _ string, _ *v1alpha2.ComponentHealthProbe) error {
+				return errBoom
+			}), logging.NewNopLogger())
+			defer p.cancel()
+			err := p.poll(context.Background(), types.NamespacedName{Name: "cool"}, "polled")
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.poll(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Priority class error strings.
+const (
+	errFmtGetPriorityClass      = "cannot get PriorityClass %q"
+	errFmtPriorityClassNotFound = "PriorityClass %q of component %q does not exist"
+)
+
+// podPriorityClassPath is the field path of the priority class of workloads
+// that embed a pod template.
+const podPriorityClassPath = "spec.template.spec.priorityClassName"
+
+// injectPriorityClass sets the supplied priority class in the pod template of
+// the supplied workload. It returns false if there is a priority class to
+// inject but the workload has no pod template.
+func injectPriorityClass(w *unstructured.Unstructured, name string) (bool, error) {
+	if name == "" {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	return true, p.SetString(podPriorityClassPath, name)
+}
+
+// missingPriorityClasses returns a message for each PriorityClass of the
+// supplied workloads that does not exist.
+func missingPriorityClasses(ctx context.Context, c client.Reader, w []Workload) ([]string, error) {
+	msgs := make([]string, 0)
+	for _, wl := range w {
+		if wl.PriorityClassName == "" {
+			continue
+		}
+		err := c.Get(ctx, types.NamespacedName{Name: wl.PriorityClassName}, &schedulingv1.PriorityClass{})
+		if kerrors.IsNotFound(err) {
+			msgs = append(msgs, fmt.Sprintf(errFmtPriorityClassNotFound, wl.PriorityClassName, wl.ComponentName))
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetPriorityClass, wl.PriorityClassName)
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestInjectPriorityClass(t *testing.T) {
+	workload := func(priorityClass string) *unstructured.Unstructured {
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(podTemplateContainersPath, []interface{}{map[string]interface{}{"name": "c0"}})
+		if priorityClass != "" {
+			_ = p.SetString(podPriorityClassPath, priorityClass)
+		}
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+
+	type want struct {
+		priorityClass string
+		injected      bool
+	}
+	cases := map[string]struct {
+		reason        string
+		w             *unstructured.Unstructured
+		priorityClass string
+		want          want
+	}{
+		"NoPriorityClass": {
+			reason: "A workload's priority class should be unchanged when none is supplied",
+			w:      workload("low"),
+			want:   want{priorityClass: "low", injected: true},
+		},
+		"PodTemplate": {
+			reason:        "The supplied priority class should replace that of the pod template",
+			w:             workload("low"),
+			priorityClass: "high",
+			want:          want{priorityClass: "high", injected: true},
+		},
+		"NoPodTemplate": {
+			reason:        "A workload without a pod template should be unchanged, and reported as such",
+			w:             &unstructured.Unstructured{Object: map[string]interface{}{}},
+			priorityClass: "high",
+			want:          want{injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectPriorityClass(tc.w, tc.priorityClass)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectPriorityClass(...): unexpected error: %s", tc.reason, err)
+			}
+			got, _ := fieldpath.Pave(tc.w.UnstructuredContent()).GetString(podPriorityClassPath)
+			if diff := cmp.Diff(tc.want, want{priorityClass: got, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectPriorityClass(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMissingPriorityClasses(t *testing.T) {
+	errBoom := errors.New("boom")
+	w := []Workload{
+		{ComponentName: "a", PriorityClassName: "exists"},
+		{ComponentName: "b"},
+		{ComponentName: "c", PriorityClassName: "missing"},
+	}
+
+	type want struct {
+		missing []string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"Missing": {
+			reason: "PriorityClasses that do not exist should be reported",
+			client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, _ runtime.Object) error {
+				if key.Name == "missing" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				return nil
+			}},
+			want: want{missing: []string{fmt.Sprintf(errFmtPriorityClassNotFound, "missing", "c")}},
+		},
+		"GetError": {
+			reason: "Errors getting PriorityClasses should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetPriorityClass, "exists")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			missing, err := missingPriorityClasses(context.Background(), tc.client, w)
+			if diff := cmp.Diff(tc.want, want{missing: missing, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmissingPriorityClasses(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Probe error strings.
+const (
+	errFmtConvertProbe    = "cannot convert %s"
+	errFmtNoMainContainer = "pod template has no container named %q"
+)
+
+// Probe fields of a container.
+const (
+	fieldLivenessProbe  = "livenessProbe"
+	fieldReadinessProbe = "readinessProbe"
+)
+
+// injectLivenessProbe sets the supplied liveness probe on the main container
+// of the pod template of the supplied workload, replacing any probe it
+// already has. The main container is the one with the supplied name, or the
+// first container if no name is supplied. A probe without a handler removes
+// the container's probe. It returns false if there is a probe to inject but
+// the workload has no pod template containers.
+func injectLivenessProbe(w *unstructured.Unstructured, probe *corev1.Probe, container string) (bool, error) {
+	return injectProbe(w, fieldLivenessProbe, probe, container)
+}
+
+// injectReadinessProbe sets the supplied readiness probe on the main container
+// of the pod template of the supplied workload, in the same way as
+// injectLivenessProbe.
+func injectReadinessProbe(w *unstructured.Unstructured, probe *corev1.Probe, container string) (bool, error) {
+	return injectProbe(w, fieldReadinessProbe, probe, container)
+}
+
+func injectProbe(w *unstructured.Unstructured, field string, probe *corev1.Probe, container string) (bool, error) {
+	if probe == nil {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	v, err := p.GetValue(podTemplateContainersPath)
+	if err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	containers, ok := v.([]interface{})
+	if !ok || len(containers) == 0 {
+		return false, nil
+	}
+
+	main := -1
+	for i := range containers {
+		c, _ := containers[i].(map[string]interface{})
+		if name, _ := c["name"].(string); container == "" || name == container {
+			main = i
+			break
+		}
+	}
+	if main < 0 {
+		return false, errors.Errorf(errFmtNoMainContainer, container)
+	}
+	c, ok := containers[main].(map[string]interface{})
+	if !ok {
+		return false, errors.Errorf(errFmtNoMainContainer, container)
+	}
+
+	if probe.Exec == nil && probe.HTTPGet == nil && probe.TCPSocket == nil {
+		cleared := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			if k != field {
+				cleared[k] = v
+			}
+		}
+		return true, p.SetValue(fmt.Sprintf("%s[%d]", podTemplateContainersPath, main), cleared)
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(probe)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtConvertProbe, field)
+	}
+	return true, p.SetValue(fmt.Sprintf("%s[%d].%s", podTemplateContainersPath, main, field), m)
+}
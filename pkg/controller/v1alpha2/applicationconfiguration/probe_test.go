@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestInjectLivenessProbe(t *testing.T) {
+	existing := map[string]interface{}{"exec": map[string]interface{}{"command": []interface{}{"true"}}}
+	healthz := map[string]interface{}{"httpGet": map[string]interface{}{"path": "/healthz", "port": float64(8080)}}
+
+	// workload returns a workload whose pod template has a main and a sidecar
+	// container, with the supplied liveness probes.
+	workload := func(main, sidecar map[string]interface{}) *unstructured.Unstructured {
+		container := func(name string, probe map[string]interface{}) map[string]interface{} {
+			c := map[string]interface{}{"name": name}
+			if probe != nil {
+				c["livenessProbe"] = probe
+			}
+			return c
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{container("main", main), container("sidecar", sidecar)},
+			}}},
+		}}
+	}
+	probe := &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)}}}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+		err      error
+	}
+	cases := map[string]struct {
+		reason    string
+		w         *unstructured.Unstructured
+		probe     *corev1.Probe
+		container string
+		want      want
+	}{
+		"NilProbe": {
+			reason: "A workload should be unchanged when no probe is supplied",
+			w:      workload(existing, nil),
+			want:   want{w: workload(existing, nil), injected: true},
+		},
+		"ReplaceFirstContainer": {
+			reason: "The probe should replace that of the first container when no main container is named",
+			w:      workload(existing, nil),
+			probe:  probe,
+			want:   want{w: workload(healthz, nil), injected: true},
+		},
+		"NamedContainer": {
+			reason:    "The probe should replace that of the named main container",
+			w:         workload(existing, existing),
+			probe:     probe,
+			container: "sidecar",
+			want:      want{w: workload(existing, healthz), injected: true},
+		},
+		"EmptyProbeClears": {
+			reason: "A probe without a handler should clear the probe from the component",
+			w:      workload(existing, existing),
+			probe:  &corev1.Probe{},
+			want:   want{w: workload(nil, existing), injected: true},
+		},
+		"UnknownContainer": {
+			reason:    "An error should be returned if the named main container does not exist",
+			w:         workload(existing, nil),
+			probe:     probe,
+			container: "missing",
+			want:      want{w: workload(existing, nil), err: errors.Errorf(errFmtNoMainContainer, "missing")},
+		},
+		"NoPodTemplate": {
+			reason: "A workload without a pod template should be unchanged, and reported as such",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			probe:  probe,
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectLivenessProbe(tc.w, tc.probe, tc.container)
+			got := want{w: tc.w, injected: injected, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectLivenessProbe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInjectReadinessProbe(t *testing.T) {
+	liveness := map[string]interface{}{"exec": map[string]interface{}{"command": []interface{}{"true"}}}
+	ready := map[string]interface{}{"httpGet": map[string]interface{}{"path": "/ready", "port": float64(8080)}}
+
+	// workload returns a workload whose pod template has a main and a sidecar
+	// container, with the supplied readiness probes. Both containers have a
+	// liveness probe, which should never be touched.
+	workload := func(main, sidecar map[string]interface{}) *unstructured.Unstructured {
+		container := func(name string, probe map[string]interface{}) map[string]interface{} {
+			c := map[string]interface{}{"name": name, "livenessProbe": liveness}
+			if probe != nil {
+				c["readinessProbe"] = probe
+			}
+			return c
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{container("main", main), container("sidecar", sidecar)},
+			}}},
+		}}
+	}
+	probe := &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt(8080)}}}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+		err      error
+	}
+	cases := map[string]struct {
+		reason    string
+		w         *unstructured.Unstructured
+		probe     *corev1.Probe
+		container string
+		want      want
+	}{
+		"NilProbe": {
+			reason: "A workload should be unchanged when no probe is supplied",
+			w:      workload(liveness, nil),
+			want:   want{w: workload(liveness, nil), injected: true},
+		},
+		"NamedContainer": {
+			reason:    "The probe should replace, not merge with, that of the named main container",
+			w:         workload(liveness, liveness),
+			probe:     probe,
+			container: "sidecar",
+			want:      want{w: workload(liveness, ready), injected: true},
+		},
+		"EmptyProbeClears": {
+			reason:    "A probe without a handler (readinessProbe: {}) should clear the probe of the named main container",
+			w:         workload(ready, ready),
+			probe:     &corev1.Probe{},
+			container: "sidecar",
+			want:      want{w: workload(ready, nil), injected: true},
+		},
+		"UnknownContainer": {
+			reason:    "An error should be returned if the named main container does not exist",
+			w:         workload(ready, nil),
+			probe:     probe,
+			container: "missing",
+			want:      want{w: workload(ready, nil), err: errors.Errorf(errFmtNoMainContainer, "missing")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectReadinessProbe(tc.w, tc.probe, tc.container)
+			got := want{w: tc.w, injected: injected, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectReadinessProbe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
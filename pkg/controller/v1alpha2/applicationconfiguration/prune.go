@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Pruning error strings.
+const (
+	errFmtPruneComponent  = "cannot prune component %q"
+	errFmtGetTraitDef     = "cannot get trait definition of trait %q"
+	errFmtPruneResources  = "cannot prune %s resources of trait %q"
+	errFmtDeletePruned    = "cannot delete %s %q"
+	errFmtRemoveFromScope = "cannot remove workload from scope %q"
+	errFmtDeleteTrait     = "cannot delete trait %q"
+	errFmtDeleteWorkload  = "cannot delete workload %q"
+)
+
+// A ComponentPruner prunes the resources of components that were removed from
+// an ApplicationConfiguration.
+type ComponentPruner interface {
+	// Prune the resources of the supplied workload statuses, whose components
+	// were removed, from the supplied cluster.
+	Prune(ctx context.Context, target client.Client, namespace string, removed []v1alpha2.WorkloadStatus) error
+}
+
+// A ComponentPrunerFn prunes the resources of components that were removed
+// from an ApplicationConfiguration.
+type ComponentPrunerFn func(ctx context.Context, target client.Client, namespace string, removed []v1alpha2.WorkloadStatus) error
+
+// Prune the resources of the supplied workload statuses, whose components
+// were removed, from the supplied cluster.
+func (fn ComponentPrunerFn) Prune(ctx context.Context, target client.Client, namespace string, removed []v1alpha2.WorkloadStatus) error {
+	return fn(ctx, target, namespace, removed)
+}
+
+// A componentPruner prunes a removed component in the reverse order in which
+// it was applied: its traits in descending order of priority, each after the
+// cluster scoped resources its TraitDefinition says it may have created, then
+// its workload, then the workload's scope membership.
+type componentPruner struct {
+	// definitions reads TraitDefinitions, which are always read from the
+	// cluster of the ApplicationConfiguration.
+	definitions client.Reader
+
+	// appliers delete the kinds of trait that need more than a plain delete.
+	appliers *TraitApplierRegistry
+}
+
+type prunedTrait struct {
+	reference  runtimev1alpha1.TypedReference
+	definition v1alpha2.TraitDefinitionSpec
+}
+
+func (p *componentPruner) Prune(ctx context.Context, target client.Client, namespace string, removed []v1alpha2.WorkloadStatus) error {
+	for _, ws := range removed {
+		if err := p.prune(ctx, target, namespace, ws); err != nil {
+			return errors.Wrapf(err, errFmtPruneComponent, ws.ComponentName)
+		}
+	}
+	return nil
+}
+
+func (p *componentPruner) prune(ctx context.Context, target client.Client, namespace string, ws v1alpha2.WorkloadStatus) error {
+	traits, err := p.byPriority(ctx, ws.Traits)
+	if err != nil {
+		return err
+	}
+	for _, t := range traits {
+		if ws.ComponentUID != "" {
+			for _, k := range t.definition.PrunedResources {
+				if err := prunedResources(ctx, target, k, ws.ComponentUID); err != nil {
+					return errors.Wrapf(err, errFmtPruneResources, k.Kind, t.reference.Name)
+				}
+			}
+		}
+		if err := p.appliers.Delete(ctx, target, asUnstructured(t.reference, namespace)); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtDeleteTrait, t.reference.Name)
+		}
+	}
+
+	// Revision workloads are pruned along with the revision history.
+	if !IsRevisionWorkload(ws) {
+		if err := target.Delete(ctx, asUnstructured(ws.Reference, namespace)); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtDeleteWorkload, ws.Reference.Name)
+		}
+	}
+
+	for _, s := range ws.Scopes {
+		if err := removeFromScope(ctx, target, namespace, ws, s); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtRemoveFromScope, s.Reference.Name)
+		}
+	}
+	return nil
+}
+
+// byPriority returns the supplied traits with their definitions, in descending
+// order of priority. Traits without a definition have the default priority.
+func (p *componentPruner) byPriority(ctx context.Context, ts []v1alpha2.WorkloadTrait) ([]prunedTrait, error) {
+	traits := make([]prunedTrait, 0, len(ts))
+	for _, t := range ts {
+		pt := prunedTrait{reference: t.Reference}
+		td, err := util.FetchTraitDefinition(ctx, p.definitions, asUnstructured(t.Reference, ""))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, errFmtGetTraitDef, t.Reference.Name)
+		}
+		if td != nil {
+			pt.definition = td.Spec
+		}
+		traits = append(traits, pt)
+	}
+	sort.SliceStable(traits, func(i, j int) bool {
+		return traits[i].definition.Priority > traits[j].definition.Priority
+	})
+	return traits, nil
+}
+
+// prunedResources deletes the resources of the supplied kind that are
+// labelled with the supplied component UID.
+func prunedResources(ctx context.Context, c client.Client, k v1alpha2.PrunedResourceKind, uid string) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetAPIVersion(k.APIVersion)
+	l.SetKind(k.Kind + "List")
+	if err := c.List(ctx, l, client.MatchingLabels{oam.LabelComponentUID: uid}); err != nil {
+		return err
+	}
+	for i := range l.Items {
+		if err := c.Delete(ctx, &l.Items[i]); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtDeletePruned, k.Kind, l.Items[i].GetName())
+		}
+	}
+	return nil
+}
+
+// removedComponents partitions the supplied workload statuses into those of
+// components that are still among the supplied components, and those of
+// components that were removed.
+func removedComponents(acc []v1alpha2.ApplicationConfigurationComponent, ws []v1alpha2.WorkloadStatus) (retained, removed []v1alpha2.WorkloadStatus) {
+	names := make(map[string]bool, len(acc))
+	for _, c := range acc {
+		name := c.ComponentName
+		if c.RevisionName != "" {
+			name = ExtractComponentName(c.RevisionName)
+		}
+		names[name] = true
+	}
+	for _, s := range ws {
+		if names[s.ComponentName] {
+			retained = append(retained, s)
+			continue
+		}
+		removed = append(removed, s)
+	}
+	return retained, removed
+}
+
+func asUnstructured(ref runtimev1alpha1.TypedReference, namespace string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	u.SetNamespace(namespace)
+	u.SetName(ref.Name)
+	return u
+}
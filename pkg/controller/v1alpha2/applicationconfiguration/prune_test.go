@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestComponentPruner(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workloadRef := runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "Workload", Name: "workload"}
+	ws := v1alpha2.WorkloadStatus{
+		ComponentName: "removed",
+		ComponentUID:  "uid",
+		Reference:     workloadRef,
+		Traits: []v1alpha2.WorkloadTrait{
+			{Reference: runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "LowTrait", Name: "low"}},
+			{Reference: runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "HighTrait", Name: "high"}},
+		},
+		Scopes: []v1alpha2.WorkloadScope{
+			{Reference: runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "Scope", Name: "scope"}},
+		},
+	}
+
+	definitions := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		if !strings.HasPrefix(key.Name, "high") {
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		obj.(*v1alpha2.TraitDefinition).Spec = v1alpha2.TraitDefinitionSpec{
+			Priority:        10,
+			PrunedResources: []v1alpha2.PrunedResourceKind{{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"}},
+		}
+		return nil
+	}}
+
+	type want struct {
+		ops []string
+		err error
+	}
+	cases := map[string]struct {
+		reason      string
+		definitions client.Reader
+		deleteErr   error
+		want        want
+	}{
+		"ReverseApplyOrder": {
+			reason:      "Cluster scoped resources and traits should be pruned in descending order of priority, then the workload, then its scope membership",
+			definitions: definitions,
+			want: want{ops: []string{
+				"list ClusterRoleBindingList oam.dev/component-uid=uid",
+				"delete ClusterRoleBinding binding",
+				"delete HighTrait high",
+				"delete LowTrait low",
+				"delete Workload workload",
+				"get Scope scope",
+				"update Scope scope",
+			}},
+		},
+		"GetTraitDefinitionError": {
+			reason:      "Errors getting trait definitions should be returned",
+			definitions: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:        want{err: errors.Wrapf(errors.Wrapf(errBoom, errFmtGetTraitDef, "low"), errFmtPruneComponent, "removed")},
+		},
+		"DeleteError": {
+			reason:      "Errors deleting resources should be returned",
+			definitions: definitions,
+			deleteErr:   errBoom,
+			want: want{
+				ops: []string{
+					"list ClusterRoleBindingList oam.dev/component-uid=uid",
+					"delete ClusterRoleBinding binding",
+				},
+				err: errors.Wrapf(errors.Wrapf(errors.Wrapf(errBoom, errFmtDeletePruned, "ClusterRoleBinding", "binding"),
+					errFmtPruneResources, "ClusterRoleBinding", "high"), errFmtPruneComponent, "removed"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ops []string
+			target := &test.MockClient{
+				MockList: func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					l := list.(*unstructured.UnstructuredList)
+					ops = append(ops, "list "+l.GetKind()+" "+lo.LabelSelector.String())
+					b := unstructured.Unstructured{}
+					b.SetKind("ClusterRoleBinding")
+					b.SetName("binding")
+					b.SetLabels(map[string]string{oam.LabelComponentUID: "uid"})
+					l.Items = []unstructured.Unstructured{b}
+					return nil
+				},
+				MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+					u := obj.(*unstructured.Unstructured)
+					ops = append(ops, "delete "+u.GetKind()+" "+u.GetName())
+					return tc.deleteErr
+				},
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					u := obj.(*unstructured.Unstructured)
+					u.SetName(key.Name)
+					ops = append(ops, "get "+u.GetKind()+" "+u.GetName())
+					u.Object["spec"] = map[string]interface{}{"workloadRefs": []interface{}{map[string]interface{}{
+						"apiVersion": workloadRef.APIVersion,
+						"kind":       workloadRef.Kind,
+						"name":       workloadRef.Name,
+					}}}
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					u := obj.(*unstructured.Unstructured)
+					ops = append(ops, "update "+u.GetKind()+" "+u.GetName())
+					return nil
+				},
+			}
+
+			p := &componentPruner{definitions: tc.definitions}
+			err := p.Prune(context.Background(), target, "ns", []v1alpha2.WorkloadStatus{ws})
+			if diff := cmp.Diff(tc.want, want{ops: ops, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Prune(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemovedComponents(t *testing.T) {
+	acc := []v1alpha2.ApplicationConfigurationComponent{
+		{ComponentName: "retained"},
+		{RevisionName: "revisioned-v2"},
+	}
+	ws := []v1alpha2.WorkloadStatus{
+		{ComponentName: "retained"},
+		{ComponentName: "revisioned"},
+		{ComponentName: "removed"},
+	}
+
+	retained, removed := removedComponents(acc, ws)
+	if diff := cmp.Diff([]v1alpha2.WorkloadStatus{{ComponentName: "retained"}, {ComponentName: "revisioned"}}, retained); diff != "" {
+		t.Errorf("removedComponents(...): -want retained, +got retained:\n%s", diff)
+	}
+	if diff := cmp.Diff([]v1alpha2.WorkloadStatus{{ComponentName: "removed"}}, removed); diff != "" {
+		t.Errorf("removedComponents(...): -want removed, +got removed:\n%s", diff)
+	}
+}
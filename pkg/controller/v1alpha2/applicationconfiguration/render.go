@@ -19,19 +19,27 @@ package applicationconfiguration
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	clientappv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/dependency"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
@@ -54,6 +62,71 @@ const (
 	errFmtRequiredParam          = "required parameter %q not specified"
 	errFmtControllerRevisionData = "cannot get valid component data from controllerRevision %q"
 	errSetValueForField          = "can not set value %q for fieldPath %q"
+	errFmtDuplicateOutput        = "output %q is exposed by more than one component"
+	errFmtUnknownOutput          = "component %q input refers to unknown output %q"
+	errFmtCyclicDependency       = "inputs and outputs of components %q form a cycle"
+	errFmtGetOutput              = "cannot get output %q of component %q"
+	errFmtTraitConflict          = "trait %s of component %q conflicts with trait %s"
+
+	errFmtParameterType        = "must be of type %s"
+	errFmtOverrideImages       = "cannot override images of component %q"
+	errFmtSubstituteVariables  = "cannot substitute global variables into component %q"
+	errFmtInjectEnv            = "cannot inject environment variables into component %q"
+	errFmtUnsupportedEnv       = "workload of component %q has no pod template into which to inject environment variables"
+	errFmtInjectAffinity       = "cannot inject affinity into component %q"
+	errFmtUnsupportedAffinity  = "workload of component %q has no pod template into which to inject an affinity"
+	errFmtInjectTolerations    = "cannot inject tolerations into component %q"
+	errFmtInjectPriorityClass  = "cannot inject priority class into component %q"
+	errFmtInjectGracePeriod    = "cannot inject termination grace period into component %q"
+	errFmtInjectServiceAcct    = "cannot inject service account into component %q"
+	errFmtInjectSecurityCtx    = "cannot inject security context into component %q"
+	errFmtInjectHostNetwork    = "cannot inject host network mode into component %q"
+	errFmtInjectLivenessProbe  = "cannot inject liveness probe into component %q"
+	errFmtInjectReadinessProbe = "cannot inject readiness probe into component %q"
+	errFmtRenderPDB            = "cannot render pod disruption budget of component %q"
+	errFmtRenderNameTemplate   = "cannot render workload name template of component %q"
+	errFmtUnsupportedTols      = "workload of component %q has no pod template into which to inject tolerations"
+	errFmtUnsupportedPriority  = "workload of component %q has no pod template into which to inject a priority class"
+	errFmtInjectNodeSelector   = "cannot inject node selector into component %q"
+	errFmtUnsupportedNodeSel   = "workload of component %q has no pod template into which to inject a node selector"
+	errFmtUnsupportedHostNet   = "workload of component %q has no pod template into which to inject host network mode"
+	errFmtUnsupportedLiveness  = "workload of component %q has no pod template into which to inject a liveness probe"
+	errFmtUnsupportedPDB       = "workload of component %q has no label selector from which to render a pod disruption budget"
+	errFmtUnsupportedSvcAcct   = "workload of component %q has no pod template into which to inject a service account"
+	errFmtUnsupportedReadiness = "workload of component %q has no pod template into which to inject a readiness probe"
+	errFmtUnsupportedSecCtx    = "workload of component %q has no pod template into which to inject a security context"
+	errFmtUnsupportedTraffic   = "workload of component %q is not a Deployment with a label selector across which to split traffic"
+	errFmtInjectBinding        = "cannot inject service binding into component %q"
+	errFmtInjectVolumes        = "cannot inject ConfigMap volumes into component %q"
+	errNoPodTemplate           = "workload has no pod template"
+
+	errFmtGetWorkloadDefinition = "cannot get workload definition of component %q"
+	errFmtApplySchematic        = "cannot apply workload schematic of component %q"
+	errFmtOverrideReplicas      = "cannot override replicas of component %q"
+	errFmtSuspend               = "cannot suspend component %q"
+	errFmtResume                = "cannot resume component %q"
+	errFmtReplicasConflict      = "replicas conflict with parameter %q, which also sets %q"
+	errFmtGenerateComponentUID  = "cannot generate UID for component %q"
+)
+
+// defaultReplicaPath is the field path of the replicas of workloads whose
+// WorkloadDefinition does not specify a replica path.
+const defaultReplicaPath = "spec.replicas"
+
+// containerNamePlaceholder is replaced with the target container name of a
+// TraitDefinition in its workload reference path.
+const containerNamePlaceholder = "{containerName}"
+
+// podTemplateContainersPath is the field path of the containers of workloads
+// that embed a pod template, e.g. Deployments.
+const podTemplateContainersPath = "spec.template.spec.containers"
+
+// Parameter types.
+const (
+	parameterTypeString  = "string"
+	parameterTypeInteger = "integer"
+	parameterTypeNumber  = "number"
+	parameterTypeBoolean = "boolean"
 )
 
 const instanceNamePath = "metadata.name"
@@ -79,12 +152,38 @@ type components struct {
 	params     ParameterResolver
 	workload   ResourceRenderer
 	trait      ResourceRenderer
+	decryptor  SpecDecryptor
+	encryptor  SpecEncryptor
+	schematics OCISchematicFetcher
+	kustomize  KustomizeSchematicRenderer
+	variables  TemplateSubstitutor
+	secrets    oam.ParameterResolver
+
+	// labels propagates the labels of ApplicationConfigurations to their
+	// workloads and traits. Labels are not propagated if it is nil.
+	labels *LabelPropagator
+
+	// defaults supplies the default parameter values of the namespace of
+	// ApplicationConfigurations. No defaults are used if it is nil.
+	defaults *NamespaceParameterDefaultsCache
 }
 
 func (r *components) Render(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]Workload, error) {
+	sorted, err := sortByInputs(ac.Spec.Components)
+	if err != nil {
+		return nil, err
+	}
+
 	workloads := make([]Workload, 0, len(ac.Spec.Components))
 	dag := dependency.NewDAG(ac.DeepCopy())
-	for _, acc := range ac.Spec.Components {
+	outputs := make(map[string]intstr.IntOrString)
+	for _, acc := range sorted {
+		pv, ready := resolveInputs(acc.Inputs, outputs)
+		if !ready { // depends on outputs that are not yet available. Not creating now.
+			continue
+		}
+		acc.ParameterValues = append(pv, acc.ParameterValues...)
+
 		w, err := r.renderComponent(ctx, acc, ac, dag)
 		if err != nil {
 			return nil, err
@@ -92,11 +191,14 @@ func (r *components) Render(ctx context.Context, ac *v1alpha2.ApplicationConfigu
 		if w == nil { // depends on other resources. Not creating now.
 			continue
 		}
+		if err := r.resolveOutputs(ctx, acc, w.Workload, outputs); err != nil {
+			return nil, err
+		}
 		workloads = append(workloads, *w)
 	}
 
 	if !dag.IsEmpty() {
-		dependency.GlobalManager.AddDAG(ac.GetNamespace()+"/"+ac.GetName(), dag)
+		dependency.GlobalManager.AddDAG(util.AppConfigKey(ac), dag)
 	}
 	return workloads, nil
 }
@@ -109,20 +211,189 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 	if err != nil {
 		return nil, err
 	}
+	if rev := rollbackRevision(ac, acc.ComponentName, componentRevisionName); rev != "" {
+		// The component was rolled back from this revision.
+		acc.RevisionName = rev
+		if c, componentRevisionName, err = r.getComponent(ctx, acc, ac.GetNamespace()); err != nil {
+			return nil, err
+		}
+	}
+	if acc.Traits, err = withGlobalTraits(ac.Spec.GlobalTraits, acc); err != nil {
+		return nil, err
+	}
+	decrypted := hasEncrypted(acc.ParameterValues)
+	if acc.ParameterValues, err = decryptParameterValues(ctx, r.decryptor, acc.ParameterValues); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecryptComp, acc.ComponentName)
+	}
+	if ac.Spec.Encryption != nil {
+		pv, err := decryptSensitiveParameterValues(ctx, r.encryptor, acc.ParameterValues)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecryptComp, acc.ComponentName)
+		}
+		acc.ParameterValues = pv
+	}
+	pv, err := resolveSecretParameterValues(ctx, r.secrets, acc.ParameterValues, secretCacheTTL(ac))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtResolveSecrets, acc.ComponentName)
+	}
+	acc.ParameterValues = pv
+	if r.defaults != nil {
+		kind, err := rawWorkloadKind(c.Spec.Workload.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParameterDefaults, acc.ComponentName)
+		}
+		d, err := r.defaults.Get(ctx, ac.GetNamespace(), kind)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParameterDefaults, acc.ComponentName)
+		}
+		acc.ParameterValues = withParameterDefaults(c.Spec.Parameters, acc.ParameterValues, d)
+	}
+	if ac.GetAnnotations()[oam.AnnotationStrictParameterValidation] == "true" {
+		path := field.NewPath("spec", "components").Key(acc.ComponentName).Child("parameterValues")
+		if errs := validateParameterValues(path, c.Spec.Parameters, acc.ParameterValues); len(errs) > 0 {
+			return nil, &parameterValidationError{errs: errs}
+		}
+	}
 	p, err := r.params.Resolve(c.Spec.Parameters, acc.ParameterValues)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtResolveParams, acc.ComponentName)
 	}
 
-	w, err := r.workload.Render(c.Spec.Workload.Raw, p...)
+	raw, err := r.applySchematic(ctx, c.Spec.Workload.Raw, p)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtApplySchematic, acc.ComponentName)
+	}
+
+	w, err := r.workload.Render(raw, p...)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtRenderWorkload, acc.ComponentName)
 	}
 
+	if err := overrideImages(w, acc.ImageOverridePath, acc.ImageOverrides); err != nil {
+		return nil, errors.Wrapf(err, errFmtOverrideImages, acc.ComponentName)
+	}
+
+	strict := ac.GetAnnotations()[oam.AnnotationStrictGlobalVariables] == "true"
+	if len(ac.Spec.GlobalVariables) > 0 || strict {
+		if err := r.variables.Substitute(w, ac.Spec.GlobalVariables, strict); err != nil {
+			return nil, errors.Wrapf(err, errFmtSubstituteVariables, acc.ComponentName)
+		}
+	}
+
+	if err := applyPatches(w, acc.Patches); err != nil {
+		return nil, &patchError{component: acc.ComponentName, err: err}
+	}
+
+	injected, err := injectEnv(w, acc.Env)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectEnv, acc.ComponentName)
+	}
+
+	if acc.ServiceBindingRef != nil {
+		secret := util.ServiceBindingSecretName(ac.GetName(), acc.ComponentName)
+		if err := injectBinding(w, secret, acc.ServiceBindingRef.MountPath); err != nil {
+			return nil, errors.Wrapf(err, errFmtInjectBinding, acc.ComponentName)
+		}
+	}
+
+	if err := injectConfigMapVolumes(w, acc.ConfigMapVolumes); err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectVolumes, acc.ComponentName)
+	}
+
+	affinity, err := injectAffinity(w, acc.Affinity)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectAffinity, acc.ComponentName)
+	}
+
+	tolerations, err := injectTolerations(w, acc.Tolerations)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectTolerations, acc.ComponentName)
+	}
+
+	nodeSelector, err := injectNodeSelector(w, acc.NodeSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectNodeSelector, acc.ComponentName)
+	}
+
+	priority, err := injectPriorityClass(w, acc.PriorityClassName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectPriorityClass, acc.ComponentName)
+	}
+
+	hostNetwork, err := injectHostNetwork(w, acc.HostNetwork)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectHostNetwork, acc.ComponentName)
+	}
+
+	serviceAccount, err := injectServiceAccount(w, acc.ServiceAccountName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectServiceAcct, acc.ComponentName)
+	}
+
+	securityContext, err := injectSecurityContext(w, acc.SecurityContext)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectSecurityCtx, acc.ComponentName)
+	}
+
+	if err := injectTerminationGracePeriod(w, terminationGracePeriod(ac, acc)); err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectGracePeriod, acc.ComponentName)
+	}
+
+	liveness, err := injectLivenessProbe(w, acc.LivenessProbe, acc.MainContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectLivenessProbe, acc.ComponentName)
+	}
+
+	readiness, err := injectReadinessProbe(w, acc.ReadinessProbe, acc.MainContainerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInjectReadinessProbe, acc.ComponentName)
+	}
+
+	if acc.Replicas != nil {
+		path, err := r.replicaPath(ctx, w)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, acc.ComponentName)
+		}
+		if err := overrideReplicas(w, path, *acc.Replicas, p); err != nil {
+			return nil, errors.Wrapf(err, errFmtOverrideReplicas, acc.ComponentName)
+		}
+	}
+
+	resumed := !acc.Suspended && wasSuspended(ac.Status.Workloads, acc.ComponentName)
+	if acc.Suspended || resumed {
+		path, err := r.replicaPath(ctx, w)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, acc.ComponentName)
+		}
+		if acc.Suspended {
+			if err := fieldpath.Pave(w.UnstructuredContent()).SetNumber(path, 0); err != nil {
+				return nil, errors.Wrapf(err, errFmtSuspend, acc.ComponentName)
+			}
+		} else if err := resumeReplicas(w, path); err != nil {
+			return nil, errors.Wrapf(err, errFmtResume, acc.ComponentName)
+		}
+	}
+
 	ref := metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)
 	w.SetOwnerReferences([]metav1.OwnerReference{*ref})
 	w.SetNamespace(ac.GetNamespace())
 
+	uid, err := componentUID(ac.Status.Workloads, acc.ComponentName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGenerateComponentUID, acc.ComponentName)
+	}
+	meta.AddAnnotations(w, map[string]string{oam.AnnotationComponentUID: uid})
+
+	if len(acc.SubscribesTo) > 0 {
+		e, err := lastReceivedEvent(ctx, r.client, ac, acc.SubscribesTo)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtLastReceivedEvent, acc.ComponentName)
+		}
+		if e != "" {
+			meta.AddAnnotations(w, map[string]string{oam.AnnotationLastReceivedEvent: e})
+		}
+	}
+
 	traits := make([]unstructured.Unstructured, 0, len(acc.Traits))
 	traitDefs := make([]v1alpha2.TraitDefinition, 0, len(acc.Traits))
 	for _, ct := range acc.Traits {
@@ -134,12 +405,55 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 		if t == nil { // Depends on other resources. Not creating it now.
 			continue
 		}
+		if err := renderCanaryWeights(ac, t); err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderTrait, acc.ComponentName)
+		}
 		traits = append(traits, *t)
 		traitDefs = append(traitDefs, *traitDef)
 	}
+	if err := checkTraitConflicts(acc.ComponentName, traits, traitDefs); err != nil {
+		return nil, err
+	}
+	sortTraitsByPriority(traits, traitDefs)
 	if err := SetWorkloadInstanceName(traitDefs, w, c); err != nil {
 		return nil, err
 	}
+	nameTemplateErr := ""
+	if acc.WorkloadNameTemplate != "" {
+		revision := componentRevisionName
+		if revision == "" && c.Status.LatestRevision != nil {
+			revision = c.Status.LatestRevision.Name
+		}
+		name, err := renderWorkloadName(acc.WorkloadNameTemplate, workloadNameValues{
+			AppConfigName: ac.GetName(),
+			ComponentName: acc.ComponentName,
+			Namespace:     ac.GetNamespace(),
+			Revision:      revision,
+		})
+		if err != nil {
+			// We fall back to the name defined by the component.
+			nameTemplateErr = errors.Wrapf(err, errFmtRenderNameTemplate, acc.ComponentName).Error()
+		} else {
+			w.SetName(name)
+		}
+	}
+
+	pdb, pdbSupported, err := renderPodDisruptionBudget(w, acc.PodDisruptionBudget)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRenderPDB, acc.ComponentName)
+	}
+
+	if r.labels != nil {
+		r.labels.Propagate(ac.GetLabels(), w)
+		for i := range traits {
+			r.labels.Propagate(ac.GetLabels(), &traits[i])
+		}
+	}
+
+	split, splitSupported, err := renderTrafficSplit(w, acc.TrafficWeight)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRenderTrafficSplit, acc.ComponentName)
+	}
 
 	scopes := make([]unstructured.Unstructured, 0, len(acc.Scopes))
 	for _, cs := range acc.Scopes {
@@ -161,7 +475,43 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 		return nil, nil
 	}
 
-	return &Workload{ComponentName: acc.ComponentName, ComponentRevisionName: componentRevisionName, Workload: w, Traits: traits, Scopes: scopes}, nil
+	wl := &Workload{ComponentName: acc.ComponentName, ComponentRevisionName: componentRevisionName, ComponentUID: uid, Workload: w, Traits: traits, Scopes: scopes}
+	wl.UnsupportedEnv = !injected
+	wl.UnsupportedAffinity = !affinity
+	wl.UnsupportedTolerations = !tolerations
+	wl.UnsupportedNodeSelector = !nodeSelector
+	wl.UnsupportedPriorityClass = !priority
+	wl.UnsupportedHostNetwork = !hostNetwork
+	wl.UnsupportedLivenessProbe = !liveness
+	wl.UnsupportedReadinessProbe = !readiness
+	wl.PodDisruptionBudget = pdb
+	wl.UnsupportedPodDisruptionBudget = !pdbSupported
+	wl.TrafficSplit = split
+	wl.UnsupportedTrafficWeight = !splitSupported
+	wl.NameTemplateError = nameTemplateErr
+	if priority {
+		wl.PriorityClassName = acc.PriorityClassName
+	}
+	wl.UnsupportedServiceAccount = !serviceAccount
+	if serviceAccount {
+		wl.PodServiceAccountName = acc.ServiceAccountName
+		wl.CreatePodServiceAccount = acc.CreateServiceAccount
+	}
+	wl.SecurityContextInjected = securityContext && acc.SecurityContext != nil
+	wl.UnsupportedSecurityContext = !securityContext
+	wl.Suspended = acc.Suspended
+	wl.Resumed = resumed
+	wl.Decrypted = decrypted
+	for _, v := range acc.ConfigMapVolumes {
+		wl.ConfigMaps = append(wl.ConfigMaps, v.ConfigMapName)
+	}
+	if acc.ApplyAs != nil {
+		wl.ServiceAccountName = acc.ApplyAs.ServiceAccountRef.Name
+	}
+	if acc.ApplyTimeout != nil {
+		wl.ApplyTimeout = acc.ApplyTimeout.Duration
+	}
+	return wl, nil
 }
 
 func (r *components) renderTrait(ctx context.Context, ct v1alpha2.ComponentTrait, namespace, componentName string, ref *metav1.OwnerReference, dag *dependency.DAG) (*unstructured.Unstructured, *v1alpha2.TraitDefinition, error) {
@@ -307,6 +657,215 @@ func renderWorkload(data []byte, p ...Parameter) (*unstructured.Unstructured, er
 	return &unstructured.Unstructured{Object: w.UnstructuredContent()}, nil
 }
 
+// overrideImages sets the image of each container at the supplied field path
+// whose name matches a key of the supplied overrides. The pod template's
+// containers are used if no path is supplied. Workloads with no containers at
+// the path are left unchanged.
+func overrideImages(w *unstructured.Unstructured, path string, overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	if path == "" {
+		path = podTemplateContainersPath
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	v, err := p.GetValue(path)
+	if err != nil {
+		// The workload has no containers at this path.
+		return nil
+	}
+	containers, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	for i := range containers {
+		c, ok := containers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := c["name"].(string)
+		image, ok := overrides[name]
+		if !ok {
+			continue
+		}
+		if err := p.SetString(fmt.Sprintf("%s[%d].image", path, i), image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectEnv sets the supplied environment variables on each container of the
+// pod template of the supplied workload, replacing any variables of the same
+// name. It returns false if there are variables to inject but the workload
+// has no pod template containers.
+func injectEnv(w *unstructured.Unstructured, env []v1alpha2.ComponentEnvVar) (bool, error) {
+	if len(env) == 0 {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	v, err := p.GetValue(podTemplateContainersPath)
+	if err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	containers, ok := v.([]interface{})
+	if !ok || len(containers) == 0 {
+		return false, nil
+	}
+	for i := range containers {
+		path := fmt.Sprintf("%s[%d].env", podTemplateContainersPath, i)
+		existing, _ := p.GetValue(path)
+		vars, _ := existing.([]interface{})
+		for _, e := range env {
+			vars = setEnvVar(vars, e)
+		}
+		if err := p.SetValue(path, vars); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// bindingVolumeName is the name of the volume in which the connection
+// information of a service binding is mounted.
+const bindingVolumeName = "oam-service-binding"
+
+// injectBinding injects the connection information in the supplied secret
+// into each container of the pod template of the supplied workload. The
+// secret is mounted at the supplied path if there is one, and its values are
+// injected as environment variables if not.
+func injectBinding(w *unstructured.Unstructured, secret, mountPath string) error {
+	p := fieldpath.Pave(w.UnstructuredContent())
+	v, err := p.GetValue(podTemplateContainersPath)
+	if err != nil {
+		return errors.New(errNoPodTemplate)
+	}
+	containers, ok := v.([]interface{})
+	if !ok || len(containers) == 0 {
+		return errors.New(errNoPodTemplate)
+	}
+
+	if mountPath != "" {
+		vols, _ := p.GetValue("spec.template.spec.volumes")
+		volumes, _ := vols.([]interface{})
+		volumes = append(volumes, map[string]interface{}{
+			"name":   bindingVolumeName,
+			"secret": map[string]interface{}{"secretName": secret},
+		})
+		if err := p.SetValue("spec.template.spec.volumes", volumes); err != nil {
+			return err
+		}
+	}
+
+	for i := range containers {
+		key, value := "envFrom", map[string]interface{}{"secretRef": map[string]interface{}{"name": secret}}
+		if mountPath != "" {
+			key, value = "volumeMounts", map[string]interface{}{"name": bindingVolumeName, "mountPath": mountPath, "readOnly": true}
+		}
+		path := fmt.Sprintf("%s[%d].%s", podTemplateContainersPath, i, key)
+		existing, _ := p.GetValue(path)
+		values, _ := existing.([]interface{})
+		if err := p.SetValue(path, append(values, value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setEnvVar replaces the environment variable of the supplied name, or appends
+// it if there is none.
+func setEnvVar(vars []interface{}, e v1alpha2.ComponentEnvVar) []interface{} {
+	v := map[string]interface{}{"name": e.Name, "value": e.Value}
+	for i := range vars {
+		if ev, ok := vars[i].(map[string]interface{}); ok && ev["name"] == e.Name {
+			vars[i] = v
+			return vars
+		}
+	}
+	return append(vars, v)
+}
+
+// replicaPath returns the field path of the replicas of the supplied workload.
+// Workloads whose kind has no WorkloadDefinition use the default path.
+func (r *components) replicaPath(ctx context.Context, w *unstructured.Unstructured) (string, error) {
+	wd, err := util.FetchWorkloadDefinition(ctx, r.client, w)
+	if kerrors.IsNotFound(err) {
+		return defaultReplicaPath, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if wd.Spec.ReplicaPath == "" {
+		return defaultReplicaPath, nil
+	}
+	return wd.Spec.ReplicaPath, nil
+}
+
+// overrideReplicas sets the supplied replicas at the supplied field path of
+// the workload. It returns an error if any of the supplied parameters also
+// sets the path.
+func overrideReplicas(w *unstructured.Unstructured, path string, replicas int32, p []Parameter) error {
+	for _, param := range p {
+		for _, fp := range param.FieldPaths {
+			if fp == path {
+				return errors.Errorf(errFmtReplicasConflict, param.Name, path)
+			}
+		}
+	}
+	return fieldpath.Pave(w.UnstructuredContent()).SetNumber(path, float64(replicas))
+}
+
+// sortTraitsByPriority sorts the supplied traits, and their parallel slice of
+// definitions, in ascending order of priority. Traits of equal priority retain
+// the order in which they were specified.
+func sortTraitsByPriority(traits []unstructured.Unstructured, defs []v1alpha2.TraitDefinition) {
+	sort.Stable(traitsByPriority{traits: traits, defs: defs})
+}
+
+type traitsByPriority struct {
+	traits []unstructured.Unstructured
+	defs   []v1alpha2.TraitDefinition
+}
+
+func (p traitsByPriority) Len() int {
+	return len(p.traits)
+}
+
+func (p traitsByPriority) Less(i, j int) bool {
+	return p.defs[i].Spec.Priority < p.defs[j].Spec.Priority
+}
+
+func (p traitsByPriority) Swap(i, j int) {
+	p.traits[i], p.traits[j] = p.traits[j], p.traits[i]
+	p.defs[i], p.defs[j] = p.defs[j], p.defs[i]
+}
+
+// wasSuspended returns true if the supplied workload statuses indicate that
+// the named component was suspended when it was last reconciled.
+func wasSuspended(ws []v1alpha2.WorkloadStatus, component string) bool {
+	for _, s := range ws {
+		if s.ComponentName == component && s.GetCondition(v1alpha2.TypeSuspended).Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeReplicas restores the replicas of a workload whose component is no
+// longer suspended. Replicas rendered from the component are left as is. If
+// the component sets no replicas they are explicitly cleared so that applying
+// the workload removes the zero replicas set while it was suspended.
+func resumeReplicas(w *unstructured.Unstructured, path string) error {
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(path); err == nil {
+		return nil
+	}
+	return p.SetValue(path, nil)
+}
+
 func renderTrait(data []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
 	// TODO(negz): Is there a better decoder to use here?
 	u := &unstructured.Unstructured{}
@@ -379,6 +938,64 @@ func resolve(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameter
 	return params, nil
 }
 
+// A parameterValidationError indicates that the parameter values supplied to
+// a component do not match their declared types.
+type parameterValidationError struct {
+	errs field.ErrorList
+}
+
+func (e *parameterValidationError) Error() string {
+	return e.errs.ToAggregate().Error()
+}
+
+// IsParameterValidationFailed returns true if the supplied error indicates
+// that the parameter values supplied to a component do not match their
+// declared types.
+func IsParameterValidationFailed(err error) bool {
+	_, ok := errors.Cause(err).(*parameterValidationError)
+	return ok
+}
+
+// validateParameterValues validates the supplied parameter values against the
+// types of the parameters they set. Parameters that do not declare a type and
+// values for unknown parameters are not validated.
+func validateParameterValues(path *field.Path, cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameterValue) field.ErrorList {
+	declared := make(map[string]string)
+	for _, p := range cp {
+		if p.Type != nil {
+			declared[p.Name] = *p.Type
+		}
+	}
+
+	var errs field.ErrorList
+	for _, v := range cpv {
+		t, ok := declared[v.Name]
+		if !ok || validParameterValue(t, v.Value) {
+			continue
+		}
+		errs = append(errs, field.Invalid(path.Key(v.Name), v.Value.String(), fmt.Sprintf(errFmtParameterType, t)))
+	}
+	return errs
+}
+
+func validParameterValue(t string, v intstr.IntOrString) bool {
+	switch t {
+	case parameterTypeString:
+		return v.Type == intstr.String
+	case parameterTypeInteger:
+		return v.Type == intstr.Int
+	case parameterTypeNumber:
+		if v.Type == intstr.Int {
+			return true
+		}
+		_, err := strconv.ParseFloat(v.StrVal, 64)
+		return err == nil
+	case parameterTypeBoolean:
+		return v.Type == intstr.String && (v.StrVal == "true" || v.StrVal == "false")
+	}
+	return true
+}
+
 func addDataOutputsToDAG(dag *dependency.DAG, outs []v1alpha2.DataOutput, obj *unstructured.Unstructured) {
 	for _, out := range outs {
 		r := &corev1.ObjectReference{
@@ -397,3 +1014,220 @@ func addDataInputsToDAG(dag *dependency.DAG, ins []v1alpha2.DataInput, obj *unst
 		dag.AddSink(in.ValueFrom.DataOutputName, obj, attaches, in.ToFieldPaths)
 	}
 }
+
+// A cyclicDependencyError indicates that the inputs and outputs of an
+// ApplicationConfiguration's components form a cycle.
+type cyclicDependencyError struct {
+	components []string
+}
+
+func (e *cyclicDependencyError) Error() string {
+	return fmt.Sprintf(errFmtCyclicDependency, e.components)
+}
+
+// IsCyclicDependency returns true if the supplied error indicates that the
+// inputs and outputs of an ApplicationConfiguration's components form a cycle.
+func IsCyclicDependency(err error) bool {
+	_, ok := errors.Cause(err).(*cyclicDependencyError)
+	return ok
+}
+
+// A traitConflictError indicates that two traits of a component conflict with
+// each other.
+type traitConflictError struct {
+	component string
+	a, b      string
+}
+
+func (e *traitConflictError) Error() string {
+	return fmt.Sprintf(errFmtTraitConflict, e.a, e.component, e.b)
+}
+
+// IsTraitConflict returns true if the supplied error indicates that two traits
+// of a component conflict with each other.
+func IsTraitConflict(err error) bool {
+	_, ok := errors.Cause(err).(*traitConflictError)
+	return ok
+}
+
+// checkTraitConflicts returns an error if the TraitDefinition of any of the
+// supplied traits declares that it conflicts with another of the supplied
+// traits. The supplied traits and TraitDefinitions must correspond by index.
+func checkTraitConflicts(component string, traits []unstructured.Unstructured, traitDefs []v1alpha2.TraitDefinition) error {
+	for i := range traitDefs {
+		for _, c := range traitDefs[i].Spec.ConflictsWith {
+			for j := range traits {
+				if i == j {
+					continue
+				}
+				if traits[j].GetAPIVersion() == c.APIVersion && traits[j].GetKind() == c.Kind {
+					return &traitConflictError{component: component, a: traitKind(traits[i]), b: traitKind(traits[j])}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func traitKind(t unstructured.Unstructured) string {
+	return t.GetKind() + "." + t.GetAPIVersion()
+}
+
+// sortByInputs sorts the supplied components such that each component follows
+// the components whose outputs it consumes. Components that do not depend on
+// each other keep their relative order.
+func sortByInputs(acc []v1alpha2.ApplicationConfigurationComponent) ([]v1alpha2.ApplicationConfigurationComponent, error) {
+	producer := make(map[string]int)
+	for i, c := range acc {
+		for _, o := range c.Outputs {
+			if _, ok := producer[o.Name]; ok {
+				return nil, errors.Errorf(errFmtDuplicateOutput, o.Name)
+			}
+			producer[o.Name] = i
+		}
+	}
+
+	// pending[i] is the number of components whose outputs component i
+	// consumes and that have not yet been sorted.
+	pending := make([]int, len(acc))
+	consumers := make([][]int, len(acc))
+	for i, c := range acc {
+		deps := make(map[int]bool)
+		for _, in := range c.Inputs {
+			p, ok := producer[in.From]
+			if !ok {
+				return nil, errors.Errorf(errFmtUnknownOutput, componentName(c), in.From)
+			}
+			if deps[p] {
+				continue
+			}
+			deps[p] = true
+			pending[i]++
+			consumers[p] = append(consumers[p], i)
+		}
+	}
+
+	sorted := make([]v1alpha2.ApplicationConfigurationComponent, 0, len(acc))
+	done := make([]bool, len(acc))
+	for len(sorted) < len(acc) {
+		progressed := false
+		for i := range acc {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			done[i], progressed = true, true
+			sorted = append(sorted, acc[i])
+			for _, c := range consumers[i] {
+				pending[c]--
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	if len(sorted) < len(acc) {
+		cycle := make([]string, 0, len(acc)-len(sorted))
+		for i := range acc {
+			if !done[i] {
+				cycle = append(cycle, componentName(acc[i]))
+			}
+		}
+		return nil, &cyclicDependencyError{components: cycle}
+	}
+
+	return sorted, nil
+}
+
+func componentName(acc v1alpha2.ApplicationConfigurationComponent) string {
+	if acc.RevisionName != "" {
+		return ExtractComponentName(acc.RevisionName)
+	}
+	return acc.ComponentName
+}
+
+// resolveInputs returns the parameter values supplied by the supplied inputs.
+// It returns false if any input refers to an output that has not yet been
+// resolved.
+func resolveInputs(ins []v1alpha2.ComponentInput, outputs map[string]intstr.IntOrString) ([]v1alpha2.ComponentParameterValue, bool) {
+	pv := make([]v1alpha2.ComponentParameterValue, 0, len(ins))
+	for _, in := range ins {
+		v, ok := outputs[in.From]
+		if !ok {
+			return nil, false
+		}
+		pv = append(pv, v1alpha2.ComponentParameterValue{Name: in.ParameterKey, Value: v})
+	}
+	return pv, true
+}
+
+// resolveOutputs reads the outputs of the supplied component from the applied
+// instance of its workload. Outputs whose workload has not yet been applied or
+// whose field is not yet set are left unresolved.
+func (r *components) resolveOutputs(ctx context.Context, acc v1alpha2.ApplicationConfigurationComponent, w *unstructured.Unstructured, outputs map[string]intstr.IntOrString) error {
+	if len(acc.Outputs) == 0 {
+		return nil
+	}
+
+	applied := &unstructured.Unstructured{}
+	applied.SetAPIVersion(w.GetAPIVersion())
+	applied.SetKind(w.GetKind())
+	nn := types.NamespacedName{Namespace: w.GetNamespace(), Name: w.GetName()}
+	if err := r.client.Get(ctx, nn, applied); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, errFmtGetOutput, acc.Outputs[0].Name, componentName(acc))
+	}
+
+	for _, o := range acc.Outputs {
+		v, err := fieldpath.Pave(applied.UnstructuredContent()).GetValue(o.FieldPath)
+		if err != nil {
+			// The field is not yet set.
+			continue
+		}
+		out, err := outputValue(v)
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetOutput, o.Name, componentName(acc))
+		}
+		outputs[o.Name] = out
+	}
+	return nil
+}
+
+// outputValue converts the supplied field value to a parameter value. Values
+// that are neither strings nor whole numbers are supplied as their JSON
+// encoding.
+func outputValue(v interface{}) (intstr.IntOrString, error) {
+	switch t := v.(type) {
+	case string:
+		return intstr.FromString(t), nil
+	case int64:
+		return intstr.FromInt(int(t)), nil
+	case float64:
+		if t == float64(int32(t)) {
+			return intstr.FromInt(int(t)), nil
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return intstr.IntOrString{}, err
+	}
+	return intstr.FromString(string(b)), nil
+}
+
+// componentUID returns the UID of the supplied component, as recorded in the
+// supplied workload statuses. A new UID is generated if none is recorded,
+// i.e. if the component's workload has not yet been applied.
+func componentUID(ws []v1alpha2.WorkloadStatus, componentName string) (string, error) {
+	for _, s := range ws {
+		if s.ComponentName == componentName && s.ComponentUID != "" {
+			return s.ComponentUID, nil
+		}
+	}
+	uid, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+	return uid.String(), nil
+}
@@ -18,24 +18,32 @@ package applicationconfiguration
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes/fake"
 	clientappv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
@@ -50,6 +58,10 @@ func TestRenderComponents(t *testing.T) {
 	traitName := "coolTrait"
 	revisionName := "coolcomponent-aa1111"
 	revisionName2 := "coolcomponent-bb2222"
+	uid := "definitely-a-component-uid"
+	applied := v1alpha2.ApplicationConfigurationStatus{
+		Workloads: []v1alpha2.WorkloadStatus{{ComponentName: componentName, ComponentUID: uid}},
+	}
 
 	ac := &v1alpha2.ApplicationConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -65,6 +77,7 @@ func TestRenderComponents(t *testing.T) {
 				},
 			},
 		},
+		Status: applied,
 	}
 	revAC := &v1alpha2.ApplicationConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -80,6 +93,7 @@ func TestRenderComponents(t *testing.T) {
 				},
 			},
 		},
+		Status: applied,
 	}
 	fakeAppClient := fake.NewSimpleClientset().AppsV1()
 	fakeAppClient.ControllerRevisions(namespace).Create(context.Background(), &v1.ControllerRevision{
@@ -199,11 +213,13 @@ func TestRenderComponents(t *testing.T) {
 				w: []Workload{
 					{
 						ComponentName: componentName,
+						ComponentUID:  uid,
 						Workload: func() *unstructured.Unstructured {
 							w := &unstructured.Unstructured{}
 							w.SetNamespace(namespace)
 							w.SetName(workloadName)
 							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetAnnotations(map[string]string{oam.AnnotationComponentUID: uid})
 							return w
 						}(),
 						Traits: []unstructured.Unstructured{
@@ -244,11 +260,13 @@ func TestRenderComponents(t *testing.T) {
 					{
 						ComponentName:         componentName,
 						ComponentRevisionName: revisionName,
+						ComponentUID:          uid,
 						Workload: func() *unstructured.Unstructured {
 							w := &unstructured.Unstructured{}
 							w.SetNamespace(namespace)
 							w.SetName(componentName)
 							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetAnnotations(map[string]string{oam.AnnotationComponentUID: uid})
 							return w
 						}(),
 						Traits: []unstructured.Unstructured{
@@ -298,11 +316,13 @@ func TestRenderComponents(t *testing.T) {
 					{
 						ComponentName:         componentName,
 						ComponentRevisionName: revisionName2,
+						ComponentUID:          uid,
 						Workload: func() *unstructured.Unstructured {
 							w := &unstructured.Unstructured{}
 							w.SetNamespace(namespace)
 							w.SetName(revisionName2)
 							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetAnnotations(map[string]string{oam.AnnotationComponentUID: uid})
 							return w
 						}(),
 						Traits: []unstructured.Unstructured{
@@ -322,7 +342,7 @@ func TestRenderComponents(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := &components{tc.fields.client, tc.fields.appclient, tc.fields.params, tc.fields.workload, tc.fields.trait}
+			r := &components{client: tc.fields.client, appsClient: tc.fields.appclient, params: tc.fields.params, workload: tc.fields.workload, trait: tc.fields.trait}
 			got, err := r.Render(tc.args.ctx, tc.args.ac)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Render(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -668,7 +688,7 @@ func TestRenderTraitWithoutMetadataName(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := &components{tc.fields.client, nil, tc.fields.params, tc.fields.workload, tc.fields.trait}
+			r := &components{client: tc.fields.client, params: tc.fields.params, workload: tc.fields.workload, trait: tc.fields.trait}
 			got, _ := r.Render(tc.args.ctx, tc.args.ac)
 			if len(got) == 0 || len(got[0].Traits) == 0 || got[0].Traits[0].GetName() != componentName {
 				t.Errorf("\n%s\nr.Render(...): -want error, +got error:\n%s\n", tc.reason, "Trait name is NOT"+
@@ -874,7 +894,7 @@ func TestGetComponent(t *testing.T) {
 			return w, nil
 		}),
 	}
-	r := &components{fields.client, fields.appclient, fields.params, fields.workload, fields.trait}
+	r := &components{client: fields.client, appsClient: fields.appclient, params: fields.params, workload: fields.workload, trait: fields.trait}
 	c, revision, err := r.getComponent(context.Background(), v1alpha2.ApplicationConfigurationComponent{RevisionName: revisionName}, namespace)
 	assert.NoError(t, err)
 	assert.Equal(t, revisionName, revision)
@@ -905,3 +925,616 @@ func TestGetComponent(t *testing.T) {
 		},
 	}, c)
 }
+
+func TestSortByInputs(t *testing.T) {
+	db := v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "db",
+		Outputs:       []v1alpha2.ComponentOutput{{Name: "conn", FieldPath: "status.connectionString"}},
+	}
+	app := v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "app",
+		Inputs:        []v1alpha2.ComponentInput{{ParameterKey: "dbConn", From: "conn"}},
+	}
+	web := v1alpha2.ApplicationConfigurationComponent{ComponentName: "web"}
+
+	cyclicA := v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "a",
+		Outputs:       []v1alpha2.ComponentOutput{{Name: "a", FieldPath: "status.a"}},
+		Inputs:        []v1alpha2.ComponentInput{{ParameterKey: "b", From: "b"}},
+	}
+	cyclicB := v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "b",
+		Outputs:       []v1alpha2.ComponentOutput{{Name: "b", FieldPath: "status.b"}},
+		Inputs:        []v1alpha2.ComponentInput{{ParameterKey: "a", From: "a"}},
+	}
+
+	type want struct {
+		sorted []v1alpha2.ApplicationConfigurationComponent
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		acc    []v1alpha2.ApplicationConfigurationComponent
+		want   want
+	}{
+		"NoInputs": {
+			reason: "Components without inputs should keep their order",
+			acc:    []v1alpha2.ApplicationConfigurationComponent{web, db},
+			want:   want{sorted: []v1alpha2.ApplicationConfigurationComponent{web, db}},
+		},
+		"ConsumerFollowsProducer": {
+			reason: "A component should be sorted after the component whose output it consumes",
+			acc:    []v1alpha2.ApplicationConfigurationComponent{app, web, db},
+			want:   want{sorted: []v1alpha2.ApplicationConfigurationComponent{web, db, app}},
+		},
+		"UnknownOutput": {
+			reason: "An input referring to an output no component exposes should return an error",
+			acc:    []v1alpha2.ApplicationConfigurationComponent{app},
+			want:   want{err: errors.Errorf(errFmtUnknownOutput, "app", "conn")},
+		},
+		"DuplicateOutput": {
+			reason: "An output exposed by more than one component should return an error",
+			acc:    []v1alpha2.ApplicationConfigurationComponent{db, db},
+			want:   want{err: errors.Errorf(errFmtDuplicateOutput, "conn")},
+		},
+		"Cycle": {
+			reason: "Components whose inputs and outputs form a cycle should return a cyclic dependency error",
+			acc:    []v1alpha2.ApplicationConfigurationComponent{web, cyclicA, cyclicB},
+			want:   want{err: &cyclicDependencyError{components: []string{"a", "b"}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := sortByInputs(tc.acc)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nsortByInputs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.sorted, got); diff != "" {
+				t.Errorf("\n%s\nsortByInputs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	if !IsCyclicDependency(errors.Wrap(&cyclicDependencyError{}, "wrapped")) {
+		t.Errorf("IsCyclicDependency(...): want true for a wrapped cyclic dependency error")
+	}
+}
+
+func TestResolveOutputs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	acc := v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "db",
+		Outputs: []v1alpha2.ComponentOutput{
+			{Name: "conn", FieldPath: "status.connectionString"},
+			{Name: "port", FieldPath: "status.port"},
+			{Name: "unset", FieldPath: "status.unset"},
+		},
+	}
+
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion("v")
+	w.SetKind("Database")
+	w.SetNamespace("ns")
+	w.SetName("db")
+
+	applied := func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		u.Object["status"] = map[string]interface{}{
+			"connectionString": "db:5432",
+			"port":             int64(5432),
+		}
+		return nil
+	}
+
+	type want struct {
+		outputs map[string]intstr.IntOrString
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"NotApplied": {
+			reason: "Outputs of a workload that has not yet been applied should be left unresolved",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			want:   want{outputs: map[string]intstr.IntOrString{}},
+		},
+		"GetError": {
+			reason: "Errors getting the applied workload should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				outputs: map[string]intstr.IntOrString{},
+				err:     errors.Wrapf(errBoom, errFmtGetOutput, "conn", "db"),
+			},
+		},
+		"Resolved": {
+			reason: "Outputs whose fields are set should be resolved",
+			client: &test.MockClient{MockGet: applied},
+			want: want{outputs: map[string]intstr.IntOrString{
+				"conn": intstr.FromString("db:5432"),
+				"port": intstr.FromInt(5432),
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &components{client: tc.client}
+			outputs := make(map[string]intstr.IntOrString)
+			err := r.resolveOutputs(context.Background(), acc, w, outputs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.resolveOutputs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.outputs, outputs); diff != "" {
+				t.Errorf("\n%s\nr.resolveOutputs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateParameterValues(t *testing.T) {
+	path := field.NewPath("spec", "components").Key("coolcomponent").Child("parameterValues")
+	typed := func(name, t string) v1alpha2.ComponentParameter {
+		return v1alpha2.ComponentParameter{Name: name, Type: &t}
+	}
+	cp := []v1alpha2.ComponentParameter{
+		typed("str", "string"),
+		typed("int", "integer"),
+		typed("num", "number"),
+		typed("bool", "boolean"),
+		{Name: "untyped"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		cpv    []v1alpha2.ComponentParameterValue
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "Values matching their declared types should be valid",
+			cpv: []v1alpha2.ComponentParameterValue{
+				{Name: "str", Value: intstr.FromString("cool")},
+				{Name: "int", Value: intstr.FromInt(3)},
+				{Name: "num", Value: intstr.FromString("0.5")},
+				{Name: "bool", Value: intstr.FromString("false")},
+				{Name: "untyped", Value: intstr.FromInt(3)},
+				{Name: "unknown", Value: intstr.FromString("cool")},
+			},
+		},
+		"Invalid": {
+			reason: "Values not matching their declared types should be reported per field",
+			cpv: []v1alpha2.ComponentParameterValue{
+				{Name: "str", Value: intstr.FromInt(3)},
+				{Name: "int", Value: intstr.FromString("3")},
+				{Name: "num", Value: intstr.FromString("many")},
+				{Name: "bool", Value: intstr.FromString("yes")},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Key("str"), "3", "must be of type string"),
+				field.Invalid(path.Key("int"), "3", "must be of type integer"),
+				field.Invalid(path.Key("num"), "many", "must be of type number"),
+				field.Invalid(path.Key("bool"), "yes", "must be of type boolean"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validateParameterValues(path, cp, tc.cpv)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateParameterValues(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	if !IsParameterValidationFailed(errors.Wrap(&parameterValidationError{}, "wrapped")) {
+		t.Errorf("IsParameterValidationFailed(...): want true for a wrapped parameter validation error")
+	}
+}
+
+func TestOverrideImages(t *testing.T) {
+	workload := func(path string, images ...string) *unstructured.Unstructured {
+		containers := make([]interface{}, 0, len(images))
+		for i, image := range images {
+			containers = append(containers, map[string]interface{}{"name": fmt.Sprintf("c%d", i), "image": image})
+		}
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(path, containers)
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+
+	cases := map[string]struct {
+		reason    string
+		w         *unstructured.Unstructured
+		path      string
+		overrides map[string]string
+		want      *unstructured.Unstructured
+	}{
+		"NoOverrides": {
+			reason: "A workload should be unchanged when no overrides are supplied",
+			w:      workload(podTemplateContainersPath, "nginx"),
+			want:   workload(podTemplateContainersPath, "nginx"),
+		},
+		"PodTemplate": {
+			reason:    "Images of matching pod template containers should be overridden",
+			w:         workload(podTemplateContainersPath, "nginx", "envoy"),
+			overrides: map[string]string{"c1": "mirror.example.org/envoy"},
+			want:      workload(podTemplateContainersPath, "nginx", "mirror.example.org/envoy"),
+		},
+		"OverridePath": {
+			reason:    "Images of matching containers at the override path should be overridden",
+			w:         workload("spec.containers", "nginx"),
+			path:      "spec.containers",
+			overrides: map[string]string{"c0": "mirror.example.org/nginx"},
+			want:      workload("spec.containers", "mirror.example.org/nginx"),
+		},
+		"NoContainers": {
+			reason:    "A workload with no containers at the expected path should be unchanged",
+			w:         workload("spec.containers", "nginx"),
+			overrides: map[string]string{"c0": "mirror.example.org/nginx"},
+			want:      workload("spec.containers", "nginx"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := overrideImages(tc.w, tc.path, tc.overrides); err != nil {
+				t.Fatalf("\n%s\noverrideImages(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.w); diff != "" {
+				t.Errorf("\n%s\noverrideImages(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInjectEnv(t *testing.T) {
+	workload := func(path string, env ...interface{}) *unstructured.Unstructured {
+		c := map[string]interface{}{"name": "c0"}
+		if len(env) > 0 {
+			c["env"] = env
+		}
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(path, []interface{}{c})
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+	envVar := func(name, value string) interface{} {
+		return map[string]interface{}{"name": name, "value": value}
+	}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		env    []v1alpha2.ComponentEnvVar
+		want   want
+	}{
+		"NoEnv": {
+			reason: "A workload should be unchanged when no environment variables are supplied",
+			w:      workload("spec.containers"),
+			want:   want{w: workload("spec.containers"), injected: true},
+		},
+		"PodTemplate": {
+			reason: "Environment variables should be appended to, or replace those of, pod template containers",
+			w:      workload(podTemplateContainersPath, envVar("LOG_LEVEL", "info"), envVar("PORT", "80")),
+			env:    []v1alpha2.ComponentEnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "REGION", Value: "us-west"}},
+			want: want{
+				w:        workload(podTemplateContainersPath, envVar("LOG_LEVEL", "debug"), envVar("PORT", "80"), envVar("REGION", "us-west")),
+				injected: true,
+			},
+		},
+		"NoPodTemplate": {
+			reason: "A workload without a pod template should be unchanged, and reported as such",
+			w:      workload("spec.containers"),
+			env:    []v1alpha2.ComponentEnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			want:   want{w: workload("spec.containers"), injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectEnv(tc.w, tc.env)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectEnv(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectEnv(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInjectBinding(t *testing.T) {
+	workload := func(volumes []interface{}, c map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": []interface{}{c}}
+		if volumes != nil {
+			spec["volumes"] = volumes
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason    string
+		w         *unstructured.Unstructured
+		mountPath string
+		want      want
+	}{
+		"Env": {
+			reason: "The binding secret should be injected as environment variables when no mount path is supplied",
+			w:      workload(nil, map[string]interface{}{"name": "c0"}),
+			want: want{w: workload(nil, map[string]interface{}{
+				"name":    "c0",
+				"envFrom": []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": "binding"}}},
+			})},
+		},
+		"Volume": {
+			reason:    "The binding secret should be mounted at the supplied mount path",
+			w:         workload(nil, map[string]interface{}{"name": "c0"}),
+			mountPath: "/bindings/db",
+			want: want{w: workload(
+				[]interface{}{map[string]interface{}{"name": bindingVolumeName, "secret": map[string]interface{}{"secretName": "binding"}}},
+				map[string]interface{}{
+					"name":         "c0",
+					"volumeMounts": []interface{}{map[string]interface{}{"name": bindingVolumeName, "mountPath": "/bindings/db", "readOnly": true}},
+				},
+			)},
+		},
+		"NoPodTemplate": {
+			reason: "A workload without a pod template cannot be bound",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, err: errors.New(errNoPodTemplate)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := injectBinding(tc.w, "binding", tc.mountPath)
+			if diff := cmp.Diff(tc.want, want{w: tc.w, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectBinding(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckTraitConflicts(t *testing.T) {
+	trait := func(kind string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind(kind)
+		return u
+	}
+	def := func(conflicts ...string) v1alpha2.TraitDefinition {
+		td := v1alpha2.TraitDefinition{}
+		for _, k := range conflicts {
+			td.Spec.ConflictsWith = append(td.Spec.ConflictsWith, v1alpha2.TraitKindReference{APIVersion: "example.org/v1", Kind: k})
+		}
+		return td
+	}
+
+	cases := map[string]struct {
+		reason    string
+		traits    []unstructured.Unstructured
+		traitDefs []v1alpha2.TraitDefinition
+		want      error
+	}{
+		"NoConflicts": {
+			reason:    "Traits that do not declare conflicts should not conflict",
+			traits:    []unstructured.Unstructured{trait("HPA"), trait("Route")},
+			traitDefs: []v1alpha2.TraitDefinition{def(), def()},
+		},
+		"ConflictWithSelf": {
+			reason:    "A trait should not conflict with itself",
+			traits:    []unstructured.Unstructured{trait("HPA")},
+			traitDefs: []v1alpha2.TraitDefinition{def("HPA")},
+		},
+		"Conflict": {
+			reason:    "A trait that declares a conflict with another attached trait should conflict",
+			traits:    []unstructured.Unstructured{trait("HPA"), trait("Route"), trait("KEDA")},
+			traitDefs: []v1alpha2.TraitDefinition{def(), def(), def("HPA")},
+			want:      &traitConflictError{component: "coolcomponent", a: "KEDA.example.org/v1", b: "HPA.example.org/v1"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := checkTraitConflicts("coolcomponent", tc.traits, tc.traitDefs)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckTraitConflicts(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	if !IsTraitConflict(errors.Wrap(&traitConflictError{}, "wrapped")) {
+		t.Errorf("IsTraitConflict(...): want true for a wrapped trait conflict error")
+	}
+}
+
+func TestOverrideReplicas(t *testing.T) {
+	workload := func(replicas ...float64) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if len(replicas) > 0 {
+			_ = fieldpath.Pave(w.Object).SetNumber("spec.replicas", replicas[0])
+		}
+		return w
+	}
+
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		path   string
+		p      []Parameter
+		want   want
+	}{
+		"SetReplicas": {
+			reason: "Replicas should be set at the supplied path",
+			w:      workload(1),
+			path:   "spec.replicas",
+			p:      []Parameter{{Name: "image", FieldPaths: []string{"spec.image"}}},
+			want:   want{w: workload(3)},
+		},
+		"ParameterConflict": {
+			reason: "Replicas should not be set if a parameter also sets the supplied path",
+			w:      workload(),
+			path:   "spec.replicas",
+			p:      []Parameter{{Name: "replicas", FieldPaths: []string{"spec.replicas"}}},
+			want: want{
+				w:   workload(),
+				err: errors.Errorf(errFmtReplicasConflict, "replicas", "spec.replicas"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := overrideReplicas(tc.w, tc.path, 3, tc.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\noverrideReplicas(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, tc.w); diff != "" {
+				t.Errorf("\n%s\noverrideReplicas(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResumeReplicas(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		want   *unstructured.Unstructured
+	}{
+		"ComponentReplicas": {
+			reason: "Replicas rendered from the component should be left as is",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}},
+			want:   &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}},
+		},
+		"NoComponentReplicas": {
+			reason: "Replicas should be explicitly cleared if the component sets none",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": nil}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := resumeReplicas(tc.w, "spec.replicas"); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, tc.w); diff != "" {
+				t.Errorf("\n%s\nresumeReplicas(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWasSuspended(t *testing.T) {
+	suspended := v1alpha2.WorkloadStatus{ComponentName: "suspended"}
+	suspended.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSuspended, corev1.ConditionTrue, v1alpha2.ReasonComponentSuspended, ""))
+	resumed := v1alpha2.WorkloadStatus{ComponentName: "resumed"}
+	resumed.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeSuspended, corev1.ConditionFalse, v1alpha2.ReasonComponentResumed, ""))
+	ws := []v1alpha2.WorkloadStatus{suspended, resumed, {ComponentName: "running"}}
+
+	cases := map[string]bool{
+		"suspended": true,
+		"resumed":   false,
+		"running":   false,
+		"unknown":   false,
+	}
+	for component, want := range cases {
+		t.Run(component, func(t *testing.T) {
+			if diff := cmp.Diff(want, wasSuspended(ws, component)); diff != "" {
+				t.Errorf("wasSuspended(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplicaPath(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion("example.org/v1")
+	w.SetKind("CoolWorkload")
+
+	type want struct {
+		path string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the WorkloadDefinition should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errBoom},
+		},
+		"NoWorkloadDefinition": {
+			reason: "Workloads without a WorkloadDefinition should use the default replica path",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			want:   want{path: defaultReplicaPath},
+		},
+		"NoReplicaPath": {
+			reason: "Workloads whose WorkloadDefinition has no replica path should use the default replica path",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			want:   want{path: defaultReplicaPath},
+		},
+		"ReplicaPath": {
+			reason: "Workloads whose WorkloadDefinition has a replica path should use it",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+				o.(*v1alpha2.WorkloadDefinition).Spec.ReplicaPath = "spec.scale.count"
+				return nil
+			})},
+			want: want{path: "spec.scale.count"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &components{client: tc.client}
+			got, err := r.replicaPath(context.Background(), w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.replicaPath(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.path, got); diff != "" {
+				t.Errorf("\n%s\nr.replicaPath(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComponentUID(t *testing.T) {
+	ws := []v1alpha2.WorkloadStatus{
+		{ComponentName: "other", ComponentUID: "other-uid"},
+		{ComponentName: "cool", ComponentUID: "cool-uid"},
+	}
+
+	got, err := componentUID(ws, "cool")
+	if err != nil {
+		t.Fatalf("componentUID(...): %v", err)
+	}
+	if diff := cmp.Diff("cool-uid", got); diff != "" {
+		t.Errorf("componentUID(...): the recorded UID should persist: -want, +got:\n%s", diff)
+	}
+
+	a, err := componentUID(ws, "new")
+	if err != nil {
+		t.Fatalf("componentUID(...): %v", err)
+	}
+	b, _ := componentUID(ws, "new")
+	if _, err := uuid.Parse(a); err != nil {
+		t.Errorf("componentUID(...): a new component should be given a UUID, got %q", a)
+	}
+	if a == b {
+		t.Errorf("componentUID(...): each new UID should be unique, got %q twice", a)
+	}
+}
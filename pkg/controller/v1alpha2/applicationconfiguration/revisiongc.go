@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Revision garbage collection error strings.
+const (
+	errFmtGetRevisionDefinition = "cannot get workload definition of workload %q"
+	errFmtListRevisions         = "cannot list %s revisions of workload %q"
+)
+
+// defaultRevisionHistoryLimit is the number of unused old revisions of a
+// workload that are retained if neither its WorkloadDefinition nor the
+// workload specifies a limit. It matches the default of a Deployment.
+const defaultRevisionHistoryLimit = 10
+
+// revisionHistoryLimitPath is the field path of the revision history limit
+// of workloads like Deployments and StatefulSets.
+const revisionHistoryLimitPath = "spec.revisionHistoryLimit"
+
+// collectRevisions garbage collects the surplus old revisions of the supplied
+// workloads that were applied, per their WorkloadDefinitions. Workloads whose
+// WorkloadDefinition does not specify a revision garbage collector are
+// ignored. It returns the number of revisions that were deleted.
+func collectRevisions(ctx context.Context, definitions client.Reader, target client.Client, w []Workload) (int, error) {
+	deleted := 0
+	for _, wl := range w {
+		if wl.LastAppliedTime == nil {
+			continue
+		}
+		wd, err := util.FetchWorkloadDefinition(ctx, definitions, wl.Workload)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return deleted, errors.Wrapf(err, errFmtGetRevisionDefinition, wl.Workload.GetName())
+		}
+		if wd.Spec.RevisionGarbageCollector == nil {
+			continue
+		}
+		n, err := collectWorkloadRevisions(ctx, target, wl.Workload, *wd.Spec.RevisionGarbageCollector)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// collectWorkloadRevisions deletes the old revisions of the supplied workload
+// that are not in use, beyond the revision history limit.
+func collectWorkloadRevisions(ctx context.Context, c client.Client, w *unstructured.Unstructured, gc v1alpha2.RevisionGarbageCollector) (int, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetAPIVersion(gc.APIVersion)
+	l.SetKind(gc.Kind + "List")
+	if err := c.List(ctx, l, client.InNamespace(w.GetNamespace())); err != nil {
+		return 0, errors.Wrapf(err, errFmtListRevisions, gc.Kind, w.GetName())
+	}
+
+	revisions := make([]unstructured.Unstructured, 0, len(l.Items))
+	for i := range l.Items {
+		if controlledBy(&l.Items[i], w) {
+			revisions = append(revisions, l.Items[i])
+		}
+	}
+	// Newest first.
+	sort.SliceStable(revisions, func(i, j int) bool {
+		ti, tj := revisions[i].GetCreationTimestamp(), revisions[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return revisions[i].GetName() > revisions[j].GetName()
+	})
+
+	limit := revisionHistoryLimit(w, gc)
+	path := strings.TrimPrefix(gc.GarbageCollectPath, ".")
+	kept, deleted := 0, 0
+	// The newest revision is the workload's current revision.
+	for i := 1; i < len(revisions); i++ {
+		r := &revisions[i]
+		if path != "" {
+			if n, _ := replicas(fieldpath.Pave(r.UnstructuredContent()), path); n != 0 {
+				continue
+			}
+		}
+		if kept < limit {
+			kept++
+			continue
+		}
+		if err := c.Delete(ctx, r); resource.IgnoreNotFound(err) != nil {
+			return deleted, errors.Wrapf(err, errFmtDeleteRevision, r.GetName())
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// revisionHistoryLimit returns the number of unused old revisions of the
+// supplied workload to retain.
+func revisionHistoryLimit(w *unstructured.Unstructured, gc v1alpha2.RevisionGarbageCollector) int {
+	if gc.RevisionHistoryLimit != nil {
+		return int(*gc.RevisionHistoryLimit)
+	}
+	if n, ok := replicas(fieldpath.Pave(w.UnstructuredContent()), revisionHistoryLimitPath); ok {
+		return int(n)
+	}
+	return defaultRevisionHistoryLimit
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestCollectWorkloadRevisions(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+	controller := true
+	two, three := int32(2), int32(3)
+
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion("apps/v1")
+	w.SetKind("Deployment")
+	w.SetNamespace("ns")
+	w.SetName("cool")
+	w.SetUID("cool")
+
+	// revision returns a ReplicaSet controlled by the supplied owner, created
+	// the supplied number of minutes ago, with the supplied replicas.
+	revision := func(name, owner string, age int, replicas int64) unstructured.Unstructured {
+		r := unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"replicas": replicas}}}
+		r.SetAPIVersion("apps/v1")
+		r.SetKind("ReplicaSet")
+		r.SetName(name)
+		r.SetCreationTimestamp(metav1.NewTime(now.Add(-time.Duration(age) * time.Minute)))
+		r.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: owner, UID: types.UID(owner), Controller: &controller}})
+		return r
+	}
+	revisions := []unstructured.Unstructured{
+		revision("old-3", "cool", 30, 0),
+		revision("current", "cool", 0, 0),
+		revision("old-1", "cool", 10, 0),
+		revision("in-use", "cool", 40, 1),
+		revision("old-2", "cool", 20, 0),
+		revision("other", "other", 50, 0),
+		revision("old-4", "cool", 60, 0),
+	}
+	gc := v1alpha2.RevisionGarbageCollector{APIVersion: "apps/v1", Kind: "ReplicaSet", GarbageCollectPath: ".status.replicas", RevisionHistoryLimit: &two}
+
+	type want struct {
+		deleted []string
+		n       int
+		err     error
+	}
+	cases := map[string]struct {
+		reason    string
+		gc        v1alpha2.RevisionGarbageCollector
+		deleteErr error
+		want      want
+	}{
+		"Collected": {
+			reason: "Unused old revisions beyond the history limit should be deleted, oldest first",
+			gc:     gc,
+			want:   want{deleted: []string{"old-3", "old-4"}, n: 2},
+		},
+		"NoPath": {
+			reason: "All old revisions should be considered unused if there is no garbage collect path",
+			gc:     v1alpha2.RevisionGarbageCollector{APIVersion: "apps/v1", Kind: "ReplicaSet", RevisionHistoryLimit: &three},
+			want:   want{deleted: []string{"in-use", "old-4"}, n: 2},
+		},
+		"DefaultLimit": {
+			reason: "Up to ten unused old revisions should be retained by default",
+			gc:     v1alpha2.RevisionGarbageCollector{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+			want:   want{n: 0},
+		},
+		"DeleteError": {
+			reason:    "Errors deleting revisions should be returned",
+			gc:        gc,
+			deleteErr: errBoom,
+			want:      want{deleted: []string{"old-3"}, err: errors.Wrapf(errBoom, errFmtDeleteRevision, "old-3")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := make([]string, 0)
+			c := &test.MockClient{
+				MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					obj.(*unstructured.UnstructuredList).Items = append([]unstructured.Unstructured{}, revisions...)
+					return nil
+				},
+				MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+					deleted = append(deleted, obj.(*unstructured.Unstructured).GetName())
+					return tc.deleteErr
+				},
+			}
+			n, err := collectWorkloadRevisions(context.Background(), c, w, tc.gc)
+			if tc.want.deleted == nil {
+				tc.want.deleted = []string{}
+			}
+			if diff := cmp.Diff(tc.want, want{deleted: deleted, n: n, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncollectWorkloadRevisions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCollectRevisions(t *testing.T) {
+	errBoom := errors.New("boom")
+	applied := metav1.Now()
+	workload := func(name string, appliedAt *metav1.Time) Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetName(name)
+		return Workload{Workload: w, LastAppliedTime: appliedAt}
+	}
+
+	type want struct {
+		n   int
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		w      []Workload
+		want   want
+	}{
+		"NotApplied": {
+			reason: "Workloads that were not applied should be ignored",
+			get:    test.NewMockGetFn(errBoom),
+			w:      []Workload{workload("cool", nil)},
+		},
+		"NoDefinition": {
+			reason: "Workloads without a WorkloadDefinition should be ignored",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "deployments.apps")),
+			w:      []Workload{workload("cool", &applied)},
+		},
+		"NoGarbageCollector": {
+			reason: "Workloads whose WorkloadDefinition does not specify a revision garbage collector should be ignored",
+			get:    test.NewMockGetFn(nil),
+			w:      []Workload{workload("cool", &applied)},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting WorkloadDefinitions should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			w:      []Workload{workload("cool", &applied)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetRevisionDefinition, "cool")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			target := &test.MockClient{MockList: func(context.Context, runtime.Object, ...client.ListOption) error {
+				return fmt.Errorf("unexpected list")
+			}}
+			n, err := collectRevisions(context.Background(), &test.MockClient{MockGet: tc.get}, target, tc.w)
+			if diff := cmp.Diff(tc.want, want{n: n, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncollectRevisions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
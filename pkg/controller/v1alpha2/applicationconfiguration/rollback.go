@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Rollback error strings.
+const (
+	errFmtRecordRollback = "cannot record rollback of component %q"
+)
+
+// defaultRollbackCooldown is the minimum time between rollbacks unless an
+// ApplicationConfiguration specifies otherwise.
+const defaultRollbackCooldown = 10 * time.Minute
+
+// rollbackCooldown returns the minimum time between rollbacks of the supplied
+// ApplicationConfiguration.
+func rollbackCooldown(ac *v1alpha2.ApplicationConfiguration) time.Duration {
+	if ac.Spec.Rollback == nil || ac.Spec.Rollback.CooldownPeriod == nil {
+		return defaultRollbackCooldown
+	}
+	return ac.Spec.Rollback.CooldownPeriod.Duration
+}
+
+// aggregateHealth returns the aggregate health of the supplied workload
+// statuses; Unhealthy if any are unhealthy or their rollout is degraded,
+// Progressing if any are still being rolled out or have fewer updated than
+// desired replicas, Healthy if any are healthy, and empty if none were probed.
+func aggregateHealth(ws []v1alpha2.WorkloadStatus) v1alpha2.HealthStatus {
+	var h v1alpha2.HealthStatus
+	progressing := false
+	for _, s := range ws {
+		if r := s.Rollout; r != nil {
+			if r.Phase == v1alpha2.RolloutPhaseDegraded {
+				return v1alpha2.HealthStatusUnhealthy
+			}
+			if r.Phase == v1alpha2.RolloutPhaseProgressing || r.UpdatedReplicas < r.DesiredReplicas {
+				progressing = true
+			}
+		}
+		if s.Health == nil {
+			continue
+		}
+		if s.Health.Status == v1alpha2.HealthStatusUnhealthy {
+			return v1alpha2.HealthStatusUnhealthy
+		}
+		h = v1alpha2.HealthStatusHealthy
+	}
+	if progressing {
+		return v1alpha2.HealthStatusProgressing
+	}
+	return h
+}
+
+// rollbackRevision returns the revision of the named component that should be
+// rendered instead of the supplied revision, or an empty string if the
+// component has not been rolled back from it. Rollbacks of the component from
+// other revisions are removed from the supplied ApplicationConfiguration's
+// status, given that the component has since been revised.
+func rollbackRevision(ac *v1alpha2.ApplicationConfiguration, component, revision string) string {
+	to := ""
+	kept := ac.Status.Rollbacks[:0]
+	for _, rb := range ac.Status.Rollbacks {
+		if rb.ComponentName != component {
+			kept = append(kept, rb)
+			continue
+		}
+		if rb.FromRevision == revision {
+			to = rb.ToRevision
+			kept = append(kept, rb)
+		}
+	}
+	ac.Status.Rollbacks = kept
+	if len(kept) == 0 {
+		ac.Status.Rollbacks = nil
+	}
+	return to
+}
+
+// rollbackRequested returns true if the rollback of the supplied
+// ApplicationConfiguration was requested since it was last rolled back.
+func rollbackRequested(ac *v1alpha2.ApplicationConfiguration) bool {
+	v, ok := ac.GetAnnotations()[oam.AnnotationRollbackRequested]
+	if !ok {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return false
+	}
+	return ac.Status.LastRollbackTime == nil || ac.Status.LastRollbackTime.Time.Before(at)
+}
+
+// rollback records the aggregate health of the supplied
+// ApplicationConfiguration, and the revisions of its healthy workloads. If
+// rollback is enabled and the ApplicationConfiguration just became unhealthy
+// it rolls each unhealthy workload back to the last revision of its component
+// under which it was healthy, unless a rollback happened within the cooldown
+// period. If a rollback was requested, e.g. by a rollout group, every
+// workload is rolled back regardless of its health and the cooldown period;
+// healthy workloads to the revision under which they were previously healthy.
+// Each rollback is recorded as an ApplicationConfigurationEvent. It returns
+// true if any component was rolled back.
+func (r *Reconciler) rollback(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (bool, error) {
+	previous := ac.Status.Health
+	ac.Status.Health = aggregateHealth(ac.Status.Workloads)
+	for _, ws := range ac.Status.Workloads {
+		if ws.Health == nil || ws.Health.Status != v1alpha2.HealthStatusHealthy || ws.ComponentRevisionName == "" {
+			continue
+		}
+		last := ac.Status.LastHealthyRevisions[ws.ComponentName]
+		if last == ws.ComponentRevisionName {
+			continue
+		}
+		if last != "" {
+			if ac.Status.PreviousHealthyRevisions == nil {
+				ac.Status.PreviousHealthyRevisions = make(map[string]string)
+			}
+			ac.Status.PreviousHealthyRevisions[ws.ComponentName] = last
+		}
+		if ac.Status.LastHealthyRevisions == nil {
+			ac.Status.LastHealthyRevisions = make(map[string]string)
+		}
+		ac.Status.LastHealthyRevisions[ws.ComponentName] = ws.ComponentRevisionName
+	}
+
+	requested := rollbackRequested(ac)
+	if !requested {
+		if ac.Spec.Rollback == nil || !ac.Spec.Rollback.Enabled {
+			return false, nil
+		}
+		// Workloads are usually still being rolled out when they become
+		// unhealthy.
+		wasHealthy := previous == v1alpha2.HealthStatusHealthy || previous == v1alpha2.HealthStatusProgressing
+		if !wasHealthy || ac.Status.Health != v1alpha2.HealthStatusUnhealthy {
+			return false, nil
+		}
+		if t := ac.Status.LastRollbackTime; t != nil && time.Since(t.Time) < rollbackCooldown(ac) {
+			return false, nil
+		}
+	}
+
+	now := metav1.Now()
+	if requested {
+		// A requested rollback happens once, even if no component had a
+		// healthy revision to roll back to.
+		ac.Status.LastRollbackTime = &now
+	}
+	rolledBack := false
+	for _, ws := range ac.Status.Workloads {
+		if !requested && (ws.Health == nil || ws.Health.Status != v1alpha2.HealthStatusUnhealthy) {
+			continue
+		}
+		to := ac.Status.LastHealthyRevisions[ws.ComponentName]
+		if requested && to == ws.ComponentRevisionName {
+			// Healthy workloads are rolled back to the revision under which
+			// they were healthy before.
+			to = ac.Status.PreviousHealthyRevisions[ws.ComponentName]
+		}
+		if to == "" || to == ws.ComponentRevisionName {
+			continue
+		}
+		e := &v1alpha2.ApplicationConfigurationEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ac.GetNamespace(),
+				GenerateName:    ac.GetName() + "-",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+			},
+			Spec: v1alpha2.ApplicationConfigurationEventSpec{
+				ApplicationConfigurationName: ac.GetName(),
+				ComponentName:                ws.ComponentName,
+				Reason:                       v1alpha2.EventReasonRollback,
+				FromRevision:                 ws.ComponentRevisionName,
+				ToRevision:                   to,
+				Timestamp:                    now,
+			},
+		}
+		if err := r.client.Create(ctx, e); err != nil {
+			return rolledBack, errors.Wrapf(err, errFmtRecordRollback, ws.ComponentName)
+		}
+		ac.Status.Rollbacks = append(ac.Status.Rollbacks, v1alpha2.ComponentRollback{
+			ComponentName: ws.ComponentName,
+			FromRevision:  ws.ComponentRevisionName,
+			ToRevision:    to,
+		})
+		ac.Status.LastRollbackTime = &now
+		rolledBack = true
+	}
+	return rolledBack, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestAggregateHealth(t *testing.T) {
+	healthy := &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy}
+	unhealthy := &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusUnhealthy}
+	updating := &v1alpha2.WorkloadRollout{DesiredReplicas: 3, UpdatedReplicas: 1}
+
+	cases := map[string]struct {
+		reason string
+		ws     []v1alpha2.WorkloadStatus
+		want   v1alpha2.HealthStatus
+	}{
+		"NotProbed": {
+			reason: "The aggregate health should be empty when no workload was probed",
+			ws:     []v1alpha2.WorkloadStatus{{}},
+		},
+		"Healthy": {
+			reason: "The aggregate health should be Healthy when all probed workloads are healthy",
+			ws:     []v1alpha2.WorkloadStatus{{Health: healthy}, {}},
+			want:   v1alpha2.HealthStatusHealthy,
+		},
+		"Progressing": {
+			reason: "The aggregate health should be Progressing when a workload has fewer updated than desired replicas",
+			ws:     []v1alpha2.WorkloadStatus{{Health: healthy}, {Rollout: updating}},
+			want:   v1alpha2.HealthStatusProgressing,
+		},
+		"Degraded": {
+			reason: "The aggregate health should be Unhealthy when any workload's rollout is degraded",
+			ws:     []v1alpha2.WorkloadStatus{{Health: healthy}, {Rollout: &v1alpha2.WorkloadRollout{Phase: v1alpha2.RolloutPhaseDegraded}}},
+			want:   v1alpha2.HealthStatusUnhealthy,
+		},
+		"Unhealthy": {
+			reason: "The aggregate health should be Unhealthy when any probed workload is unhealthy",
+			ws:     []v1alpha2.WorkloadStatus{{Health: unhealthy}, {Rollout: updating}},
+			want:   v1alpha2.HealthStatusUnhealthy,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := aggregateHealth(tc.ws); got != tc.want {
+				t.Errorf("\n%s\naggregateHealth(...): want %q, got %q", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRollbackRevision(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{
+		Rollbacks: []v1alpha2.ComponentRollback{
+			{ComponentName: "web", FromRevision: "web-v3", ToRevision: "web-v2"},
+			{ComponentName: "db", FromRevision: "db-v2", ToRevision: "db-v1"},
+		},
+	}}
+
+	if got := rollbackRevision(ac, "web", "web-v3"); got != "web-v2" {
+		t.Errorf("rollbackRevision(...): want web-v2, got %q", got)
+	}
+	if got := rollbackRevision(ac, "db", "db-v3"); got != "" {
+		t.Errorf("rollbackRevision(...): want no revision for a revised component, got %q", got)
+	}
+	want := []v1alpha2.ComponentRollback{{ComponentName: "web", FromRevision: "web-v3", ToRevision: "web-v2"}}
+	if diff := cmp.Diff(want, ac.Status.Rollbacks); diff != "" {
+		t.Errorf("rollbackRevision(...): -want rollbacks, +got rollbacks:\n%s", diff)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+	recent := metav1.NewTime(time.Now().Add(-1 * time.Minute))
+
+	workload := func(component, revision string, h v1alpha2.HealthStatus) v1alpha2.WorkloadStatus {
+		return v1alpha2.WorkloadStatus{
+			ComponentName:         component,
+			ComponentRevisionName: revision,
+			Health:                &v1alpha2.WorkloadHealth{Status: h},
+		}
+	}
+	ac := func(enabled bool, last *metav1.Time, ws ...v1alpha2.WorkloadStatus) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app"},
+			Spec:       v1alpha2.ApplicationConfigurationSpec{Rollback: &v1alpha2.RollbackPolicy{Enabled: enabled}},
+			Status: v1alpha2.ApplicationConfigurationStatus{
+				Health:               v1alpha2.HealthStatusHealthy,
+				LastHealthyRevisions: map[string]string{"web": "web-v1", "db": "db-v1"},
+				LastRollbackTime:     last,
+				Workloads:            ws,
+			},
+		}
+	}
+
+	type want struct {
+		rolledBack bool
+		err        error
+		health     v1alpha2.HealthStatus
+		healthy    map[string]string
+		previous   map[string]string
+		rollbacks  []v1alpha2.ComponentRollback
+		events     []v1alpha2.ApplicationConfigurationEventSpec
+	}
+	cases := map[string]struct {
+		reason    string
+		ac        *v1alpha2.ApplicationConfiguration
+		createErr error
+		want      want
+	}{
+		"StillHealthy": {
+			reason: "The revisions of healthy workloads should be recorded, and nothing rolled back",
+			ac:     ac(true, nil, workload("web", "web-v2", v1alpha2.HealthStatusHealthy)),
+			want: want{
+				health:   v1alpha2.HealthStatusHealthy,
+				healthy:  map[string]string{"web": "web-v2", "db": "db-v1"},
+				previous: map[string]string{"web": "web-v1"},
+			},
+		},
+		"Disabled": {
+			reason: "Nothing should be rolled back when rollback is disabled",
+			ac:     ac(false, nil, workload("web", "web-v2", v1alpha2.HealthStatusUnhealthy)),
+			want: want{
+				health:  v1alpha2.HealthStatusUnhealthy,
+				healthy: map[string]string{"web": "web-v1", "db": "db-v1"},
+			},
+		},
+		"CoolingDown": {
+			reason: "Nothing should be rolled back within the cooldown period of the last rollback",
+			ac:     ac(true, &recent, workload("web", "web-v2", v1alpha2.HealthStatusUnhealthy)),
+			want: want{
+				health:  v1alpha2.HealthStatusUnhealthy,
+				healthy: map[string]string{"web": "web-v1", "db": "db-v1"},
+			},
+		},
+		"RolledBack": {
+			reason: "Unhealthy workloads should be rolled back to their last healthy revision, and the rollback recorded",
+			ac: ac(true, nil,
+				workload("web", "web-v2", v1alpha2.HealthStatusUnhealthy),
+				workload("db", "db-v1", v1alpha2.HealthStatusUnhealthy),
+			),
+			want: want{
+				rolledBack: true,
+				health:     v1alpha2.HealthStatusUnhealthy,
+				healthy:    map[string]string{"web": "web-v1", "db": "db-v1"},
+				rollbacks:  []v1alpha2.ComponentRollback{{ComponentName: "web", FromRevision: "web-v2", ToRevision: "web-v1"}},
+				events: []v1alpha2.ApplicationConfigurationEventSpec{{
+					ApplicationConfigurationName: "app",
+					ComponentName:                "web",
+					Reason:                       v1alpha2.EventReasonRollback,
+					FromRevision:                 "web-v2",
+					ToRevision:                   "web-v1",
+				}},
+			},
+		},
+		"Requested": {
+			reason: "Every workload should be rolled back when a rollback was requested since the last one, regardless of health and cooldown",
+			ac: func() *v1alpha2.ApplicationConfiguration {
+				ac := ac(false, &recent,
+					workload("web", "web-v2", v1alpha2.HealthStatusHealthy),
+					workload("db", "db-v2", v1alpha2.HealthStatusUnhealthy),
+				)
+				ac.SetAnnotations(map[string]string{oam.AnnotationRollbackRequested: time.Now().Format(time.RFC3339)})
+				return ac
+			}(),
+			want: want{
+				rolledBack: true,
+				health:     v1alpha2.HealthStatusUnhealthy,
+				healthy:    map[string]string{"web": "web-v2", "db": "db-v1"},
+				previous:   map[string]string{"web": "web-v1"},
+				rollbacks: []v1alpha2.ComponentRollback{
+					{ComponentName: "web", FromRevision: "web-v2", ToRevision: "web-v1"},
+					{ComponentName: "db", FromRevision: "db-v2", ToRevision: "db-v1"},
+				},
+				events: []v1alpha2.ApplicationConfigurationEventSpec{
+					{ApplicationConfigurationName: "app", ComponentName: "web", Reason: v1alpha2.EventReasonRollback, FromRevision: "web-v2", ToRevision: "web-v1"},
+					{ApplicationConfigurationName: "app", ComponentName: "db", Reason: v1alpha2.EventReasonRollback, FromRevision: "db-v2", ToRevision: "db-v1"},
+				},
+			},
+		},
+		"CreateEventError": {
+			reason:    "Errors recording a rollback should be returned",
+			ac:        ac(true, nil, workload("web", "web-v2", v1alpha2.HealthStatusUnhealthy)),
+			createErr: errBoom,
+			want: want{
+				err:     errors.Wrapf(errBoom, errFmtRecordRollback, "web"),
+				health:  v1alpha2.HealthStatusUnhealthy,
+				healthy: map[string]string{"web": "web-v1", "db": "db-v1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var events []v1alpha2.ApplicationConfigurationEventSpec
+			r := &Reconciler{client: &test.MockClient{
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					if tc.createErr != nil {
+						return tc.createErr
+					}
+					e := obj.(*v1alpha2.ApplicationConfigurationEvent)
+					e.Spec.Timestamp = metav1.Time{}
+					events = append(events, e.Spec)
+					return nil
+				},
+			}}
+			rolledBack, err := r.rollback(context.Background(), tc.ac)
+			got := want{
+				rolledBack: rolledBack,
+				err:        err,
+				health:     tc.ac.Status.Health,
+				healthy:    tc.ac.Status.LastHealthyRevisions,
+				previous:   tc.ac.Status.PreviousHealthyRevisions,
+				rollbacks:  tc.ac.Status.Rollbacks,
+				events:     events,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nr.rollback(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Component group error strings.
+const (
+	errFmtComponentInGroups     = "component %q is in more than one group: %q and %q"
+	errFmtUnknownGroupComponent = "group %q refers to unknown component %q"
+)
+
+// defaultMaxConcurrentGroups is the number of component groups that may be
+// rolled out at once unless otherwise configured.
+const defaultMaxConcurrentGroups = 1
+
+// rollout splits the supplied workloads into those that may be applied, and
+// those that are held back because the component group they are in is waiting
+// for an earlier group to become healthy. Components that are not in a group
+// are always released.
+func (r *Reconciler) rollout(ac *v1alpha2.ApplicationConfiguration, w []Workload) ([]Workload, []Workload, error) {
+	if len(ac.Spec.ComponentGroups) == 0 {
+		return w, nil, nil
+	}
+
+	known := make(map[string]bool, len(ac.Spec.Components))
+	for _, acc := range ac.Spec.Components {
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = ExtractComponentName(acc.RevisionName)
+		}
+		known[name] = true
+	}
+
+	groupOf := make(map[string]string)
+	for _, g := range ac.Spec.ComponentGroups {
+		for _, c := range g.Components {
+			if !known[c] {
+				return nil, nil, errors.Errorf(errFmtUnknownGroupComponent, g.Name, c)
+			}
+			if other, ok := groupOf[c]; ok {
+				return nil, nil, errors.Errorf(errFmtComponentInGroups, c, other, g.Name)
+			}
+			groupOf[c] = g.Name
+		}
+	}
+
+	rendered := make(map[string]Workload, len(w))
+	for _, wl := range w {
+		rendered[wl.ComponentName] = wl
+	}
+
+	max := r.maxConcurrentGroups
+	if max < 1 {
+		max = defaultMaxConcurrentGroups
+	}
+
+	release := make(map[string]bool)
+	progressing := 0
+	for _, g := range ac.Spec.ComponentGroups {
+		if progressing >= max {
+			break
+		}
+		healthy := true
+		for _, c := range g.Components {
+			wl, ok := rendered[c]
+			if ok {
+				release[c] = true
+			}
+			if ok && isHealthy(ac.Status.Workloads, wl) {
+				continue
+			}
+			healthy = false
+			if g.RolloutStrategy == v1alpha2.GroupRolloutOneByOne {
+				break
+			}
+		}
+		if !healthy {
+			progressing++
+		}
+	}
+
+	released := make([]Workload, 0, len(w))
+	held := make([]Workload, 0)
+	for _, wl := range w {
+		if _, grouped := groupOf[wl.ComponentName]; grouped && !release[wl.ComponentName] {
+			held = append(held, wl)
+			continue
+		}
+		released = append(released, wl)
+	}
+	return released, held, nil
+}
+
+// isHealthy returns true if the current revision of the supplied workload has
+// been applied, and its health probe (if any) last succeeded.
+func isHealthy(ws []v1alpha2.WorkloadStatus, w Workload) bool {
+	for _, s := range ws {
+		if s.ComponentName != w.ComponentName || s.Reference.Name != w.Workload.GetName() {
+			continue
+		}
+		if s.ComponentRevisionName != w.ComponentRevisionName {
+			return false
+		}
+		return s.Health == nil || s.Health.Status == v1alpha2.HealthStatusHealthy
+	}
+	return false
+}
+
+// heldStatuses returns the supplied workload statuses split into those of
+// workloads that were released, and those of workloads that are held back.
+func heldStatuses(ws []v1alpha2.WorkloadStatus, held []Workload) ([]v1alpha2.WorkloadStatus, []v1alpha2.WorkloadStatus) {
+	if len(held) == 0 {
+		return ws, nil
+	}
+	isHeld := make(map[string]bool, len(held))
+	for _, wl := range held {
+		isHeld[wl.ComponentName] = true
+	}
+	released := make([]v1alpha2.WorkloadStatus, 0, len(ws))
+	kept := make([]v1alpha2.WorkloadStatus, 0)
+	for _, s := range ws {
+		if isHeld[s.ComponentName] {
+			kept = append(kept, s)
+			continue
+		}
+		released = append(released, s)
+	}
+	return released, kept
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRollout(t *testing.T) {
+	workload := func(name string) Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("v")
+		w.SetKind("workload")
+		w.SetName(name)
+		return Workload{ComponentName: name, ComponentRevisionName: name + "-v1", Workload: w}
+	}
+	a, b, c, d := workload("a"), workload("b"), workload("c"), workload("d")
+	all := []Workload{a, b, c, d}
+
+	healthy := func(w Workload) v1alpha2.WorkloadStatus {
+		return v1alpha2.WorkloadStatus{
+			ComponentName:         w.ComponentName,
+			ComponentRevisionName: w.ComponentRevisionName,
+			Reference:             runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: w.Workload.GetName()},
+		}
+	}
+	unhealthy := func(w Workload) v1alpha2.WorkloadStatus {
+		s := healthy(w)
+		s.Health = &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusUnhealthy}
+		return s
+	}
+	outdated := func(w Workload) v1alpha2.WorkloadStatus {
+		s := healthy(w)
+		s.ComponentRevisionName = w.ComponentName + "-v0"
+		return s
+	}
+
+	ac := func(groups []v1alpha2.ComponentGroup, ws ...v1alpha2.WorkloadStatus) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{
+					{ComponentName: "a"}, {ComponentName: "b"}, {ComponentName: "c"}, {RevisionName: "d-v1"},
+				},
+				ComponentGroups: groups,
+			},
+			Status: v1alpha2.ApplicationConfigurationStatus{Workloads: ws},
+		}
+	}
+	waves := []v1alpha2.ComponentGroup{
+		{Name: "first", Components: []string{"a", "b"}},
+		{Name: "second", Components: []string{"c"}},
+	}
+
+	type want struct {
+		released []Workload
+		held     []Workload
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		max    int
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"NoGroups": {
+			reason: "All workloads should be released if there are no groups",
+			ac:     ac(nil),
+			want:   want{released: all},
+		},
+		"UnknownComponent": {
+			reason: "Groups may only refer to components of the ApplicationConfiguration",
+			ac:     ac([]v1alpha2.ComponentGroup{{Name: "first", Components: []string{"e"}}}),
+			want:   want{err: errors.Errorf(errFmtUnknownGroupComponent, "first", "e")},
+		},
+		"ComponentInGroups": {
+			reason: "A component may be in at most one group",
+			ac:     ac([]v1alpha2.ComponentGroup{{Name: "first", Components: []string{"a"}}, {Name: "second", Components: []string{"a"}}}),
+			want:   want{err: errors.Errorf(errFmtComponentInGroups, "a", "first", "second")},
+		},
+		"FirstWave": {
+			reason: "Later groups should be held until the first group is healthy",
+			ac:     ac(waves),
+			want:   want{released: []Workload{a, b, d}, held: []Workload{c}},
+		},
+		"FirstWaveUnhealthy": {
+			reason: "Later groups should be held while any component of the first group is unhealthy",
+			ac:     ac(waves, healthy(a), unhealthy(b)),
+			want:   want{released: []Workload{a, b, d}, held: []Workload{c}},
+		},
+		"FirstWaveOutdated": {
+			reason: "Later groups should be held until the current revision of the first group is applied",
+			ac:     ac(waves, healthy(a), outdated(b)),
+			want:   want{released: []Workload{a, b, d}, held: []Workload{c}},
+		},
+		"SecondWave": {
+			reason: "Later groups should be released once the first group is healthy",
+			ac:     ac(waves, healthy(a), healthy(b)),
+			want:   want{released: all, held: []Workload{}},
+		},
+		"Concurrent": {
+			reason: "Up to the maximum number of concurrent groups should be rolled out at once",
+			max:    2,
+			ac:     ac(waves),
+			want:   want{released: all, held: []Workload{}},
+		},
+		"OneByOne": {
+			reason: "Components of a OneByOne group should be released once the components before them are healthy",
+			ac:     ac([]v1alpha2.ComponentGroup{{Name: "first", Components: []string{"a", "b", "c"}, RolloutStrategy: v1alpha2.GroupRolloutOneByOne}}, healthy(a)),
+			want:   want{released: []Workload{a, b, d}, held: []Workload{c}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{maxConcurrentGroups: tc.max}
+			released, held, err := r.rollout(tc.ac, all)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.rollout(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.released, released); diff != "" {
+				t.Errorf("\n%s\nr.rollout(...): -want released, +got released:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.held, held); diff != "" {
+				t.Errorf("\n%s\nr.rollout(...): -want held, +got held:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHeldStatuses(t *testing.T) {
+	released := v1alpha2.WorkloadStatus{ComponentName: "released"}
+	held := v1alpha2.WorkloadStatus{ComponentName: "held"}
+
+	gotReleased, gotHeld := heldStatuses([]v1alpha2.WorkloadStatus{released, held}, []Workload{{ComponentName: "held"}})
+	if diff := cmp.Diff([]v1alpha2.WorkloadStatus{released}, gotReleased); diff != "" {
+		t.Errorf("heldStatuses(...): -want released, +got released:\n%s", diff)
+	}
+	if diff := cmp.Diff([]v1alpha2.WorkloadStatus{held}, gotHeld); diff != "" {
+		t.Errorf("heldStatuses(...): -want held, +got held:\n%s", diff)
+	}
+}
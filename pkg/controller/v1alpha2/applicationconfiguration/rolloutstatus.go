@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Rollout status error strings.
+const (
+	errFmtGetRolloutWorkload = "cannot get workload %q to observe its rollout"
+)
+
+// Field paths of the replica counts of workloads that are rolled out, e.g.
+// Deployments and StatefulSets.
+const (
+	desiredReplicasPath = "spec.replicas"
+	readyReplicasPath   = "status.readyReplicas"
+	updatedReplicasPath = "status.updatedReplicas"
+)
+
+// observeRollouts records the rollout status of each workload of the supplied
+// ApplicationConfiguration, as determined by the supplied HealthChecker from
+// the live workload.
+func observeRollouts(ctx context.Context, c client.Reader, hc HealthChecker, ac *v1alpha2.ApplicationConfiguration) error {
+	for i := range ac.Status.Workloads {
+		ws := &ac.Status.Workloads[i]
+		w := asUnstructured(ws.Reference, ac.GetNamespace())
+		err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ws.Reference.Name}, w)
+		if kerrors.IsNotFound(err) {
+			ws.Rollout = nil
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetRolloutWorkload, ws.Reference.Name)
+		}
+		ws.Rollout = hc.Check(ctx, c, w)
+	}
+	return nil
+}
+
+// rolloutOf returns the rollout status of the supplied workload, or nil if it
+// has no replicas.
+func rolloutOf(p *fieldpath.Paved) *v1alpha2.WorkloadRollout {
+	desired, ok := replicas(p, desiredReplicasPath)
+	if !ok {
+		return nil
+	}
+	ready, _ := replicas(p, readyReplicasPath)
+	updated, _ := replicas(p, updatedReplicasPath)
+	r := &v1alpha2.WorkloadRollout{
+		DesiredReplicas: desired,
+		ReadyReplicas:   ready,
+		UpdatedReplicas: updated,
+		Phase:           v1alpha2.RolloutPhaseComplete,
+	}
+	if updated < desired || ready < desired {
+		r.Phase = v1alpha2.RolloutPhaseProgressing
+	}
+	return r
+}
+
+// replicas returns the replica count at the supplied path, and whether there
+// is one.
+func replicas(p *fieldpath.Paved, path string) (int32, bool) {
+	v, err := p.GetValue(path)
+	if err != nil {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int64:
+		return int32(n), true
+	case float64:
+		return int32(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestObserveRollouts(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ref := func(name string) runtimev1alpha1.TypedReference {
+		return runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: name}
+	}
+	live := map[string]map[string]interface{}{
+		"updating": {
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(3), "updatedReplicas": int64(1)},
+		},
+		"complete": {
+			"spec":   map[string]interface{}{"replicas": int64(2)},
+			"status": map[string]interface{}{"readyReplicas": int64(2), "updatedReplicas": int64(2)},
+		},
+		"unreplicated": {
+			"spec": map[string]interface{}{"schedule": "@daily"},
+		},
+	}
+
+	type want struct {
+		rollouts []*v1alpha2.WorkloadRollout
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		ws     []v1alpha2.WorkloadStatus
+		want   want
+	}{
+		"Observed": {
+			reason: "The rollout of each live workload with replicas should be recorded",
+			client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+				o, ok := live[key.Name]
+				if !ok {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				obj.(*unstructured.Unstructured).Object = o
+				return nil
+			}},
+			ws: []v1alpha2.WorkloadStatus{
+				{Reference: ref("updating")},
+				{Reference: ref("complete")},
+				{Reference: ref("unreplicated")},
+				{Reference: ref("deleted"), Rollout: &v1alpha2.WorkloadRollout{Phase: v1alpha2.RolloutPhaseComplete}},
+			},
+			want: want{rollouts: []*v1alpha2.WorkloadRollout{
+				{DesiredReplicas: 3, ReadyReplicas: 3, UpdatedReplicas: 1, Phase: v1alpha2.RolloutPhaseProgressing},
+				{DesiredReplicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2, Phase: v1alpha2.RolloutPhaseComplete},
+				nil,
+				nil,
+			}},
+		},
+		"GetError": {
+			reason: "Errors getting a workload should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ws:     []v1alpha2.WorkloadStatus{{Reference: ref("updating")}},
+			want: want{
+				rollouts: []*v1alpha2.WorkloadRollout{nil},
+				err:      errors.Wrapf(errBoom, errFmtGetRolloutWorkload, "updating"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{Workloads: tc.ws}}
+			err := observeRollouts(context.Background(), tc.client, ReplicaHealthChecker{}, ac)
+			got := want{err: err}
+			for _, ws := range ac.Status.Workloads {
+				got.rollouts = append(got.rollouts, ws.Rollout)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nobserveRollouts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	oras "oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Schematic error strings.
+const (
+	errMarshalWorkload       = "cannot marshal workload"
+	errFmtFetchSchematic     = "cannot fetch schematic %q"
+	errFmtNoSchematicKind    = "schematic %q has no %s %s manifest"
+	errFmtInvalidOCIRef      = "invalid OCI reference %q"
+	errFmtFetchOCIManifest   = "cannot fetch OCI manifest of %q"
+	errFmtDecodeManifests    = "cannot decode YAML manifests of %s"
+	errDecodeManifest        = "cannot decode OCI manifest"
+	errFmtGetSchematicLayers = "cannot get layer %s"
+)
+
+// annotationOCIImageTitle is the file name of an OCI layer, as set by oras.
+const annotationOCIImageTitle = ocispec.AnnotationTitle
+
+// registryTimeout bounds each request to an OCI registry.
+const registryTimeout = 30 * time.Second
+
+// An OCISchematicFetcher fetches the YAML manifests of a workload schematic
+// that is stored as an OCI artifact.
+type OCISchematicFetcher interface {
+	// Fetch the manifests of the supplied OCI reference.
+	Fetch(ctx context.Context, ref string) ([]unstructured.Unstructured, error)
+}
+
+// An OCISchematicFetcherFn fetches the YAML manifests of a workload schematic
+// that is stored as an OCI artifact.
+type OCISchematicFetcherFn func(ctx context.Context, ref string) ([]unstructured.Unstructured, error)
+
+// Fetch the manifests of the supplied OCI reference.
+func (fn OCISchematicFetcherFn) Fetch(ctx context.Context, ref string) ([]unstructured.Unstructured, error) {
+	return fn(ctx, ref)
+}
+
+// A RegistrySchematicFetcher fetches workload schematics from OCI registries
+// using oras. Registries are authenticated to using the credentials of the
+// controller's Docker config file, if any, and anonymously otherwise.
+// Schematics are cached by digest, so an artifact's layers are pulled only
+// once.
+type RegistrySchematicFetcher struct {
+	client *auth.Client
+
+	// plainHTTP is used to reach registries when testing.
+	plainHTTP bool
+
+	mu    sync.RWMutex
+	cache map[string][]unstructured.Unstructured
+}
+
+// NewOCISchematicFetcher returns an OCISchematicFetcher that fetches
+// schematics using the supplied HTTP client.
+func NewOCISchematicFetcher(c *http.Client) *RegistrySchematicFetcher {
+	ac := &auth.Client{Client: c, Cache: auth.NewCache()}
+	if s, err := credentials.NewStoreFromDocker(credentials.StoreOptions{}); err == nil {
+		ac.Credential = credentials.Credential(s)
+	}
+	return &RegistrySchematicFetcher{client: ac, cache: make(map[string][]unstructured.Unstructured)}
+}
+
+// Fetch the manifests of the supplied OCI reference. The manifests are
+// returned from the cache if the reference resolves to a digest that was
+// fetched before.
+func (f *RegistrySchematicFetcher) Fetch(ctx context.Context, ref string) ([]unstructured.Unstructured, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtInvalidOCIRef, ref)
+	}
+	repo.Client = f.client
+	repo.PlainHTTP = f.plainHTTP
+
+	desc, body, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchOCIManifest, ref)
+	}
+	digest := desc.Digest.String()
+	if m, ok := f.cached(digest); ok {
+		return m, nil
+	}
+
+	m := &ocispec.Manifest{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, errors.Wrap(err, errDecodeManifest)
+	}
+	manifests := make([]unstructured.Unstructured, 0)
+	for _, l := range m.Layers {
+		// FetchAll verifies the size and digest of the layer.
+		b, err := content.FetchAll(ctx, repo, l)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetSchematicLayers, l.Digest)
+		}
+		u, err := layerManifests(l, b)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeManifests, l.Digest)
+		}
+		manifests = append(manifests, u...)
+	}
+
+	f.mu.Lock()
+	f.cache[digest] = manifests
+	f.mu.Unlock()
+	return copyManifests(manifests), nil
+}
+
+func (f *RegistrySchematicFetcher) cached(digest string) ([]unstructured.Unstructured, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	m, ok := f.cache[digest]
+	if !ok {
+		return nil, false
+	}
+	return copyManifests(m), true
+}
+
+// layerManifests returns the YAML manifests of the supplied layer. Layers
+// titled as YAML files are manifests, tarballs are searched for YAML files,
+// and other layers are manifests if their media type says they are YAML.
+func layerManifests(l ocispec.Descriptor, b []byte) ([]unstructured.Unstructured, error) {
+	// oras pushes files with the tar layer media type unless told otherwise,
+	// so a layer titled as a YAML file is a manifest whatever its media type.
+	if isYAMLFile(l.Annotations[annotationOCIImageTitle]) {
+		return decodeManifests(bytes.NewReader(b))
+	}
+	if strings.Contains(l.MediaType, "tar") {
+		var r io.Reader = bytes.NewReader(b)
+		if strings.Contains(l.MediaType, "gzip") {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			defer gz.Close() // nolint:errcheck
+			r = gz
+		}
+		out := make([]unstructured.Unstructured, 0)
+		tr := tar.NewReader(r)
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				return out, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if h.Typeflag != tar.TypeReg || !isYAMLFile(h.Name) {
+				continue
+			}
+			u, err := decodeManifests(tr)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, u...)
+		}
+	}
+	if strings.Contains(l.MediaType, "yaml") {
+		return decodeManifests(bytes.NewReader(b))
+	}
+	return nil, nil
+}
+
+func isYAMLFile(name string) bool {
+	ext := path.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decodeManifests decodes the YAML (or JSON) documents read from the supplied
+// reader. Empty documents are skipped.
+func decodeManifests(r io.Reader) ([]unstructured.Unstructured, error) {
+	out := make([]unstructured.Unstructured, 0)
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := d.Decode(&obj); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		out = append(out, unstructured.Unstructured{Object: obj})
+	}
+}
+
+// applySchematic returns the supplied raw workload rendered on top of the
+// manifest of its kind in the schematic of its WorkloadDefinition, if any.
+// Workloads whose kind has no WorkloadDefinition, or no schematic, are
+// returned unchanged. The supplied parameters are applied to kustomize
+// schematics as patches.
+func (r *components) applySchematic(ctx context.Context, raw []byte, p []Parameter) ([]byte, error) {
+	if r.schematics == nil && r.kustomize == nil {
+		return raw, nil
+	}
+	w := &unstructured.Unstructured{}
+	if err := w.UnmarshalJSON(raw); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalWorkload)
+	}
+	wd, err := util.FetchWorkloadDefinition(ctx, r.client, w)
+	if kerrors.IsNotFound(err) {
+		return raw, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if wd.Spec.Schematic == nil {
+		return raw, nil
+	}
+
+	var ref string
+	var manifests []unstructured.Unstructured
+	switch s := wd.Spec.Schematic; {
+	case s.OCI != nil && r.schematics != nil:
+		ref = s.OCI.Ref
+		if manifests, err = r.schematics.Fetch(ctx, ref); err != nil {
+			return nil, errors.Wrapf(err, errFmtFetchSchematic, ref)
+		}
+	case s.Kustomize != nil && r.kustomize != nil:
+		ref = s.Kustomize.URL
+		patches, err := kustomizePatches(w, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderKustomize, ref)
+		}
+		if manifests, err = r.kustomize.Render(ctx, *s.Kustomize, patches); err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderKustomize, ref)
+		}
+	default:
+		return raw, nil
+	}
+
+	for _, m := range manifests {
+		if m.GetAPIVersion() != w.GetAPIVersion() || m.GetKind() != w.GetKind() {
+			continue
+		}
+		b, err := json.Marshal(mergeTemplate(m.Object, w.Object))
+		return b, errors.Wrap(err, errMarshalWorkload)
+	}
+	return nil, errors.Errorf(errFmtNoSchematicKind, ref, w.GetAPIVersion(), w.GetKind())
+}
+
+// mergeTemplate returns the supplied workload merged on top of the supplied
+// template. Objects are merged recursively; any other value of the workload
+// replaces that of the template.
+func mergeTemplate(template, workload map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(template))
+	for k, v := range template {
+		out[k] = v
+	}
+	for k, v := range workload {
+		tv, tok := out[k].(map[string]interface{})
+		wv, wok := v.(map[string]interface{})
+		if tok && wok {
+			out[k] = mergeTemplate(tv, wv)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"oras.land/oras-go/v2/errdef"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRegistrySchematicFetcher(t *testing.T) {
+	deployment := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: template\n"
+	service := "apiVersion: v1\nkind: Service\nmetadata:\n  name: template\n---\n"
+
+	tgz := &bytes.Buffer{}
+	gz := gzip.NewWriter(tgz)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Name: "service.yaml", Mode: 0600, Size: int64(len(service)), Typeflag: tar.TypeReg})
+	_, _ = tw.Write([]byte(service))
+	_ = tw.Close()
+	_ = gz.Close()
+
+	blobs := map[string][]byte{
+		digest.FromString(deployment).String(): []byte(deployment),
+		digest.FromBytes(tgz.Bytes()).String(): tgz.Bytes(),
+	}
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{
+			{
+				MediaType:   ocispec.MediaTypeImageLayer,
+				Digest:      digest.FromString(deployment),
+				Size:        int64(len(deployment)),
+				Annotations: map[string]string{ocispec.AnnotationTitle: "deployment.yaml"},
+			},
+			{
+				MediaType: ocispec.MediaTypeImageLayerGzip,
+				Digest:    digest.FromBytes(tgz.Bytes()),
+				Size:      int64(tgz.Len()),
+			},
+		},
+	})
+
+	blobGets := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "secret"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:example/schematics:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/example/schematics/manifests/v1", r.URL.Path == "/v2/example/schematics/manifests/"+digest.FromBytes(manifest).String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(r.URL.Path, "/v2/example/schematics/blobs/"):
+			b, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/example/schematics/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			blobGets++
+			_, _ = w.Write(b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	f := NewOCISchematicFetcher(srv.Client())
+	f.plainHTTP = true
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	want := []unstructured.Unstructured{
+		{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[string]interface{}{"name": "template"}}},
+		{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Service", "metadata": map[string]interface{}{"name": "template"}}},
+	}
+	for _, ref := range []string{host + "/example/schematics:v1", host + "/example/schematics:v1", host + "/example/schematics@" + digest.FromBytes(manifest).String()} {
+		got, err := f.Fetch(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("f.Fetch(%q): %v", ref, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("f.Fetch(%q): -want, +got:\n%s", ref, diff)
+		}
+	}
+	if blobGets != len(blobs) {
+		t.Errorf("f.Fetch(...): want each layer pulled once, got %d pulls of %d layers", blobGets, len(blobs))
+	}
+
+	if _, err := f.Fetch(context.Background(), host+"/example/missing:v1"); !errors.Is(err, errdef.ErrNotFound) {
+		t.Errorf("f.Fetch(...): want not found error, got %v", err)
+	}
+	if _, err := f.Fetch(context.Background(), "schematics"); err == nil {
+		t.Errorf("f.Fetch(...): want error fetching a reference without a registry, got nil")
+	}
+}
+
+func TestApplySchematic(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := "ghcr.io/example/schematics:v1"
+	raw := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"},"spec":{"replicas":3}}`)
+
+	withSchematic := func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+		obj.(*v1alpha2.WorkloadDefinition).Spec.Schematic = &v1alpha2.Schematic{OCI: &v1alpha2.OCISchematic{Ref: ref}}
+		return nil
+	}
+	template := []unstructured.Unstructured{
+		{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Service"}},
+		{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "template", "labels": map[string]interface{}{"app": "template"}},
+			"spec":       map[string]interface{}{"replicas": int64(1), "strategy": map[string]interface{}{"type": "Recreate"}},
+		}},
+	}
+
+	type want struct {
+		raw string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		get     test.MockGetFn
+		fetcher OCISchematicFetcher
+		want    want
+	}{
+		"NoWorkloadDefinition": {
+			reason:  "Workloads of kinds without a WorkloadDefinition should be returned unchanged",
+			get:     test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			fetcher: OCISchematicFetcherFn(func(_ context.Context, _ string) ([]unstructured.Unstructured, error) { return nil, errBoom }),
+			want:    want{raw: string(raw)},
+		},
+		"NoSchematic": {
+			reason:  "Workloads of kinds without a schematic should be returned unchanged",
+			get:     test.NewMockGetFn(nil),
+			fetcher: OCISchematicFetcherFn(func(_ context.Context, _ string) ([]unstructured.Unstructured, error) { return nil, errBoom }),
+			want:    want{raw: string(raw)},
+		},
+		"FetchError": {
+			reason:  "Errors fetching a schematic should be returned",
+			get:     withSchematic,
+			fetcher: OCISchematicFetcherFn(func(_ context.Context, _ string) ([]unstructured.Unstructured, error) { return nil, errBoom }),
+			want:    want{err: errors.Wrapf(errBoom, errFmtFetchSchematic, ref)},
+		},
+		"NoManifestOfKind": {
+			reason:  "Schematics without a manifest of the workload's kind should be rejected",
+			get:     withSchematic,
+			fetcher: OCISchematicFetcherFn(func(_ context.Context, _ string) ([]unstructured.Unstructured, error) { return template[:1], nil }),
+			want:    want{err: errors.Errorf(errFmtNoSchematicKind, ref, "apps/v1", "Deployment")},
+		},
+		"Merged": {
+			reason:  "Workloads should be rendered on top of the manifest of their kind",
+			get:     withSchematic,
+			fetcher: OCISchematicFetcherFn(func(_ context.Context, _ string) ([]unstructured.Unstructured, error) { return template, nil }),
+			want: want{raw: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"labels":{"app":"template"},"name":"cool"},` +
+				`"spec":{"replicas":3,"strategy":{"type":"Recreate"}}}`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &components{client: &test.MockClient{MockGet: tc.get}, schematics: tc.fetcher}
+			got, err := r.applySchematic(context.Background(), raw, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.applySchematic(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.raw, string(got)); diff != "" {
+				t.Errorf("\n%s\nr.applySchematic(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
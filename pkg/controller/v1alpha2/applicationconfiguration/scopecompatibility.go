@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Scope compatibility error strings.
+const (
+	errFmtGetScopeDefinition = "cannot get scope definition %q"
+	errFmtIncompatibleScope  = "workload %q of component %q is not a %s workload kind, so it cannot join scope %q"
+)
+
+// incompatibleScopes removes the scopes of the supplied workloads whose
+// ScopeDefinition does not apply to the kind of the workload, returning a
+// message for each. Scopes whose kind has no ScopeDefinition apply to all
+// workload kinds. No scopes are removed if an error is returned.
+func incompatibleScopes(ctx context.Context, c client.Reader, w []Workload) ([]string, error) {
+	definitions := make(map[string]*v1alpha2.ScopeDefinition)
+	compatible := make([][]unstructured.Unstructured, len(w))
+	msgs := make([]string, 0)
+	for i := range w {
+		compatible[i] = make([]unstructured.Unstructured, 0, len(w[i].Scopes))
+		for _, s := range w[i].Scopes {
+			name := util.GetCRDName(&s)
+			sd, ok := definitions[name]
+			if !ok {
+				sd = &v1alpha2.ScopeDefinition{}
+				err := c.Get(ctx, types.NamespacedName{Name: name}, sd)
+				if kerrors.IsNotFound(err) {
+					sd = nil
+					err = nil
+				}
+				if err != nil {
+					return nil, errors.Wrapf(err, errFmtGetScopeDefinition, name)
+				}
+				definitions[name] = sd
+			}
+			if sd != nil && !appliesToWorkload(sd.Spec.AppliesToWorkloads, w[i].Workload) {
+				msgs = append(msgs, fmt.Sprintf(errFmtIncompatibleScope, w[i].Workload.GetName(), w[i].ComponentName, strings.Join(sd.Spec.AppliesToWorkloads, ", "), s.GetName()))
+				continue
+			}
+			compatible[i] = append(compatible[i], s)
+		}
+	}
+	for i := range w {
+		if len(compatible[i]) != len(w[i].Scopes) {
+			w[i].Scopes = compatible[i]
+		}
+	}
+	return msgs, nil
+}
+
+// appliesToWorkload returns true if any of the supplied workload kinds, in
+// kind.group/version format or the name of a WorkloadDefinition, is the kind
+// of the supplied workload. An empty list of kinds applies to all workloads.
+func appliesToWorkload(kinds []string, w *unstructured.Unstructured) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	gvk := w.GroupVersionKind()
+	kind := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+	kind += "/" + gvk.Version
+	definition := util.GetCRDName(w)
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) || k == definition {
+			return true
+		}
+	}
+	return false
+}
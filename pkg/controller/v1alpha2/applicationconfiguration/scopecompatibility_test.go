@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestIncompatibleScopes(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	scope := func() unstructured.Unstructured {
+		s := unstructured.Unstructured{}
+		s.SetAPIVersion("core.oam.dev/v1alpha2")
+		s.SetKind("HealthScope")
+		s.SetName("health")
+		return s
+	}
+	workloads := func() []Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("core.oam.dev/v1alpha2")
+		w.SetKind("ContainerizedWorkload")
+		w.SetName("web")
+		return []Workload{{ComponentName: "web", Workload: w, Scopes: []unstructured.Unstructured{scope()}}}
+	}
+	definition := func(appliesTo ...string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			sd := obj.(*v1alpha2.ScopeDefinition)
+			sd.Spec.AppliesToWorkloads = appliesTo
+			return nil
+		})
+	}
+
+	type want struct {
+		msgs   []string
+		scopes []unstructured.Unstructured
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		want   want
+	}{
+		"AnyWorkload": {
+			reason: "A ScopeDefinition that omits appliesToWorkloads should apply to any workload kind",
+			get:    definition(),
+			want:   want{msgs: []string{}, scopes: []unstructured.Unstructured{scope()}},
+		},
+		"AppliesToKind": {
+			reason: "A ScopeDefinition should apply to workload kinds in kind.group/version format",
+			get:    definition("containerizedworkload.core.oam.dev/v1alpha2"),
+			want:   want{msgs: []string{}, scopes: []unstructured.Unstructured{scope()}},
+		},
+		"AppliesToDefinition": {
+			reason: "A ScopeDefinition should apply to workload kinds named by their WorkloadDefinition",
+			get:    definition("containerizedworkloads.core.oam.dev"),
+			want:   want{msgs: []string{}, scopes: []unstructured.Unstructured{scope()}},
+		},
+		"Incompatible": {
+			reason: "A scope that does not apply to the workload's kind should be removed, and reported",
+			get:    definition("deployment.apps/v1"),
+			want: want{
+				msgs:   []string{fmt.Sprintf(errFmtIncompatibleScope, "web", "web", "deployment.apps/v1", "health")},
+				scopes: []unstructured.Unstructured{},
+			},
+		},
+		"NoDefinition": {
+			reason: "A scope whose kind has no ScopeDefinition should apply to any workload kind",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "healthscopes.core.oam.dev")),
+			want:   want{msgs: []string{}, scopes: []unstructured.Unstructured{scope()}},
+		},
+		"GetError": {
+			reason: "Errors getting a ScopeDefinition should be returned, without removing any scopes",
+			get:    test.NewMockGetFn(errBoom),
+			want: want{
+				scopes: []unstructured.Unstructured{scope()},
+				err:    errors.Wrapf(errBoom, errFmtGetScopeDefinition, "healthscopes.core.oam.dev"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := workloads()
+			msgs, err := incompatibleScopes(context.Background(), &test.MockClient{MockGet: tc.get}, w)
+			if diff := cmp.Diff(tc.want, want{msgs: msgs, scopes: w[0].Scopes, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nincompatibleScopes(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
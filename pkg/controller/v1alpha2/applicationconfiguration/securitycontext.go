@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Security context error strings.
+const (
+	errConvertSecurityContext = "cannot convert security context"
+	errFmtGetPodSecurityLevel = "cannot get PodSecurity level of namespace %q"
+	errFmtPodSecurity         = "security context of component %q violates PodSecurity %q: %s"
+)
+
+// podSecurityContextPath is the field path of the pod security context of
+// workloads that embed a pod template.
+const podSecurityContextPath = "spec.template.spec.securityContext"
+
+// labelPodSecurityEnforce is the label of a namespace that specifies the
+// PodSecurity admission level enforced on its pods.
+const labelPodSecurityEnforce = "pod-security.kubernetes.io/enforce"
+
+// PodSecurity admission levels.
+const (
+	podSecurityBaseline   = "baseline"
+	podSecurityRestricted = "restricted"
+)
+
+var (
+	// podSecuritySELinuxTypes that the baseline level allows.
+	podSecuritySELinuxTypes = map[string]bool{"": true, "container_t": true, "container_init_t": true, "container_kvm_t": true}
+
+	// podSecuritySysctls that the baseline level allows.
+	podSecuritySysctls = map[string]bool{
+		"kernel.shm_rmid_forced":              true,
+		"net.ipv4.ip_local_port_range":        true,
+		"net.ipv4.ip_unprivileged_port_start": true,
+		"net.ipv4.tcp_syncookies":             true,
+		"net.ipv4.ping_group_range":           true,
+	}
+
+	// podSecuritySeccompTypes that the restricted level requires.
+	podSecuritySeccompTypes = map[string]bool{"RuntimeDefault": true, "Localhost": true}
+)
+
+// injectSecurityContext merges the supplied security context into the pod
+// template of the supplied workload. Fields set in the supplied security
+// context replace those of the pod template's. It returns false if there is a
+// security context to inject but the workload has no pod template.
+func injectSecurityContext(w *unstructured.Unstructured, sc *corev1.PodSecurityContext) (bool, error) {
+	if sc == nil {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sc)
+	if err != nil {
+		return false, errors.Wrap(err, errConvertSecurityContext)
+	}
+	merged := map[string]interface{}{}
+	if v, err := p.GetValue(podSecurityContextPath); err == nil {
+		if existing, ok := v.(map[string]interface{}); ok {
+			merged = existing
+		}
+	}
+	for k, v := range m {
+		merged[k] = v
+	}
+	// SetValue would turn integer fields such as runAsUser into floats.
+	return true, unstructured.SetNestedMap(w.Object, merged, "spec", "template", "spec", "securityContext")
+}
+
+// podSecurityViolations returns a message for each of the supplied workloads
+// whose injected security context violates the PodSecurity admission level
+// enforced on its namespace. Only the controls that a security context
+// affects are checked.
+func podSecurityViolations(ctx context.Context, c client.Reader, w []Workload) ([]string, error) {
+	msgs := make([]string, 0)
+	levels := make(map[string]string)
+	for _, wl := range w {
+		if !wl.SecurityContextInjected {
+			continue
+		}
+		ns := wl.Workload.GetNamespace()
+		level, ok := levels[ns]
+		if !ok {
+			n := &corev1.Namespace{}
+			if err := c.Get(ctx, types.NamespacedName{Name: ns}, n); err != nil {
+				return nil, errors.Wrapf(err, errFmtGetPodSecurityLevel, ns)
+			}
+			level = n.GetLabels()[labelPodSecurityEnforce]
+			levels[ns] = level
+		}
+		if v := securityContextViolations(wl.Workload, level); len(v) > 0 {
+			msgs = append(msgs, fmt.Sprintf(errFmtPodSecurity, wl.ComponentName, level, strings.Join(v, ", ")))
+		}
+	}
+	return msgs, nil
+}
+
+// securityContextViolations returns the controls of the supplied PodSecurity
+// level that the security contexts of the pod template of the supplied
+// workload violate. The privileged level, or no level, allows anything.
+func securityContextViolations(w *unstructured.Unstructured, level string) []string {
+	if level != podSecurityBaseline && level != podSecurityRestricted {
+		return nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	pod, _ := p.GetValue(podSecurityContextPath)
+	podSC, _ := pod.(map[string]interface{})
+	containerSCs := make([]map[string]interface{}, 0)
+	for _, path := range []string{podTemplateContainersPath, "spec.template.spec.initContainers"} {
+		v, _ := p.GetValue(path)
+		containers, _ := v.([]interface{})
+		for _, c := range containers {
+			cm, _ := c.(map[string]interface{})
+			sc, _ := cm["securityContext"].(map[string]interface{})
+			containerSCs = append(containerSCs, sc)
+		}
+	}
+	all := append([]map[string]interface{}{podSC}, containerSCs...)
+
+	violations := make([]string, 0)
+	for _, sc := range all {
+		t, _, _ := unstructured.NestedString(sc, "seLinuxOptions", "type")
+		user, _, _ := unstructured.NestedString(sc, "seLinuxOptions", "user")
+		role, _, _ := unstructured.NestedString(sc, "seLinuxOptions", "role")
+		if !podSecuritySELinuxTypes[t] || user != "" || role != "" {
+			violations = append(violations, "seLinuxOptions")
+			break
+		}
+	}
+	for _, sc := range all {
+		if t, _, _ := unstructured.NestedString(sc, "seccompProfile", "type"); t == "Unconfined" {
+			violations = append(violations, "seccompProfile")
+			break
+		}
+	}
+	for _, sc := range all {
+		if hp, _, _ := unstructured.NestedBool(sc, "windowsOptions", "hostProcess"); hp {
+			violations = append(violations, "hostProcess")
+			break
+		}
+	}
+	sysctls, _, _ := unstructured.NestedSlice(podSC, "sysctls")
+	for _, s := range sysctls {
+		sm, _ := s.(map[string]interface{})
+		if name, _ := sm["name"].(string); !podSecuritySysctls[name] {
+			violations = append(violations, "sysctls")
+			break
+		}
+	}
+	if level != podSecurityRestricted {
+		return violations
+	}
+
+	// The restricted level requires that pods run as a non-root user, and
+	// use a seccomp profile, either by setting them for the pod or for every
+	// container.
+	if !allowedByPodOrContainers(podSC, containerSCs, func(sc map[string]interface{}) (bool, bool) {
+		v, set, _ := unstructured.NestedBool(sc, "runAsNonRoot")
+		return v, set
+	}) {
+		violations = append(violations, "runAsNonRoot != true")
+	}
+	for _, sc := range all {
+		if uid, set, _ := unstructured.NestedInt64(sc, "runAsUser"); set && uid == 0 {
+			violations = append(violations, "runAsUser=0")
+			break
+		}
+	}
+	if !allowedByPodOrContainers(podSC, containerSCs, func(sc map[string]interface{}) (bool, bool) {
+		t, set, _ := unstructured.NestedString(sc, "seccompProfile", "type")
+		return podSecuritySeccompTypes[t], set
+	}) {
+		violations = append(violations, "seccompProfile not RuntimeDefault or Localhost")
+	}
+	return violations
+}
+
+// allowedByPodOrContainers returns true if the supplied control, which
+// returns whether a security context is allowed and whether it sets the
+// control at all, is allowed for the pod and every container. Containers
+// that do not set the control inherit the pod's.
+func allowedByPodOrContainers(pod map[string]interface{}, containers []map[string]interface{}, control func(sc map[string]interface{}) (allowed, set bool)) bool {
+	podAllowed, podSet := control(pod)
+	if podSet && !podAllowed {
+		return false
+	}
+	for _, sc := range containers {
+		allowed, set := control(sc)
+		if set && !allowed {
+			return false
+		}
+		if !set && !podAllowed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestInjectSecurityContext(t *testing.T) {
+	runAsUser, fsGroup, override := int64(1000), int64(2000), int64(3000)
+
+	// workload returns a workload whose pod template has the supplied
+	// security context, if any.
+	workload := func(sc map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "main"}}}
+		if sc != nil {
+			spec["securityContext"] = sc
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		sc     *corev1.PodSecurityContext
+		want   want
+	}{
+		"NilSecurityContext": {
+			reason: "A workload should be unchanged when no security context is supplied",
+			w:      workload(map[string]interface{}{"runAsUser": runAsUser}),
+			want:   want{w: workload(map[string]interface{}{"runAsUser": runAsUser}), injected: true},
+		},
+		"Injected": {
+			reason: "The security context should be injected into a pod template without one",
+			w:      workload(nil),
+			sc:     &corev1.PodSecurityContext{RunAsUser: &runAsUser},
+			want:   want{w: workload(map[string]interface{}{"runAsUser": runAsUser}), injected: true},
+		},
+		"Merged": {
+			reason: "Fields set in the supplied security context should replace those of the pod template, which should otherwise be retained",
+			w:      workload(map[string]interface{}{"runAsUser": runAsUser, "fsGroup": fsGroup}),
+			sc:     &corev1.PodSecurityContext{RunAsUser: &override},
+			want:   want{w: workload(map[string]interface{}{"runAsUser": override, "fsGroup": fsGroup}), injected: true},
+		},
+		"NoPodTemplate": {
+			reason: "A workload without a pod template should be unchanged, and reported as such",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			sc:     &corev1.PodSecurityContext{RunAsUser: &runAsUser},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectSecurityContext(tc.w, tc.sc)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectSecurityContext(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectSecurityContext(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecurityContextViolations(t *testing.T) {
+	// workload returns a workload whose pod template has the supplied pod
+	// security context, and a container with the supplied security context.
+	workload := func(pod, container map[string]interface{}) *unstructured.Unstructured {
+		c := map[string]interface{}{"name": "main"}
+		if container != nil {
+			c["securityContext"] = container
+		}
+		spec := map[string]interface{}{"containers": []interface{}{c}}
+		if pod != nil {
+			spec["securityContext"] = pod
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+	restricted := map[string]interface{}{"runAsNonRoot": true, "seccompProfile": map[string]interface{}{"type": "RuntimeDefault"}}
+
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		level  string
+		want   []string
+	}{
+		"Privileged": {
+			reason: "The privileged level should allow any security context",
+			w:      workload(map[string]interface{}{"runAsUser": int64(0), "seLinuxOptions": map[string]interface{}{"user": "root"}}, nil),
+			level:  "privileged",
+		},
+		"Unlabelled": {
+			reason: "Namespaces without a PodSecurity level should allow any security context",
+			w:      workload(map[string]interface{}{"seccompProfile": map[string]interface{}{"type": "Unconfined"}}, nil),
+		},
+		"Baseline": {
+			reason: "The baseline level should forbid custom SELinux users, unconfined seccomp profiles, and unsafe sysctls",
+			w: workload(map[string]interface{}{
+				"seLinuxOptions": map[string]interface{}{"user": "root"},
+				"sysctls":        []interface{}{map[string]interface{}{"name": "kernel.msgmax", "value": "1"}},
+			}, map[string]interface{}{"seccompProfile": map[string]interface{}{"type": "Unconfined"}}),
+			level: podSecurityBaseline,
+			want:  []string{"seLinuxOptions", "seccompProfile", "sysctls"},
+		},
+		"BaselineAllowsRoot": {
+			reason: "The baseline level should allow pods that run as root",
+			w:      workload(map[string]interface{}{"runAsUser": int64(0)}, nil),
+			level:  podSecurityBaseline,
+		},
+		"Restricted": {
+			reason: "The restricted level should require pods to run as a non-root user with a seccomp profile",
+			w:      workload(map[string]interface{}{"runAsUser": int64(0)}, nil),
+			level:  podSecurityRestricted,
+			want:   []string{"runAsNonRoot != true", "runAsUser=0", "seccompProfile not RuntimeDefault or Localhost"},
+		},
+		"RestrictedPod": {
+			reason: "The restricted level should be satisfied by the pod security context",
+			w:      workload(restricted, nil),
+			level:  podSecurityRestricted,
+		},
+		"RestrictedContainers": {
+			reason: "The restricted level should be satisfied by every container's security context",
+			w:      workload(nil, restricted),
+			level:  podSecurityRestricted,
+		},
+		"RestrictedContainerOverride": {
+			reason: "A container should not be allowed to override a compliant pod security context",
+			w:      workload(restricted, map[string]interface{}{"runAsNonRoot": false}),
+			level:  podSecurityRestricted,
+			want:   []string{"runAsNonRoot != true"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := securityContextViolations(tc.w, tc.level)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nsecurityContextViolations(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPodSecurityViolations(t *testing.T) {
+	errBoom := errors.New("boom")
+	workload := func(name string, injected bool) Workload {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers":      []interface{}{map[string]interface{}{"name": "main"}},
+				"securityContext": map[string]interface{}{"runAsUser": int64(0)},
+			}}},
+		}}
+		w.SetNamespace("ns")
+		return Workload{ComponentName: name, Workload: w, SecurityContextInjected: injected}
+	}
+	namespace := func(level string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.Namespace).SetLabels(map[string]string{labelPodSecurityEnforce: level})
+			return nil
+		})
+	}
+
+	type want struct {
+		msgs []string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		w      []Workload
+		want   want
+	}{
+		"Violation": {
+			reason: "Workloads whose injected security context violates their namespace's PodSecurity level should be reported",
+			get:    namespace(podSecurityRestricted),
+			w:      []Workload{workload("injected", true), workload("untouched", false)},
+			want: want{msgs: []string{fmt.Sprintf(errFmtPodSecurity, "injected", podSecurityRestricted,
+				"runAsNonRoot != true, runAsUser=0, seccompProfile not RuntimeDefault or Localhost")}},
+		},
+		"Compliant": {
+			reason: "No workloads should be reported if their security contexts comply",
+			get:    namespace(podSecurityBaseline),
+			w:      []Workload{workload("injected", true)},
+			want:   want{msgs: []string{}},
+		},
+		"NotInjected": {
+			reason: "Namespaces should not be read if no security contexts were injected",
+			get:    test.NewMockGetFn(errBoom),
+			w:      []Workload{workload("untouched", false)},
+			want:   want{msgs: []string{}},
+		},
+		"GetNamespaceError": {
+			reason: "Errors getting the namespace should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			w:      []Workload{workload("injected", true)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetPodSecurityLevel, "ns")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			msgs, err := podSecurityViolations(context.Background(), &test.MockClient{MockGet: tc.get}, tc.w)
+			if diff := cmp.Diff(tc.want, want{msgs: msgs, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\npodSecurityViolations(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
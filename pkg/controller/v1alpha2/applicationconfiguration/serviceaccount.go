@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Service account error strings.
+const (
+	errFmtGetPodServiceAccount    = "cannot get service account %q"
+	errFmtCreatePodServiceAccount = "cannot create service account %q"
+	errFmtServiceAccountNotFound  = "service account %q of component %q does not exist"
+)
+
+// podServiceAccountPath is the field path of the service account of workloads
+// that embed a pod template.
+const podServiceAccountPath = "spec.template.spec.serviceAccountName"
+
+// injectServiceAccount sets the supplied service account in the pod template
+// of the supplied workload. It returns false if there is a service account to
+// inject but the workload has no pod template.
+func injectServiceAccount(w *unstructured.Unstructured, name string) (bool, error) {
+	if name == "" {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+	return true, p.SetString(podServiceAccountPath, name)
+}
+
+// ensureServiceAccounts creates the service accounts of the supplied workloads
+// that do not exist and whose components ask for them to be created. The
+// created service accounts have no permissions, and are controlled by the
+// supplied ApplicationConfiguration. It returns a message for each service
+// account that does not exist and was not created.
+func ensureServiceAccounts(ctx context.Context, c client.Client, ac *v1alpha2.ApplicationConfiguration, w []Workload) ([]string, error) {
+	msgs := make([]string, 0)
+	exists := make(map[string]bool)
+	for _, wl := range w {
+		name := wl.PodServiceAccountName
+		if name == "" || exists[name] {
+			continue
+		}
+		err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: name}, &corev1.ServiceAccount{})
+		if err == nil {
+			exists[name] = true
+			continue
+		}
+		if !kerrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, errFmtGetPodServiceAccount, name)
+		}
+		if !wl.CreatePodServiceAccount {
+			msgs = append(msgs, fmt.Sprintf(errFmtServiceAccountNotFound, name, wl.ComponentName))
+			continue
+		}
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       ac.GetNamespace(),
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+		}}
+		if err := c.Create(ctx, sa); err != nil && !kerrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, errFmtCreatePodServiceAccount, name)
+		}
+		exists[name] = true
+	}
+	return msgs, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestInjectServiceAccount(t *testing.T) {
+	podTemplate := func() *unstructured.Unstructured {
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(podTemplateContainersPath, []interface{}{map[string]interface{}{"name": "c0"}})
+		_ = p.SetString(podServiceAccountPath, "default")
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+
+	type want struct {
+		serviceAccount string
+		injected       bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		name   string
+		want   want
+	}{
+		"NoServiceAccount": {
+			reason: "The workload's service account should be retained if none is supplied",
+			w:      podTemplate(),
+			want:   want{serviceAccount: "default", injected: true},
+		},
+		"Replaced": {
+			reason: "The supplied service account should replace the workload's",
+			w:      podTemplate(),
+			name:   "cool",
+			want:   want{serviceAccount: "cool", injected: true},
+		},
+		"NoPodTemplate": {
+			reason: "Service accounts cannot be injected into workloads without a pod template",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			name:   "cool",
+			want:   want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectServiceAccount(tc.w, tc.name)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectServiceAccount(...): unexpected error: %s", tc.reason, err)
+			}
+			got, _ := fieldpath.Pave(tc.w.UnstructuredContent()).GetString(podServiceAccountPath)
+			if diff := cmp.Diff(tc.want, want{serviceAccount: got, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectServiceAccount(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnsureServiceAccounts(t *testing.T) {
+	errBoom := errors.New("boom")
+	ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool", UID: "uid"}}
+	w := []Workload{
+		{ComponentName: "a", PodServiceAccountName: "exists"},
+		{ComponentName: "b"},
+		{ComponentName: "c", PodServiceAccountName: "missing"},
+		{ComponentName: "d", PodServiceAccountName: "created", CreatePodServiceAccount: true},
+	}
+	getFn := func(_ context.Context, key client.ObjectKey, _ runtime.Object) error {
+		if key.Name == "exists" {
+			return nil
+		}
+		return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+
+	type want struct {
+		missing []string
+		created []string
+		err     error
+	}
+	cases := map[string]struct {
+		reason    string
+		get       test.MockGetFn
+		createErr error
+		want      want
+	}{
+		"Ensured": {
+			reason: "Missing service accounts should be created if their component asks for it, and reported otherwise",
+			get:    getFn,
+			want: want{
+				missing: []string{fmt.Sprintf(errFmtServiceAccountNotFound, "missing", "c")},
+				created: []string{"ns/created owned by cool"},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting service accounts should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetPodServiceAccount, "exists")},
+		},
+		"CreateError": {
+			reason:    "Errors creating service accounts should be returned",
+			get:       getFn,
+			createErr: errBoom,
+			want: want{
+				created: []string{"ns/created owned by cool"},
+				err:     errors.Wrapf(errBoom, errFmtCreatePodServiceAccount, "created"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created []string
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					sa := obj.(*corev1.ServiceAccount)
+					created = append(created, fmt.Sprintf("%s/%s owned by %s", sa.GetNamespace(), sa.GetName(), metav1.GetControllerOf(sa).Name))
+					return tc.createErr
+				},
+			}
+			missing, err := ensureServiceAccounts(context.Background(), c, ac, w)
+			if diff := cmp.Diff(tc.want, want{missing: missing, created: created, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nensureServiceAccounts(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Spec source error strings.
+const (
+	errFmtGetSpecSource        = "cannot get spec source ConfigMap %q"
+	errFmtNoSpecSourceKey      = "spec source ConfigMap %q has no key %q"
+	errFmtNotSpecSource        = "ConfigMap %q is not labelled as a spec source"
+	errFmtParseSpecSource      = "cannot parse components of spec source ConfigMap %q key %q"
+	errListAppConfigsConfigMap = "cannot list ApplicationConfigurations that may use ConfigMap"
+	errFmtParseDocument        = "cannot parse document %d starting at line %d: %s"
+)
+
+// specSourceConfigMaps selects the ConfigMaps from which
+// ApplicationConfigurations may read their components.
+var specSourceConfigMaps = labels.SelectorFromSet(labels.Set{oam.LabelAppConfigSource: "true"})
+
+// resolveSpecSource replaces the components of the supplied
+// ApplicationConfiguration with those read from its SpecSource, if any.
+// ApplicationSets are rendered by the supplied renderer, or by an uncached
+// renderer if it is nil.
+func resolveSpecSource(ctx context.Context, c client.Reader, as *ArgoApplicationSetRenderer, ac *v1alpha2.ApplicationConfiguration) error {
+	if ac.Spec.SpecSource == nil {
+		return nil
+	}
+	if asRef := ac.Spec.SpecSource.ArgoApplicationSetRef; asRef != nil {
+		if as == nil {
+			as = NewArgoApplicationSetRenderer(c)
+		}
+		comps, err := as.Render(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: asRef.Name})
+		if err != nil {
+			return err
+		}
+		ac.Spec.Components = comps
+		return nil
+	}
+	ref := ac.Spec.SpecSource.ConfigMapRef
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ref.Name}, cm); err != nil {
+		return errors.Wrapf(err, errFmtGetSpecSource, ref.Name)
+	}
+	if !specSourceConfigMaps.Matches(labels.Set(cm.GetLabels())) {
+		return errors.Errorf(errFmtNotSpecSource, ref.Name)
+	}
+	data, ok := cm.Data[ref.Key]
+	if !ok {
+		return errors.Errorf(errFmtNoSpecSourceKey, ref.Name, ref.Key)
+	}
+	comps, err := MultiDocumentYAMLParser{}.Parse(data)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseSpecSource, ref.Name, ref.Key)
+	}
+	ac.Spec.Components = comps
+	return nil
+}
+
+// A MultiDocumentYAMLParser parses components from JSON or YAML that may
+// consist of several YAML documents separated by ---. Each document is either
+// a component or a list of components.
+type MultiDocumentYAMLParser struct{}
+
+// Parse the components of the supplied data. Components with the same name
+// are merged: the last one wins, at the position of the first.
+func (p MultiDocumentYAMLParser) Parse(data string) ([]v1alpha2.ApplicationConfigurationComponent, error) {
+	comps := make([]v1alpha2.ApplicationConfigurationComponent, 0)
+	index := make(map[string]int)
+	for i, doc := range splitYAMLDocuments(data) {
+		parsed, err := parseYAMLDocument(doc.data)
+		if err != nil {
+			return nil, &specParseError{document: i + 1, line: doc.line, err: err}
+		}
+		for _, c := range parsed {
+			name := c.ComponentName
+			if c.RevisionName != "" {
+				name = ExtractComponentName(c.RevisionName)
+			}
+			if j, ok := index[name]; ok {
+				comps[j] = c
+				continue
+			}
+			index[name] = len(comps)
+			comps = append(comps, c)
+		}
+	}
+	return comps, nil
+}
+
+type yamlDocument struct {
+	// line of the data at which the document starts.
+	line int
+	data string
+}
+
+// splitYAMLDocuments splits the supplied data into documents at each line
+// that starts with the --- document separator.
+func splitYAMLDocuments(data string) []yamlDocument {
+	docs := make([]yamlDocument, 0)
+	cur, start := []string{}, 1
+	lines := strings.Split(data, "\n")
+	for i, l := range lines {
+		l = strings.TrimRight(l, " \t\r")
+		if l == "---" || strings.HasPrefix(l, "--- ") {
+			docs = append(docs, yamlDocument{line: start, data: strings.Join(cur, "\n")})
+			cur, start = []string{}, i+2
+			continue
+		}
+		cur = append(cur, lines[i])
+	}
+	return append(docs, yamlDocument{line: start, data: strings.Join(cur, "\n")})
+}
+
+// parseYAMLDocument parses the components of the supplied YAML document,
+// which may be a component, a list of components, or empty.
+func parseYAMLDocument(doc string) ([]v1alpha2.ApplicationConfigurationComponent, error) {
+	j, err := yaml.ToJSON([]byte(doc))
+	if err != nil {
+		return nil, err
+	}
+	j = bytes.TrimSpace(j)
+	switch {
+	case len(j) == 0 || string(j) == "null":
+		return nil, nil
+	case j[0] == '[':
+		comps := make([]v1alpha2.ApplicationConfigurationComponent, 0)
+		err := json.Unmarshal(j, &comps)
+		return comps, err
+	default:
+		c := v1alpha2.ApplicationConfigurationComponent{}
+		err := json.Unmarshal(j, &c)
+		return []v1alpha2.ApplicationConfigurationComponent{c}, err
+	}
+}
+
+// yamlLine matches the line numbers in YAML parse errors.
+var yamlLine = regexp.MustCompile(`line (\d+)`)
+
+// A specParseError indicates that a document of a spec source could not be
+// parsed.
+type specParseError struct {
+	document int
+	line     int
+	err      error
+}
+
+// Error returns the parse error with its line numbers relative to the start
+// of the spec source, rather than of the document.
+func (e *specParseError) Error() string {
+	msg := yamlLine.ReplaceAllStringFunc(e.err.Error(), func(m string) string {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, "line "))
+		if err != nil {
+			return m
+		}
+		return fmt.Sprintf("line %d", n+e.line-1)
+	})
+	return fmt.Sprintf(errFmtParseDocument, e.document, e.line, msg)
+}
+
+// IsSpecParseError returns true if the supplied error indicates that a
+// document of a spec source could not be parsed.
+func IsSpecParseError(err error) bool {
+	_, ok := errors.Cause(err).(*specParseError)
+	return ok
+}
+
+// A specSourceConfigMapMapper maps a ConfigMap to the ApplicationConfigurations
+// in its namespace that read their components from it.
+type specSourceConfigMapMapper struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+var _ handler.Mapper = &specSourceConfigMapMapper{}
+
+func (m *specSourceConfigMapMapper) Map(o handler.MapObject) []reconcile.Request {
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := m.client.List(context.Background(), acs, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+		m.log.Debug(errListAppConfigsConfigMap, "error", err, "configmap", o.Meta.GetName())
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, ac := range acs.Items {
+		if ac.Spec.SpecSource == nil || ac.Spec.SpecSource.ArgoApplicationSetRef != nil || ac.Spec.SpecSource.ConfigMapRef.Name != o.Meta.GetName() {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}})
+	}
+	return reqs
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestResolveSpecSource(t *testing.T) {
+	errBoom := errors.New("boom")
+	inline := []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "inline"}}
+
+	ac := func(src *v1alpha2.SpecSource) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"},
+			Spec:       v1alpha2.ApplicationConfigurationSpec{Components: inline, SpecSource: src},
+		}
+	}
+	src := &v1alpha2.SpecSource{ConfigMapRef: v1alpha2.ConfigMapKeySelector{Name: "gitops", Key: "components"}}
+	configMap := func(data map[string]string) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			obj.(*corev1.ConfigMap).SetLabels(map[string]string{oam.LabelAppConfigSource: "true"})
+			obj.(*corev1.ConfigMap).Data = data
+			return nil
+		}
+	}
+
+	type want struct {
+		components []v1alpha2.ApplicationConfigurationComponent
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		client *test.MockClient
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"NoSpecSource": {
+			reason: "Inline components should be used when no spec source is set",
+			client: &test.MockClient{},
+			ac:     ac(nil),
+			want:   want{components: inline},
+		},
+		"GetConfigMapError": {
+			reason: "Errors getting the spec source ConfigMap should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ac:     ac(src),
+			want:   want{components: inline, err: errors.Wrapf(errBoom, errFmtGetSpecSource, "gitops")},
+		},
+		"NotSpecSource": {
+			reason: "A ConfigMap that is not labelled as a spec source should return an error",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			ac:     ac(src),
+			want:   want{components: inline, err: errors.Errorf(errFmtNotSpecSource, "gitops")},
+		},
+		"MissingKey": {
+			reason: "A spec source ConfigMap without the referenced key should return an error",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, configMap(nil))},
+			ac:     ac(src),
+			want:   want{components: inline, err: errors.Errorf(errFmtNoSpecSourceKey, "gitops", "components")},
+		},
+		"YAML": {
+			reason: "Components should be read from YAML encoded spec sources",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, configMap(map[string]string{
+				"components": "- componentName: a\n- componentName: b\n",
+			}))},
+			ac:   ac(src),
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "a"}, {ComponentName: "b"}}},
+		},
+		"JSON": {
+			reason: "Components should be read from JSON encoded spec sources",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, configMap(map[string]string{
+				"components": `[{"componentName":"a"}]`,
+			}))},
+			ac:   ac(src),
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "a"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := resolveSpecSource(context.Background(), tc.client, nil, tc.ac)
+			got := want{components: tc.ac.Spec.Components, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveSpecSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSpecSourceConfigMapMapper(t *testing.T) {
+	using := v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "using"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			SpecSource: &v1alpha2.SpecSource{ConfigMapRef: v1alpha2.ConfigMapKeySelector{Name: "gitops", Key: "components"}},
+		},
+	}
+	other := v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			SpecSource: &v1alpha2.SpecSource{ConfigMapRef: v1alpha2.ConfigMapKeySelector{Name: "elsewhere", Key: "components"}},
+		},
+	}
+	inline := v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "inline"}}
+
+	m := &specSourceConfigMapMapper{
+		client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.ApplicationConfigurationList).Items = []v1alpha2.ApplicationConfiguration{using, other, inline}
+			return nil
+		})},
+		log: logging.NewNopLogger(),
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "gitops"}}
+	got := m.Map(handler.MapObject{Meta: cm, Object: cm})
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "using"}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("m.Map(...): -want, +got:\n%s", diff)
+	}
+}
+func TestMultiDocumentYAMLParser(t *testing.T) {
+	type want struct {
+		components []v1alpha2.ApplicationConfigurationComponent
+		parseError bool
+		msg        string
+	}
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   want
+	}{
+		"List": {
+			reason: "A single document containing a list of components should be parsed",
+			data:   "- componentName: a\n- componentName: b\n",
+			want:   want{components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "a"}, {ComponentName: "b"}}},
+		},
+		"Documents": {
+			reason: "Each document should be parsed as a component or a list of components",
+			data:   "componentName: a\n---\n- componentName: b\n- componentName: c\n--- # the last one\ncomponentName: d\n",
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "a"}, {ComponentName: "b"}, {ComponentName: "c"}, {ComponentName: "d"},
+			}},
+		},
+		"Merge": {
+			reason: "Components with the same name should be merged, with the last one winning",
+			data:   "componentName: a\nrevisionName: a-v1\n---\ncomponentName: b\n---\nrevisionName: a-v2\n",
+			want: want{components: []v1alpha2.ApplicationConfigurationComponent{
+				{RevisionName: "a-v2"}, {ComponentName: "b"},
+			}},
+		},
+		"Empty": {
+			reason: "Empty documents should be ignored",
+			data:   "---\ncomponentName: a\n---\n",
+			want:   want{components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "a"}}},
+		},
+		"Malformed": {
+			reason: "Malformed documents should return a parse error with the line number relative to the spec source",
+			data:   "componentName: a\n---\ncomponentName: b\n  traits: [\n",
+			want:   want{parseError: true, msg: "cannot parse document 2 starting at line 3"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comps, err := MultiDocumentYAMLParser{}.Parse(tc.data)
+			if diff := cmp.Diff(tc.want.components, comps); diff != "" {
+				t.Errorf("\n%s\np.Parse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.parseError, IsSpecParseError(err)); diff != "" {
+				t.Errorf("\n%s\nIsSpecParseError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), tc.want.msg) {
+				t.Errorf("\n%s\np.Parse(...): want error starting with %q, got %q", tc.reason, tc.want.msg, err)
+			}
+		})
+	}
+}
+
+func TestSpecParseErrorLines(t *testing.T) {
+	err := &specParseError{document: 2, line: 10, err: errors.New("yaml: line 3: mapping values are not allowed in this context")}
+	want := "cannot parse document 2 starting at line 10: yaml: line 12: mapping values are not allowed in this context"
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Errorf("err.Error(): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Spec validation error strings.
+const (
+	errFmtGetWorkloadDefinitionOf = "cannot get workload definition of workload %q"
+	errFmtGetSchemaCRD            = "cannot get CustomResourceDefinition %q"
+	errFmtConvertSchemaCRD        = "cannot convert CustomResourceDefinition %q"
+	errFmtConvertSchema           = "cannot convert OpenAPI schema of CustomResourceDefinition %q"
+	errFmtNewSchemaValidator      = "cannot create OpenAPI schema validator for CustomResourceDefinition %q"
+	errFmtValidateWorkloadSpec    = "cannot validate workload of component %q"
+	errFmtInvalidWorkloadSpec     = "workload %q of component %q does not match its schema: %s"
+)
+
+// A SpecValidator validates rendered workloads against the schema of their
+// kind.
+type SpecValidator interface {
+	// Validate the supplied workload, returning how it is invalid, if at all.
+	Validate(ctx context.Context, w *unstructured.Unstructured) (field.ErrorList, error)
+}
+
+// A SpecValidateFn validates rendered workloads against the schema of their
+// kind.
+type SpecValidateFn func(ctx context.Context, w *unstructured.Unstructured) (field.ErrorList, error)
+
+// Validate the supplied workload, returning how it is invalid, if at all.
+func (fn SpecValidateFn) Validate(ctx context.Context, w *unstructured.Unstructured) (field.ErrorList, error) {
+	return fn(ctx, w)
+}
+
+// A crdSpecValidator validates workloads against the OpenAPI schema of the
+// version of their kind served by the CustomResourceDefinition referenced by
+// their WorkloadDefinition. Workloads whose kind has no WorkloadDefinition, or
+// whose CustomResourceDefinition has no schema, are considered valid.
+type crdSpecValidator struct {
+	// client reads WorkloadDefinitions and CustomResourceDefinitions, which
+	// are always read from the cluster of the ApplicationConfiguration.
+	client client.Reader
+}
+
+func (v *crdSpecValidator) Validate(ctx context.Context, w *unstructured.Unstructured) (field.ErrorList, error) {
+	wd, err := util.FetchWorkloadDefinition(ctx, v.client, w)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetWorkloadDefinitionOf, w.GetName())
+	}
+	name := wd.Spec.Reference.Name
+	if name == "" {
+		return nil, nil
+	}
+
+	// CustomResourceDefinitions are read as unstructured objects so that the
+	// apiextensions types need not be registered with the manager's scheme.
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiextensionsv1.SchemeGroupVersion.String())
+	u.SetKind("CustomResourceDefinition")
+	err = v.client.Get(ctx, types.NamespacedName{Name: name}, u)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetSchemaCRD, name)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), crd); err != nil {
+		return nil, errors.Wrapf(err, errFmtConvertSchemaCRD, name)
+	}
+
+	s := schemaOf(crd, w.GroupVersionKind().Version)
+	if s == nil {
+		return nil, nil
+	}
+	in := &apiextensions.CustomResourceValidation{}
+	if err := apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(s, in, nil); err != nil {
+		return nil, errors.Wrapf(err, errFmtConvertSchema, name)
+	}
+	sv, _, err := validation.NewSchemaValidator(in)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtNewSchemaValidator, name)
+	}
+	return validation.ValidateCustomResource(nil, w.UnstructuredContent(), sv), nil
+}
+
+// schemaOf returns the schema of the supplied version of the supplied
+// CustomResourceDefinition, or nil if that version has no schema.
+func schemaOf(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.CustomResourceValidation {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+			return v.Schema
+		}
+	}
+	return nil
+}
+
+// invalidWorkloads returns a message for each of the supplied workloads that
+// does not match the schema of its kind.
+func invalidWorkloads(ctx context.Context, v SpecValidator, w []Workload) ([]string, error) {
+	msgs := make([]string, 0)
+	for _, wl := range w {
+		errs, err := v.Validate(ctx, wl.Workload)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtValidateWorkloadSpec, wl.ComponentName)
+		}
+		if len(errs) > 0 {
+			msgs = append(msgs, fmt.Sprintf(errFmtInvalidWorkloadSpec, wl.Workload.GetName(), wl.ComponentName, errs.ToAggregate()))
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestCRDSpecValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+	crdName := "workloads.example.org"
+
+	crd := map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": crdName},
+		"spec": map[string]interface{}{
+			"group": "example.org",
+			"versions": []interface{}{map[string]interface{}{
+				"name":    "v1",
+				"served":  true,
+				"storage": true,
+				"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{"spec": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"image"},
+						"properties": map[string]interface{}{
+							"image":    map[string]interface{}{"type": "string"},
+							"replicas": map[string]interface{}{"type": "integer"},
+						},
+					}},
+				}},
+			}},
+		},
+	}
+
+	workload := func(version string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/" + version,
+			"kind":       "Workload",
+			"metadata":   map[string]interface{}{"name": "workload"},
+			"spec":       spec,
+		}}
+	}
+
+	get := func(wdErr, crdErr error) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.WorkloadDefinition:
+				o.Spec.Reference.Name = crdName
+				return wdErr
+			case *unstructured.Unstructured:
+				if key.Name != crdName {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				o.SetUnstructuredContent(crd)
+				return crdErr
+			}
+			return nil
+		}
+	}
+
+	// The invalid fields are compared, rather than the errors, whose details
+	// are determined by the OpenAPI validator.
+	type want struct {
+		fields []string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		w      *unstructured.Unstructured
+		want   want
+	}{
+		"Valid": {
+			reason: "A workload that matches its schema should be valid",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			w:      workload("v1", map[string]interface{}{"image": "nginx", "replicas": int64(3)}),
+		},
+		"Invalid": {
+			reason: "A workload that does not match its schema should be invalid",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			w:      workload("v1", map[string]interface{}{"replicas": "three"}),
+			want:   want{fields: []string{"spec.image", "spec.replicas"}},
+		},
+		"NoSchema": {
+			reason: "A workload whose version has no schema should be valid",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			w:      workload("v2", map[string]interface{}{"replicas": "three"}),
+		},
+		"NoWorkloadDefinition": {
+			reason: "A workload whose kind has no WorkloadDefinition should be valid",
+			client: &test.MockClient{MockGet: get(kerrors.NewNotFound(schema.GroupResource{}, crdName), nil)},
+			w:      workload("v1", map[string]interface{}{"replicas": "three"}),
+		},
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition should be returned",
+			client: &test.MockClient{MockGet: get(errBoom, nil)},
+			w:      workload("v1", nil),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetWorkloadDefinitionOf, "workload")},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CustomResourceDefinition should be returned",
+			client: &test.MockClient{MockGet: get(nil, errBoom)},
+			w:      workload("v1", nil),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetSchemaCRD, crdName)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &crdSpecValidator{client: tc.client}
+			errs, err := v.Validate(context.Background(), tc.w)
+			got := want{err: err}
+			for _, e := range errs {
+				got.fields = append(got.fields, e.Field)
+			}
+			sort.Strings(got.fields)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Validate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInvalidWorkloads(t *testing.T) {
+	errBoom := errors.New("boom")
+	wl := &unstructured.Unstructured{}
+	wl.SetName("workload")
+	invalid := field.ErrorList{field.Required(field.NewPath("spec", "image"), "")}
+
+	cases := map[string]struct {
+		reason string
+		v      SpecValidator
+		want   []string
+		err    error
+	}{
+		"Valid": {
+			reason: "Valid workloads should not be reported",
+			v: SpecValidateFn(func(_ context.Context, _ *unstructured.Unstructured) (field.ErrorList, error) {
+				return nil, nil
+			}),
+			want: []string{},
+		},
+		"Invalid": {
+			reason: "Invalid workloads should be reported",
+			v: SpecValidateFn(func(_ context.Context, _ *unstructured.Unstructured) (field.ErrorList, error) {
+				return invalid, nil
+			}),
+			want: []string{fmt.Sprintf(errFmtInvalidWorkloadSpec, "workload", "c", invalid.ToAggregate())},
+		},
+		"ValidateError": {
+			reason: "Errors validating workloads should be returned",
+			v: SpecValidateFn(func(_ context.Context, _ *unstructured.Unstructured) (field.ErrorList, error) {
+				return nil, errBoom
+			}),
+			err: errors.Wrapf(errBoom, errFmtValidateWorkloadSpec, "c"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := invalidWorkloads(context.Background(), tc.v, []Workload{{ComponentName: "c", Workload: wl}})
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninvalidWorkloads(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninvalidWorkloads(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
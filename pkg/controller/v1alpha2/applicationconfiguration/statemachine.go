@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const errFmtInvalidTransition = "invalid ApplicationConfiguration state transition from %q to %q"
+
+// StateTransitionGraph maps each ApplicationConfiguration state to the states
+// it may transition to. Every reconcile starts by rendering components, so an
+// ApplicationConfiguration must pass through Rendering (and usually Applying)
+// before it can be Ready. Rendering may move straight to Ready only when the
+// rendered components are unchanged since they were last successfully
+// applied. The empty state is that of a new ApplicationConfiguration.
+// Transitioning to the current state is always valid.
+var StateTransitionGraph = map[v1alpha2.ApplicationConfigurationState][]v1alpha2.ApplicationConfigurationState{
+	"":                      {v1alpha2.StateRendering, v1alpha2.StatePaused},
+	v1alpha2.StateRendering: {v1alpha2.StateApplying, v1alpha2.StateReady, v1alpha2.StateDegraded, v1alpha2.StatePaused},
+	v1alpha2.StateApplying:  {v1alpha2.StateRendering, v1alpha2.StateReady, v1alpha2.StateDegraded, v1alpha2.StatePaused},
+	v1alpha2.StateReady:     {v1alpha2.StateRendering, v1alpha2.StatePaused},
+	v1alpha2.StateDegraded:  {v1alpha2.StateRendering, v1alpha2.StatePaused},
+	v1alpha2.StatePaused:    {v1alpha2.StateRendering},
+}
+
+// A StateMachine transitions ApplicationConfigurations between the states of
+// the StateTransitionGraph, rejecting invalid transitions.
+type StateMachine struct {
+	log logging.Logger
+
+	// strict state machines panic on invalid transitions. They are intended
+	// for use in tests.
+	strict bool
+}
+
+// A StateMachineOption configures a StateMachine.
+type StateMachineOption func(*StateMachine)
+
+// WithStrictTransitions causes the StateMachine to panic when asked to make an
+// invalid transition. Strict state machines should only be used in tests.
+func WithStrictTransitions() StateMachineOption {
+	return func(m *StateMachine) {
+		m.strict = true
+	}
+}
+
+// NewStateMachine returns a StateMachine that logs a warning when asked to
+// make an invalid transition.
+func NewStateMachine(l logging.Logger, o ...StateMachineOption) *StateMachine {
+	m := &StateMachine{log: l}
+	for _, fn := range o {
+		fn(m)
+	}
+	return m
+}
+
+// ValidTransition returns true if the StateTransitionGraph allows a transition
+// between the supplied states.
+func ValidTransition(from, to v1alpha2.ApplicationConfigurationState) bool {
+	if from == to {
+		return true
+	}
+	for _, s := range StateTransitionGraph[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition the supplied ApplicationConfiguration to the supplied state. The
+// ApplicationConfiguration's state is unchanged if the transition is invalid.
+// Transition returns false if it rejected the transition.
+func (m *StateMachine) Transition(ac *v1alpha2.ApplicationConfiguration, to v1alpha2.ApplicationConfigurationState) bool {
+	from := ac.Status.State
+	if !ValidTransition(from, to) {
+		if m.strict {
+			panic(fmt.Sprintf(errFmtInvalidTransition, from, to))
+		}
+		m.log.Info("Rejected invalid state transition", "namespace", ac.GetNamespace(), "name", ac.GetName(), "from", from, "to", to)
+		return false
+	}
+	ac.Status.State = to
+	return true
+}
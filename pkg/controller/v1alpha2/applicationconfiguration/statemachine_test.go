@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestStateMachineTransition(t *testing.T) {
+	type want struct {
+		ok    bool
+		state v1alpha2.ApplicationConfigurationState
+	}
+	cases := map[string]struct {
+		reason string
+		from   v1alpha2.ApplicationConfigurationState
+		to     v1alpha2.ApplicationConfigurationState
+		want   want
+	}{
+		"NewToRendering": {
+			reason: "A new ApplicationConfiguration should be able to start rendering",
+			from:   "",
+			to:     v1alpha2.StateRendering,
+			want:   want{ok: true, state: v1alpha2.StateRendering},
+		},
+		"ApplyingToReady": {
+			reason: "An ApplicationConfiguration should become ready once it has been applied",
+			from:   v1alpha2.StateApplying,
+			to:     v1alpha2.StateReady,
+			want:   want{ok: true, state: v1alpha2.StateReady},
+		},
+		"SameState": {
+			reason: "Transitioning to the current state should always be valid",
+			from:   v1alpha2.StateDegraded,
+			to:     v1alpha2.StateDegraded,
+			want:   want{ok: true, state: v1alpha2.StateDegraded},
+		},
+		"DegradedToReady": {
+			reason: "A degraded ApplicationConfiguration should not become ready without being applied",
+			from:   v1alpha2.StateDegraded,
+			to:     v1alpha2.StateReady,
+			want:   want{ok: false, state: v1alpha2.StateDegraded},
+		},
+		"PausedToApplying": {
+			reason: "A paused ApplicationConfiguration should not apply without rendering",
+			from:   v1alpha2.StatePaused,
+			to:     v1alpha2.StateApplying,
+			want:   want{ok: false, state: v1alpha2.StatePaused},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := ac(withState(tc.from))
+			ok := NewStateMachine(logging.NewNopLogger()).Transition(ac, tc.to)
+			if diff := cmp.Diff(tc.want, want{ok: ok, state: ac.Status.State}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nTransition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStrictStateMachine(t *testing.T) {
+	defer func() {
+		want := fmt.Sprintf(errFmtInvalidTransition, v1alpha2.StateDegraded, v1alpha2.StateReady)
+		if diff := cmp.Diff(want, recover()); diff != "" {
+			t.Errorf("Transition(...): -want panic, +got panic:\n%s", diff)
+		}
+	}()
+	NewStateMachine(logging.NewNopLogger(), WithStrictTransitions()).Transition(ac(withState(v1alpha2.StateDegraded)), v1alpha2.StateReady)
+}
+
+func TestStateTransitionGraph(t *testing.T) {
+	// Every state should be able to reach Ready.
+	for from := range StateTransitionGraph {
+		seen := map[v1alpha2.ApplicationConfigurationState]bool{from: true}
+		queue := []v1alpha2.ApplicationConfigurationState{from}
+		for len(queue) > 0 {
+			s := queue[0]
+			queue = queue[1:]
+			for _, to := range StateTransitionGraph[s] {
+				if !seen[to] {
+					seen[to] = true
+					queue = append(queue, to)
+				}
+			}
+		}
+		if !seen[v1alpha2.StateReady] {
+			t.Errorf("StateTransitionGraph: state %q cannot reach %q", from, v1alpha2.StateReady)
+		}
+	}
+}
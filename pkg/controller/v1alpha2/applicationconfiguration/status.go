@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// pruneWorkloadStatuses removes orphaned entries from the workload statuses of
+// the supplied ApplicationConfiguration. An entry is orphaned if its workload
+// was not rendered and neither its workload nor any of its traits exist, for
+// example because the reconciler stopped after removing them but before
+// updating status. Entries with scopes are kept so that the workload can be
+// removed from its scopes.
+func (r *Reconciler) pruneWorkloadStatuses(ctx context.Context, c client.Reader, ac *v1alpha2.ApplicationConfiguration, w []Workload) error {
+	rendered := make(map[runtimev1alpha1.TypedReference]bool, len(w))
+	for _, wl := range w {
+		rendered[wl.Status().Reference] = true
+	}
+
+	pruned := make([]v1alpha2.WorkloadStatus, 0, len(ac.Status.Workloads))
+	for _, ws := range ac.Status.Workloads {
+		if rendered[ws.Reference] || len(ws.Scopes) > 0 {
+			pruned = append(pruned, ws)
+			continue
+		}
+		live, err := anyExist(ctx, c, ac.GetNamespace(), ws)
+		if err != nil {
+			return err
+		}
+		if live {
+			pruned = append(pruned, ws)
+		}
+	}
+
+	ac.Status.Workloads = nil
+	if len(pruned) > 0 {
+		ac.Status.Workloads = pruned
+	}
+	return nil
+}
+
+// anyExist returns true if the workload or any of the traits referenced by the
+// supplied workload status exist.
+func anyExist(ctx context.Context, c client.Reader, namespace string, ws v1alpha2.WorkloadStatus) (bool, error) {
+	refs := make([]runtimev1alpha1.TypedReference, 0, len(ws.Traits)+1)
+	refs = append(refs, ws.Reference)
+	for _, t := range ws.Traits {
+		refs = append(refs, t.Reference)
+	}
+
+	for _, ref := range refs {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, u)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, errFmtGetApplied, ref.Kind, ref.Name)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// preserveLastAppliedTimes sets the LastAppliedTime of each of the supplied
+// workload statuses that has none, i.e. whose workload was not applied this
+// time, to that of the matching previous status.
+func preserveLastAppliedTimes(ws, previous []v1alpha2.WorkloadStatus) {
+	last := make(map[runtimev1alpha1.TypedReference]*metav1.Time, len(previous))
+	for _, s := range previous {
+		last[s.Reference] = s.LastAppliedTime
+	}
+	for i := range ws {
+		if ws[i].LastAppliedTime == nil {
+			ws[i].LastAppliedTime = last[ws[i].Reference]
+		}
+	}
+}
+
+// preserveHealth sets the health of each of the supplied workload statuses,
+// and when it last transitioned, to that of the same workload in the supplied
+// previous workload statuses, so that a transition is not recorded every time
+// the workloads are applied.
+func preserveHealth(ws, previous []v1alpha2.WorkloadStatus) {
+	last := make(map[runtimev1alpha1.TypedReference]v1alpha2.WorkloadStatus, len(previous))
+	for _, s := range previous {
+		last[s.Reference] = s
+	}
+	for i := range ws {
+		if ws[i].Health != nil {
+			continue
+		}
+		if s, ok := last[ws[i].Reference]; ok {
+			ws[i].Health = s.Health
+			ws[i].LastTransitionTime = s.LastTransitionTime
+		}
+	}
+}
+
+// preserveTraitUIDs sets the UID of each trait of the supplied workload
+// statuses that has none, e.g. because it failed to apply, to that of the
+// same trait in the supplied previous workload statuses.
+func preserveTraitUIDs(ws, previous []v1alpha2.WorkloadStatus) {
+	last := make(map[runtimev1alpha1.TypedReference]types.UID)
+	for _, s := range previous {
+		for _, t := range s.Traits {
+			last[t.Reference] = t.UID
+		}
+	}
+	for i := range ws {
+		for j := range ws[i].Traits {
+			if ws[i].Traits[j].UID == "" {
+				ws[i].Traits[j].UID = last[ws[i].Traits[j].Reference]
+			}
+		}
+	}
+}
+
+// A ConditionDeduplicator removes duplicate conditions from the status of an
+// ApplicationConfiguration before it is written.
+type ConditionDeduplicator interface {
+	// Deduplicate the supplied conditions.
+	Deduplicate(c []runtimev1alpha1.Condition) []runtimev1alpha1.Condition
+}
+
+// A ConditionDeduplicatorFn removes duplicate conditions from the status of an
+// ApplicationConfiguration before it is written.
+type ConditionDeduplicatorFn func(c []runtimev1alpha1.Condition) []runtimev1alpha1.Condition
+
+// Deduplicate the supplied conditions.
+func (fn ConditionDeduplicatorFn) Deduplicate(c []runtimev1alpha1.Condition) []runtimev1alpha1.Condition {
+	return fn(c)
+}
+
+// LatestConditions keeps only the most recent of the supplied conditions of
+// each type, i.e. the one that last transitioned, or the last of those that
+// transitioned at the same time. Each type keeps the position at which it
+// first appears.
+func LatestConditions(c []runtimev1alpha1.Condition) []runtimev1alpha1.Condition {
+	if len(c) == 0 {
+		return c
+	}
+	idx := make(map[runtimev1alpha1.ConditionType]int, len(c))
+	out := make([]runtimev1alpha1.Condition, 0, len(c))
+	for _, cond := range c {
+		i, ok := idx[cond.Type]
+		if !ok {
+			idx[cond.Type] = len(out)
+			out = append(out, cond)
+			continue
+		}
+		if !cond.LastTransitionTime.Before(&out[i].LastTransitionTime) {
+			out[i] = cond
+		}
+	}
+	return out
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestPruneWorkloadStatuses(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("v")
+	workload.SetKind("workload")
+	workload.SetName("rendered")
+
+	ref := func(kind, name string) runtimev1alpha1.TypedReference {
+		return runtimev1alpha1.TypedReference{APIVersion: "v", Kind: kind, Name: name}
+	}
+	rendered := v1alpha2.WorkloadStatus{ComponentName: "rendered", Reference: ref("workload", "rendered")}
+	orphan := v1alpha2.WorkloadStatus{ComponentName: "orphan", Reference: ref("workload", "orphan")}
+	liveTrait := v1alpha2.WorkloadStatus{
+		ComponentName: "livetrait",
+		Reference:     ref("workload", "livetrait"),
+		Traits:        []v1alpha2.WorkloadTrait{{Reference: ref("trait", "live")}},
+	}
+	scoped := v1alpha2.WorkloadStatus{
+		ComponentName: "scoped",
+		Reference:     ref("workload", "scoped"),
+		Scopes:        []v1alpha2.WorkloadScope{{Reference: ref("scope", "scope")}},
+	}
+
+	// getLive returns a MockGetFn for which only the named resources exist.
+	getLive := func(names ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, _ runtime.Object) error {
+			for _, n := range names {
+				if key.Name == n {
+					return nil
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	type want struct {
+		ws  []v1alpha2.WorkloadStatus
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		ws     []v1alpha2.WorkloadStatus
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors checking whether an unrendered workload exists should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ws:     []v1alpha2.WorkloadStatus{rendered, orphan},
+			want: want{
+				ws:  []v1alpha2.WorkloadStatus{rendered, orphan},
+				err: errors.Wrapf(errBoom, errFmtGetApplied, "workload", "orphan"),
+			},
+		},
+		"PruneOrphans": {
+			reason: "Statuses of unrendered workloads with no live workload or traits should be pruned",
+			client: &test.MockClient{MockGet: getLive("live")},
+			ws:     []v1alpha2.WorkloadStatus{rendered, orphan, liveTrait, scoped},
+			want: want{
+				ws: []v1alpha2.WorkloadStatus{rendered, liveTrait, scoped},
+			},
+		},
+		"OnlyOrphans": {
+			reason: "Pruning every status should leave no workload statuses",
+			client: &test.MockClient{MockGet: getLive()},
+			ws:     []v1alpha2.WorkloadStatus{orphan},
+			want:   want{},
+		},
+		"NoOrphans": {
+			reason: "Statuses of unrendered workloads that still exist should be kept so they can be garbage collected",
+			client: &test.MockClient{MockGet: getLive("orphan")},
+			ws:     []v1alpha2.WorkloadStatus{rendered, orphan},
+			want: want{
+				ws: []v1alpha2.WorkloadStatus{rendered, orphan},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{}
+			a := &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{Workloads: tc.ws}}
+			err := r.pruneWorkloadStatuses(context.Background(), tc.client, a, []Workload{{ComponentName: "rendered", Workload: workload}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.pruneWorkloadStatuses(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ws, a.Status.Workloads); diff != "" {
+				t.Errorf("\n%s\nr.pruneWorkloadStatuses(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPreserveLastAppliedTimes(t *testing.T) {
+	then := metav1.Unix(1, 0)
+	now := metav1.Unix(2, 0)
+	applied := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "applied"}
+	timedOut := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "timedout"}
+	added := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "added"}
+
+	ws := []v1alpha2.WorkloadStatus{
+		{Reference: applied, LastAppliedTime: &now},
+		{Reference: timedOut},
+		{Reference: added},
+	}
+	previous := []v1alpha2.WorkloadStatus{
+		{Reference: applied, LastAppliedTime: &then},
+		{Reference: timedOut, LastAppliedTime: &then},
+	}
+	preserveLastAppliedTimes(ws, previous)
+
+	want := []v1alpha2.WorkloadStatus{
+		{Reference: applied, LastAppliedTime: &now},
+		{Reference: timedOut, LastAppliedTime: &then},
+		{Reference: added},
+	}
+	if diff := cmp.Diff(want, ws); diff != "" {
+		t.Errorf("preserveLastAppliedTimes(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestPreserveHealth(t *testing.T) {
+	then := metav1.Unix(1, 0)
+	probed := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "probed"}
+	added := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "workload", Name: "added"}
+	healthy := &v1alpha2.WorkloadHealth{Status: v1alpha2.HealthStatusHealthy, LastProbeTime: then}
+
+	ws := []v1alpha2.WorkloadStatus{
+		{Reference: probed},
+		{Reference: added},
+	}
+	previous := []v1alpha2.WorkloadStatus{
+		{Reference: probed, Health: healthy, LastTransitionTime: &then},
+	}
+	preserveHealth(ws, previous)
+
+	want := []v1alpha2.WorkloadStatus{
+		{Reference: probed, Health: healthy, LastTransitionTime: &then},
+		{Reference: added},
+	}
+	if diff := cmp.Diff(want, ws); diff != "" {
+		t.Errorf("preserveHealth(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestLatestConditions(t *testing.T) {
+	then := metav1.Unix(1, 0)
+	now := metav1.Unix(2, 0)
+
+	cases := map[string]struct {
+		reason string
+		c      []runtimev1alpha1.Condition
+		want   []runtimev1alpha1.Condition
+	}{
+		"NoConditions": {
+			reason: "No conditions should be returned when none are supplied",
+		},
+		"MostRecent": {
+			reason: "Only the condition of each type that last transitioned should be kept, at the position the type first appears",
+			c: []runtimev1alpha1.Condition{
+				{Type: "Synced", LastTransitionTime: now, Reason: "new"},
+				{Type: "Ready", LastTransitionTime: then},
+				{Type: "Synced", LastTransitionTime: then, Reason: "old"},
+			},
+			want: []runtimev1alpha1.Condition{
+				{Type: "Synced", LastTransitionTime: now, Reason: "new"},
+				{Type: "Ready", LastTransitionTime: then},
+			},
+		},
+		"SameTransitionTime": {
+			reason: "The last of the conditions of a type that transitioned at the same time should be kept",
+			c: []runtimev1alpha1.Condition{
+				{Type: "Synced", LastTransitionTime: now, Reason: "first"},
+				{Type: "Synced", LastTransitionTime: now, Reason: "last"},
+			},
+			want: []runtimev1alpha1.Condition{
+				{Type: "Synced", LastTransitionTime: now, Reason: "last"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := LatestConditions(tc.c)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLatestConditions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPreserveTraitUIDs(t *testing.T) {
+	applied := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "trait", Name: "applied"}
+	failed := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "trait", Name: "failed"}
+	added := runtimev1alpha1.TypedReference{APIVersion: "v", Kind: "trait", Name: "added"}
+
+	ws := []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{
+		{Reference: applied, UID: "new"},
+		{Reference: failed},
+		{Reference: added},
+	}}}
+	previous := []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{
+		{Reference: applied, UID: "old"},
+		{Reference: failed, UID: "old"},
+	}}}
+	preserveTraitUIDs(ws, previous)
+
+	want := []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{
+		{Reference: applied, UID: "new"},
+		{Reference: failed, UID: "old"},
+		{Reference: added},
+	}}}
+	if diff := cmp.Diff(want, ws); diff != "" {
+		t.Errorf("preserveTraitUIDs(...): -want, +got:\n%s", diff)
+	}
+}
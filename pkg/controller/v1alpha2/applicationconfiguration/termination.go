@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Termination grace period error strings.
+const (
+	errFmtInvalidGracePeriod = "termination grace period must be positive, got %d"
+)
+
+// podTerminationGracePeriodPath is the field path of the termination grace
+// period of workloads that embed a pod template.
+const podTerminationGracePeriodPath = "spec.template.spec.terminationGracePeriodSeconds"
+
+// terminationGracePeriod returns the termination grace period of the supplied
+// component, which defaults to that of the supplied ApplicationConfiguration.
+func terminationGracePeriod(ac *v1alpha2.ApplicationConfiguration, acc v1alpha2.ApplicationConfigurationComponent) *int64 {
+	if acc.TerminationGracePeriodSeconds != nil {
+		return acc.TerminationGracePeriodSeconds
+	}
+	return ac.Spec.TerminationGracePeriodSeconds
+}
+
+// injectTerminationGracePeriod sets the supplied termination grace period in
+// the pod template of the supplied workload. Workloads without a pod template
+// are returned unchanged. Components read from a spec source are not
+// validated by the API server, so the grace period is validated here too.
+func injectTerminationGracePeriod(w *unstructured.Unstructured, seconds *int64) error {
+	if seconds == nil {
+		return nil
+	}
+	if *seconds < 1 {
+		return errors.Errorf(errFmtInvalidGracePeriod, *seconds)
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return nil
+	}
+	return p.SetNumber(podTerminationGracePeriodPath, float64(*seconds))
+}
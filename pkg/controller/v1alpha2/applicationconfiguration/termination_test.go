@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestTerminationGracePeriod(t *testing.T) {
+	ac, comp := int64(30), int64(60)
+
+	cases := map[string]struct {
+		reason string
+		ac     *int64
+		acc    *int64
+		want   *int64
+	}{
+		"Unset": {
+			reason: "No grace period should be returned if neither the ApplicationConfiguration nor the component set one",
+		},
+		"ApplicationConfiguration": {
+			reason: "The ApplicationConfiguration's grace period should be used by default",
+			ac:     &ac,
+			want:   &ac,
+		},
+		"Component": {
+			reason: "The component's grace period should take precedence",
+			ac:     &ac,
+			acc:    &comp,
+			want:   &comp,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := terminationGracePeriod(
+				&v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{TerminationGracePeriodSeconds: tc.ac}},
+				v1alpha2.ApplicationConfigurationComponent{TerminationGracePeriodSeconds: tc.acc},
+			)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nterminationGracePeriod(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInjectTerminationGracePeriod(t *testing.T) {
+	podTemplate := func() *unstructured.Unstructured {
+		p := fieldpath.Pave(map[string]interface{}{})
+		_ = p.SetValue(podTemplateContainersPath, []interface{}{map[string]interface{}{"name": "c0"}})
+		_ = p.SetNumber(podTerminationGracePeriodPath, 10)
+		return &unstructured.Unstructured{Object: p.UnstructuredContent()}
+	}
+	period := func(s int64) *int64 { return &s }
+
+	type want struct {
+		seconds interface{}
+		err     error
+	}
+	cases := map[string]struct {
+		reason  string
+		w       *unstructured.Unstructured
+		seconds *int64
+		want    want
+	}{
+		"Unset": {
+			reason: "The workload's grace period should be retained if none is supplied",
+			w:      podTemplate(),
+			want:   want{seconds: float64(10)},
+		},
+		"Replaced": {
+			reason:  "The supplied grace period should replace the workload's",
+			w:       podTemplate(),
+			seconds: period(30),
+			want:    want{seconds: float64(30)},
+		},
+		"NoPodTemplate": {
+			reason:  "Workloads without a pod template should be returned unchanged",
+			w:       &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}},
+			seconds: period(30),
+			want:    want{},
+		},
+		"NotPositive": {
+			reason:  "Grace periods that are not positive should be rejected",
+			w:       podTemplate(),
+			seconds: period(0),
+			want:    want{seconds: float64(10), err: errors.Errorf(errFmtInvalidGracePeriod, 0)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := injectTerminationGracePeriod(tc.w, tc.seconds)
+			got, _ := fieldpath.Pave(tc.w.UnstructuredContent()).GetValue(podTerminationGracePeriodPath)
+			if diff := cmp.Diff(tc.want, want{seconds: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectTerminationGracePeriod(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
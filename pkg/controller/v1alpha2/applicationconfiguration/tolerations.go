@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Toleration error strings.
+const (
+	errParseTolerations   = "cannot parse pod template tolerations"
+	errConvertTolerations = "cannot convert tolerations"
+)
+
+// podTolerationsPath is the field path of the tolerations of workloads that
+// embed a pod template.
+const podTolerationsPath = "spec.template.spec.tolerations"
+
+// injectTolerations merges the supplied tolerations into the pod template of
+// the supplied workload. Tolerations with the same key, operator, and value as
+// one already in the pod template are not added. It returns false if there
+// are tolerations to inject but the workload has no pod template.
+func injectTolerations(w *unstructured.Unstructured, t []corev1.Toleration) (bool, error) {
+	if len(t) == 0 {
+		return true, nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	if _, err := p.GetValue(podSpecPath); err != nil {
+		// The workload has no pod template.
+		return false, nil
+	}
+
+	existing := make([]interface{}, 0)
+	if v, err := p.GetValue(podTolerationsPath); err == nil {
+		l, ok := v.([]interface{})
+		if !ok {
+			return false, errors.New(errParseTolerations)
+		}
+		existing = l
+	}
+
+	seen := make(map[string]bool, len(existing)+len(t))
+	for _, e := range existing {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return false, errors.New(errParseTolerations)
+		}
+		tol := corev1.Toleration{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &tol); err != nil {
+			return false, errors.Wrap(err, errParseTolerations)
+		}
+		seen[tolerationKey(tol)] = true
+	}
+
+	merged := existing
+	for i := range t {
+		if seen[tolerationKey(t[i])] {
+			continue
+		}
+		seen[tolerationKey(t[i])] = true
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&t[i])
+		if err != nil {
+			return false, errors.Wrap(err, errConvertTolerations)
+		}
+		merged = append(merged, m)
+	}
+	return true, p.SetValue(podTolerationsPath, merged)
+}
+
+// tolerationKey identifies a toleration by its key, operator, and value.
+func tolerationKey(t corev1.Toleration) string {
+	return fmt.Sprintf("%s/%s/%s", t.Key, t.Operator, t.Value)
+}
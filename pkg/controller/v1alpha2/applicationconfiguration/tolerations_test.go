@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInjectTolerations(t *testing.T) {
+	workload := func(tolerations ...interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "c0"}}}
+		if len(tolerations) > 0 {
+			spec["tolerations"] = tolerations
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+	dedicated := map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "tenant-a", "effect": "NoSchedule"}
+	gpu := map[string]interface{}{"key": "gpu", "operator": "Exists", "effect": "NoSchedule"}
+
+	type want struct {
+		w        *unstructured.Unstructured
+		injected bool
+	}
+	cases := map[string]struct {
+		reason      string
+		w           *unstructured.Unstructured
+		tolerations []corev1.Toleration
+		want        want
+	}{
+		"NoTolerations": {
+			reason: "A workload should be unchanged when no tolerations are supplied",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: true},
+		},
+		"Merged": {
+			reason: "Tolerations should be appended to those of the pod template, unless they have the same key, operator, and value",
+			w:      workload(dedicated),
+			tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "tenant-a", Effect: corev1.TaintEffectNoExecute},
+				{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			want: want{w: workload(dedicated, gpu), injected: true},
+		},
+		"NoPodTemplate": {
+			reason:      "A workload without a pod template should be unchanged, and reported as such",
+			w:           &unstructured.Unstructured{Object: map[string]interface{}{}},
+			tolerations: []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+			want:        want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, injected: false},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			injected, err := injectTolerations(tc.w, tc.tolerations)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectTolerations(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, injected: injected}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectTolerations(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// topology returns the topology of the supplied workloads.
+func topology(w []Workload) *v1alpha2.Topology {
+	t := &v1alpha2.Topology{
+		Nodes: make(map[string]runtimev1alpha1.TypedReference),
+		Edges: make(map[string][]v1alpha2.TopologyEdge),
+	}
+	for _, wl := range w {
+		ws := wl.Status()
+		from := nodeID(ws.Reference)
+		t.Nodes[from] = ws.Reference
+		edges := make([]v1alpha2.TopologyEdge, 0, len(ws.Traits)+len(ws.Scopes))
+		for _, tr := range ws.Traits {
+			to := nodeID(tr.Reference)
+			t.Nodes[to] = tr.Reference
+			edges = append(edges, v1alpha2.TopologyEdge{To: to, Type: v1alpha2.TopologyEdgeTrait})
+		}
+		for _, s := range ws.Scopes {
+			to := nodeID(s.Reference)
+			t.Nodes[to] = s.Reference
+			edges = append(edges, v1alpha2.TopologyEdge{To: to, Type: v1alpha2.TopologyEdgeScope})
+		}
+		if len(edges) > 0 {
+			t.Edges[from] = edges
+		}
+	}
+	return t
+}
+
+// nodeID returns the ID of the topology node that represents the supplied
+// object.
+func nodeID(r runtimev1alpha1.TypedReference) string {
+	return r.APIVersion + "/" + r.Kind + "/" + r.Name
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestTopology(t *testing.T) {
+	object := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+
+	w := []Workload{
+		{
+			Workload: object("v", "Workload", "a"),
+			Traits:   []unstructured.Unstructured{*object("v", "Trait", "t")},
+			Scopes:   []unstructured.Unstructured{*object("v", "Scope", "s")},
+		},
+		{
+			Workload: object("v", "Workload", "b"),
+			Scopes:   []unstructured.Unstructured{*object("v", "Scope", "s")},
+		},
+		{
+			Workload: object("v", "Workload", "c"),
+		},
+	}
+
+	want := &v1alpha2.Topology{
+		Nodes: map[string]runtimev1alpha1.TypedReference{
+			"v/Workload/a": {APIVersion: "v", Kind: "Workload", Name: "a"},
+			"v/Workload/b": {APIVersion: "v", Kind: "Workload", Name: "b"},
+			"v/Workload/c": {APIVersion: "v", Kind: "Workload", Name: "c"},
+			"v/Trait/t":    {APIVersion: "v", Kind: "Trait", Name: "t"},
+			"v/Scope/s":    {APIVersion: "v", Kind: "Scope", Name: "s"},
+		},
+		Edges: map[string][]v1alpha2.TopologyEdge{
+			"v/Workload/a": {
+				{To: "v/Trait/t", Type: v1alpha2.TopologyEdgeTrait},
+				{To: "v/Scope/s", Type: v1alpha2.TopologyEdgeScope},
+			},
+			"v/Workload/b": {
+				{To: "v/Scope/s", Type: v1alpha2.TopologyEdgeScope},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, topology(w)); diff != "" {
+		t.Errorf("topology(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clientappv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Traffic split error strings.
+const (
+	errFmtRenderTrafficSplit  = "cannot render traffic split of component %q"
+	errFmtGetStableDeployment = "cannot get stable deployment %q"
+	errFmtApplyTrafficSplit   = "cannot apply traffic split of workload %q"
+	errFmtScaleDeployment     = "cannot scale deployment %q"
+	errFmtRemoveTrafficSplit  = "cannot remove traffic split of workload %q"
+	errNoDeploymentScaler     = "no deployment scaler is configured"
+)
+
+// Traffic tracks.
+const (
+	trafficTrackStable = "stable"
+	trafficTrackCanary = "canary"
+)
+
+var deploymentGroupVersionKind = appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+// A TrafficSplit is the canary Deployment and Services that are applied
+// alongside a Deployment workload whose component has a traffic weight.
+type TrafficSplit struct {
+	// Canary Deployment, running the rendered pod template.
+	Canary *unstructured.Unstructured
+
+	// Services selecting the pods of the stable and canary Deployments. There
+	// are none if the workload's containers expose no ports.
+	Services []unstructured.Unstructured
+
+	// StableReplicas and CanaryReplicas are the replicas of the stable and
+	// canary Deployments.
+	StableReplicas int32
+	CanaryReplicas int32
+
+	// Promoted is true if the stable Deployment receives no weight, in which
+	// case it too is updated to the rendered pod template.
+	Promoted bool
+}
+
+// DeepCopy returns a deep copy of this traffic split.
+func (t *TrafficSplit) DeepCopy() *TrafficSplit {
+	out := *t
+	out.Canary = t.Canary.DeepCopy()
+	out.Services = make([]unstructured.Unstructured, len(t.Services))
+	for i := range t.Services {
+		t.Services[i].DeepCopyInto(&out.Services[i])
+	}
+	return &out
+}
+
+// Status returns the status of this traffic split.
+func (t *TrafficSplit) Status() *v1alpha2.WorkloadTrafficSplit {
+	return &v1alpha2.WorkloadTrafficSplit{
+		CanaryDeploymentName: t.Canary.GetName(),
+		StableReplicas:       t.StableReplicas,
+		CanaryReplicas:       t.CanaryReplicas,
+	}
+}
+
+// renderTrafficSplit renders the traffic split of the supplied workload, which
+// becomes the stable Deployment, or returns nil if it has none. The replicas
+// of the workload are removed; they are set by scaling the stable and canary
+// Deployments once they are applied. It returns false if a traffic split is to
+// be rendered but the workload is not a Deployment with a label selector.
+func renderTrafficSplit(w *unstructured.Unstructured, tw *v1alpha2.ComponentTrafficWeight) (*TrafficSplit, bool, error) {
+	if tw == nil {
+		return nil, true, nil
+	}
+	if w.GroupVersionKind() != deploymentGroupVersionKind {
+		return nil, false, nil
+	}
+	selector, found, err := unstructured.NestedStringMap(w.Object, "spec", "selector", "matchLabels")
+	if err != nil {
+		return nil, false, err
+	}
+	if !found || len(selector) == 0 {
+		return nil, false, nil
+	}
+
+	replicas, found, err := unstructured.NestedInt64(w.Object, "spec", "replicas")
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		// The default replicas of a Deployment.
+		replicas = 1
+	}
+	unstructured.RemoveNestedField(w.Object, "spec", "replicas")
+
+	canary := w.DeepCopy()
+	canary.SetName(canaryDeploymentName(w.GetName()))
+	if err := unstructured.SetNestedField(canary.Object, trafficTrackCanary, "spec", "selector", "matchLabels", oam.LabelTrafficTrack); err != nil {
+		return nil, false, err
+	}
+	if err := setTrafficTrack(canary, trafficTrackCanary); err != nil {
+		return nil, false, err
+	}
+	if err := setTrafficTrack(w, trafficTrackStable); err != nil {
+		return nil, false, err
+	}
+
+	services := make([]unstructured.Unstructured, 0, 2)
+	ports, err := containerPorts(w)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(ports) > 0 {
+		for _, track := range []string{trafficTrackStable, trafficTrackCanary} {
+			services = append(services, *renderTrackService(w.GetName(), track, selector, ports))
+		}
+	}
+
+	return &TrafficSplit{
+		Canary:         canary,
+		Services:       services,
+		StableReplicas: int32(replicas * int64(tw.Stable) / 100),
+		CanaryReplicas: int32(replicas * int64(tw.Canary) / 100),
+		Promoted:       tw.Stable == 0,
+	}, true, nil
+}
+
+func canaryDeploymentName(name string) string {
+	return name + "-" + trafficTrackCanary
+}
+
+func trackServiceName(name, track string) string {
+	return name + "-" + track
+}
+
+// setTrafficTrack labels the pod template of the supplied Deployment with the
+// supplied traffic track.
+func setTrafficTrack(d *unstructured.Unstructured, track string) error {
+	return unstructured.SetNestedField(d.Object, track, "spec", "template", "metadata", "labels", oam.LabelTrafficTrack)
+}
+
+// containerPorts returns the ports of the supplied Deployment's containers as
+// Service ports.
+func containerPorts(d *unstructured.Unstructured) ([]interface{}, error) {
+	containers, _, err := unstructured.NestedSlice(d.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]interface{}, 0)
+	for _, c := range containers {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cp, _, err := unstructured.NestedSlice(cm, "ports")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range cp {
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			port, ok := pm["containerPort"]
+			if !ok {
+				continue
+			}
+			sp := map[string]interface{}{"port": port, "targetPort": port}
+			for _, f := range []string{"name", "protocol"} {
+				if v, ok := pm[f]; ok {
+					sp[f] = v
+				}
+			}
+			ports = append(ports, sp)
+		}
+	}
+	return ports, nil
+}
+
+// renderTrackService renders the Service that selects the pods of the
+// supplied traffic track of the supplied Deployment. Its namespace is set when
+// it is applied.
+func renderTrackService(name, track string, selector map[string]string, ports []interface{}) *unstructured.Unstructured {
+	sel := make(map[string]interface{}, len(selector)+1)
+	for k, v := range selector {
+		sel[k] = v
+	}
+	sel[oam.LabelTrafficTrack] = track
+
+	s := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": sel,
+			"ports":    ports,
+		},
+	}}
+	s.SetAPIVersion("v1")
+	s.SetKind("Service")
+	s.SetName(trackServiceName(name, track))
+	return s
+}
+
+// A DeploymentScaler scales Deployments using their scale subresource.
+type DeploymentScaler interface {
+	Scale(ctx context.Context, namespace, name string, replicas int32) error
+}
+
+// A DeploymentScaleFn scales Deployments using their scale subresource.
+type DeploymentScaleFn func(ctx context.Context, namespace, name string, replicas int32) error
+
+// Scale the supplied Deployment to the supplied replicas.
+func (fn DeploymentScaleFn) Scale(ctx context.Context, namespace, name string, replicas int32) error {
+	return fn(ctx, namespace, name, replicas)
+}
+
+type deploymentScaler struct {
+	client clientappv1.DeploymentsGetter
+}
+
+// Scale the supplied Deployment by patching the replicas of its scale
+// subresource, rather than updating its spec.
+func (s *deploymentScaler) Scale(ctx context.Context, namespace, name string, replicas int32) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	_, err := s.client.Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "scale")
+	return errors.Wrapf(err, errFmtScaleDeployment, name)
+}
+
+// preserveStableTemplate sets the pod template of the supplied workload, which
+// is the stable Deployment of a traffic split, to that of the live stable
+// Deployment, so that updates are only rolled out to the canary Deployment
+// until the stable Deployment is promoted.
+func (a *workloads) preserveStableTemplate(ctx context.Context, wl Workload) error {
+	if wl.TrafficSplit == nil || wl.TrafficSplit.Promoted {
+		return nil
+	}
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(wl.Workload.GroupVersionKind())
+	err := a.rawClient.Get(ctx, types.NamespacedName{Namespace: wl.Workload.GetNamespace(), Name: wl.Workload.GetName()}, live)
+	if kerrors.IsNotFound(err) {
+		// The stable Deployment is created with the rendered pod template.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetStableDeployment, wl.Workload.GetName())
+	}
+	template, found, err := unstructured.NestedMap(live.Object, "spec", "template")
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetStableDeployment, wl.Workload.GetName())
+	}
+	if !found {
+		return nil
+	}
+	if err := unstructured.SetNestedMap(wl.Workload.Object, template, "spec", "template"); err != nil {
+		return errors.Wrapf(err, errFmtGetStableDeployment, wl.Workload.GetName())
+	}
+	return errors.Wrapf(setTrafficTrack(wl.Workload, trafficTrackStable), errFmtGetStableDeployment, wl.Workload.GetName())
+}
+
+// applyTrafficSplit applies the canary Deployment and Services of the supplied
+// workload, if any, controlled by the applied workload, then scales the stable
+// and canary Deployments to their share of the workload's replicas.
+func (a *workloads) applyTrafficSplit(ctx context.Context, applicator resource.Applicator, wl Workload) error {
+	if wl.TrafficSplit == nil {
+		return nil
+	}
+	if a.scaler == nil {
+		return errors.Wrapf(errors.New(errNoDeploymentScaler), errFmtApplyTrafficSplit, wl.Workload.GetName())
+	}
+	ref := metav1.NewControllerRef(wl.Workload, wl.Workload.GroupVersionKind())
+	objs := append([]unstructured.Unstructured{*wl.TrafficSplit.Canary}, wl.TrafficSplit.Services...)
+	for i := range objs {
+		o := objs[i].DeepCopy()
+		o.SetNamespace(wl.Workload.GetNamespace())
+		o.SetOwnerReferences([]metav1.OwnerReference{*ref})
+		err := applicator.Apply(ctx, o, resource.MustBeControllableBy(wl.Workload.GetUID()))
+		err = explainForbidden(err, wl.ServiceAccountName, o.GetKind(), o.GetName())
+		if err != nil {
+			return errors.Wrapf(err, errFmtApplyTrafficSplit, wl.Workload.GetName())
+		}
+	}
+
+	ns := wl.Workload.GetNamespace()
+	if err := a.scaler.Scale(ctx, ns, wl.Workload.GetName(), wl.TrafficSplit.StableReplicas); err != nil {
+		return errors.Wrapf(err, errFmtApplyTrafficSplit, wl.Workload.GetName())
+	}
+	return errors.Wrapf(a.scaler.Scale(ctx, ns, wl.TrafficSplit.Canary.GetName(), wl.TrafficSplit.CanaryReplicas), errFmtApplyTrafficSplit, wl.Workload.GetName())
+}
+
+// removeTrafficSplits deletes the canary Deployments and Services of the
+// supplied workloads whose components no longer have a traffic weight, as
+// recorded in the supplied workload statuses.
+func (a *workloads) removeTrafficSplits(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload) error {
+	// Workloads without a traffic split, by name, in their namespace.
+	rendered := make(map[string]string, len(w))
+	for _, wl := range w {
+		if wl.TrafficSplit == nil {
+			rendered[wl.Workload.GetName()] = wl.Workload.GetNamespace()
+		}
+	}
+	for _, s := range status {
+		ns, ok := rendered[s.Reference.Name]
+		if s.TrafficSplit == nil || !ok {
+			continue
+		}
+		canary := &unstructured.Unstructured{}
+		canary.SetGroupVersionKind(deploymentGroupVersionKind)
+		canary.SetNamespace(ns)
+		canary.SetName(s.TrafficSplit.CanaryDeploymentName)
+		del := []*unstructured.Unstructured{canary}
+		for _, track := range []string{trafficTrackStable, trafficTrackCanary} {
+			svc := &unstructured.Unstructured{}
+			svc.SetAPIVersion("v1")
+			svc.SetKind("Service")
+			svc.SetNamespace(ns)
+			svc.SetName(trackServiceName(s.Reference.Name, track))
+			del = append(del, svc)
+		}
+		for _, o := range del {
+			if err := a.rawClient.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errFmtRemoveTrafficSplit, s.Reference.Name)
+			}
+		}
+	}
+	return nil
+}
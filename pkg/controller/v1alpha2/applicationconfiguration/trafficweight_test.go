@@ -0,0 +1,345 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestRenderTrafficSplit(t *testing.T) {
+	deployment := func(name string, replicas interface{}, track string) *unstructured.Unstructured {
+		selector := map[string]interface{}{"app": "web"}
+		template := map[string]interface{}{
+			"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{
+				"name":  "web",
+				"ports": []interface{}{map[string]interface{}{"name": "http", "containerPort": int64(8080)}},
+			}}},
+		}
+		if track != "" {
+			template["metadata"] = map[string]interface{}{"labels": map[string]interface{}{oam.LabelTrafficTrack: track}}
+		}
+		if track == trafficTrackCanary {
+			selector[oam.LabelTrafficTrack] = track
+		}
+		spec := map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": selector},
+			"template": template,
+		}
+		if replicas != nil {
+			spec["replicas"] = replicas
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       spec,
+		}}
+	}
+	service := func(track string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "web-" + track},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"app": "web", oam.LabelTrafficTrack: track},
+				"ports":    []interface{}{map[string]interface{}{"name": "http", "port": int64(8080), "targetPort": int64(8080)}},
+			},
+		}}
+	}
+
+	type want struct {
+		w         *unstructured.Unstructured
+		split     *TrafficSplit
+		supported bool
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		tw     *v1alpha2.ComponentTrafficWeight
+		want   want
+	}{
+		"NoTrafficWeight": {
+			reason: "No traffic split should be rendered when the component does not specify a traffic weight",
+			w:      deployment("web", int64(10), ""),
+			want:   want{w: deployment("web", int64(10), ""), supported: true},
+		},
+		"Split": {
+			reason: "The replicas of the workload should be split between the stable and canary Deployments, each selected by a Service",
+			w:      deployment("web", int64(10), ""),
+			tw:     &v1alpha2.ComponentTrafficWeight{Stable: 80, Canary: 20},
+			want: want{
+				w: deployment("web", nil, trafficTrackStable),
+				split: &TrafficSplit{
+					Canary:         deployment("web-canary", nil, trafficTrackCanary),
+					Services:       []unstructured.Unstructured{service(trafficTrackStable), service(trafficTrackCanary)},
+					StableReplicas: 8,
+					CanaryReplicas: 2,
+				},
+				supported: true,
+			},
+		},
+		"Promoted": {
+			reason: "A stable Deployment that receives no weight should be promoted",
+			w:      deployment("web", nil, ""),
+			tw:     &v1alpha2.ComponentTrafficWeight{Stable: 0, Canary: 100},
+			want: want{
+				w: deployment("web", nil, trafficTrackStable),
+				split: &TrafficSplit{
+					Canary:         deployment("web-canary", nil, trafficTrackCanary),
+					Services:       []unstructured.Unstructured{service(trafficTrackStable), service(trafficTrackCanary)},
+					StableReplicas: 0,
+					CanaryReplicas: 1,
+					Promoted:       true,
+				},
+				supported: true,
+			},
+		},
+		"NotDeployment": {
+			reason: "A workload that is not a Deployment should be reported as unsupported",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": "StatefulSet"}},
+			tw:     &v1alpha2.ComponentTrafficWeight{Stable: 50, Canary: 50},
+			want: want{
+				w:         &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": "StatefulSet"}},
+				supported: false,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			split, supported, err := renderTrafficSplit(tc.w, tc.tw)
+			if err != nil {
+				t.Fatalf("\n%s\nrenderTrafficSplit(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{w: tc.w, split: split, supported: supported}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nrenderTrafficSplit(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyTrafficSplit(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := func() Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetNamespace("ns")
+		w.SetName("web")
+		w.SetUID("web-uid")
+		canary := &unstructured.Unstructured{}
+		canary.SetAPIVersion("apps/v1")
+		canary.SetKind("Deployment")
+		canary.SetName("web-canary")
+		svc := unstructured.Unstructured{}
+		svc.SetAPIVersion("v1")
+		svc.SetKind("Service")
+		svc.SetName("web-canary")
+		return Workload{Workload: w, TrafficSplit: &TrafficSplit{
+			Canary:         canary,
+			Services:       []unstructured.Unstructured{svc},
+			StableReplicas: 3,
+			CanaryReplicas: 1,
+		}}
+	}
+
+	type scaled struct {
+		Namespace string
+		Name      string
+		Replicas  int32
+	}
+	type want struct {
+		applied []string
+		scaled  []scaled
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		apply  error
+		scale  error
+		want   want
+	}{
+		"Applied": {
+			reason: "The canary Deployment and Services should be applied, then the stable and canary Deployments scaled",
+			want: want{
+				applied: []string{"Deployment/web-canary", "Service/web-canary"},
+				scaled:  []scaled{{"ns", "web", 3}, {"ns", "web-canary", 1}},
+			},
+		},
+		"ApplyError": {
+			reason: "Errors applying the canary Deployment should be returned",
+			apply:  errBoom,
+			want: want{
+				applied: []string{"Deployment/web-canary"},
+				err:     errors.Wrapf(errBoom, errFmtApplyTrafficSplit, "web"),
+			},
+		},
+		"ScaleError": {
+			reason: "Errors scaling the stable Deployment should be returned",
+			scale:  errBoom,
+			want: want{
+				applied: []string{"Deployment/web-canary", "Service/web-canary"},
+				scaled:  []scaled{{"ns", "web", 3}},
+				err:     errors.Wrapf(errBoom, errFmtApplyTrafficSplit, "web"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			a := &workloads{scaler: DeploymentScaleFn(func(_ context.Context, namespace, name string, replicas int32) error {
+				got.scaled = append(got.scaled, scaled{namespace, name, replicas})
+				return tc.scale
+			})}
+			applicator := resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				u := o.(*unstructured.Unstructured)
+				if u.GetNamespace() != "ns" || len(u.GetOwnerReferences()) != 1 || u.GetOwnerReferences()[0].UID != "web-uid" {
+					t.Errorf("\n%s\napplyTrafficSplit(...): want %s to be controlled by its workload, in its namespace", tc.reason, u.GetName())
+				}
+				got.applied = append(got.applied, u.GetKind()+"/"+u.GetName())
+				return tc.apply
+			})
+			got.err = a.applyTrafficSplit(context.Background(), applicator, workload())
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napplyTrafficSplit(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPreserveStableTemplate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := func(image string, promoted bool) Workload {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": image}}},
+			}},
+		}}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetName("web")
+		return Workload{Workload: w, TrafficSplit: &TrafficSplit{Promoted: promoted}}
+	}
+	stable := func(image string) Workload {
+		w := workload(image, false)
+		_ = setTrafficTrack(w.Workload, trafficTrackStable)
+		return w
+	}
+	live := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		u.Object["spec"] = workload("v1", false).Workload.Object["spec"]
+		return nil
+	})
+
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		wl     Workload
+		get    test.MockGetFn
+		want   want
+	}{
+		"Preserved": {
+			reason: "The live pod template of the stable Deployment should be preserved",
+			wl:     workload("v2", false),
+			get:    live,
+			want:   want{w: stable("v1").Workload},
+		},
+		"Promoted": {
+			reason: "A promoted stable Deployment should be updated to the rendered pod template",
+			wl:     workload("v2", true),
+			get:    live,
+			want:   want{w: workload("v2", true).Workload},
+		},
+		"NotFound": {
+			reason: "A stable Deployment that does not exist yet should be created with the rendered pod template",
+			wl:     workload("v2", false),
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "web")),
+			want:   want{w: workload("v2", false).Workload},
+		},
+		"GetError": {
+			reason: "Errors getting the stable Deployment should be returned",
+			wl:     workload("v2", false),
+			get:    test.NewMockGetFn(errBoom),
+			want: want{
+				w:   workload("v2", false).Workload,
+				err: errors.Wrapf(errBoom, errFmtGetStableDeployment, "web"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := &workloads{rawClient: &test.MockClient{MockGet: tc.get}}
+			err := a.preserveStableTemplate(context.Background(), tc.wl)
+			if diff := cmp.Diff(tc.want, want{w: tc.wl.Workload, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\npreserveStableTemplate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveTrafficSplits(t *testing.T) {
+	w := &unstructured.Unstructured{}
+	w.SetNamespace("ns")
+	w.SetName("web")
+	status := []v1alpha2.WorkloadStatus{{
+		Reference:    runtimev1alpha1.TypedReference{Name: "web"},
+		TrafficSplit: &v1alpha2.WorkloadTrafficSplit{CanaryDeploymentName: "web-canary"},
+	}}
+
+	var deleted []string
+	a := &workloads{rawClient: &test.MockClient{MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+		u := obj.(*unstructured.Unstructured)
+		deleted = append(deleted, u.GetNamespace()+"/"+u.GetKind()+"/"+u.GetName())
+		return kerrors.NewNotFound(schema.GroupResource{}, u.GetName())
+	}}}
+	if err := a.removeTrafficSplits(context.Background(), status, []Workload{{Workload: w}}); err != nil {
+		t.Fatalf("removeTrafficSplits(...): unexpected error: %s", err)
+	}
+	want := []string{"ns/Deployment/web-canary", "ns/Service/web-stable", "ns/Service/web-canary"}
+	if diff := cmp.Diff(want, deleted); diff != "" {
+		t.Errorf("removeTrafficSplits(...): -want, +got:\n%s", diff)
+	}
+
+	deleted = nil
+	split := Workload{Workload: w, TrafficSplit: &TrafficSplit{}}
+	if err := a.removeTrafficSplits(context.Background(), status, []Workload{split}); err != nil {
+		t.Fatalf("removeTrafficSplits(...): unexpected error: %s", err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("removeTrafficSplits(...): want traffic splits that are still rendered to be kept, deleted %v", deleted)
+	}
+}
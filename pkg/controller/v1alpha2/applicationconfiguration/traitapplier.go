@@ -0,0 +1,249 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/traits/certmanager"
+)
+
+// Trait applier error strings.
+const (
+	errSetScaleTargetRef = "cannot set scale target reference"
+
+	errFmtGetReadinessTrait = "cannot get trait %q to check its readiness"
+)
+
+// KEDAScaledObjectGroupVersionKind is the kind of a KEDA ScaledObject.
+var KEDAScaledObjectGroupVersionKind = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+
+// A TraitApplier manages the lifecycle of traits of a particular kind whose
+// controller requires more than a plain apply, e.g. because they must be
+// deleted before the workload they scale.
+type TraitApplier interface {
+	// Apply the supplied trait of the supplied workload, creating it if it
+	// does not exist and updating it otherwise.
+	Apply(ctx context.Context, c client.Client, t, w *unstructured.Unstructured, ao ...resource.ApplyOption) error
+
+	// Delete the supplied trait.
+	Delete(ctx context.Context, c client.Client, t *unstructured.Unstructured) error
+}
+
+// A TraitReadinessChecker is a TraitApplier that can tell whether the traits
+// it applies are ready, e.g. because the controller of a trait reports its
+// progress in its status.
+type TraitReadinessChecker interface {
+	TraitApplier
+
+	// Ready returns true if the supplied trait is ready. Otherwise it returns
+	// a message explaining why it is not.
+	Ready(t *unstructured.Unstructured) (bool, string)
+}
+
+// A TraitApplierRegistry looks up the TraitApplier registered for a kind of
+// trait. Traits of kinds without a registered TraitApplier are applied and
+// deleted like any other resource. A nil registry has no TraitAppliers.
+type TraitApplierRegistry struct {
+	appliers map[schema.GroupVersionKind]TraitApplier
+}
+
+// NewTraitApplierRegistry returns an empty TraitApplierRegistry.
+func NewTraitApplierRegistry() *TraitApplierRegistry {
+	return &TraitApplierRegistry{appliers: make(map[schema.GroupVersionKind]TraitApplier)}
+}
+
+// Register the supplied TraitApplier for the supplied kind of trait.
+func (r *TraitApplierRegistry) Register(gvk schema.GroupVersionKind, a TraitApplier) {
+	r.appliers[gvk] = a
+}
+
+// Lookup the TraitApplier registered for the supplied kind of trait.
+func (r *TraitApplierRegistry) Lookup(gvk schema.GroupVersionKind) (TraitApplier, bool) {
+	if r == nil {
+		return nil, false
+	}
+	a, ok := r.appliers[gvk]
+	return a, ok
+}
+
+// Delete the supplied resource, using its TraitApplier if one is registered.
+// A resource with a UID is only deleted if it still has that UID; a resource
+// that was since replaced by another of the same name is left alone.
+func (r *TraitApplierRegistry) Delete(ctx context.Context, c client.Client, o *unstructured.Unstructured) error {
+	var err error
+	if a, ok := r.Lookup(o.GroupVersionKind()); ok {
+		err = a.Delete(ctx, c, o)
+	} else {
+		err = c.Delete(ctx, o, deleteOptions(o)...)
+	}
+	if o.GetUID() != "" && kerrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteOptions returns options that delete the supplied resource only if it
+// still has its UID, if it has one.
+func deleteOptions(o *unstructured.Unstructured) []client.DeleteOption {
+	uid := o.GetUID()
+	if uid == "" {
+		return nil
+	}
+	return []client.DeleteOption{client.Preconditions{UID: &uid}}
+}
+
+// DeletionOrder returns the supplied resources ordered such that traits with a
+// registered TraitApplier are deleted before the workloads they may depend on.
+// The order of the resources is otherwise unchanged.
+func (r *TraitApplierRegistry) DeletionOrder(objs []unstructured.Unstructured) []unstructured.Unstructured {
+	ordered := make([]unstructured.Unstructured, 0, len(objs))
+	var rest []unstructured.Unstructured
+	for _, o := range objs {
+		if _, ok := r.Lookup(o.GroupVersionKind()); ok {
+			ordered = append(ordered, o)
+			continue
+		}
+		rest = append(rest, o)
+	}
+	return append(ordered, rest...)
+}
+
+// defaultTraitAppliers returns a TraitApplierRegistry that manages the
+// lifecycle of KEDA ScaledObjects, cert-manager Certificates, and Istio
+// VirtualServices.
+func defaultTraitAppliers() *TraitApplierRegistry {
+	r := NewTraitApplierRegistry()
+	r.Register(KEDAScaledObjectGroupVersionKind, &KEDAScaledObjectApplier{})
+	r.Register(certmanager.CertificateGroupVersionKind, &certmanager.CertificateTrait{})
+	for _, gvk := range IstioVirtualServiceGroupVersionKinds {
+		r.Register(gvk, &VirtualServiceApplier{})
+	}
+	return r
+}
+
+// observeTraitReadiness records whether each trait of the supplied
+// ApplicationConfiguration whose TraitApplier is a TraitReadinessChecker is
+// ready, as the Ready condition of its trait status.
+func observeTraitReadiness(ctx context.Context, c client.Reader, r *TraitApplierRegistry, ac *v1alpha2.ApplicationConfiguration) error {
+	for i := range ac.Status.Workloads {
+		for j := range ac.Status.Workloads[i].Traits {
+			ts := &ac.Status.Workloads[i].Traits[j]
+			a, ok := r.Lookup(schema.FromAPIVersionAndKind(ts.Reference.APIVersion, ts.Reference.Kind))
+			if !ok {
+				continue
+			}
+			rc, ok := a.(TraitReadinessChecker)
+			if !ok {
+				continue
+			}
+			t := asUnstructured(ts.Reference, ac.GetNamespace())
+			err := c.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ts.Reference.Name}, t)
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, errFmtGetReadinessTrait, ts.Reference.Name)
+			}
+			ready, msg := rc.Ready(t)
+			if ready {
+				ts.SetConditions(runtimev1alpha1.Available())
+				continue
+			}
+			cond := runtimev1alpha1.Unavailable()
+			cond.Message = msg
+			ts.SetConditions(cond)
+		}
+	}
+	return nil
+}
+
+// A KEDAScaledObjectApplier manages the lifecycle of KEDA ScaledObjects. A
+// ScaledObject is owned by the workload it scales, so that it is garbage
+// collected along with the workload, and targets that workload unless it
+// specifies a scale target. KEDA restores the replicas of a ScaledObject's
+// target when the ScaledObject is deleted, so ScaledObjects must be deleted
+// before their workload.
+type KEDAScaledObjectApplier struct{}
+
+// Apply the supplied ScaledObject of the supplied workload. A ScaledObject
+// that does not exist is created. One that exists is updated using a JSON
+// merge patch, preserving the finalizers and labels added by KEDA.
+func (a *KEDAScaledObjectApplier) Apply(ctx context.Context, c client.Client, t, w *unstructured.Unstructured, ao ...resource.ApplyOption) error {
+	if err := setScaleTargetRef(t, w); err != nil {
+		return errors.Wrap(err, errSetScaleTargetRef)
+	}
+	if w.GetUID() != "" {
+		meta.AddOwnerReference(t, metav1.OwnerReference{
+			APIVersion: w.GetAPIVersion(),
+			Kind:       w.GetKind(),
+			Name:       w.GetName(),
+			UID:        w.GetUID(),
+		})
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(t.GroupVersionKind())
+	err := c.Get(ctx, types.NamespacedName{Namespace: t.GetNamespace(), Name: t.GetName()}, current)
+	if kerrors.IsNotFound(err) {
+		return errors.Wrap(c.Create(ctx, t), errCreateObject)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetObject)
+	}
+
+	for _, fn := range ao {
+		if err := fn(ctx, current, t); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(c.Patch(ctx, t, client.Merge), errPatchObject)
+}
+
+// Delete the supplied ScaledObject. Its deletion completes once KEDA has
+// restored the replicas of its target.
+func (a *KEDAScaledObjectApplier) Delete(ctx context.Context, c client.Client, t *unstructured.Unstructured) error {
+	return c.Delete(ctx, t, deleteOptions(t)...)
+}
+
+// setScaleTargetRef targets the supplied workload, unless the supplied
+// ScaledObject already specifies a scale target.
+func setScaleTargetRef(t, w *unstructured.Unstructured) error {
+	p := fieldpath.Pave(t.UnstructuredContent())
+	if name, _ := p.GetString("spec.scaleTargetRef.name"); name != "" {
+		return nil
+	}
+	return p.SetValue("spec.scaleTargetRef", map[string]interface{}{
+		"apiVersion": w.GetAPIVersion(),
+		"kind":       w.GetKind(),
+		"name":       w.GetName(),
+	})
+}
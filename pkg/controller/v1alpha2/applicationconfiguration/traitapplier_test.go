@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestKEDAScaledObjectApplier(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("apps/v1")
+	workload.SetKind("Deployment")
+	workload.SetName("workload")
+	workload.SetUID(types.UID("workload-uid"))
+
+	scaledObject := func(spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		u.SetGroupVersionKind(KEDAScaledObjectGroupVersionKind)
+		u.SetNamespace("ns")
+		u.SetName("scaler")
+		return u
+	}
+	targetWorkload := map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "workload"}
+
+	type want struct {
+		ops            []string
+		scaleTargetRef interface{}
+		owners         int
+		err            error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		t      *unstructured.Unstructured
+		ao     []resource.ApplyOption
+		want   want
+	}{
+		"Create": {
+			reason: "A ScaledObject that does not exist should be created, owned by and targeting its workload",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			t:      scaledObject(map[string]interface{}{}),
+			want:   want{ops: []string{"create"}, scaleTargetRef: targetWorkload, owners: 1},
+		},
+		"Update": {
+			reason: "A ScaledObject that exists should be patched, keeping the scale target it specifies",
+			get:    test.NewMockGetFn(nil),
+			t:      scaledObject(map[string]interface{}{"scaleTargetRef": map[string]interface{}{"name": "other"}}),
+			want:   want{ops: []string{"patch"}, scaleTargetRef: map[string]interface{}{"name": "other"}, owners: 1},
+		},
+		"GetError": {
+			reason: "Errors getting the ScaledObject should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			t:      scaledObject(map[string]interface{}{}),
+			want:   want{scaleTargetRef: targetWorkload, owners: 1, err: errors.Wrap(errBoom, errGetObject)},
+		},
+		"ApplyOptionError": {
+			reason: "Errors returned by apply options should prevent the ScaledObject from being updated",
+			get:    test.NewMockGetFn(nil),
+			t:      scaledObject(map[string]interface{}{}),
+			ao: []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error {
+				return errBoom
+			}},
+			want: want{scaleTargetRef: targetWorkload, owners: 1, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ops []string
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, _ runtime.Object, _ ...client.CreateOption) error {
+					ops = append(ops, "create")
+					return nil
+				},
+				MockPatch: func(_ context.Context, _ runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					ops = append(ops, "patch")
+					return nil
+				},
+			}
+			a := &KEDAScaledObjectApplier{}
+			err := a.Apply(context.Background(), c, tc.t, workload, tc.ao...)
+			got := want{
+				ops:            ops,
+				scaleTargetRef: tc.t.Object["spec"].(map[string]interface{})["scaleTargetRef"],
+				owners:         len(tc.t.GetOwnerReferences()),
+				err:            err,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\na.Apply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTraitApplierRegistry(t *testing.T) {
+	object := func(kind, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetGroupVersionKind(KEDAScaledObjectGroupVersionKind.GroupVersion().WithKind(kind))
+		u.SetName(name)
+		return u
+	}
+	eligible := []unstructured.Unstructured{
+		object("Workload", "a"),
+		object("ScaledObject", "a-scaler"),
+		object("Workload", "b"),
+		object("ScaledObject", "b-scaler"),
+	}
+
+	var deleted []string
+	r := NewTraitApplierRegistry()
+	r.Register(KEDAScaledObjectGroupVersionKind, &KEDAScaledObjectApplier{})
+	c := &test.MockClient{MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+		deleted = append(deleted, obj.(*unstructured.Unstructured).GetName())
+		return nil
+	}}
+	for _, o := range r.DeletionOrder(eligible) {
+		o := o
+		if err := r.Delete(context.Background(), c, &o); err != nil {
+			t.Fatalf("r.Delete(...): %s", err)
+		}
+	}
+	want := []string{"a-scaler", "b-scaler", "a", "b"}
+	if diff := cmp.Diff(want, deleted); diff != "" {
+		t.Errorf("r.DeletionOrder(...): -want, +got:\n%s", diff)
+	}
+
+	var none *TraitApplierRegistry
+	if diff := cmp.Diff(eligible, none.DeletionOrder(eligible)); diff != "" {
+		t.Errorf("none.DeletionOrder(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestTraitApplierRegistryDeleteUID(t *testing.T) {
+	trait := func(uid types.UID) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind("Trait")
+		u.SetName("renamed")
+		if uid != "" {
+			u.SetUID(uid)
+		}
+		return u
+	}
+
+	type want struct {
+		precondition *types.UID
+		err          error
+	}
+	cases := map[string]struct {
+		reason    string
+		trait     *unstructured.Unstructured
+		deleteErr error
+		want      want
+	}{
+		"NoUID": {
+			reason: "A trait without a UID should be deleted unconditionally",
+			trait:  trait(""),
+		},
+		"UID": {
+			reason: "A trait with a UID should only be deleted if it still has that UID",
+			trait:  trait("uid"),
+			want:   want{precondition: func() *types.UID { u := types.UID("uid"); return &u }()},
+		},
+		"Replaced": {
+			reason:    "A trait that was replaced by another of the same name should be left alone",
+			trait:     trait("uid"),
+			deleteErr: kerrors.NewConflict(schema.GroupResource{}, "renamed", errors.New("uid mismatch")),
+			want:      want{precondition: func() *types.UID { u := types.UID("uid"); return &u }()},
+		},
+		"ConflictWithoutUID": {
+			reason:    "Conflicts deleting a trait without a UID should be returned",
+			trait:     trait(""),
+			deleteErr: kerrors.NewConflict(schema.GroupResource{}, "renamed", errors.New("boom")),
+			want:      want{err: kerrors.NewConflict(schema.GroupResource{}, "renamed", errors.New("boom"))},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			c := &test.MockClient{MockDelete: func(_ context.Context, _ runtime.Object, opts ...client.DeleteOption) error {
+				do := &client.DeleteOptions{}
+				do.ApplyOptions(opts)
+				if do.Preconditions != nil {
+					got.precondition = do.Preconditions.UID
+				}
+				return tc.deleteErr
+			}}
+			got.err = NewTraitApplierRegistry().Delete(context.Background(), c, tc.trait)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Delete(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveTraitReadiness(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cert := runtimev1alpha1.TypedReference{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Name: "cert"}
+	other := runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "Trait", Name: "other"}
+	ac := func() *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{
+			Workloads: []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{{Reference: cert}, {Reference: other}}}},
+		}}
+	}
+	ready := func(status string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": status, "message": "pending"}},
+			}
+			return nil
+		})
+	}
+	unavailable := runtimev1alpha1.Unavailable()
+	unavailable.Message = "pending"
+
+	type want struct {
+		conditions []runtimev1alpha1.Condition
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		want   want
+	}{
+		"Ready": {
+			reason: "A ready Certificate should be available",
+			get:    ready("True"),
+			want:   want{conditions: []runtimev1alpha1.Condition{runtimev1alpha1.Available()}},
+		},
+		"NotReady": {
+			reason: "A Certificate that is not ready should be unavailable, explained by its message",
+			get:    ready("False"),
+			want:   want{conditions: []runtimev1alpha1.Condition{unavailable}},
+		},
+		"NotFound": {
+			reason: "A Certificate that does not exist should be skipped",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cert")),
+		},
+		"GetError": {
+			reason: "Errors getting a Certificate should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetReadinessTrait, "cert")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := ac()
+			err := observeTraitReadiness(context.Background(), &test.MockClient{MockGet: tc.get}, defaultTraitAppliers(), a)
+			got := want{conditions: a.Status.Workloads[0].Traits[0].Conditions, err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nobserveTraitReadiness(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if len(a.Status.Workloads[0].Traits[1].Conditions) != 0 {
+				t.Errorf("\n%s\nobserveTraitReadiness(...): want no conditions for traits without a readiness checker", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// DefaultTraitDefinitionTTL is the default duration for which a cached
+// TraitDefinition is used before it is read again.
+const DefaultTraitDefinitionTTL = 5 * time.Minute
+
+type cachedTraitDefinition struct {
+	td      *v1alpha2.TraitDefinition
+	expires time.Time
+}
+
+// A TraitDefinitionCache caches the TraitDefinitions of traits, keyed by the
+// apiVersion and kind of the trait. Cached TraitDefinitions are read again
+// once their TTL expires. A TraitDefinitionCache is also an event handler;
+// TraitDefinitions are evicted from the cache when they are changed.
+type TraitDefinitionCache struct {
+	client client.Reader
+	ttl    time.Duration
+	now    func() time.Time
+
+	entries sync.Map
+}
+
+var _ handler.EventHandler = &TraitDefinitionCache{}
+
+// NewTraitDefinitionCache returns a TraitDefinitionCache that reads
+// TraitDefinitions using the supplied client, and caches them for the supplied
+// TTL.
+func NewTraitDefinitionCache(c client.Reader, ttl time.Duration) *TraitDefinitionCache {
+	return &TraitDefinitionCache{client: c, ttl: ttl, now: time.Now}
+}
+
+// Get the TraitDefinition of the supplied trait.
+func (c *TraitDefinitionCache) Get(ctx context.Context, t *unstructured.Unstructured) (*v1alpha2.TraitDefinition, error) {
+	key := t.GetAPIVersion() + "/" + t.GetKind()
+	if e, ok := c.entries.Load(key); ok {
+		if cached := e.(cachedTraitDefinition); c.now().Before(cached.expires) {
+			return cached.td.DeepCopy(), nil
+		}
+	}
+
+	td, err := util.FetchTraitDefinition(ctx, c.client, t)
+	if err != nil {
+		return nil, err
+	}
+	c.entries.Store(key, cachedTraitDefinition{td: td.DeepCopy(), expires: c.now().Add(c.ttl)})
+	return td, nil
+}
+
+// evict any cached TraitDefinition with the supplied name.
+func (c *TraitDefinitionCache) evict(name string) {
+	c.entries.Range(func(key, e interface{}) bool {
+		if e.(cachedTraitDefinition).td.GetName() == name {
+			c.entries.Delete(key)
+		}
+		return true
+	})
+}
+
+// Create implements EventHandler
+func (c *TraitDefinitionCache) Create(evt event.CreateEvent, _ workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta.GetName())
+}
+
+// Update implements EventHandler
+func (c *TraitDefinitionCache) Update(evt event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+	c.evict(evt.MetaNew.GetName())
+}
+
+// Delete implements EventHandler
+func (c *TraitDefinitionCache) Delete(evt event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta.GetName())
+}
+
+// Generic implements EventHandler
+func (c *TraitDefinitionCache) Generic(evt event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	c.evict(evt.Meta.GetName())
+}
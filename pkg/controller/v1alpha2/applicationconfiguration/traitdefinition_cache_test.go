@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestTraitDefinitionCache(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	trait := &unstructured.Unstructured{}
+	trait.SetAPIVersion("example.com/v1")
+	trait.SetKind("Trait")
+
+	td := &v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: "traits.example.com"}}
+
+	// step is performed before each Get on the cache.
+	type step func(c *TraitDefinitionCache, now *time.Time)
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		steps  []step
+		want   int
+	}{
+		"Cached": {
+			reason: "A TraitDefinition should only be read once within its TTL",
+			steps:  []step{nil, nil, nil},
+			want:   1,
+		},
+		"Expired": {
+			reason: "A TraitDefinition should be read again once its TTL expires",
+			steps: []step{nil, func(_ *TraitDefinitionCache, now *time.Time) {
+				*now = now.Add(DefaultTraitDefinitionTTL + time.Second)
+			}},
+			want: 2,
+		},
+		"Evicted": {
+			reason: "A TraitDefinition should be read again once it is updated",
+			steps: []step{nil, func(c *TraitDefinitionCache, _ *time.Time) {
+				c.Update(event.UpdateEvent{MetaNew: td.DeepCopy()}, nil)
+			}},
+			want: 2,
+		},
+		"EvictedOther": {
+			reason: "Updates to other TraitDefinitions should not evict a cached TraitDefinition",
+			steps: []step{nil, func(c *TraitDefinitionCache, _ *time.Time) {
+				c.Update(event.UpdateEvent{MetaNew: &v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: "other"}}}, nil)
+			}},
+			want: 1,
+		},
+		"ErrorNotCached": {
+			reason: "Errors reading a TraitDefinition should not be cached",
+			err:    errBoom,
+			steps:  []step{nil, nil},
+			want:   2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gets := 0
+			c := NewTraitDefinitionCache(&test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					gets++
+					td.DeepCopyInto(obj.(*v1alpha2.TraitDefinition))
+					return tc.err
+				},
+			}, DefaultTraitDefinitionTTL)
+			now := time.Now()
+			c.now = func() time.Time { return now }
+
+			for _, s := range tc.steps {
+				if s != nil {
+					s(c, &now)
+				}
+				got, err := c.Get(context.Background(), trait)
+				if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+					t.Errorf("\n%s\nc.Get(...): -want error, +got error:\n%s", tc.reason, diff)
+				}
+				if tc.err == nil {
+					if diff := cmp.Diff(td, got); diff != "" {
+						t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, gets); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want reads, +got reads:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
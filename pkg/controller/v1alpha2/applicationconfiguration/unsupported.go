@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// An unsupportedField is a field of an ApplicationConfiguration's components
+// that cannot be applied to the workloads of some components, for example
+// because they have no pod template.
+type unsupportedField struct {
+	// failed returns true if the field could not be applied to the supplied
+	// workload.
+	failed func(w Workload) bool
+
+	// msgFmt formats the name of a component whose field could not be
+	// applied into a message.
+	msgFmt string
+
+	// event is the reason of the event recorded when the field could not be
+	// applied to some workloads.
+	event event.Reason
+
+	// condition that is true while the field could not be applied to some
+	// workloads, set for the unsupported reason, and false once it could be
+	// applied to all workloads, set for the supported reason.
+	condition   runtimev1alpha1.ConditionType
+	unsupported runtimev1alpha1.ConditionReason
+	supported   runtimev1alpha1.ConditionReason
+}
+
+// unsupportedFields are reported by reportUnsupported.
+var unsupportedFields = []unsupportedField{
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedEnv },
+		msgFmt:      errFmtUnsupportedEnv,
+		event:       reasonUnsupportedEnv,
+		condition:   v1alpha2.TypeUnsupportedEnv,
+		unsupported: v1alpha2.ReasonUnsupportedEnv,
+		supported:   v1alpha2.ReasonEnvInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedAffinity },
+		msgFmt:      errFmtUnsupportedAffinity,
+		event:       reasonUnsupportedAffinity,
+		condition:   v1alpha2.TypeUnsupportedAffinity,
+		unsupported: v1alpha2.ReasonUnsupportedAffinity,
+		supported:   v1alpha2.ReasonAffinityInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedTolerations },
+		msgFmt:      errFmtUnsupportedTols,
+		event:       reasonUnsupportedTolerations,
+		condition:   v1alpha2.TypeUnsupportedTolerations,
+		unsupported: v1alpha2.ReasonUnsupportedTolerations,
+		supported:   v1alpha2.ReasonTolerationsInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedPriorityClass },
+		msgFmt:      errFmtUnsupportedPriority,
+		event:       reasonUnsupportedPriority,
+		condition:   v1alpha2.TypeUnsupportedPriorityClass,
+		unsupported: v1alpha2.ReasonUnsupportedPriorityClass,
+		supported:   v1alpha2.ReasonPriorityClassInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedNodeSelector },
+		msgFmt:      errFmtUnsupportedNodeSel,
+		event:       reasonUnsupportedNodeSel,
+		condition:   v1alpha2.TypeUnsupportedNodeSelector,
+		unsupported: v1alpha2.ReasonUnsupportedNodeSelector,
+		supported:   v1alpha2.ReasonNodeSelectorInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedHostNetwork },
+		msgFmt:      errFmtUnsupportedHostNet,
+		event:       reasonUnsupportedHostNet,
+		condition:   v1alpha2.TypeUnsupportedHostNetwork,
+		unsupported: v1alpha2.ReasonUnsupportedHostNetwork,
+		supported:   v1alpha2.ReasonHostNetworkInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedLivenessProbe },
+		msgFmt:      errFmtUnsupportedLiveness,
+		event:       reasonUnsupportedLiveness,
+		condition:   v1alpha2.TypeUnsupportedLivenessProbe,
+		unsupported: v1alpha2.ReasonUnsupportedLivenessProbe,
+		supported:   v1alpha2.ReasonLivenessProbeInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedPodDisruptionBudget },
+		msgFmt:      errFmtUnsupportedPDB,
+		event:       reasonUnsupportedPDB,
+		condition:   v1alpha2.TypeUnsupportedPodDisruptionBudget,
+		unsupported: v1alpha2.ReasonUnsupportedPodDisruptionBudget,
+		supported:   v1alpha2.ReasonPodDisruptionBudgetsRendered,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedServiceAccount },
+		msgFmt:      errFmtUnsupportedSvcAcct,
+		event:       reasonUnsupportedSvcAcct,
+		condition:   v1alpha2.TypeUnsupportedServiceAccount,
+		unsupported: v1alpha2.ReasonUnsupportedServiceAccount,
+		supported:   v1alpha2.ReasonServiceAccountInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedReadinessProbe },
+		msgFmt:      errFmtUnsupportedReadiness,
+		event:       reasonUnsupportedReadiness,
+		condition:   v1alpha2.TypeUnsupportedReadinessProbe,
+		unsupported: v1alpha2.ReasonUnsupportedReadinessProbe,
+		supported:   v1alpha2.ReasonReadinessProbeInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedSecurityContext },
+		msgFmt:      errFmtUnsupportedSecCtx,
+		event:       reasonUnsupportedSecCtx,
+		condition:   v1alpha2.TypeUnsupportedSecurityContext,
+		unsupported: v1alpha2.ReasonUnsupportedSecurityContext,
+		supported:   v1alpha2.ReasonSecurityContextInjected,
+	},
+	{
+		failed:      func(w Workload) bool { return w.UnsupportedTrafficWeight },
+		msgFmt:      errFmtUnsupportedTraffic,
+		event:       reasonUnsupportedTraffic,
+		condition:   v1alpha2.TypeUnsupportedTrafficWeight,
+		unsupported: v1alpha2.ReasonUnsupportedTrafficWeight,
+		supported:   v1alpha2.ReasonTrafficWeightsSplit,
+	},
+}
+
+// reportUnsupported records an event and sets a true condition on the
+// supplied ApplicationConfiguration for each field that could not be applied
+// to some of the supplied workloads. The conditions of fields that could be
+// applied to all workloads are set to false if they were true.
+func (r *Reconciler) reportUnsupported(log logging.Logger, ac *v1alpha2.ApplicationConfiguration, w []Workload) {
+	for _, f := range unsupportedFields {
+		msgs := make([]string, 0)
+		for _, wl := range w {
+			if f.failed(wl) {
+				msgs = append(msgs, fmt.Sprintf(f.msgFmt, wl.ComponentName))
+			}
+		}
+		if len(msgs) > 0 {
+			msg := strings.Join(msgs, "; ")
+			log.Debug("Some component fields cannot be applied to their workloads", "error", msg)
+			r.record.Event(ac, event.Warning(f.event, errors.New(msg)))
+			ac.SetConditions(v1alpha2.NewCondition(f.condition, corev1.ConditionTrue, f.unsupported, msg))
+			continue
+		}
+		if ac.GetCondition(f.condition).Status == corev1.ConditionTrue {
+			ac.SetConditions(v1alpha2.NewCondition(f.condition, corev1.ConditionFalse, f.supported, ""))
+		}
+	}
+}
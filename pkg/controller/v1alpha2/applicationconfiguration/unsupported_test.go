@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestReportUnsupported(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cond   []runtimev1alpha1.Condition
+		w      []Workload
+		want   runtimev1alpha1.Condition
+	}{
+		"Unsupported": {
+			reason: "A true condition should be set for fields that cannot be applied to some workloads",
+			w:      []Workload{{ComponentName: "a", UnsupportedAffinity: true}, {ComponentName: "b"}},
+			want:   v1alpha2.NewCondition(v1alpha2.TypeUnsupportedAffinity, corev1.ConditionTrue, v1alpha2.ReasonUnsupportedAffinity, fmt.Sprintf(errFmtUnsupportedAffinity, "a")),
+		},
+		"NowSupported": {
+			reason: "A true condition should be set to false once the field can be applied to all workloads",
+			cond:   []runtimev1alpha1.Condition{v1alpha2.NewCondition(v1alpha2.TypeUnsupportedAffinity, corev1.ConditionTrue, v1alpha2.ReasonUnsupportedAffinity, "")},
+			w:      []Workload{{ComponentName: "a"}},
+			want:   v1alpha2.NewCondition(v1alpha2.TypeUnsupportedAffinity, corev1.ConditionFalse, v1alpha2.ReasonAffinityInjected, ""),
+		},
+		"NeverUnsupported": {
+			reason: "No condition should be set if the field could always be applied",
+			w:      []Workload{{ComponentName: "a"}},
+			want:   runtimev1alpha1.Condition{Type: v1alpha2.TypeUnsupportedAffinity, Status: corev1.ConditionUnknown},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{record: event.NewNopRecorder()}
+			ac := &v1alpha2.ApplicationConfiguration{}
+			ac.SetConditions(tc.cond...)
+			r.reportUnsupported(logging.NewNopLogger(), ac, tc.w)
+
+			got := ac.GetCondition(v1alpha2.TypeUnsupportedAffinity)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(runtimev1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nr.reportUnsupported(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const errFmtUnknownVariables = "unknown global variables: %s"
+
+// variablePlaceholder matches a ${{variable}} placeholder, capturing the name
+// of the variable. Whitespace within the braces is ignored.
+var variablePlaceholder = regexp.MustCompile(`\$\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// A TemplateSubstitutor substitutes variables for the placeholders in the spec
+// of a rendered workload.
+type TemplateSubstitutor interface {
+	// Substitute the supplied variables for the placeholders in the spec of
+	// the supplied workload. Placeholders of unknown variables fail
+	// substitution if strict is true.
+	Substitute(w *unstructured.Unstructured, vars map[string]string, strict bool) error
+}
+
+// A TemplateSubstituteFn substitutes variables for the placeholders in the
+// spec of a rendered workload.
+type TemplateSubstituteFn func(w *unstructured.Unstructured, vars map[string]string, strict bool) error
+
+// Substitute the supplied variables for the placeholders in the spec of the
+// supplied workload.
+func (fn TemplateSubstituteFn) Substitute(w *unstructured.Unstructured, vars map[string]string, strict bool) error {
+	return fn(w, vars, strict)
+}
+
+// substituteVariables replaces ${{variable}} placeholders in the string values
+// of the supplied workload's spec with the supplied variables. Placeholders of
+// unknown variables are left as is, or return an error if strict is true.
+func substituteVariables(w *unstructured.Unstructured, vars map[string]string, strict bool) error {
+	spec, ok := w.Object["spec"]
+	if !ok {
+		return nil
+	}
+	unknown := map[string]bool{}
+	w.Object["spec"] = substitute(spec, vars, unknown)
+	if strict && len(unknown) > 0 {
+		names := make([]string, 0, len(unknown))
+		for n := range unknown {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return errors.Errorf(errFmtUnknownVariables, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// substitute returns the supplied JSON value with variables substituted for
+// the placeholders of its string values, recording any unknown variables.
+func substitute(v interface{}, vars map[string]string, unknown map[string]bool) interface{} {
+	switch t := v.(type) {
+	case string:
+		return variablePlaceholder.ReplaceAllStringFunc(t, func(p string) string {
+			name := variablePlaceholder.FindStringSubmatch(p)[1]
+			value, ok := vars[name]
+			if !ok {
+				unknown[name] = true
+				return p
+			}
+			return value
+		})
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = substitute(e, vars, unknown)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = substitute(e, vars, unknown)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	workload := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "${{name}}"},
+			"spec": map[string]interface{}{
+				"image":    "registry.example.org/app:${{ version }}",
+				"replicas": int64(3),
+				"env": []interface{}{
+					map[string]interface{}{"name": "REGION", "value": "${{region}}"},
+				},
+			},
+		}}
+	}
+	substituted := func(region string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "${{name}}"},
+			"spec": map[string]interface{}{
+				"image":    "registry.example.org/app:v1",
+				"replicas": int64(3),
+				"env": []interface{}{
+					map[string]interface{}{"name": "REGION", "value": region},
+				},
+			},
+		}}
+	}
+
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		vars   map[string]string
+		strict bool
+		want   want
+	}{
+		"Substituted": {
+			reason: "Placeholders within the spec should be replaced by their variables",
+			vars:   map[string]string{"version": "v1", "region": "eu-west-1", "name": "ignored"},
+			want:   want{w: substituted("eu-west-1")},
+		},
+		"LenientUnknownVariable": {
+			reason: "Placeholders of unknown variables should be left as is in lenient mode",
+			vars:   map[string]string{"version": "v1"},
+			want:   want{w: substituted("${{region}}")},
+		},
+		"StrictUnknownVariable": {
+			reason: "Placeholders of unknown variables should return an error in strict mode",
+			vars:   map[string]string{"version": "v1"},
+			strict: true,
+			want:   want{w: substituted("${{region}}"), err: errors.Errorf(errFmtUnknownVariables, "region")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := workload()
+			err := substituteVariables(w, tc.vars, tc.strict)
+			if diff := cmp.Diff(tc.want, want{w: w, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nsubstituteVariables(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Vault error strings.
+const (
+	errNoParameterResolver = "parameter value references a secret, but no secret resolver is configured"
+	errFmtResolveSecretPV  = "cannot resolve secret of parameter %q"
+	errFmtResolveSecrets   = "cannot resolve secret parameter values of component %q"
+	errFmtInvalidVaultRef  = "invalid Vault reference %q: must be of the form vault://<path>#<key>"
+	errFmtNoVaultKey       = "Vault secret %q has no key %q"
+	errFmtVaultStatus      = "Vault returned %s"
+	errFmtReadVaultSecret  = "cannot read Vault secret %q"
+	errVaultLogin          = "cannot log in to Vault using AppRole"
+	errVaultRenew          = "cannot renew Vault token"
+	errMarshalVaultRequest = "cannot marshal Vault request"
+	errDecodeVaultResponse = "cannot decode Vault response"
+	errNewVaultRequest     = "cannot create Vault request"
+	errVaultNoClientToken  = "Vault login returned no client token"
+	errFmtVaultNotString   = "Vault secret %q key %q is not a string"
+)
+
+const (
+	vaultRefPrefix = "vault://"
+
+	defaultVaultCacheTTL = 5 * time.Minute
+
+	// vaultRenewCheckInterval is how often the token is checked for renewal
+	// when there is no token, or its lease is unknown.
+	vaultRenewCheckInterval = 1 * time.Minute
+)
+
+// A VaultSecretResolver resolves parameter values of the form
+// vault://<path>#<key> to the value of the supplied key of the Vault secret at
+// the supplied path, e.g. vault://secret/data/db#password. Both KV version 1
+// and 2 secrets are supported. The resolver authenticates to Vault using
+// AppRole, and renews its token in the background while it is started.
+type VaultSecretResolver struct {
+	client   *http.Client
+	addr     string
+	roleID   string
+	secretID string
+	log      logging.Logger
+	now      func() time.Time
+
+	mu    sync.Mutex
+	token string
+	lease time.Duration
+	cache map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+var _ oam.ParameterResolver = &VaultSecretResolver{}
+
+// NewVaultSecretResolver returns a resolver that reads secrets from the Vault
+// server at the supplied address, authenticating with the supplied AppRole
+// role and secret IDs.
+func NewVaultSecretResolver(c *http.Client, addr, roleID, secretID string, l logging.Logger) *VaultSecretResolver {
+	return &VaultSecretResolver{
+		client:   c,
+		addr:     strings.TrimSuffix(addr, "/"),
+		roleID:   roleID,
+		secretID: secretID,
+		log:      l,
+		now:      time.Now,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// Resolves returns true if the supplied value is a Vault reference.
+func (v *VaultSecretResolver) Resolves(value string) bool {
+	return strings.HasPrefix(value, vaultRefPrefix)
+}
+
+// Resolve the supplied Vault reference. Resolved values are cached for the
+// supplied TTL.
+func (v *VaultSecretResolver) Resolve(ctx context.Context, value string, ttl time.Duration) (string, error) {
+	path, key, err := parseVaultRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	v.mu.Lock()
+	c, ok := v.cache[value]
+	v.mu.Unlock()
+	if ok && v.now().Before(c.expires) {
+		return c.value, nil
+	}
+
+	data, err := v.read(ctx, path)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtReadVaultSecret, path)
+	}
+	raw, ok := data[key]
+	if !ok {
+		return "", errors.Errorf(errFmtNoVaultKey, path, key)
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return "", errors.Errorf(errFmtVaultNotString, path, key)
+	}
+
+	v.mu.Lock()
+	v.cache[value] = cachedSecret{value: s, expires: v.now().Add(ttl)}
+	v.mu.Unlock()
+	return s, nil
+}
+
+// Start renewing the Vault token in the background. It blocks until the
+// supplied channel is closed.
+func (v *VaultSecretResolver) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t := time.NewTimer(v.renewAfter())
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			if err := v.renew(ctx); err != nil {
+				v.log.Debug("Cannot renew Vault token", "error", err)
+			}
+			t.Reset(v.renewAfter())
+		}
+	}
+}
+
+// renewAfter returns how long to wait before renewing the token. Tokens are
+// renewed when two thirds of their lease has elapsed.
+func (v *VaultSecretResolver) renewAfter() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.token == "" || v.lease <= 0 {
+		return vaultRenewCheckInterval
+	}
+	return v.lease * 2 / 3
+}
+
+// renew the current token, if any, logging in again if it cannot be renewed.
+func (v *VaultSecretResolver) renew(ctx context.Context) error {
+	v.mu.Lock()
+	token := v.token
+	v.mu.Unlock()
+	if token == "" {
+		return nil
+	}
+
+	rsp := &vaultAuthResponse{}
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", token, struct{}{}, rsp); err != nil {
+		v.log.Debug("Cannot renew Vault token; logging in again", "error", err)
+		_, err := v.login(ctx)
+		return errors.Wrap(err, errVaultRenew)
+	}
+	v.mu.Lock()
+	v.lease = time.Duration(rsp.Auth.LeaseDuration) * time.Second
+	v.mu.Unlock()
+	return nil
+}
+
+// read the data of the secret at the supplied path. The read is retried once
+// with a new token if Vault rejects the current token.
+func (v *VaultSecretResolver) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	token, err := v.currentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &vaultSecretResponse{}
+	err = v.do(ctx, http.MethodGet, "/v1/"+path, token, nil, rsp)
+	if isVaultForbidden(err) {
+		if token, err = v.login(ctx); err != nil {
+			return nil, err
+		}
+		err = v.do(ctx, http.MethodGet, "/v1/"+path, token, nil, rsp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// KV version 2 secrets nest their data, alongside their metadata.
+	if inner, ok := rsp.Data["data"].(map[string]interface{}); ok {
+		if _, ok := rsp.Data["metadata"]; ok {
+			return inner, nil
+		}
+	}
+	return rsp.Data, nil
+}
+
+func (v *VaultSecretResolver) currentToken(ctx context.Context) (string, error) {
+	v.mu.Lock()
+	token := v.token
+	v.mu.Unlock()
+	if token != "" {
+		return token, nil
+	}
+	return v.login(ctx)
+}
+
+func (v *VaultSecretResolver) login(ctx context.Context) (string, error) {
+	rsp := &vaultAuthResponse{}
+	body := map[string]string{"role_id": v.roleID, "secret_id": v.secretID}
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/approle/login", "", body, rsp); err != nil {
+		return "", errors.Wrap(err, errVaultLogin)
+	}
+	if rsp.Auth.ClientToken == "" {
+		return "", errors.New(errVaultNoClientToken)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.token = rsp.Auth.ClientToken
+	v.lease = time.Duration(rsp.Auth.LeaseDuration) * time.Second
+	return v.token, nil
+}
+
+type vaultAuthResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+type vaultSecretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type vaultStatusError struct {
+	status int
+	msg    string
+}
+
+func (e *vaultStatusError) Error() string {
+	return fmt.Sprintf(errFmtVaultStatus, e.msg)
+}
+
+func isVaultForbidden(err error) bool {
+	se, ok := errors.Cause(err).(*vaultStatusError)
+	return ok && se.status == http.StatusForbidden
+}
+
+func (v *VaultSecretResolver) do(ctx context.Context, method, path, token string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, errMarshalVaultRequest)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, v.addr+path, body)
+	if err != nil {
+		return errors.Wrap(err, errNewVaultRequest)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	rsp, err := v.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+	if rsp.StatusCode != http.StatusOK {
+		// Drain the body so that the connection may be reused.
+		_, _ = io.Copy(ioutil.Discard, rsp.Body)
+		return &vaultStatusError{status: rsp.StatusCode, msg: rsp.Status}
+	}
+	return errors.Wrap(json.NewDecoder(rsp.Body).Decode(out), errDecodeVaultResponse)
+}
+
+// parseVaultRef returns the path and key of the supplied Vault reference.
+func parseVaultRef(ref string) (path, key string, err error) {
+	s := strings.SplitN(strings.TrimPrefix(ref, vaultRefPrefix), "#", 2)
+	if len(s) != 2 || s[0] == "" || s[1] == "" {
+		return "", "", errors.Errorf(errFmtInvalidVaultRef, ref)
+	}
+	return strings.Trim(s[0], "/"), s[1], nil
+}
+
+// secretCacheTTL returns how long the supplied ApplicationConfiguration's
+// resolved secret parameter values may be cached.
+func secretCacheTTL(ac *v1alpha2.ApplicationConfiguration) time.Duration {
+	if ac.Spec.VaultCacheTTL == nil || ac.Spec.VaultCacheTTL.Duration < 0 {
+		return defaultVaultCacheTTL
+	}
+	return ac.Spec.VaultCacheTTL.Duration
+}
+
+// resolveSecretParameterValues returns a copy of the supplied parameter values
+// with any values that reference secrets replaced by their plaintext values.
+// Other values are returned unchanged.
+func resolveSecretParameterValues(ctx context.Context, pr oam.ParameterResolver, pv []v1alpha2.ComponentParameterValue, ttl time.Duration) ([]v1alpha2.ComponentParameterValue, error) {
+	if len(pv) == 0 {
+		return pv, nil
+	}
+	out := make([]v1alpha2.ComponentParameterValue, len(pv))
+	for i, v := range pv {
+		out[i] = v
+		if v.Value.Type != intstr.String || !strings.HasPrefix(v.Value.StrVal, vaultRefPrefix) {
+			continue
+		}
+		if pr == nil || !pr.Resolves(v.Value.StrVal) {
+			return nil, errors.Wrapf(errors.New(errNoParameterResolver), errFmtResolveSecretPV, v.Name)
+		}
+		plain, err := pr.Resolve(ctx, v.Value.StrVal, ttl)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtResolveSecretPV, v.Name)
+		}
+		out[i].Value = intstr.FromString(plain)
+	}
+	return out, nil
+}
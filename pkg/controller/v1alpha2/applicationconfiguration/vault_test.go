@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestVaultSecretResolver(t *testing.T) {
+	var logins, reads int
+	token := "expired"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			body := map[string]string{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			logins++
+			token = fmt.Sprintf("token-%d", logins)
+			fmt.Fprintf(w, `{"auth":{"client_token":%q,"lease_duration":60}}`, token)
+		case "/v1/secret/data/db":
+			if r.Header.Get("X-Vault-Token") != token {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			reads++
+			fmt.Fprint(w, `{"data":{"data":{"password":"hunter2"},"metadata":{"version":1}}}`)
+		case "/v1/kv/db":
+			if r.Header.Get("X-Vault-Token") != token {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			reads++
+			fmt.Fprint(w, `{"data":{"password":"hunter3"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	v := NewVaultSecretResolver(srv.Client(), srv.URL+"/", "role", "secret", logging.NewNopLogger())
+	v.now = func() time.Time { return now }
+
+	type want struct {
+		value  string
+		err    error
+		logins int
+		reads  int
+	}
+	cases := map[string]struct {
+		reason  string
+		token   string
+		ref     string
+		advance time.Duration
+		want    want
+	}{
+		"KVv2": {
+			reason: "The resolver should log in, then unwrap the data of KV version 2 secrets",
+			ref:    "vault://secret/data/db#password",
+			want:   want{value: "hunter2", logins: 1, reads: 1},
+		},
+		"Cached": {
+			reason: "Values should be served from the cache until their TTL elapses",
+			ref:    "vault://secret/data/db#password",
+			want:   want{value: "hunter2", logins: 1, reads: 1},
+		},
+		"CacheExpired": {
+			reason:  "Values should be read again once their TTL elapses",
+			ref:     "vault://secret/data/db#password",
+			advance: 2 * time.Minute,
+			want:    want{value: "hunter2", logins: 1, reads: 2},
+		},
+		"KVv1": {
+			reason: "The data of KV version 1 secrets should be used as is",
+			ref:    "vault://kv/db#password",
+			want:   want{value: "hunter3", logins: 1, reads: 3},
+		},
+		"TokenRevoked": {
+			reason:  "The resolver should log in again and retry once when its token is rejected",
+			token:   "revoked",
+			ref:     "vault://kv/db#password",
+			advance: 2 * time.Minute,
+			want:    want{value: "hunter3", logins: 2, reads: 4},
+		},
+		"NoKey": {
+			reason: "Referencing a key the secret does not have should return an error",
+			ref:    "vault://kv/db#username",
+			want:   want{err: errors.Errorf(errFmtNoVaultKey, "kv/db", "username"), logins: 2, reads: 5},
+		},
+		"NotFound": {
+			reason: "Errors reading secrets should be returned",
+			ref:    "vault://kv/missing#password",
+			want: want{
+				err:    errors.Wrapf(&vaultStatusError{status: http.StatusNotFound, msg: "404 Not Found"}, errFmtReadVaultSecret, "kv/missing"),
+				logins: 2,
+				reads:  5,
+			},
+		},
+		"InvalidRef": {
+			reason: "References without a key should return an error",
+			ref:    "vault://kv/db",
+			want:   want{err: errors.Errorf(errFmtInvalidVaultRef, "vault://kv/db"), logins: 2, reads: 5},
+		},
+	}
+
+	// The cases share a resolver, and thus must run in order.
+	for _, name := range []string{"KVv2", "Cached", "CacheExpired", "KVv1", "TokenRevoked", "NoKey", "NotFound", "InvalidRef"} {
+		tc := cases[name]
+		t.Run(name, func(t *testing.T) {
+			if tc.token != "" {
+				token = tc.token
+			}
+			now = now.Add(tc.advance)
+			value, err := v.Resolve(context.Background(), tc.ref, time.Minute)
+			got := want{value: value, err: err, logins: logins, reads: reads}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVaultSecretResolverRenew(t *testing.T) {
+	var renewed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/token/renew-self" || r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		renewed = true
+		fmt.Fprint(w, `{"auth":{"client_token":"token","lease_duration":90}}`)
+	}))
+	defer srv.Close()
+
+	v := NewVaultSecretResolver(srv.Client(), srv.URL, "role", "secret", logging.NewNopLogger())
+	if got := v.renewAfter(); got != vaultRenewCheckInterval {
+		t.Errorf("v.renewAfter(): want %s without a token, got %s", vaultRenewCheckInterval, got)
+	}
+
+	v.token, v.lease = "token", 30*time.Second
+	if err := v.renew(context.Background()); err != nil {
+		t.Errorf("v.renew(...): %s", err)
+	}
+	if !renewed {
+		t.Errorf("v.renew(...): want token renewed")
+	}
+	if got, want := v.renewAfter(), 60*time.Second; got != want {
+		t.Errorf("v.renewAfter(): want %s, got %s", want, got)
+	}
+}
+
+func TestResolveSecretParameterValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	pv := []v1alpha2.ComponentParameterValue{
+		{Name: "plain", Value: intstr.FromString("value")},
+		{Name: "port", Value: intstr.FromInt(80)},
+		{Name: "secret", Value: intstr.FromString("vault://kv/db#password")},
+	}
+	resolver := func(value string, err error) oam.ParameterResolver {
+		return &mockParameterResolver{value: value, err: err}
+	}
+
+	type want struct {
+		pv  []v1alpha2.ComponentParameterValue
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		pr     oam.ParameterResolver
+		pv     []v1alpha2.ComponentParameterValue
+		want   want
+	}{
+		"NoSecrets": {
+			reason: "Parameter values that reference no secrets should be returned unchanged",
+			pv:     pv[:2],
+			want:   want{pv: pv[:2]},
+		},
+		"NoResolver": {
+			reason: "Referencing a secret when no resolver is configured should return an error",
+			pv:     pv,
+			want:   want{err: errors.Wrapf(errors.New(errNoParameterResolver), errFmtResolveSecretPV, "secret")},
+		},
+		"ResolveError": {
+			reason: "Errors resolving secrets should be returned",
+			pr:     resolver("", errBoom),
+			pv:     pv,
+			want:   want{err: errors.Wrapf(errBoom, errFmtResolveSecretPV, "secret")},
+		},
+		"Resolved": {
+			reason: "Secret references should be replaced by their plaintext values",
+			pr:     resolver("hunter2", nil),
+			pv:     pv,
+			want: want{pv: []v1alpha2.ComponentParameterValue{
+				{Name: "plain", Value: intstr.FromString("value")},
+				{Name: "port", Value: intstr.FromInt(80)},
+				{Name: "secret", Value: intstr.FromString("hunter2")},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveSecretParameterValues(context.Background(), tc.pr, tc.pv, time.Minute)
+			if diff := cmp.Diff(tc.want, want{pv: got, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveSecretParameterValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	if pv[2].Value.StrVal != "vault://kv/db#password" {
+		t.Errorf("resolveSecretParameterValues(...): want supplied parameter values unchanged, got %q", pv[2].Value.StrVal)
+	}
+}
+
+type mockParameterResolver struct {
+	value string
+	err   error
+}
+
+func (m *mockParameterResolver) Resolves(_ string) bool { return true }
+
+func (m *mockParameterResolver) Resolve(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return m.value, m.err
+}
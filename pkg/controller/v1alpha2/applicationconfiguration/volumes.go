@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// ConfigMap volume error strings.
+const (
+	errFmtNoVolumeContainer = "workload has no container %q into which to mount ConfigMap %q"
+	errFmtGetConfigMap      = "cannot get ConfigMap %q"
+	errFmtConfigMapNotFound = "ConfigMap %q mounted into component %q does not exist"
+)
+
+// podVolumesPath is the field path of the volumes of workloads that embed a
+// pod template.
+const podVolumesPath = "spec.template.spec.volumes"
+
+// configMapVolumeName returns the name of the volume in which the supplied
+// ConfigMap is mounted.
+func configMapVolumeName(configMap string) string {
+	return "oam-configmap-" + configMap
+}
+
+// injectConfigMapVolumes mounts the supplied ConfigMaps into the containers of
+// the pod template of the supplied workload. Each ConfigMap is mounted into
+// the container of its container name, or into all containers if it has none.
+func injectConfigMapVolumes(w *unstructured.Unstructured, vols []v1alpha2.ComponentConfigMapVolume) error {
+	if len(vols) == 0 {
+		return nil
+	}
+
+	p := fieldpath.Pave(w.UnstructuredContent())
+	v, err := p.GetValue(podTemplateContainersPath)
+	if err != nil {
+		return errors.New(errNoPodTemplate)
+	}
+	containers, ok := v.([]interface{})
+	if !ok || len(containers) == 0 {
+		return errors.New(errNoPodTemplate)
+	}
+
+	existing, _ := p.GetValue(podVolumesPath)
+	volumes, _ := existing.([]interface{})
+	volumed := map[string]bool{}
+	for _, vol := range vols {
+		name := configMapVolumeName(vol.ConfigMapName)
+		if !volumed[name] {
+			volumes = append(volumes, map[string]interface{}{
+				"name":      name,
+				"configMap": map[string]interface{}{"name": vol.ConfigMapName},
+			})
+			volumed[name] = true
+		}
+
+		mounted := false
+		for i := range containers {
+			c, _ := containers[i].(map[string]interface{})
+			if vol.ContainerName != "" && c["name"] != vol.ContainerName {
+				continue
+			}
+			path := fmt.Sprintf("%s[%d].volumeMounts", podTemplateContainersPath, i)
+			existing, _ := p.GetValue(path)
+			mounts, _ := existing.([]interface{})
+			mount := map[string]interface{}{"name": name, "mountPath": vol.MountPath, "readOnly": true}
+			if err := p.SetValue(path, append(mounts, mount)); err != nil {
+				return err
+			}
+			mounted = true
+		}
+		if !mounted {
+			return errors.Errorf(errFmtNoVolumeContainer, vol.ContainerName, vol.ConfigMapName)
+		}
+	}
+	return p.SetValue(podVolumesPath, volumes)
+}
+
+// missingConfigMaps returns a message for each ConfigMap mounted into the
+// supplied workloads that does not exist in the supplied namespace.
+func missingConfigMaps(ctx context.Context, c client.Reader, namespace string, w []Workload) ([]string, error) {
+	msgs := make([]string, 0)
+	for _, wl := range w {
+		for _, name := range wl.ConfigMaps {
+			err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &corev1.ConfigMap{})
+			if kerrors.IsNotFound(err) {
+				msgs = append(msgs, fmt.Sprintf(errFmtConfigMapNotFound, name, wl.ComponentName))
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetConfigMap, name)
+			}
+		}
+	}
+	return msgs, nil
+}
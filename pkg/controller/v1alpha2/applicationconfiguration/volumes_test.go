@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestInjectConfigMapVolumes(t *testing.T) {
+	workload := func(volumes []interface{}, containers ...interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"containers": containers}
+		if volumes != nil {
+			spec["volumes"] = volumes
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+	}
+	volume := map[string]interface{}{"name": "oam-configmap-config", "configMap": map[string]interface{}{"name": "config"}}
+	mounted := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":         name,
+			"volumeMounts": []interface{}{map[string]interface{}{"name": "oam-configmap-config", "mountPath": "/etc/config", "readOnly": true}},
+		}
+	}
+
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		w      *unstructured.Unstructured
+		vols   []v1alpha2.ComponentConfigMapVolume
+		want   want
+	}{
+		"NoVolumes": {
+			reason: "A workload should be unchanged when no ConfigMap volumes are supplied",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}},
+		},
+		"AllContainers": {
+			reason: "ConfigMaps without a container name should be mounted into all containers",
+			w:      workload(nil, map[string]interface{}{"name": "c0"}, map[string]interface{}{"name": "c1"}),
+			vols:   []v1alpha2.ComponentConfigMapVolume{{ConfigMapName: "config", MountPath: "/etc/config"}},
+			want:   want{w: workload([]interface{}{volume}, mounted("c0"), mounted("c1"))},
+		},
+		"NamedContainer": {
+			reason: "ConfigMaps with a container name should only be mounted into that container",
+			w:      workload(nil, map[string]interface{}{"name": "c0"}, map[string]interface{}{"name": "c1"}),
+			vols:   []v1alpha2.ComponentConfigMapVolume{{ConfigMapName: "config", MountPath: "/etc/config", ContainerName: "c1"}},
+			want:   want{w: workload([]interface{}{volume}, map[string]interface{}{"name": "c0"}, mounted("c1"))},
+		},
+		"NoSuchContainer": {
+			reason: "ConfigMaps whose container does not exist should return an error",
+			w:      workload(nil, map[string]interface{}{"name": "c0"}),
+			vols:   []v1alpha2.ComponentConfigMapVolume{{ConfigMapName: "config", MountPath: "/etc/config", ContainerName: "c1"}},
+			want: want{
+				w:   workload(nil, map[string]interface{}{"name": "c0"}),
+				err: errors.Errorf(errFmtNoVolumeContainer, "c1", "config"),
+			},
+		},
+		"NoPodTemplate": {
+			reason: "ConfigMaps cannot be mounted into a workload without a pod template",
+			w:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			vols:   []v1alpha2.ComponentConfigMapVolume{{ConfigMapName: "config", MountPath: "/etc/config"}},
+			want:   want{w: &unstructured.Unstructured{Object: map[string]interface{}{}}, err: errors.New(errNoPodTemplate)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := injectConfigMapVolumes(tc.w, tc.vols)
+			if diff := cmp.Diff(tc.want, want{w: tc.w, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectConfigMapVolumes(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMissingConfigMaps(t *testing.T) {
+	errBoom := errors.New("boom")
+	w := []Workload{{ComponentName: "c", ConfigMaps: []string{"exists", "missing"}}}
+
+	type want struct {
+		missing []string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"Missing": {
+			reason: "ConfigMaps that do not exist should be reported",
+			client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, _ runtime.Object) error {
+				if key.Name == "missing" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				return nil
+			}},
+			want: want{missing: []string{fmt.Sprintf(errFmtConfigMapNotFound, "missing", "c")}},
+		},
+		"GetError": {
+			reason: "Errors getting ConfigMaps should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetConfigMap, "exists")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			missing, err := missingConfigMaps(context.Background(), tc.client, "ns", w)
+			if diff := cmp.Diff(tc.want, want{missing: missing, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmissingConfigMaps(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
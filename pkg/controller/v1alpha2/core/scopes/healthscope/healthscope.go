@@ -23,6 +23,7 @@ import (
 	"time"
 
 	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,10 +43,14 @@ const (
 	errDeploymentUnavailable = "no ready instance found in %q %q %q"
 
 	defaultTimeout = 10 * time.Second
+
+	defaultHistoryRetentionCount = 10
 )
 
-// UpdateHealthStatus updates the status of the healthscope based on workload resources.
-func UpdateHealthStatus(ctx context.Context, log logging.Logger, client client.Client, healthScope *v1alpha2.HealthScope) error {
+// UpdateHealthStatus updates the status of the healthscope based on workload
+// resources, and on the Prometheus queries of its workloads if the supplied
+// checker is not nil.
+func UpdateHealthStatus(ctx context.Context, log logging.Logger, client client.Client, checker HealthChecker, healthScope *v1alpha2.HealthScope) error {
 	timeout := defaultTimeout
 	if healthScope.Spec.ProbeTimeout != nil {
 		timeout = time.Duration(*healthScope.Spec.ProbeTimeout) * time.Second
@@ -88,6 +93,9 @@ func UpdateHealthStatus(ctx context.Context, log logging.Logger, client client.C
 	for r := range statusc {
 		status = status && r
 	}
+	if checker != nil && !prometheusHealthStatus(ctxWithTimeout, log, checker, healthScope) {
+		status = false
+	}
 
 	health := "unhealthy"
 	if status {
@@ -98,6 +106,24 @@ func UpdateHealthStatus(ctx context.Context, log logging.Logger, client client.C
 	return nil
 }
 
+// RecordHealthHistory appends the current health of the supplied healthscope
+// to its health history, pruning the oldest entries beyond its retention count.
+func RecordHealthHistory(healthScope *v1alpha2.HealthScope, now metav1.Time) {
+	retain := defaultHistoryRetentionCount
+	if healthScope.Spec.HistoryRetentionCount != nil {
+		retain = int(*healthScope.Spec.HistoryRetentionCount)
+	}
+
+	history := append(healthScope.Status.HealthHistory, v1alpha2.HealthHistoryEntry{Time: now, Health: healthScope.Status.Health})
+	if len(history) > retain {
+		history = history[len(history)-retain:]
+	}
+	if len(history) == 0 {
+		history = nil
+	}
+	healthScope.Status.HealthHistory = history
+}
+
 func resourcesHealthStatus(ctx context.Context, log logging.Logger, client client.Client, namespace string, refs []runtimev1alpha1.TypedReference) <-chan bool {
 	status := make(chan bool, len(refs))
 	var wg sync.WaitGroup
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -66,7 +67,8 @@ func Setup(mgr ctrl.Manager, l logging.Logger) error {
 
 // A Reconciler reconciles OAM Scopes by keeping track of the health status of components.
 type Reconciler struct {
-	client client.Client
+	client  client.Client
+	checker HealthChecker
 
 	log    logging.Logger
 	record event.Recorder
@@ -89,12 +91,21 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithHealthChecker specifies how the Reconciler should query the metrics of
+// workloads that specify a Prometheus endpoint.
+func WithHealthChecker(c HealthChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.checker = c
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles HealthScope by keeping track of its healthstatus.
 func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
-		client: m.GetClient(),
-		log:    logging.NewNopLogger(),
-		record: event.NewNopRecorder(),
+		client:  m.GetClient(),
+		checker: NewPrometheusHealthChecker(),
+		log:     logging.NewNopLogger(),
+		record:  event.NewNopRecorder(),
 	}
 
 	for _, ro := range o {
@@ -130,13 +141,15 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 
 	log = log.WithValues("uid", hs.GetUID(), "version", hs.GetResourceVersion())
 
-	if err := UpdateHealthStatus(ctx, log, r.client, hs); err != nil {
+	if err := UpdateHealthStatus(ctx, log, r.client, r.checker, hs); err != nil {
 		log.Debug("Could not update health status", "error", err, "requeue-after", time.Now().Add(shortWait))
 		r.record.Event(hs, event.Warning(reasonHealthCheckFailed, err))
 		hs.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errUpdateHealthScopeStatus)))
 		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, hs), errUpdateHealthScopeStatus)
 	}
 
+	RecordHealthHistory(hs, metav1.Now())
+
 	log.Debug("Successfully ran health check", "scope", hs.Name)
 	r.record.Event(hs, event.Normal(reasonHealthCheck, "Successfully ran health check"))
 
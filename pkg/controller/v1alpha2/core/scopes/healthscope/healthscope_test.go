@@ -20,10 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -439,7 +441,7 @@ func TestUpdateHealthStatus(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			log := logging.NewNopLogger()
 			scope := tc.args.healthScope
-			err := UpdateHealthStatus(context.Background(), log, tc.args.client, scope)
+			err := UpdateHealthStatus(context.Background(), log, tc.args.client, nil, scope)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\nReason: %s\nUpdateHealthStatus(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -451,3 +453,57 @@ func TestUpdateHealthStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordHealthHistory(t *testing.T) {
+	t0 := metav1.NewTime(time.Unix(0, 0))
+	t1 := metav1.NewTime(time.Unix(60, 0))
+	t2 := metav1.NewTime(time.Unix(120, 0))
+	two := int32(2)
+	zero := int32(0)
+
+	cases := map[string]struct {
+		reason string
+		hs     *v1alpha2.HealthScope
+		want   []v1alpha2.HealthHistoryEntry
+	}{
+		"Appended": {
+			reason: "The current health should be appended to the history.",
+			hs: &v1alpha2.HealthScope{Status: v1alpha2.HealthScopeStatus{
+				Health:        "healthy",
+				HealthHistory: []v1alpha2.HealthHistoryEntry{{Time: t0, Health: "unhealthy"}},
+			}},
+			want: []v1alpha2.HealthHistoryEntry{{Time: t0, Health: "unhealthy"}, {Time: t2, Health: "healthy"}},
+		},
+		"Pruned": {
+			reason: "The oldest entries beyond the retention count should be pruned.",
+			hs: &v1alpha2.HealthScope{
+				Spec: v1alpha2.HealthScopeSpec{HistoryRetentionCount: &two},
+				Status: v1alpha2.HealthScopeStatus{
+					Health:        "healthy",
+					HealthHistory: []v1alpha2.HealthHistoryEntry{{Time: t0, Health: "healthy"}, {Time: t1, Health: "unhealthy"}},
+				},
+			},
+			want: []v1alpha2.HealthHistoryEntry{{Time: t1, Health: "unhealthy"}, {Time: t2, Health: "healthy"}},
+		},
+		"Disabled": {
+			reason: "No history should be retained when the retention count is zero.",
+			hs: &v1alpha2.HealthScope{
+				Spec: v1alpha2.HealthScopeSpec{HistoryRetentionCount: &zero},
+				Status: v1alpha2.HealthScopeStatus{
+					Health:        "healthy",
+					HealthHistory: []v1alpha2.HealthHistoryEntry{{Time: t0, Health: "healthy"}},
+				},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			RecordHealthHistory(tc.hs, t2)
+			if diff := cmp.Diff(tc.want, tc.hs.Status.HealthHistory); diff != "" {
+				t.Errorf("\nReason: %s\nRecordHealthHistory(...): -want history, +got history:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
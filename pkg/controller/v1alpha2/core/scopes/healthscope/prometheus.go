@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	errFmtParseThreshold  = "cannot parse healthy threshold %q"
+	errFmtQueryPrometheus = "cannot query Prometheus at %s"
+	errFmtPrometheusError = "query failed: %s"
+	errFmtPrometheusCode  = "query returned %s"
+	errFmtResultType      = "unsupported Prometheus result type %q"
+	errFmtCircuitOpen     = "not querying %s after %d consecutive failures"
+	errFmtUnhealthyMetric = "health of workload %q is %g, which is not above the healthy threshold of %g"
+	errNoSamples          = "query returned no samples"
+	errTooManySamples     = "query returned more than one sample"
+	errDecodeResponse     = "cannot decode Prometheus response"
+	errParseSample        = "cannot parse Prometheus sample"
+
+	defaultPrometheusTimeout  = 5 * time.Second
+	defaultFailureThreshold   = 3
+	defaultCircuitOpenTimeout = 1 * time.Minute
+)
+
+// A HealthChecker determines the health of a workload by querying its metrics.
+type HealthChecker interface {
+	// Check returns nil if the workload of the supplied endpoint is healthy.
+	Check(ctx context.Context, e v1alpha2.WorkloadPrometheusEndpoint) error
+}
+
+// A HealthCheckerFn determines the health of a workload by querying its
+// metrics.
+type HealthCheckerFn func(ctx context.Context, e v1alpha2.WorkloadPrometheusEndpoint) error
+
+// Check returns nil if the workload of the supplied endpoint is healthy.
+func (fn HealthCheckerFn) Check(ctx context.Context, e v1alpha2.WorkloadPrometheusEndpoint) error {
+	return fn(ctx, e)
+}
+
+// A PrometheusHealthChecker determines the health of workloads using instant
+// Prometheus queries. Queries time out so that slow Prometheus servers cannot
+// block the reconciliation of scopes, and each server has a circuit breaker:
+// after a number of consecutive failures the server is not queried again,
+// and its workloads are unhealthy, until a cooldown elapses.
+type PrometheusHealthChecker struct {
+	client *http.Client
+
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// A circuitBreaker tracks the consecutive failures of a Prometheus server.
+type circuitBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// A PrometheusHealthCheckerOption configures a PrometheusHealthChecker.
+type PrometheusHealthCheckerOption func(*PrometheusHealthChecker)
+
+// WithQueryTimeout specifies how long a Prometheus query may take.
+func WithQueryTimeout(t time.Duration) PrometheusHealthCheckerOption {
+	return func(c *PrometheusHealthChecker) {
+		c.client.Timeout = t
+	}
+}
+
+// WithCircuitBreaker specifies how many consecutive failures open the
+// circuit breaker of a Prometheus server, and how long it stays open.
+func WithCircuitBreaker(failures int, cooldown time.Duration) PrometheusHealthCheckerOption {
+	return func(c *PrometheusHealthChecker) {
+		c.failureThreshold = failures
+		c.cooldown = cooldown
+	}
+}
+
+// NewPrometheusHealthChecker returns a HealthChecker that queries Prometheus.
+func NewPrometheusHealthChecker(o ...PrometheusHealthCheckerOption) *PrometheusHealthChecker {
+	c := &PrometheusHealthChecker{
+		client:           &http.Client{Timeout: defaultPrometheusTimeout},
+		failureThreshold: defaultFailureThreshold,
+		cooldown:         defaultCircuitOpenTimeout,
+		breakers:         make(map[string]*circuitBreaker),
+	}
+	for _, co := range o {
+		co(c)
+	}
+	return c
+}
+
+// Check returns nil if the result of the supplied endpoint's query is above
+// its healthy threshold.
+func (c *PrometheusHealthChecker) Check(ctx context.Context, e v1alpha2.WorkloadPrometheusEndpoint) error {
+	threshold, err := strconv.ParseFloat(e.HealthyThreshold, 64)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseThreshold, e.HealthyThreshold)
+	}
+	if err := c.allow(e.PrometheusURL); err != nil {
+		return err
+	}
+
+	v, err := c.query(ctx, e.PrometheusURL, e.HealthQuery)
+	c.record(e.PrometheusURL, err)
+	if err != nil {
+		return errors.Wrapf(err, errFmtQueryPrometheus, e.PrometheusURL)
+	}
+	if v <= threshold {
+		return errors.Errorf(errFmtUnhealthyMetric, e.WorkloadName, v, threshold)
+	}
+	return nil
+}
+
+// allow returns an error if the circuit breaker of the supplied server is
+// open. Once it has been open for the cooldown one query is allowed through;
+// the breaker closes if it succeeds, and opens again if it fails.
+func (c *PrometheusHealthChecker) allow(server string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.breakers[server]
+	if !ok || b.failures < c.failureThreshold {
+		return nil
+	}
+	now := time.Now()
+	if now.Before(b.openUntil) {
+		return errors.Errorf(errFmtCircuitOpen, server, b.failures)
+	}
+	// Half open: let this query through, but no others until it completes.
+	b.openUntil = now.Add(c.cooldown)
+	return nil
+}
+
+func (c *PrometheusHealthChecker) record(server string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.breakers, server)
+		return
+	}
+	b, ok := c.breakers[server]
+	if !ok {
+		b = &circuitBreaker{}
+		c.breakers[server] = b
+	}
+	b.failures++
+	if b.failures >= c.failureThreshold {
+		b.openUntil = time.Now().Add(c.cooldown)
+	}
+}
+
+// A prometheusResponse is the response of the Prometheus instant query API.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// query returns the value of the supplied instant query, which must return a
+// scalar or a single element vector.
+func (c *PrometheusHealthChecker) query(ctx context.Context, server, query string) (float64, error) {
+	u := strings.TrimSuffix(server, "/") + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	rsp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+
+	// Prometheus returns error details with 4xx and 5xx status codes.
+	pr := &prometheusResponse{}
+	if err := json.NewDecoder(rsp.Body).Decode(pr); err != nil {
+		if rsp.StatusCode != http.StatusOK {
+			return 0, errors.Errorf(errFmtPrometheusCode, rsp.Status)
+		}
+		return 0, errors.Wrap(err, errDecodeResponse)
+	}
+	if pr.Status != "success" {
+		return 0, errors.Errorf(errFmtPrometheusError, pr.Error)
+	}
+
+	var sample []interface{}
+	switch pr.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(pr.Data.Result, &sample); err != nil {
+			return 0, errors.Wrap(err, errDecodeResponse)
+		}
+	case "vector":
+		vector := []struct {
+			Value []interface{} `json:"value"`
+		}{}
+		if err := json.Unmarshal(pr.Data.Result, &vector); err != nil {
+			return 0, errors.Wrap(err, errDecodeResponse)
+		}
+		if len(vector) == 0 {
+			return 0, errors.New(errNoSamples)
+		}
+		if len(vector) > 1 {
+			return 0, errors.New(errTooManySamples)
+		}
+		sample = vector[0].Value
+	default:
+		return 0, errors.Errorf(errFmtResultType, pr.Data.ResultType)
+	}
+
+	// Samples are [<unix time>, "<value>"].
+	if len(sample) != 2 {
+		return 0, errors.New(errParseSample)
+	}
+	v, err := strconv.ParseFloat(fmt.Sprintf("%v", sample[1]), 64)
+	return v, errors.Wrap(err, errParseSample)
+}
+
+// prometheusHealthStatus returns false if the Prometheus health check of any
+// workload in the supplied healthscope fails. Endpoints of workloads that are
+// not in the scope are ignored.
+func prometheusHealthStatus(ctx context.Context, log logging.Logger, checker HealthChecker, healthScope *v1alpha2.HealthScope) bool {
+	inScope := make(map[string]bool, len(healthScope.Spec.WorkloadReferences))
+	for _, ref := range healthScope.Spec.WorkloadReferences {
+		inScope[ref.Name] = true
+	}
+
+	status := make(chan bool, len(healthScope.Spec.WorkloadPrometheusEndpoints))
+	var wg sync.WaitGroup
+	for _, e := range healthScope.Spec.WorkloadPrometheusEndpoints {
+		if !inScope[e.WorkloadName] {
+			continue
+		}
+		wg.Add(1)
+		go func(e v1alpha2.WorkloadPrometheusEndpoint) {
+			defer wg.Done()
+			err := checker.Check(ctx, e)
+			status <- (err == nil)
+			if err != nil {
+				log.Debug("Unhealthy workload", "workload", e.WorkloadName, "error", err)
+			}
+		}(e)
+	}
+	wg.Wait()
+	close(status)
+
+	healthy := true
+	for s := range status {
+		healthy = healthy && s
+	}
+	return healthy
+}
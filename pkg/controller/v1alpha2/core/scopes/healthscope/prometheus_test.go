@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthscope
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestPrometheusHealthCheckerCheck(t *testing.T) {
+	serve := func(code int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/query" || r.URL.Query().Get("query") != "up" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	cases := map[string]struct {
+		reason    string
+		code      int
+		body      string
+		threshold string
+		want      string
+	}{
+		"HealthyScalar": {
+			reason:    "A scalar above the threshold should be healthy.",
+			code:      http.StatusOK,
+			body:      `{"status":"success","data":{"resultType":"scalar","result":[1600000000,"0.99"]}}`,
+			threshold: "0.95",
+		},
+		"UnhealthyVector": {
+			reason:    "A vector sample that is not above the threshold should be unhealthy.",
+			code:      http.StatusOK,
+			body:      `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1600000000,"0.5"]}]}}`,
+			threshold: "0.95",
+			want:      "health of workload \"myWorkload\" is 0.5, which is not above the healthy threshold of 0.95",
+		},
+		"NoSamples": {
+			reason:    "A query that returns no samples should be unhealthy.",
+			code:      http.StatusOK,
+			body:      `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			threshold: "0.95",
+			want:      errNoSamples,
+		},
+		"QueryError": {
+			reason:    "Errors returned by Prometheus should be returned.",
+			code:      http.StatusBadRequest,
+			body:      `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			threshold: "0.95",
+			want:      "query failed: parse error",
+		},
+		"InvalidThreshold": {
+			reason:    "Thresholds that are not decimal numbers should be rejected.",
+			threshold: "high",
+			want:      `strconv.ParseFloat: parsing "high": invalid syntax`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := serve(tc.code, tc.body)
+			defer srv.Close()
+
+			c := NewPrometheusHealthChecker()
+			err := c.Check(context.Background(), v1alpha2.WorkloadPrometheusEndpoint{
+				WorkloadName:     workloadName,
+				PrometheusURL:    srv.URL,
+				HealthQuery:      "up",
+				HealthyThreshold: tc.threshold,
+			})
+			got := ""
+			if err != nil {
+				got = errors.Cause(err).Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.Check(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrometheusHealthCheckerCircuitBreaker(t *testing.T) {
+	queries := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewPrometheusHealthChecker(WithCircuitBreaker(2, time.Hour))
+	e := v1alpha2.WorkloadPrometheusEndpoint{WorkloadName: workloadName, PrometheusURL: srv.URL, HealthQuery: "up", HealthyThreshold: "0"}
+	for i := 0; i < 3; i++ {
+		if err := c.Check(context.Background(), e); err == nil {
+			t.Fatalf("c.Check(...): want error, got nil")
+		}
+	}
+	if diff := cmp.Diff(2, queries); diff != "" {
+		t.Errorf("c.Check(...): -want queries, +got queries:\n%s", diff)
+	}
+	want := errors.Errorf(errFmtCircuitOpen, srv.URL, 2)
+	if diff := cmp.Diff(want, c.Check(context.Background(), e), test.EquateErrors()); diff != "" {
+		t.Errorf("c.Check(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestPrometheusHealthStatus(t *testing.T) {
+	hs := &v1alpha2.HealthScope{Spec: v1alpha2.HealthScopeSpec{
+		WorkloadReferences: []runtimev1alpha1.TypedReference{{Name: workloadName}},
+		WorkloadPrometheusEndpoints: []v1alpha2.WorkloadPrometheusEndpoint{
+			{WorkloadName: workloadName, HealthQuery: "healthy"},
+			{WorkloadName: "elsewhere", HealthQuery: "unhealthy"},
+		},
+	}}
+
+	cases := map[string]struct {
+		reason  string
+		checker HealthChecker
+		want    bool
+	}{
+		"Healthy": {
+			reason: "Workloads that are not in the scope should be ignored.",
+			checker: HealthCheckerFn(func(_ context.Context, e v1alpha2.WorkloadPrometheusEndpoint) error {
+				if e.HealthQuery == "unhealthy" {
+					return errors.New("unhealthy")
+				}
+				return nil
+			}),
+			want: true,
+		},
+		"Unhealthy": {
+			reason: "The scope should be unhealthy if any check fails.",
+			checker: HealthCheckerFn(func(_ context.Context, _ v1alpha2.WorkloadPrometheusEndpoint) error {
+				return errors.New("unhealthy")
+			}),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := prometheusHealthStatus(context.Background(), logging.NewNopLogger(), tc.checker, hs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nprometheusHealthStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rolloutgroup rolls out the ApplicationConfigurations of a rollout
+// group one at a time.
+package rolloutgroup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+)
+
+// DefaultStepTimeout is how long an ApplicationConfiguration of a rollout
+// group may take to become healthy unless it specifies otherwise.
+const DefaultStepTimeout = 10 * time.Minute
+
+// Reconcile error strings.
+const (
+	errGetAppConfig   = "cannot get application configuration"
+	errListAppConfigs = "cannot list application configurations"
+
+	errFmtPause           = "cannot pause application configuration %q"
+	errFmtResume          = "cannot resume application configuration %q"
+	errFmtStartStep       = "cannot record rollout step start of application configuration %q"
+	errFmtEndStep         = "cannot remove rollout step start of application configuration %q"
+	errFmtRequestRollback = "cannot request rollback of application configuration %q"
+)
+
+// Reconcile event reasons.
+const (
+	reasonPaused            = "PausedByRolloutGroup"
+	reasonResumed           = "ResumedByRolloutGroup"
+	reasonStepTimedOut      = "RolloutStepTimedOut"
+	reasonCannotRollOut     = "CannotRollOutGroup"
+	reasonRollbackRequested = "RollbackRequestedByRolloutGroup"
+)
+
+// Setup adds a controller that rolls out the ApplicationConfigurations of
+// rollout groups.
+func Setup(mgr ctrl.Manager, l logging.Logger) error {
+	name := "oam/rolloutgroup"
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.ApplicationConfiguration{}).
+		Complete(NewReconciler(mgr,
+			WithLogger(l.WithValues("controller", name)),
+			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+}
+
+// A Reconciler rolls out the ApplicationConfigurations of a rollout group in
+// ascending rollout order. Each ApplicationConfiguration is paused until those
+// before it are healthy. If an ApplicationConfiguration does not become
+// healthy within its rollout step timeout the rollback of it and those before
+// it is requested, and the group stops progressing until the rollback request
+// annotations are removed.
+type Reconciler struct {
+	client client.Client
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that rolls out rollout groups.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile the rollout group of an ApplicationConfiguration.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := r.client.Get(ctx, req.NamespacedName, ac); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetAppConfig)
+	}
+	if ac.Spec.RolloutGroup == "" {
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues("group", ac.Spec.RolloutGroup)
+
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := r.client.List(ctx, acs, client.InNamespace(ac.GetNamespace())); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListAppConfigs)
+	}
+	group := members(acs.Items, ac.Spec.RolloutGroup)
+
+	requeue, err := r.rollOut(ctx, log, group)
+	if err != nil {
+		log.Debug("Cannot roll out group", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(ac, event.Warning(reasonCannotRollOut, err))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+	if requeue {
+		// The group is waiting for an ApplicationConfiguration to become
+		// healthy, or to time out.
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// rollOut the supplied ApplicationConfigurations of a rollout group, which
+// must be in rollout order. It returns true if the group is waiting for an
+// ApplicationConfiguration to become healthy.
+func (r *Reconciler) rollOut(ctx context.Context, log logging.Logger, group []*v1alpha2.ApplicationConfiguration) (bool, error) {
+	// ready is true while all ApplicationConfigurations before the current
+	// one are healthy.
+	ready := true
+	waiting := false
+	for i, ac := range group {
+		requested := hasAnnotation(ac, oam.AnnotationRollbackRequested)
+		if !ready && !requested {
+			if err := r.pause(ctx, ac); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if err := r.resume(ctx, ac); err != nil {
+			return false, err
+		}
+		if requested {
+			// The group does not progress while it is rolled back.
+			ready = false
+			continue
+		}
+		if healthy(ac) {
+			if err := r.endStep(ctx, ac); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		ready = false
+		waiting = true
+		started, err := r.startStep(ctx, ac)
+		if err != nil {
+			return false, err
+		}
+		if time.Since(started) < stepTimeout(ac) {
+			continue
+		}
+		log.Debug("Rollout step timed out", "name", ac.GetName(), "started", started)
+		r.record.Event(ac, event.Warning(reasonStepTimedOut, errors.Errorf("not healthy within %s", stepTimeout(ac))))
+		if err := r.requestRollback(ctx, group[:i+1]); err != nil {
+			return false, err
+		}
+		waiting = false
+	}
+	return waiting, nil
+}
+
+// pause the supplied ApplicationConfiguration unless it is already paused.
+func (r *Reconciler) pause(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
+	if ac.GetAnnotations()[oam.AnnotationPaused] == "true" {
+		return nil
+	}
+	err := r.patch(ctx, ac, func(a map[string]string) {
+		a[oam.AnnotationPaused] = "true"
+		a[oam.AnnotationRolloutGroupPaused] = "true"
+	})
+	if err != nil {
+		return errors.Wrapf(err, errFmtPause, ac.GetName())
+	}
+	r.record.Event(ac, event.Normal(reasonPaused, "Paused until the ApplicationConfigurations before it in its rollout group are healthy"))
+	return nil
+}
+
+// resume the supplied ApplicationConfiguration if it was paused by its
+// rollout group.
+func (r *Reconciler) resume(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
+	if ac.GetAnnotations()[oam.AnnotationRolloutGroupPaused] != "true" {
+		return nil
+	}
+	err := r.patch(ctx, ac, func(a map[string]string) {
+		delete(a, oam.AnnotationPaused)
+		delete(a, oam.AnnotationRolloutGroupPaused)
+	})
+	if err != nil {
+		return errors.Wrapf(err, errFmtResume, ac.GetName())
+	}
+	r.record.Event(ac, event.Normal(reasonResumed, "Resumed by its rollout group"))
+	return nil
+}
+
+// startStep returns the time at which its rollout group started waiting for
+// the supplied ApplicationConfiguration to become healthy, recording it if
+// the group only just started waiting.
+func (r *Reconciler) startStep(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) (time.Time, error) {
+	if started, err := time.Parse(time.RFC3339, ac.GetAnnotations()[oam.AnnotationRolloutStepStarted]); err == nil {
+		return started, nil
+	}
+	now := time.Now()
+	err := r.patch(ctx, ac, func(a map[string]string) {
+		a[oam.AnnotationRolloutStepStarted] = now.Format(time.RFC3339)
+	})
+	return now, errors.Wrapf(err, errFmtStartStep, ac.GetName())
+}
+
+// endStep removes the rollout step start time of the supplied healthy
+// ApplicationConfiguration, if any.
+func (r *Reconciler) endStep(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
+	if !hasAnnotation(ac, oam.AnnotationRolloutStepStarted) {
+		return nil
+	}
+	err := r.patch(ctx, ac, func(a map[string]string) {
+		delete(a, oam.AnnotationRolloutStepStarted)
+	})
+	return errors.Wrapf(err, errFmtEndStep, ac.GetName())
+}
+
+// requestRollback of the supplied ApplicationConfigurations.
+func (r *Reconciler) requestRollback(ctx context.Context, acs []*v1alpha2.ApplicationConfiguration) error {
+	now := time.Now().Format(time.RFC3339)
+	for _, ac := range acs {
+		err := r.patch(ctx, ac, func(a map[string]string) {
+			a[oam.AnnotationRollbackRequested] = now
+			delete(a, oam.AnnotationRolloutStepStarted)
+		})
+		if err != nil {
+			return errors.Wrapf(err, errFmtRequestRollback, ac.GetName())
+		}
+		r.record.Event(ac, event.Normal(reasonRollbackRequested, "Requested rollback because a step of its rollout group timed out"))
+	}
+	return nil
+}
+
+// patch the annotations of the supplied ApplicationConfiguration.
+func (r *Reconciler) patch(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, fn func(a map[string]string)) error {
+	original := ac.DeepCopy()
+	a := ac.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	fn(a)
+	ac.SetAnnotations(a)
+	return r.client.Patch(ctx, ac, client.MergeFrom(original))
+}
+
+// members returns the ApplicationConfigurations of the named rollout group in
+// ascending rollout order, then by name.
+func members(acs []v1alpha2.ApplicationConfiguration, group string) []*v1alpha2.ApplicationConfiguration {
+	m := make([]*v1alpha2.ApplicationConfiguration, 0)
+	for i := range acs {
+		if acs[i].Spec.RolloutGroup == group && acs[i].GetDeletionTimestamp() == nil {
+			m = append(m, &acs[i])
+		}
+	}
+	sort.SliceStable(m, func(i, j int) bool {
+		if m[i].Spec.RolloutOrder != m[j].Spec.RolloutOrder {
+			return m[i].Spec.RolloutOrder < m[j].Spec.RolloutOrder
+		}
+		return m[i].GetName() < m[j].GetName()
+	})
+	return m
+}
+
+// stepTimeout returns how long the supplied ApplicationConfiguration may take
+// to become healthy.
+func stepTimeout(ac *v1alpha2.ApplicationConfiguration) time.Duration {
+	if ac.Spec.RolloutStepTimeout == nil {
+		return DefaultStepTimeout
+	}
+	return ac.Spec.RolloutStepTimeout.Duration
+}
+
+// healthy returns true if the supplied ApplicationConfiguration was
+// reconciled since it was last paused, and its workloads are healthy.
+func healthy(ac *v1alpha2.ApplicationConfiguration) bool {
+	return ac.Status.State == v1alpha2.StateReady && ac.Status.Health == v1alpha2.HealthStatusHealthy
+}
+
+func hasAnnotation(ac *v1alpha2.ApplicationConfiguration, key string) bool {
+	_, ok := ac.GetAnnotations()[key]
+	return ok
+}
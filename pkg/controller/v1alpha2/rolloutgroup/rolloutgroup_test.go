@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutgroup
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestRollOut(t *testing.T) {
+	errBoom := errors.New("boom")
+	longAgo := time.Now().Add(-1 * time.Hour).Format(time.RFC3339)
+
+	ac := func(name string, state v1alpha2.ApplicationConfigurationState, h v1alpha2.HealthStatus, annotations map[string]string) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Status:     v1alpha2.ApplicationConfigurationStatus{State: state, Health: h},
+		}
+	}
+	// groupPaused returns new annotations each time, since they are patched
+	// in place.
+	groupPaused := func() map[string]string {
+		return map[string]string{oam.AnnotationPaused: "true", oam.AnnotationRolloutGroupPaused: "true"}
+	}
+
+	type want struct {
+		waiting     bool
+		err         error
+		annotations map[string][]string
+	}
+	cases := map[string]struct {
+		reason   string
+		group    []v1alpha2.ApplicationConfiguration
+		patchErr error
+		want     want
+	}{
+		"Progressing": {
+			reason: "ApplicationConfigurations after one that is not yet healthy should be paused, and its step start recorded",
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusProgressing, nil),
+				ac("b", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, nil),
+			},
+			want: want{waiting: true, annotations: map[string][]string{
+				"a": {oam.AnnotationRolloutStepStarted},
+				"b": {oam.AnnotationPaused, oam.AnnotationRolloutGroupPaused},
+			}},
+		},
+		"Healthy": {
+			reason: "The ApplicationConfiguration after a healthy one should be resumed, and not count as healthy until it is reconciled",
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, map[string]string{oam.AnnotationRolloutStepStarted: longAgo}),
+				ac("b", v1alpha2.StatePaused, v1alpha2.HealthStatusHealthy, groupPaused()),
+				ac("c", v1alpha2.StatePaused, v1alpha2.HealthStatusHealthy, groupPaused()),
+			},
+			want: want{waiting: true, annotations: map[string][]string{
+				"a": {},
+				"b": {oam.AnnotationRolloutStepStarted},
+				"c": {oam.AnnotationPaused, oam.AnnotationRolloutGroupPaused},
+			}},
+		},
+		"UserPaused": {
+			reason: "ApplicationConfigurations paused by a user should not be resumed",
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, nil),
+				ac("b", v1alpha2.StatePaused, v1alpha2.HealthStatusHealthy, map[string]string{oam.AnnotationPaused: "true"}),
+			},
+			want: want{waiting: true, annotations: map[string][]string{
+				"a": {},
+				"b": {oam.AnnotationPaused, oam.AnnotationRolloutStepStarted},
+			}},
+		},
+		"TimedOut": {
+			reason: "The rollback of an ApplicationConfiguration that timed out, and of those before it, should be requested",
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, nil),
+				ac("b", v1alpha2.StateReady, v1alpha2.HealthStatusUnhealthy, map[string]string{oam.AnnotationRolloutStepStarted: longAgo}),
+				ac("c", v1alpha2.StatePaused, v1alpha2.HealthStatusHealthy, groupPaused()),
+			},
+			want: want{annotations: map[string][]string{
+				"a": {oam.AnnotationRollbackRequested},
+				"b": {oam.AnnotationRollbackRequested},
+				"c": {oam.AnnotationPaused, oam.AnnotationRolloutGroupPaused},
+			}},
+		},
+		"RollingBack": {
+			reason: "A rollout group should not progress while it is rolled back",
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, map[string]string{oam.AnnotationRollbackRequested: longAgo}),
+				ac("b", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, nil),
+			},
+			want: want{annotations: map[string][]string{
+				"a": {oam.AnnotationRollbackRequested},
+				"b": {oam.AnnotationPaused, oam.AnnotationRolloutGroupPaused},
+			}},
+		},
+		"PatchError": {
+			reason:   "Errors pausing an ApplicationConfiguration should be returned",
+			patchErr: errBoom,
+			group: []v1alpha2.ApplicationConfiguration{
+				ac("a", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, map[string]string{oam.AnnotationRollbackRequested: longAgo}),
+				ac("b", v1alpha2.StateReady, v1alpha2.HealthStatusHealthy, nil),
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtPause, "b"), annotations: map[string][]string{
+				"a": {oam.AnnotationRollbackRequested},
+				"b": {oam.AnnotationPaused, oam.AnnotationRolloutGroupPaused},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				client: &test.MockClient{MockPatch: func(_ context.Context, _ runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					return tc.patchErr
+				}},
+				log:    logging.NewNopLogger(),
+				record: event.NewNopRecorder(),
+			}
+			group := members(tc.group, "")
+			waiting, err := r.rollOut(context.Background(), r.log, group)
+
+			got := want{waiting: waiting, err: err, annotations: make(map[string][]string)}
+			for _, ac := range group {
+				keys := make([]string, 0)
+				for k := range ac.GetAnnotations() {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				got.annotations[ac.GetName()] = keys
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.rollOut(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	ac := func(name, group string, order int32) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha2.ApplicationConfigurationSpec{RolloutGroup: group, RolloutOrder: order},
+		}
+	}
+	acs := []v1alpha2.ApplicationConfiguration{
+		ac("c", "blue-green", 1),
+		ac("other", "canary", 0),
+		ac("b", "blue-green", 0),
+		ac("a", "blue-green", 1),
+	}
+
+	names := make([]string, 0)
+	for _, m := range members(acs, "blue-green") {
+		names = append(names, m.GetName())
+	}
+	if diff := cmp.Diff([]string{"b", "a", "c"}, names); diff != "" {
+		t.Errorf("members(...): -want, +got:\n%s", diff)
+	}
+}
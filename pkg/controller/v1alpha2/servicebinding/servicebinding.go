@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servicebinding resolves the connection information to which the
+// components of ApplicationConfigurations are bound.
+package servicebinding
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetAppConfig = "cannot get application configuration"
+	errApplySecret  = "cannot apply service binding secret"
+
+	errFmtNotApplied          = "bound component %q has not been applied"
+	errFmtGetWorkload         = "cannot get workload of bound component %q"
+	errFmtGetDefinition       = "cannot get workload definition of bound component %q"
+	errFmtNotBindable         = "workload of bound component %q is not bindable"
+	errFmtNoBindingPath       = "workload definition of bound component %q has no binding path"
+	errFmtGetBinding          = "cannot get connection information of bound component %q"
+	errFmtMarshalBindingValue = "cannot marshal connection information value %q"
+)
+
+// Reconcile event reasons.
+const (
+	reasonBind          = "BoundComponents"
+	reasonCannotResolve = "CannotResolveServiceBinding"
+	reasonCannotBind    = "CannotBindComponents"
+)
+
+// Setup adds a controller that resolves the service bindings of
+// ApplicationConfigurations.
+func Setup(mgr ctrl.Manager, l logging.Logger) error {
+	name := "oam/servicebinding"
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.ApplicationConfiguration{}).
+		Owns(&corev1.Secret{}).
+		Complete(NewReconciler(mgr,
+			WithLogger(l.WithValues("controller", name)),
+			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+}
+
+// A Reconciler resolves the connection information to which the components
+// of an ApplicationConfiguration are bound, and stores it in a Secret per
+// bound component from which the ApplicationConfiguration controller injects
+// it into the component's workload.
+type Reconciler struct {
+	client     client.Client
+	applicator resource.Applicator
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithApplicator specifies how the Reconciler should apply the Secrets that
+// contain connection information.
+func WithApplicator(a resource.Applicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applicator = a
+	}
+}
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that resolves service bindings.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:     m.GetClient(),
+		applicator: resource.NewAPIPatchingApplicator(m.GetClient()),
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile the service bindings of an ApplicationConfiguration.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := r.client.Get(ctx, req.NamespacedName, ac); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetAppConfig)
+	}
+
+	log = log.WithValues("uid", ac.GetUID(), "version", ac.GetResourceVersion())
+
+	bound := 0
+	for _, acc := range ac.Spec.Components {
+		if acc.ServiceBindingRef == nil {
+			continue
+		}
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = applicationconfiguration.ExtractComponentName(acc.RevisionName)
+		}
+
+		// The bound component may not have been applied yet, or may not yet
+		// have published its connection information.
+		data, err := r.resolve(ctx, ac, acc.ServiceBindingRef.ComponentName)
+		if err != nil {
+			log.Debug("Cannot resolve service binding", "component", name, "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(ac, event.Warning(reasonCannotResolve, err))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+
+		if err := r.applicator.Apply(ctx, bindingSecret(ac, name, data), resource.MustBeControllableBy(ac.GetUID())); err != nil {
+			log.Debug("Cannot apply service binding secret", "component", name, "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(ac, event.Warning(reasonCannotBind, errors.Wrap(err, errApplySecret)))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+		bound++
+	}
+
+	if bound > 0 {
+		log.Debug("Successfully bound components", "components", bound)
+		r.record.Event(ac, event.Normal(reasonBind, "Successfully bound components"))
+	}
+
+	// Connection information may change at any time, so we check it again
+	// after a while.
+	return reconcile.Result{RequeueAfter: longWait}, nil
+}
+
+// resolve the connection information of the workload of the supplied
+// component of the supplied ApplicationConfiguration.
+func (r *Reconciler) resolve(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, component string) (map[string][]byte, error) {
+	var ref *v1alpha2.WorkloadStatus
+	for i := range ac.Status.Workloads {
+		if ac.Status.Workloads[i].ComponentName == component {
+			ref = &ac.Status.Workloads[i]
+			break
+		}
+	}
+	if ref == nil {
+		return nil, errors.Errorf(errFmtNotApplied, component)
+	}
+
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion(ref.Reference.APIVersion)
+	w.SetKind(ref.Reference.Kind)
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ac.GetNamespace(), Name: ref.Reference.Name}, w); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetWorkload, component)
+	}
+
+	wd, err := util.FetchWorkloadDefinition(ctx, r.client, w)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetDefinition, component)
+	}
+	if !wd.Spec.Bindable {
+		return nil, errors.Errorf(errFmtNotBindable, component)
+	}
+	if wd.Spec.BindingPath == "" {
+		return nil, errors.Errorf(errFmtNoBindingPath, component)
+	}
+
+	v, err := fieldpath.Pave(w.UnstructuredContent()).GetValue(wd.Spec.BindingPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetBinding, component)
+	}
+	values, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf(errFmtGetBinding, component)
+	}
+	return secretData(values)
+}
+
+// secretData returns the supplied connection information as Secret data.
+// String values are stored as is, and other values as JSON.
+func secretData(values map[string]interface{}) (map[string][]byte, error) {
+	data := make(map[string][]byte, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			data[k] = []byte(s)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMarshalBindingValue, k)
+		}
+		data[k] = b
+	}
+	return data, nil
+}
+
+// bindingSecret returns the Secret that contains the connection information
+// to which the supplied component is bound.
+func bindingSecret(ac *v1alpha2.ApplicationConfiguration, component string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       ac.GetNamespace(),
+			Name:            util.ServiceBindingSecretName(ac.GetName(), component),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)},
+		},
+		Data: data,
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ac := func(applied bool) *v1alpha2.ApplicationConfiguration {
+		ac := &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app", UID: "app-uid"},
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "db"},
+				{ComponentName: "web", ServiceBindingRef: &v1alpha2.ServiceBindingReference{ComponentName: "db"}},
+			}},
+		}
+		if applied {
+			ac.Status.Workloads = []v1alpha2.WorkloadStatus{{
+				ComponentName: "db",
+				Reference:     runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "Database", Name: "db"},
+			}}
+		}
+		return ac
+	}
+	get := func(applied, bindable bool) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.ApplicationConfiguration:
+				*o = *ac(applied)
+			case *unstructured.Unstructured:
+				o.Object["status"] = map[string]interface{}{"binding": map[string]interface{}{"host": "db.example.org", "port": int64(5432)}}
+			case *v1alpha2.WorkloadDefinition:
+				o.Spec.Bindable = bindable
+				o.Spec.BindingPath = "status.binding"
+			}
+			return nil
+		})
+	}
+
+	type args struct {
+		m manager.Manager
+		o []ReconcilerOption
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+		secret *corev1.Secret
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetAppConfigError": {
+			reason: "Errors getting the ApplicationConfiguration under reconciliation should be returned",
+			args: args{
+				m: &mock.Manager{Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetAppConfig)},
+		},
+		"NotApplied": {
+			reason: "We should wait for the bound component to be applied",
+			args: args{
+				m: &mock.Manager{Client: &test.MockClient{MockGet: get(false, true)}},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"NotBindable": {
+			reason: "We should not bind to workloads whose definition is not bindable",
+			args: args{
+				m: &mock.Manager{Client: &test.MockClient{MockGet: get(true, false)}},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Bound": {
+			reason: "The connection information of the bound workload should be stored in a Secret",
+			args: args{
+				m: &mock.Manager{Client: &test.MockClient{MockGet: get(true, true)}},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: longWait},
+				secret: &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "ns",
+						Name:            "app-web-binding",
+						OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ac(true), v1alpha2.ApplicationConfigurationGroupVersionKind)},
+					},
+					Data: map[string][]byte{"host": []byte("db.example.org"), "port": []byte("5432")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied *corev1.Secret
+			o := append([]ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, obj runtime.Object, _ ...resource.ApplyOption) error {
+				applied = obj.(*corev1.Secret)
+				return nil
+			}))}, tc.args.o...)
+			r := NewReconciler(tc.args.m, o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "app"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secret, applied); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want secret, +got secret:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -25,12 +25,22 @@ import (
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/healthscope"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/manualscalertrait"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/rolloutgroup"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/servicebinding"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/traitdefinition"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/workloaddefinition"
 )
 
-// Setup workload controllers.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// Setup workload controllers. The supplied options configure the
+// ApplicationConfiguration reconciler.
+func Setup(mgr ctrl.Manager, l logging.Logger, o ...applicationconfiguration.ReconcilerOption) error {
+	if err := applicationconfiguration.Setup(mgr, l, o...); err != nil {
+		return err
+	}
 	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		applicationconfiguration.Setup, containerizedworkload.Setup, manualscalertrait.Setup, healthscope.Setup,
+		containerizedworkload.Setup, manualscalertrait.Setup, healthscope.Setup,
+		workloaddefinition.Setup, traitdefinition.Setup, servicebinding.Setup,
+		rolloutgroup.Setup,
 	} {
 		if err := setup(mgr, l); err != nil {
 			return err
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traitdefinition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+)
+
+// Reconcile error strings.
+const (
+	errGetTraitDefinition    = "cannot get trait definition"
+	errUpdateTraitDefinition = "cannot update trait definition status"
+	errGetCRD                = "cannot get CustomResourceDefinition"
+	errListTraitDefinitions  = "cannot list trait definitions that may reference CustomResourceDefinition"
+
+	msgFmtCRDNotInstalled = "CustomResourceDefinition %q is not installed"
+)
+
+// Reconcile event reasons.
+const (
+	reasonCRDInstalled    = "CRDInstalled"
+	reasonCRDNotInstalled = "CRDNotInstalled"
+	reasonGetCRDFailed    = "GetCRDFailed"
+)
+
+// Annotations from which the installed version of a CustomResourceDefinition
+// is read, in order of precedence.
+const (
+	annotationHelmChart  = "helm.sh/chart"
+	annotationAppVersion = "app.kubernetes.io/version"
+)
+
+// Setup adds a controller that reconciles TraitDefinitions.
+func Setup(mgr ctrl.Manager, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.TraitDefinitionGroupKind)
+	log := l.WithValues("controller", name)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.TraitDefinition{}).
+		Watches(&source.Kind{Type: newCRD()}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &crdMapper{client: mgr.GetClient(), log: log},
+		}).
+		Complete(NewReconciler(mgr,
+			WithLogger(log),
+			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+}
+
+// A Reconciler reconciles TraitDefinitions by recording the installed version
+// of the CustomResourceDefinition they reference.
+type Reconciler struct {
+	client client.Client
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that reconciles TraitDefinitions.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a TraitDefinition by recording the installed version of the
+// CustomResourceDefinition it references.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	td := &v1alpha2.TraitDefinition{}
+	if err := r.client.Get(ctx, req.NamespacedName, td); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetTraitDefinition)
+	}
+
+	log = log.WithValues("uid", td.GetUID(), "version", td.GetResourceVersion())
+
+	name := td.Spec.Reference.Name
+	crd := newCRD()
+	err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd)
+	if kerrors.IsNotFound(err) {
+		msg := fmt.Sprintf(msgFmtCRDNotInstalled, name)
+		log.Debug("CustomResourceDefinition is not installed", "crd", name)
+		r.record.Event(td, event.Warning(reasonCRDNotInstalled, errors.New(msg)))
+		td.Status.InstalledVersion = ""
+		td.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionFalse, v1alpha2.ReasonCRDNotInstalled, msg), runtimev1alpha1.ReconcileSuccess())
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, td), errUpdateTraitDefinition)
+	}
+	if err != nil {
+		log.Debug("Cannot get CustomResourceDefinition", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(td, event.Warning(reasonGetCRDFailed, err))
+		td.SetConditions(runtimev1alpha1.ReconcileError(errors.Wrap(err, errGetCRD)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, td), errUpdateTraitDefinition)
+	}
+
+	v := installedVersion(crd.GetAnnotations())
+	if v != td.Status.InstalledVersion {
+		r.record.Event(td, event.Normal(reasonCRDInstalled, "Installed CustomResourceDefinition version changed", "crd", name, "version", v))
+	}
+	td.Status.InstalledVersion = v
+	td.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, td), errUpdateTraitDefinition)
+}
+
+// installedVersion returns the version of a CustomResourceDefinition with the
+// supplied annotations. The helm.sh/chart annotation takes precedence over
+// the app.kubernetes.io/version annotation. An empty string is returned if
+// neither annotation is set.
+func installedVersion(annotations map[string]string) string {
+	if v := annotations[annotationHelmChart]; v != "" {
+		return v
+	}
+	return annotations[annotationAppVersion]
+}
+
+// newCRD returns an empty CustomResourceDefinition. CustomResourceDefinitions
+// are read as unstructured objects so that the apiextensions types need not be
+// registered with the manager's scheme.
+func newCRD() *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	return crd
+}
+
+// A crdMapper maps a CustomResourceDefinition to the TraitDefinitions that
+// reference it.
+type crdMapper struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+var _ handler.Mapper = &crdMapper{}
+
+func (m *crdMapper) Map(o handler.MapObject) []reconcile.Request {
+	tds := &v1alpha2.TraitDefinitionList{}
+	if err := m.client.List(context.Background(), tds); err != nil {
+		m.log.Debug(errListTraitDefinitions, "error", err, "crd", o.Meta.GetName())
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, td := range tds.Items {
+		if td.Spec.Reference.Name != o.Meta.GetName() {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: td.GetName()}})
+	}
+	return reqs
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traitdefinition
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnexpectedStatus := errors.New("unexpected status")
+
+	crd := "cooltraits.example.org"
+
+	td := func(version string, c ...runtimev1alpha1.Condition) *v1alpha2.TraitDefinition {
+		td := &v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{
+			Reference: v1alpha2.DefinitionReference{Name: crd},
+		}}
+		td.Status.InstalledVersion = version
+		td.SetConditions(c...)
+		return td
+	}
+
+	statusUpdate := func(want *v1alpha2.TraitDefinition) test.MockStatusUpdateFn {
+		return test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
+			if diff := cmp.Diff(want, o.(*v1alpha2.TraitDefinition), cmpopts.EquateEmpty(),
+				cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+				return errUnexpectedStatus
+			}
+			return nil
+		})
+	}
+
+	get := func(crdErr error, annotations map[string]string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, o runtime.Object) error {
+			switch o := o.(type) {
+			case *v1alpha2.TraitDefinition:
+				*o = *td("v0.1.0")
+			case *unstructured.Unstructured:
+				if key.Name != crd {
+					return errUnexpectedStatus
+				}
+				o.SetAnnotations(annotations)
+				return crdErr
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		m manager.Manager
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetTraitDefinitionError": {
+			reason: "Errors getting the TraitDefinition under reconciliation should be returned",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetTraitDefinition),
+			},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CustomResourceDefinition should be reflected as a status condition",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet:          get(errBoom, nil),
+						MockStatusUpdate: statusUpdate(td("v0.1.0", runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errGetCRD)))),
+					},
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"CRDNotInstalled": {
+			reason: "A missing CustomResourceDefinition should clear the installed version",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: get(kerrors.NewNotFound(schema.GroupResource{}, crd), nil),
+						MockStatusUpdate: statusUpdate(td("",
+							v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionFalse, v1alpha2.ReasonCRDNotInstalled, fmt.Sprintf(msgFmtCRDNotInstalled, crd)),
+							runtimev1alpha1.ReconcileSuccess(),
+						)),
+					},
+				},
+			},
+		},
+		"HelmChartVersion": {
+			reason: "The helm.sh/chart annotation should take precedence over the app.kubernetes.io/version annotation",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: get(nil, map[string]string{
+							annotationHelmChart:  "cool-traits-v0.2.0",
+							annotationAppVersion: "v0.2.0",
+						}),
+						MockStatusUpdate: statusUpdate(td("cool-traits-v0.2.0", v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())),
+					},
+				},
+			},
+		},
+		"AppVersion": {
+			reason: "The app.kubernetes.io/version annotation should be used when the CRD was not installed by Helm",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet:          get(nil, map[string]string{annotationAppVersion: "v0.2.0"}),
+						MockStatusUpdate: statusUpdate(td("v0.2.0", v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())),
+					},
+				},
+			},
+		},
+		"NoVersion": {
+			reason: "A CustomResourceDefinition without version annotations should clear the installed version",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet:          get(nil, nil),
+						MockStatusUpdate: statusUpdate(td("", v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())),
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m)
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDMapper(t *testing.T) {
+	referencing := v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "referencing"},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "cooltraits.example.org"}},
+	}
+	other := v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "othertraits.example.org"}},
+	}
+
+	m := &crdMapper{
+		client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.TraitDefinitionList).Items = []v1alpha2.TraitDefinition{referencing, other}
+			return nil
+		})},
+		log: logging.NewNopLogger(),
+	}
+
+	crd := newCRD()
+	crd.SetName("cooltraits.example.org")
+	got := m.Map(handler.MapObject{Meta: crd, Object: crd})
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "referencing"}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("m.Map(...): -want, +got:\n%s", diff)
+	}
+}
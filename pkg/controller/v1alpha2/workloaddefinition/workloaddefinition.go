@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloaddefinition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetWorkloadDefinition    = "cannot get workload definition"
+	errUpdateWorkloadDefinition = "cannot update workload definition status"
+	errCheckCRD                 = "cannot check whether the CustomResourceDefinition is installed"
+	errDiscoverGroups           = "cannot discover API groups"
+	errFmtDiscoverResources     = "cannot discover API resources for %q"
+	errIndexCategories          = "cannot index workload definition categories"
+	errIndexTags                = "cannot index workload definition tags"
+
+	msgFmtCRDNotInstalled = "CustomResourceDefinition %q is not served by the API server"
+)
+
+// Reconcile event reasons.
+const (
+	reasonCRDInstalled    = "CRDInstalled"
+	reasonCRDNotInstalled = "CRDNotInstalled"
+	reasonCheckCRDFailed  = "CheckCRDFailed"
+)
+
+// Setup adds a controller that reconciles WorkloadDefinitions.
+func Setup(mgr ctrl.Manager, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.WorkloadDefinitionGroupKind)
+
+	// WorkloadDefinitions are indexed so that they may be listed by
+	// category or tag.
+	fi := mgr.GetFieldIndexer()
+	if err := fi.IndexField(context.Background(), &v1alpha2.WorkloadDefinition{}, util.IndexWorkloadDefinitionCategory, util.WorkloadDefinitionCategories); err != nil {
+		return errors.Wrap(err, errIndexCategories)
+	}
+	if err := fi.IndexField(context.Background(), &v1alpha2.WorkloadDefinition{}, util.IndexWorkloadDefinitionTag, util.WorkloadDefinitionTags); err != nil {
+		return errors.Wrap(err, errIndexTags)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.WorkloadDefinition{}).
+		Complete(NewReconciler(mgr,
+			WithLogger(l.WithValues("controller", name)),
+			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+}
+
+// A CRDChecker checks whether a CustomResourceDefinition is installed.
+type CRDChecker interface {
+	// Installed returns true if the named CustomResourceDefinition is served
+	// by the API server.
+	Installed(ctx context.Context, name string) (bool, error)
+}
+
+// A CRDCheckFn checks whether a CustomResourceDefinition is installed.
+type CRDCheckFn func(ctx context.Context, name string) (bool, error)
+
+// Installed returns true if the named CustomResourceDefinition is served by
+// the API server.
+func (fn CRDCheckFn) Installed(ctx context.Context, name string) (bool, error) {
+	return fn(ctx, name)
+}
+
+// A Reconciler reconciles WorkloadDefinitions by checking whether the
+// CustomResourceDefinition they reference is installed.
+type Reconciler struct {
+	client client.Client
+	crds   CRDChecker
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithCRDChecker specifies how the Reconciler should check whether a
+// CustomResourceDefinition is installed.
+func WithCRDChecker(c CRDChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.crds = c
+	}
+}
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that reconciles WorkloadDefinitions.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		crds:   &discoveryCRDChecker{discovery: discovery.NewDiscoveryClientForConfigOrDie(m.GetConfig())},
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a WorkloadDefinition by checking whether the
+// CustomResourceDefinition it references is installed.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	wd := &v1alpha2.WorkloadDefinition{}
+	if err := r.client.Get(ctx, req.NamespacedName, wd); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetWorkloadDefinition)
+	}
+
+	log = log.WithValues("uid", wd.GetUID(), "version", wd.GetResourceVersion())
+
+	crd := wd.Spec.Reference.Name
+	installed, err := r.crds.Installed(ctx, crd)
+	if err != nil {
+		log.Debug("Cannot check CustomResourceDefinition", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(wd, event.Warning(reasonCheckCRDFailed, err))
+		wd.SetConditions(runtimev1alpha1.ReconcileError(errors.Wrap(err, errCheckCRD)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, wd), errUpdateWorkloadDefinition)
+	}
+
+	if !installed {
+		msg := fmt.Sprintf(msgFmtCRDNotInstalled, crd)
+		log.Debug("CustomResourceDefinition is not installed", "crd", crd, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(wd, event.Warning(reasonCRDNotInstalled, errors.New(msg)))
+		wd.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionFalse, v1alpha2.ReasonCRDNotInstalled, msg), runtimev1alpha1.ReconcileSuccess())
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, wd), errUpdateWorkloadDefinition)
+	}
+
+	r.record.Event(wd, event.Normal(reasonCRDInstalled, "CustomResourceDefinition is installed", "crd", crd))
+	wd.SetConditions(v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, wd), errUpdateWorkloadDefinition)
+}
+
+// discoveryCRDChecker checks whether a CustomResourceDefinition is installed
+// by looking for the resource it defines in the API server's discovery
+// endpoint.
+type discoveryCRDChecker struct {
+	discovery discovery.DiscoveryInterface
+}
+
+// Installed returns true if the resource defined by the named
+// CustomResourceDefinition, e.g. deployments.apps, is served in any version of
+// its API group.
+func (c *discoveryCRDChecker) Installed(_ context.Context, name string) (bool, error) {
+	plural, group := name, ""
+	if i := strings.Index(name, "."); i >= 0 {
+		plural, group = name[:i], name[i+1:]
+	}
+
+	groups, err := c.discovery.ServerGroups()
+	if err != nil {
+		return false, errors.Wrap(err, errDiscoverGroups)
+	}
+
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		for _, v := range g.Versions {
+			l, err := c.discovery.ServerResourcesForGroupVersion(v.GroupVersion)
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtDiscoverResources, v.GroupVersion)
+			}
+			for _, r := range l.APIResources {
+				if r.Name == plural {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
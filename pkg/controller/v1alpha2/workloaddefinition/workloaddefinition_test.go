@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloaddefinition
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnexpectedStatus := errors.New("unexpected status")
+
+	crd := "coolworkloads.example.org"
+
+	wd := func(c ...runtimev1alpha1.Condition) *v1alpha2.WorkloadDefinition {
+		wd := &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+			Reference: v1alpha2.DefinitionReference{Name: crd},
+		}}
+		wd.SetConditions(c...)
+		return wd
+	}
+
+	statusUpdate := func(want *v1alpha2.WorkloadDefinition) test.MockStatusUpdateFn {
+		return test.NewMockStatusUpdateFn(nil, func(o runtime.Object) error {
+			if diff := cmp.Diff(want, o.(*v1alpha2.WorkloadDefinition), cmpopts.EquateEmpty(),
+				cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\nclient.Status().Update(): -want, +got:\n%s", diff)
+				return errUnexpectedStatus
+			}
+			return nil
+		})
+	}
+
+	getFn := test.NewMockGetFn(nil, func(o runtime.Object) error {
+		*o.(*v1alpha2.WorkloadDefinition) = *wd()
+		return nil
+	})
+
+	type args struct {
+		m manager.Manager
+		o []ReconcilerOption
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition under reconciliation should be returned",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetWorkloadDefinition),
+			},
+		},
+		"CheckCRDError": {
+			reason: "Errors checking the CustomResourceDefinition should be reflected as a status condition",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet:          getFn,
+						MockStatusUpdate: statusUpdate(wd(runtimev1alpha1.ReconcileError(errors.Wrap(errBoom, errCheckCRD)))),
+					},
+				},
+				o: []ReconcilerOption{
+					WithCRDChecker(CRDCheckFn(func(_ context.Context, _ string) (bool, error) {
+						return false, errBoom
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"CRDNotInstalled": {
+			reason: "A missing CustomResourceDefinition should be reflected as a CRDInstalled=False condition",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet: getFn,
+						MockStatusUpdate: statusUpdate(wd(
+							v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionFalse, v1alpha2.ReasonCRDNotInstalled, fmt.Sprintf(msgFmtCRDNotInstalled, crd)),
+							runtimev1alpha1.ReconcileSuccess(),
+						)),
+					},
+				},
+				o: []ReconcilerOption{
+					WithCRDChecker(CRDCheckFn(func(_ context.Context, _ string) (bool, error) {
+						return false, nil
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"CRDInstalled": {
+			reason: "An installed CustomResourceDefinition should be reflected as a CRDInstalled=True condition",
+			args: args{
+				m: &mock.Manager{
+					Client: &test.MockClient{
+						MockGet:          getFn,
+						MockStatusUpdate: statusUpdate(wd(v1alpha2.NewCondition(v1alpha2.TypeCRDInstalled, corev1.ConditionTrue, v1alpha2.ReasonCRDInstalled, ""), runtimev1alpha1.ReconcileSuccess())),
+					},
+				},
+				o: []ReconcilerOption{
+					WithCRDChecker(CRDCheckFn(func(_ context.Context, name string) (bool, error) {
+						return name == crd, nil
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: longWait},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiscoveryCRDChecker(t *testing.T) {
+	d := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "services", Kind: "Service"}},
+		},
+	}}}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   bool
+	}{
+		"Installed": {
+			reason: "A resource served by the API server should be reported as installed",
+			name:   "deployments.apps",
+			want:   true,
+		},
+		"CoreGroupInstalled": {
+			reason: "A resource served by the API server in the core group should be reported as installed",
+			name:   "services",
+			want:   true,
+		},
+		"ResourceNotInstalled": {
+			reason: "A resource that is not served in a known group should not be reported as installed",
+			name:   "statefulsets.apps",
+			want:   false,
+		},
+		"GroupNotInstalled": {
+			reason: "A resource in an unknown group should not be reported as installed",
+			name:   "coolworkloads.example.org",
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &discoveryCRDChecker{discovery: d}
+			got, err := c.Installed(context.Background(), tc.name)
+			if err != nil {
+				t.Fatalf("\n%s\nc.Installed(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Installed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -1,12 +1,11 @@
 package dependency
 
 import (
-	"path"
-
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
 // DAG is the dependency graph for an AppConfig.
@@ -20,7 +19,7 @@ type DAG struct {
 // SinksPerSource represents the sinks that belong to a source.
 type SinksPerSource struct {
 	Source *Source
-	// The key is of format 'kind:namespace/name' which should be unique for any object in an AppConfig.
+	// The key is of format 'namespace/apiVersion/kind/name' which is unique for any object in an AppConfig.
 	Sinks map[string]*Sink
 }
 
@@ -73,9 +72,7 @@ func (d *DAG) AddSource(sourceName string, ref *corev1.ObjectReference, m []v1al
 func (d *DAG) AddSink(sourceName string, obj *unstructured.Unstructured, attaches []unstructured.Unstructured, f []string) {
 	sps := d.getOrCreateSinksPerSource(sourceName)
 
-	// Assuming 'kind:namespace/name' is unique. E.g, 'trait:default/app1-ingress'.
-	key := obj.GetKind() + ":" + path.Join(obj.GetNamespace(), obj.GetName())
-	sps.Sinks[key] = &Sink{
+	sps.Sinks[util.WorkloadIdentityKey(obj)] = &Sink{
 		Object:       obj,
 		attaches:     attaches,
 		ToFieldPaths: f,
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oam
+
+// Annotations recognised by the OAM runtime.
+const (
+	// AnnotationStrictParameterValidation enables validation of the types of
+	// an ApplicationConfiguration's parameter values when set to "true".
+	AnnotationStrictParameterValidation = "oam.dev/strict-parameter-validation"
+
+	// AnnotationStrictGlobalVariables causes placeholders of unknown global
+	// variables in an ApplicationConfiguration's rendered workloads to fail
+	// rendering when set to "true".
+	AnnotationStrictGlobalVariables = "oam.dev/strict-global-variables"
+
+	// AnnotationGloballyUnique requires the names of ApplicationConfigurations
+	// in an annotated namespace to be unique across all annotated namespaces
+	// when set to "true" on a Namespace.
+	AnnotationGloballyUnique = "oam.dev/globally-unique"
+
+	// AnnotationAllowHostNetwork allows the components of
+	// ApplicationConfigurations in an annotated namespace to enable host
+	// network mode when set to "true" on a Namespace.
+	AnnotationAllowHostNetwork = "oam.dev/allow-host-network"
+
+	// AnnotationSensitive indicates that the parameter values of an
+	// ApplicationConfiguration are sensitive when set to "true". Sensitive
+	// values are encrypted per its spec.encryption before it is stored.
+	AnnotationSensitive = "oam.dev/sensitive"
+
+	// AnnotationOwner is set on objects adopted by an ApplicationConfiguration
+	// to the namespace and name of the ApplicationConfiguration.
+	AnnotationOwner = "oam.dev/owner"
+
+	// AnnotationLastAppliedConfig is set on traits whose TraitDefinition uses
+	// the merge strategy to the trait as it was last applied.
+	AnnotationLastAppliedConfig = "oam.dev/last-applied-configuration"
+
+	// AnnotationComponentUID is set on workloads to the stable identity of
+	// the ApplicationConfiguration component that produced them.
+	AnnotationComponentUID = "oam.dev/component-uid"
+
+	// AnnotationPaused pauses the reconciliation of an ApplicationConfiguration
+	// when set to "true".
+	AnnotationPaused = "oam.dev/paused"
+
+	// AnnotationAutoInjected is set to "true" on traits that were injected
+	// into a component by a TraitPolicy.
+	AnnotationAutoInjected = "oam.dev/auto-injected"
+
+	// AnnotationAcyclic is set to "true" on scopes whose memberships may not
+	// form a cycle, e.g. because a scope is a member of itself through the
+	// workloads of its members.
+	AnnotationAcyclic = "oam.dev/acyclic"
+
+	// AnnotationPropagatedLabels is set on workloads and traits to the comma
+	// separated keys of the labels that were propagated to them from their
+	// ApplicationConfiguration.
+	AnnotationPropagatedLabels = "oam.dev/propagated-labels"
+
+	// AnnotationRolloutGroupPaused is set to "true" on
+	// ApplicationConfigurations that were paused by their rollout group, which
+	// only resumes the ApplicationConfigurations it paused.
+	AnnotationRolloutGroupPaused = "oam.dev/rollout-group-paused"
+
+	// AnnotationRolloutStepStarted is set to the RFC 3339 time at which the
+	// rollout group of an ApplicationConfiguration started waiting for it to
+	// become healthy.
+	AnnotationRolloutStepStarted = "oam.dev/rollout-step-started"
+
+	// AnnotationRollbackRequested is set to the RFC 3339 time at which the
+	// rollback of an ApplicationConfiguration was requested. Its components
+	// are rolled back to their last healthy revisions unless they were
+	// rolled back since. A rollout group does not progress while any of its
+	// ApplicationConfigurations has this annotation.
+	AnnotationRollbackRequested = "oam.dev/rollback-requested"
+
+	// AnnotationLastAppliedSpecLimit is set on ApplicationConfigurations to
+	// the maximum size, as a quantity (e.g. 100Ki), of the last applied spec
+	// that is recorded in the status of each of their workloads.
+	AnnotationLastAppliedSpecLimit = "oam.dev/last-applied-spec-limit"
+
+	// AnnotationCanaryWeight is set on ApplicationConfigurations to the
+	// percentage (0-100) of traffic that the last route of the first HTTP
+	// route of each of their Istio VirtualService traits receives. The other
+	// routes share the remaining traffic. Changes to it are applied to the
+	// VirtualServices without rendering the ApplicationConfiguration.
+	AnnotationCanaryWeight = "oam.dev/canary-weight"
+
+	// AnnotationEventPrefix prefixes the annotations with which workloads
+	// emit the events their components declare, e.g. events.oam.dev/ready.
+	// An event is emitted each time the value of its annotation changes.
+	AnnotationEventPrefix = "events.oam.dev/"
+
+	// AnnotationLastReceivedEvent is set on the workloads of components that
+	// subscribe to events to the name of the latest ComponentEvent they
+	// subscribe to.
+	AnnotationLastReceivedEvent = "oam.dev/last-received-event"
+
+	// AnnotationApplicationSetComponent may be set on the template of an Argo
+	// CD ApplicationSet from which an ApplicationConfiguration reads its
+	// components to the JSON or YAML encoded component of each generated
+	// application. Generator parameters are substituted into it.
+	AnnotationApplicationSetComponent = "oam.dev/component"
+
+	// AnnotationSpecTruncated is set to "true" on last applied specs that
+	// were truncated because they exceeded the last applied spec limit.
+	AnnotationSpecTruncated = "oam.dev/spec-truncated"
+)
+
+// Labels recognised by the OAM runtime.
+const (
+	// LabelCopyOf is set to the UID of an ApplicationConfiguration on the
+	// copies of its workloads and traits that are applied to the namespaces
+	// selected by its namespace selector. Copies may not be owned by the
+	// ApplicationConfiguration, so they are found by this label when it is
+	// deleted.
+	LabelCopyOf = "oam.dev/copy-of"
+
+	// LabelKubeconfig must be set to "true" on the Secrets from which
+	// ApplicationConfigurations read the kubeconfig of their TargetCluster.
+	// Only Secrets with this label are watched and cached.
+	LabelKubeconfig = "oam.dev/kubeconfig"
+
+	// LabelAppConfigSource must be set to "true" on the ConfigMaps from which
+	// ApplicationConfigurations read their components.
+	LabelAppConfigSource = "oam.dev/app-config-source"
+
+	// LabelComponentUID is set by trait controllers on the cluster scoped
+	// resources they create to the stable identity of the
+	// ApplicationConfiguration component whose trait they reconcile, so that
+	// the resources may be pruned when the component is removed.
+	LabelComponentUID = "oam.dev/component-uid"
+
+	// LabelPartitionMember is set to "true" on the Leases held by each
+	// replica of the controller when ApplicationConfigurations are
+	// partitioned between replicas.
+	LabelPartitionMember = "oam.dev/partition-member"
+
+	// LabelTrafficTrack is set to "stable" or "canary" on the pods of the
+	// stable and canary Deployments of components with a traffic weight, so
+	// that each may be selected by its own Service.
+	LabelTrafficTrack = "oam.dev/traffic-track"
+)
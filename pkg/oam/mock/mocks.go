@@ -146,6 +146,9 @@ type Manager struct {
 // GetClient returns the client.
 func (m *Manager) GetClient() client.Client { return m.Client }
 
+// GetAPIReader returns the client.
+func (m *Manager) GetAPIReader() client.Reader { return m.Client }
+
 // GetScheme returns the scheme.
 func (m *Manager) GetScheme() *runtime.Scheme { return m.Scheme }
 
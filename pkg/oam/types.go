@@ -19,6 +19,7 @@ package oam
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -55,6 +56,18 @@ type WorkloadsReferencer interface {
 	AddWorkloadReference(runtimev1alpha1.TypedReference)
 }
 
+// A ParameterResolver resolves parameter values that reference secrets held
+// by an external secret manager.
+type ParameterResolver interface {
+	// Resolves returns true if the supplied parameter value is a reference
+	// that this resolver resolves.
+	Resolves(value string) bool
+
+	// Resolve the supplied reference to its plaintext value. Resolved values
+	// may be cached for the supplied TTL.
+	Resolve(ctx context.Context, value string, ttl time.Duration) (string, error)
+}
+
 // A Finalizer manages the finalizers on the resource.
 type Finalizer interface {
 	AddFinalizer(ctx context.Context, obj Object) error
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -99,6 +101,73 @@ func FetchWorkloadDefinition(ctx context.Context, r client.Reader,
 	return workloadDefinition, nil
 }
 
+// Field indexes of WorkloadDefinitions. They must be added to the cache of the
+// client that is used to list WorkloadDefinitions by category or tag.
+const (
+	// IndexWorkloadDefinitionCategory indexes WorkloadDefinitions by each of
+	// their categories.
+	IndexWorkloadDefinitionCategory = "spec.categories"
+
+	// IndexWorkloadDefinitionTag indexes WorkloadDefinitions by each of their
+	// tags, formatted as key=value.
+	IndexWorkloadDefinitionTag = "spec.tags"
+)
+
+// WorkloadDefinitionCategories extracts the categories of a WorkloadDefinition
+// for IndexWorkloadDefinitionCategory.
+func WorkloadDefinitionCategories(o runtime.Object) []string {
+	wd, ok := o.(*v1alpha2.WorkloadDefinition)
+	if !ok {
+		return nil
+	}
+	return wd.Spec.Categories
+}
+
+// WorkloadDefinitionTags extracts the tags of a WorkloadDefinition for
+// IndexWorkloadDefinitionTag.
+func WorkloadDefinitionTags(o runtime.Object) []string {
+	wd, ok := o.(*v1alpha2.WorkloadDefinition)
+	if !ok {
+		return nil
+	}
+	tags := make([]string, 0, len(wd.Spec.Tags))
+	for k, v := range wd.Spec.Tags {
+		tags = append(tags, tag(k, v))
+	}
+	return tags
+}
+
+func tag(key, value string) string {
+	return key + "=" + value
+}
+
+// ListWorkloadsByCategory lists the WorkloadDefinitions of the supplied
+// category. The client's cache must index IndexWorkloadDefinitionCategory.
+func ListWorkloadsByCategory(ctx context.Context, r client.Reader, category string) ([]v1alpha2.WorkloadDefinition, error) {
+	l := &v1alpha2.WorkloadDefinitionList{}
+	if err := r.List(ctx, l, client.MatchingFields{IndexWorkloadDefinitionCategory: category}); err != nil {
+		return nil, err
+	}
+	return l.Items, nil
+}
+
+// ListWorkloadsByTag lists the WorkloadDefinitions with the supplied tag. The
+// client's cache must index IndexWorkloadDefinitionTag.
+func ListWorkloadsByTag(ctx context.Context, r client.Reader, key, value string) ([]v1alpha2.WorkloadDefinition, error) {
+	l := &v1alpha2.WorkloadDefinitionList{}
+	if err := r.List(ctx, l, client.MatchingFields{IndexWorkloadDefinitionTag: tag(key, value)}); err != nil {
+		return nil, err
+	}
+	return l.Items, nil
+}
+
+// ServiceBindingSecretName returns the name of the secret that contains the
+// connection information to which the supplied component of the supplied
+// ApplicationConfiguration is bound.
+func ServiceBindingSecretName(appConfig, component string) string {
+	return fmt.Sprintf("%s-%s-binding", appConfig, component)
+}
+
 // FetchWorkloadChildResources fetch corresponding child resources given a workload
 func FetchWorkloadChildResources(ctx context.Context, mLog logr.Logger, r client.Reader,
 	workload *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
@@ -161,6 +230,25 @@ func GetCRDName(u *unstructured.Unstructured) string {
 	return strings.Join(resources, ".")
 }
 
+// WorkloadIdentityKey returns a key of the form
+// namespace/apiVersion/kind/name that uniquely identifies the supplied object.
+// The namespace, kind, and name are URL path escaped so that they cannot
+// introduce additional separators.
+func WorkloadIdentityKey(u *unstructured.Unstructured) string {
+	return strings.Join([]string{
+		url.PathEscape(u.GetNamespace()),
+		u.GetAPIVersion(),
+		url.PathEscape(u.GetKind()),
+		url.PathEscape(u.GetName()),
+	}, "/")
+}
+
+// AppConfigKey returns a key of the form namespace/name that uniquely
+// identifies the supplied ApplicationConfiguration, e.g. in the DAG manager.
+func AppConfigKey(ac *v1alpha2.ApplicationConfiguration) string {
+	return types.NamespacedName{Namespace: ac.GetNamespace(), Name: ac.GetName()}.String()
+}
+
 // APIVersion2GroupVersion turn an apiVersion string into group and version
 func APIVersion2GroupVersion(str string) (string, string) {
 	strs := strings.Split(str, "/")
@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -115,6 +116,63 @@ var _ = Describe("Test workload related helper utils", func() {
 		}
 	})
 
+	It("Test list workloadDefinitions by category", func() {
+		database := workloadDefinition
+		database.Spec.Categories = []string{"database", "stateful"}
+		listErr := fmt.Errorf("list failed")
+
+		type want struct {
+			wds []v1alpha2.WorkloadDefinition
+			err error
+		}
+		cases := map[string]struct {
+			listErr error
+			want    want
+		}{
+			"ListWorkloadsByCategory fail when list fails": {
+				listErr: listErr,
+				want:    want{err: listErr},
+			},
+			"ListWorkloadsByCategory Success": {
+				want: want{wds: []v1alpha2.WorkloadDefinition{database}},
+			},
+		}
+		for name, tc := range cases {
+			var fields client.MatchingFields
+			tclient := test.MockClient{
+				MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					fields = client.MatchingFields{}
+					if lo.FieldSelector != nil {
+						for _, r := range lo.FieldSelector.Requirements() {
+							fields[r.Field] = r.Value
+						}
+					}
+					if tc.listErr != nil {
+						return tc.listErr
+					}
+					obj.(*v1alpha2.WorkloadDefinitionList).Items = []v1alpha2.WorkloadDefinition{database}
+					return nil
+				},
+			}
+			got, err := util.ListWorkloadsByCategory(ctx, &tclient, "database")
+			By(fmt.Sprint("Running test: ", name))
+			Expect(tc.want.err).Should(util.BeEquivalentToError(err))
+			Expect(tc.want.wds).Should(Equal(got))
+			Expect(fields).Should(Equal(client.MatchingFields{util.IndexWorkloadDefinitionCategory: "database"}))
+		}
+	})
+
+	It("Test extract indexed fields of a workloadDefinition", func() {
+		wd := workloadDefinition
+		wd.Spec.Categories = []string{"database"}
+		wd.Spec.Tags = map[string]string{"vendor": "example"}
+		Expect(util.WorkloadDefinitionCategories(&wd)).Should(Equal([]string{"database"}))
+		Expect(util.WorkloadDefinitionTags(&wd)).Should(Equal([]string{"vendor=example"}))
+		Expect(util.WorkloadDefinitionTags(&workload)).Should(BeNil())
+	})
+
 	It("Test extract child resources from any workload", func() {
 		crkl := []v1alpha2.ChildResourceKind{
 			{
@@ -291,4 +349,55 @@ var _ = Describe("Test unstructured related helper utils", func() {
 			Expect(ti.exp).Should(Equal(got))
 		}
 	})
+
+	It("Test get workload identity key from an unstructured object", func() {
+		tests := map[string]struct {
+			u   *unstructured.Unstructured
+			exp string
+		}{
+			"native resource": {
+				u: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"namespace": "default",
+						"name":      "web",
+					},
+				}},
+				exp: "default/apps/v1/Deployment/web",
+			},
+			"core resource": {
+				u: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": "default",
+						"name":      "web",
+					},
+				}},
+				exp: "default/v1/Service/web",
+			},
+			"name with slashes": {
+				u: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "core.oam.dev/v1alpha2",
+					"kind":       "ContainerizedWorkload",
+					"metadata": map[string]interface{}{
+						"namespace": "default",
+						"name":      "web/v1",
+					},
+				}},
+				exp: "default/core.oam.dev/v1alpha2/ContainerizedWorkload/web%2Fv1",
+			},
+		}
+		for name, ti := range tests {
+			got := util.WorkloadIdentityKey(ti.u)
+			By(fmt.Sprint("Running test: ", name))
+			Expect(ti.exp).Should(Equal(got))
+		}
+	})
+
+	It("Test get the key of an ApplicationConfiguration", func() {
+		ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+		Expect(util.AppConfigKey(ac)).Should(Equal("default/app"))
+	})
 })
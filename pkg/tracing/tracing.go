@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces the work done by OAM controllers using OpenTelemetry,
+// and exports traces to an OTLP endpoint.
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracers that trace OAM controllers.
+const InstrumentationName = "github.com/crossplane/oam-kubernetes-runtime"
+
+// exportTimeout bounds each export of a batch of spans.
+const exportTimeout = 10 * time.Second
+
+// Tracing error strings.
+const (
+	errFmtParseEndpoint = "cannot parse OTLP endpoint %q"
+	errCreateExporter   = "cannot create OTLP exporter"
+)
+
+// NewTracerProvider returns a TracerProvider that batches the spans of the
+// supplied service and exports them to the supplied OTLP HTTP endpoint, e.g.
+// http://collector:4318. Call its Shutdown method to export any remaining
+// spans.
+func NewTracerProvider(ctx context.Context, endpoint, service string) (*sdktrace.TracerProvider, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseEndpoint, endpoint)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf(errFmtParseEndpoint, endpoint)
+	}
+	o := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host), otlptracehttp.WithTimeout(exportTimeout)}
+	if u.Scheme == "http" {
+		o = append(o, otlptracehttp.WithInsecure())
+	}
+	if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+		o = append(o, otlptracehttp.WithURLPath(p+"/v1/traces"))
+	}
+	e, err := otlptracehttp.New(ctx, o...)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateExporter)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(e),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(service))),
+	), nil
+}
+
+// StartSpan starts a child of the span in the supplied context, using the
+// TracerProvider that started it. The returned span records nothing if the
+// context contains no span.
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(InstrumentationName).Start(ctx, name)
+}
+
+// RecordError records that the work of the supplied span failed, if the
+// supplied error is not nil.
+func RecordError(s trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	s.RecordError(err)
+	s.SetStatus(codes.Error, err.Error())
+}
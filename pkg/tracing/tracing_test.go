@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ctx, root := tp.Tracer(InstrumentationName).Start(context.Background(), "appconfig.reconcile")
+	_, child := StartSpan(ctx, "render")
+	RecordError(child, errors.New("boom"))
+	child.End()
+	RecordError(root, nil)
+	root.End()
+
+	ended := sr.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("StartSpan(...): want 2 ended spans, got %d", len(ended))
+	}
+	render, reconcile := ended[0], ended[1]
+	if diff := cmp.Diff("render", render.Name()); diff != "" {
+		t.Errorf("StartSpan(...): -want name, +got name:\n%s", diff)
+	}
+	if render.Parent().SpanID() != reconcile.SpanContext().SpanID() || render.SpanContext().TraceID() != reconcile.SpanContext().TraceID() {
+		t.Errorf("StartSpan(...): want a child of the span in the supplied context")
+	}
+	if diff := cmp.Diff(sdktrace.Status{Code: codes.Error, Description: "boom"}, render.Status()); diff != "" {
+		t.Errorf("RecordError(...): -want status, +got status:\n%s", diff)
+	}
+	if diff := cmp.Diff(sdktrace.Status{Code: codes.Unset}, reconcile.Status()); diff != "" {
+		t.Errorf("RecordError(...): -want status, +got status:\n%s", diff)
+	}
+}
+
+func TestStartSpanWithoutParent(t *testing.T) {
+	_, s := StartSpan(context.Background(), "render")
+
+	// None of these should panic.
+	RecordError(s, errors.New("boom"))
+	s.End()
+
+	if s.SpanContext().IsValid() || s.IsRecording() {
+		t.Errorf("StartSpan(...): a context without a span should start no spans")
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	cases := map[string]struct {
+		endpoint string
+		wantErr  bool
+	}{
+		"Valid":     {endpoint: "http://otel-collector:4318"},
+		"WithPath":  {endpoint: "https://example.org/otlp/"},
+		"NoHost":    {endpoint: "otel-collector", wantErr: true},
+		"BadScheme": {endpoint: "://otel-collector", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tp, err := NewTracerProvider(context.Background(), tc.endpoint, "oam")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewTracerProvider(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if tp != nil {
+				_ = tp.Shutdown(context.Background())
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certmanager manages the lifecycle of cert-manager Certificate
+// traits.
+package certmanager
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Certificate error strings.
+const (
+	errGetCertificate    = "cannot get certificate"
+	errCreateCertificate = "cannot create certificate"
+	errPatchCertificate  = "cannot patch certificate"
+
+	errNotIssued = "certificate has not been issued"
+)
+
+// CertificateGroupVersionKind is the kind of a cert-manager Certificate.
+var CertificateGroupVersionKind = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// A CertificateTrait manages the lifecycle of cert-manager Certificates. A
+// Certificate is owned by the workload it was applied alongside, so that it
+// is garbage collected along with the workload, and is only ready once
+// cert-manager reports that its certificate was issued.
+type CertificateTrait struct{}
+
+// Apply the supplied Certificate of the supplied workload. A Certificate that
+// does not exist is created. One that exists is updated using a JSON merge
+// patch, preserving the fields set by cert-manager.
+func (a *CertificateTrait) Apply(ctx context.Context, c client.Client, t, w *unstructured.Unstructured, ao ...resource.ApplyOption) error {
+	if w.GetUID() != "" {
+		meta.AddOwnerReference(t, metav1.OwnerReference{
+			APIVersion: w.GetAPIVersion(),
+			Kind:       w.GetKind(),
+			Name:       w.GetName(),
+			UID:        w.GetUID(),
+		})
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(t.GroupVersionKind())
+	err := c.Get(ctx, types.NamespacedName{Namespace: t.GetNamespace(), Name: t.GetName()}, current)
+	if kerrors.IsNotFound(err) {
+		return errors.Wrap(c.Create(ctx, t), errCreateCertificate)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetCertificate)
+	}
+
+	for _, fn := range ao {
+		if err := fn(ctx, current, t); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(c.Patch(ctx, t, client.Merge), errPatchCertificate)
+}
+
+// Delete the supplied Certificate. A Certificate with a UID is only deleted if
+// it still has that UID. The Secret cert-manager issued the certificate to is
+// not deleted.
+func (a *CertificateTrait) Delete(ctx context.Context, c client.Client, t *unstructured.Unstructured) error {
+	uid := t.GetUID()
+	if uid == "" {
+		return c.Delete(ctx, t)
+	}
+	return c.Delete(ctx, t, client.Preconditions{UID: &uid})
+}
+
+// Ready returns true if the supplied Certificate has a Ready condition with
+// status True. Otherwise it returns the message of its Ready condition, if
+// any, explaining why it is not ready.
+func (a *CertificateTrait) Ready(t *unstructured.Unstructured) (bool, string) {
+	conditions, _ := fieldpath.Pave(t.UnstructuredContent()).GetValue("status.conditions")
+	cs, _ := conditions.([]interface{})
+	for _, raw := range cs {
+		c, ok := raw.(map[string]interface{})
+		if !ok || c["type"] != "Ready" {
+			continue
+		}
+		if c["status"] == "True" {
+			return true, ""
+		}
+		if msg, ok := c["message"].(string); ok && msg != "" {
+			return false, msg
+		}
+	}
+	return false, errNotIssued
+}
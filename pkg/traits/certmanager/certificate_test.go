@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCertificateTraitApply(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("apps/v1")
+	workload.SetKind("Deployment")
+	workload.SetName("workload")
+	workload.SetUID(types.UID("workload-uid"))
+
+	certificate := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"secretName": "tls"}}}
+		u.SetGroupVersionKind(CertificateGroupVersionKind)
+		u.SetNamespace("ns")
+		u.SetName("cert")
+		return u
+	}
+
+	type want struct {
+		ops    []string
+		owners int
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		ao     []resource.ApplyOption
+		want   want
+	}{
+		"Create": {
+			reason: "A Certificate that does not exist should be created, owned by its workload",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			want:   want{ops: []string{"create"}, owners: 1},
+		},
+		"Update": {
+			reason: "A Certificate that exists should be patched",
+			get:    test.NewMockGetFn(nil),
+			want:   want{ops: []string{"patch"}, owners: 1},
+		},
+		"GetError": {
+			reason: "Errors getting the Certificate should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   want{owners: 1, err: errors.Wrap(errBoom, errGetCertificate)},
+		},
+		"ApplyOptionError": {
+			reason: "Errors returned by apply options should prevent the Certificate from being updated",
+			get:    test.NewMockGetFn(nil),
+			ao: []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error {
+				return errBoom
+			}},
+			want: want{owners: 1, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ops []string
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, _ runtime.Object, _ ...client.CreateOption) error {
+					ops = append(ops, "create")
+					return nil
+				},
+				MockPatch: func(_ context.Context, _ runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					ops = append(ops, "patch")
+					return nil
+				},
+			}
+			cert := certificate()
+			a := &CertificateTrait{}
+			err := a.Apply(context.Background(), c, cert, workload, tc.ao...)
+			got := want{ops: ops, owners: len(cert.GetOwnerReferences()), err: err}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\na.Apply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCertificateTraitReady(t *testing.T) {
+	certificate := func(conditions ...interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if conditions != nil {
+			u.Object["status"] = map[string]interface{}{"conditions": conditions}
+		}
+		return u
+	}
+
+	type want struct {
+		ready bool
+		msg   string
+	}
+	cases := map[string]struct {
+		reason string
+		t      *unstructured.Unstructured
+		want   want
+	}{
+		"NoStatus": {
+			reason: "A Certificate that cert-manager has not yet observed should not be ready",
+			t:      certificate(),
+			want:   want{msg: errNotIssued},
+		},
+		"Ready": {
+			reason: "A Certificate whose Ready condition is True should be ready",
+			t: certificate(
+				map[string]interface{}{"type": "Issuing", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			),
+			want: want{ready: true},
+		},
+		"NotReady": {
+			reason: "A Certificate whose Ready condition is False should not be ready, explained by its message",
+			t:      certificate(map[string]interface{}{"type": "Ready", "status": "False", "message": "Issuing certificate as Secret does not exist"}),
+			want:   want{msg: "Issuing certificate as Secret does not exist"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ready, msg := (&CertificateTrait{}).Ready(tc.t)
+			if diff := cmp.Diff(tc.want, want{ready: ready, msg: msg}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\na.Ready(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
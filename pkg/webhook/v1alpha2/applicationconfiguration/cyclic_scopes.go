@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// Cyclic scope error strings.
+const (
+	errFmtGetScope    = "cannot get scope %q"
+	errListAppConfigs = "cannot list application configurations"
+
+	msgFmtCyclicMembers = "membership of acyclic scope %s would form a cycle: %s"
+)
+
+// A membershipGraph maps each scope to the workloads that are its members.
+// Workloads that are themselves scopes are also keys of the graph.
+type membershipGraph map[string][]string
+
+func refKey(r runtimev1alpha1.TypedReference) string {
+	return r.Kind + "." + r.APIVersion + "/" + r.Name
+}
+
+// cyclicScopes returns a message for each scope of the supplied
+// ApplicationConfiguration that is annotated as acyclic and would become a
+// member of itself, directly or through the workloads of its members. The
+// membership graph is built from all ApplicationConfigurations in the
+// namespace, with the supplied ApplicationConfiguration replacing any existing
+// version of itself.
+func (h *ValidatingHandler) cyclicScopes(ctx context.Context, namespace string, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	acyclic, err := h.acyclicScopes(ctx, namespace, ac)
+	if err != nil || len(acyclic) == 0 {
+		return nil, err
+	}
+
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := h.client.List(ctx, acs, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, errListAppConfigs)
+	}
+	g := membershipGraph{}
+	if err := h.addMemberships(ctx, g, namespace, ac); err != nil {
+		return nil, err
+	}
+	for i := range acs.Items {
+		if acs.Items[i].GetName() == ac.GetName() {
+			continue
+		}
+		if err := h.addMemberships(ctx, g, namespace, &acs.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	msgs := make([]string, 0)
+	for _, s := range acyclic {
+		if path := g.cycle(s); path != nil {
+			msgs = append(msgs, fmt.Sprintf(msgFmtCyclicMembers, s, strings.Join(path, " -> ")))
+		}
+	}
+	return msgs, nil
+}
+
+// acyclicScopes returns the scopes of the supplied ApplicationConfiguration
+// that are annotated as acyclic. Scopes that do not exist are ignored.
+func (h *ValidatingHandler) acyclicScopes(ctx context.Context, namespace string, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	seen := map[string]bool{}
+	acyclic := make([]string, 0)
+	for _, acc := range ac.Spec.Components {
+		for _, cs := range acc.Scopes {
+			ref := cs.ScopeReference
+			if seen[refKey(ref)] {
+				continue
+			}
+			seen[refKey(ref)] = true
+
+			s := &unstructured.Unstructured{}
+			s.SetAPIVersion(ref.APIVersion)
+			s.SetKind(ref.Kind)
+			err := h.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, s)
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetScope, ref.Name)
+			}
+			if s.GetAnnotations()[oam.AnnotationAcyclic] == "true" {
+				acyclic = append(acyclic, refKey(ref))
+			}
+		}
+	}
+	return acyclic, nil
+}
+
+// addMemberships adds an edge from each scope of each component of the
+// supplied ApplicationConfiguration to the component's workload. Components
+// that do not exist yet are ignored.
+func (h *ValidatingHandler) addMemberships(ctx context.Context, g membershipGraph, namespace string, ac *v1alpha2.ApplicationConfiguration) error {
+	for _, acc := range ac.Spec.Components {
+		if len(acc.Scopes) == 0 {
+			continue
+		}
+		c, err := h.getComponent(ctx, namespace, acc)
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		w := &unstructured.Unstructured{}
+		if err := json.Unmarshal(c.Spec.Workload.Raw, w); err != nil {
+			return errors.Wrapf(err, errFmtUnmarshalWorkload, c.GetName())
+		}
+		// Workloads are named after their component unless they specify
+		// a name.
+		name := w.GetName()
+		if name == "" {
+			name = c.GetName()
+		}
+		member := refKey(runtimev1alpha1.TypedReference{APIVersion: w.GetAPIVersion(), Kind: w.GetKind(), Name: name})
+		for _, cs := range acc.Scopes {
+			s := refKey(cs.ScopeReference)
+			g[s] = append(g[s], member)
+		}
+	}
+	return nil
+}
+
+// cycle returns a path of memberships from the supplied scope back to itself,
+// or nil if there is none.
+func (g membershipGraph) cycle(scope string) []string {
+	visited := map[string]bool{}
+	var visit func(node string, path []string) []string
+	visit = func(node string, path []string) []string {
+		for _, m := range g[node] {
+			if m == scope {
+				return append(path, m)
+			}
+			if visited[m] {
+				continue
+			}
+			visited[m] = true
+			if p := visit(m, append(path, m)); p != nil {
+				return p
+			}
+		}
+		return nil
+	}
+	return visit(scope, []string{scope})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestCyclicScopes(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	scope := func(name string) runtimev1alpha1.TypedReference {
+		return runtimev1alpha1.TypedReference{APIVersion: "example.org/v1", Kind: "NetworkScope", Name: name}
+	}
+	ac := func(name, component string, s runtimev1alpha1.TypedReference) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: component,
+				Scopes:        []v1alpha2.ComponentScope{{ScopeReference: s}},
+			}}},
+		}
+	}
+
+	// Component "a" is scope "y", and is a member of scope "x". Component "b"
+	// is scope "x", and is a member of scope "y".
+	admitted := ac("admitted", "a", scope("x"))
+	existing := ac("existing", "b", scope("y"))
+	workloads := map[string]string{
+		"a": `{"apiVersion":"example.org/v1","kind":"NetworkScope","metadata":{"name":"y"}}`,
+		"b": `{"apiVersion":"example.org/v1","kind":"NetworkScope","metadata":{"name":"x"}}`,
+	}
+
+	get := func(annotations map[string]string, scopeErr error) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *unstructured.Unstructured:
+				o.SetAnnotations(annotations)
+				return scopeErr
+			case *v1alpha2.Component:
+				o.SetName(key.Name)
+				o.Spec.Workload = runtime.RawExtension{Raw: []byte(workloads[key.Name])}
+			}
+			return nil
+		}
+	}
+	list := func(acs ...v1alpha2.ApplicationConfiguration) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.ApplicationConfigurationList).Items = acs
+			return nil
+		})
+	}
+	acyclic := map[string]string{oam.AnnotationAcyclic: "true"}
+
+	type want struct {
+		msgs []string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		client *test.MockClient
+		want   want
+	}{
+		"NotAcyclic": {
+			reason: "Scopes that are not annotated as acyclic should not be checked",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			want:   want{msgs: nil},
+		},
+		"GetScopeError": {
+			reason: "Errors getting scopes should be returned",
+			client: &test.MockClient{MockGet: get(nil, errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetScope, "x")},
+		},
+		"ListError": {
+			reason: "Errors listing application configurations should be returned",
+			client: &test.MockClient{MockGet: get(acyclic, nil), MockList: test.NewMockListFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errListAppConfigs)},
+		},
+		"NoCycle": {
+			reason: "Memberships that do not form a cycle should be allowed",
+			client: &test.MockClient{MockGet: get(acyclic, nil), MockList: list(admitted)},
+			want:   want{msgs: []string{}},
+		},
+		"Cycle": {
+			reason: "Memberships that form a cycle through another application configuration should be reported",
+			client: &test.MockClient{MockGet: get(acyclic, nil), MockList: list(existing)},
+			want: want{msgs: []string{fmt.Sprintf(msgFmtCyclicMembers,
+				"NetworkScope.example.org/v1/x",
+				"NetworkScope.example.org/v1/x -> NetworkScope.example.org/v1/y -> NetworkScope.example.org/v1/x")}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := &ValidatingHandler{client: tc.client}
+			in := admitted
+			msgs, err := h.cyclicScopes(context.Background(), "ns", &in)
+			if diff := cmp.Diff(tc.want, want{msgs: msgs, err: err}, cmp.AllowUnexported(want{}), test.EquateErrors(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nh.cyclicScopes(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
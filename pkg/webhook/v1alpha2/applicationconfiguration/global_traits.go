@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Global trait validation messages.
+const (
+	msgFmtInvalidGlobalTrait     = "global trait %d is not a valid trait: %s"
+	msgFmtConflictingGlobalTrait = "global trait %d is of the same kind %s as global trait %d"
+)
+
+// conflictingGlobalTraits returns a message for each global trait of the
+// supplied ApplicationConfiguration that cannot be decoded, or that is of the
+// same apiVersion and kind as an earlier global trait.
+func conflictingGlobalTraits(ac *v1alpha2.ApplicationConfiguration) []string {
+	msgs := make([]string, 0)
+	seen := make(map[schema.GroupVersionKind]int, len(ac.Spec.GlobalTraits))
+	for i, ct := range ac.Spec.GlobalTraits {
+		t := &unstructured.Unstructured{}
+		if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+			msgs = append(msgs, fmt.Sprintf(msgFmtInvalidGlobalTrait, i, err))
+			continue
+		}
+		gvk := t.GroupVersionKind()
+		if j, ok := seen[gvk]; ok {
+			msgs = append(msgs, fmt.Sprintf(msgFmtConflictingGlobalTrait, i, t.GetKind()+"."+t.GetAPIVersion(), j))
+			continue
+		}
+		seen[gvk] = i
+	}
+	return msgs
+}
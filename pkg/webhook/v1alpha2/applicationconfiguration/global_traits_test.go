@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestConflictingGlobalTraits(t *testing.T) {
+	hpa := []byte(`{"apiVersion":"autoscaling/v1","kind":"HorizontalPodAutoscaler"}`)
+	route := []byte(`{"apiVersion":"example.org/v1","kind":"Route"}`)
+	routeV2 := []byte(`{"apiVersion":"example.org/v2","kind":"Route"}`)
+
+	ac := func(traits ...[]byte) *v1alpha2.ApplicationConfiguration {
+		ac := &v1alpha2.ApplicationConfiguration{}
+		for _, t := range traits {
+			ac.Spec.GlobalTraits = append(ac.Spec.GlobalTraits, v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: t}})
+		}
+		return ac
+	}
+
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		want   []string
+	}{
+		"NoGlobalTraits": {
+			reason: "ApplicationConfigurations without global traits should not be reported",
+			ac:     ac(),
+		},
+		"DistinctKinds": {
+			reason: "Global traits of distinct kinds, including distinct versions of a kind, should not be reported",
+			ac:     ac(hpa, route, routeV2),
+		},
+		"Conflict": {
+			reason: "Global traits of the same kind as an earlier global trait should be reported",
+			ac:     ac(route, hpa, route),
+			want:   []string{fmt.Sprintf(msgFmtConflictingGlobalTrait, 2, "Route.example.org/v1", 0)},
+		},
+		"Invalid": {
+			reason: "Global traits that cannot be decoded should be reported",
+			ac:     ac([]byte(`{`)),
+			want:   []string{fmt.Sprintf(msgFmtInvalidGlobalTrait, 0, "unexpected end of JSON input")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := conflictingGlobalTraits(tc.ac)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nconflictingGlobalTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+const msgFmtHostNetworkNotAllowed = "component %q enables host network mode, which namespace %q does not allow"
+
+// disallowedHostNetwork returns a message for each component of the supplied
+// ApplicationConfiguration that enables host network mode, unless the
+// supplied namespace is annotated to allow it.
+func (h *ValidatingHandler) disallowedHostNetwork(ctx context.Context, namespace string, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	enabled := make([]string, 0)
+	for _, acc := range ac.Spec.Components {
+		if !acc.HostNetwork {
+			continue
+		}
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = acc.RevisionName
+		}
+		enabled = append(enabled, name)
+	}
+	if len(enabled) == 0 {
+		return nil, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return nil, errors.Wrap(err, errGetNamespace)
+	}
+	if ns.GetAnnotations()[oam.AnnotationAllowHostNetwork] == "true" {
+		return nil, nil
+	}
+
+	msgs := make([]string, 0, len(enabled))
+	for _, name := range enabled {
+		msgs = append(msgs, fmt.Sprintf(msgFmtHostNetworkNotAllowed, name, namespace))
+	}
+	return msgs, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestDisallowedHostNetwork(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	namespace := func(annotations map[string]string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			obj.(*corev1.Namespace).SetAnnotations(annotations)
+			return nil
+		}
+	}
+
+	ac := func(acc ...v1alpha2.ApplicationConfigurationComponent) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{Components: acc}}
+	}
+
+	type want struct {
+		msgs []string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"HostNetworkNotEnabled": {
+			reason: "The namespace should not be checked when no component enables host network mode",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ac:     ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "a"}),
+		},
+		"GetNamespaceError": {
+			reason: "Errors getting the namespace should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ac:     ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "a", HostNetwork: true}),
+			want:   want{err: errors.Wrap(errBoom, errGetNamespace)},
+		},
+		"NotAllowed": {
+			reason: "Components that enable host network mode should be rejected in namespaces that are not annotated to allow it",
+			client: &test.MockClient{MockGet: namespace(nil)},
+			ac: ac(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "a", HostNetwork: true},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "b"},
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "c-v1", HostNetwork: true},
+			),
+			want: want{msgs: []string{
+				fmt.Sprintf(msgFmtHostNetworkNotAllowed, "a", "ns"),
+				fmt.Sprintf(msgFmtHostNetworkNotAllowed, "c-v1", "ns"),
+			}},
+		},
+		"Allowed": {
+			reason: "Components that enable host network mode should be allowed in namespaces annotated to allow it",
+			client: &test.MockClient{MockGet: namespace(map[string]string{oam.AnnotationAllowHostNetwork: "true"})},
+			ac:     ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "a", HostNetwork: true}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewValidatingHandler(tc.client, nil)
+			got, err := h.disallowedHostNetwork(context.Background(), "ns", tc.ac)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.disallowedHostNetwork(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msgs, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nh.disallowedHostNetwork(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	appconfig "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// MutatingPath is the path at which the ApplicationConfiguration mutating
+// webhook is served.
+const MutatingPath = "/mutating-core-oam-dev-v1alpha2-applicationconfigurations"
+
+// Mutation error strings.
+const (
+	errListTraitPolicies = "cannot list trait policies"
+	errInjectTraits      = "cannot inject traits"
+	errEncryptSensitive  = "cannot encrypt sensitive parameter values"
+	errEncodeAppConfig   = "cannot encode application configuration"
+
+	errFmtParseSelector = "cannot parse selector of trait policy %q"
+	errFmtDecodeTrait   = "cannot decode trait of trait policy %q"
+)
+
+// A MutatingHandler injects the traits of the TraitPolicies that select an
+// ApplicationConfiguration into each of its components, and encrypts its
+// sensitive parameter values.
+type MutatingHandler struct {
+	client    client.Client
+	encryptor appconfig.SpecEncryptor
+	decoder   *admission.Decoder
+}
+
+var _ admission.Handler = &MutatingHandler{}
+var _ admission.DecoderInjector = &MutatingHandler{}
+
+// NewMutatingHandler returns a MutatingHandler that reads TraitPolicies and
+// encryption keys using the supplied client, and encrypts sensitive parameter
+// values using the supplied encryptor. Sensitive parameter values are not
+// encrypted if the encryptor is nil.
+func NewMutatingHandler(c client.Client, e appconfig.SpecEncryptor) *MutatingHandler {
+	return &MutatingHandler{client: c, encryptor: e}
+}
+
+// Handle an admission request to create or update an ApplicationConfiguration.
+func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := h.decoder.Decode(req, ac); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeRequest))
+	}
+
+	tps := &v1alpha2.TraitPolicyList{}
+	if err := h.client.List(ctx, tps); err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListTraitPolicies))
+	}
+
+	injected, err := injectTraits(ac, tps.Items)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errInjectTraits))
+	}
+
+	encrypted := false
+	if h.encryptor != nil {
+		encrypted, err = appconfig.EncryptSensitiveParameterValues(ctx, h.client, h.encryptor, ac)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errEncryptSensitive))
+		}
+	}
+	if !injected && !encrypted {
+		return admission.Allowed("")
+	}
+
+	raw, err := json.Marshal(ac)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errEncodeAppConfig))
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
+
+// InjectDecoder injects the decoder used to decode admission requests.
+func (h *MutatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// injectTraits injects the traits of the supplied TraitPolicies that select
+// the supplied ApplicationConfiguration into each of its components, unless
+// the component already has a trait of the same kind. It returns true if any
+// traits were injected.
+func injectTraits(ac *v1alpha2.ApplicationConfiguration, tps []v1alpha2.TraitPolicy) (bool, error) {
+	injected := false
+	for _, tp := range tps {
+		s, err := metav1.LabelSelectorAsSelector(&tp.Spec.Selector)
+		if err != nil {
+			return false, errors.Wrapf(err, errFmtParseSelector, tp.GetName())
+		}
+		if !s.Matches(labels.Set(ac.GetLabels())) {
+			continue
+		}
+		for _, it := range tp.Spec.InjectTrait {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(it.Trait.Raw, t); err != nil {
+				return false, errors.Wrapf(err, errFmtDecodeTrait, tp.GetName())
+			}
+			meta.AddAnnotations(t, map[string]string{oam.AnnotationAutoInjected: "true"})
+			raw, err := json.Marshal(t)
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtDecodeTrait, tp.GetName())
+			}
+
+			for i := range ac.Spec.Components {
+				acc := &ac.Spec.Components[i]
+				if hasTraitKind(acc.Traits, t.GetAPIVersion(), t.GetKind()) {
+					continue
+				}
+				ct := *it.DeepCopy()
+				ct.Trait = runtime.RawExtension{Raw: raw}
+				acc.Traits = append(acc.Traits, ct)
+				injected = true
+			}
+		}
+	}
+	return injected, nil
+}
+
+// hasTraitKind returns true if any of the supplied traits is of the supplied
+// apiVersion and kind.
+func hasTraitKind(traits []v1alpha2.ComponentTrait, apiVersion, kind string) bool {
+	for _, ct := range traits {
+		t := &unstructured.Unstructured{}
+		if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+			continue
+		}
+		if t.GetAPIVersion() == apiVersion && t.GetKind() == kind {
+			return true
+		}
+	}
+	return false
+}
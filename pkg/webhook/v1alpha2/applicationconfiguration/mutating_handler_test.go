@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestInjectTraits(t *testing.T) {
+	sidecar := v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Sidecar"}`)}}
+	injected := v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Sidecar","metadata":{"annotations":{"oam.dev/auto-injected":"true"}}}`)}}
+	scaler := v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Scaler"}`)}}
+
+	ac := func(traits ...v1alpha2.ComponentTrait) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}},
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "web", Traits: traits},
+			}},
+		}
+	}
+	policy := func(selector map[string]string) v1alpha2.TraitPolicy {
+		return v1alpha2.TraitPolicy{Spec: v1alpha2.TraitPolicySpec{
+			Selector:    metav1.LabelSelector{MatchLabels: selector},
+			InjectTrait: []v1alpha2.ComponentTrait{sidecar},
+		}}
+	}
+
+	type want struct {
+		ac       *v1alpha2.ApplicationConfiguration
+		injected bool
+	}
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		tps    []v1alpha2.TraitPolicy
+		want   want
+	}{
+		"Selected": {
+			reason: "Traits of a selecting policy should be injected, and annotated as such",
+			ac:     ac(scaler),
+			tps:    []v1alpha2.TraitPolicy{policy(map[string]string{"team": "a"})},
+			want:   want{ac: ac(scaler, injected), injected: true},
+		},
+		"EmptySelector": {
+			reason: "Policies with an empty selector should select all ApplicationConfigurations",
+			ac:     ac(),
+			tps:    []v1alpha2.TraitPolicy{policy(nil)},
+			want:   want{ac: ac(injected), injected: true},
+		},
+		"NotSelected": {
+			reason: "Traits of a policy that does not select the ApplicationConfiguration should not be injected",
+			ac:     ac(scaler),
+			tps:    []v1alpha2.TraitPolicy{policy(map[string]string{"team": "b"})},
+			want:   want{ac: ac(scaler)},
+		},
+		"AlreadyHasKind": {
+			reason: "Traits should not be injected into components that already have a trait of the same kind",
+			ac:     ac(injected),
+			tps:    []v1alpha2.TraitPolicy{policy(map[string]string{"team": "a"})},
+			want:   want{ac: ac(injected)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := injectTraits(tc.ac, tc.tps)
+			if err != nil {
+				t.Fatalf("\n%s\ninjectTraits(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{ac: tc.ac, injected: got}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ninjectTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMutatingHandler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatalf("core.AddToScheme(...): %s", err)
+	}
+	d, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("admission.NewDecoder(...): %s", err)
+	}
+
+	ac := &v1alpha2.ApplicationConfiguration{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ApplicationConfigurationKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "coolappconfig"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+			{ComponentName: "web"},
+		}},
+	}
+	raw, _ := json.Marshal(ac)
+	req := func(op admissionv1beta1.Operation) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Namespace: ac.GetNamespace(),
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+	list := func(tps ...v1alpha2.TraitPolicy) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj runtime.Object) error {
+			obj.(*v1alpha2.TraitPolicyList).Items = tps
+			return nil
+		})
+	}
+	sidecar := v1alpha2.TraitPolicy{Spec: v1alpha2.TraitPolicySpec{InjectTrait: []v1alpha2.ComponentTrait{
+		{Trait: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Sidecar"}`)}},
+	}}}
+
+	type want struct {
+		allowed bool
+		patched bool
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		req    admission.Request
+		want   want
+	}{
+		"Delete": {
+			reason: "Requests to delete should be allowed",
+			client: &test.MockClient{},
+			req:    req(admissionv1beta1.Delete),
+			want:   want{allowed: true},
+		},
+		"ListTraitPoliciesError": {
+			reason: "Requests should be rejected if trait policies cannot be read",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			req:    req(admissionv1beta1.Create),
+		},
+		"NoTraitPolicies": {
+			reason: "Requests should be allowed unchanged if there are no trait policies",
+			client: &test.MockClient{MockList: list()},
+			req:    req(admissionv1beta1.Create),
+			want:   want{allowed: true},
+		},
+		"InjectTraits": {
+			reason: "Requests should be patched to inject the traits of selecting trait policies",
+			client: &test.MockClient{MockList: list(sidecar)},
+			req:    req(admissionv1beta1.Update),
+			want:   want{allowed: true, patched: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewMutatingHandler(tc.client, nil)
+			if err := h.InjectDecoder(d); err != nil {
+				t.Fatalf("h.InjectDecoder(...): %s", err)
+			}
+			got := h.Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want, want{allowed: got.Allowed, patched: len(got.Patches) > 0}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nh.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
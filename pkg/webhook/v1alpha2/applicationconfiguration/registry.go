@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// RegistryName is the name of the ConfigMap in which the names of globally
+// unique ApplicationConfigurations are registered.
+const RegistryName = "application-configuration-registry"
+
+// Registry error strings.
+const (
+	errGetRegistry       = "cannot get application configuration registry"
+	errCreateRegistry    = "cannot create application configuration registry"
+	errUpdateRegistry    = "cannot update application configuration registry"
+	errFmtGetRegistered  = "cannot get registered application configuration %q"
+	errFmtNameRegistered = "application configuration name %q is already in use in namespace %q"
+)
+
+// A Registry records the namespace of each globally unique
+// ApplicationConfiguration name in a ConfigMap, keyed by name. Updates to the
+// ConfigMap are guarded by its resource version, so concurrent registrations
+// of the same name cannot both succeed.
+type Registry struct {
+	client    client.Client
+	namespace string
+	name      string
+}
+
+// NewRegistry returns a Registry backed by the ConfigMap RegistryName in the
+// supplied namespace.
+func NewRegistry(c client.Client, namespace string) *Registry {
+	return &Registry{client: c, namespace: namespace, name: RegistryName}
+}
+
+// Register the supplied ApplicationConfiguration name. It returns an error
+// satisfying IsNameRegistered if the name is registered by an extant
+// ApplicationConfiguration in another namespace. Registrations of
+// ApplicationConfigurations that no longer exist are replaced.
+func (r *Registry) Register(ctx context.Context, nn types.NamespacedName) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: r.name}, cm)
+		if kerrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: r.namespace, Name: r.name},
+				Data:       map[string]string{nn.Name: nn.Namespace},
+			}
+			err := r.client.Create(ctx, cm)
+			if kerrors.IsAlreadyExists(err) {
+				// Someone else created the registry first. Try again.
+				return kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, r.name, err)
+			}
+			return errors.Wrap(err, errCreateRegistry)
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetRegistry)
+		}
+
+		if ns, ok := cm.Data[nn.Name]; ok {
+			if ns == nn.Namespace {
+				return nil
+			}
+			ac := &v1alpha2.ApplicationConfiguration{}
+			err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: nn.Name}, ac)
+			if err == nil {
+				return &nameRegisteredError{name: nn.Name, namespace: ns}
+			}
+			if !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, errFmtGetRegistered, ns+"/"+nn.Name)
+			}
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[nn.Name] = nn.Namespace
+		return r.update(ctx, cm)
+	})
+}
+
+// Unregister the supplied ApplicationConfiguration name, if it is registered
+// to the supplied namespace.
+func (r *Registry) Unregister(ctx context.Context, nn types.NamespacedName) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: r.name}, cm)
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetRegistry)
+		}
+		if ns, ok := cm.Data[nn.Name]; !ok || ns != nn.Namespace {
+			return nil
+		}
+		delete(cm.Data, nn.Name)
+		return r.update(ctx, cm)
+	})
+}
+
+func (r *Registry) update(ctx context.Context, cm *corev1.ConfigMap) error {
+	err := r.client.Update(ctx, cm)
+	if kerrors.IsConflict(err) {
+		// Return conflicts unwrapped so that they are retried.
+		return err
+	}
+	return errors.Wrap(err, errUpdateRegistry)
+}
+
+// A nameRegisteredError indicates that an ApplicationConfiguration name is
+// registered to another namespace.
+type nameRegisteredError struct {
+	name      string
+	namespace string
+}
+
+func (e *nameRegisteredError) Error() string {
+	return fmt.Sprintf(errFmtNameRegistered, e.name, e.namespace)
+}
+
+// IsNameRegistered returns true if the supplied error indicates that an
+// ApplicationConfiguration name is registered to another namespace.
+func IsNameRegistered(err error) bool {
+	_, ok := errors.Cause(err).(*nameRegisteredError)
+	return ok
+}
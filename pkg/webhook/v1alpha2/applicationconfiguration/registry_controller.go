@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	appconfig "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
+)
+
+const reconcileTimeout = 1 * time.Minute
+
+// Registry reconcile error strings.
+const (
+	errGetAppConfig = "cannot get application configuration"
+	errUnregister   = "cannot unregister application configuration name"
+)
+
+// Setup registers the ApplicationConfiguration validating and mutating
+// webhooks, and adds a controller that removes deleted
+// ApplicationConfigurations from the registry in the supplied namespace.
+// Sensitive parameter values are encrypted using the supplied encryptor, if
+// any.
+func Setup(mgr ctrl.Manager, namespace string, e appconfig.SpecEncryptor, l logging.Logger) error {
+	r := NewRegistry(mgr.GetClient(), namespace)
+	mgr.GetWebhookServer().Register(ValidatingPath, &webhook.Admission{Handler: NewValidatingHandler(mgr.GetClient(), r)})
+	mgr.GetWebhookServer().Register(MutatingPath, &webhook.Admission{Handler: NewMutatingHandler(mgr.GetClient(), e)})
+
+	name := "oam/" + strings.ToLower(v1alpha2.ApplicationConfigurationGroupKind) + "-registry"
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.ApplicationConfiguration{}).
+		Complete(&RegistryReconciler{client: mgr.GetClient(), registry: r, log: l.WithValues("controller", name)})
+}
+
+// A RegistryReconciler unregisters the names of deleted
+// ApplicationConfigurations.
+type RegistryReconciler struct {
+	client   client.Client
+	registry *Registry
+	log      logging.Logger
+}
+
+// Reconcile an ApplicationConfiguration by unregistering its name if it no
+// longer exists.
+func (r *RegistryReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	err := r.client.Get(ctx, req.NamespacedName, &v1alpha2.ApplicationConfiguration{})
+	if resource.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.Wrap(err, errGetAppConfig)
+	}
+	if err == nil {
+		return reconcile.Result{}, nil
+	}
+
+	log.Debug("Unregistering deleted application configuration")
+	return reconcile.Result{}, errors.Wrap(r.registry.Unregister(ctx, req.NamespacedName), errUnregister)
+}
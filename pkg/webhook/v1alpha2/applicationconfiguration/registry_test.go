@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRegister(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "", errBoom)
+
+	nn := types.NamespacedName{Namespace: "team-a", Name: "coolappconfig"}
+
+	// get returns a MockGetFn that returns a registry with the supplied data,
+	// and the supplied error when getting an ApplicationConfiguration.
+	get := func(data map[string]string, acErr error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				if data == nil {
+					return errNotFound
+				}
+				o.Data = make(map[string]string, len(data))
+				for k, v := range data {
+					o.Data[k] = v
+				}
+			case *v1alpha2.ApplicationConfiguration:
+				return acErr
+			}
+			return nil
+		}
+	}
+
+	// wantData returns a MockUpdateFn that fails the test if the registry is
+	// not updated to contain the supplied data.
+	wantData := func(want map[string]string) test.MockUpdateFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			if diff := cmp.Diff(want, obj.(*corev1.ConfigMap).Data); diff != "" {
+				t.Errorf("client.Update(...): -want, +got:\n%s", diff)
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   error
+	}{
+		"GetRegistryError": {
+			reason: "Errors getting the registry should be returned",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrap(errBoom, errGetRegistry),
+		},
+		"CreateRegistry": {
+			reason: "The registry should be created if it does not exist",
+			client: &test.MockClient{
+				MockGet: get(nil, nil),
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					if diff := cmp.Diff(map[string]string{nn.Name: nn.Namespace}, obj.(*corev1.ConfigMap).Data); diff != "" {
+						t.Errorf("client.Create(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				},
+			},
+		},
+		"AlreadyRegistered": {
+			reason: "Names that are already registered to the same namespace should not be updated",
+			client: &test.MockClient{MockGet: get(map[string]string{nn.Name: nn.Namespace}, nil)},
+		},
+		"NameRegistered": {
+			reason: "Names that are registered by an extant ApplicationConfiguration in another namespace should be rejected",
+			client: &test.MockClient{MockGet: get(map[string]string{nn.Name: "team-b"}, nil)},
+			want:   &nameRegisteredError{name: nn.Name, namespace: "team-b"},
+		},
+		"StaleRegistration": {
+			reason: "Names that are registered by an ApplicationConfiguration that no longer exists should be replaced",
+			client: &test.MockClient{
+				MockGet:    get(map[string]string{nn.Name: "team-b", "other": "team-c"}, errNotFound),
+				MockUpdate: wantData(map[string]string{nn.Name: nn.Namespace, "other": "team-c"}),
+			},
+		},
+		"Register": {
+			reason: "Unregistered names should be added to the registry",
+			client: &test.MockClient{
+				MockGet:    get(map[string]string{"other": "team-c"}, nil),
+				MockUpdate: wantData(map[string]string{nn.Name: nn.Namespace, "other": "team-c"}),
+			},
+		},
+		"RetryOnConflict": {
+			reason: "Conflicting updates to the registry should be retried",
+			client: func() client.Client {
+				calls := 0
+				return &test.MockClient{
+					MockGet: get(map[string]string{}, nil),
+					MockUpdate: func(_ context.Context, _ runtime.Object, _ ...client.UpdateOption) error {
+						calls++
+						if calls == 1 {
+							return errConflict
+						}
+						return nil
+					},
+				}
+			}(),
+		},
+		"UpdateError": {
+			reason: "Errors updating the registry should be returned",
+			client: &test.MockClient{
+				MockGet:    get(map[string]string{}, nil),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: errors.Wrap(errBoom, errUpdateRegistry),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry(tc.client, "oam-system")
+			err := r.Register(context.Background(), nn)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Register(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "team-a", Name: "coolappconfig"}
+
+	get := func(data map[string]string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.ConfigMap).Data = data
+			return nil
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   error
+	}{
+		"NoRegistry": {
+			reason: "Unregistering from a registry that does not exist should succeed",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+		},
+		"RegisteredElsewhere": {
+			reason: "Names registered to another namespace should not be unregistered",
+			client: &test.MockClient{MockGet: get(map[string]string{nn.Name: "team-b"})},
+		},
+		"Unregister": {
+			reason: "Names registered to the namespace should be unregistered",
+			client: &test.MockClient{
+				MockGet: get(map[string]string{nn.Name: nn.Namespace}),
+				MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					if _, ok := obj.(*corev1.ConfigMap).Data[nn.Name]; ok {
+						t.Errorf("client.Update(...): name %q was not unregistered", nn.Name)
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry(tc.client, "oam-system")
+			err := r.Unregister(context.Background(), nn)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Unregister(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
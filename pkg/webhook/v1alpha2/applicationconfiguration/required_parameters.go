@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const msgFmtMissingParameters = "component %q is missing values for required parameters %v"
+
+// missingParameters returns a message for each component of the supplied
+// ApplicationConfiguration that lacks a value for any of its required
+// parameters. Parameters supplied by the inputs of a component have a value.
+// Components that do not exist yet are not checked.
+func (h *ValidatingHandler) missingParameters(ctx context.Context, namespace string, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	msgs := make([]string, 0)
+	for _, acc := range ac.Spec.Components {
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = acc.RevisionName
+		}
+
+		c, err := h.getComponent(ctx, namespace, acc)
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		supplied := make(map[string]bool, len(acc.ParameterValues)+len(acc.Inputs))
+		for _, pv := range acc.ParameterValues {
+			supplied[pv.Name] = true
+		}
+		for _, in := range acc.Inputs {
+			supplied[in.ParameterKey] = true
+		}
+
+		missing := make([]string, 0)
+		for _, p := range c.Spec.Parameters {
+			if p.Required != nil && *p.Required && !supplied[p.Name] {
+				missing = append(missing, p.Name)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf(msgFmtMissingParameters, name, missing))
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestMissingParameters(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	required := true
+
+	get := func(err error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			c := obj.(*v1alpha2.Component)
+			c.Spec.Parameters = []v1alpha2.ComponentParameter{
+				{Name: "image", Required: &required},
+				{Name: "port", Required: &required},
+				{Name: "replicas"},
+			}
+			return err
+		}
+	}
+
+	ac := func(acc ...v1alpha2.ApplicationConfigurationComponent) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{Components: acc}}
+	}
+
+	type want struct {
+		msgs []string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"GetComponentError": {
+			reason: "Errors getting a component should be returned",
+			client: &test.MockClient{MockGet: get(errBoom)},
+			ac:     ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "a"}),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetComponent, "a")},
+		},
+		"ComponentNotFound": {
+			reason: "Components that do not exist should not be checked",
+			client: &test.MockClient{MockGet: get(errNotFound)},
+			ac:     ac(v1alpha2.ApplicationConfigurationComponent{ComponentName: "a"}),
+		},
+		"MissingParameters": {
+			reason: "Missing required parameters should be reported grouped by component",
+			client: &test.MockClient{MockGet: get(nil)},
+			ac: ac(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "a"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "b", ParameterValues: []v1alpha2.ComponentParameterValue{{Name: "image"}}},
+			),
+			want: want{msgs: []string{
+				fmt.Sprintf(msgFmtMissingParameters, "a", []string{"image", "port"}),
+				fmt.Sprintf(msgFmtMissingParameters, "b", []string{"port"}),
+			}},
+		},
+		"RequiredParametersSupplied": {
+			reason: "Required parameters supplied as parameter values or inputs should not be reported",
+			client: &test.MockClient{MockGet: get(nil)},
+			ac: ac(v1alpha2.ApplicationConfigurationComponent{
+				ComponentName:   "a",
+				ParameterValues: []v1alpha2.ComponentParameterValue{{Name: "image"}},
+				Inputs:          []v1alpha2.ComponentInput{{ParameterKey: "port", From: "b-port"}},
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewValidatingHandler(tc.client, nil)
+			got, err := h.missingParameters(context.Background(), "ns", tc.ac)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.missingParameters(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msgs, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nh.missingParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	appconfig "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Required trait error strings.
+const (
+	errFmtGetComponent         = "cannot get component %q"
+	errFmtGetComponentRevision = "cannot get component revision %q"
+	errFmtUnpackRevision       = "cannot get valid component data from component revision %q"
+	errFmtUnmarshalWorkload    = "cannot unmarshal workload of component %q"
+	errFmtUnmarshalTrait       = "cannot unmarshal trait of component %q"
+	errFmtGetDefinition        = "cannot get workload definition of component %q"
+
+	msgFmtMissingTraits = "component %q is missing traits %v required by workload definition %q"
+)
+
+// missingTraits returns a message for each component of the supplied
+// ApplicationConfiguration that lacks a trait required by the
+// WorkloadDefinition of its workload. Components that do not exist yet, and
+// workloads that have no WorkloadDefinition, are not checked.
+func (h *ValidatingHandler) missingTraits(ctx context.Context, namespace string, ac *v1alpha2.ApplicationConfiguration) ([]string, error) {
+	msgs := make([]string, 0)
+	for _, acc := range ac.Spec.Components {
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = acc.RevisionName
+		}
+
+		c, err := h.getComponent(ctx, namespace, acc)
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		w := &unstructured.Unstructured{}
+		if err := json.Unmarshal(c.Spec.Workload.Raw, w); err != nil {
+			return nil, errors.Wrapf(err, errFmtUnmarshalWorkload, name)
+		}
+		wd, err := util.FetchWorkloadDefinition(ctx, h.client, w)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetDefinition, name)
+		}
+		if len(wd.Spec.RequiredTraits) == 0 {
+			continue
+		}
+
+		// Global traits are appended to the traits of every component.
+		traits := make([]v1alpha2.ComponentTrait, 0, len(acc.Traits)+len(ac.Spec.GlobalTraits))
+		traits = append(append(traits, acc.Traits...), ac.Spec.GlobalTraits...)
+		attached := make(map[v1alpha2.TraitKindReference]bool, len(traits))
+		for _, ct := range traits {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				return nil, errors.Wrapf(err, errFmtUnmarshalTrait, name)
+			}
+			attached[v1alpha2.TraitKindReference{APIVersion: t.GetAPIVersion(), Kind: t.GetKind()}] = true
+		}
+
+		missing := make([]string, 0)
+		for _, rt := range wd.Spec.RequiredTraits {
+			if !attached[rt] {
+				missing = append(missing, rt.Kind+"."+rt.APIVersion)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf(msgFmtMissingTraits, name, missing, wd.GetName()))
+		}
+	}
+	return msgs, nil
+}
+
+// getComponent returns the Component, or the Component revision, referenced by
+// the supplied ApplicationConfiguration component.
+func (h *ValidatingHandler) getComponent(ctx context.Context, namespace string, acc v1alpha2.ApplicationConfigurationComponent) (*v1alpha2.Component, error) {
+	if acc.RevisionName != "" {
+		rev := &appsv1.ControllerRevision{}
+		if err := h.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: acc.RevisionName}, rev); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetComponentRevision, acc.RevisionName)
+		}
+		c, err := appconfig.UnpackRevisionData(rev)
+		return c, errors.Wrapf(err, errFmtUnpackRevision, acc.RevisionName)
+	}
+
+	c := &v1alpha2.Component{}
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: acc.ComponentName}, c); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetComponent, acc.ComponentName)
+	}
+	return c, nil
+}
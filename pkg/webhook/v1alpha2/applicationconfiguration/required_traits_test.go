@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestMissingTraits(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	workload := []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)
+	hpa := []byte(`{"apiVersion":"autoscaling/v1","kind":"HorizontalPodAutoscaler"}`)
+	route := []byte(`{"apiVersion":"example.org/v1","kind":"Route"}`)
+
+	required := []v1alpha2.TraitKindReference{
+		{APIVersion: "autoscaling/v1", Kind: "HorizontalPodAutoscaler"},
+		{APIVersion: "example.org/v1", Kind: "Route"},
+	}
+
+	ac := func(traits ...[]byte) *v1alpha2.ApplicationConfiguration {
+		acc := v1alpha2.ApplicationConfigurationComponent{ComponentName: "coolcomponent"}
+		for _, t := range traits {
+			acc.Traits = append(acc.Traits, v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: t}})
+		}
+		return &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{acc},
+		}}
+	}
+
+	// get returns a MockGetFn that returns a Component with a Deployment
+	// workload, and a WorkloadDefinition requiring the supplied traits.
+	get := func(componentErr, definitionErr error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.Component:
+				o.Spec.Workload = runtime.RawExtension{Raw: workload}
+				return componentErr
+			case *v1alpha2.WorkloadDefinition:
+				o.SetName("deployments.apps")
+				o.Spec.RequiredTraits = required
+				return definitionErr
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		msgs []string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		ac     *v1alpha2.ApplicationConfiguration
+		want   want
+	}{
+		"GetComponentError": {
+			reason: "Errors getting a component should be returned",
+			client: &test.MockClient{MockGet: get(errBoom, nil)},
+			ac:     ac(),
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetComponent, "coolcomponent")},
+		},
+		"ComponentNotFound": {
+			reason: "Components that do not exist should not be checked",
+			client: &test.MockClient{MockGet: get(errNotFound, nil)},
+			ac:     ac(),
+		},
+		"WorkloadDefinitionNotFound": {
+			reason: "Workloads without a WorkloadDefinition should not be checked",
+			client: &test.MockClient{MockGet: get(nil, errNotFound)},
+			ac:     ac(),
+		},
+		"MissingTraits": {
+			reason: "Components missing required traits should be reported",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			ac:     ac(route),
+			want: want{msgs: []string{
+				fmt.Sprintf(msgFmtMissingTraits, "coolcomponent", []string{"HorizontalPodAutoscaler.autoscaling/v1"}, "deployments.apps"),
+			}},
+		},
+		"RequiredTraitsAttached": {
+			reason: "Components with all required traits should not be reported",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			ac:     ac(hpa, route),
+		},
+		"RequiredGlobalTraits": {
+			reason: "Required traits may be attached as global traits",
+			client: &test.MockClient{MockGet: get(nil, nil)},
+			ac: func() *v1alpha2.ApplicationConfiguration {
+				ac := ac(route)
+				ac.Spec.GlobalTraits = []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: hpa}}}
+				return ac
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewValidatingHandler(tc.client, nil)
+			got, err := h.missingTraits(context.Background(), "ns", tc.ac)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.missingTraits(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msgs, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nh.missingTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
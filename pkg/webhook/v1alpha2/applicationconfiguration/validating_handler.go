@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package applicationconfiguration implements admission webhooks for OAM
+// ApplicationConfigurations.
+package applicationconfiguration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// ValidatingPath is the path at which the ApplicationConfiguration validating
+// webhook is served.
+const ValidatingPath = "/validating-core-oam-dev-v1alpha2-applicationconfigurations"
+
+// Validation error strings.
+const (
+	errGetNamespace        = "cannot get namespace"
+	errRegisterName        = "cannot register application configuration name"
+	errDecodeRequest       = "cannot decode application configuration"
+	errCheckRequiredTraits = "cannot check required traits"
+	errCheckRequiredParams = "cannot check required parameters"
+	errCheckCyclicScopes   = "cannot check for cyclic scope memberships"
+	errCheckHostNetwork    = "cannot check whether host network mode is allowed"
+)
+
+// A ValidatingHandler validates ApplicationConfigurations. Each component must
+// have the traits required by the WorkloadDefinition of its workload, and a
+// value for each of its required parameters. Scopes annotated as acyclic must
+// not become members of themselves. Components may only enable host network
+// mode in namespaces annotated to allow it. No two global traits may be of the
+// same kind. The names of ApplicationConfigurations created in namespaces
+// annotated as globally unique must not be in use in any other such namespace.
+type ValidatingHandler struct {
+	client   client.Client
+	registry *Registry
+	decoder  *admission.Decoder
+}
+
+var _ admission.Handler = &ValidatingHandler{}
+var _ admission.DecoderInjector = &ValidatingHandler{}
+
+// NewValidatingHandler returns a ValidatingHandler that registers globally
+// unique ApplicationConfiguration names in the supplied Registry.
+func NewValidatingHandler(c client.Client, r *Registry) *ValidatingHandler {
+	return &ValidatingHandler{client: c, registry: r}
+}
+
+// Handle an admission request to create or update an ApplicationConfiguration.
+func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	ac := &v1alpha2.ApplicationConfiguration{}
+	if err := h.decoder.Decode(req, ac); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeRequest))
+	}
+
+	msgs, err := h.missingTraits(ctx, req.Namespace, ac)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errCheckRequiredTraits))
+	}
+	params, err := h.missingParameters(ctx, req.Namespace, ac)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errCheckRequiredParams))
+	}
+	msgs = append(msgs, params...)
+	cycles, err := h.cyclicScopes(ctx, req.Namespace, ac)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errCheckCyclicScopes))
+	}
+	msgs = append(msgs, cycles...)
+	hostNetwork, err := h.disallowedHostNetwork(ctx, req.Namespace, ac)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errCheckHostNetwork))
+	}
+	msgs = append(msgs, hostNetwork...)
+	msgs = append(msgs, conflictingGlobalTraits(ac)...)
+	if len(msgs) > 0 {
+		return admission.Denied(strings.Join(msgs, "; "))
+	}
+
+	if req.Operation != admissionv1beta1.Create {
+		return admission.Allowed("")
+	}
+
+	ns := &corev1.Namespace{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errGetNamespace))
+	}
+	if ns.GetAnnotations()[oam.AnnotationGloballyUnique] != "true" {
+		return admission.Allowed("")
+	}
+
+	err = h.registry.Register(ctx, types.NamespacedName{Namespace: req.Namespace, Name: ac.GetName()})
+	if IsNameRegistered(err) {
+		return admission.Denied(err.Error())
+	}
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errRegisterName))
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder used to decode admission requests.
+func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
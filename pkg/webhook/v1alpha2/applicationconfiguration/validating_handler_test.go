@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestValidatingHandler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatalf("core.AddToScheme(...): %s", err)
+	}
+	d, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("admission.NewDecoder(...): %s", err)
+	}
+
+	ac := &v1alpha2.ApplicationConfiguration{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ApplicationConfigurationKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "coolappconfig"},
+	}
+	raw, _ := json.Marshal(ac)
+
+	req := func(op admissionv1beta1.Operation) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Namespace: ac.GetNamespace(),
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	// get returns a MockGetFn that returns a namespace with the supplied
+	// annotations, and a registry with the supplied data.
+	get := func(annotations, data map[string]string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Namespace:
+				o.SetAnnotations(annotations)
+			case *corev1.ConfigMap:
+				o.Data = data
+			}
+			return nil
+		}
+	}
+	unique := map[string]string{oam.AnnotationGloballyUnique: "true"}
+
+	cases := map[string]struct {
+		reason  string
+		client  client.Client
+		req     admission.Request
+		allowed bool
+	}{
+		"Delete": {
+			reason:  "Requests to delete should be allowed",
+			client:  &test.MockClient{},
+			req:     req(admissionv1beta1.Delete),
+			allowed: true,
+		},
+		"Update": {
+			reason:  "Requests to update should be allowed without registering the name",
+			client:  &test.MockClient{},
+			req:     req(admissionv1beta1.Update),
+			allowed: true,
+		},
+		"GetNamespaceError": {
+			reason: "Requests should be rejected if the namespace cannot be read",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			req:    req(admissionv1beta1.Create),
+		},
+		"NotGloballyUnique": {
+			reason:  "Requests in namespaces that are not annotated as globally unique should be allowed",
+			client:  &test.MockClient{MockGet: get(nil, nil)},
+			req:     req(admissionv1beta1.Create),
+			allowed: true,
+		},
+		"NameRegistered": {
+			reason: "Requests for names that are registered to another namespace should be denied",
+			client: &test.MockClient{MockGet: get(unique, map[string]string{ac.GetName(): "team-b"})},
+			req:    req(admissionv1beta1.Create),
+		},
+		"NameAvailable": {
+			reason: "Requests for names that are not registered should be allowed",
+			client: &test.MockClient{
+				MockGet:    get(unique, map[string]string{}),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			req:     req(admissionv1beta1.Create),
+			allowed: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewValidatingHandler(tc.client, NewRegistry(tc.client, "oam-system"))
+			if err := h.InjectDecoder(d); err != nil {
+				t.Fatalf("h.InjectDecoder(...): %s", err)
+			}
+			got := h.Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.allowed, got.Allowed); diff != "" {
+				t.Errorf("\n%s\nh.Handle(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// +build integration
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test/integration"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	v1alph2controller "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+var (
+	errAppliedLocally = "workload was applied to the local cluster"
+
+	remoteSecretName = "remote-kubeconfig"
+	remoteSecretKey  = "kubeconfig"
+	remoteACName     = "test-remote-ac"
+	remoteCompName   = "test-remote-component"
+	remoteCWName     = "test-remote-cw"
+)
+
+// kubeconfig returns a kubeconfig that connects to the API server of the
+// supplied REST config.
+func kubeconfig(cfg *rest.Config) ([]byte, error) {
+	return clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"remote": {
+			Server:                   cfg.Host,
+			CertificateAuthorityData: cfg.CAData,
+		}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"remote": {
+			ClientCertificateData: cfg.CertData,
+			ClientKeyData:         cfg.KeyData,
+			Token:                 cfg.BearerToken,
+		}},
+		Contexts:       map[string]*clientcmdapi.Context{"remote": {Cluster: "remote", AuthInfo: "remote"}},
+		CurrentContext: "remote",
+	})
+}
+
+func TestAppConfigTargetCluster(t *testing.T) {
+	// The remote cluster is a second, separate API server.
+	remote := &envtest.Environment{CRDDirectoryPaths: []string{"../../charts/oam-core-runtime/crds"}}
+	rcfg, err := remote.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := remote.Stop(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := client.New(rcfg, client.Options{Scheme: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := integration.New(cfg,
+		integration.WithCRDPaths("../../charts/oam-core-runtime/crds"),
+		integration.WithCleaners(
+			integration.NewCRDCleaner(),
+			integration.NewCRDDirCleaner()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddToScheme(i.GetScheme()); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(i.GetScheme()); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiextensionsv1beta1.AddToScheme(i.GetScheme()); err != nil {
+		t.Fatal(err)
+	}
+
+	zl := zap.New(zap.UseDevMode(true))
+	log := logging.NewLogrLogger(zl.WithName("app-config"))
+	if err := v1alph2controller.Setup(i, log); err != nil {
+		t.Fatal(err)
+	}
+
+	i.Run()
+
+	defer func() {
+		if err := i.Cleanup(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	c := i.GetClient()
+	ctx := context.Background()
+
+	kc, err := kubeconfig(rcfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNS,
+			Name:      remoteSecretName,
+			Labels:    map[string]string{oam.LabelKubeconfig: "true"},
+		},
+		Data: map[string][]byte{remoteSecretKey: kc},
+	}
+	if err := c.Create(ctx, sec); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(ctx, wd(wdNameAndDef(wdName))); err != nil && !kerrors.IsAlreadyExists(err) {
+		t.Fatal(err)
+	}
+	workload := cw(
+		cwWithName(remoteCWName),
+		cwWithContainers([]v1alpha2.Container{{Name: containerName, Image: containerImage}}),
+	)
+	if err := c.Create(ctx, comp(
+		compWithName(remoteCompName),
+		compWithNamespace(defaultNS),
+		compWithWorkload(runtime.RawExtension{Object: workload}),
+	)); err != nil {
+		t.Fatal(err)
+	}
+	a := ac(
+		acWithName(remoteACName),
+		acWithNamspace(defaultNS),
+		acWithComps([]v1alpha2.ApplicationConfigurationComponent{{ComponentName: remoteCompName}}),
+	)
+	a.Spec.TargetCluster = &v1alpha2.TargetCluster{
+		KubeconfigSecretRef: v1alpha2.SecretKeySelector{Name: remoteSecretName, Key: remoteSecretKey},
+	}
+	if err := c.Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	nn := types.NamespacedName{Namespace: defaultNS, Name: remoteCWName}
+	if err := waitFor(ctx, 3*time.Second, func() (bool, error) {
+		if err := rc.Get(ctx, nn, &v1alpha2.ContainerizedWorkload{}); err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+			return true, err
+		}
+		return true, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Get(ctx, nn, &v1alpha2.ContainerizedWorkload{})
+	if err == nil {
+		t.Fatal(errors.New(errAppliedLocally))
+	}
+	if !kerrors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+}